@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jrswab/helpi/internal/backup"
+	"github.com/jrswab/helpi/internal/config"
+)
+
+// backupPassphraseEnv holds the passphrase for encrypted backups. It is read
+// from the environment so it does not show up in the process list.
+const backupPassphraseEnv = "HELPI_BACKUP_PASSPHRASE"
+
+// dataSources lists where the bot keeps its data. Sessions, settings and
+// usage stats share memory.path with the file backend. Bots other than the
+// default one have their own per-chat files, see botStores.
+func dataSources(cfg *config.Config) []backup.Source {
+	var sources []backup.Source
+	if cfg.Memory.Backend != "postgres" {
+		sources = append(sources, backup.Source{Name: "sessions", Path: cfg.Memory.Path})
+	}
+	sources = append(sources,
+		backup.Source{Name: "memory", Path: cfg.Memory.RAG.Path},
+		backup.Source{Name: "facts", Path: cfg.Memory.Facts.Path},
+		backup.Source{Name: "documents", Path: cfg.Documents.Path},
+		backup.Source{Name: "usernames.json", Path: cfg.Access.UsernamesPath},
+		backup.Source{Name: "budget.json", Path: cfg.Budget.Path},
+		backup.Source{Name: "feedback.json", Path: cfg.Feedback.Path},
+	)
+	perBot := []backup.Source{
+		{Name: "approved_users.json", Path: cfg.Access.ApprovedPath},
+		{Name: "groups.json", Path: cfg.Groups.Path},
+		{Name: "digest.json", Path: cfg.Digest.Path},
+		{Name: "feeds.json", Path: cfg.Feeds.Path},
+		{Name: "queue.json", Path: cfg.OfflineQueue.Path},
+		{Name: "held.json", Path: cfg.QuietHours.Path},
+		{Name: "batches.json", Path: cfg.Batch.Path},
+	}
+	sources = append(sources, perBot...)
+	for _, b := range cfg.Bots {
+		if b.Name == "default" {
+			continue
+		}
+		for _, s := range perBot {
+			sources = append(sources, backup.Source{Name: botPath(s.Name, b.Name), Path: botPath(s.Path, b.Name)})
+		}
+	}
+	return sources
+}
+
+// runBackupCommand implements `helpi backup <file>` and `helpi restore
+// <file>`.
+func runBackupCommand(command string, args []string, w io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintf(w, "usage: helpi %s <file.tar.gz>\n", command)
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+	sources := dataSources(cfg)
+	passphrase := os.Getenv(backupPassphraseEnv)
+
+	if command == "restore" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintln(w, err)
+			return 1
+		}
+		defer f.Close()
+
+		n, err := backup.Restore(f, sources, passphrase)
+		if errors.Is(err, backup.ErrPassphraseRequired) {
+			err = fmt.Errorf("%w: set %s", err, backupPassphraseEnv)
+		}
+		if err != nil {
+			fmt.Fprintf(w, "restore failed after %d files: %v\n", n, err)
+			return 1
+		}
+		fmt.Fprintf(w, "Restored %d files from %s\n", n, args[0])
+		return 0
+	}
+
+	f, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+	n, err := backup.Write(f, sources, passphrase)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(args[0])
+		fmt.Fprintln(w, err)
+		return 1
+	}
+
+	encrypted := ""
+	if passphrase != "" {
+		encrypted = " (encrypted)"
+	}
+	fmt.Fprintf(w, "Backed up %d files to %s%s\n", n, args[0], encrypted)
+	if cfg.Memory.Backend == "postgres" {
+		fmt.Fprintln(w, "Sessions are stored in Postgres and are not included; back them up with pg_dump.")
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// checkTimeout bounds each probe made by --check.
+const checkTimeout = 15 * time.Second
+
+type checkResult struct {
+	name   string
+	detail string
+	err    error
+}
+
+// runCheck verifies that the bot can start with cfg: every Telegram token is
+// accepted by getMe, every enabled provider answers a cheap authenticated
+// request and session storage is usable. It prints a report to w and
+// returns the number of failed checks.
+func runCheck(ctx context.Context, cfg *config.Config, w io.Writer) int {
+	var results []checkResult
+	results = append(results, checkTelegram(ctx, cfg)...)
+	results = append(results, checkProviders(ctx, cfg)...)
+	results = append(results, checkSessions(cfg))
+	return printReport(w, results)
+}
+
+// runConfigCommand implements `helpi config validate`, which loads the
+// config and fails on unknown fields as well as invalid settings.
+func runConfigCommand(args []string, w io.Writer) int {
+	if len(args) != 1 || args[0] != "validate" {
+		fmt.Fprintln(w, "usage: helpi config validate")
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+	if err := cfg.UnknownFields(); err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+	fmt.Fprintln(w, "config is valid")
+	return 0
+}
+
+func checkTelegram(ctx context.Context, cfg *config.Config) []checkResult {
+	var results []checkResult
+	for _, bc := range cfg.AllBots() {
+		result := checkResult{name: "telegram " + bc.Name}
+		b, err := tgbot.New(bc.Token, tgbot.WithSkipGetMe(), tgbot.WithDefaultHandler(nil))
+		if err != nil {
+			result.err = err
+			results = append(results, result)
+			continue
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		me, err := b.GetMe(probeCtx)
+		cancel()
+		if err != nil {
+			result.err = fmt.Errorf("getMe failed: %w", err)
+		} else {
+			result.detail = "@" + me.Username
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func checkProviders(ctx context.Context, cfg *config.Config) []checkResult {
+	router, err := llm.NewRouter(cfg)
+	if err != nil {
+		return []checkResult{{name: "providers", err: err}}
+	}
+
+	names := router.ProviderNames()
+	if len(names) == 0 {
+		return []checkResult{{name: "providers", err: fmt.Errorf("no LLM provider enabled")}}
+	}
+
+	var results []checkResult
+	for _, name := range names {
+		result := checkResult{name: "provider " + name}
+		provider, err := router.GetProviderByName(name)
+		if err != nil {
+			result.err = err
+			results = append(results, result)
+			continue
+		}
+		if mp, ok := provider.(llm.ModelProvider); ok {
+			result.detail = mp.Model()
+		}
+
+		pinger, ok := provider.(llm.Pinger)
+		if !ok {
+			result.detail = "no probe available"
+			results = append(results, result)
+			continue
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		start := time.Now()
+		err = pinger.Ping(probeCtx)
+		cancel()
+		if err != nil {
+			result.err = err
+		} else {
+			result.detail = fmt.Sprintf("%s, %s", result.detail, time.Since(start).Round(time.Millisecond))
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkSessions opens the session store the way the bot does, which
+// connects to Postgres and Redis when they are configured. For the file
+// backend it also writes and removes a probe file.
+func checkSessions(cfg *config.Config) checkResult {
+	result := checkResult{name: "sessions"}
+	if _, err := newSessionManager(cfg); err != nil {
+		result.err = err
+		return result
+	}
+	if cfg.Memory.Backend == "postgres" {
+		result.detail = "postgres"
+		return result
+	}
+
+	if err := checkWritable(cfg.Memory.Path); err != nil {
+		result.err = err
+		return result
+	}
+	result.detail = cfg.Memory.Path + " is writable"
+	return result
+}
+
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".helpi-check-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", filepath.Clean(dir), err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+func printReport(w io.Writer, results []checkResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Fprintf(w, "FAIL  %-20s %v\n", r.name, r.err)
+			continue
+		}
+		fmt.Fprintf(w, "ok    %-20s %s\n", r.name, r.detail)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(w, "\n%d of %d checks failed\n", failed, len(results))
+	} else {
+		fmt.Fprintf(w, "\nAll %d checks passed\n", len(results))
+	}
+	return failed
+}
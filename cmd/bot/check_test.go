@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrintReport(t *testing.T) {
+	var out bytes.Buffer
+	failed := printReport(&out, []checkResult{
+		{name: "telegram default", detail: "@helpi_bot"},
+		{name: "provider openai", err: errors.New("openai: 401 Unauthorized")},
+	})
+
+	if failed != 1 {
+		t.Errorf("expected 1 failure, got %d", failed)
+	}
+	report := out.String()
+	for _, want := range []string{"ok    telegram default", "@helpi_bot", "FAIL  provider openai", "1 of 2 checks failed"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+	if err := checkWritable(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected the probe file to be removed, got %v", entries)
+	}
+
+	if err := checkWritable(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
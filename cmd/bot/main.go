@@ -2,81 +2,705 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	tgbot "github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/api"
+	"github.com/jrswab/helpi/internal/batch"
 	"github.com/jrswab/helpi/internal/bot"
+	"github.com/jrswab/helpi/internal/budget"
 	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/digest"
+	"github.com/jrswab/helpi/internal/facts"
+	"github.com/jrswab/helpi/internal/feedback"
+	"github.com/jrswab/helpi/internal/feeds"
+	"github.com/jrswab/helpi/internal/groups"
+	"github.com/jrswab/helpi/internal/health"
+	"github.com/jrswab/helpi/internal/ingest"
 	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/memory"
+	"github.com/jrswab/helpi/internal/mqtt"
+	"github.com/jrswab/helpi/internal/queue"
+	"github.com/jrswab/helpi/internal/scheduler"
 	"github.com/jrswab/helpi/internal/session"
+	"github.com/jrswab/helpi/internal/settings"
+	"github.com/jrswab/helpi/internal/storage"
+	"github.com/jrswab/helpi/internal/version"
+	"github.com/jrswab/helpi/internal/webhook"
 )
 
 func main() {
+	check := flag.Bool("check", false, "verify the config, Telegram tokens, provider keys and session storage, then exit")
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println("helpi " + version.String())
+		return
+	}
+
+	switch flag.Arg(0) {
+	case "config":
+		os.Exit(runConfigCommand(flag.Args()[1:], os.Stdout))
+	case "install-service":
+		os.Exit(runInstallService(flag.Args()[1:], os.Stdout))
+	case "migrate":
+		os.Exit(runMigrate(flag.Args()[1:], os.Stdout))
+	case "backup", "restore":
+		os.Exit(runBackupCommand(flag.Arg(0), flag.Args()[1:], os.Stdout))
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
+		if *check {
+			fmt.Printf("FAIL  %-20s %v\n", "config", err)
+			os.Exit(1)
+		}
 		log.Fatalf("Failed to load config: %v", err)
 	}
-
-	if cfg.Telegram.Token == "" {
-		log.Fatal("Telegram bot token is required")
+	if err := cfg.UnknownFields(); err != nil {
+		log.Printf("Ignoring unknown config fields:\n%v", err)
 	}
+	if *check {
+		if runCheck(context.Background(), cfg, os.Stdout) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	log.Printf("Starting helpi %s", version.String())
 
-	llmRouter, err := llm.NewRouter(cfg)
+	sessionManager, err := newSessionManager(cfg)
 	if err != nil {
-		log.Fatalf("Failed to initialize LLM router: %v", err)
+		log.Fatalf("Failed to initialize session manager: %v", err)
+	}
+	if err := session.TrackOwners(sessionManager); err != nil {
+		log.Fatalf("Failed to initialize session manager: %v", err)
 	}
 
-	sessionManager, err := session.NewManager(cfg.Memory.Path, cfg.Memory.MaxMessages)
+	initialRouter, err := buildRouter(cfg, sessionManager)
 	if err != nil {
-		log.Fatalf("Failed to initialize session manager: %v", err)
+		log.Fatalf("Failed to initialize LLM router: %v", err)
 	}
+	llmRouter := llm.NewReloadableRouter(initialRouter)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	telegramBot, err := tgbot.New(cfg.Telegram.Token, tgbot.WithDefaultHandler(nil))
+	var handlerOpts []bot.Option
+	handlerOpts = append(handlerOpts, bot.WithAdmins(cfg.Admins), bot.WithOwners(cfg.Roles.Owners), bot.WithGuests(cfg.Roles.Guests))
+	handlerOpts = append(handlerOpts, bot.WithMemoryBackend(cfg.Memory.Backend))
+	handlerOpts = append(handlerOpts, bot.WithStorage(storageDirs(cfg), storage.Quota{
+		UserBytes:  int64(cfg.Storage.UserMB) << 20,
+		TotalBytes: int64(cfg.Storage.TotalMB) << 20,
+	}))
+	handlerOpts = append(handlerOpts, bot.WithContinuation(cfg.Continuation.Max, cfg.Continuation.Button))
+	var webhooks *webhook.Dispatcher
+	if endpoints := webhookEndpoints(cfg); len(endpoints) > 0 {
+		webhooks = webhook.New(endpoints)
+		handlerOpts = append(handlerOpts, bot.WithWebhooks(webhooks))
+	}
+	var bridge *mqtt.Bridge
+	if cfg.MQTT.Enabled {
+		bridge, err = mqtt.Connect(mqtt.Options{
+			Broker:   cfg.MQTT.Broker,
+			ClientID: cfg.MQTT.ClientID,
+			Username: cfg.MQTT.Username,
+			Password: cfg.APIKeys["MQTT_PASSWORD"],
+		})
+		if err != nil {
+			log.Fatalf("Failed to connect to MQTT broker: %v", err)
+		}
+		defer bridge.Close()
+		handlerOpts = append(handlerOpts, bot.WithHomeAssistant(bridge, cfg.MQTT.CommandTopic))
+	}
+	if cfg.Telegram.AdminChatID != 0 {
+		handlerOpts = append(handlerOpts, bot.WithErrorReporter(bot.NewErrorReporter(cfg.Telegram.AdminChatID)))
+	}
+	usernames, err := bot.NewUsernameCache(cfg.Access.UsernamesPath)
+	if err != nil {
+		log.Fatalf("Failed to load usernames: %v", err)
+	}
+	handlerOpts = append(handlerOpts, bot.WithUsernameCache(usernames))
+	if cfg.Feedback.Enabled {
+		feedbackStore, err := feedback.NewStore(cfg.Feedback.Path)
+		if err != nil {
+			log.Fatalf("Failed to initialize feedback store: %v", err)
+		}
+		handlerOpts = append(handlerOpts, bot.WithFeedbackStore(feedbackStore), bot.WithFeedbackButtons(cfg.Feedback.Buttons))
+	}
+	handlerOpts = append(handlerOpts, bot.WithTranslateRoute(cfg.Translate))
+	handlerOpts = append(handlerOpts, bot.WithTitles(cfg.Titles), bot.WithPostprocess(cfg.Postprocess), bot.WithPrefixes(cfg.Prefixes), bot.WithOfflineNotice(cfg.Offline.Enabled))
+	handlerOpts = append(handlerOpts, bot.WithDefaultLanguage(cfg.Telegram.DefaultLanguage))
+	handlerOpts = append(handlerOpts, bot.WithDebounce(time.Duration(cfg.Telegram.DebounceSeconds)*time.Second))
+	handlerOpts = append(handlerOpts, bot.WithReactions(cfg.Telegram.Reactions), bot.WithQuoteReplies(cfg.Telegram.QuoteReplies))
+	handlerOpts = append(handlerOpts, bot.WithEditReprocessing(cfg.Telegram.ReprocessEdits))
+	inlinePerMinute := cfg.Telegram.Inline.QueriesPerMinute
+	if cfg.Telegram.Inline.Unlimited {
+		inlinePerMinute = 0
+	}
+	handlerOpts = append(handlerOpts, bot.WithInlineQueries(inlinePerMinute, cfg.Telegram.Inline.Burst))
+
+	var budgetTracker *budget.Tracker
+	if cfg.Budget.Enabled() {
+		prices := make(map[string]budget.Price, len(cfg.Budget.Prices))
+		for key, p := range cfg.Budget.Prices {
+			prices[key] = budget.Price{Input: p.Input, Output: p.Output}
+		}
+		budgetTracker, err = budget.NewTracker(cfg.Budget.Path, budget.Limits{
+			UserDaily:   cfg.Budget.DailyUserTokens,
+			GlobalDaily: cfg.Budget.DailyGlobalTokens,
+			UserSpend:   cfg.Budget.DailyUserSpend,
+			GlobalSpend: cfg.Budget.DailyGlobalSpend,
+			Prices:      prices,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize token budget: %v", err)
+		}
+		llmRouter.SetMeter(budgetTracker)
+	}
+	handlerOpts = append(handlerOpts, bot.WithShowRoute(cfg.Routing.ShowRoute))
+	handlerOpts = append(handlerOpts, bot.WithShowReasoning(cfg.ShowsReasoning()))
+	handlerOpts = append(handlerOpts, bot.WithThinkingStatus(cfg.UsesThinking()))
+	handlerOpts = append(handlerOpts, bot.WithThinkSpoilers(cfg.SpoilersThink()))
+	handlerOpts = append(handlerOpts, bot.WithBudget(budgetTracker, cfg.Budget.MaxInputTokens))
+
+	handlerOpts = append(handlerOpts, bot.WithChatContext(cfg.Groups.ChatContext))
+	if len(cfg.Personas) > 0 {
+		handlerOpts = append(handlerOpts, bot.WithPersonas(cfg.Personas))
+	}
+	if len(cfg.Commands) > 0 {
+		handlerOpts = append(handlerOpts, bot.WithCommandRoutes(cfg.Commands))
+	}
+
+	if cfg.Memory.Pruning == "relevance" {
+		embedder, err := llm.NewEmbedder(llmRouter, cfg.Memory.Relevance.Provider)
+		if err != nil {
+			log.Fatalf("Failed to initialize relevance pruning: %v", err)
+		}
+		selector := memory.NewRelevanceSelector(embedder, cfg.Memory.Relevance.Model, cfg.Memory.Relevance.TopK, cfg.Memory.Relevance.Recent)
+		handlerOpts = append(handlerOpts, bot.WithContextSelector(selector))
+	}
+
+	if cfg.Memory.Dedup.Enabled {
+		embedder, err := llm.NewEmbedder(llmRouter, cfg.Memory.Dedup.Provider)
+		if err != nil {
+			log.Fatalf("Failed to initialize history deduplication: %v", err)
+		}
+		handlerOpts = append(handlerOpts, bot.WithHistoryDeduplicator(memory.NewDeduplicator(embedder, cfg.Memory.Dedup.Model, cfg.Memory.Dedup.Threshold)))
+	}
+
+	if cfg.Memory.RAG.Enabled {
+		embedder, err := llm.NewEmbedder(llmRouter, cfg.Memory.RAG.Provider)
+		if err != nil {
+			log.Fatalf("Failed to initialize long-term memory: %v", err)
+		}
+		longTermStore, err := memory.NewLongTermStore(embedder, cfg.Memory.RAG.Model, cfg.Memory.RAG.Path, cfg.Memory.RAG.TopK, cfg.Memory.RAG.MinScore)
+		if err != nil {
+			log.Fatalf("Failed to initialize long-term memory: %v", err)
+		}
+		handlerOpts = append(handlerOpts, bot.WithLongTermMemory(longTermStore))
+	}
+
+	if cfg.Memory.Facts.Enabled {
+		factStore, err := facts.NewStore(cfg.Memory.Facts.Path)
+		if err != nil {
+			log.Fatalf("Failed to initialize fact store: %v", err)
+		}
+		handlerOpts = append(handlerOpts, bot.WithFactStore(factStore))
+		if cfg.Memory.Facts.Extract {
+			handlerOpts = append(handlerOpts, bot.WithFactExtractor(facts.NewExtractor(llmRouter, factStore)))
+		}
+	}
+
+	if cfg.Documents.Enabled {
+		documentStore, err := ingest.NewStore(cfg.Documents.Path)
+		if err != nil {
+			log.Fatalf("Failed to initialize document store: %v", err)
+		}
+		handlerOpts = append(handlerOpts, bot.WithDocumentStore(documentStore, cfg.Documents.MaxExcerpts))
+	}
+
+	if cfg.Memory.MaxTokens > 0 {
+		handlerOpts = append(handlerOpts, bot.WithHistoryTokens(cfg.Memory.MaxTokens))
+	}
+
+	if cfg.Memory.Strategy == "summarize" {
+		handlerOpts = append(handlerOpts, bot.WithHistoryCompactor(memory.NewSummarizer(llmRouter, cfg.Memory.MaxMessages)))
+	}
+
+	var instances []*botInstance
+	bots := make(map[string]*botInstance)
+	for _, bc := range cfg.AllBots() {
+		instance, err := newBotInstance(cfg, bc, llmRouter, sessionManager, handlerOpts)
+		if err != nil {
+			log.Fatalf("Failed to start bot %s: %v", bc.Name, err)
+		}
+		instances = append(instances, instance)
+		bots[bc.Name] = instance
+	}
+	if err := restoreBotChoices(llmRouter, instances); err != nil {
+		log.Fatalf("Failed to initialize LLM router: %v", err)
+	}
+	// owner returns the bot that serves the chats of a job naming bot, the
+	// first one when it names none.
+	owner := func(name string) (*botInstance, error) {
+		if name == "" {
+			return instances[0], nil
+		}
+		instance, ok := bots[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown bot %q", name)
+		}
+		return instance, nil
+	}
+
+	for _, instance := range instances {
+		if err := instance.handlers.PublishCommands(ctx, instance.telegram); err != nil {
+			log.Printf("Failed to publish commands for bot %s: %v", instance.name, err)
+		}
+		log.Printf("Starting polling for bot %s...", instance.name)
+		go instance.telegram.Start(ctx)
+	}
+
+	// Reports about the process as a whole go to the admin chat through the
+	// first bot.
+	handlers, telegramBot := instances[0].handlers, instances[0].telegram
+
+	if err := cfg.UnknownFields(); err != nil {
+		handlers.ErrorReporter().Report(ctx, telegramBot, bot.ErrorKindConfig, fmt.Sprintf("Ignoring unknown config fields:\n%v", err))
+	}
+
+	if bridge != nil {
+		bridge.Subscribe(mqttSubscriptions(cfg), func(m mqtt.Message) {
+			n := api.Notification{ChatID: m.Subscription.ChatID, Text: m.Payload, Prompt: m.Subscription.Prompt, Provider: m.Subscription.Provider}
+			instance, err := owner(m.Subscription.Bot)
+			if err == nil {
+				_, err = instance.handlers.Notify(ctx, instance.telegram, n)
+			}
+			if err != nil {
+				log.Printf("MQTT: failed to forward message on %s to chat %d: %v", m.Topic, n.ChatID, err)
+			}
+		})
+	}
+
+	if cfg.Offline.Enabled {
+		go llm.WatchReachability(ctx, llmRouter, time.Duration(cfg.Offline.ProbeSeconds)*time.Second)
+	}
+
+	sched := scheduler.New()
+	for _, sp := range cfg.ScheduledPrompts {
+		instance, err := owner(sp.Bot)
+		if err != nil {
+			log.Fatalf("Failed to schedule prompt %s: %v", sp.Name, err)
+		}
+		if err := sched.Daily(sp.Name, sp.At, func(ctx context.Context) {
+			instance.handlers.RunScheduledPrompt(ctx, instance.telegram, sp)
+		}); err != nil {
+			log.Fatalf("Failed to schedule prompt: %v", err)
+		}
+	}
+	for _, instance := range instances {
+		if err := scheduleBotJobs(ctx, sched, cfg, instance); err != nil {
+			log.Fatalf("Failed to schedule jobs of bot %s: %v", instance.name, err)
+		}
+	}
+	if cfg.Storage.UserMB > 0 || cfg.Storage.TotalMB > 0 {
+		go handlers.EnforceStorageQuota()
+		if err := sched.Every("storage-quota", time.Hour, func(ctx context.Context) {
+			handlers.EnforceStorageQuota()
+		}); err != nil {
+			log.Fatalf("Failed to schedule storage quota: %v", err)
+		}
+	}
+	if cfg.Updates.Check {
+		checker, err := version.NewUpdateChecker(version.Current(), cfg.Updates.Path)
+		if err != nil {
+			log.Fatalf("Failed to initialize update check: %v", err)
+		}
+		if err := sched.Daily("update-check", cfg.Updates.At, func(ctx context.Context) {
+			checkForUpdate(ctx, checker, handlers, telegramBot)
+		}); err != nil {
+			log.Fatalf("Failed to schedule update check: %v", err)
+		}
+	}
+	go sched.Run(ctx, 30*time.Second)
+
+	if expirer, ok := sessionManager.(session.Expirer); ok && cfg.Memory.TTLDays > 0 {
+		janitor := session.NewJanitor(expirer, time.Duration(cfg.Memory.TTLDays)*24*time.Hour, cfg.Memory.Archive)
+		go janitor.Run(ctx, time.Hour)
+	}
+
+	if cfg.Health.Enabled {
+		healthServer := health.NewServer(func(ctx context.Context) error {
+			for _, instance := range instances {
+				if _, err := instance.telegram.GetMe(ctx); err != nil {
+					return fmt.Errorf("bot %s: %w", instance.name, err)
+				}
+			}
+			return nil
+		}, llmRouter)
+		go func() {
+			if err := healthServer.ListenAndServe(ctx, cfg.Health.Addr); err != nil {
+				log.Printf("%v", err)
+			}
+		}()
+	}
+
+	if cfg.API.Enabled {
+		apiServer := api.NewServer(cfg.APIKeys["HELPI_API_TOKEN"], func(ctx context.Context, n api.Notification) (bool, error) {
+			instance, err := owner(n.Bot)
+			if err != nil {
+				return false, err
+			}
+			return instance.handlers.Notify(ctx, instance.telegram, n)
+		})
+		go func() {
+			if err := apiServer.ListenAndServe(ctx, cfg.API.Addr); err != nil {
+				log.Printf("%v", err)
+			}
+		}()
+	}
+
+	go watchReload(ctx, cfg, llmRouter, instances, sessionManager)
+
+	waitForSignal()
+	log.Println("Shutting down bot...")
+	if webhooks != nil {
+		webhooks.Wait()
+	}
+}
+
+// scheduleBotJobs runs the background jobs that deliver to the chats of
+// instance through it: queued, held and batch replies, digests and feeds.
+func scheduleBotJobs(ctx context.Context, sched *scheduler.Scheduler, cfg *config.Config, instance *botInstance) error {
+	handlers, telegramBot := instance.handlers, instance.telegram
+	job := func(name string) string {
+		if instance.name == "default" {
+			return name
+		}
+		return name + "-" + instance.name
+	}
+
+	if cfg.OfflineQueue.Enabled {
+		interval := time.Duration(cfg.OfflineQueue.CheckIntervalSeconds) * time.Second
+		go handlers.RunOfflineQueue(ctx, telegramBot, interval)
+	}
+	go handlers.RunBatchPoller(ctx, telegramBot, time.Duration(cfg.Batch.PollIntervalSeconds)*time.Second)
+
+	if cfg.Digest.Enabled {
+		weekday, err := scheduler.ParseWeekday(cfg.Digest.Weekday)
+		if err != nil {
+			return err
+		}
+		if err := sched.Daily(job("digest-daily"), cfg.Digest.At, func(ctx context.Context) {
+			handlers.RunDigests(ctx, telegramBot, digest.Daily)
+		}); err != nil {
+			return err
+		}
+		if err := sched.Weekly(job("digest-weekly"), weekday, cfg.Digest.At, func(ctx context.Context) {
+			handlers.RunDigests(ctx, telegramBot, digest.Weekly)
+		}); err != nil {
+			return err
+		}
+	}
+	if cfg.Feeds.Enabled {
+		if err := sched.Every(job("feeds"), time.Duration(cfg.Feeds.IntervalMinutes)*time.Minute, func(ctx context.Context) {
+			handlers.RunFeeds(ctx, telegramBot)
+		}); err != nil {
+			return err
+		}
+	}
+	return sched.Every(job("quiet-hours"), time.Minute, func(ctx context.Context) {
+		handlers.RunQuietHours(ctx, telegramBot)
+	})
+}
+
+func checkForUpdate(ctx context.Context, checker *version.UpdateChecker, handlers *bot.Handlers, telegramBot *tgbot.Bot) {
+	release, newer, err := checker.Check(ctx)
+	if err != nil {
+		log.Printf("%v", err)
+		return
+	}
+	if newer {
+		log.Printf("helpi %s is available: %s", release.Version, release.URL)
+		handlers.NotifyAdmins(ctx, telegramBot, "update.available", release.Version, version.String(), release.URL)
+	}
+}
+
+// botInstance is one Telegram bot served by this process. Bots other than
+// the default one keep their sessions and settings apart under their ID.
+type botInstance struct {
+	name     string
+	id       int64
+	telegram *tgbot.Bot
+	handlers *bot.Handlers
+}
+
+func newBotInstance(cfg *config.Config, bc config.BotConfig, llmRouter llm.Router, sessionManager session.Manager, shared []bot.Option) (*botInstance, error) {
+	allowedUsers, err := accessList(cfg, bc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load approved users: %w", err)
+	}
+
+	var id int64
+	if bc.Name != "default" {
+		if id, err = tokenBotID(bc.Token); err != nil {
+			return nil, err
+		}
+		sessionManager = session.ForBot(sessionManager, id)
+	}
+	stores, err := botStores(cfg, bc.Name, sessionManager)
 	if err != nil {
-		log.Fatalf("Failed to create Telegram bot: %v", err)
+		return nil, err
 	}
 
-	handlers := bot.NewHandlers(llmRouter, sessionManager, cfg.AllowedUsers)
+	opts := append([]bot.Option(nil), shared...)
+	opts = append(opts, stores...)
+	opts = append(opts, bot.WithBotID(id), bot.WithSystemPrompt(bc.SystemPrompt), bot.WithDefaultProvider(bc.DefaultProvider), bot.WithAllowedUsernames(bc.AllowedUsernames))
+	handlers := bot.NewHandlers(llmRouter, sessionManager, allowedUsers, opts...)
+
+	telegramBot, err := tgbot.New(bc.Token,
+		tgbot.WithDefaultHandler(nil),
+		tgbot.WithHTTPClient(time.Minute, bot.NewFloodControl(&http.Client{Timeout: time.Minute}, 0)),
+		tgbot.WithMiddlewares(middlewares(cfg, handlers)...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Telegram bot: %w", err)
+	}
 
-	telegramBot.RegisterHandler(tgbot.HandlerTypeMessageText, "/start", tgbot.MatchTypeExact, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
-		handlers.StartHandler(ctx, b, update)
+	me, err := telegramBot.GetMe(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the Telegram bot: %w", err)
+	}
+	handlers.RegisterCommands(telegramBot, me.Username)
+	telegramBot.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "access:", tgbot.MatchTypePrefix, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		handlers.AccessCallbackHandler(ctx, b, update)
 	})
-	telegramBot.RegisterHandler(tgbot.HandlerTypeMessageText, "/help", tgbot.MatchTypeExact, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
-		handlers.HelpHandler(ctx, b, update)
+	telegramBot.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "model:", tgbot.MatchTypePrefix, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		handlers.ModelCallbackHandler(ctx, b, update)
 	})
-	telegramBot.RegisterHandler(tgbot.HandlerTypeMessageText, "/myid", tgbot.MatchTypeExact, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
-		handlers.MyIDHandler(ctx, b, update)
+	telegramBot.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "persona:", tgbot.MatchTypePrefix, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		handlers.PersonaCallbackHandler(ctx, b, update)
 	})
-	telegramBot.RegisterHandler(tgbot.HandlerTypeMessageText, "/model", tgbot.MatchTypeExact, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
-		handlers.ModelHandler(ctx, b, update)
+	telegramBot.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "feedback:", tgbot.MatchTypePrefix, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		handlers.FeedbackCallbackHandler(ctx, b, update)
 	})
-	telegramBot.RegisterHandler(tgbot.HandlerTypeMessageText, "/clear", tgbot.MatchTypeExact, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
-		handlers.ClearHandler(ctx, b, update)
+	telegramBot.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "settings:", tgbot.MatchTypePrefix, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		handlers.SettingsCallbackHandler(ctx, b, update)
 	})
+	telegramBot.RegisterHandler(tgbot.HandlerTypeCallbackQueryData, "continue:", tgbot.MatchTypePrefix, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		handlers.ContinueCallbackHandler(ctx, b, update)
+	})
+	telegramBot.RegisterHandlerMatchFunc(bot.IsPhotoMessage, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		handlers.PhotoHandler(ctx, b, update)
+	})
+	telegramBot.RegisterHandlerMatchFunc(bot.IsDocumentMessage, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		handlers.DocumentHandler(ctx, b, update)
+	})
+	if cfg.Telegram.ReprocessEdits {
+		telegramBot.RegisterHandlerMatchFunc(bot.IsEditedMessage, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+			handlers.EditedMessageHandler(ctx, b, update)
+		})
+	}
+	if cfg.Telegram.Inline.Enabled {
+		telegramBot.RegisterHandlerMatchFunc(bot.IsInlineQuery, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+			handlers.InlineQueryHandler(ctx, b, update)
+		})
+	}
 	telegramBot.RegisterHandler(tgbot.HandlerTypeMessageText, "", tgbot.MatchTypeContains, func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
 		handlers.TextMessageHandler(ctx, b, update)
 	})
 
-	log.Printf("Bot started with token: %s...", maskToken(cfg.Telegram.Token))
-	log.Printf("Allowed users count: %d", len(cfg.AllowedUsers))
-	if len(cfg.AllowedUsers) == 0 {
-		log.Println("WARNING: Development mode - no allowed users configured")
+	log.Printf("Bot %s started with token: %s...", bc.Name, maskToken(bc.Token))
+	log.Printf("Bot %s allowed users count: %d", bc.Name, len(allowedUsers))
+	if len(bc.AllowedUsers) == 0 && len(bc.AllowedUsernames) == 0 && len(cfg.Roles.Guests) == 0 &&
+		len(cfg.Roles.Owners) == 0 && len(cfg.Admins) == 0 {
+		log.Printf("WARNING: Development mode - no allowed users configured for bot %s", bc.Name)
 	}
 
-	log.Println("Starting polling...")
+	return &botInstance{name: bc.Name, id: id, telegram: telegramBot, handlers: handlers}, nil
+}
 
-	go func() {
-		telegramBot.Start(ctx)
-	}()
+// middlewares builds the chain every update passes through, outermost first.
+// Recovery wraps everything so a panic anywhere is logged instead of killing
+// the bot, and rate limiting runs after auth so strangers don't use up
+// buckets. Guests have a limiter of their own on top of the shared one.
+func middlewares(cfg *config.Config, handlers *bot.Handlers) []tgbot.Middleware {
+	chain := []tgbot.Middleware{bot.RecoveryMiddleware}
+	if reporter := handlers.ErrorReporter(); reporter != nil {
+		chain = append(chain, reporter.Middleware)
+	}
+	chain = append(chain, bot.LoggingMiddleware, handlers.AuthMiddleware().Middleware)
+	if cfg.RateLimit.MessagesPerMinute > 0 {
+		rateLimiter := bot.NewRateLimitMiddleware(cfg.RateLimit.MessagesPerMinute, cfg.RateLimit.Burst)
+		rateLimiter.SetLanguage(handlers.Language)
+		chain = append(chain, rateLimiter.Middleware)
+	}
+	if len(cfg.Roles.Guests) > 0 {
+		guestLimiter := bot.NewRateLimitMiddleware(cfg.Roles.Guest.MessagesPerMinute, cfg.Roles.Guest.Burst)
+		guestLimiter.SetLanguage(handlers.Language)
+		guestLimiter.SetScope(handlers.IsGuest)
+		chain = append(chain, guestLimiter.Middleware)
+	}
+	return chain
+}
 
-	waitForSignal()
-	log.Println("Shutting down bot...")
+func newSessionManager(cfg *config.Config) (session.Manager, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var store session.Manager
+	var err error
+	if cfg.Memory.Backend == "postgres" {
+		store, err = session.NewPostgresManager(ctx, cfg.APIKeys["DATABASE_URL"], cfg.Memory.MaxMessages)
+	} else {
+		store, err = session.NewManager(cfg.Memory.Path, cfg.Memory.MaxMessages)
+	}
+	if err != nil || !cfg.Memory.Cache.Enabled {
+		return store, err
+	}
+
+	c := cfg.Memory.Cache
+	cache, err := session.NewRedisCache(ctx, c.Addr, cfg.APIKeys["REDIS_PASSWORD"], c.DB)
+	if err != nil {
+		return nil, err
+	}
+	return session.NewCachedManager(store, cache, time.Duration(c.TTLSeconds)*time.Second, cfg.Memory.MaxMessages), nil
+}
+
+func buildRouter(cfg *config.Config, sessionManager session.Manager) (llm.Router, error) {
+	llmRouter, err := llm.NewRouter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	userProviders, err := sessionManager.Providers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user providers: %w", err)
+	}
+	for userID, name := range userProviders {
+		if err := llmRouter.SetDefaultForUser(userID, name); err != nil {
+			log.Printf("Ignoring saved provider %s for user %d: %v", name, userID, err)
+		}
+	}
+
+	return llmRouter, nil
+}
+
+// restoreBotChoices loads the /switch choices made with bots other than the
+// default one into r; buildRouter loads the default bot's.
+func restoreBotChoices(r llm.Router, instances []*botInstance) error {
+	for _, instance := range instances {
+		if instance.id == 0 {
+			continue
+		}
+		if err := instance.handlers.RestoreProviderChoices(r); err != nil {
+			return fmt.Errorf("bot %s: %w", instance.name, err)
+		}
+	}
+	return nil
+}
+
+func accessList(cfg *config.Config, bc config.BotConfig) ([]int64, error) {
+	allowedUsers := append([]int64(nil), bc.AllowedUsers...)
+	if !cfg.Access.ReportUnauthorized || len(allowedUsers)+len(bc.AllowedUsernames)+len(cfg.Roles.Guests) == 0 {
+		return allowedUsers, nil
+	}
+
+	approved, err := bot.LoadApprovedUsers(botPath(cfg.Access.ApprovedPath, bc.Name))
+	if err != nil {
+		return nil, err
+	}
+	return append(allowedUsers, approved...), nil
+}
+
+func watchReload(ctx context.Context, cfg *config.Config, llmRouter *llm.ReloadableRouter, instances []*botInstance, sessionManager session.Manager) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	reporter, telegramBot := instances[0].handlers.ErrorReporter(), instances[0].telegram
+	reloadFailed := func(err error) {
+		log.Printf("Config reload failed, keeping current config: %v", err)
+		reporter.Report(ctx, telegramBot, bot.ErrorKindConfig, fmt.Sprintf("Config reload failed, keeping current config: %v", err))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigChan:
+		}
+
+		log.Println("Reloading configuration...")
+		next, err := config.Load()
+		if err != nil {
+			reloadFailed(err)
+			continue
+		}
+		if err := next.UnknownFields(); err != nil {
+			log.Printf("Ignoring unknown config fields:\n%v", err)
+			reporter.Report(ctx, telegramBot, bot.ErrorKindConfig, fmt.Sprintf("Ignoring unknown config fields:\n%v", err))
+		}
+
+		nextRouter, err := buildRouter(next, sessionManager)
+		if err == nil {
+			err = restoreBotChoices(nextRouter, instances)
+		}
+		if err != nil {
+			reloadFailed(err)
+			continue
+		}
+		access := make(map[string]bot.Access)
+		for _, bc := range next.AllBots() {
+			var allowedUsers []int64
+			if allowedUsers, err = accessList(next, bc); err != nil {
+				break
+			}
+			access[bc.Name] = bot.Access{
+				Owners:    next.Roles.Owners,
+				Admins:    next.Admins,
+				Users:     allowedUsers,
+				Guests:    next.Roles.Guests,
+				Usernames: bc.AllowedUsernames,
+			}
+		}
+		if err != nil {
+			reloadFailed(err)
+			continue
+		}
+
+		llmRouter.Swap(nextRouter)
+		for _, instance := range instances {
+			a, ok := access[instance.name]
+			if !ok {
+				log.Printf("Bot %s is no longer configured; restart to stop it", instance.name)
+				continue
+			}
+			instance.handlers.UpdateAccess(a)
+		}
+
+		changes := config.Changes(cfg, next)
+		if len(changes) == 0 {
+			log.Println("Config reloaded: no changes")
+		}
+		for _, change := range changes {
+			log.Printf("Config reloaded: %s", change)
+		}
+		cfg = next
+	}
 }
 
 func maskToken(token string) string {
@@ -88,10 +712,131 @@ func maskToken(token string) string {
 
 func waitForSignal() {
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	<-sigChan
 }
 
 func init() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 }
+
+// storageDirs lists the directories that hold per-user files: file
+// sessions and uploaded documents.
+func storageDirs(cfg *config.Config) []string {
+	var dirs []string
+	if cfg.Memory.Backend == "file" {
+		dirs = append(dirs, cfg.Memory.Path)
+	}
+	if cfg.Documents.Enabled {
+		dirs = append(dirs, cfg.Documents.Path)
+	}
+	return dirs
+}
+
+// webhookEndpoints resolves the signing secrets of the configured webhooks.
+func webhookEndpoints(cfg *config.Config) []webhook.Endpoint {
+	var endpoints []webhook.Endpoint
+	for _, w := range cfg.Webhooks {
+		endpoints = append(endpoints, webhook.Endpoint{
+			URL:            w.URL,
+			Secret:         cfg.APIKeys[w.SecretEnv],
+			Events:         w.Events,
+			IncludeContent: w.IncludeContent,
+		})
+	}
+	return endpoints
+}
+
+func mqttSubscriptions(cfg *config.Config) []mqtt.Subscription {
+	var subs []mqtt.Subscription
+	for _, s := range cfg.MQTT.Subscriptions {
+		subs = append(subs, mqtt.Subscription{
+			Topic:    s.Topic,
+			ChatID:   s.ChatID,
+			Prompt:   s.Prompt,
+			Provider: s.Provider,
+			Bot:      s.Bot,
+		})
+	}
+	return subs
+}
+
+// botStores opens the stores that hold per-chat state of the bot name:
+// settings, approved users, group modes, queued, held and batched messages,
+// digests and feeds. The default bot uses the configured paths; other bots get files of
+// their own next to them.
+func botStores(cfg *config.Config, name string, sessions session.Manager) ([]bot.Option, error) {
+	var opts []bot.Option
+	if backend, ok := sessions.(settings.Backend); ok {
+		opts = append(opts, bot.WithSettings(settings.New(backend)))
+	}
+
+	if cfg.Access.ReportUnauthorized {
+		reporter := bot.NewAccessReporter(botPath(cfg.Access.ApprovedPath, name))
+		reporter.SetChat(cfg.Telegram.AdminChatID)
+		opts = append(opts, bot.WithAccessReporter(reporter))
+	}
+
+	groupStore, err := groups.NewStore(botPath(cfg.Groups.Path, name), cfg.Groups.DefaultMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize group settings: %w", err)
+	}
+	opts = append(opts, bot.WithGroupStore(groupStore))
+
+	if cfg.OfflineQueue.Enabled {
+		offlineQueue, err := queue.NewQueue(botPath(cfg.OfflineQueue.Path, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize offline queue: %w", err)
+		}
+		opts = append(opts, bot.WithOfflineQueue(offlineQueue))
+	}
+
+	heldMessages, err := queue.NewQueue(botPath(cfg.QuietHours.Path, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize quiet hours: %w", err)
+	}
+	opts = append(opts, bot.WithQuietHours(cfg.QuietHours.Hours, heldMessages))
+
+	batchTracker, err := batch.NewTracker(botPath(cfg.Batch.Path, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize batch tracker: %w", err)
+	}
+	opts = append(opts, bot.WithBatchTracker(batchTracker))
+
+	if cfg.Digest.Enabled {
+		digestStore, err := digest.NewStore(botPath(cfg.Digest.Path, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize digest store: %w", err)
+		}
+		opts = append(opts, bot.WithDigest(digestStore, cfg.Digest.Provider))
+	}
+
+	if cfg.Feeds.Enabled {
+		feedStore, err := feeds.NewStore(botPath(cfg.Feeds.Path, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize feed store: %w", err)
+		}
+		opts = append(opts, bot.WithFeeds(feedStore, cfg.Feeds.Summarize, cfg.Feeds.Provider))
+	}
+	return opts, nil
+}
+
+// botPath returns path for the default bot and path with name before its
+// extension, such as data/queue.support.json, for other bots.
+func botPath(path, name string) string {
+	if name == "default" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + name + ext
+}
+
+// tokenBotID returns the bot ID a Telegram token starts with.
+func tokenBotID(token string) (int64, error) {
+	id, _, _ := strings.Cut(token, ":")
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, errors.New("token does not start with a bot ID")
+	}
+	return n, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func TestBotPath(t *testing.T) {
+	if got := botPath("./data/queue.json", "default"); got != "./data/queue.json" {
+		t.Errorf("expected the default bot to keep the configured path, got %q", got)
+	}
+	if got := botPath("./data/queue.json", "chef"); got != "./data/queue.chef.json" {
+		t.Errorf("expected a file of the bot's own, got %q", got)
+	}
+}
+
+func TestTokenBotID(t *testing.T) {
+	if id, err := tokenBotID("123456:ABC-def"); err != nil || id != 123456 {
+		t.Errorf("expected bot ID 123456, got %d (%v)", id, err)
+	}
+	for _, token := range []string{"chef-token", ":abc", "-5:abc"} {
+		if _, err := tokenBotID(token); err == nil {
+			t.Errorf("expected %q to be rejected", token)
+		}
+	}
+}
+
+func TestAccessList_ReadsTheBotsOwnApprovals(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Access.ReportUnauthorized = true
+	cfg.Access.ApprovedPath = filepath.Join(dir, "approved_users.json")
+	if err := os.WriteFile(cfg.Access.ApprovedPath, []byte("[7]"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "approved_users.chef.json"), []byte("[8]"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := accessList(cfg, config.BotConfig{Name: "chef", AllowedUsers: []int64{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 8 {
+		t.Errorf("expected the chef bot to allow users 1 and 8, got %v", got)
+	}
+}
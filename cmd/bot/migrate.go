@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+// runMigrate implements `helpi migrate --from file --to postgres`, which
+// copies every conversation, thread, setting and usage stat between session
+// backends.
+func runMigrate(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(w)
+	from := fs.String("from", "file", "backend to copy from: file or postgres")
+	to := fs.String("to", "", "backend to copy to: file or postgres")
+	dryRun := fs.Bool("dry-run", false, "report what would be copied without writing anything")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *to == "" || *to == *from || fs.NArg() > 0 {
+		fmt.Fprintln(w, "usage: helpi migrate --from file --to postgres [--dry-run]")
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	src, err := openSessionBackend(ctx, cfg, *from)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+	dst, err := openSessionBackend(ctx, cfg, *to)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+
+	report, err := session.Migrate(ctx, src.(session.Exporter), dst.(session.Importer), *dryRun)
+	action := "Copied"
+	if *dryRun {
+		action = "Would copy"
+	}
+	fmt.Fprintf(w, "%s %d users, %d threads, %d messages, %d stored values and %d usage stats from %s to %s\n",
+		action, report.Users, report.Threads, report.Messages, report.Values, report.Stats, *from, *to)
+	if err != nil {
+		fmt.Fprintf(w, "migration failed: %v\n", err)
+		return 1
+	}
+	if !*dryRun {
+		fmt.Fprintf(w, "Verified. Set memory.backend: %s to use it.\n", *to)
+	}
+	return 0
+}
+
+func openSessionBackend(ctx context.Context, cfg *config.Config, backend string) (session.Manager, error) {
+	switch backend {
+	case "file":
+		return session.NewManager(cfg.Memory.Path, cfg.Memory.MaxMessages)
+	case "postgres":
+		return session.NewPostgresManager(ctx, cfg.APIKeys["DATABASE_URL"], cfg.Memory.MaxMessages)
+	default:
+		return nil, fmt.Errorf("unsupported session backend %q (file or postgres)", backend)
+	}
+}
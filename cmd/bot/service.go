@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	serviceName  = "helpi"
+	launchdLabel = "com.jrswab.helpi"
+)
+
+// serviceOS and runServiceCommand are replaced in tests.
+var (
+	serviceOS         = runtime.GOOS
+	runServiceCommand = func(name string, args ...string) error {
+		out, err := exec.Command(name, args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+)
+
+type serviceSpec struct {
+	binary string
+	dir    string
+	user   bool
+	// runAs is the account a system-level systemd service runs as.
+	runAs string
+}
+
+// runInstallService implements `helpi install-service`, which writes a
+// systemd unit on Linux or a launchd plist on macOS that starts this binary
+// in the config directory, then enables and starts it.
+func runInstallService(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("install-service", flag.ContinueOnError)
+	fs.SetOutput(w)
+	userLevel := fs.Bool("user", false, "install for the current user instead of system-wide")
+	dir := fs.String("config-dir", "", "directory holding config.yaml and .env (default: the current directory)")
+	printOnly := fs.Bool("print", false, "print the service file instead of installing it")
+	noStart := fs.Bool("no-start", false, "install the service without enabling or starting it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	spec, err := newServiceSpec(*dir, *userLevel)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+	path, err := servicePath(spec.user)
+	if err != nil {
+		fmt.Fprintln(w, err)
+		return 1
+	}
+	content := renderService(spec)
+	if *printOnly {
+		fmt.Fprint(w, content)
+		return 0
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Fprintln(w, serviceWriteError(path, err, spec.user))
+		return 1
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		fmt.Fprintln(w, serviceWriteError(path, err, spec.user))
+		return 1
+	}
+	fmt.Fprintf(w, "Wrote %s\n", path)
+
+	commands := startCommands(path, spec.user)
+	if *noStart {
+		fmt.Fprintf(w, "Start it with: %s\n", strings.Join(commands[len(commands)-1], " "))
+		return 0
+	}
+	for _, cmd := range commands {
+		if err := runServiceCommand(cmd[0], cmd[1:]...); err != nil {
+			fmt.Fprintln(w, err)
+			return 1
+		}
+	}
+	fmt.Fprintln(w, "helpi is running as a service")
+	return 0
+}
+
+func newServiceSpec(dir string, userLevel bool) (serviceSpec, error) {
+	binary, err := os.Executable()
+	if err != nil {
+		return serviceSpec{}, fmt.Errorf("failed to find the helpi binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		binary = resolved
+	}
+
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return serviceSpec{}, fmt.Errorf("failed to get current working directory: %w", err)
+		}
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return serviceSpec{}, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.yaml")); err != nil {
+		return serviceSpec{}, fmt.Errorf("config.yaml not found in %s; pass --config-dir", dir)
+	}
+
+	spec := serviceSpec{binary: binary, dir: dir, user: userLevel}
+	// Under sudo, run the system service as the user who invoked it rather
+	// than as root.
+	if !userLevel {
+		spec.runAs = os.Getenv("SUDO_USER")
+	}
+	return spec, nil
+}
+
+func servicePath(userLevel bool) (string, error) {
+	home := ""
+	if userLevel {
+		var err error
+		if home, err = os.UserHomeDir(); err != nil {
+			return "", fmt.Errorf("failed to find your home directory: %w", err)
+		}
+	}
+
+	switch serviceOS {
+	case "linux":
+		if userLevel {
+			return filepath.Join(home, ".config", "systemd", "user", serviceName+".service"), nil
+		}
+		return filepath.Join("/etc", "systemd", "system", serviceName+".service"), nil
+	case "darwin":
+		if userLevel {
+			return filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist"), nil
+		}
+		return filepath.Join("/Library", "LaunchDaemons", launchdLabel+".plist"), nil
+	}
+	return "", fmt.Errorf("install-service supports Linux (systemd) and macOS (launchd), not %s", serviceOS)
+}
+
+func renderService(spec serviceSpec) string {
+	if serviceOS == "darwin" {
+		return renderPlist(spec)
+	}
+	return renderUnit(spec)
+}
+
+func renderUnit(spec serviceSpec) string {
+	var sb strings.Builder
+	sb.WriteString("[Unit]\n")
+	sb.WriteString("Description=helpi Telegram bot\n")
+	sb.WriteString("After=network-online.target\n")
+	sb.WriteString("Wants=network-online.target\n\n")
+	sb.WriteString("[Service]\n")
+	sb.WriteString("Type=simple\n")
+	fmt.Fprintf(&sb, "ExecStart=%s\n", systemdQuote(spec.binary))
+	fmt.Fprintf(&sb, "WorkingDirectory=%s\n", spec.dir)
+	if spec.runAs != "" {
+		fmt.Fprintf(&sb, "User=%s\n", spec.runAs)
+		if u, err := user.Lookup(spec.runAs); err == nil {
+			if g, err := user.LookupGroupId(u.Gid); err == nil {
+				fmt.Fprintf(&sb, "Group=%s\n", g.Name)
+			}
+		}
+	}
+	sb.WriteString("Restart=on-failure\n")
+	sb.WriteString("RestartSec=5\n\n")
+	sb.WriteString("[Install]\n")
+	if spec.user {
+		sb.WriteString("WantedBy=default.target\n")
+	} else {
+		sb.WriteString("WantedBy=multi-user.target\n")
+	}
+	return sb.String()
+}
+
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func renderPlist(spec serviceSpec) string {
+	logPath := filepath.Join(spec.dir, "helpi.log")
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	sb.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	fmt.Fprintf(&sb, "\t<key>Label</key>\n\t<string>%s</string>\n", launchdLabel)
+	fmt.Fprintf(&sb, "\t<key>ProgramArguments</key>\n\t<array>\n\t\t<string>%s</string>\n\t</array>\n", xmlEscape(spec.binary))
+	fmt.Fprintf(&sb, "\t<key>WorkingDirectory</key>\n\t<string>%s</string>\n", xmlEscape(spec.dir))
+	sb.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	sb.WriteString("\t<key>KeepAlive</key>\n\t<true/>\n")
+	fmt.Fprintf(&sb, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(logPath))
+	fmt.Fprintf(&sb, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(logPath))
+	sb.WriteString("</dict>\n</plist>\n")
+	return sb.String()
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// startCommands returns the commands that load and start the installed
+// service, the last of which starts it.
+func startCommands(path string, userLevel bool) [][]string {
+	if serviceOS == "darwin" {
+		return [][]string{{"launchctl", "load", "-w", path}}
+	}
+	systemctl := []string{"systemctl"}
+	if userLevel {
+		systemctl = append(systemctl, "--user")
+	}
+	return [][]string{
+		append(append([]string(nil), systemctl...), "daemon-reload"),
+		append(append([]string(nil), systemctl...), "enable", "--now", serviceName+".service"),
+	}
+}
+
+func serviceWriteError(path string, err error, userLevel bool) error {
+	if !userLevel && errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("cannot write %s: run with sudo or pass --user", path)
+	}
+	return fmt.Errorf("cannot write %s: %w", path, err)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderUnit(t *testing.T) {
+	defer func(goos string) { serviceOS = goos }(serviceOS)
+	serviceOS = "linux"
+
+	unit := renderService(serviceSpec{binary: "/opt/helpi bot/helpi", dir: "/srv/helpi", runAs: "helpi"})
+	for _, want := range []string{
+		`ExecStart="/opt/helpi bot/helpi"`,
+		"WorkingDirectory=/srv/helpi",
+		"User=helpi",
+		"WantedBy=multi-user.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("expected unit to contain %q, got:\n%s", want, unit)
+		}
+	}
+
+	unit = renderService(serviceSpec{binary: "/usr/local/bin/helpi", dir: "/home/me/helpi", user: true})
+	if !strings.Contains(unit, "WantedBy=default.target") || strings.Contains(unit, "User=") {
+		t.Errorf("unexpected user unit:\n%s", unit)
+	}
+}
+
+func TestRenderPlist(t *testing.T) {
+	defer func(goos string) { serviceOS = goos }(serviceOS)
+	serviceOS = "darwin"
+
+	plist := renderService(serviceSpec{binary: "/Applications/helpi", dir: "/Users/me/R&D", user: true})
+	for _, want := range []string{
+		"<string>com.jrswab.helpi</string>",
+		"<string>/Applications/helpi</string>",
+		"<string>/Users/me/R&amp;D</string>",
+		"<string>/Users/me/R&amp;D/helpi.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("expected plist to contain %q, got:\n%s", want, plist)
+		}
+	}
+}
+
+func TestRunInstallService_User(t *testing.T) {
+	defer func(goos string) { serviceOS = goos }(serviceOS)
+	defer func(run func(string, ...string) error) { runServiceCommand = run }(runServiceCommand)
+	serviceOS = "linux"
+	var ran []string
+	runServiceCommand = func(name string, args ...string) error {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		return nil
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("telegram: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if code := runInstallService([]string{"--user", "--config-dir", dir}, &out); code != 0 {
+		t.Fatalf("exit code %d: %s", code, out.String())
+	}
+
+	unit, err := os.ReadFile(filepath.Join(home, ".config", "systemd", "user", "helpi.service"))
+	if err != nil {
+		t.Fatalf("unit not written: %v", err)
+	}
+	if !strings.Contains(string(unit), "WorkingDirectory="+dir) {
+		t.Errorf("unexpected unit:\n%s", unit)
+	}
+	want := []string{"systemctl --user daemon-reload", "systemctl --user enable --now helpi.service"}
+	if strings.Join(ran, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected commands %v, got %v", want, ran)
+	}
+}
+
+func TestRunInstallService_MissingConfig(t *testing.T) {
+	var out bytes.Buffer
+	if code := runInstallService([]string{"--print", "--config-dir", t.TempDir()}, &out); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(out.String(), "config.yaml not found") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
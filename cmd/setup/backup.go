@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// writeWithBackup replaces path with data, readable by the owner only. An
+// existing file with different contents is first copied to
+// path.<stamp>.bak.
+func writeWithBackup(path string, data []byte, stamp string) error {
+	old, err := os.ReadFile(path)
+	switch {
+	case err == nil && string(old) != string(data):
+		backup := path + "." + stamp + ".bak"
+		if err := os.WriteFile(backup, old, 0600); err != nil {
+			return fmt.Errorf("failed to back up %s: %v", path, err)
+		}
+	case err != nil && !os.IsNotExist(err):
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	// WriteFile keeps the mode of an existing file.
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict permissions of %s: %v", path, err)
+	}
+	return nil
+}
+
+// pendingChanges describes how saving files would change config.yaml and
+// .env on disk. Values in .env are masked. It is empty when nothing changes.
+func pendingChanges(files savedFiles) string {
+	var sb strings.Builder
+	for _, f := range []struct {
+		path string
+		data []byte
+		mask bool
+	}{
+		{"config.yaml", files.config, false},
+		{".env", files.env, true},
+	} {
+		old, err := os.ReadFile(f.path)
+		if err != nil {
+			continue
+		}
+		diff := lineDiff(string(old), string(f.data))
+		if diff == "" {
+			continue
+		}
+		if f.mask {
+			diff = maskEnv(diff)
+		}
+		sb.WriteString("--- " + f.path + "\n" + diff)
+	}
+	return sb.String()
+}
+
+// confirmChanges shows the changes saving files would make to existing
+// files and asks whether to go ahead.
+func confirmChanges(reader *bufio.Reader, files savedFiles) bool {
+	diff := pendingChanges(files)
+	if diff == "" {
+		return true
+	}
+
+	fmt.Println()
+	fmt.Print(diff)
+	for {
+		fmt.Print("Write these changes? Backups of the current files are kept. (y/n) [y]: ")
+		switch strings.ToLower(readLine(reader)) {
+		case "", "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+		fmt.Println("Please enter y or n")
+	}
+}
+
+func maskEnv(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			lines[i] = key + "=" + maskString(value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineDiff returns the lines removed from old and added in new, prefixed
+// with - and +, in file order. Unchanged lines are left out.
+func lineDiff(old, new string) string {
+	a, b := splitLines(old), splitLines(new)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
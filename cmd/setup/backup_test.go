@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("OPENAI_API_KEY=old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeWithBackup(path, []byte("OPENAI_API_KEY=new\n"), "20260101-120000"); err != nil {
+		t.Fatalf("writeWithBackup failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected 0600, got %v", info.Mode().Perm())
+	}
+	backup, err := os.ReadFile(path + ".20260101-120000.bak")
+	if err != nil || string(backup) != "OPENAI_API_KEY=old\n" {
+		t.Errorf("expected the old file to be backed up, got %q, %v", backup, err)
+	}
+
+	if err := writeWithBackup(path, []byte("OPENAI_API_KEY=new\n"), "20260101-130000"); err != nil {
+		t.Fatalf("writeWithBackup failed: %v", err)
+	}
+	if _, err := os.Stat(path + ".20260101-130000.bak"); !os.IsNotExist(err) {
+		t.Error("expected no backup when the contents are unchanged")
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	old := "telegram:\n  token: abc\nmemory:\n  max_messages: 50\n"
+	new := "telegram:\n  token: abc\nmemory:\n  max_messages: 100\n  path: ./data\n"
+
+	want := "-   max_messages: 50\n+   max_messages: 100\n+   path: ./data\n"
+	if got := lineDiff(old, new); got != want {
+		t.Errorf("lineDiff() =\n%s\nwant\n%s", got, want)
+	}
+	if got := lineDiff(old, old); got != "" {
+		t.Errorf("expected no diff for equal contents, got %q", got)
+	}
+}
+
+func TestPendingChanges_MasksSecrets(t *testing.T) {
+	origCwd, _ := os.Getwd()
+	defer os.Chdir(origCwd)
+	os.Chdir(t.TempDir())
+
+	if err := os.WriteFile(".env", []byte("OPENAI_API_KEY=sk-old-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	diff := pendingChanges(savedFiles{env: []byte("OPENAI_API_KEY=sk-new-secret\n")})
+	if strings.Contains(diff, "sk-old") || strings.Contains(diff, "sk-new") {
+		t.Errorf("expected secrets to be masked, got %q", diff)
+	}
+	if !strings.Contains(diff, "--- .env") || !strings.Contains(diff, "+ OPENAI_API_KEY=****cret") {
+		t.Errorf("expected a masked diff of .env, got %q", diff)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+type setupFlags struct {
+	token          string
+	enable         stringList
+	allowedUsers   string
+	memoryPath     string
+	maxMessages    int
+	yes            bool
+	skipValidation bool
+	keyring        bool
+}
+
+func parseFlags(args []string, output io.Writer) (*setupFlags, error) {
+	f := &setupFlags{}
+	fs := flag.NewFlagSet("setup", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&f.token, "token", "", "Telegram bot token (defaults to $TELEGRAM_BOT_TOKEN)")
+	fs.Var(&f.enable, "enable", "enable a provider as name or name=model; repeatable")
+	fs.StringVar(&f.allowedUsers, "allowed-users", "", "comma-separated Telegram user IDs")
+	fs.StringVar(&f.memoryPath, "memory-path", "", "session storage path")
+	fs.IntVar(&f.maxMessages, "max-messages", -1, "max messages per conversation (0 to retain all)")
+	fs.BoolVar(&f.yes, "yes", false, "write config.yaml and .env without prompting")
+	fs.BoolVar(&f.skipValidation, "skip-validation", false, "do not test provider connections before saving")
+	fs.BoolVar(&f.keyring, "keyring", false, "store the token and API keys in the OS keyring instead of .env")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return f, nil
+}
+
+// applyFlags overlays flag values on the existing configuration. In
+// interactive mode they become the defaults shown in each prompt.
+func applyFlags(cfg *ExistingConfig, f *setupFlags) error {
+	if f.token != "" {
+		cfg.Telegram = f.token
+	}
+
+	for _, entry := range f.enable {
+		name, model, _ := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := providerDefaults[name]; !ok {
+			return fmt.Errorf("unknown provider %q in --enable", name)
+		}
+		if model = strings.TrimSpace(model); model == "" {
+			model = getProviderModel(cfg.Providers, name)
+		}
+		if model == "" {
+			model = providerDefaults[name]
+		}
+		setProviderEnabled(&cfg.Providers, name, true)
+		setProviderModel(&cfg.Providers, name, model)
+	}
+
+	if f.allowedUsers != "" {
+		ids, err := parseUserIDs(f.allowedUsers)
+		if err != nil {
+			return err
+		}
+		cfg.AllowedUsers = ids
+	}
+
+	if f.memoryPath != "" {
+		cfg.Memory.Path = f.memoryPath
+	}
+	if f.maxMessages >= 0 {
+		cfg.Memory.MaxMessages = f.maxMessages
+	}
+
+	return nil
+}
+
+// completeNonInteractive fills in what the prompts would otherwise ask for
+// and rejects configurations the bot would refuse to start with.
+func completeNonInteractive(cfg *ExistingConfig) error {
+	if cfg.Telegram == "" {
+		cfg.Telegram = cfg.APIKeys["TELEGRAM_BOT_TOKEN"]
+	}
+	if cfg.Telegram == "" {
+		return errors.New("telegram token is required (--token or TELEGRAM_BOT_TOKEN)")
+	}
+
+	enabled := 0
+	for _, name := range providerList {
+		if !isProviderEnabled(cfg.Providers, name) {
+			continue
+		}
+		enabled++
+
+		envKey := providerEnvKeys[name]
+		if cfg.APIKeys[envKey] == "" {
+			if name != "ollama" {
+				return fmt.Errorf("%s is enabled but %s is not set", name, envKey)
+			}
+			cfg.APIKeys[envKey] = defaultOllamaURL
+		}
+		if getProviderModel(cfg.Providers, name) == "" {
+			setProviderModel(&cfg.Providers, name, providerDefaults[name])
+		}
+	}
+	if enabled == 0 {
+		return errors.New("at least one provider must be enabled (--enable)")
+	}
+
+	return nil
+}
+
+func parseUserIDs(input string) ([]int64, error) {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user ID: %s. Must be numeric", part)
+		}
+
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func newTestConfig() *ExistingConfig {
+	return &ExistingConfig{
+		APIKeys: make(map[string]string),
+		Memory:  MemoryConfig{Path: "./data/sessions", MaxMessages: 50},
+	}
+}
+
+func TestApplyFlags(t *testing.T) {
+	opts, err := parseFlags([]string{
+		"--token", "123:abc",
+		"--enable", "openai=gpt-4o-mini",
+		"--enable", "ollama",
+		"--allowed-users", "1, 2,2",
+		"--max-messages", "0",
+		"--yes",
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseFlags() returned error: %v", err)
+	}
+
+	cfg := newTestConfig()
+	if err := applyFlags(cfg, opts); err != nil {
+		t.Fatalf("applyFlags() returned error: %v", err)
+	}
+
+	if !opts.yes || cfg.Telegram != "123:abc" {
+		t.Errorf("unexpected token/yes: %q %v", cfg.Telegram, opts.yes)
+	}
+	if !cfg.Providers.OpenAI.Enabled || cfg.Providers.OpenAI.DefaultModel != "gpt-4o-mini" {
+		t.Errorf("unexpected openai config %+v", cfg.Providers.OpenAI)
+	}
+	if !cfg.Providers.Ollama.Enabled || cfg.Providers.Ollama.DefaultModel != providerDefaults["ollama"] {
+		t.Errorf("unexpected ollama config %+v", cfg.Providers.Ollama)
+	}
+	if len(cfg.AllowedUsers) != 2 || cfg.AllowedUsers[0] != 1 || cfg.AllowedUsers[1] != 2 {
+		t.Errorf("unexpected allowed users %v", cfg.AllowedUsers)
+	}
+	if cfg.Memory.MaxMessages != 0 || cfg.Memory.Path != "./data/sessions" {
+		t.Errorf("unexpected memory config %+v", cfg.Memory)
+	}
+}
+
+func TestApplyFlags_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "unknown provider", args: []string{"--enable", "gemini"}, wantErr: "unknown provider"},
+		{name: "bad user id", args: []string{"--allowed-users", "1,abc"}, wantErr: "invalid user ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseFlags(tt.args, io.Discard)
+			if err != nil {
+				t.Fatalf("parseFlags() returned error: %v", err)
+			}
+			if err := applyFlags(newTestConfig(), opts); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCompleteNonInteractive(t *testing.T) {
+	cfg := newTestConfig()
+	if err := completeNonInteractive(cfg); err == nil || !strings.Contains(err.Error(), "token") {
+		t.Errorf("expected missing token error, got %v", err)
+	}
+
+	cfg.APIKeys["TELEGRAM_BOT_TOKEN"] = "123:abc"
+	if err := completeNonInteractive(cfg); err == nil || !strings.Contains(err.Error(), "at least one provider") {
+		t.Errorf("expected missing provider error, got %v", err)
+	}
+
+	cfg.Providers.Anthropic.Enabled = true
+	if err := completeNonInteractive(cfg); err == nil || !strings.Contains(err.Error(), "ANTHROPIC_API_KEY") {
+		t.Errorf("expected missing API key error, got %v", err)
+	}
+
+	cfg.Providers.Anthropic.Enabled = false
+	cfg.Providers.Ollama.Enabled = true
+	if err := completeNonInteractive(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Telegram != "123:abc" || cfg.APIKeys["OLLAMA_BASE_URL"] != defaultOllamaURL {
+		t.Errorf("expected token and ollama URL to be filled in, got %q %q", cfg.Telegram, cfg.APIKeys["OLLAMA_BASE_URL"])
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// promptLocal lists the local servers that are running and offers to use
+// one of them through the local provider.
+func promptLocal(reader *bufio.Reader, local ProviderConfig, apiKeys map[string]string, servers []llm.LocalServer) ProviderConfig {
+	if len(servers) == 0 {
+		return local
+	}
+
+	fmt.Println("Found local model servers:")
+	for i, s := range servers {
+		fmt.Printf("  %d. %s at %s (%d models)\n", i+1, s.Name, s.BaseURL, len(s.Models))
+	}
+
+	var server llm.LocalServer
+	for {
+		fmt.Print("Use a local server? (number, or n) [1]: ")
+		input := strings.ToLower(readLine(reader))
+		if input == "n" || input == "no" {
+			local.Enabled = false
+			return local
+		}
+		if input == "" || input == "y" || input == "yes" {
+			input = "1"
+		}
+		i, err := strconv.Atoi(input)
+		if err == nil && i >= 1 && i <= len(servers) {
+			server = servers[i-1]
+			break
+		}
+		fmt.Printf("Please enter a number from 1 to %d, or n\n", len(servers))
+	}
+
+	local.Enabled = true
+	apiKeys["LOCAL_BASE_URL"] = server.BaseURL
+
+	defaultModel := local.DefaultModel
+	if defaultModel == "" && len(server.Models) > 0 {
+		defaultModel = server.Models[0]
+	}
+	local.DefaultModel = promptModel(reader, "local", defaultModel)
+	return local
+}
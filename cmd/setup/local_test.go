@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestPromptLocal(t *testing.T) {
+	servers := []llm.LocalServer{
+		{Name: "LM Studio", BaseURL: "http://localhost:1234/v1", Models: []string{"qwen2.5-7b-instruct"}},
+		{Name: "llama.cpp", BaseURL: "http://localhost:8080/v1"},
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantURL   string
+		wantModel string
+	}{
+		{name: "default", input: "\n\n", wantURL: "http://localhost:1234/v1", wantModel: "qwen2.5-7b-instruct"},
+		{name: "second", input: "7\n2\nmistral\n", wantURL: "http://localhost:8080/v1", wantModel: "mistral"},
+		{name: "declined", input: "n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeys := map[string]string{}
+			local := promptLocal(bufio.NewReader(strings.NewReader(tt.input)), ProviderConfig{}, apiKeys, servers)
+			if local.Enabled != (tt.wantURL != "") || apiKeys["LOCAL_BASE_URL"] != tt.wantURL || local.DefaultModel != tt.wantModel {
+				t.Errorf("got %+v with LOCAL_BASE_URL %q", local, apiKeys["LOCAL_BASE_URL"])
+			}
+		})
+	}
+}
+
+func TestPromptLocal_NothingFound(t *testing.T) {
+	local := promptLocal(bufio.NewReader(strings.NewReader("")), ProviderConfig{Enabled: true}, map[string]string{}, nil)
+	if !local.Enabled {
+		t.Error("expected the existing setting to be kept")
+	}
+}
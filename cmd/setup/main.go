@@ -2,13 +2,19 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/secrets"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,6 +24,7 @@ type ExistingConfig struct {
 	Providers    ProvidersConfig   `yaml:"providers" json:"providers"`
 	Memory       MemoryConfig      `yaml:"memory" json:"memory"`
 	APIKeys      map[string]string `yaml:"-" json:"-"`
+	UseKeyring   bool              `yaml:"-" json:"-"`
 }
 
 type ProvidersConfig struct {
@@ -26,6 +33,7 @@ type ProvidersConfig struct {
 	OpenRouter ProviderConfig `yaml:"openrouter" json:"openrouter"`
 	OpenCode   ProviderConfig `yaml:"opencode" json:"opencode"`
 	Ollama     ProviderConfig `yaml:"ollama" json:"ollama"`
+	Local      ProviderConfig `yaml:"local" json:"local"`
 }
 
 type ProviderConfig struct {
@@ -54,7 +62,24 @@ var providerEnvKeys = map[string]string{
 	"ollama":     "OLLAMA_BASE_URL",
 }
 
+// keyringKeys are the secrets saveConfig moves to the OS keyring when
+// UseKeyring is set. OLLAMA_BASE_URL and LOCAL_BASE_URL are not secrets and
+// stay in .env.
+var keyringKeys = []string{"TELEGRAM_BOT_TOKEN", "OPENAI_API_KEY", "ANTHROPIC_API_KEY", "OPENROUTER_API_KEY", "OPENCODE_API_KEY"}
+
+var providerList = []string{"openai", "anthropic", "openrouter", "opencode", "ollama"}
+
+const defaultOllamaURL = "http://localhost:11434"
+
 func main() {
+	opts, err := parseFlags(os.Args[1:], os.Stderr)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	cfg := &ExistingConfig{
@@ -73,14 +98,57 @@ func main() {
 	}
 
 	loadExistingConfig(cfg)
+	if err := applyFlags(cfg, opts); err != nil {
+		fmt.Printf("✗ Error: %v\n", err)
+		os.Exit(2)
+	}
 
-	fmt.Println("=== Helpi Setup Wizard ===")
-	fmt.Println()
+	client := &http.Client{}
+	if opts.yes {
+		if opts.keyring && !secrets.KeyringAvailable() {
+			fmt.Println("✗ Error: --keyring was given but no OS keyring is available")
+			os.Exit(1)
+		}
+		cfg.UseKeyring = opts.keyring
+		if err := completeNonInteractive(cfg); err != nil {
+			fmt.Printf("✗ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !opts.skipValidation {
+			if err := validateProviders(client, cfg); err != nil {
+				fmt.Printf("✗ Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	} else {
+		fmt.Println("=== Helpi Setup Wizard ===")
+		fmt.Println()
+
+		cfg.Telegram = promptToken(reader, cfg.Telegram)
+		var tester *http.Client
+		if !opts.skipValidation {
+			tester = client
+		}
+		cfg.Providers = promptProviders(reader, cfg.Providers, cfg.APIKeys, tester)
+		cfg.Providers.Local = promptLocal(reader, cfg.Providers.Local, cfg.APIKeys, llm.DiscoverLocalServers(context.Background(), client))
+		cfg.AllowedUsers = promptAllowedUsers(reader, cfg.AllowedUsers)
+		cfg.Memory = promptMemory(reader, cfg.Memory)
+		if secrets.KeyringAvailable() {
+			cfg.UseKeyring = promptKeyring(reader)
+		}
+	}
 
-	cfg.Telegram = promptToken(reader, cfg.Telegram)
-	cfg.Providers = promptProviders(reader, cfg.Providers, cfg.APIKeys)
-	cfg.AllowedUsers = promptAllowedUsers(reader, cfg.AllowedUsers)
-	cfg.Memory = promptMemory(reader, cfg.Memory)
+	if !opts.yes {
+		files, err := renderConfig(cfg)
+		if err != nil {
+			fmt.Printf("✗ Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !confirmChanges(reader, files) {
+			fmt.Println("Nothing was written.")
+			return
+		}
+	}
 
 	if err := saveConfig(cfg); err != nil {
 		fmt.Printf("✗ Error: %v\n", err)
@@ -88,7 +156,11 @@ func main() {
 	}
 
 	fmt.Println("✓ Configuration saved to config.yaml")
-	fmt.Println("✓ Secrets saved to .env")
+	if cfg.UseKeyring {
+		fmt.Println("✓ Secrets saved to the OS keyring")
+	} else {
+		fmt.Println("✓ Secrets saved to .env")
+	}
 	fmt.Println()
 	fmt.Println("Run the bot with: go run ./cmd/bot")
 }
@@ -119,6 +191,27 @@ func loadExistingConfig(cfg *ExistingConfig) {
 	cfg.APIKeys["OPENROUTER_API_KEY"] = os.Getenv("OPENROUTER_API_KEY")
 	cfg.APIKeys["OPENCODE_API_KEY"] = os.Getenv("OPENCODE_API_KEY")
 	cfg.APIKeys["OLLAMA_BASE_URL"] = os.Getenv("OLLAMA_BASE_URL")
+	cfg.APIKeys["LOCAL_BASE_URL"] = os.Getenv("LOCAL_BASE_URL")
+	for _, key := range keyringKeys {
+		if cfg.APIKeys[key] == "" {
+			cfg.APIKeys[key] = secrets.FromKeyring(key)
+		}
+	}
+}
+
+// promptKeyring offers to keep secrets in the OS keyring rather than in a
+// plain .env file.
+func promptKeyring(reader *bufio.Reader) bool {
+	for {
+		fmt.Print("Store the token and API keys in the OS keyring instead of .env? (y/n) [y]: ")
+		switch strings.ToLower(readLine(reader)) {
+		case "", "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+		fmt.Println("Please enter y or n")
+	}
 }
 
 func promptToken(reader *bufio.Reader, current string) string {
@@ -140,9 +233,9 @@ func promptToken(reader *bufio.Reader, current string) string {
 	}
 }
 
-func promptProviders(reader *bufio.Reader, providers ProvidersConfig, apiKeys map[string]string) ProvidersConfig {
-	providerList := []string{"openai", "anthropic", "openrouter", "opencode", "ollama"}
-
+// promptProviders asks about each provider. When tester is non-nil the user
+// is offered a connection test after entering a key.
+func promptProviders(reader *bufio.Reader, providers ProvidersConfig, apiKeys map[string]string, tester *http.Client) ProvidersConfig {
 	for _, name := range providerList {
 		enabled := isProviderEnabled(providers, name)
 		defaultEnabled := "n"
@@ -174,6 +267,9 @@ func promptProviders(reader *bufio.Reader, providers ProvidersConfig, apiKeys ma
 			currentKey := apiKeys[envKey]
 
 			apiKeys[envKey] = promptAPIKey(reader, name, currentKey, envKey)
+			for tester != nil && promptConnectionTest(reader, tester, name, apiKeys[envKey]) {
+				apiKeys[envKey] = promptAPIKey(reader, name, "", envKey)
+			}
 
 			if apiKeys[envKey] == "" && envKey != "OLLAMA_BASE_URL" {
 				fmt.Printf("Warning: %s is enabled but no API key is set - provider may not work\n", name)
@@ -197,7 +293,7 @@ func promptAPIKey(reader *bufio.Reader, provider, current, envKey string) string
 		if current != "" {
 			prompt = prompt + "[SET]: "
 		} else if envKey == "OLLAMA_BASE_URL" {
-			prompt = prompt + "[http://localhost:11434]: "
+			prompt = prompt + "[" + defaultOllamaURL + "]: "
 		}
 
 		fmt.Print(prompt)
@@ -210,7 +306,7 @@ func promptAPIKey(reader *bufio.Reader, provider, current, envKey string) string
 			return current
 		}
 		if envKey == "OLLAMA_BASE_URL" {
-			return "http://localhost:11434"
+			return defaultOllamaURL
 		}
 		fmt.Printf("Error: %s API Key is required\n", provider)
 	}
@@ -252,31 +348,9 @@ func promptAllowedUsers(reader *bufio.Reader, current []int64) []int64 {
 			return current
 		}
 
-		parts := strings.Split(input, ",")
-		var ids []int64
-		seen := make(map[int64]bool)
-		hasError := false
-
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if part == "" {
-				continue
-			}
-
-			id, err := strconv.ParseInt(part, 10, 64)
-			if err != nil {
-				fmt.Printf("Error: Invalid user ID: %s. Must be numeric.\n", part)
-				hasError = true
-				break
-			}
-
-			if !seen[id] {
-				seen[id] = true
-				ids = append(ids, id)
-			}
-		}
-
-		if hasError {
+		ids, err := parseUserIDs(input)
+		if err != nil {
+			fmt.Printf("Error: %v.\n", err)
 			continue
 		}
 
@@ -310,50 +384,75 @@ func promptMemory(reader *bufio.Reader, memory MemoryConfig) MemoryConfig {
 	return memory
 }
 
-func saveConfig(cfg *ExistingConfig) error {
+// savedFiles is what saveConfig writes: config.yaml, .env and the secrets
+// that go to the OS keyring instead of .env.
+type savedFiles struct {
+	config  []byte
+	env     []byte
+	keyring map[string]string
+}
+
+func renderConfig(cfg *ExistingConfig) (savedFiles, error) {
+	values := map[string]string{"TELEGRAM_BOT_TOKEN": cfg.Telegram}
+	for _, key := range []string{"OPENAI_API_KEY", "ANTHROPIC_API_KEY", "OPENROUTER_API_KEY", "OPENCODE_API_KEY", "OLLAMA_BASE_URL", "LOCAL_BASE_URL"} {
+		values[key] = cfg.APIKeys[key]
+	}
+
+	out := savedFiles{keyring: make(map[string]string)}
+	if cfg.UseKeyring {
+		for _, key := range keyringKeys {
+			if values[key] != "" {
+				out.keyring[key] = values[key]
+			}
+		}
+	}
+
 	yamlData := map[string]interface{}{
-		"telegram": map[string]string{
-			"token": cfg.Telegram,
-		},
 		"allowed_users": cfg.AllowedUsers,
 		"providers":     cfg.Providers,
 		"memory":        cfg.Memory,
 	}
+	if _, ok := out.keyring["TELEGRAM_BOT_TOKEN"]; !ok {
+		yamlData["telegram"] = map[string]string{
+			"token": cfg.Telegram,
+		}
+	}
 
 	data, err := yaml.Marshal(yamlData)
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %v", err)
-	}
-
-	if err := os.WriteFile("config.yaml", data, 0644); err != nil {
-		return fmt.Errorf("failed to write config.yaml: %v", err)
+		return savedFiles{}, fmt.Errorf("failed to marshal config: %v", err)
 	}
+	out.config = data
 
 	envContent := ""
-	if cfg.Telegram != "" {
-		envContent += fmt.Sprintf("TELEGRAM_BOT_TOKEN=%s\n", cfg.Telegram)
-	}
-	if cfg.APIKeys["OPENAI_API_KEY"] != "" {
-		envContent += fmt.Sprintf("OPENAI_API_KEY=%s\n", cfg.APIKeys["OPENAI_API_KEY"])
-	}
-	if cfg.APIKeys["ANTHROPIC_API_KEY"] != "" {
-		envContent += fmt.Sprintf("ANTHROPIC_API_KEY=%s\n", cfg.APIKeys["ANTHROPIC_API_KEY"])
-	}
-	if cfg.APIKeys["OPENROUTER_API_KEY"] != "" {
-		envContent += fmt.Sprintf("OPENROUTER_API_KEY=%s\n", cfg.APIKeys["OPENROUTER_API_KEY"])
-	}
-	if cfg.APIKeys["OPENCODE_API_KEY"] != "" {
-		envContent += fmt.Sprintf("OPENCODE_API_KEY=%s\n", cfg.APIKeys["OPENCODE_API_KEY"])
+	for _, key := range []string{"TELEGRAM_BOT_TOKEN", "OPENAI_API_KEY", "ANTHROPIC_API_KEY", "OPENROUTER_API_KEY", "OPENCODE_API_KEY", "OLLAMA_BASE_URL", "LOCAL_BASE_URL"} {
+		if _, ok := out.keyring[key]; values[key] != "" && !ok {
+			envContent += fmt.Sprintf("%s=%s\n", key, values[key])
+		}
 	}
-	if cfg.APIKeys["OLLAMA_BASE_URL"] != "" {
-		envContent += fmt.Sprintf("OLLAMA_BASE_URL=%s\n", cfg.APIKeys["OLLAMA_BASE_URL"])
+	out.env = []byte(envContent)
+	return out, nil
+}
+
+// saveConfig writes config.yaml and .env readable by the owner only, since
+// both hold secrets. Files being replaced are kept as timestamped backups.
+func saveConfig(cfg *ExistingConfig) error {
+	files, err := renderConfig(cfg)
+	if err != nil {
+		return err
 	}
 
-	if err := os.WriteFile(".env", []byte(envContent), 0644); err != nil {
-		return fmt.Errorf("failed to write .env: %v", err)
+	for key, value := range files.keyring {
+		if err := secrets.StoreInKeyring(key, value); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	stamp := time.Now().Format("20060102-150405")
+	if err := writeWithBackup("config.yaml", files.config, stamp); err != nil {
+		return err
+	}
+	return writeWithBackup(".env", files.env, stamp)
 }
 
 func isProviderEnabled(providers ProvidersConfig, name string) bool {
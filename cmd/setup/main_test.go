@@ -6,6 +6,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/jrswab/helpi/internal/secrets"
+	"github.com/zalando/go-keyring"
 	"gopkg.in/yaml.v3"
 )
 
@@ -211,6 +213,56 @@ func TestSaveConfig_YAMLMarshaling(t *testing.T) {
 	}
 }
 
+func TestSaveConfig_Keyring(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/tmp/test-bus")
+
+	tmpDir := t.TempDir()
+	origCwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+	defer os.Chdir(origCwd)
+	os.Chdir(tmpDir)
+
+	cfg := &ExistingConfig{
+		Telegram:     "test-token-123",
+		AllowedUsers: []int64{123456789},
+		Providers: ProvidersConfig{
+			OpenAI: ProviderConfig{Enabled: true, DefaultModel: "gpt-4o"},
+			Ollama: ProviderConfig{Enabled: true, DefaultModel: "llama3.2"},
+		},
+		Memory: MemoryConfig{Path: "./data/sessions", MaxMessages: 50},
+		APIKeys: map[string]string{
+			"OPENAI_API_KEY":  "sk-test-key",
+			"OLLAMA_BASE_URL": "http://localhost:11434",
+		},
+		UseKeyring: true,
+	}
+	if err := saveConfig(cfg); err != nil {
+		t.Fatalf("saveConfig failed: %v", err)
+	}
+
+	if got := secrets.FromKeyring("OPENAI_API_KEY"); got != "sk-test-key" {
+		t.Errorf("expected the API key in the keyring, got %q", got)
+	}
+	if got := secrets.FromKeyring("TELEGRAM_BOT_TOKEN"); got != "test-token-123" {
+		t.Errorf("expected the token in the keyring, got %q", got)
+	}
+
+	envData, _ := os.ReadFile(".env")
+	if contains(string(envData), "sk-test-key") || contains(string(envData), "test-token-123") {
+		t.Errorf("expected no secrets in .env, got %q", envData)
+	}
+	if !contains(string(envData), "OLLAMA_BASE_URL=http://localhost:11434") {
+		t.Errorf("expected OLLAMA_BASE_URL to stay in .env, got %q", envData)
+	}
+	configData, _ := os.ReadFile("config.yaml")
+	if contains(string(configData), "test-token-123") {
+		t.Errorf("expected no token in config.yaml, got %q", configData)
+	}
+}
+
 func TestSaveConfig_WriteError(t *testing.T) {
 	origCwd, err := os.Getwd()
 	if err != nil {
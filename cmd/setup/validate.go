@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const connectionTimeout = 15 * time.Second
+
+var providerCheckURLs = map[string]string{
+	"openai":     "https://api.openai.com/v1/models",
+	"anthropic":  "https://api.anthropic.com/v1/models",
+	"openrouter": "https://openrouter.ai/api/v1/key",
+	"opencode":   "https://opencode.ai/zen/v1/models",
+}
+
+// testConnection makes a cheap authenticated request that fails fast on a
+// bad key. For Ollama the "key" is the server's base URL.
+func testConnection(ctx context.Context, client *http.Client, provider, key string) error {
+	url := providerCheckURLs[provider]
+	if provider == "ollama" {
+		url = strings.TrimSuffix(strings.TrimSuffix(key, "/"), "/v1") + "/api/tags"
+	}
+	if url == "" {
+		return fmt.Errorf("unknown provider %s", provider)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, connectionTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	switch provider {
+	case "anthropic":
+		req.Header.Set("x-api-key", key)
+		req.Header.Set("anthropic-version", "2023-06-01")
+	case "ollama":
+	default:
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("API key rejected (HTTP %d)", resp.StatusCode)
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("unexpected response: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// promptConnectionTest offers a test call and reports whether the user wants
+// to re-enter the key after a failure.
+func promptConnectionTest(reader *bufio.Reader, client *http.Client, provider, key string) bool {
+	fmt.Printf("Test %s connection? (y/n) [y]: ", provider)
+	if input := strings.ToLower(readLine(reader)); input == "n" || input == "no" {
+		return false
+	}
+
+	if err := testConnection(context.Background(), client, provider, key); err != nil {
+		fmt.Printf("✗ %s connection failed: %v\n", provider, err)
+		fmt.Print("Re-enter the key? (y/n) [y]: ")
+		input := strings.ToLower(readLine(reader))
+		return input != "n" && input != "no"
+	}
+
+	fmt.Printf("✓ %s connection OK\n", provider)
+	return false
+}
+
+func validateProviders(client *http.Client, cfg *ExistingConfig) error {
+	for _, name := range providerList {
+		if !isProviderEnabled(cfg.Providers, name) {
+			continue
+		}
+		if err := testConnection(context.Background(), client, name, cfg.APIKeys[providerEnvKeys[name]]); err != nil {
+			return fmt.Errorf("%s connection failed: %v (use --skip-validation to save anyway)", name, err)
+		}
+		fmt.Printf("✓ %s connection OK\n", name)
+	}
+	return nil
+}
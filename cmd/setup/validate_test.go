@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTestConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/tags":
+		case r.Header.Get("Authorization") == "Bearer good", r.Header.Get("x-api-key") == "good":
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	saved := providerCheckURLs
+	providerCheckURLs = map[string]string{"openai": server.URL + "/models", "anthropic": server.URL + "/models"}
+	defer func() { providerCheckURLs = saved }()
+
+	ctx := context.Background()
+	if err := testConnection(ctx, server.Client(), "openai", "good"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := testConnection(ctx, server.Client(), "anthropic", "good"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := testConnection(ctx, server.Client(), "openai", "typo"); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("expected rejected key, got %v", err)
+	}
+	if err := testConnection(ctx, server.Client(), "ollama", server.URL+"/v1"); err != nil {
+		t.Errorf("unexpected ollama error: %v", err)
+	}
+}
+
+func TestPromptProviders_RetriesAfterFailedTest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	saved := providerCheckURLs
+	providerCheckURLs = map[string]string{"openai": server.URL}
+	defer func() { providerCheckURLs = saved }()
+
+	input := strings.Join([]string{
+		"y", "typo", "", "", // enable openai, bad key, test, re-enter
+		"good", "", // new key, test passes
+		"", // model
+		"n", "n", "n", "n",
+	}, "\n") + "\n"
+	keys := map[string]string{}
+	providers := promptProviders(bufio.NewReader(strings.NewReader(input)), ProvidersConfig{}, keys, server.Client())
+
+	if !providers.OpenAI.Enabled || keys["OPENAI_API_KEY"] != "good" {
+		t.Errorf("expected corrected key, got enabled=%v key=%q", providers.OpenAI.Enabled, keys["OPENAI_API_KEY"])
+	}
+}
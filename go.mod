@@ -3,15 +3,35 @@ module github.com/jrswab/helpi
 go 1.24.4
 
 require (
-	github.com/anthropics/anthropic-sdk-go v1.23.0 // indirect
-	github.com/go-telegram/bot v1.18.0 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
-	github.com/openai/openai-go/v3 v3.22.0 // indirect
+	github.com/anthropics/anthropic-sdk-go v1.23.0
+	github.com/eclipse/paho.mqtt.golang v1.5.0
+	github.com/go-telegram/bot v1.18.0
+	github.com/jackc/pgx/v5 v5.7.5
+	github.com/joho/godotenv v1.5.1
+	github.com/openai/openai-go/v3 v3.22.0
+	github.com/redis/go-redis/v9 v9.7.3
+	github.com/zalando/go-keyring v0.2.6
+	golang.org/x/net v0.41.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	al.essio.dev/pkg/shellescape v1.5.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/danieljoos/wincred v1.2.2 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/godbus/dbus/v5 v5.1.0 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/tidwall/gjson v1.18.0 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
 	github.com/tidwall/sjson v1.2.5 // indirect
+	golang.org/x/crypto v0.40.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/sys v0.41.0 // indirect
 	golang.org/x/term v0.40.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/text v0.27.0 // indirect
 )
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const maxBodyBytes = 1 << 20
+
+// Notification is the JSON body of POST /api/notify. Text is sent to
+// ChatID as is, or, when Prompt is set, the answer to Prompt followed by
+// Text is sent instead. Provider picks the provider that answers and Bot
+// the bot that sends it, the first configured one when empty. Urgent
+// notifications are sent during the chat's quiet hours instead of being
+// held.
+type Notification struct {
+	ChatID   int64  `json:"chat_id"`
+	Text     string `json:"text"`
+	Prompt   string `json:"prompt,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Bot      string `json:"bot,omitempty"`
+	Urgent   bool   `json:"urgent,omitempty"`
+}
+
+// Notifier sends a notification to its chat and reports whether it was
+// held for the chat's quiet hours instead.
+type Notifier func(ctx context.Context, n Notification) (held bool, err error)
+
+type result struct {
+	OK    bool   `json:"ok"`
+	Held  bool   `json:"held,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type Server struct {
+	token  string
+	notify Notifier
+}
+
+// NewServer returns a server that accepts requests carrying token as a
+// bearer token.
+func NewServer(token string, notify Notifier) *Server {
+	return &Server{
+		token:  token,
+		notify: notify,
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/notify", s.handleNotify)
+	return mux
+}
+
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("API server listening on %s", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("api server failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) handleNotify(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeResult(w, http.StatusUnauthorized, "invalid or missing bearer token")
+		return
+	}
+
+	var n Notification
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&n); err != nil {
+		writeResult(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if n.ChatID == 0 {
+		writeResult(w, http.StatusBadRequest, "chat_id is required")
+		return
+	}
+	if strings.TrimSpace(n.Text) == "" {
+		writeResult(w, http.StatusBadRequest, "text is required")
+		return
+	}
+
+	held, err := s.notify(r.Context(), n)
+	if err != nil {
+		log.Printf("API: failed to notify chat %d: %v", n.ChatID, err)
+		writeResult(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	if held {
+		writeJSON(w, http.StatusAccepted, result{OK: true, Held: true})
+		return
+	}
+	writeResult(w, http.StatusOK, "")
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func writeResult(w http.ResponseWriter, status int, errMsg string) {
+	writeJSON(w, status, result{OK: errMsg == "", Error: errMsg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, res result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
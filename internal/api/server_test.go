@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func post(s *Server, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/notify", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNotify_SendsNotification(t *testing.T) {
+	var got Notification
+	s := NewServer("secret", func(ctx context.Context, n Notification) (bool, error) {
+		got = n
+		return false, nil
+	})
+
+	rec := post(s, "secret", `{"chat_id": 42, "text": "disk full", "prompt": "Summarize this alert"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if got.ChatID != 42 || got.Text != "disk full" || got.Prompt != "Summarize this alert" {
+		t.Errorf("unexpected notification %+v", got)
+	}
+	var res result
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil || !res.OK {
+		t.Errorf("expected ok result, got %+v (%v)", res, err)
+	}
+}
+
+func TestNotify_ReportsHeld(t *testing.T) {
+	s := NewServer("secret", func(ctx context.Context, n Notification) (bool, error) {
+		return true, nil
+	})
+
+	rec := post(s, "secret", `{"chat_id": 42, "text": "disk full"}`)
+
+	var res result
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil || rec.Code != http.StatusAccepted || !res.OK || !res.Held {
+		t.Errorf("expected 202 with held, got %d %+v (%v)", rec.Code, res, err)
+	}
+}
+
+func TestNotify_RequiresToken(t *testing.T) {
+	called := false
+	s := NewServer("secret", func(ctx context.Context, n Notification) (bool, error) {
+		called = true
+		return false, nil
+	})
+
+	for _, token := range []string{"", "wrong"} {
+		if rec := post(s, token, `{"chat_id": 42, "text": "hi"}`); rec.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: expected 401, got %d", token, rec.Code)
+		}
+	}
+	if called {
+		t.Error("expected no notification without a valid token")
+	}
+}
+
+func TestNotify_RejectsInvalidBodies(t *testing.T) {
+	s := NewServer("secret", func(ctx context.Context, n Notification) (bool, error) {
+		return false, nil
+	})
+
+	for _, body := range []string{`not json`, `{"text": "hi"}`, `{"chat_id": 42, "text": " "}`} {
+		if rec := post(s, "secret", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestNotify_ReportsFailures(t *testing.T) {
+	s := NewServer("secret", func(ctx context.Context, n Notification) (bool, error) {
+		return false, errors.New("chat not found")
+	})
+
+	rec := post(s, "secret", `{"chat_id": 42, "text": "hi"}`)
+
+	if rec.Code != http.StatusBadGateway || !strings.Contains(rec.Body.String(), "chat not found") {
+		t.Errorf("expected 502 with the error, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestNotify_OnlyAcceptsPost(t *testing.T) {
+	s := NewServer("secret", nil)
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/notify", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,307 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	saltSize  = 16
+	keyRounds = 600000
+)
+
+// maxRestore caps the size of an archive and of the files in it.
+var maxRestore int64 = 1 << 30
+
+// magic starts encrypted archives, so restore can tell them from plain
+// gzip files.
+var magic = []byte("helpi-backup-aes1")
+
+var (
+	ErrTooLarge           = errors.New("backup is too large to restore")
+	ErrPassphraseRequired = errors.New("archive is encrypted; a passphrase is required")
+	ErrDecrypt            = errors.New("wrong passphrase or damaged archive")
+)
+
+// Source is a file or directory of bot data. Name identifies it in the
+// archive, so data can be restored to a different path than it was backed
+// up from.
+type Source struct {
+	Name string
+	Path string
+}
+
+// Write archives every source that exists to w and returns how many files
+// were written. With a passphrase the archive is encrypted with AES-GCM.
+func Write(w io.Writer, sources []Source, passphrase string) (int, error) {
+	if passphrase == "" {
+		return writeArchive(w, sources)
+	}
+
+	var buf bytes.Buffer
+	n, err := writeArchive(&buf, sources)
+	if err != nil {
+		return 0, err
+	}
+	sealed, err := encrypt(buf.Bytes(), passphrase)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(sealed); err != nil {
+		return 0, fmt.Errorf("failed to write backup: %w", err)
+	}
+	return n, nil
+}
+
+func writeArchive(w io.Writer, sources []Source) (int, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	count := 0
+	for _, src := range sources {
+		info, err := os.Stat(src.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", src.Path, err)
+		}
+		if !info.IsDir() {
+			if err := addFile(tw, src.Name, src.Path, info.Mode()); err != nil {
+				return 0, err
+			}
+			count++
+			continue
+		}
+
+		err = filepath.WalkDir(src.Path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(src.Path, p)
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			count++
+			return addFile(tw, src.Name+"/"+filepath.ToSlash(rel), p, info.Mode())
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to back up %s: %w", src.Path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write backup: %w", err)
+	}
+	return count, nil
+}
+
+func addFile(tw *tar.Writer, name, p string, mode fs.FileMode) error {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: int64(mode.Perm()), Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// Restore writes the files in the archive back to the paths of the matching
+// sources with the permissions they were backed up with, replacing files
+// that already exist. Files of sources that are not configured are skipped.
+// Every file is staged next to its target first and only moved into place
+// once the whole archive was read, so a damaged archive leaves the data as
+// it was. It returns how many files were restored.
+func Restore(r io.Reader, sources []Source, passphrase string) (int, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxRestore+1))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read backup: %w", err)
+	}
+	if int64(len(data)) > maxRestore {
+		return 0, ErrTooLarge
+	}
+	if bytes.HasPrefix(data, magic) {
+		if passphrase == "" {
+			return 0, ErrPassphraseRequired
+		}
+		if data, err = decrypt(data, passphrase); err != nil {
+			return 0, err
+		}
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("not a helpi backup: %w", err)
+	}
+
+	var staged []stagedFile
+	defer func() {
+		for _, f := range staged {
+			os.Remove(f.temp)
+		}
+	}()
+
+	tr := tar.NewReader(gz)
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read backup: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		target, err := targetPath(sources, hdr.Name)
+		if err != nil {
+			return 0, err
+		}
+		if target == "" {
+			continue
+		}
+		if total += hdr.Size; total > maxRestore {
+			return 0, ErrTooLarge
+		}
+		temp, err := stage(target, tr, fs.FileMode(hdr.Mode).Perm())
+		if err != nil {
+			return 0, err
+		}
+		staged = append(staged, stagedFile{temp: temp, target: target})
+	}
+
+	for i, f := range staged {
+		if err := os.Rename(f.temp, f.target); err != nil {
+			return i, fmt.Errorf("failed to restore %s: %w", f.target, err)
+		}
+	}
+	count := len(staged)
+	staged = nil
+	return count, nil
+}
+
+type stagedFile struct {
+	temp   string
+	target string
+}
+
+// stage writes r to a temporary file in target's directory, so it can be
+// renamed over target.
+func stage(target string, r io.Reader, mode fs.FileMode) (string, error) {
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(target)+".restore-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to restore %s: %w", target, err)
+	}
+	_, err = io.Copy(f, r)
+	if err == nil {
+		err = f.Chmod(mode)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to restore %s: %w", target, err)
+	}
+	return f.Name(), nil
+}
+
+// targetPath maps an archive entry to where it is restored, or "" when no
+// source matches it.
+func targetPath(sources []Source, name string) (string, error) {
+	source, rel, _ := strings.Cut(name, "/")
+	for _, src := range sources {
+		if src.Name != source {
+			continue
+		}
+		if rel == "" {
+			return src.Path, nil
+		}
+		rel = path.Clean(rel)
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return "", fmt.Errorf("backup entry %q is outside its directory", name)
+		}
+		return filepath.Join(src.Path, filepath.FromSlash(rel)), nil
+	}
+	return "", nil
+}
+
+func encrypt(plain []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte(nil), magic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plain, magic), nil
+}
+
+func decrypt(data []byte, passphrase string) ([]byte, error) {
+	data = data[len(magic):]
+	if len(data) < saltSize {
+		return nil, ErrDecrypt
+	}
+	gcm, err := newGCM(passphrase, data[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], magic)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, keyRounds, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
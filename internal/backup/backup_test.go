@@ -0,0 +1,161 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestWriteRestore_RoundTrip(t *testing.T) {
+	for _, passphrase := range []string{"", "correct horse"} {
+		src := t.TempDir()
+		writeFile(t, filepath.Join(src, "sessions", "1.json"), `[{"Role":"user"}]`)
+		writeFile(t, filepath.Join(src, "sessions", "archive", "2.json"), `[]`)
+		writeFile(t, filepath.Join(src, "feeds.json"), `[{"url":"x"}]`)
+		if err := os.Chmod(filepath.Join(src, "feeds.json"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		sources := []Source{
+			{Name: "sessions", Path: filepath.Join(src, "sessions")},
+			{Name: "feeds.json", Path: filepath.Join(src, "feeds.json")},
+			{Name: "budget.json", Path: filepath.Join(src, "missing.json")},
+		}
+
+		var buf bytes.Buffer
+		n, err := Write(&buf, sources, passphrase)
+		if err != nil || n != 3 {
+			t.Fatalf("Write() = %d, %v; expected 3 files", n, err)
+		}
+		if encrypted := bytes.HasPrefix(buf.Bytes(), magic); encrypted != (passphrase != "") {
+			t.Errorf("passphrase %q: expected encrypted=%v", passphrase, passphrase != "")
+		}
+
+		// Restoring to different paths follows the sources, not the
+		// paths the backup was made from.
+		dst := t.TempDir()
+		restored := []Source{
+			{Name: "sessions", Path: filepath.Join(dst, "s")},
+			{Name: "feeds.json", Path: filepath.Join(dst, "data", "f.json")},
+		}
+		n, err = Restore(&buf, restored, passphrase)
+		if err != nil || n != 3 {
+			t.Fatalf("Restore() = %d, %v; expected 3 files", n, err)
+		}
+		if got := readFile(t, filepath.Join(dst, "s", "archive", "2.json")); got != `[]` {
+			t.Errorf("unexpected archived session %q", got)
+		}
+		if got := readFile(t, filepath.Join(dst, "data", "f.json")); got != `[{"url":"x"}]` {
+			t.Errorf("unexpected feeds %q", got)
+		}
+		if info, err := os.Stat(filepath.Join(dst, "data", "f.json")); err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("expected the original mode 0600, got %v (%v)", info.Mode(), err)
+		}
+	}
+}
+
+func TestRestore_Passphrase(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Write(&buf, nil, "secret"); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	data := buf.Bytes()
+
+	if _, err := Restore(bytes.NewReader(data), nil, ""); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("expected ErrPassphraseRequired, got %v", err)
+	}
+	if _, err := Restore(bytes.NewReader(data), nil, "wrong"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("expected ErrDecrypt, got %v", err)
+	}
+}
+
+func TestRestore_RejectsEntriesOutsideSource(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "sessions/../../evil", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+	gz.Close()
+
+	dir := t.TempDir()
+	_, err := Restore(&buf, []Source{{Name: "sessions", Path: filepath.Join(dir, "sessions")}}, "")
+	if err == nil {
+		t.Fatal("expected an error for an entry outside its directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "evil")); err == nil {
+		t.Error("entry was written outside the source directory")
+	}
+}
+
+func TestRestore_DamagedArchiveKeepsData(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "feeds.json", Mode: 0644, Size: 3, Typeflag: tar.TypeReg})
+	tw.Write([]byte("new"))
+	tw.WriteHeader(&tar.Header{Name: "sessions/1.json", Mode: 0644, Size: 100, Typeflag: tar.TypeReg})
+	tw.Write([]byte("cut off"))
+	gz.Close()
+
+	dir := t.TempDir()
+	feeds := filepath.Join(dir, "feeds.json")
+	writeFile(t, feeds, "old")
+	sources := []Source{{Name: "feeds.json", Path: feeds}, {Name: "sessions", Path: filepath.Join(dir, "sessions")}}
+
+	if _, err := Restore(&buf, sources, ""); err == nil {
+		t.Fatal("expected an error for a damaged archive")
+	}
+	if got := readFile(t, feeds); got != "old" {
+		t.Errorf("expected the live file to be kept, got %q", got)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected no staged files to be left behind, got %v", entries)
+	}
+}
+
+func TestRestore_RejectsLargeArchives(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "feeds.json"), strings.Repeat("x", 4096))
+	sources := []Source{{Name: "feeds.json", Path: filepath.Join(src, "feeds.json")}}
+	var buf bytes.Buffer
+	if _, err := Write(&buf, sources, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(limit int64) { maxRestore = limit }(maxRestore)
+	maxRestore = int64(buf.Len()) - 1
+	if _, err := Restore(bytes.NewReader(buf.Bytes()), sources, ""); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge for the archive, got %v", err)
+	}
+
+	// Files that unpack to more than the limit are rejected as well.
+	maxRestore = 1024
+	if _, err := Restore(bytes.NewReader(buf.Bytes()), sources, ""); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge for the unpacked files, got %v", err)
+	}
+}
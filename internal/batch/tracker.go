@@ -0,0 +1,116 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type Job struct {
+	Name        string    `json:"name"`
+	Provider    string    `json:"provider"`
+	BatchID     string    `json:"batch_id"`
+	ChatID      int64     `json:"chat_id"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	// Redactions maps the placeholders in the submitted prompt to their
+	// original values, which are put back into the result.
+	Redactions map[string]string `json:"redactions,omitempty"`
+}
+
+type Tracker interface {
+	Add(job Job) error
+	Pending() ([]Job, error)
+	Remove(batchID string) error
+}
+
+type fileTracker struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewTracker(path string) (Tracker, error) {
+	if path == "" {
+		path = "./data/batches.json"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create batch directory: %w", err)
+	}
+
+	return &fileTracker{path: path}, nil
+}
+
+func (t *fileTracker) Add(job Job) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	jobs, err := t.read()
+	if err != nil {
+		return err
+	}
+
+	if job.SubmittedAt.IsZero() {
+		job.SubmittedAt = time.Now()
+	}
+
+	return t.write(append(jobs, job))
+}
+
+func (t *fileTracker) Pending() ([]Job, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.read()
+}
+
+func (t *fileTracker) Remove(batchID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	jobs, err := t.read()
+	if err != nil {
+		return err
+	}
+
+	kept := jobs[:0]
+	for _, job := range jobs {
+		if job.BatchID != batchID {
+			kept = append(kept, job)
+		}
+	}
+
+	return t.write(kept)
+}
+
+func (t *fileTracker) read() ([]Job, error) {
+	data, err := os.ReadFile(t.path)
+	if os.IsNotExist(err) {
+		return []Job{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch jobs: %w", err)
+	}
+
+	var jobs []Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse batch jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (t *fileTracker) write(jobs []Job) error {
+	data, err := json.Marshal(jobs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch jobs: %w", err)
+	}
+
+	if err := os.WriteFile(t.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write batch jobs: %w", err)
+	}
+
+	return nil
+}
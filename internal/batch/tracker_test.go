@@ -0,0 +1,52 @@
+package batch
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTracker_AddPendingRemove(t *testing.T) {
+	tr, err := NewTracker(filepath.Join(t.TempDir(), "batches.json"))
+	if err != nil {
+		t.Fatalf("NewTracker() returned error: %v", err)
+	}
+
+	if err := tr.Add(Job{Name: "digest", Provider: "openai", BatchID: "batch_1", ChatID: 10}); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if err := tr.Add(Job{Name: "news", Provider: "anthropic", BatchID: "batch_2", ChatID: 20}); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+
+	jobs, err := tr.Pending()
+	if err != nil {
+		t.Fatalf("Pending() returned error: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 pending jobs, got %d", len(jobs))
+	}
+	if jobs[0].SubmittedAt.IsZero() {
+		t.Error("expected SubmittedAt to be set")
+	}
+
+	if err := tr.Remove("batch_1"); err != nil {
+		t.Fatalf("Remove() returned error: %v", err)
+	}
+
+	jobs, _ = tr.Pending()
+	if len(jobs) != 1 || jobs[0].BatchID != "batch_2" {
+		t.Errorf("expected only batch_2 to remain, got %+v", jobs)
+	}
+}
+
+func TestTracker_EmptyWhenMissing(t *testing.T) {
+	tr, _ := NewTracker(filepath.Join(t.TempDir(), "missing", "batches.json"))
+
+	jobs, err := tr.Pending()
+	if err != nil {
+		t.Fatalf("Pending() returned error: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("expected no jobs, got %d", len(jobs))
+	}
+}
@@ -0,0 +1,345 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/i18n"
+)
+
+const (
+	accessCallbackPrefix  = "access:"
+	approveCallbackPrefix = accessCallbackPrefix + "approve:"
+	denyCallbackPrefix    = accessCallbackPrefix + "deny:"
+	accessRenotifyAfter   = 24 * time.Hour
+	accessSnippetLength   = 100
+)
+
+// ErrOpenAccess is returned by AllowUser while no one is configured and
+// everyone may use the bot. Approving one user would lock everyone else
+// out, so access has to be restricted in the config instead.
+var ErrOpenAccess = errors.New("access is open to everyone; configure allowed users or admins to restrict it")
+
+type accessAttempt struct {
+	username     string
+	name         string
+	firstMessage string
+	count        int
+	lastNotified time.Time
+	denied       bool
+}
+
+type AccessReporter struct {
+	approvedPath string
+	chatID       int64
+	mu           sync.Mutex
+	attempts     map[int64]*accessAttempt
+	now          func() time.Time
+}
+
+func NewAccessReporter(approvedPath string) *AccessReporter {
+	return &AccessReporter{
+		approvedPath: approvedPath,
+		attempts:     make(map[int64]*accessAttempt),
+		now:          time.Now,
+	}
+}
+
+// SetChat sends access requests to chatID, such as an admin group, instead
+// of to each admin privately.
+func (r *AccessReporter) SetChat(chatID int64) {
+	r.chatID = chatID
+}
+
+func WithAdmins(admins []int64) Option {
+	return func(h *Handlers) {
+		h.admins = admins
+	}
+}
+
+func WithAccessReporter(r *AccessReporter) Option {
+	return func(h *Handlers) {
+		h.accessReporter = r
+	}
+}
+
+func (h *Handlers) UpdateAccess(a Access) {
+	h.authMu.Lock()
+	defer h.authMu.Unlock()
+	h.owners = a.Owners
+	h.admins = a.Admins
+	h.allowedUsers = a.Users
+	h.guests = a.Guests
+	h.allowedNames = a.Usernames
+}
+
+// adminIDs returns the owners and admins, who are told about access
+// requests, feedback and errors.
+func (h *Handlers) adminIDs() []int64 {
+	h.authMu.RLock()
+	defer h.authMu.RUnlock()
+	return mergeIDs(slices.Clone(h.owners), h.admins)
+}
+
+func (h *Handlers) isAdmin(userID int64) bool {
+	return h.Role(userID) >= RoleAdmin
+}
+
+func (r *AccessReporter) record(user *models.User, text string) (*accessAttempt, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[user.ID]
+	if !ok {
+		attempt = &accessAttempt{
+			username:     user.Username,
+			name:         strings.TrimSpace(user.FirstName + " " + user.LastName),
+			firstMessage: truncate(text, accessSnippetLength),
+		}
+		r.attempts[user.ID] = attempt
+	}
+	attempt.count++
+	if attempt.denied {
+		return attempt, false
+	}
+
+	now := r.now()
+	if now.Sub(attempt.lastNotified) < accessRenotifyAfter {
+		return attempt, false
+	}
+	attempt.lastNotified = now
+
+	snapshot := *attempt
+	return &snapshot, true
+}
+
+func (r *AccessReporter) forget(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, userID)
+}
+
+// deny stops notifications about userID until the bot restarts.
+func (r *AccessReporter) deny(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[userID]
+	if !ok {
+		attempt = &accessAttempt{}
+		r.attempts[userID] = attempt
+	}
+	attempt.denied = true
+}
+
+func (h *Handlers) reportUnauthorized(ctx context.Context, sender BotSender, update *models.Update) {
+	admins := h.adminIDs()
+	if update.Message == nil || update.Message.From == nil || len(admins) == 0 {
+		return
+	}
+
+	user := update.Message.From
+	attempt, notify := h.accessReporter.record(user, update.Message.Text)
+	if !notify {
+		return
+	}
+
+	if chatID := h.accessReporter.chatID; chatID != 0 {
+		admins = []int64{chatID}
+	}
+	for _, admin := range admins {
+		lang := h.Language(&models.User{ID: admin})
+		if _, err := sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: admin,
+			Text:   formatAccessAttempt(lang, user.ID, attempt),
+			ReplyMarkup: &models.InlineKeyboardMarkup{
+				InlineKeyboard: [][]models.InlineKeyboardButton{{
+					{Text: i18n.T(lang, "access.approve"), CallbackData: fmt.Sprintf("%s%d", approveCallbackPrefix, user.ID)},
+					{Text: i18n.T(lang, "access.deny"), CallbackData: fmt.Sprintf("%s%d", denyCallbackPrefix, user.ID)},
+				}},
+			},
+		}); err != nil {
+			log.Printf("Failed to notify admin %d about user %d: %v", admin, user.ID, err)
+		}
+	}
+}
+
+func formatAccessAttempt(lang string, userID int64, a *accessAttempt) string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, "access.attempt") + "\n\n")
+	sb.WriteString(i18n.T(lang, "access.attempt_user_id", userID) + "\n")
+	if a.username != "" {
+		sb.WriteString(i18n.T(lang, "access.attempt_username", a.username) + "\n")
+	}
+	if a.name != "" {
+		sb.WriteString(i18n.T(lang, "access.attempt_name", a.name) + "\n")
+	}
+	if a.firstMessage != "" {
+		sb.WriteString(i18n.T(lang, "access.attempt_first_message", a.firstMessage) + "\n")
+	}
+	sb.WriteString(i18n.T(lang, "access.attempt_count", a.count))
+	return sb.String()
+}
+
+func (h *Handlers) AccessCallbackHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || update.CallbackQuery == nil {
+		return
+	}
+
+	query := update.CallbackQuery
+	answer := func(text string) {
+		if answerer, ok := sender.(CallbackAnswerer); ok {
+			answerer.AnswerCallbackQuery(ctx, &tgbot.AnswerCallbackQueryParams{
+				CallbackQueryID: query.ID,
+				Text:            text,
+			})
+		}
+	}
+
+	if !h.isAdmin(query.From.ID) {
+		log.Printf("[%s] Non-admin user %d attempted to approve access", timestamp(), query.From.ID)
+		answer(h.tr(&query.From, "access.admins_only"))
+		return
+	}
+
+	action, id, _ := strings.Cut(strings.TrimPrefix(query.Data, accessCallbackPrefix), ":")
+	if action != "approve" && action != "deny" {
+		answer(h.tr(&query.From, "callback.unknown"))
+		return
+	}
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || userID <= 0 {
+		answer(h.tr(&query.From, "access.invalid_user"))
+		return
+	}
+
+	outcome, notice := "access.outcome_approved", "access.approved"
+	if action == "deny" {
+		outcome, notice = "access.outcome_denied", "access.denied"
+		if h.accessReporter != nil {
+			h.accessReporter.deny(userID)
+		}
+		answer(h.tr(&query.From, "access.user_denied"))
+		log.Printf("[%s] Admin %d denied user %d", timestamp(), query.From.ID, userID)
+	} else {
+		if err := h.AllowUser(userID); errors.Is(err, ErrOpenAccess) {
+			answer(h.tr(&query.From, "access.open"))
+			return
+		} else if err != nil {
+			log.Printf("Failed to persist approval for user %d: %v", userID, err)
+			answer(h.tr(&query.From, "access.save_error"))
+		} else {
+			answer(h.tr(&query.From, "access.user_approved"))
+		}
+		log.Printf("[%s] Admin %d approved user %d", timestamp(), query.From.ID, userID)
+	}
+
+	if editor, ok := sender.(MessageEditor); ok && query.Message.Message != nil {
+		editor.EditMessageText(ctx, &tgbot.EditMessageTextParams{
+			ChatID:    query.Message.Message.Chat.ID,
+			MessageID: query.Message.Message.ID,
+			Text:      query.Message.Message.Text + "\n\n" + h.tr(&query.From, outcome, query.From.ID),
+		})
+	}
+
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: userID,
+		Text:   h.tr(&models.User{ID: userID}, notice),
+	})
+}
+
+// AllowUser adds userID to the allowed users and saves the approval. It
+// returns ErrOpenAccess instead of ending open access as a side effect.
+func (h *Handlers) AllowUser(userID int64) error {
+	h.authMu.Lock()
+	if h.openAccess() {
+		h.authMu.Unlock()
+		return ErrOpenAccess
+	}
+	for _, allowed := range h.allowedUsers {
+		if allowed == userID {
+			h.authMu.Unlock()
+			return nil
+		}
+	}
+	h.allowedUsers = append(h.allowedUsers, userID)
+	h.authMu.Unlock()
+
+	if h.accessReporter == nil {
+		return nil
+	}
+	h.accessReporter.forget(userID)
+
+	return h.accessReporter.saveApproved(userID)
+}
+
+func (r *AccessReporter) saveApproved(userID int64) error {
+	if r.approvedPath == "" {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	approved, err := LoadApprovedUsers(r.approvedPath)
+	if err != nil {
+		return err
+	}
+	for _, id := range approved {
+		if id == userID {
+			return nil
+		}
+	}
+	approved = append(approved, userID)
+
+	data, err := json.Marshal(approved)
+	if err != nil {
+		return fmt.Errorf("failed to marshal approved users: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.approvedPath), 0755); err != nil {
+		return fmt.Errorf("failed to create approved users directory: %w", err)
+	}
+	if err := os.WriteFile(r.approvedPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write approved users: %w", err)
+	}
+
+	return nil
+}
+
+func LoadApprovedUsers(path string) ([]int64, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []int64{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read approved users: %w", err)
+	}
+
+	var approved []int64
+	if err := json.Unmarshal(data, &approved); err != nil {
+		return nil, fmt.Errorf("failed to parse approved users: %w", err)
+	}
+
+	return approved, nil
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
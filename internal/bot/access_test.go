@@ -0,0 +1,201 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+)
+
+func makeCallbackUpdate(userID int64, data string) *models.Update {
+	return &models.Update{
+		CallbackQuery: &models.CallbackQuery{
+			ID:   "cb1",
+			From: models.User{ID: userID},
+			Data: data,
+			Message: models.MaybeInaccessibleMessage{
+				Message: &models.Message{ID: 7, Chat: models.Chat{ID: userID}, Text: "Unauthorized access attempt"},
+			},
+		},
+	}
+}
+
+func TestCheckAuth_ReportsUnauthorizedToAdmins(t *testing.T) {
+	reporter := NewAccessReporter(filepath.Join(t.TempDir(), "approved.json"))
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100, 200}), WithAccessReporter(reporter))
+
+	bot := &mockBot{}
+	update := makeUpdate(555, 555, "hello there")
+	update.Message.From.Username = "stranger"
+	if handlers.checkAuth(context.Background(), bot, update) {
+		t.Fatal("expected stranger to be denied")
+	}
+
+	if len(bot.sentMessages) != 2 {
+		t.Fatalf("expected a notification per admin, got %d messages", len(bot.sentMessages))
+	}
+	msg := bot.sentMessages[0]
+	if msg.ChatID != int64(100) {
+		t.Errorf("expected first notification to admin 100, got %v", msg.ChatID)
+	}
+	for _, want := range []string{"555", "@stranger", "hello there", "Attempts: 1"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("expected notification to contain %q, got %q", want, msg.Text)
+		}
+	}
+	markup, ok := msg.ReplyMarkup.(*models.InlineKeyboardMarkup)
+	if !ok || markup.InlineKeyboard[0][0].CallbackData != "access:approve:555" || markup.InlineKeyboard[0][1].CallbackData != "access:deny:555" {
+		t.Errorf("expected approve and deny buttons, got %+v", msg.ReplyMarkup)
+	}
+
+	handlers.checkAuth(context.Background(), bot, update)
+	if len(bot.sentMessages) != 2 {
+		t.Errorf("expected repeated attempts to be aggregated, got %d messages", len(bot.sentMessages))
+	}
+}
+
+func TestAccessReporter_RenotifiesAfterInterval(t *testing.T) {
+	r := NewAccessReporter("")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	user := &models.User{ID: 5}
+
+	if _, notify := r.record(user, "hi"); !notify {
+		t.Error("expected first attempt to notify")
+	}
+	if a, notify := r.record(user, "again"); notify || a.count != 2 {
+		t.Errorf("expected second attempt to be aggregated, notify=%v count=%d", notify, a.count)
+	}
+
+	now = now.Add(25 * time.Hour)
+	a, notify := r.record(user, "later")
+	if !notify {
+		t.Error("expected renotification after interval")
+	}
+	if a.count != 3 || a.firstMessage != "hi" {
+		t.Errorf("expected aggregated attempt, got %+v", a)
+	}
+}
+
+func TestAccessCallbackHandler_Approve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approved.json")
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100}), WithAccessReporter(NewAccessReporter(path)))
+
+	bot := &mockBot{}
+	handlers.AccessCallbackHandler(context.Background(), bot, makeCallbackUpdate(100, "access:approve:555"))
+
+	if !handlers.isAllowed(555) {
+		t.Error("expected user to be allowed after approval")
+	}
+	if bot.lastCallback == nil || bot.lastCallback.Text != "User approved." {
+		t.Errorf("expected callback answer, got %+v", bot.lastCallback)
+	}
+	if bot.lastEdit == nil || !strings.Contains(bot.lastEdit.Text, "Approved") {
+		t.Errorf("expected admin message to be edited, got %+v", bot.lastEdit)
+	}
+	if bot.lastMessageParams == nil || bot.lastMessageParams.ChatID != int64(555) {
+		t.Errorf("expected approved user to be notified, got %+v", bot.lastMessageParams)
+	}
+
+	approved, err := LoadApprovedUsers(path)
+	if err != nil {
+		t.Fatalf("LoadApprovedUsers() returned error: %v", err)
+	}
+	if len(approved) != 1 || approved[0] != 555 {
+		t.Errorf("expected approval to be persisted, got %v", approved)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() returned error: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected approved users to be readable by the owner only, got %v", info.Mode().Perm())
+	}
+}
+
+func TestAccessCallbackHandler_Deny(t *testing.T) {
+	reporter := NewAccessReporter("")
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100}), WithAccessReporter(reporter))
+
+	bot := &mockBot{}
+	handlers.AccessCallbackHandler(context.Background(), bot, makeCallbackUpdate(100, "access:deny:555"))
+
+	if handlers.isAllowed(555) {
+		t.Error("expected denied user to stay unauthorized")
+	}
+	if bot.lastEdit == nil || !strings.Contains(bot.lastEdit.Text, "Denied") {
+		t.Errorf("expected admin message to be edited, got %+v", bot.lastEdit)
+	}
+	if bot.lastMessageParams == nil || bot.lastMessageParams.ChatID != int64(555) || !strings.Contains(bot.lastMessageParams.Text, "declined") {
+		t.Errorf("expected denied user to be told, got %+v", bot.lastMessageParams)
+	}
+
+	bot = &mockBot{}
+	handlers.checkAuth(context.Background(), bot, makeUpdate(555, 555, "please"))
+	if len(bot.sentMessages) != 0 {
+		t.Errorf("expected no more requests from a denied user, got %d messages", len(bot.sentMessages))
+	}
+}
+
+func TestCheckAuth_ReportsToAdminChat(t *testing.T) {
+	reporter := NewAccessReporter("")
+	reporter.SetChat(-1001)
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100, 200}), WithAccessReporter(reporter))
+
+	bot := &mockBot{}
+	handlers.checkAuth(context.Background(), bot, makeUpdate(555, 555, "hello"))
+	if len(bot.sentMessages) != 1 || bot.sentMessages[0].ChatID != int64(-1001) {
+		t.Errorf("expected one request in the admin chat, got %+v", bot.sentMessages)
+	}
+}
+
+func TestAccessCallbackHandler_NonAdminRejected(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100}), WithAccessReporter(NewAccessReporter("")))
+
+	bot := &mockBot{}
+	handlers.AccessCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, "access:approve:555"))
+
+	if handlers.isAllowed(555) {
+		t.Error("expected non-admin approval to be rejected")
+	}
+	if bot.lastCallback == nil || !strings.Contains(bot.lastCallback.Text, "Only admins") {
+		t.Errorf("expected rejection answer, got %+v", bot.lastCallback)
+	}
+}
+
+func TestCheckAuth_AdminsAreAuthorized(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100}))
+
+	if !handlers.checkAuth(context.Background(), &mockBot{}, makeUpdate(100, 100, "/start")) {
+		t.Error("expected admin to be authorized")
+	}
+}
+
+func TestUpdateAccess_ReplacesAllowedUsers(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1})
+
+	handlers.UpdateAccess(Access{Users: []int64{2}})
+
+	if handlers.checkAuth(context.Background(), &mockBot{}, makeUpdate(1, 1, "/start")) {
+		t.Error("expected removed user to be denied")
+	}
+	if !handlers.checkAuth(context.Background(), &mockBot{}, makeUpdate(2, 2, "/start")) {
+		t.Error("expected added user to be authorized")
+	}
+}
+
+func TestAllowUser_KeepsOpenAccess(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, nil)
+
+	if err := handlers.AllowUser(555); !errors.Is(err, ErrOpenAccess) {
+		t.Fatalf("expected ErrOpenAccess, got %v", err)
+	}
+	if handlers.isAllowed(555) || !handlers.devMode() {
+		t.Error("expected approving a user not to end open access")
+	}
+}
@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// AdminHandler runs operator tools. /admin providers checks every enabled
+// provider and reports whether it answers; /admin storage shows how much
+// disk each user's data takes; /admin stats sums up the answer ratings.
+func (h *Handlers) AdminHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	switch strings.ToLower(commandArgs(update.Message.Text)) {
+	case "providers":
+		checker, ok := h.router.(llm.HealthChecker)
+		if !ok {
+			reply(h.tr(user, "admin.providers.unsupported"))
+			return
+		}
+		reply(h.tr(user, "admin.providers.checking"))
+		reply(h.providerReport(user, checker.CheckProviders(ctx)))
+	case "storage":
+		reply(h.storageReport(user))
+	case "stats":
+		reply(h.ratingReport(user))
+	default:
+		reply(h.tr(user, "admin.usage"))
+	}
+}
+
+func (h *Handlers) providerReport(user *models.User, results []llm.ProviderHealth) string {
+	if len(results) == 0 {
+		return h.tr(user, "admin.providers.none")
+	}
+
+	lines := []string{h.tr(user, "admin.providers.header")}
+	for _, r := range results {
+		model := r.Model
+		if model == "" {
+			model = "-"
+		}
+		if r.Up {
+			lines = append(lines, h.tr(user, "admin.providers.up", r.Name, model, r.Latency.Round(time.Millisecond)))
+		} else {
+			lines = append(lines, h.tr(user, "admin.providers.down", r.Name, model, r.Err))
+		}
+		if r.LastError != "" {
+			ago := time.Since(r.LastErrorAt).Round(time.Second)
+			lines = append(lines, h.tr(user, "admin.providers.last_error", ago, truncate(r.LastError, 300)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NotifyAdmins sends every admin the message key, translated into their
+// language.
+func (h *Handlers) NotifyAdmins(ctx context.Context, b any, key string, args ...any) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+	for _, admin := range h.adminIDs() {
+		if _, err := sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: admin,
+			Text:   h.tr(&models.User{ID: admin}, key, args...),
+		}); err != nil {
+			log.Printf("Failed to notify admin %d: %v", admin, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type checkingRouter struct {
+	mockRouter
+	results []llm.ProviderHealth
+}
+
+func (r *checkingRouter) CheckProviders(ctx context.Context) []llm.ProviderHealth {
+	return r.results
+}
+
+func TestAdminHandler_Providers(t *testing.T) {
+	router := &checkingRouter{results: []llm.ProviderHealth{
+		{Name: "openai", Model: "gpt-4o", Up: true, Latency: 420 * time.Millisecond},
+		{Name: "anthropic", Err: errors.New("invalid api key"), LastError: "invalid api key", LastErrorAt: time.Now()},
+	}}
+	handlers := NewHandlers(router, &mockSessionManager{}, nil, WithAdmins([]int64{1}))
+	b := &mockBot{}
+
+	handlers.AdminHandler(context.Background(), b, makeUpdate(1, 1, "/admin providers"))
+
+	text := b.lastMessageParams.Text
+	for _, want := range []string{"openai (gpt-4o): up, 420ms", "anthropic (-): down: invalid api key", "last error"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected report to contain %q, got %q", want, text)
+		}
+	}
+}
+
+func TestAdminHandler_NoProviders(t *testing.T) {
+	handlers := NewHandlers(&checkingRouter{}, &mockSessionManager{}, nil, WithAdmins([]int64{1}))
+	b := &mockBot{}
+
+	handlers.AdminHandler(context.Background(), b, makeUpdate(1, 1, "/admin providers"))
+
+	if !strings.Contains(b.lastMessageParams.Text, "No LLM provider is enabled") {
+		t.Errorf("expected the no provider hint, got %q", b.lastMessageParams.Text)
+	}
+}
+
+func TestAdminHandler_Usage(t *testing.T) {
+	handlers := NewHandlers(&checkingRouter{}, &mockSessionManager{}, nil, WithAdmins([]int64{1}))
+	b := &mockBot{}
+
+	handlers.AdminHandler(context.Background(), b, makeUpdate(1, 1, "/admin"))
+
+	if b.lastMessageParams.Text != "Usage: /admin providers|storage|stats" {
+		t.Errorf("expected usage, got %q", b.lastMessageParams.Text)
+	}
+}
+
+func TestNotifyAdmins(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100, 200}))
+
+	bot := &mockBot{}
+	handlers.NotifyAdmins(context.Background(), bot, "update.available", "v1.4.0", "v1.3.2", "https://example.com")
+	if len(bot.sentMessages) != 2 {
+		t.Fatalf("expected a message per admin, got %d", len(bot.sentMessages))
+	}
+	for i, admin := range []int64{100, 200} {
+		msg := bot.sentMessages[i]
+		if msg.ChatID != admin || !strings.Contains(msg.Text, "v1.4.0 (running v1.3.2)") {
+			t.Errorf("unexpected message %+v", msg)
+		}
+	}
+}
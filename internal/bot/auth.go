@@ -6,10 +6,12 @@ import (
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/i18n"
 )
 
 type AuthMiddleware struct {
 	allowedUsers []int64
+	authorize    func(ctx context.Context, sender BotSender, update *models.Update) bool
 }
 
 func NewAuthMiddleware(allowedUsers []int64) *AuthMiddleware {
@@ -21,14 +23,27 @@ func NewAuthMiddleware(allowedUsers []int64) *AuthMiddleware {
 	}
 }
 
+// AuthMiddleware checks every update against the handlers' access list, so
+// approvals and config reloads apply without restarting. Admins are always
+// allowed and rejected users are reported when access reporting is enabled.
+func (h *Handlers) AuthMiddleware() *AuthMiddleware {
+	return &AuthMiddleware{authorize: h.checkAuth}
+}
+
 func (m *AuthMiddleware) Middleware(next bot.HandlerFunc) bot.HandlerFunc {
 	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		if m.authorize != nil {
+			if m.authorize(ctx, resolveSender(b), update) {
+				next(ctx, b, update)
+			}
+			return
+		}
 		if !m.isAuthorized(update) {
 			chatID := m.getChatID(update)
 			if chatID != 0 {
 				b.SendMessage(ctx, &bot.SendMessageParams{
 					ChatID: chatID,
-					Text:   "Access denied. You are not authorized to use this bot.",
+					Text:   i18n.T(m.language(update), "auth.denied"),
 				})
 			}
 			return
@@ -86,3 +101,11 @@ func (m *AuthMiddleware) getChatID(update *models.Update) int64 {
 	}
 	return 0
 }
+
+// language is the language Telegram reports for the sender of update.
+func (m *AuthMiddleware) language(update *models.Update) string {
+	if user := updateUser(update); user != nil {
+		return i18n.Normalize(user.LanguageCode)
+	}
+	return i18n.DefaultLanguage
+}
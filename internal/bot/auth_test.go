@@ -349,3 +349,35 @@ func TestAuthMiddleware_Middleware(t *testing.T) {
 		}
 	})
 }
+
+func TestHandlers_AuthMiddleware(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100}))
+
+	for _, tc := range []struct {
+		userID int64
+		want   bool
+	}{
+		{userID: 1, want: true},
+		{userID: 100, want: true},
+		{userID: 2, want: false},
+	} {
+		nextCalled := false
+		wrapped := handlers.AuthMiddleware().Middleware(func(ctx context.Context, b *bot.Bot, update *models.Update) {
+			nextCalled = true
+		})
+		wrapped(context.Background(), nil, makeUpdate(tc.userID, tc.userID, "hi"))
+		if nextCalled != tc.want {
+			t.Errorf("user %d: expected next called = %v, got %v", tc.userID, tc.want, nextCalled)
+		}
+	}
+
+	handlers.UpdateAccess(Access{Users: []int64{2}})
+	nextCalled := false
+	wrapped := handlers.AuthMiddleware().Middleware(func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		nextCalled = true
+	})
+	wrapped(context.Background(), nil, makeUpdate(2, 2, "hi"))
+	if !nextCalled {
+		t.Error("expected access changes to apply to the middleware")
+	}
+}
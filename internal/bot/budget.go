@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"errors"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/budget"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/webhook"
+)
+
+// WithBudget caps daily token usage with tracker, which may be nil, and trims
+// history so no request exceeds maxInputTokens.
+func WithBudget(tracker *budget.Tracker, maxInputTokens int) Option {
+	return func(h *Handlers) {
+		h.budget = tracker
+		h.maxInputTokens = maxInputTokens
+	}
+}
+
+// budgetRefusal returns the message to send instead of answering when the
+// daily budget is spent, or "" when the request may go ahead.
+func (h *Handlers) budgetRefusal(user *models.User) string {
+	if h.budget == nil {
+		return ""
+	}
+	err := h.budget.Allow(user.ID)
+	switch {
+	case errors.Is(err, budget.ErrGlobalBudget):
+		return h.tr(user, "budget.global_exceeded")
+	case errors.Is(err, budget.ErrUserBudget):
+		return h.tr(user, "budget.user_exceeded")
+	}
+	return ""
+}
+
+// warnUsage warns the user once the requests charged to them, including
+// ones made in the background, pass 80% of a daily budget.
+func (h *Handlers) warnUsage(ctx context.Context, sender BotSender, chatID int64, user *models.User) {
+	if h.budget == nil {
+		return
+	}
+
+	var text, scope string
+	switch h.budget.TakeWarning(user.ID) {
+	case budget.UserWarning:
+		text, scope = h.tr(user, "budget.user_warning"), "user"
+	case budget.GlobalWarning:
+		text, scope = h.tr(user, "budget.global_warning"), "global"
+	default:
+		return
+	}
+	h.notify(webhook.EventUsage, user.ID, chatID, map[string]any{"scope": scope})
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: chatID,
+		Text:   text,
+	})
+}
+
+// charge counts a request sent straight to provider, which bypasses the
+// router's metering, against the global budget.
+func (h *Handlers) charge(provider llm.Provider, messages []llm.Message, response string) {
+	if h.budget == nil {
+		return
+	}
+	usage := llm.Usage{
+		Provider:         provider.Name(),
+		PromptTokens:     llm.CountTokensFor(provider.Name(), messages),
+		CompletionTokens: llm.EstimateTokens(response),
+	}
+	if mp, ok := provider.(llm.ModelProvider); ok {
+		usage.Model = mp.Model()
+	}
+	h.budget.Charge(0, usage)
+}
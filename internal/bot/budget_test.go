@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/budget"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// metered wraps router the way the bot's router is wrapped in production,
+// so every request is charged to tracker.
+func metered(router llm.Router, tracker *budget.Tracker) llm.Router {
+	r := llm.NewReloadableRouter(router)
+	r.SetMeter(tracker)
+	return r
+}
+
+func TestTextMessageHandler_RefusesWhenBudgetSpent(t *testing.T) {
+	tracker, _ := budget.NewTracker(filepath.Join(t.TempDir(), "budget.json"), budget.Limits{UserDaily: 10})
+	tracker.Record(1, 10)
+	router := &mockRouter{response: "answer"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithBudget(tracker, 0))
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "hello"))
+
+	if router.lastMessages != nil {
+		t.Error("expected no request once the budget is spent")
+	}
+	if !strings.Contains(bot.lastMessageParams.Text, "token budget") {
+		t.Errorf("expected budget refusal, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestTextMessageHandler_WarnsNearBudget(t *testing.T) {
+	tracker, _ := budget.NewTracker(filepath.Join(t.TempDir(), "budget.json"), budget.Limits{UserDaily: 20})
+	tracker.Record(1, 15)
+	handlers := NewHandlers(metered(&mockRouter{response: "answer"}, tracker), &mockSessionManager{}, []int64{1}, WithBudget(tracker, 0))
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "hello"))
+
+	if !strings.Contains(bot.lastMessageParams.Text, "80%") {
+		t.Errorf("expected budget warning, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestRouter_ChargesRequestsOutsideChats(t *testing.T) {
+	tracker, _ := budget.NewTracker(filepath.Join(t.TempDir(), "budget.json"), budget.Limits{UserDaily: 10})
+	router := metered(&mockRouter{response: "a summary of the conversation so far"}, tracker)
+
+	if _, err := router.SendMessage(context.Background(), []llm.Message{{Role: "user", Content: "summarize this"}}, llm.WithUser(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := router.SendMessage(context.Background(), []llm.Message{{Role: "user", Content: "again"}}, llm.WithUser(1)); !errors.Is(err, budget.ErrUserBudget) {
+		t.Errorf("expected the spent budget to refuse background requests, got %v", err)
+	}
+}
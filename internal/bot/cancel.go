@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// typingInterval refreshes the typing indicator before Telegram drops it
+// after five seconds.
+const typingInterval = 4 * time.Second
+
+// generations tracks the in-flight requests of each user so /cancel can
+// abort them.
+type generations struct {
+	mu      sync.Mutex
+	next    uint64
+	cancels map[int64]map[uint64]context.CancelFunc
+}
+
+func (g *generations) start(ctx context.Context, userID int64) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cancels == nil {
+		g.cancels = make(map[int64]map[uint64]context.CancelFunc)
+	}
+	if g.cancels[userID] == nil {
+		g.cancels[userID] = make(map[uint64]context.CancelFunc)
+	}
+	g.next++
+	id := g.next
+	g.cancels[userID][id] = cancel
+
+	return ctx, func() {
+		g.mu.Lock()
+		delete(g.cancels[userID], id)
+		if len(g.cancels[userID]) == 0 {
+			delete(g.cancels, userID)
+		}
+		g.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel aborts every in-flight request of userID and returns how many
+// there were.
+func (g *generations) cancel(userID int64) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := len(g.cancels[userID])
+	for _, cancel := range g.cancels[userID] {
+		cancel()
+	}
+	delete(g.cancels, userID)
+	return n
+}
+
+// generate starts a cancellable request for userID and shows the typing
+// indicator in chatID until the returned func is called or /cancel aborts
+// the request.
+func (h *Handlers) generate(ctx context.Context, sender BotSender, userID, chatID int64) (context.Context, func()) {
+	ctx, done := h.generations.start(ctx, userID)
+
+	typing := func() {
+		sender.SendChatAction(ctx, &tgbot.SendChatActionParams{
+			ChatID: chatID,
+			Action: models.ChatActionTyping,
+		})
+	}
+	typing()
+
+	go func() {
+		ticker := time.NewTicker(typingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				typing()
+			}
+		}
+	}()
+
+	return ctx, done
+}
+
+func (h *Handlers) CancelHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	key := "cancel.none"
+	if h.generations.cancel(update.Message.From.ID) > 0 {
+		key = "cancel.done"
+	}
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: update.Message.Chat.ID,
+		Text:   h.tr(update.Message.From, key),
+	})
+}
@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// blockingRouter answers only once the request context is done.
+type blockingRouter struct {
+	mockRouter
+	started chan struct{}
+}
+
+func (r *blockingRouter) SendMessage(ctx context.Context, messages []llm.Message, opts ...llm.RequestOption) (string, error) {
+	close(r.started)
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+type lockedBot struct {
+	mockBot
+	mu   sync.Mutex
+	sent []string
+}
+
+func (b *lockedBot) SendMessage(ctx context.Context, params *tgbot.SendMessageParams) (*models.Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, params.Text)
+	return &models.Message{}, nil
+}
+
+func (b *lockedBot) SendChatAction(ctx context.Context, params *tgbot.SendChatActionParams) (bool, error) {
+	return true, nil
+}
+
+func TestCancelHandler_AbortsGeneration(t *testing.T) {
+	router := &blockingRouter{started: make(chan struct{})}
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{})
+	bot := &lockedBot{}
+
+	finished := make(chan struct{})
+	go func() {
+		handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "write a long story"))
+		close(finished)
+	}()
+	<-router.started
+
+	handlers.CancelHandler(context.Background(), bot, makeUpdate(1, 1, "/cancel"))
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("expected generation to stop after /cancel")
+	}
+
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	if len(bot.sent) != 1 || bot.sent[0] != "Stopped generating the answer." {
+		t.Errorf("expected only the cancel confirmation, got %q", bot.sent)
+	}
+}
+
+func TestCancelHandler_NothingToCancel(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{})
+	bot := &mockBot{}
+
+	handlers.CancelHandler(context.Background(), bot, makeUpdate(1, 1, "/cancel"))
+
+	if bot.lastMessageParams == nil || bot.lastMessageParams.Text != "Nothing to cancel." {
+		t.Errorf("expected nothing-to-cancel notice, got %+v", bot.lastMessageParams)
+	}
+}
+
+func TestGenerations_CancelOnlyAffectsUser(t *testing.T) {
+	var g generations
+	ctx1, done1 := g.start(context.Background(), 1)
+	defer done1()
+	ctx2, done2 := g.start(context.Background(), 2)
+	defer done2()
+
+	if n := g.cancel(1); n != 1 {
+		t.Errorf("expected 1 cancelled request, got %d", n)
+	}
+	if ctx1.Err() == nil {
+		t.Error("expected user 1's request to be cancelled")
+	}
+	if ctx2.Err() != nil {
+		t.Error("expected user 2's request to keep running")
+	}
+
+	done2()
+	if n := g.cancel(2); n != 0 {
+		t.Errorf("expected finished requests to be untracked, got %d", n)
+	}
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	ChatContextDescription = "description"
+	ChatContextPinned      = "pinned"
+
+	// chatContextTTL is how long a chat's description or pinned message is
+	// reused before it is fetched from Telegram again.
+	chatContextTTL = 5 * time.Minute
+
+	chatContextInstruction = "Standing instructions from the admins of this chat:"
+)
+
+type ChatGetter interface {
+	GetChat(ctx context.Context, params *tgbot.GetChatParams) (*models.ChatFullInfo, error)
+}
+
+type chatContextEntry struct {
+	text    string
+	fetched time.Time
+}
+
+// chatContexts caches the standing context of each chat, so the chat does
+// not have to be fetched for every message.
+type chatContexts struct {
+	source  string
+	mu      sync.Mutex
+	entries map[int64]chatContextEntry
+}
+
+// WithChatContext adds a group's description or pinned message, depending
+// on source, to its requests. A pinned message is only used when an admin of
+// the group wrote it. An empty source disables it.
+func WithChatContext(source string) Option {
+	return func(h *Handlers) {
+		if source == "" {
+			h.chatContexts = nil
+			return
+		}
+		h.chatContexts = &chatContexts{
+			source:  source,
+			entries: make(map[int64]chatContextEntry),
+		}
+	}
+}
+
+// refreshChatContext fetches the context of chatID if the cached one is
+// missing or stale. Failures keep the previous context.
+func (h *Handlers) refreshChatContext(ctx context.Context, sender BotSender, chatID int64) {
+	if h.chatContexts == nil || !isGroupChat(chatID) {
+		return
+	}
+	getter, ok := sender.(ChatGetter)
+	if !ok {
+		return
+	}
+
+	c := h.chatContexts
+	c.mu.Lock()
+	entry, cached := c.entries[chatID]
+	c.mu.Unlock()
+	if cached && time.Since(entry.fetched) < chatContextTTL {
+		return
+	}
+
+	chat, err := getter.GetChat(ctx, &tgbot.GetChatParams{ChatID: chatID})
+	if err != nil {
+		log.Printf("Failed to load context for chat %d: %v", chatID, err)
+		return
+	}
+
+	text := chat.Description
+	if c.source == ChatContextPinned {
+		text = ""
+		if pinned := chat.PinnedMessage; pinned != nil && h.pinnedByAdmin(ctx, sender, chatID, pinned) {
+			text = pinned.Text
+			if text == "" {
+				text = pinned.Caption
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.entries[chatID] = chatContextEntry{text: strings.TrimSpace(text), fetched: time.Now()}
+	c.mu.Unlock()
+}
+
+// pinnedByAdmin reports whether an admin of chatID wrote msg, either under
+// their own name or anonymously on behalf of the group.
+func (h *Handlers) pinnedByAdmin(ctx context.Context, sender BotSender, chatID int64, msg *models.Message) bool {
+	if msg.SenderChat != nil {
+		return msg.SenderChat.ID == chatID
+	}
+	return msg.From != nil && h.isGroupAdmin(ctx, sender, chatID, msg.From.ID)
+}
+
+func (h *Handlers) chatContext(chatID int64) string {
+	if h.chatContexts == nil || !isGroupChat(chatID) {
+		return ""
+	}
+	h.chatContexts.mu.Lock()
+	defer h.chatContexts.mu.Unlock()
+	return h.chatContexts.entries[chatID].text
+}
+
+// withChatContext adds the chat's standing context to the system prompt,
+// or as a context message when there is none.
+func (h *Handlers) withChatContext(chatID int64, messages []llm.Message) []llm.Message {
+	text := h.chatContext(chatID)
+	if text == "" {
+		return messages
+	}
+	text = chatContextInstruction + "\n" + truncate(text, maxSystemPromptLength)
+
+	if len(messages) > 0 && messages[0].Role == "system" && !messages[0].Context && !llm.IsSummary(messages[0]) {
+		result := append([]llm.Message(nil), messages...)
+		result[0].Content += "\n\n" + text
+		return result
+	}
+	result := make([]llm.Message, 0, len(messages)+1)
+	result = append(result, llm.ContextMessage(text))
+	return append(result, messages...)
+}
@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const maxMessageLength = 4096
+
+func splitMessage(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	current := ""
+	for _, block := range messageBlocks(text) {
+		for _, piece := range splitBlock(block, limit) {
+			switch {
+			case current == "":
+				current = piece
+			case utf8.RuneCountInString(current)+2+utf8.RuneCountInString(piece) <= limit:
+				current += "\n\n" + piece
+			default:
+				chunks = append(chunks, current)
+				current = piece
+			}
+		}
+	}
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
+
+func messageBlocks(text string) []string {
+	var blocks []string
+	var lines []string
+	inCode := false
+
+	flush := func() {
+		if len(lines) > 0 {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+			lines = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		isFence := strings.HasPrefix(strings.TrimSpace(line), "```")
+		switch {
+		case isFence && !inCode:
+			flush()
+			lines = append(lines, line)
+			inCode = true
+		case isFence && inCode:
+			lines = append(lines, line)
+			flush()
+			inCode = false
+		case !inCode && strings.TrimSpace(line) == "":
+			flush()
+		default:
+			lines = append(lines, line)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+func splitBlock(block string, limit int) []string {
+	if utf8.RuneCountInString(block) <= limit {
+		return []string{block}
+	}
+
+	lines := strings.Split(block, "\n")
+	if !strings.HasPrefix(strings.TrimSpace(lines[0]), "```") {
+		return packLines(lines, limit)
+	}
+
+	header := strings.TrimSpace(lines[0])
+	body := lines[1:]
+	if len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "```" {
+		body = body[:len(body)-1]
+	}
+
+	budget := limit - utf8.RuneCountInString(header) - len("\n\n```")
+	if budget < 1 {
+		return packLines(lines, limit)
+	}
+
+	var pieces []string
+	for _, part := range packLines(body, budget) {
+		pieces = append(pieces, header+"\n"+part+"\n```")
+	}
+	return pieces
+}
+
+func packLines(lines []string, limit int) []string {
+	var pieces []string
+	current := ""
+	started := false
+
+	for _, line := range lines {
+		for _, part := range hardSplit(line, limit) {
+			switch {
+			case !started:
+				current = part
+				started = true
+			case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(part) <= limit:
+				current += "\n" + part
+			default:
+				pieces = append(pieces, current)
+				current = part
+			}
+		}
+	}
+	if started {
+		pieces = append(pieces, current)
+	}
+
+	return pieces
+}
+
+func hardSplit(line string, limit int) []string {
+	var parts []string
+	runes := []rune(line)
+
+	for len(runes) > limit {
+		cut := limit
+		if space := lastSpace(runes[:limit]); space > limit/2 {
+			cut = space + 1
+		}
+		parts = append(parts, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+
+	return append(parts, string(runes))
+}
+
+func lastSpace(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] == ' ' {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestSplitMessage_ShortTextUnchanged(t *testing.T) {
+	chunks := splitMessage("hello", 10)
+	if len(chunks) != 1 || chunks[0] != "hello" {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+}
+
+func TestSplitMessage_SplitsOnParagraphs(t *testing.T) {
+	text := "first paragraph\n\nsecond paragraph\n\nthird"
+	chunks := splitMessage(text, 35)
+
+	want := []string{"first paragraph\n\nsecond paragraph", "third"}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %q", len(want), chunks)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, chunks[i], want[i])
+		}
+	}
+}
+
+func TestSplitMessage_ReopensCodeBlocks(t *testing.T) {
+	code := "```go\n" + strings.Repeat("fmt.Println(1)\n", 6) + "```"
+	chunks := splitMessage("intro\n\n"+code, 50)
+
+	if len(chunks) < 2 {
+		t.Fatalf("expected code block to be split, got %d chunks", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if strings.Count(chunk, "```")%2 != 0 {
+			t.Errorf("chunk has unbalanced fences: %q", chunk)
+		}
+		if n := utf8.RuneCountInString(chunk); n > 50 {
+			t.Errorf("chunk exceeds limit: %d", n)
+		}
+	}
+	if !strings.HasPrefix(chunks[len(chunks)-1], "```go\n") {
+		t.Errorf("expected last chunk to reopen the code block, got %q", chunks[len(chunks)-1])
+	}
+}
+
+func TestSplitMessage_HardSplitsLongLines(t *testing.T) {
+	text := strings.Repeat("word ", 50)
+	chunks := splitMessage(text, 40)
+
+	if strings.Join(chunks, "") != text {
+		t.Error("expected chunks to reassemble to original text")
+	}
+	for _, chunk := range chunks {
+		if n := utf8.RuneCountInString(chunk); n > 40 {
+			t.Errorf("chunk exceeds limit: %d", n)
+		}
+	}
+}
+
+func TestSendReply_SendsMultipleMessages(t *testing.T) {
+	h := NewHandlers(nil, nil, nil)
+	bot := &mockBot{}
+	markup := &models.InlineKeyboardMarkup{}
+
+	text := strings.Repeat("a", maxMessageLength) + "\n\n" + "tail"
+	h.sendReply(context.Background(), bot, &tgbot.SendMessageParams{
+		ChatID:          1,
+		Text:            text,
+		ReplyMarkup:     markup,
+		ReplyParameters: &models.ReplyParameters{MessageID: 5},
+	})
+
+	if len(bot.sentMessages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(bot.sentMessages))
+	}
+	first, second := bot.sentMessages[0], bot.sentMessages[1]
+	if first.ReplyMarkup != nil || second.ReplyMarkup == nil {
+		t.Error("expected markup only on the last message")
+	}
+	if first.ReplyParameters == nil || second.ReplyParameters != nil {
+		t.Error("expected reply parameters only on the first message")
+	}
+	if second.Text != "tail" {
+		t.Errorf("unexpected second message %q", second.Text)
+	}
+}
@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/i18n"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func WithCommandRoutes(routes map[string]config.CommandRouteConfig) Option {
+	return func(h *Handlers) {
+		h.commandRoutes = routes
+	}
+}
+
+func (h *Handlers) RoutedCommandHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	name := commandName(update.Message.Text)
+	route, ok := h.commandRoutes[name]
+	if !ok {
+		return
+	}
+
+	text := commandArgs(update.Message.Text)
+	if text == "" {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   h.tr(update.Message.From, "command.route.usage", name),
+		})
+		return
+	}
+
+	var opts []llm.RequestOption
+	if route.Provider != "" {
+		opts = append(opts, llm.WithProvider(route.Provider))
+	}
+	if route.Model != "" {
+		opts = append(opts, llm.WithModel(route.Model))
+	}
+
+	h.chat(ctx, sender, update, text, opts...)
+}
+
+// routeDescription falls back to naming the provider and model a custom
+// command routes to, in lang.
+func routeDescription(lang, description, provider, model string) string {
+	switch {
+	case description != "" || provider == "":
+		return description
+	case model != "":
+		return i18n.T(lang, "command.route.ask_model", provider, model)
+	}
+	return i18n.T(lang, "command.route.ask", provider)
+}
+
+func commandName(text string) string {
+	text = strings.TrimPrefix(text, "/")
+	if i := strings.IndexAny(text, " \n@"); i >= 0 {
+		text = text[:i]
+	}
+	return text
+}
+
+func commandArgs(text string) string {
+	i := strings.IndexAny(text, " \n")
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimSpace(text[i+1:])
+}
@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func TestCommandNameAndArgs(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantName string
+		wantArgs string
+	}{
+		{"/summarize some long text", "summarize", "some long text"},
+		{"/code@helpibot fix this", "code", "fix this"},
+		{"/summarize", "summarize", ""},
+		{"/summarize\nmultiline\ntext", "summarize", "multiline\ntext"},
+	}
+
+	for _, tt := range tests {
+		if got := commandName(tt.text); got != tt.wantName {
+			t.Errorf("commandName(%q) = %q, want %q", tt.text, got, tt.wantName)
+		}
+		if got := commandArgs(tt.text); got != tt.wantArgs {
+			t.Errorf("commandArgs(%q) = %q, want %q", tt.text, got, tt.wantArgs)
+		}
+	}
+}
+
+func TestRoutedCommandHandler_UsesRoute(t *testing.T) {
+	router := &mockRouter{response: "summary"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{}, WithCommandRoutes(map[string]config.CommandRouteConfig{
+		"summarize": {Provider: "openai", Model: "gpt-4o-mini"},
+	}))
+
+	bot := &mockBot{}
+	handlers.RoutedCommandHandler(context.Background(), bot, makeUpdate(1, 1, "/summarize a long article"))
+
+	if bot.lastMessageParams == nil || bot.lastMessageParams.Text != "summary" {
+		t.Fatalf("expected routed reply, got %+v", bot.lastMessageParams)
+	}
+	if len(router.lastOpts) != 4 {
+		t.Errorf("expected user, provider, model and route report options, got %d", len(router.lastOpts))
+	}
+	if last := router.lastMessages[len(router.lastMessages)-1]; last.Content != "a long article" {
+		t.Errorf("expected command arguments as prompt, got %q", last.Content)
+	}
+}
+
+func TestRoutedCommandHandler_Usage(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{}, WithCommandRoutes(map[string]config.CommandRouteConfig{
+		"code": {Provider: "opencode"},
+	}))
+
+	bot := &mockBot{}
+	handlers.RoutedCommandHandler(context.Background(), bot, makeUpdate(1, 1, "/code"))
+
+	if bot.lastMessageParams == nil || bot.lastMessageParams.Text != "Usage: /code <text>" {
+		t.Errorf("expected usage message, got %+v", bot.lastMessageParams)
+	}
+}
+
+func TestHelpHandler_ListsCustomCommands(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{}, WithCommandRoutes(map[string]config.CommandRouteConfig{
+		"code": {Provider: "opencode", Description: "Ask the coding model"},
+	}))
+
+	bot := &mockBot{}
+	handlers.HelpHandler(context.Background(), bot, &models.Update{Message: &models.Message{
+		From: &models.User{ID: 1},
+		Chat: models.Chat{ID: 1},
+		Text: "/help",
+	}})
+
+	if !strings.Contains(bot.lastMessageParams.Text, "/code <text> - Ask the coding model") {
+		t.Errorf("expected custom command in help, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestHelpHandler_DescribesRoutesInTheUsersLanguage(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{languages: map[int64]string{1: "es"}}, []int64{}, WithCommandRoutes(map[string]config.CommandRouteConfig{
+		"code": {Provider: "opencode", Model: "coder"},
+	}))
+
+	bot := &mockBot{}
+	handlers.HelpHandler(context.Background(), bot, makeUpdate(1, 1, "/help"))
+	if !strings.Contains(bot.lastMessageParams.Text, "/code <texto> - Preguntar a opencode (coder)") {
+		t.Errorf("expected the route described in Spanish, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.RoutedCommandHandler(context.Background(), bot, makeUpdate(1, 1, "/code"))
+	if bot.lastMessageParams.Text != "Uso: /code <texto>" {
+		t.Errorf("expected usage in Spanish, got %q", bot.lastMessageParams.Text)
+	}
+}
@@ -0,0 +1,200 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	continueCallback = "continue:"
+
+	continuationPrompt = "Your previous answer was cut off. Continue exactly where it stopped, without repeating anything."
+)
+
+// continuation controls what happens to answers cut off by the provider's
+// token limit.
+type continuation struct {
+	max    int
+	button bool
+}
+
+// WithContinuation asks the model to continue cut-off answers up to max
+// times. With button, answers that are still cut off get a button that
+// asks for more.
+func WithContinuation(max int, button bool) Option {
+	return func(h *Handlers) {
+		if max <= 0 && !button {
+			h.continuation = nil
+			return
+		}
+		h.continuation = &continuation{max: max, button: button}
+	}
+}
+
+// sendContinued sends request and, while the provider reports the answer
+// was cut off, asks for the rest and stitches the parts together. It
+// reports whether the answer is still cut off after the last continuation.
+func (h *Handlers) sendContinued(ctx context.Context, request []llm.Message, opts []llm.RequestOption) (string, bool, error) {
+	if h.continuation == nil {
+		response, err := h.router.SendMessage(ctx, request, opts...)
+		return response, false, err
+	}
+
+	truncated := false
+	opts = append(opts[:len(opts):len(opts)], llm.WithTruncationReport(func() { truncated = true }))
+	response, err := h.router.SendMessage(ctx, request, opts...)
+	if err != nil {
+		return "", false, err
+	}
+
+	for i := 0; truncated && i < h.continuation.max; i++ {
+		truncated = false
+		more, err := h.router.SendMessage(ctx, continuationRequest(request, response), opts...)
+		if err != nil {
+			log.Printf("Failed to continue a cut-off answer: %v", err)
+			truncated = true
+			break
+		}
+		response += more
+	}
+	return response, truncated, nil
+}
+
+// continuationRequest asks the model to pick up answer where it stopped.
+func continuationRequest(request []llm.Message, answer string) []llm.Message {
+	result := make([]llm.Message, 0, len(request)+2)
+	result = append(result, request...)
+	return append(result,
+		llm.Message{Role: "assistant", Content: answer},
+		llm.Message{Role: "user", Content: continuationPrompt},
+	)
+}
+
+// answerMarkup returns the buttons under the answer stored as messageID: the
+// feedback buttons and, for a cut-off answer, the continue button.
+func (h *Handlers) answerMarkup(user *models.User, messageID string, truncated bool) models.ReplyMarkup {
+	markup := h.feedbackMarkup(user, messageID)
+	if !truncated || h.continuation == nil || !h.continuation.button {
+		return markup
+	}
+
+	row := []models.InlineKeyboardButton{{Text: h.tr(user, "continue.button"), CallbackData: continueCallback + messageID}}
+	if keyboard, ok := markup.(*models.InlineKeyboardMarkup); ok {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
+		return keyboard
+	}
+	return &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{row}}
+}
+
+// ContinueCallbackHandler asks for the rest of the answer named in the
+// button's callback data, which the provider cut off, and appends it to the
+// stored answer.
+func (h *Handlers) ContinueCallbackHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || update.CallbackQuery == nil {
+		return
+	}
+
+	query := update.CallbackQuery
+	user := &query.From
+	answer := func(text string) {
+		if answerer, ok := sender.(CallbackAnswerer); ok {
+			answerer.AnswerCallbackQuery(ctx, &tgbot.AnswerCallbackQueryParams{
+				CallbackQueryID: query.ID,
+				Text:            text,
+			})
+		}
+	}
+
+	msg := query.Message.Message
+	answerID, ok := strings.CutPrefix(query.Data, continueCallback)
+	if !ok || h.continuation == nil || msg == nil {
+		answer(h.tr(user, "callback.unknown"))
+		return
+	}
+
+	chatID := msg.Chat.ID
+	key := h.sessionKey(chatID, user.ID)
+	unlock, err := h.lockSession(ctx, key)
+	if err != nil {
+		answer(h.tr(user, "chat.history_error"))
+		return
+	}
+	defer unlock()
+	messages, err := h.sessionManager.Get(key)
+	if err != nil {
+		answer(h.tr(user, "chat.history_error"))
+		return
+	}
+	i := slices.IndexFunc(messages, func(m llm.Message) bool { return m.ID == answerID })
+	if answerID == "" || i < 1 || messages[i].Role != "assistant" || messages[i-1].Role != "user" {
+		answer(h.tr(user, "continue.nothing"))
+		return
+	}
+	if refusal := h.budgetRefusal(user); refusal != "" {
+		answer(refusal)
+		return
+	}
+	answer("")
+
+	if editor, ok := sender.(MarkupEditor); ok {
+		editor.EditMessageReplyMarkup(ctx, &tgbot.EditMessageReplyMarkupParams{
+			ChatID:      chatID,
+			MessageID:   msg.ID,
+			ReplyMarkup: h.feedbackMarkup(user, answerID),
+		})
+	}
+
+	ctx, done := h.generate(ctx, sender, user.ID, chatID)
+	defer done()
+
+	last := messages[i]
+	request := continuationRequest(h.buildRequest(ctx, chatID, user.ID, messages[:i-1], messages[i-1]), last.Content)
+	response, truncated, err := h.sendContinued(ctx, request, h.chatOptions(chatID, user.ID))
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		log.Printf("Continue failed for user %d: %v", user.ID, err)
+		errMsg := h.tr(user, "chat.error")
+		if key, ok := providerErrorKeys[llm.Classify(err)]; ok {
+			errMsg = h.tr(user, key)
+		}
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   errMsg,
+		})
+		return
+	}
+	response = h.postprocess(ctx, user.ID, response)
+	if response == "" {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(user, "chat.empty"),
+		})
+		return
+	}
+
+	last.Content += response
+	last.Tokens.Completion += llm.EstimateTokens(response)
+	messages[i] = last
+	if err := h.sessionManager.Save(key, messages); err != nil {
+		log.Printf("Failed to save continued answer for user %d: %v", user.ID, err)
+	}
+
+	if _, err := h.sendReply(ctx, sender, &tgbot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        response,
+		ReplyMarkup: h.answerMarkup(user, last.ID, truncated),
+	}); err != nil {
+		log.Printf("Failed to send reply to chat %d: %v", chatID, err)
+	}
+	h.warnUsage(ctx, sender, chatID, user)
+}
@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// newPartsRouter returns a router whose Ollama provider answers with parts
+// in turn. Every part but the last is reported as cut off.
+func newPartsRouter(t *testing.T, parts ...string) llm.Router {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		part := parts[min(calls, len(parts)-1)]
+		reason := "stop"
+		if calls < len(parts)-1 {
+			reason = "length"
+		}
+		calls++
+		fmt.Fprintf(w, `{"message":{"role":"assistant","content":%q},"done":true,"done_reason":%q}`, part, reason)
+	}))
+	t.Cleanup(server.Close)
+
+	router, err := llm.NewRouter(&config.Config{
+		Providers: config.ProvidersConfig{Ollama: config.ProviderConfig{Enabled: true, DefaultModel: "llama3.2"}},
+		APIKeys:   map[string]string{"OLLAMA_BASE_URL": server.URL + "/v1/"},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	return router
+}
+
+func continueButton(markup models.ReplyMarkup) bool {
+	keyboard, ok := markup.(*models.InlineKeyboardMarkup)
+	if !ok {
+		return false
+	}
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			if strings.HasPrefix(button.CallbackData, continueCallback) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestTextMessageHandler_ContinuesCutOffAnswers(t *testing.T) {
+	router := newPartsRouter(t, "Once upon", " a time", " there was a bot")
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{1}, WithContinuation(1, true))
+	bot := &mockBot{}
+
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "Tell me a story"))
+
+	if bot.lastMessageParams.Text != "Once upon a time" {
+		t.Errorf("expected the stitched answer, got %q", bot.lastMessageParams.Text)
+	}
+	if !continueButton(bot.lastMessageParams.ReplyMarkup) {
+		t.Error("expected a continue button on an answer that is still cut off")
+	}
+	if len(sessions.saved) != 2 || sessions.saved[1].Content != "Once upon a time" {
+		t.Fatalf("expected the stitched answer to be saved, got %+v", sessions.saved)
+	}
+
+	sessions.messages = sessions.saved
+	handlers.ContinueCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, continueCallback+sessions.saved[1].ID))
+
+	if bot.lastMessageParams.Text != " there was a bot" {
+		t.Errorf("expected the rest of the answer, got %q", bot.lastMessageParams.Text)
+	}
+	if continueButton(bot.lastMessageParams.ReplyMarkup) {
+		t.Error("expected no continue button on a finished answer")
+	}
+	if got := sessions.saved[1].Content; got != "Once upon a time there was a bot" {
+		t.Errorf("expected the stored answer to be extended, got %q", got)
+	}
+}
+
+func TestTextMessageHandler_NoContinuationByDefault(t *testing.T) {
+	router := newPartsRouter(t, "Once upon", " a time")
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+	bot := &mockBot{}
+
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "Tell me a story"))
+
+	if bot.lastMessageParams.Text != "Once upon" {
+		t.Errorf("expected the cut-off answer as is, got %q", bot.lastMessageParams.Text)
+	}
+	if continueButton(bot.lastMessageParams.ReplyMarkup) {
+		t.Error("expected no continue button when continuation is off")
+	}
+}
+
+func TestContinueCallbackHandler_NothingToContinue(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithContinuation(0, true))
+	bot := &mockBot{}
+
+	handlers.ContinueCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, continueCallback))
+
+	if bot.lastCallback == nil || bot.lastCallback.Text != "There is no answer to continue." {
+		t.Errorf("unexpected callback answer %+v", bot.lastCallback)
+	}
+}
+
+func TestContinueCallbackHandler_ContinuesTheAnswerOfTheButton(t *testing.T) {
+	router := &mockRouter{response: " and the rest"}
+	sessions := &mockSessionManager{messages: []llm.Message{
+		{ID: "q1", Role: "user", Content: "Tell me a story"},
+		{ID: "a1", Role: "assistant", Content: "Once upon"},
+		{ID: "q2", Role: "user", Content: "Thanks"},
+		{ID: "a2", Role: "assistant", Content: "You're welcome"},
+	}}
+	handlers := NewHandlers(router, sessions, []int64{1}, WithContinuation(0, true))
+	bot := &mockBot{}
+
+	handlers.ContinueCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, continueCallback+"a1"))
+
+	if got := router.lastMessages[len(router.lastMessages)-2].Content; got != "Once upon" {
+		t.Errorf("expected the answer of the button to be continued, got %q", got)
+	}
+	if len(sessions.saved) != 4 || sessions.saved[1].Content != "Once upon and the rest" || sessions.saved[3].Content != "You're welcome" {
+		t.Errorf("expected only the answer of the button to be extended, got %+v", sessions.saved)
+	}
+}
@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot/models"
+)
+
+type debounceKey struct {
+	chatID int64
+	userID int64
+}
+
+type pendingMessages struct {
+	ctx    context.Context
+	sender BotSender
+	update *models.Update
+	texts  []string
+	timer  *time.Timer
+}
+
+type debouncer struct {
+	window  time.Duration
+	mu      sync.Mutex
+	pending map[debounceKey]*pendingMessages
+}
+
+// WithDebounce coalesces text messages a user sends within window of each
+// other into a single prompt. A zero window answers every message directly.
+func WithDebounce(window time.Duration) Option {
+	return func(h *Handlers) {
+		if window <= 0 {
+			return
+		}
+		h.debouncer = &debouncer{
+			window:  window,
+			pending: make(map[debounceKey]*pendingMessages),
+		}
+	}
+}
+
+// debounce holds the message until the user has been quiet for the debounce
+// window. Each new message restarts the wait.
+func (h *Handlers) debounce(ctx context.Context, sender BotSender, update *models.Update, text string) {
+	d := h.debouncer
+	key := debounceKey{chatID: update.Message.Chat.ID, userID: update.Message.From.ID}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if p, ok := d.pending[key]; ok {
+		p.ctx = ctx
+		p.sender = sender
+		p.update = update
+		p.texts = append(p.texts, text)
+		p.timer.Reset(d.window)
+		return
+	}
+
+	p := &pendingMessages{ctx: ctx, sender: sender, update: update, texts: []string{text}}
+	p.timer = time.AfterFunc(d.window, func() { h.flushDebounced(key, p) })
+	d.pending[key] = p
+}
+
+func (h *Handlers) flushDebounced(key debounceKey, p *pendingMessages) {
+	d := h.debouncer
+	d.mu.Lock()
+	if d.pending[key] != p {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.pending, key)
+	d.mu.Unlock()
+
+	msg := *p.update.Message
+	msg.Text = strings.Join(p.texts, "\n")
+	update := *p.update
+	update.Message = &msg
+
+	h.chat(p.ctx, p.sender, &update, msg.Text)
+}
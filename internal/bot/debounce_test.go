@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTextMessageHandler_DebounceCoalescesMessages(t *testing.T) {
+	router := &mockRouter{response: "ok"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithDebounce(time.Hour))
+
+	bot := &mockBot{}
+	for _, text := range []string{"hi", "I have a question", "what is Go?"} {
+		handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, text))
+	}
+	if router.lastMessages != nil {
+		t.Fatal("expected no request before the debounce window ends")
+	}
+
+	key := debounceKey{chatID: 1, userID: 1}
+	p := handlers.debouncer.pending[key]
+	p.timer.Stop()
+	handlers.flushDebounced(key, p)
+
+	last := router.lastMessages[len(router.lastMessages)-1]
+	if last.Content != "hi\nI have a question\nwhat is Go?" {
+		t.Errorf("expected coalesced prompt, got %q", last.Content)
+	}
+	if _, ok := handlers.debouncer.pending[key]; ok {
+		t.Error("expected pending messages to be cleared")
+	}
+}
+
+func TestFlushDebounced_IgnoresStaleTimer(t *testing.T) {
+	router := &mockRouter{response: "ok"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithDebounce(time.Hour))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hi"))
+	key := debounceKey{chatID: 1, userID: 1}
+	handlers.debouncer.pending[key].timer.Stop()
+
+	handlers.flushDebounced(key, &pendingMessages{})
+	if router.lastMessages != nil {
+		t.Error("expected stale flush to be ignored")
+	}
+}
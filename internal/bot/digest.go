@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/digest"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/settings"
+)
+
+const digestInstructions = "Write a short digest of the conversations below for the user who had them. " +
+	"List the main topics, answers worth remembering and anything left open. " +
+	"Address the user directly and write in the language of the conversations."
+
+// maxDigestExcerpt caps how much of each message goes into the digest
+// request so a busy week still fits in the context window.
+const maxDigestExcerpt = 500
+
+// WithDigest keeps the exchanges of users who opt in with /digest so
+// RunDigests can summarize them. provider may be empty to use the default.
+func WithDigest(store digest.Store, provider string) Option {
+	return func(h *Handlers) {
+		h.digestStore = store
+		h.digestProvider = provider
+	}
+}
+
+func (h *Handlers) DigestHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	if h.digestStore == nil || h.settings == nil {
+		reply(h.tr(user, "digest.disabled"))
+		return
+	}
+
+	frequency := strings.ToLower(commandArgs(update.Message.Text))
+	switch {
+	case frequency == "":
+		current, err := h.settings.String(user.ID, settings.Digest)
+		if err != nil {
+			log.Printf("Failed to load digest setting for user %d: %v", user.ID, err)
+		}
+		if current == "" {
+			current = "off"
+		}
+		reply(h.tr(user, "digest.status", current))
+		return
+	case frequency == "off":
+		frequency = ""
+	case !digest.ValidFrequency(frequency):
+		reply(h.tr(user, "digest.usage"))
+		return
+	}
+
+	if err := h.settings.SetString(user.ID, settings.Digest, frequency); err != nil {
+		log.Printf("Failed to save digest setting for user %d: %v", user.ID, err)
+		reply(h.tr(user, "digest.save_error"))
+		return
+	}
+	if frequency == "" {
+		reply(h.tr(user, "digest.off"))
+		return
+	}
+	reply(h.tr(user, "digest.on."+frequency))
+}
+
+// recordDigest keeps an exchange for the user's next digest when they have
+// opted in.
+func (h *Handlers) recordDigest(userID int64, prompt, response string) {
+	if h.digestStore == nil || h.settings == nil || strings.TrimSpace(prompt) == "" {
+		return
+	}
+	frequency, err := h.settings.String(userID, settings.Digest)
+	if err != nil || frequency == "" {
+		return
+	}
+	if err := h.digestStore.Record(digest.Entry{UserID: userID, Prompt: prompt, Response: response}); err != nil {
+		log.Printf("Failed to record digest entry for user %d: %v", userID, err)
+	}
+}
+
+// RunDigests sends a digest of the past day or week to every user who opted
+// in to frequency, then drops exchanges no digest needs anymore.
+func (h *Handlers) RunDigests(ctx context.Context, b any, frequency string) {
+	sender := resolveSender(b)
+	if sender == nil || h.digestStore == nil || h.settings == nil {
+		return
+	}
+
+	users, err := h.settings.All(settings.Digest)
+	if err != nil {
+		log.Printf("Digest: %v", err)
+		return
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -1)
+	if frequency == digest.Weekly {
+		since = now.AddDate(0, 0, -7)
+	}
+
+	for userID, f := range users {
+		if f != frequency {
+			continue
+		}
+		entries, err := h.digestStore.Since(userID, since)
+		if err != nil {
+			log.Printf("Digest for user %d: %v", userID, err)
+			continue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		summary, err := h.router.SendMessage(ctx, digestRequest(entries), h.digestOptions(userID)...)
+		if err != nil {
+			log.Printf("Digest for user %d failed: %v", userID, err)
+			continue
+		}
+		if strings.TrimSpace(summary) == "" {
+			continue
+		}
+
+		user := &models.User{ID: userID}
+		text := h.tr(user, "digest.header."+frequency) + "\n\n" + strings.TrimSpace(summary)
+		if err := h.deliver(ctx, sender, userID, text); err != nil {
+			log.Printf("Failed to send digest to user %d: %v", userID, err)
+		}
+	}
+
+	if err := h.digestStore.Prune(now.AddDate(0, 0, -7)); err != nil {
+		log.Printf("Digest: %v", err)
+	}
+}
+
+func (h *Handlers) digestOptions(userID int64) []llm.RequestOption {
+	opts := h.requestOptions(userID)
+	if h.digestProvider != "" {
+		opts = append(opts, llm.WithProvider(h.digestProvider))
+	}
+	return opts
+}
+
+func digestRequest(entries []digest.Entry) []llm.Message {
+	var transcript strings.Builder
+	for _, e := range entries {
+		transcript.WriteString(e.CreatedAt.Format("Mon 15:04") + "\n")
+		transcript.WriteString("User: " + truncate(e.Prompt, maxDigestExcerpt) + "\n")
+		transcript.WriteString("Assistant: " + truncate(e.Response, maxDigestExcerpt) + "\n\n")
+	}
+
+	return []llm.Message{
+		{Role: "system", Content: digestInstructions},
+		{Role: "user", Content: transcript.String()},
+	}
+}
@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/digest"
+	"github.com/jrswab/helpi/internal/settings"
+)
+
+func newDigestHandlers(t *testing.T, router *mockRouter) (*Handlers, digest.Store) {
+	store, err := digest.NewStore(filepath.Join(t.TempDir(), "digest.json"))
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+	handlers := NewHandlers(router, &mockSessionManager{}, nil,
+		WithDigest(store, ""), WithSettings(settings.New(settings.NewMemoryBackend())))
+	return handlers, store
+}
+
+func TestDigestHandler_OptInAndOut(t *testing.T) {
+	handlers, _ := newDigestHandlers(t, &mockRouter{})
+	b := &mockBot{}
+
+	handlers.DigestHandler(context.Background(), b, makeUpdate(1, 1, "/digest monthly"))
+	if !strings.Contains(b.lastMessageParams.Text, "Usage") {
+		t.Errorf("expected usage for an unknown frequency, got %q", b.lastMessageParams.Text)
+	}
+
+	handlers.DigestHandler(context.Background(), b, makeUpdate(1, 1, "/digest weekly"))
+	if f, _ := handlers.settings.String(1, settings.Digest); f != digest.Weekly {
+		t.Errorf("expected weekly digests, got %q", f)
+	}
+
+	handlers.DigestHandler(context.Background(), b, makeUpdate(1, 1, "/digest off"))
+	if f, _ := handlers.settings.String(1, settings.Digest); f != "" {
+		t.Errorf("expected digests to be turned off, got %q", f)
+	}
+}
+
+func TestRunDigests_SummarizesOptedInUsers(t *testing.T) {
+	router := &mockRouter{response: "You asked about pasta"}
+	handlers, store := newDigestHandlers(t, router)
+	handlers.settings.SetString(1, settings.Digest, digest.Daily)
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "how do I cook pasta?"))
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(2, 2, "not opted in"))
+
+	if entries, _ := store.Since(2, time.Time{}); len(entries) != 0 {
+		t.Errorf("expected nothing recorded for users who did not opt in, got %+v", entries)
+	}
+
+	b := &mockBot{}
+	handlers.RunDigests(context.Background(), b, digest.Weekly)
+	if len(b.sentMessages) != 0 {
+		t.Fatalf("expected no weekly digest for a daily subscriber, got %d messages", len(b.sentMessages))
+	}
+
+	handlers.RunDigests(context.Background(), b, digest.Daily)
+	if len(b.sentMessages) != 1 || b.sentMessages[0].ChatID != int64(1) {
+		t.Fatalf("expected one digest sent to user 1, got %+v", b.sentMessages)
+	}
+	if !strings.Contains(b.sentMessages[0].Text, "You asked about pasta") {
+		t.Errorf("expected the summary in the digest, got %q", b.sentMessages[0].Text)
+	}
+	if !strings.Contains(router.lastMessages[1].Content, "how do I cook pasta?") {
+		t.Errorf("expected the exchange in the digest request, got %+v", router.lastMessages)
+	}
+}
@@ -0,0 +1,182 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/ingest"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	maxDocumentSize     = 10 << 20
+	defaultMaxExcerpts  = 4
+	documentInstruction = "Answer using the following excerpts from the user's documents when they are relevant. Mention the document name when you rely on it."
+)
+
+func WithDocumentStore(s ingest.Store, maxExcerpts int) Option {
+	return func(h *Handlers) {
+		h.documentStore = s
+		h.maxExcerpts = maxExcerpts
+		if h.maxExcerpts <= 0 {
+			h.maxExcerpts = defaultMaxExcerpts
+		}
+	}
+}
+
+func IsDocumentMessage(update *models.Update) bool {
+	return update.Message != nil && update.Message.Document != nil
+}
+
+func (h *Handlers) DocumentHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || !IsDocumentMessage(update) {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	userID := update.Message.From.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	if isImportCaption(update.Message) {
+		h.ImportHandler(ctx, b, update)
+		return
+	}
+
+	doc := update.Message.Document
+	if h.documentStore == nil {
+		reply(h.tr(update.Message.From, "docs.disabled"))
+		return
+	}
+	if !ingest.Supported(doc.FileName, doc.MimeType) {
+		reply(h.tr(update.Message.From, "docs.unsupported"))
+		return
+	}
+	if doc.FileSize > maxDocumentSize {
+		reply(h.tr(update.Message.From, "docs.too_large", maxDocumentSize>>20))
+		return
+	}
+
+	downloader, ok := sender.(FileDownloader)
+	if !ok {
+		return
+	}
+	sender.SendChatAction(ctx, &tgbot.SendChatActionParams{
+		ChatID: chatID,
+		Action: models.ChatActionTyping,
+	})
+
+	data, err := downloadFile(ctx, downloader, doc.FileID, maxDocumentSize)
+	if err != nil {
+		log.Printf("Failed to download document from user %d: %v", userID, err)
+		reply(h.tr(update.Message.From, "docs.download_error"))
+		return
+	}
+
+	text, err := ingest.Extract(doc.FileName, doc.MimeType, data)
+	if errors.Is(err, ingest.ErrNoText) {
+		reply(h.tr(update.Message.From, "docs.no_text"))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to extract document from user %d: %v", userID, err)
+		reply(h.tr(update.Message.From, "docs.read_error"))
+		return
+	}
+
+	chunks := ingest.Chunk(text, ingest.DefaultChunkSize)
+	if _, err := h.documentStore.Add(userID, doc.FileName, chunks); err != nil {
+		log.Printf("Failed to store document from user %d: %v", userID, err)
+		reply(h.tr(update.Message.From, "docs.save_error"))
+		return
+	}
+
+	if question := strings.TrimSpace(update.Message.Caption); question != "" {
+		h.ask(ctx, sender, update, llm.Message{Role: "user", Content: attributed(update.Message, question)})
+		return
+	}
+	reply(h.tr(update.Message.From, "docs.saved", doc.FileName, len(chunks)))
+}
+
+func (h *Handlers) DocsHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	userID := update.Message.From.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	if h.documentStore == nil {
+		reply(h.tr(update.Message.From, "docs.disabled"))
+		return
+	}
+
+	if commandArgs(update.Message.Text) == "clear" {
+		if err := h.documentStore.Clear(userID); err != nil {
+			reply(h.tr(update.Message.From, "docs.delete_error", err))
+			return
+		}
+		reply(h.tr(update.Message.From, "docs.cleared"))
+		return
+	}
+
+	docs, err := h.documentStore.List(userID)
+	if err != nil {
+		reply(h.tr(update.Message.From, "docs.load_error", err))
+		return
+	}
+	if len(docs) == 0 {
+		reply(h.tr(update.Message.From, "docs.empty"))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.tr(update.Message.From, "docs.header") + "\n\n")
+	for _, d := range docs {
+		sb.WriteString(h.tr(update.Message.From, "docs.entry", d.ID, d.Name, len(d.Chunks)) + "\n")
+	}
+	sb.WriteString("\n" + h.tr(update.Message.From, "docs.footer"))
+	reply(sb.String())
+}
+
+func (h *Handlers) withDocuments(userID int64, messages []llm.Message, query string) []llm.Message {
+	if h.documentStore == nil || strings.TrimSpace(query) == "" {
+		return messages
+	}
+
+	excerpts, err := h.documentStore.Search(userID, query, h.maxExcerpts)
+	if err != nil {
+		log.Printf("Failed to search documents for user %d: %v", userID, err)
+		return messages
+	}
+	if len(excerpts) == 0 {
+		return messages
+	}
+
+	var sb strings.Builder
+	sb.WriteString(documentInstruction)
+	for _, e := range excerpts {
+		sb.WriteString(fmt.Sprintf("\n\n[%s]\n%s", e.Document, e.Text))
+	}
+
+	result := make([]llm.Message, 0, len(messages)+1)
+	result = append(result, llm.ContextMessage(sb.String()))
+	return append(result, messages...)
+}
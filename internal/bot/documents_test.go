@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/ingest"
+)
+
+func makeDocumentUpdate(userID int64, name, caption string) *models.Update {
+	update := makeUpdate(userID, userID, "")
+	update.Message.Caption = caption
+	update.Message.Document = &models.Document{FileID: "doc1", FileName: name, FileSize: 64}
+	return update
+}
+
+func TestDocumentHandler_StoresAndAnswersFromDocument(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "The wifi password is hunter2.\n\nCheckout is at 11am.")
+	}))
+	defer server.Close()
+
+	store, err := ingest.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+	router := &mockRouter{response: "It is hunter2"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithDocumentStore(store, 0))
+
+	bot := &mockFileBot{baseURL: server.URL}
+	handlers.DocumentHandler(context.Background(), bot, makeDocumentUpdate(1, "house.txt", ""))
+
+	if !strings.Contains(bot.lastMessageParams.Text, "Saved house.txt") {
+		t.Fatalf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "what is the wifi password?"))
+
+	if router.lastMessages[0].Role != "system" || !strings.Contains(router.lastMessages[0].Content, "hunter2") {
+		t.Errorf("expected document excerpt in request, got %+v", router.lastMessages)
+	}
+}
+
+func TestDocumentHandler_RejectsUnsupportedType(t *testing.T) {
+	store, _ := ingest.NewStore(t.TempDir())
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithDocumentStore(store, 0))
+
+	bot := &mockFileBot{}
+	handlers.DocumentHandler(context.Background(), bot, makeDocumentUpdate(1, "archive.zip", ""))
+
+	if bot.requested != "" || !strings.Contains(bot.lastMessageParams.Text, "Unsupported file type") {
+		t.Errorf("expected rejection without download, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestDocsHandler_ListAndClear(t *testing.T) {
+	store, _ := ingest.NewStore(t.TempDir())
+	store.Add(1, "notes.md", []string{"a", "b"})
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithDocumentStore(store, 0))
+
+	bot := &mockBot{}
+	handlers.DocsHandler(context.Background(), bot, makeUpdate(1, 1, "/docs"))
+	if !strings.Contains(bot.lastMessageParams.Text, "1. notes.md (2 sections)") {
+		t.Errorf("unexpected list %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.DocsHandler(context.Background(), bot, makeUpdate(1, 1, "/docs clear"))
+	if docs, _ := store.List(1); len(docs) != 0 {
+		t.Errorf("expected documents to be cleared, got %d", len(docs))
+	}
+}
@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// promptRef identifies the Telegram message behind the last prompt of a
+// session.
+type promptRef struct {
+	chatID    int64
+	messageID int
+}
+
+// lastPrompts remembers which message produced the latest exchange of each
+// session so an edit of that message can replace it.
+type lastPrompts struct {
+	mu   sync.Mutex
+	refs map[int64]promptRef
+}
+
+func (l *lastPrompts) set(key int64, ref promptRef) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.refs == nil {
+		l.refs = make(map[int64]promptRef)
+	}
+	l.refs[key] = ref
+}
+
+func (l *lastPrompts) is(key int64, ref promptRef) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.refs[key] == ref
+}
+
+func (l *lastPrompts) forget(key int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.refs, key)
+}
+
+// WithEditReprocessing answers edits of a user's last prompt again, replacing
+// the previous exchange in the session.
+func WithEditReprocessing(enabled bool) Option {
+	return func(h *Handlers) {
+		h.reprocessEdits = enabled
+	}
+}
+
+// IsEditedMessage matches updates sent when a user edits one of their
+// messages.
+func IsEditedMessage(update *models.Update) bool {
+	return update.EditedMessage != nil && update.EditedMessage.From != nil
+}
+
+// EditedMessageHandler regenerates the answer when the user edits the prompt
+// of the latest exchange. Edits of older messages are ignored since later
+// answers may depend on them.
+func (h *Handlers) EditedMessageHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || !h.reprocessEdits || !IsEditedMessage(update) {
+		return
+	}
+
+	msg := update.EditedMessage
+	if len(msg.Photo) > 0 || msg.Document != nil || strings.HasPrefix(msg.Text, "/") {
+		return
+	}
+	key := h.sessionKey(msg.Chat.ID, msg.From.ID)
+	if !h.lastPrompts.is(key, promptRef{chatID: msg.Chat.ID, messageID: msg.ID}) {
+		return
+	}
+	text := messageText(msg)
+	if text == "" {
+		return
+	}
+
+	if !h.dropLastExchange(ctx, key, msg) {
+		return
+	}
+
+	h.chat(ctx, sender, &models.Update{ID: update.ID, Message: msg}, text)
+}
+
+// dropLastExchange removes the latest prompt and answer from the session
+// key if msg is still its prompt once no other request is being answered.
+func (h *Handlers) dropLastExchange(ctx context.Context, key int64, msg *models.Message) bool {
+	userID := msg.From.ID
+	unlock, err := h.lockSession(ctx, key)
+	if err != nil {
+		log.Printf("Failed to lock session for edited message from user %d: %v", userID, err)
+		return false
+	}
+	defer unlock()
+	if !h.lastPrompts.is(key, promptRef{chatID: msg.Chat.ID, messageID: msg.ID}) {
+		return false
+	}
+
+	messages, err := h.sessionManager.Get(key)
+	if err != nil {
+		log.Printf("Failed to load session for edited message from user %d: %v", userID, err)
+		return false
+	}
+	n := len(messages)
+	if n < 2 || messages[n-1].Role != "assistant" || messages[n-2].Role != "user" {
+		return false
+	}
+	if err := h.sessionManager.Save(key, messages[:n-2]); err != nil {
+		log.Printf("Failed to drop edited exchange for user %d: %v", userID, err)
+		return false
+	}
+	h.lastPrompts.forget(key)
+	return true
+}
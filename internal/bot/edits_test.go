@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// historySessions returns what was last saved, like a real store.
+type historySessions struct {
+	mockSessionManager
+}
+
+func (s *historySessions) Get(userID int64) ([]llm.Message, error) {
+	return s.saved, nil
+}
+
+func makeEditedUpdate(userID, chatID int64, messageID int, text string) *models.Update {
+	return &models.Update{EditedMessage: &models.Message{
+		ID:   messageID,
+		From: &models.User{ID: userID},
+		Chat: models.Chat{ID: chatID},
+		Text: text,
+	}}
+}
+
+func TestEditedMessageHandler_ReplacesLastExchange(t *testing.T) {
+	router := &mockRouter{response: "4"}
+	sessions := &historySessions{}
+	handlers := NewHandlers(router, sessions, nil, WithEditReprocessing(true))
+	b := &mockBot{}
+
+	update := makeUpdate(1, 1, "2+2?")
+	update.Message.ID = 10
+	handlers.TextMessageHandler(context.Background(), b, update)
+
+	router.response = "6"
+	handlers.EditedMessageHandler(context.Background(), b, makeEditedUpdate(1, 1, 9, "older message"))
+	if len(sessions.saved) != 2 || sessions.saved[1].Content != "4" {
+		t.Fatalf("expected edits of older messages to be ignored, got %+v", sessions.saved)
+	}
+
+	handlers.EditedMessageHandler(context.Background(), b, makeEditedUpdate(1, 1, 10, "3+3?"))
+	if len(sessions.saved) != 2 || sessions.saved[0].Content != "3+3?" || sessions.saved[1].Content != "6" {
+		t.Errorf("expected the exchange to be replaced, got %+v", sessions.saved)
+	}
+	if b.lastMessageParams.Text != "6" {
+		t.Errorf("expected the new answer to be sent, got %q", b.lastMessageParams.Text)
+	}
+}
+
+func TestEditedMessageHandler_Disabled(t *testing.T) {
+	router := &mockRouter{response: "4"}
+	sessions := &historySessions{}
+	handlers := NewHandlers(router, sessions, nil)
+
+	update := makeUpdate(1, 1, "2+2?")
+	update.Message.ID = 10
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, update)
+
+	router.response = "6"
+	handlers.EditedMessageHandler(context.Background(), &mockBot{}, makeEditedUpdate(1, 1, 10, "3+3?"))
+	if sessions.saved[1].Content != "4" {
+		t.Errorf("expected edits to be ignored when the mode is off, got %+v", sessions.saved)
+	}
+}
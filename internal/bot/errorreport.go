@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// errorReportWindow is how long an error is held back after it has been
+	// reported. Repeats in that time are counted into the next report.
+	errorReportWindow = 15 * time.Minute
+	errorReportLength = 1000
+)
+
+// Kinds of errors sent to the admin chat.
+const (
+	ErrorKindProvider = "provider"
+	ErrorKindPanic    = "panic"
+	ErrorKindConfig   = "config"
+)
+
+var errorReportDigits = regexp.MustCompile(`\d+`)
+
+type reportedError struct {
+	sentAt     time.Time
+	suppressed int
+}
+
+// ErrorReporter forwards provider failures, panics and config problems to
+// an admin chat. Errors that differ only in numbers, such as request IDs and
+// retry delays, count as the same error.
+type ErrorReporter struct {
+	chatID int64
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]*reportedError
+}
+
+func NewErrorReporter(chatID int64) *ErrorReporter {
+	return &ErrorReporter{
+		chatID: chatID,
+		window: errorReportWindow,
+		now:    time.Now,
+		seen:   make(map[string]*reportedError),
+	}
+}
+
+func WithErrorReporter(r *ErrorReporter) Option {
+	return func(h *Handlers) {
+		h.errorReporter = r
+	}
+}
+
+// Report sends message to the admin chat unless the same error was sent
+// within the throttle window.
+func (r *ErrorReporter) Report(ctx context.Context, b any, kind, message string) {
+	if r == nil {
+		return
+	}
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+	repeats, ok := r.record(kind, message)
+	if !ok {
+		return
+	}
+
+	text := fmt.Sprintf("⚠️ %s error\n\n%s", kind, truncate(message, errorReportLength))
+	if repeats > 0 {
+		text += fmt.Sprintf("\n\nSeen %d more times since the last report.", repeats)
+	}
+	if _, err := sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: r.chatID,
+		Text:   text,
+	}); err != nil {
+		log.Printf("Failed to report %s error to chat %d: %v", kind, r.chatID, err)
+	}
+}
+
+// record returns how many repeats were held back since the error was last
+// sent, and whether it should be sent now.
+func (r *ErrorReporter) record(kind, message string) (int, bool) {
+	key := kind + ":" + errorReportDigits.ReplaceAllString(message, "#")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	seen, ok := r.seen[key]
+	if !ok {
+		r.seen[key] = &reportedError{sentAt: now}
+		return 0, true
+	}
+	if now.Sub(seen.sentAt) < r.window {
+		seen.suppressed++
+		return 0, false
+	}
+	repeats := seen.suppressed
+	seen.sentAt, seen.suppressed = now, 0
+	return repeats, true
+}
+
+// Middleware reports panics in the handlers after it and panics again, so
+// it belongs after RecoveryMiddleware.
+func (r *ErrorReporter) Middleware(next tgbot.HandlerFunc) tgbot.HandlerFunc {
+	return func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if b != nil {
+				r.Report(context.WithoutCancel(ctx), b, ErrorKindPanic, fmt.Sprintf("%s from user %d: %v\n\n%s", updateKind(update), updateUserID(update), p, debug.Stack()))
+			}
+			panic(p)
+		}()
+		next(ctx, b, update)
+	}
+}
+
+// ErrorReporter returns the reporter set with WithErrorReporter, or nil.
+func (h *Handlers) ErrorReporter() *ErrorReporter {
+	return h.errorReporter
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorReporter_Throttles(t *testing.T) {
+	now := time.Now()
+	reporter := NewErrorReporter(-100)
+	reporter.now = func() time.Time { return now }
+
+	bot := &mockBot{}
+	reporter.Report(context.Background(), bot, ErrorKindProvider, "openai: 500 request req_123 failed")
+	reporter.Report(context.Background(), bot, ErrorKindProvider, "openai: 500 request req_456 failed")
+	reporter.Report(context.Background(), bot, ErrorKindConfig, "openai: 500 request req_456 failed")
+	if len(bot.sentMessages) != 2 {
+		t.Fatalf("expected the repeat to be held back, got %d messages", len(bot.sentMessages))
+	}
+	if msg := bot.sentMessages[0]; msg.ChatID != int64(-100) || !strings.Contains(msg.Text, "provider error") {
+		t.Errorf("unexpected report %+v", msg)
+	}
+
+	now = now.Add(errorReportWindow)
+	reporter.Report(context.Background(), bot, ErrorKindProvider, "openai: 500 request req_789 failed")
+	if len(bot.sentMessages) != 3 {
+		t.Fatalf("expected a report after the window, got %d messages", len(bot.sentMessages))
+	}
+	if !strings.Contains(bot.lastMessageParams.Text, "Seen 1 more times") {
+		t.Errorf("expected the held back repeat to be counted, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestErrorReporter_Nil(t *testing.T) {
+	var reporter *ErrorReporter
+	reporter.Report(context.Background(), &mockBot{}, ErrorKindConfig, "ignored")
+}
+
+func TestTextMessageHandler_ReportsProviderErrors(t *testing.T) {
+	router := &mockRouter{providerName: "openai", err: errors.New("openai: 503 service unavailable")}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithErrorReporter(NewErrorReporter(-100)))
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "Hello"))
+
+	var reported bool
+	for _, msg := range bot.sentMessages {
+		if msg.ChatID == int64(-100) && strings.Contains(msg.Text, "503 service unavailable") {
+			reported = true
+		}
+	}
+	if !reported {
+		t.Errorf("expected the failure in the admin chat, got %d messages", len(bot.sentMessages))
+	}
+}
@@ -0,0 +1,11 @@
+package bot
+
+import "github.com/jrswab/helpi/internal/llm"
+
+// providerErrorKeys maps provider error classes to the message users see.
+var providerErrorKeys = map[llm.ErrorClass]string{
+	llm.ErrorRateLimited:   "chat.provider_rate_limited",
+	llm.ErrorAuth:          "chat.provider_auth",
+	llm.ErrorContextLength: "chat.context_length",
+	llm.ErrorServer:        "chat.provider_error",
+}
@@ -0,0 +1,217 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/facts"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const factsInstruction = "Facts the user asked you to remember:"
+
+// FactExtractor picks lasting facts out of an exchange and stores them, as
+// facts.Extractor does.
+type FactExtractor interface {
+	Extract(ctx context.Context, userID int64, user, assistant string, opts ...llm.RequestOption) ([]facts.Fact, error)
+}
+
+func WithFactStore(s facts.Store) Option {
+	return func(h *Handlers) {
+		h.factStore = s
+	}
+}
+
+func WithFactExtractor(e FactExtractor) Option {
+	return func(h *Handlers) {
+		h.factExtractor = e
+	}
+}
+
+// RememberHandler stores a fact that is added to the system prompt of every
+// request the user makes.
+func (h *Handlers) RememberHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	if h.factStore == nil {
+		reply(h.tr(user, "remember.disabled"))
+		return
+	}
+	text := commandArgs(update.Message.Text)
+	if text == "" {
+		reply(h.tr(user, "remember.usage"))
+		return
+	}
+
+	switch err := h.factStore.Add(user.ID, facts.Fact{Text: text}); {
+	case err == nil:
+		reply(h.tr(user, "remember.done"))
+	case errors.Is(err, facts.ErrDuplicate):
+		reply(h.tr(user, "remember.duplicate"))
+	case errors.Is(err, facts.ErrLimit):
+		reply(h.tr(user, "remember.limit", facts.MaxFacts))
+	case errors.Is(err, facts.ErrTooLong):
+		reply(h.tr(user, "remember.too_long", facts.MaxLength))
+	default:
+		log.Printf("Failed to store fact for user %d: %v", user.ID, err)
+		reply(h.tr(user, "remember.error"))
+	}
+}
+
+// MemoriesHandler lists the user's facts, numbered for /forget.
+func (h *Handlers) MemoriesHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	if h.factStore == nil {
+		reply(h.tr(user, "remember.disabled"))
+		return
+	}
+	list, err := h.factStore.List(user.ID)
+	if err != nil {
+		log.Printf("Failed to load facts for user %d: %v", user.ID, err)
+		reply(h.tr(user, "remember.error"))
+		return
+	}
+	if len(list) == 0 {
+		reply(h.tr(user, "memories.none"))
+		return
+	}
+
+	lines := []string{h.tr(user, "memories.header")}
+	for i, f := range list {
+		line := fmt.Sprintf("%d. %s", i+1, f.Text)
+		if f.Learned {
+			line += " " + h.tr(user, "memories.learned")
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", h.tr(user, "memories.footer"))
+	reply(strings.Join(lines, "\n"))
+}
+
+// ForgetHandler removes one fact by its number in /memories, or all of them.
+func (h *Handlers) ForgetHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	if h.factStore == nil {
+		reply(h.tr(user, "remember.disabled"))
+		return
+	}
+
+	arg := strings.ToLower(commandArgs(update.Message.Text))
+	if arg == "all" {
+		if err := h.factStore.Clear(user.ID); err != nil {
+			log.Printf("Failed to clear facts for user %d: %v", user.ID, err)
+			reply(h.tr(user, "remember.error"))
+			return
+		}
+		reply(h.tr(user, "forget.all"))
+		return
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		reply(h.tr(user, "forget.usage"))
+		return
+	}
+
+	removed, err := h.factStore.Remove(user.ID, n-1)
+	if errors.Is(err, facts.ErrNotFound) {
+		reply(h.tr(user, "forget.unknown"))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to remove fact for user %d: %v", user.ID, err)
+		reply(h.tr(user, "remember.error"))
+		return
+	}
+	reply(h.tr(user, "forget.done", removed.Text))
+}
+
+// withFacts adds the user's facts to the system prompt. Without one they are
+// sent as context, so a provider's configured system prompt still applies.
+func (h *Handlers) withFacts(userID int64, messages []llm.Message) []llm.Message {
+	if h.factStore == nil {
+		return messages
+	}
+	list, err := h.factStore.List(userID)
+	if err != nil {
+		log.Printf("Failed to load facts for user %d: %v", userID, err)
+		return messages
+	}
+	if len(list) == 0 {
+		return messages
+	}
+
+	var sb strings.Builder
+	sb.WriteString(factsInstruction)
+	for _, f := range list {
+		sb.WriteString("\n- " + f.Text)
+	}
+
+	if len(messages) > 0 && messages[0].Role == "system" && !messages[0].Context && !llm.IsSummary(messages[0]) {
+		result := append([]llm.Message(nil), messages...)
+		result[0].Content += "\n\n" + sb.String()
+		return result
+	}
+	result := make([]llm.Message, 0, len(messages)+1)
+	result = append(result, llm.ContextMessage(sb.String()))
+	return append(result, messages...)
+}
+
+// extractFacts looks for new facts in an answered exchange in the
+// background, so the reply is not held up by a second request.
+func (h *Handlers) extractFacts(ctx context.Context, userID int64, user, assistant string) {
+	if h.factExtractor == nil || strings.TrimSpace(user) == "" {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		added, err := h.factExtractor.Extract(ctx, userID, user, assistant, h.userOptions(userID)...)
+		if err != nil {
+			log.Printf("Failed to extract facts for user %d: %v", userID, err)
+			return
+		}
+		if len(added) > 0 {
+			log.Printf("Learned %d facts about user %d", len(added), userID)
+		}
+	}()
+}
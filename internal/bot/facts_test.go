@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/facts"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestFactHandlers(t *testing.T) {
+	store, err := facts.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+	handlers := NewHandlers(&mockRouter{}, &historySessions{}, []int64{1}, WithFactStore(store))
+	bot := &mockBot{}
+	ctx := context.Background()
+
+	handlers.RememberHandler(ctx, bot, makeUpdate(1, 1, "/remember"))
+	if !strings.HasPrefix(bot.lastMessageParams.Text, "Usage: /remember") {
+		t.Errorf("expected usage, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.RememberHandler(ctx, bot, makeUpdate(1, 1, "/remember I am vegetarian"))
+	handlers.RememberHandler(ctx, bot, makeUpdate(1, 1, "/remember I live in Lisbon"))
+	handlers.RememberHandler(ctx, bot, makeUpdate(1, 1, "/remember I live in Lisbon"))
+	if bot.lastMessageParams.Text != "I already remember that." {
+		t.Errorf("expected duplicate reply, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.MemoriesHandler(ctx, bot, makeUpdate(1, 1, "/memories"))
+	if !strings.Contains(bot.lastMessageParams.Text, "1. I am vegetarian\n2. I live in Lisbon") {
+		t.Errorf("unexpected memories %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.ForgetHandler(ctx, bot, makeUpdate(1, 1, "/forget 3"))
+	if bot.lastMessageParams.Text != "No memory with that number. See /memories." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+	handlers.ForgetHandler(ctx, bot, makeUpdate(1, 1, "/forget 1"))
+	if bot.lastMessageParams.Text != "Forgotten: I am vegetarian" {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+	handlers.ForgetHandler(ctx, bot, makeUpdate(1, 1, "/forget all"))
+	handlers.MemoriesHandler(ctx, bot, makeUpdate(1, 1, "/memories"))
+	if !strings.HasPrefix(bot.lastMessageParams.Text, "I don't remember anything") {
+		t.Errorf("expected no memories, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestWithFacts(t *testing.T) {
+	store, _ := facts.NewStore(t.TempDir())
+	store.Add(1, facts.Fact{Text: "I am vegetarian"})
+	handlers := NewHandlers(&mockRouter{}, &historySessions{}, []int64{1}, WithFactStore(store))
+
+	withPrompt := handlers.withFacts(1, []llm.Message{
+		{Role: "system", Content: "Be brief."},
+		{Role: "user", Content: "Suggest a dinner"},
+	})
+	if len(withPrompt) != 2 || withPrompt[0].Content != "Be brief.\n\n"+factsInstruction+"\n- I am vegetarian" {
+		t.Errorf("expected facts in the system prompt, got %+v", withPrompt)
+	}
+
+	withoutPrompt := handlers.withFacts(1, []llm.Message{{Role: "user", Content: "Suggest a dinner"}})
+	if len(withoutPrompt) != 2 || !withoutPrompt[0].Context || !strings.Contains(withoutPrompt[0].Content, "I am vegetarian") {
+		t.Errorf("expected facts as context, got %+v", withoutPrompt)
+	}
+
+	if got := handlers.withFacts(2, []llm.Message{{Role: "user", Content: "hi"}}); len(got) != 1 {
+		t.Errorf("expected no change without facts, got %+v", got)
+	}
+}
+
+type factExtractorFunc func(userID int64, user, assistant string) ([]facts.Fact, error)
+
+func (f factExtractorFunc) Extract(ctx context.Context, userID int64, user, assistant string, opts ...llm.RequestOption) ([]facts.Fact, error) {
+	return f(userID, user, assistant)
+}
+
+func TestExtractFactsAfterAnswer(t *testing.T) {
+	store, _ := facts.NewStore(t.TempDir())
+	extracted := make(chan string, 1)
+	extractor := factExtractorFunc(func(userID int64, user, assistant string) ([]facts.Fact, error) {
+		fact := facts.Fact{Text: "I am vegetarian", Learned: true}
+		store.Add(userID, fact)
+		extracted <- user + "|" + assistant
+		return []facts.Fact{fact}, nil
+	})
+	handlers := NewHandlers(&mockRouter{response: "Try a chickpea curry."}, &historySessions{}, []int64{1}, WithFactStore(store), WithFactExtractor(extractor))
+	bot := &mockBot{}
+
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "I'm vegetarian, any dinner ideas?"))
+	if got := <-extracted; got != "I'm vegetarian, any dinner ideas?|Try a chickpea curry." {
+		t.Errorf("unexpected exchange %q", got)
+	}
+
+	handlers.MemoriesHandler(context.Background(), bot, makeUpdate(1, 1, "/memories"))
+	if !strings.Contains(bot.lastMessageParams.Text, "1. I am vegetarian (learned)") {
+		t.Errorf("expected the learned fact to be marked, got %q", bot.lastMessageParams.Text)
+	}
+}
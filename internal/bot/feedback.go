@@ -0,0 +1,327 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/feedback"
+	"github.com/jrswab/helpi/internal/i18n"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+const (
+	feedbackCallbackPrefix = "feedback:"
+	badAnswerCallback      = feedbackCallbackPrefix + "bad"
+	rateUpCallback         = feedbackCallbackPrefix + "up:"
+	rateDownCallback       = feedbackCallbackPrefix + "down:"
+	feedbackListLimit      = 10
+	feedbackSnippetLength  = 80
+)
+
+type DocumentSender interface {
+	SendDocument(ctx context.Context, params *tgbot.SendDocumentParams) (*models.Message, error)
+}
+
+func WithFeedbackStore(s feedback.Store) Option {
+	return func(h *Handlers) {
+		h.feedbackStore = s
+	}
+}
+
+// WithFeedbackButtons adds 👍 and 👎 buttons to answers, next to the
+// button that reports a bad answer.
+func WithFeedbackButtons(enabled bool) Option {
+	return func(h *Handlers) {
+		h.feedbackButtons = enabled
+	}
+}
+
+// feedbackMarkup returns the feedback buttons of the answer stored as
+// messageID.
+func (h *Handlers) feedbackMarkup(user *models.User, messageID string) models.ReplyMarkup {
+	if h.feedbackStore == nil {
+		return nil
+	}
+	var rows [][]models.InlineKeyboardButton
+	if h.feedbackButtons && messageID != "" {
+		rows = append(rows, []models.InlineKeyboardButton{
+			{Text: "👍", CallbackData: rateUpCallback + messageID},
+			{Text: "👎", CallbackData: rateDownCallback + messageID},
+		})
+	}
+	rows = append(rows, []models.InlineKeyboardButton{
+		{Text: h.tr(user, "feedback.report"), CallbackData: badAnswerCallback},
+	})
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func (h *Handlers) FeedbackHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	if h.feedbackStore == nil {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "feedback.disabled"),
+		})
+		return
+	}
+
+	text := commandArgs(update.Message.Text)
+	if text == "" {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "feedback.usage"),
+		})
+		return
+	}
+
+	userID := update.Message.From.ID
+	entry, err := h.recordFeedback(feedback.Entry{
+		Kind:   feedback.KindFeedback,
+		UserID: userID,
+		ChatID: chatID,
+		Text:   text,
+	})
+	if err != nil {
+		log.Printf("Failed to store feedback from user %d: %v", userID, err)
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "feedback.save_error"),
+		})
+		return
+	}
+
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: chatID,
+		Text:   h.tr(update.Message.From, "feedback.thanks", entry.ID),
+	})
+}
+
+func (h *Handlers) FeedbackCallbackHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || update.CallbackQuery == nil {
+		return
+	}
+
+	query := update.CallbackQuery
+	answer := func(text string) {
+		if answerer, ok := sender.(CallbackAnswerer); ok {
+			answerer.AnswerCallbackQuery(ctx, &tgbot.AnswerCallbackQueryParams{
+				CallbackQueryID: query.ID,
+				Text:            text,
+			})
+		}
+	}
+
+	if h.feedbackStore != nil {
+		if id, ok := strings.CutPrefix(query.Data, rateUpCallback); ok {
+			answer(h.rateAnswer(query, id, 1))
+			return
+		}
+		if id, ok := strings.CutPrefix(query.Data, rateDownCallback); ok {
+			answer(h.rateAnswer(query, id, -1))
+			return
+		}
+	}
+	if query.Data != badAnswerCallback || h.feedbackStore == nil {
+		answer(h.tr(&query.From, "callback.unknown"))
+		return
+	}
+
+	entry := feedback.Entry{
+		Kind:   feedback.KindBadAnswer,
+		UserID: query.From.ID,
+	}
+	if msg := query.Message.Message; msg != nil {
+		entry.ChatID = msg.Chat.ID
+		entry.Answer = msg.Text
+	}
+
+	if _, err := h.recordFeedback(entry); err != nil {
+		log.Printf("Failed to store bad answer report from user %d: %v", query.From.ID, err)
+		answer(h.tr(&query.From, "feedback.report_error"))
+		return
+	}
+	answer(h.tr(&query.From, "feedback.reported"))
+}
+
+// rateAnswer records a 👍 (score 1) or 👎 (score -1) on the answer stored
+// as messageID and returns the message to show.
+func (h *Handlers) rateAnswer(query *models.CallbackQuery, messageID string, score int) string {
+	entry := feedback.Entry{
+		Kind:      feedback.KindRating,
+		UserID:    query.From.ID,
+		Score:     score,
+		MessageID: messageID,
+	}
+	if msg := query.Message.Message; msg != nil {
+		entry.ChatID = msg.Chat.ID
+		entry.Answer = msg.Text
+	}
+	entry.Conversation = h.rateExchange(entry.ChatID, entry.UserID, messageID, score)
+
+	if _, err := h.feedbackStore.Add(entry); err != nil {
+		log.Printf("Failed to store rating from user %d: %v", query.From.ID, err)
+		return h.tr(&query.From, "feedback.report_error")
+	}
+	return h.tr(&query.From, "feedback.rated")
+}
+
+// rateExchange stores score on the answer messageID in the session and
+// returns the answer with the question before it. It returns nil when the
+// answer is no longer in the session.
+func (h *Handlers) rateExchange(chatID, userID int64, messageID string, score int) []llm.Message {
+	key := h.sessionKey(chatID, userID)
+	err := h.sessionManager.SetRating(key, messageID, score)
+	if errors.Is(err, session.ErrMessageNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("Failed to save rating for user %d: %v", userID, err)
+	}
+	messages, err := h.sessionManager.Get(key)
+	if err != nil {
+		log.Printf("Failed to load conversation of user %d: %v", userID, err)
+		return nil
+	}
+	i := slices.IndexFunc(messages, func(m llm.Message) bool { return m.ID == messageID })
+	if i < 0 {
+		return nil
+	}
+	start := i
+	if i > 0 && messages[i-1].Role == "user" {
+		start = i - 1
+	}
+	return append([]llm.Message(nil), messages[start:i+1]...)
+}
+
+// ratingReport sums up the 👍 and 👎 ratings for /admin stats.
+func (h *Handlers) ratingReport(user *models.User) string {
+	if h.feedbackStore == nil {
+		return h.tr(user, "admin.stats.disabled")
+	}
+	entries, err := h.feedbackStore.List()
+	if err != nil {
+		log.Printf("Failed to load feedback: %v", err)
+		return h.tr(user, "admin.stats.error")
+	}
+	up, down := feedback.Ratings(entries)
+	if up+down == 0 {
+		return h.tr(user, "admin.stats.none")
+	}
+	return h.tr(user, "admin.stats.ratings", up, down, up*100/(up+down))
+}
+
+func (h *Handlers) recordFeedback(entry feedback.Entry) (feedback.Entry, error) {
+	messages, err := h.sessionManager.Get(h.sessionKey(entry.ChatID, entry.UserID))
+	if err != nil {
+		log.Printf("Failed to snapshot conversation for user %d: %v", entry.UserID, err)
+	}
+	entry.Conversation = messages
+	return h.feedbackStore.Add(entry)
+}
+
+func (h *Handlers) FeedbackListHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	if !h.isAdmin(update.Message.From.ID) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "feedback.admins_only"),
+		})
+		return
+	}
+	if h.feedbackStore == nil {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "feedback.disabled"),
+		})
+		return
+	}
+
+	entries, err := h.feedbackStore.List()
+	if err != nil {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "feedback.load_error", err),
+		})
+		return
+	}
+
+	if commandArgs(update.Message.Text) == "export" {
+		h.exportFeedback(ctx, sender, update.Message.From, chatID, entries)
+		return
+	}
+
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: chatID,
+		Text:   formatFeedbackList(h.Language(update.Message.From), entries),
+	})
+}
+
+func (h *Handlers) exportFeedback(ctx context.Context, sender BotSender, user *models.User, chatID int64, entries []feedback.Entry) {
+	docSender, ok := sender.(DocumentSender)
+	if !ok {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(user, "feedback.export_unsupported"),
+		})
+		return
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(user, "feedback.export_error", err),
+		})
+		return
+	}
+
+	if _, err := docSender.SendDocument(ctx, &tgbot.SendDocumentParams{
+		ChatID:   chatID,
+		Document: &models.InputFileUpload{Filename: "feedback.json", Data: bytes.NewReader(data)},
+		Caption:  h.tr(user, "feedback.export_caption", len(entries)),
+	}); err != nil {
+		log.Printf("Failed to export feedback: %v", err)
+	}
+}
+
+func formatFeedbackList(lang string, entries []feedback.Entry) string {
+	if len(entries) == 0 {
+		return i18n.T(lang, "feedback.list_empty")
+	}
+
+	start := 0
+	if len(entries) > feedbackListLimit {
+		start = len(entries) - feedbackListLimit
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, "feedback.list_header", len(entries), len(entries)-start) + "\n")
+	for _, e := range entries[start:] {
+		summary := e.Text
+		if e.Kind == feedback.KindBadAnswer {
+			summary = i18n.T(lang, "feedback.list_bad_answer", e.Answer)
+		}
+		sb.WriteString("\n" + i18n.T(lang, "feedback.list_entry", e.ID, e.CreatedAt.Format("2006-01-02 15:04"), e.UserID, truncate(summary, feedbackSnippetLength)))
+	}
+	sb.WriteString("\n\n" + i18n.T(lang, "feedback.list_footer"))
+	return sb.String()
+}
@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/feedback"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func newFeedbackStore(t *testing.T) feedback.Store {
+	t.Helper()
+	s, err := feedback.NewStore(filepath.Join(t.TempDir(), "feedback.json"))
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+	return s
+}
+
+func TestFeedbackHandler_StoresSnapshot(t *testing.T) {
+	store := newFeedbackStore(t)
+	sessions := &mockSessionManager{messages: []llm.Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}}}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1}, WithFeedbackStore(store))
+
+	bot := &mockBot{}
+	handlers.FeedbackHandler(context.Background(), bot, makeUpdate(1, 10, "/feedback answers are too long"))
+
+	if bot.lastMessageParams == nil || !strings.Contains(bot.lastMessageParams.Text, "Thanks") {
+		t.Errorf("expected confirmation, got %+v", bot.lastMessageParams)
+	}
+
+	entries, _ := store.List()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Text != "answers are too long" || entries[0].ChatID != 10 {
+		t.Errorf("unexpected entry: %+v", entries[0])
+	}
+	if len(entries[0].Conversation) != 2 {
+		t.Errorf("expected conversation snapshot, got %v", entries[0].Conversation)
+	}
+}
+
+func TestFeedbackHandler_Usage(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithFeedbackStore(newFeedbackStore(t)))
+
+	bot := &mockBot{}
+	handlers.FeedbackHandler(context.Background(), bot, makeUpdate(1, 10, "/feedback"))
+
+	if bot.lastMessageParams == nil || !strings.Contains(bot.lastMessageParams.Text, "Usage") {
+		t.Errorf("expected usage message, got %+v", bot.lastMessageParams)
+	}
+}
+
+func TestChat_AttachesReportButton(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{response: "answer"}, &mockSessionManager{}, []int64{1}, WithFeedbackStore(newFeedbackStore(t)))
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 10, "question"))
+
+	markup, ok := bot.lastMessageParams.ReplyMarkup.(*models.InlineKeyboardMarkup)
+	if !ok || markup.InlineKeyboard[0][0].CallbackData != badAnswerCallback {
+		t.Errorf("expected report button, got %+v", bot.lastMessageParams.ReplyMarkup)
+	}
+}
+
+func TestFeedbackCallbackHandler_ReportsBadAnswer(t *testing.T) {
+	store := newFeedbackStore(t)
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithFeedbackStore(store))
+
+	update := makeCallbackUpdate(1, badAnswerCallback)
+	update.CallbackQuery.Message.Message.Text = "a wrong answer"
+	bot := &mockBot{}
+	handlers.FeedbackCallbackHandler(context.Background(), bot, update)
+
+	if bot.lastCallback == nil || !strings.Contains(bot.lastCallback.Text, "reported") {
+		t.Errorf("expected callback answer, got %+v", bot.lastCallback)
+	}
+	entries, _ := store.List()
+	if len(entries) != 1 || entries[0].Kind != feedback.KindBadAnswer || entries[0].Answer != "a wrong answer" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestChat_AttachesRatingButtons(t *testing.T) {
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(&mockRouter{response: "answer"}, sessions, []int64{1}, WithFeedbackStore(newFeedbackStore(t)), WithFeedbackButtons(true))
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 10, "question"))
+
+	markup, ok := bot.lastMessageParams.ReplyMarkup.(*models.InlineKeyboardMarkup)
+	if !ok || len(markup.InlineKeyboard) != 2 {
+		t.Fatalf("expected rating and report rows, got %+v", bot.lastMessageParams.ReplyMarkup)
+	}
+	id := sessions.saved[1].ID
+	row := markup.InlineKeyboard[0]
+	if row[0].CallbackData != rateUpCallback+id || row[1].CallbackData != rateDownCallback+id {
+		t.Errorf("expected rating buttons for answer %s, got %+v", id, row)
+	}
+}
+
+func TestFeedbackCallbackHandler_RatesAnswer(t *testing.T) {
+	store := newFeedbackStore(t)
+	sessions := &mockSessionManager{messages: []llm.Message{
+		{Role: "user", Content: "earlier"},
+		{Role: "assistant", Content: "earlier answer", ID: "a1"},
+		{Role: "user", Content: "question"},
+		{Role: "assistant", Content: "answer", ID: "a2"},
+	}}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1}, WithFeedbackStore(store), WithFeedbackButtons(true))
+
+	bot := &mockBot{}
+	handlers.FeedbackCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, rateDownCallback+"a2"))
+
+	if bot.lastCallback == nil || bot.lastCallback.Text != "Thanks for rating this answer!" {
+		t.Errorf("unexpected callback answer %+v", bot.lastCallback)
+	}
+	if sessions.saved[3].Rating != -1 {
+		t.Errorf("expected the rating on the stored answer, got %+v", sessions.saved[3])
+	}
+	entries, _ := store.List()
+	if len(entries) != 1 || entries[0].Kind != feedback.KindRating || entries[0].Score != -1 || entries[0].MessageID != "a2" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if c := entries[0].Conversation; len(c) != 2 || c[0].Content != "question" || c[1].Content != "answer" {
+		t.Errorf("expected the rated exchange, got %+v", c)
+	}
+
+	handlers.AdminHandler(context.Background(), bot, makeUpdate(1, 1, "/admin stats"))
+	if bot.lastMessageParams.Text != "Answer ratings: 👍 0, 👎 1 (0% positive)" {
+		t.Errorf("unexpected stats %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestFeedbackListHandler(t *testing.T) {
+	store := newFeedbackStore(t)
+	store.Add(feedback.Entry{UserID: 1, Text: "nice"})
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100}), WithFeedbackStore(store))
+
+	bot := &mockBot{}
+	handlers.FeedbackListHandler(context.Background(), bot, makeUpdate(1, 1, "/feedbacks"))
+	if !strings.Contains(bot.lastMessageParams.Text, "Only admins") {
+		t.Errorf("expected non-admin to be rejected, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.FeedbackListHandler(context.Background(), bot, makeUpdate(100, 100, "/feedbacks"))
+	if !strings.Contains(bot.lastMessageParams.Text, "#1") || !strings.Contains(bot.lastMessageParams.Text, "nice") {
+		t.Errorf("expected feedback listing, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.FeedbackListHandler(context.Background(), bot, makeUpdate(100, 100, "/feedbacks export"))
+	if bot.lastDocument == nil {
+		t.Fatal("expected export document to be sent")
+	}
+	if doc, ok := bot.lastDocument.Document.(*models.InputFileUpload); !ok || doc.Filename != "feedback.json" {
+		t.Errorf("unexpected document: %+v", bot.lastDocument.Document)
+	}
+}
@@ -0,0 +1,271 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/feeds"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	feedSummaryInstructions = "Summarize this item from a news feed in two or three sentences for a chat message. " +
+		"Write in the language of the item and do not add anything it does not say."
+	// maxFeedItems caps how many items of one feed are posted per check, so
+	// a feed that republishes its archive does not flood the chat. The rest
+	// are marked as seen.
+	maxFeedItems     = 5
+	feedExcerptLimit = 300
+)
+
+type feedWatch struct {
+	store     feeds.Store
+	client    *http.Client
+	summarize bool
+	provider  string
+	// polling keeps a slow check from overlapping the next one.
+	polling sync.Mutex
+}
+
+// WithFeeds enables /watch. New items are summarized with provider, or the
+// default one, when summarize is set.
+func WithFeeds(store feeds.Store, summarize bool, provider string) Option {
+	return func(h *Handlers) {
+		h.feeds = &feedWatch{store: store, client: feeds.NewClient(), summarize: summarize, provider: provider}
+	}
+}
+
+// WatchHandler subscribes the chat to a feed, or lists its subscriptions
+// when no URL is given.
+func (h *Handlers) WatchHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	chatID := update.Message.Chat.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	if h.feeds == nil {
+		reply(h.tr(user, "watch.disabled"))
+		return
+	}
+
+	arg := commandArgs(update.Message.Text)
+	if arg == "" {
+		h.listFeeds(user, chatID, reply)
+		return
+	}
+	u, err := url.Parse(arg)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		reply(h.tr(user, "watch.invalid_url"))
+		return
+	}
+
+	feed, err := feeds.Fetch(ctx, h.feeds.client, u.String())
+	if err != nil {
+		log.Printf("Failed to fetch feed %s for user %d: %v", u, user.ID, err)
+		reply(h.tr(user, "watch.fetch_error"))
+		return
+	}
+
+	// Items already in the feed are not posted, only ones that appear later.
+	seen := make([]string, 0, len(feed.Items))
+	for i := len(feed.Items) - 1; i >= 0; i-- {
+		seen = append(seen, feed.Items[i].ID)
+	}
+	title := feed.Title
+	if title == "" {
+		title = u.String()
+	}
+
+	switch err := h.feeds.store.Add(feeds.Subscription{ChatID: chatID, UserID: user.ID, URL: u.String(), Title: title, Seen: seen}); {
+	case err == nil:
+		reply(h.tr(user, "watch.done", title))
+	case errors.Is(err, feeds.ErrDuplicate):
+		reply(h.tr(user, "watch.duplicate"))
+	case errors.Is(err, feeds.ErrLimit):
+		reply(h.tr(user, "watch.limit", feeds.MaxPerUser))
+	default:
+		log.Printf("Failed to save feed for user %d: %v", user.ID, err)
+		reply(h.tr(user, "watch.error"))
+	}
+}
+
+func (h *Handlers) listFeeds(user *models.User, chatID int64, reply func(string)) {
+	subs, err := h.feeds.store.List(chatID)
+	if err != nil {
+		log.Printf("Failed to load feeds for chat %d: %v", chatID, err)
+		reply(h.tr(user, "watch.error"))
+		return
+	}
+	if len(subs) == 0 {
+		reply(h.tr(user, "watch.none"))
+		return
+	}
+
+	lines := []string{h.tr(user, "watch.header")}
+	for i, sub := range subs {
+		lines = append(lines, fmt.Sprintf("%d. %s\n   %s", i+1, sub.Title, sub.URL))
+	}
+	lines = append(lines, "", h.tr(user, "watch.footer"))
+	reply(strings.Join(lines, "\n"))
+}
+
+// UnwatchHandler removes a subscription by its number in /watch.
+func (h *Handlers) UnwatchHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	if h.feeds == nil {
+		reply(h.tr(user, "watch.disabled"))
+		return
+	}
+	n, err := strconv.Atoi(commandArgs(update.Message.Text))
+	if err != nil {
+		reply(h.tr(user, "unwatch.usage"))
+		return
+	}
+
+	removed, err := h.feeds.store.Remove(update.Message.Chat.ID, n-1)
+	if errors.Is(err, feeds.ErrNotFound) {
+		reply(h.tr(user, "unwatch.unknown"))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to remove feed for user %d: %v", user.ID, err)
+		reply(h.tr(user, "watch.error"))
+		return
+	}
+	reply(h.tr(user, "unwatch.done", removed.Title))
+}
+
+// RunFeeds checks every watched feed and posts new items to the chats
+// watching it. Each feed is fetched once however many chats watch it.
+func (h *Handlers) RunFeeds(ctx context.Context, b any) {
+	sender := resolveSender(b)
+	if sender == nil || h.feeds == nil {
+		return
+	}
+	if !h.feeds.polling.TryLock() {
+		log.Printf("Feeds: previous check still running, skipping")
+		return
+	}
+	defer h.feeds.polling.Unlock()
+
+	subs, err := h.feeds.store.All()
+	if err != nil {
+		log.Printf("Feeds: %v", err)
+		return
+	}
+
+	fetched := make(map[string]*feeds.Feed)
+	for _, sub := range subs {
+		feed, ok := fetched[sub.URL]
+		if !ok {
+			f, err := feeds.Fetch(ctx, h.feeds.client, sub.URL)
+			if err != nil {
+				log.Printf("Feeds: %s: %v", sub.URL, err)
+			} else {
+				feed = &f
+			}
+			fetched[sub.URL] = feed
+		}
+		if feed == nil {
+			continue
+		}
+		h.postNewItems(ctx, sender, sub, feed.Items)
+	}
+}
+
+func (h *Handlers) postNewItems(ctx context.Context, sender BotSender, sub feeds.Subscription, items []feeds.Item) {
+	seen := make(map[string]bool, len(sub.Seen))
+	for _, id := range sub.Seen {
+		seen[id] = true
+	}
+	// Feeds list the newest items first; they are posted oldest first.
+	var fresh []feeds.Item
+	for i := len(items) - 1; i >= 0; i-- {
+		if !seen[items[i].ID] {
+			seen[items[i].ID] = true
+			fresh = append(fresh, items[i])
+		}
+	}
+	if len(fresh) == 0 {
+		return
+	}
+
+	posted := fresh
+	if len(posted) > maxFeedItems {
+		posted = posted[len(posted)-maxFeedItems:]
+	}
+	for _, item := range posted {
+		if err := h.deliver(ctx, sender, sub.ChatID, h.feedItemText(ctx, sub, item)); err != nil {
+			log.Printf("Failed to post feed item to chat %d: %v", sub.ChatID, err)
+		}
+	}
+
+	ids := append([]string(nil), sub.Seen...)
+	for _, item := range fresh {
+		ids = append(ids, item.ID)
+	}
+	if err := h.feeds.store.SetSeen(sub.ChatID, sub.URL, ids); err != nil {
+		log.Printf("Feeds: %v", err)
+	}
+}
+
+func (h *Handlers) feedItemText(ctx context.Context, sub feeds.Subscription, item feeds.Item) string {
+	body := truncate(item.Summary, feedExcerptLimit)
+	if h.feeds.summarize && item.Summary != "" {
+		opts := h.requestOptions(sub.UserID)
+		if h.feeds.provider != "" {
+			opts = append(opts, llm.WithProvider(h.feeds.provider))
+		}
+		summary, err := h.router.SendMessage(ctx, []llm.Message{
+			{Role: "system", Content: feedSummaryInstructions},
+			{Role: "user", Content: item.Title + "\n\n" + item.Summary},
+		}, opts...)
+		if err != nil {
+			log.Printf("Failed to summarize feed item %s: %v", item.Link, err)
+		} else if strings.TrimSpace(summary) != "" {
+			body = strings.TrimSpace(summary)
+		}
+	}
+
+	parts := []string{"📰 " + sub.Title}
+	if item.Title != "" {
+		parts = append(parts, item.Title)
+	}
+	if body != "" && body != item.Title {
+		parts = append(parts, body)
+	}
+	if item.Link != "" {
+		parts = append(parts, item.Link)
+	}
+	return strings.Join(parts, "\n\n")
+}
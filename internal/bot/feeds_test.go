@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/feeds"
+)
+
+type testFeed struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (f *testFeed) add(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items = append([]string{id}, f.items...)
+}
+
+func (f *testFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fmt.Fprint(w, `<rss version="2.0"><channel><title>Test feed</title>`)
+	for _, id := range f.items {
+		fmt.Fprintf(w, `<item><guid>%s</guid><title>Post %s</title><link>https://example.com/%s</link><description>About %s</description></item>`, id, id, id, id)
+	}
+	fmt.Fprint(w, `</channel></rss>`)
+}
+
+func TestFeedHandlers(t *testing.T) {
+	feed := &testFeed{items: []string{"1"}}
+	server := httptest.NewServer(feed)
+	defer server.Close()
+
+	store, err := feeds.NewStore(filepath.Join(t.TempDir(), "feeds.json"))
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+	handlers := NewHandlers(&mockRouter{}, &historySessions{}, []int64{1}, WithFeeds(store, false, ""))
+	// The test feed is on loopback, which the real client refuses.
+	handlers.feeds.client = server.Client()
+	bot := &mockBot{}
+	ctx := context.Background()
+
+	handlers.WatchHandler(ctx, bot, makeUpdate(1, 1, "/watch ftp://example.com/feed"))
+	if bot.lastMessageParams.Text != "Send a feed address starting with http:// or https://." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.WatchHandler(ctx, bot, makeUpdate(1, 1, "/watch "+server.URL))
+	if bot.lastMessageParams.Text != "Watching Test feed. New items will be posted here." {
+		t.Fatalf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+	handlers.WatchHandler(ctx, bot, makeUpdate(1, 1, "/watch"))
+	if !strings.Contains(bot.lastMessageParams.Text, "1. Test feed\n   "+server.URL) {
+		t.Errorf("unexpected feed list %q", bot.lastMessageParams.Text)
+	}
+
+	// Items already in the feed when it was watched are not posted.
+	bot.sentMessages = nil
+	handlers.RunFeeds(ctx, bot)
+	if len(bot.sentMessages) != 0 {
+		t.Fatalf("expected no posts, got %d", len(bot.sentMessages))
+	}
+
+	feed.add("2")
+	feed.add("3")
+	handlers.RunFeeds(ctx, bot)
+	if len(bot.sentMessages) != 2 {
+		t.Fatalf("expected 2 posts, got %d", len(bot.sentMessages))
+	}
+	if !strings.Contains(bot.sentMessages[0].Text, "Post 2") || !strings.Contains(bot.sentMessages[1].Text, "Post 3") {
+		t.Errorf("expected new items oldest first, got %q and %q", bot.sentMessages[0].Text, bot.sentMessages[1].Text)
+	}
+
+	handlers.RunFeeds(ctx, bot)
+	if len(bot.sentMessages) != 2 {
+		t.Errorf("expected items to be posted once, got %d posts", len(bot.sentMessages))
+	}
+
+	handlers.UnwatchHandler(ctx, bot, makeUpdate(1, 1, "/unwatch 1"))
+	if bot.lastMessageParams.Text != "Stopped watching Test feed." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestFeedItemText_Summarize(t *testing.T) {
+	router := &mockRouter{response: "A short summary."}
+	handlers := NewHandlers(router, &historySessions{}, []int64{1}, WithFeeds(nil, true, ""))
+
+	text := handlers.feedItemText(context.Background(), feeds.Subscription{UserID: 1, Title: "News"}, feeds.Item{Title: "Headline", Link: "https://example.com/a", Summary: "A long article."})
+	if text != "📰 News\n\nHeadline\n\nA short summary.\n\nhttps://example.com/a" {
+		t.Errorf("unexpected item text %q", text)
+	}
+	if len(router.lastMessages) != 2 || !strings.Contains(router.lastMessages[1].Content, "A long article.") {
+		t.Errorf("expected the item to be sent for summarizing, got %+v", router.lastMessages)
+	}
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+)
+
+const defaultFloodRetries = 5
+
+type FloodControl struct {
+	client      tgbot.HttpClient
+	maxRetries  int
+	mu          sync.Mutex
+	pausedUntil time.Time
+	now         func() time.Time
+	wait        func(ctx context.Context, d time.Duration) error
+}
+
+func NewFloodControl(client tgbot.HttpClient, maxRetries int) *FloodControl {
+	if maxRetries <= 0 {
+		maxRetries = defaultFloodRetries
+	}
+	return &FloodControl{
+		client:     client,
+		maxRetries: maxRetries,
+		now:        time.Now,
+		wait:       sleepContext,
+	}
+}
+
+func (f *FloodControl) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	for attempt := 0; ; attempt++ {
+		if d := f.pauseRemaining(); d > 0 {
+			if err := f.wait(req.Context(), d); err != nil {
+				return nil, err
+			}
+		}
+
+		r := req.Clone(req.Context())
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+
+		resp, err := f.client.Do(r)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= f.maxRetries {
+			return resp, err
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		retryAfter := parseRetryAfter(data)
+		f.pause(retryAfter)
+		log.Printf("Telegram rate limit hit, retrying in %s (attempt %d/%d)", retryAfter, attempt+1, f.maxRetries)
+	}
+}
+
+func (f *FloodControl) pause(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	until := f.now().Add(d)
+	if until.After(f.pausedUntil) {
+		f.pausedUntil = until
+	}
+}
+
+func (f *FloodControl) pauseRemaining() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pausedUntil.Sub(f.now())
+}
+
+func parseRetryAfter(body []byte) time.Duration {
+	var resp struct {
+		Parameters struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Parameters.RetryAfter <= 0 {
+		return time.Second
+	}
+	return time.Duration(resp.Parameters.RetryAfter) * time.Second
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
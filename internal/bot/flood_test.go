@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scriptedClient struct {
+	responses []*http.Response
+	bodies    []string
+}
+
+func (c *scriptedClient) Do(req *http.Request) (*http.Response, error) {
+	data, _ := io.ReadAll(req.Body)
+	c.bodies = append(c.bodies, string(data))
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, nil
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func newTestFloodControl(client *scriptedClient, retries int) (*FloodControl, *[]time.Duration) {
+	f := NewFloodControl(client, retries)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+	var waits []time.Duration
+	f.wait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		now = now.Add(d)
+		return nil
+	}
+	return f, &waits
+}
+
+func TestFloodControl_RetriesAfterRateLimit(t *testing.T) {
+	client := &scriptedClient{responses: []*http.Response{
+		jsonResponse(http.StatusTooManyRequests, `{"ok":false,"error_code":429,"parameters":{"retry_after":3}}`),
+		jsonResponse(http.StatusOK, `{"ok":true,"result":true}`),
+	}}
+	f, waits := newTestFloodControl(client, 2)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/sendMessage", strings.NewReader("payload"))
+	resp, err := f.Do(req)
+	if err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected retried request to succeed, got %d", resp.StatusCode)
+	}
+	if len(client.bodies) != 2 || client.bodies[1] != "payload" {
+		t.Errorf("expected body to be replayed, got %v", client.bodies)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 3*time.Second {
+		t.Errorf("expected to honor retry_after, got %v", *waits)
+	}
+}
+
+func TestFloodControl_GivesUpAfterMaxRetries(t *testing.T) {
+	limited := `{"ok":false,"error_code":429,"parameters":{"retry_after":1}}`
+	client := &scriptedClient{responses: []*http.Response{
+		jsonResponse(http.StatusTooManyRequests, limited),
+		jsonResponse(http.StatusTooManyRequests, limited),
+	}}
+	f, _ := newTestFloodControl(client, 1)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/sendMessage", strings.NewReader("payload"))
+	resp, err := f.Do(req)
+	if err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected final 429 to be returned, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != limited {
+		t.Errorf("expected response body to be readable, got %q", body)
+	}
+}
+
+func TestFloodControl_PausesOtherRequests(t *testing.T) {
+	client := &scriptedClient{responses: []*http.Response{jsonResponse(http.StatusOK, `{"ok":true}`)}}
+	f, waits := newTestFloodControl(client, 1)
+	f.pause(5 * time.Second)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/editMessageText", nil)
+	if _, err := f.Do(req); err != nil {
+		t.Fatalf("Do() returned error: %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 5*time.Second {
+		t.Errorf("expected request to wait for active pause, got %v", *waits)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter([]byte(`{"parameters":{"retry_after":7}}`)); d != 7*time.Second {
+		t.Errorf("expected 7s, got %s", d)
+	}
+	if d := parseRetryAfter([]byte(`not json`)); d != time.Second {
+		t.Errorf("expected 1s fallback, got %s", d)
+	}
+}
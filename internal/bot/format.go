@@ -0,0 +1,228 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const markdownV2Special = "_*[]()~`>#+-=|{}.!\\"
+
+var (
+	headerPattern  = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	bulletPattern  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	numberPattern  = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	quotePattern   = regexp.MustCompile(`^>\s?(.*)$`)
+	codeLangFilter = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+func (h *Handlers) sendReply(ctx context.Context, sender BotSender, params *tgbot.SendMessageParams) (*models.Message, error) {
+	chunks := splitMessage(params.Text, maxMessageLength)
+
+	var last *models.Message
+	for i, chunk := range chunks {
+		part := *params
+		part.Text = chunk
+		if i > 0 {
+			part.ReplyParameters = nil
+		}
+		if i < len(chunks)-1 {
+			part.ReplyMarkup = nil
+		}
+
+		msg, err := h.sendFormatted(ctx, sender, &part)
+		if err != nil {
+			return msg, err
+		}
+		last = msg
+	}
+
+	return last, nil
+}
+
+func (h *Handlers) sendFormatted(ctx context.Context, sender BotSender, params *tgbot.SendMessageParams) (*models.Message, error) {
+	plain := params.Text
+	params.Text = toMarkdownV2(plain)
+	params.ParseMode = models.ParseModeMarkdown
+
+	msg, err := sender.SendMessage(ctx, params)
+	if err == nil || !errors.Is(err, tgbot.ErrorBadRequest) {
+		return msg, err
+	}
+
+	log.Printf("Telegram rejected formatted reply, falling back to plain text: %v", err)
+	params.Text = plain
+	params.ParseMode = ""
+	return sender.SendMessage(ctx, params)
+}
+
+func toMarkdownV2(text string) string {
+	var out strings.Builder
+	inCode := false
+
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			out.WriteByte('\n')
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				out.WriteString("```")
+			} else {
+				out.WriteString("```" + codeLangFilter.ReplaceAllString(strings.TrimPrefix(trimmed, "```"), ""))
+			}
+			inCode = !inCode
+			continue
+		}
+
+		if inCode {
+			out.WriteString(escapeCode(line))
+			continue
+		}
+		out.WriteString(formatLine(line))
+	}
+
+	if inCode {
+		out.WriteString("\n```")
+	}
+	return out.String()
+}
+
+func formatLine(line string) string {
+	if m := headerPattern.FindStringSubmatch(line); m != nil {
+		title := strings.NewReplacer("**", "", "__", "").Replace(m[1])
+		return "*" + escapeMarkdownV2(title) + "*"
+	}
+	if m := bulletPattern.FindStringSubmatch(line); m != nil {
+		return m[1] + "• " + formatInline(m[2])
+	}
+	if m := numberPattern.FindStringSubmatch(line); m != nil {
+		return m[1] + m[2] + "\\. " + formatInline(m[3])
+	}
+	if m := quotePattern.FindStringSubmatch(line); m != nil {
+		return ">" + formatInline(m[1])
+	}
+	return formatInline(line)
+}
+
+func formatInline(s string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end > 0 {
+				out.WriteString("`" + escapeCode(s[i+1:i+1+end]) + "`")
+				i += end + 1
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**") || strings.HasPrefix(s[i:], "__"):
+			marker := s[i : i+2]
+			if end := strings.Index(s[i+2:], marker); end > 0 {
+				out.WriteString("*" + formatInline(s[i+2:i+2+end]) + "*")
+				i += end + 3
+				continue
+			}
+		case strings.HasPrefix(s[i:], "||"):
+			if end := strings.Index(s[i+2:], "||"); end > 0 {
+				out.WriteString("||" + formatInline(s[i+2:i+2+end]) + "||")
+				i += end + 3
+				continue
+			}
+		case strings.HasPrefix(s[i:], "~~"):
+			if end := strings.Index(s[i+2:], "~~"); end > 0 {
+				out.WriteString("~" + formatInline(s[i+2:i+2+end]) + "~")
+				i += end + 3
+				continue
+			}
+		case c == '*' || c == '_':
+			if end, ok := italicEnd(s, i); ok {
+				out.WriteString("_" + formatInline(s[i+1:end]) + "_")
+				i = end
+				continue
+			}
+		case c == '[':
+			if textEnd := strings.Index(s[i:], "]("); textEnd > 1 {
+				rest := s[i+textEnd+2:]
+				if urlEnd := strings.IndexByte(rest, ')'); urlEnd > 0 {
+					out.WriteString("[" + formatInline(s[i+1:i+textEnd]) + "](" + escapeURL(rest[:urlEnd]) + ")")
+					i += textEnd + 2 + urlEnd
+					continue
+				}
+			}
+		}
+
+		if strings.IndexByte(markdownV2Special, c) >= 0 {
+			out.WriteByte('\\')
+		}
+		out.WriteByte(c)
+	}
+
+	return out.String()
+}
+
+func italicEnd(s string, start int) (int, bool) {
+	marker := s[start]
+	if start+1 >= len(s) || s[start+1] == ' ' || s[start+1] == marker {
+		return 0, false
+	}
+	if marker == '_' && start > 0 && isWordByte(s[start-1]) {
+		return 0, false
+	}
+
+	for j := start + 1; j < len(s); j++ {
+		if s[j] != marker || s[j-1] == ' ' {
+			continue
+		}
+		if marker == '_' && j+1 < len(s) && isWordByte(s[j+1]) {
+			continue
+		}
+		return j, true
+	}
+	return 0, false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func escapeMarkdownV2(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(markdownV2Special, s[i]) >= 0 {
+			out.WriteByte('\\')
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
+}
+
+func escapeCode(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(s)
+}
+
+func escapeURL(s string) string {
+	return strings.NewReplacer("\\", "\\\\", ")", "\\)").Replace(s)
+}
+
+// thinkSpoiler formats the <think> reasoning of a provider set to
+// think: spoiler as spoilers to send above the answer.
+func thinkSpoiler(reasoning string) string {
+	if reasoning == "" {
+		return ""
+	}
+	var sb strings.Builder
+	for _, line := range strings.Split(reasoning, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			sb.WriteString("||" + strings.ReplaceAll(line, "||", "") + "||\n")
+		}
+	}
+	return sb.String() + "\n"
+}
@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type rejectingBot struct {
+	mockBot
+	attempts []models.ParseMode
+}
+
+func (r *rejectingBot) SendMessage(ctx context.Context, params *tgbot.SendMessageParams) (*models.Message, error) {
+	r.attempts = append(r.attempts, params.ParseMode)
+	if params.ParseMode != "" {
+		return nil, fmt.Errorf("%w, can't parse entities", tgbot.ErrorBadRequest)
+	}
+	return r.mockBot.SendMessage(ctx, params)
+}
+
+func TestToMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "Hello world", "Hello world"},
+		{"reserved characters", "1 + 1 = 2. Done!", `1 \+ 1 \= 2\. Done\!`},
+		{"bold", "This is **important**", "This is *important*"},
+		{"italic", "This is *subtle* and _quiet_", "This is _subtle_ and _quiet_"},
+		{"strikethrough", "~~old~~ new", "~old~ new"},
+		{"spoiler", "||hidden.|| shown", `||hidden\.|| shown`},
+		{"snake case", "use my_var_name here", `use my\_var\_name here`},
+		{"unmatched marker", "2 * 3 = 6", `2 \* 3 \= 6`},
+		{"inline code", "run `a_b.c()` now", "run `a_b.c()` now"},
+		{"link", "see [the docs](https://example.com/a_b)", "see [the docs](https://example.com/a_b)"},
+		{"header", "## Step 1.", `*Step 1\.*`},
+		{"bullet", "- first\n* second", "• first\n• second"},
+		{"numbered", "1. first", `1\. first`},
+		{"quote", "> quoted.", `>quoted\.`},
+		{"code block", "```go\nfmt.Println(\"a_b\")\n```", "```go\nfmt.Println(\"a_b\")\n```"},
+		{"unterminated code block", "```\nx := `y`", "```\nx := \\`y\\`\n```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toMarkdownV2(tt.in); got != tt.want {
+				t.Errorf("toMarkdownV2(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendReply_UsesMarkdownV2(t *testing.T) {
+	h := NewHandlers(nil, nil, nil)
+	bot := &mockBot{}
+
+	h.sendReply(context.Background(), bot, &tgbot.SendMessageParams{ChatID: 1, Text: "**Hi**."})
+
+	if bot.lastMessageParams.ParseMode != models.ParseModeMarkdown {
+		t.Errorf("expected MarkdownV2 parse mode, got %q", bot.lastMessageParams.ParseMode)
+	}
+	if bot.lastMessageParams.Text != `*Hi*\.` {
+		t.Errorf("unexpected text %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestSendReply_FallsBackToPlainText(t *testing.T) {
+	h := NewHandlers(nil, nil, nil)
+	bot := &rejectingBot{}
+
+	if _, err := h.sendReply(context.Background(), bot, &tgbot.SendMessageParams{ChatID: 1, Text: "**Hi**."}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bot.attempts) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(bot.attempts))
+	}
+	if bot.lastMessageParams.ParseMode != "" {
+		t.Errorf("expected no parse mode on fallback, got %q", bot.lastMessageParams.ParseMode)
+	}
+	if bot.lastMessageParams.Text != "**Hi**." {
+		t.Errorf("expected original text on fallback, got %q", bot.lastMessageParams.Text)
+	}
+}
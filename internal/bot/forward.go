@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// messageText returns the prompt for a message handled as text: its text or,
+// for media the model is not sent, its caption. Forwarded messages are
+// attributed to their source.
+func messageText(msg *models.Message) string {
+	if text := strings.TrimSpace(msg.Text); text != "" {
+		return attributed(msg, text)
+	}
+
+	caption := strings.TrimSpace(msg.Caption)
+	if caption == "" {
+		return ""
+	}
+	if kind := mediaKind(msg); kind != "" {
+		caption = fmt.Sprintf("[Caption of a %s, which is not included]\n%s", kind, caption)
+	}
+	return attributed(msg, caption)
+}
+
+// attributed prefixes text with the source of a forwarded message.
+func attributed(msg *models.Message, text string) string {
+	if text == "" {
+		return ""
+	}
+	if source := forwardedFrom(msg.ForwardOrigin); source != "" {
+		return fmt.Sprintf("[Forwarded from %s]\n%s", source, text)
+	}
+	return text
+}
+
+func forwardedFrom(origin *models.MessageOrigin) string {
+	if origin == nil {
+		return ""
+	}
+
+	var source, signature string
+	switch {
+	case origin.MessageOriginUser != nil:
+		source = displayName(&origin.MessageOriginUser.SenderUser)
+	case origin.MessageOriginHiddenUser != nil:
+		source = origin.MessageOriginHiddenUser.SenderUserName
+	case origin.MessageOriginChat != nil:
+		source = chatName(origin.MessageOriginChat.SenderChat)
+		if origin.MessageOriginChat.AuthorSignature != nil {
+			signature = *origin.MessageOriginChat.AuthorSignature
+		}
+	case origin.MessageOriginChannel != nil:
+		source = "the channel " + chatName(origin.MessageOriginChannel.Chat)
+		if origin.MessageOriginChannel.AuthorSignature != nil {
+			signature = *origin.MessageOriginChannel.AuthorSignature
+		}
+	}
+	if source == "" {
+		source = "an unknown sender"
+	}
+	if signature != "" {
+		source += " (" + signature + ")"
+	}
+	return source
+}
+
+func chatName(chat models.Chat) string {
+	if chat.Title != "" {
+		return chat.Title
+	}
+	if chat.Username != "" {
+		return "@" + chat.Username
+	}
+	return fmt.Sprintf("chat %d", chat.ID)
+}
+
+func mediaKind(msg *models.Message) string {
+	switch {
+	case msg.Video != nil:
+		return "video"
+	case msg.Animation != nil:
+		return "GIF"
+	case msg.Audio != nil:
+		return "audio file"
+	case msg.Voice != nil:
+		return "voice message"
+	case msg.Document != nil:
+		return "file"
+	case len(msg.Photo) > 0:
+		return "photo"
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+)
+
+func TestMessageText(t *testing.T) {
+	signature := "Jane"
+	tests := []struct {
+		name string
+		msg  *models.Message
+		want string
+	}{
+		{name: "plain text", msg: &models.Message{Text: "hello"}, want: "hello"},
+		{name: "sticker", msg: &models.Message{Sticker: &models.Sticker{}}, want: ""},
+		{name: "video caption", msg: &models.Message{Video: &models.Video{}, Caption: "what is this?"},
+			want: "[Caption of a video, which is not included]\nwhat is this?"},
+		{name: "forwarded from user", msg: &models.Message{Text: "see you at 5", ForwardOrigin: &models.MessageOrigin{
+			MessageOriginUser: &models.MessageOriginUser{SenderUser: models.User{FirstName: "Bob"}},
+		}}, want: "[Forwarded from Bob]\nsee you at 5"},
+		{name: "forwarded from hidden user", msg: &models.Message{Text: "hi", ForwardOrigin: &models.MessageOrigin{
+			MessageOriginHiddenUser: &models.MessageOriginHiddenUser{SenderUserName: "Alice"},
+		}}, want: "[Forwarded from Alice]\nhi"},
+		{name: "forwarded channel post", msg: &models.Message{Caption: "big news", Video: &models.Video{}, ForwardOrigin: &models.MessageOrigin{
+			MessageOriginChannel: &models.MessageOriginChannel{Chat: models.Chat{Title: "Daily News"}, AuthorSignature: &signature},
+		}}, want: "[Forwarded from the channel Daily News (Jane)]\n[Caption of a video, which is not included]\nbig news"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageText(tt.msg); got != tt.want {
+				t.Errorf("messageText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextMessageHandler_UsesCaption(t *testing.T) {
+	router := &mockRouter{response: "ok"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+
+	update := makeUpdate(1, 1, "")
+	update.Message.Animation = &models.Animation{}
+	update.Message.Caption = "why is this funny?"
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, update)
+
+	last := router.lastMessages[len(router.lastMessages)-1]
+	if last.Content != "[Caption of a GIF, which is not included]\nwhy is this funny?" {
+		t.Errorf("expected the caption as the prompt, got %q", last.Content)
+	}
+
+	router.lastMessages = nil
+	sticker := makeUpdate(1, 1, "")
+	sticker.Message.Sticker = &models.Sticker{}
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, sticker)
+	if router.lastMessages != nil {
+		t.Error("expected messages without text or caption to be ignored")
+	}
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"context"
+	"log"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/groups"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+type ChatMemberGetter interface {
+	GetChatMember(ctx context.Context, params *tgbot.GetChatMemberParams) (*models.ChatMember, error)
+}
+
+func WithGroupStore(s groups.Store) Option {
+	return func(h *Handlers) {
+		h.groupStore = s
+	}
+}
+
+func isGroupChat(chatID int64) bool {
+	return chatID < 0
+}
+
+func (h *Handlers) sharedGroup(chatID int64) bool {
+	return h.groupStore != nil && isGroupChat(chatID) && h.groupStore.Mode(chatID) == groups.ModeShared
+}
+
+// sessionKey returns the conversation a message belongs to: the user's own
+// in private chats, the group's in shared groups, and the user's
+// conversation within the group otherwise.
+func (h *Handlers) sessionKey(chatID, userID int64) int64 {
+	switch {
+	case !isGroupChat(chatID):
+		return userID
+	case h.sharedGroup(chatID):
+		return chatID
+	}
+	return session.UserKey(userID, chatID)
+}
+
+func (h *Handlers) GroupModeHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	if !isGroupChat(chatID) {
+		reply(h.tr(update.Message.From, "groups.only_groups"))
+		return
+	}
+	if h.groupStore == nil {
+		reply(h.tr(update.Message.From, "groups.disabled"))
+		return
+	}
+
+	mode := commandArgs(update.Message.Text)
+	if mode == "" {
+		reply(h.tr(update.Message.From, "groups.status", h.groupStore.Mode(chatID)))
+		return
+	}
+	if !groups.ValidMode(mode) {
+		reply(h.tr(update.Message.From, "groups.unknown_mode"))
+		return
+	}
+
+	userID := update.Message.From.ID
+	if !h.isGroupAdmin(ctx, sender, chatID, userID) {
+		reply(h.tr(update.Message.From, "groups.admins_only"))
+		return
+	}
+
+	if err := h.groupStore.SetMode(chatID, mode); err != nil {
+		log.Printf("Failed to set mode for group %d: %v", chatID, err)
+		reply(h.tr(update.Message.From, "groups.save_error"))
+		return
+	}
+
+	if mode == groups.ModeShared {
+		reply(h.tr(update.Message.From, "groups.shared"))
+	} else {
+		reply(h.tr(update.Message.From, "groups.per_user"))
+	}
+}
+
+func (h *Handlers) isGroupAdmin(ctx context.Context, sender BotSender, chatID, userID int64) bool {
+	if h.isAdmin(userID) {
+		return true
+	}
+
+	getter, ok := sender.(ChatMemberGetter)
+	if !ok {
+		return false
+	}
+
+	member, err := getter.GetChatMember(ctx, &tgbot.GetChatMemberParams{
+		ChatID: chatID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("Failed to look up member %d in group %d: %v", userID, chatID, err)
+		return false
+	}
+
+	return member.Type == models.ChatMemberTypeOwner || member.Type == models.ChatMemberTypeAdministrator
+}
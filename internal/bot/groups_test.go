@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/groups"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+type mockMemberBot struct {
+	mockBot
+	memberType models.ChatMemberType
+}
+
+func (m *mockMemberBot) GetChatMember(ctx context.Context, params *tgbot.GetChatMemberParams) (*models.ChatMember, error) {
+	return &models.ChatMember{Type: m.memberType}, nil
+}
+
+func newGroupStore(t *testing.T, mode string) groups.Store {
+	t.Helper()
+	s, err := groups.NewStore(filepath.Join(t.TempDir(), "groups.json"), mode)
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+	return s
+}
+
+func TestChat_SessionKeyByGroupMode(t *testing.T) {
+	store := newGroupStore(t, groups.ModePerUser)
+	sessions := &mockSessionManager{}
+	router := &mockRouter{response: "ok"}
+	handlers := NewHandlers(router, sessions, []int64{1}, WithGroupStore(store))
+
+	update := makeUpdate(1, -100, "idea")
+	update.Message.From.FirstName = "Ann"
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, update)
+	if sessions.savedID != session.ScopedKey(-100, 1) || sessions.savedID == 1 {
+		t.Errorf("expected a per-user group session key apart from the private chat, got %d", sessions.savedID)
+	}
+	if router.lastMessages[0].Content != "idea" {
+		t.Errorf("expected unprefixed prompt in per_user mode, got %q", router.lastMessages[0].Content)
+	}
+
+	store.SetMode(-100, groups.ModeShared)
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, update)
+	if sessions.savedID != -100 {
+		t.Errorf("expected shared group session key, got %d", sessions.savedID)
+	}
+	if router.lastMessages[0].Content != "Ann: idea" {
+		t.Errorf("expected speaker-prefixed prompt in shared mode, got %q", router.lastMessages[0].Content)
+	}
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "private"))
+	if sessions.savedID != 1 {
+		t.Errorf("expected private chats to use the user key, got %d", sessions.savedID)
+	}
+}
+
+func TestGroupModeHandler(t *testing.T) {
+	store := newGroupStore(t, groups.ModePerUser)
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1, 2}, WithGroupStore(store))
+
+	member := &mockMemberBot{memberType: models.ChatMemberTypeMember}
+	handlers.GroupModeHandler(context.Background(), member, makeUpdate(2, -100, "/groupmode shared"))
+	if !strings.Contains(member.lastMessageParams.Text, "Only group admins") {
+		t.Errorf("expected non-admin to be rejected, got %q", member.lastMessageParams.Text)
+	}
+	if store.Mode(-100) != groups.ModePerUser {
+		t.Error("expected mode to be unchanged")
+	}
+
+	admin := &mockMemberBot{memberType: models.ChatMemberTypeAdministrator}
+	handlers.GroupModeHandler(context.Background(), admin, makeUpdate(1, -100, "/groupmode shared"))
+	if store.Mode(-100) != groups.ModeShared {
+		t.Errorf("expected shared mode, got %q (reply %q)", store.Mode(-100), admin.lastMessageParams.Text)
+	}
+
+	handlers.GroupModeHandler(context.Background(), admin, makeUpdate(1, -100, "/groupmode"))
+	if !strings.Contains(admin.lastMessageParams.Text, "shared") {
+		t.Errorf("expected current mode to be shown, got %q", admin.lastMessageParams.Text)
+	}
+
+	bot := &mockBot{}
+	handlers.GroupModeHandler(context.Background(), bot, makeUpdate(1, 1, "/groupmode shared"))
+	if !strings.Contains(bot.lastMessageParams.Text, "only works in groups") {
+		t.Errorf("expected private chat to be rejected, got %q", bot.lastMessageParams.Text)
+	}
+}
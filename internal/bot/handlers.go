@@ -2,13 +2,27 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	tgbot "github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/batch"
+	"github.com/jrswab/helpi/internal/budget"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/digest"
+	"github.com/jrswab/helpi/internal/facts"
+	"github.com/jrswab/helpi/internal/feedback"
+	"github.com/jrswab/helpi/internal/groups"
+	"github.com/jrswab/helpi/internal/ingest"
 	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/queue"
 	"github.com/jrswab/helpi/internal/session"
+	"github.com/jrswab/helpi/internal/settings"
+	"github.com/jrswab/helpi/internal/webhook"
 )
 
 type BotSender interface {
@@ -16,6 +30,18 @@ type BotSender interface {
 	SendChatAction(ctx context.Context, params *tgbot.SendChatActionParams) (bool, error)
 }
 
+type CallbackAnswerer interface {
+	AnswerCallbackQuery(ctx context.Context, params *tgbot.AnswerCallbackQueryParams) (bool, error)
+}
+
+type MessageEditor interface {
+	EditMessageText(ctx context.Context, params *tgbot.EditMessageTextParams) (*models.Message, error)
+}
+
+type MarkupEditor interface {
+	EditMessageReplyMarkup(ctx context.Context, params *tgbot.EditMessageReplyMarkupParams) (*models.Message, error)
+}
+
 type botAdapter struct {
 	*tgbot.Bot
 }
@@ -29,17 +55,87 @@ func (a *botAdapter) SendChatAction(ctx context.Context, params *tgbot.SendChatA
 }
 
 type Handlers struct {
-	router         llm.Router
-	sessionManager session.Manager
-	allowedUsers   []int64
+	router           llm.Router
+	sessionManager   session.Manager
+	allowedUsers     []int64
+	offlineQueue     queue.Queue
+	batchTracker     batch.Tracker
+	commandRoutes    map[string]config.CommandRouteConfig
+	admins           []int64
+	owners           []int64
+	guests           []int64
+	allowedNames     []string
+	usernames        *UsernameCache
+	titles           config.TitlesConfig
+	postprocessing   []config.PostprocessConfig
+	prefixes         config.PrefixConfig
+	offline          bool
+	accessReporter   *AccessReporter
+	feedbackStore    feedback.Store
+	feedbackButtons  bool
+	translateRoute   config.CommandRouteConfig
+	contextSelector  ContextSelector
+	historyCompactor HistoryCompactor
+	deduplicator     HistoryDeduplicator
+	historyTokens    int
+	documentStore    ingest.Store
+	maxExcerpts      int
+	longTermMemory   LongTermMemory
+	defaultLanguage  string
+	groupStore       groups.Store
+	debouncer        *debouncer
+	budget           *budget.Tracker
+	maxInputTokens   int
+	showRoute        bool
+	showReasoning    bool
+	thinkSpoilers    bool
+	thinkingStatus   bool
+	reactions        config.ReactionsConfig
+	settings         *settings.Store
+	commands         *CommandRegistry
+	generations      generations
+	systemPrompt     string
+	defaultProvider  string
+	botID            int64
+	inlineLimiter    *RateLimitMiddleware
+	inlineQueries    generations
+	inlineDebounce   time.Duration
+	personas         []config.PersonaConfig
+	digestStore      digest.Store
+	digestProvider   string
+	reprocessEdits   bool
+	lastPrompts      lastPrompts
+	sessionLocks     sessionLocks
+	memoryBackend    string
+	runtime          *runtimeStats
+	errorReporter    *ErrorReporter
+	factStore        facts.Store
+	factExtractor    FactExtractor
+	feeds            *feedWatch
+	chatContexts     *chatContexts
+	quiet            *quietHours
+	quoteReplies     string
+	storage          *storageQuota
+	continuation     *continuation
+	webhooks         *webhook.Dispatcher
+	homeAssistant    *homeAssistant
+	authMu           sync.RWMutex
 }
 
-func NewHandlers(router llm.Router, sessionManager session.Manager, allowedUsers []int64) *Handlers {
-	return &Handlers{
+type Option func(*Handlers)
+
+func NewHandlers(router llm.Router, sessionManager session.Manager, allowedUsers []int64, opts ...Option) *Handlers {
+	h := &Handlers{
 		router:         router,
 		sessionManager: sessionManager,
 		allowedUsers:   allowedUsers,
+		runtime:        newRuntimeStats(),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.commands = h.defaultCommands()
+	return h
 }
 
 func (h *Handlers) StartHandler(ctx context.Context, b any, update *models.Update) {
@@ -53,12 +149,9 @@ func (h *Handlers) StartHandler(ctx context.Context, b any, update *models.Updat
 	if sender == nil {
 		return
 	}
-	if !h.checkAuth(update) {
-		return
-	}
 	sender.SendMessage(ctx, &tgbot.SendMessageParams{
 		ChatID: update.Message.Chat.ID,
-		Text:   "Welcome to Helpi! I'm here to help you interact with AI models.\n\nAvailable commands:\n/start - Show this welcome message\n/help - Get detailed help\n/myid - Get your Telegram ID\n/model - Show current model info\n/clear - Clear your conversation history\n\nJust send me a message and I'll respond using the configured AI provider.",
+		Text:   h.tr(update.Message.From, "start.welcome"),
 	})
 }
 
@@ -73,23 +166,9 @@ func (h *Handlers) HelpHandler(ctx context.Context, b any, update *models.Update
 	if sender == nil {
 		return
 	}
-	if !h.checkAuth(update) {
-		return
-	}
 	sender.SendMessage(ctx, &tgbot.SendMessageParams{
 		ChatID: update.Message.Chat.ID,
-		Text: `Available commands:
-
-/start - Welcome message
-/help - Show this help message
-/myid - Get your Telegram user ID
-/model - Display current active provider and all available providers
-/clear - Clear your conversation history
-
-How it works:
-- Send me any message and I'll forward it to the AI
-- Your conversation history is preserved between messages
-- Use /clear to start a fresh conversation`,
+		Text:   h.helpText(update.Message.From),
 	})
 }
 
@@ -104,17 +183,14 @@ func (h *Handlers) MyIDHandler(ctx context.Context, b any, update *models.Update
 	if sender == nil {
 		return
 	}
-	if !h.checkAuth(update) {
-		return
-	}
 	sender.SendMessage(ctx, &tgbot.SendMessageParams{
 		ChatID:    update.Message.Chat.ID,
-		Text:      fmt.Sprintf("Your Telegram ID: `%d`", update.Message.From.ID),
+		Text:      h.tr(update.Message.From, "myid.text", update.Message.From.ID),
 		ParseMode: models.ParseModeMarkdown,
 	})
 }
 
-func (h *Handlers) ModelHandler(ctx context.Context, b any, update *models.Update) {
+func (h *Handlers) ClearHandler(ctx context.Context, b any, update *models.Update) {
 	var sender BotSender
 	switch v := b.(type) {
 	case *tgbot.Bot:
@@ -125,24 +201,22 @@ func (h *Handlers) ModelHandler(ctx context.Context, b any, update *models.Updat
 	if sender == nil {
 		return
 	}
-	if !h.checkAuth(update) {
-		return
-	}
-	provider, err := h.router.GetProvider()
+	userID := update.Message.From.ID
+	err := h.sessionManager.Delete(h.sessionKey(update.Message.Chat.ID, userID))
 	if err != nil {
 		sender.SendMessage(ctx, &tgbot.SendMessageParams{
 			ChatID: update.Message.Chat.ID,
-			Text:   "Error: No LLM provider enabled",
+			Text:   h.tr(update.Message.From, "clear.error", err),
 		})
 		return
 	}
 	sender.SendMessage(ctx, &tgbot.SendMessageParams{
 		ChatID: update.Message.Chat.ID,
-		Text:   fmt.Sprintf("Active provider: %s", provider.Name()),
+		Text:   h.tr(update.Message.From, "clear.done"),
 	})
 }
 
-func (h *Handlers) ClearHandler(ctx context.Context, b any, update *models.Update) {
+func (h *Handlers) TextMessageHandler(ctx context.Context, b any, update *models.Update) {
 	var sender BotSender
 	switch v := b.(type) {
 	case *tgbot.Bot:
@@ -153,71 +227,133 @@ func (h *Handlers) ClearHandler(ctx context.Context, b any, update *models.Updat
 	if sender == nil {
 		return
 	}
-	if !h.checkAuth(update) {
-		return
-	}
-	userID := update.Message.From.ID
-	err := h.sessionManager.Delete(userID)
-	if err != nil {
-		sender.SendMessage(ctx, &tgbot.SendMessageParams{
-			ChatID: update.Message.Chat.ID,
-			Text:   fmt.Sprintf("Error clearing session: %v", err),
-		})
-		return
-	}
-	sender.SendMessage(ctx, &tgbot.SendMessageParams{
-		ChatID: update.Message.Chat.ID,
-		Text:   "Conversation history cleared.",
-	})
-}
 
-func (h *Handlers) TextMessageHandler(ctx context.Context, b any, update *models.Update) {
-	var sender BotSender
-	switch v := b.(type) {
-	case *tgbot.Bot:
-		sender = &botAdapter{Bot: v}
-	case BotSender:
-		sender = v
+	text := messageText(update.Message)
+	if text == "" {
+		return
 	}
-	if sender == nil {
+	if rest, opts, ok := h.providerPrefix(text); ok {
+		h.chat(ctx, sender, update, rest, opts...)
 		return
 	}
-	if !h.checkAuth(update) {
+	if h.debouncer != nil {
+		h.debounce(ctx, sender, update, text)
 		return
 	}
+	h.chat(ctx, sender, update, text)
+}
+
+func (h *Handlers) chat(ctx context.Context, sender BotSender, update *models.Update, text string, opts ...llm.RequestOption) {
+	h.ask(ctx, sender, update, llm.Message{Role: "user", Content: text}, opts...)
+}
 
+func (h *Handlers) ask(ctx context.Context, sender BotSender, update *models.Update, prompt llm.Message, opts ...llm.RequestOption) {
 	userID := update.Message.From.ID
 	chatID := update.Message.Chat.ID
 
-	sender.SendChatAction(ctx, &tgbot.SendChatActionParams{
-		ChatID: chatID,
-		Action: models.ChatActionTyping,
-	})
+	ctx, done := h.generate(ctx, sender, userID, chatID)
+	defer done()
 
-	messages, err := h.sessionManager.Get(userID)
+	if refusal := h.budgetRefusal(update.Message.From); refusal != "" {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   refusal,
+		})
+		return
+	}
+	h.react(ctx, sender, update.Message, h.reactions.Processing)
+
+	key := h.sessionKey(chatID, userID)
+	unlock, err := h.lockSession(ctx, key)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	var messages []llm.Message
+	if err == nil {
+		defer unlock()
+		messages, err = h.sessionManager.Get(key)
+	}
 	if err != nil {
+		h.react(ctx, sender, update.Message, h.reactions.Error)
 		sender.SendMessage(ctx, &tgbot.SendMessageParams{
 			ChatID: chatID,
-			Text:   "Error loading conversation history",
+			Text:   h.tr(update.Message.From, "chat.history_error"),
 		})
 		return
 	}
 
-	messages = append(messages, llm.Message{
-		Role:    "user",
-		Content: update.Message.Text,
-	})
+	if h.sharedGroup(chatID) {
+		prompt.Content = fmt.Sprintf("%s: %s", displayName(update.Message.From), prompt.Content)
+	}
 
-	response, err := h.router.SendMessage(ctx, messages)
+	h.refreshChatContext(ctx, sender, chatID)
+	request := withReplyContext(update.Message, h.buildRequest(ctx, chatID, userID, messages, prompt))
+	messages = append(messages, llm.Stamped(historyMessage(prompt)))
+
+	opts = append(h.chatOptions(chatID, userID), opts...)
+	var route llm.Route
+	opts = append(opts, llm.WithRouteReport(func(r llm.Route) { route = r }))
+	var thought string
+	if h.thinkSpoilers {
+		opts = append(opts, llm.WithThinkReport(func(s string) {
+			if thought == "" {
+				thought = s
+			}
+		}))
+	}
+	var reasoning string
+	if h.showReasoning {
+		opts = append(opts, llm.WithReasoningReport(func(s string) { reasoning = s }))
+	}
+	if h.thinkingStatus {
+		thinking := h.newThinkingStatus(ctx, sender, update.Message)
+		defer thinking.finish()
+		opts = append(opts, llm.WithThinkingReport(thinking.start))
+	}
+	start := time.Now()
+	response, truncated, err := h.sendContinued(ctx, request, opts)
+	if llm.Classify(err) == llm.ErrorContextLength {
+		history := messages[:len(messages)-1]
+		if shrunk, ok := h.shrinkHistory(ctx, userID, history); ok {
+			log.Printf("Context window exceeded for user %d, retrying with %d of %d history messages", userID, len(shrunk), len(history))
+			request = withReplyContext(update.Message, h.buildRequest(ctx, chatID, userID, shrunk, prompt))
+			messages = append(shrunk, llm.Stamped(historyMessage(prompt)))
+			response, truncated, err = h.sendContinued(ctx, request, opts)
+		}
+	}
+	latency := time.Since(start)
 	if err != nil {
-		errMsg := "Error communicating with AI"
+		class := llm.Classify(err)
+		errMsg := h.tr(update.Message.From, "chat.error")
 		if contains(err.Error(), "no LLM provider enabled") {
-			errMsg = "No LLM provider enabled. Please check configuration."
+			errMsg = h.tr(update.Message.From, "chat.no_provider")
 		} else if contains(err.Error(), "timeout") || contains(err.Error(), "context deadline") {
-			errMsg = "Request timed out. Please try again."
-		} else if contains(err.Error(), "context canceled") {
+			errMsg = h.tr(update.Message.From, "chat.timeout")
+		} else if errors.Is(err, context.Canceled) || contains(err.Error(), "context canceled") {
 			return
+		} else if errors.Is(err, llm.ErrVisionUnsupported) {
+			errMsg = h.tr(update.Message.From, "chat.no_vision")
+		} else if class == llm.ErrorAuth || class == llm.ErrorContextLength {
+			errMsg = h.tr(update.Message.From, providerErrorKeys[class])
+		} else if h.offlineQueue != nil && len(prompt.Images) == 0 {
+			if qerr := h.enqueueOffline(userID, chatID, prompt.Content); qerr != nil {
+				log.Printf("Failed to queue message for user %d: %v", userID, qerr)
+			} else {
+				errMsg = h.tr(update.Message.From, "chat.queued")
+			}
+		} else if key, ok := providerErrorKeys[class]; ok {
+			errMsg = h.tr(update.Message.From, key)
+		}
+		log.Printf("Request failed for user %d: %v", userID, err)
+		h.runtime.failed.Add(1)
+		if class != llm.ErrorContextLength && !errors.Is(err, llm.ErrVisionUnsupported) {
+			h.errorReporter.Report(ctx, sender, ErrorKindProvider, err.Error())
+			h.notify(webhook.EventError, userID, chatID, map[string]any{
+				"kind":  ErrorKindProvider,
+				"error": err.Error(),
+			})
 		}
+		h.react(ctx, sender, update.Message, h.reactions.Error)
 		sender.SendMessage(ctx, &tgbot.SendMessageParams{
 			ChatID: chatID,
 			Text:   errMsg,
@@ -225,58 +361,151 @@ func (h *Handlers) TextMessageHandler(ctx context.Context, b any, update *models
 		return
 	}
 
+	response = h.postprocess(ctx, userID, response)
 	if response == "" {
+		h.react(ctx, sender, update.Message, h.reactions.Error)
 		sender.SendMessage(ctx, &tgbot.SendMessageParams{
 			ChatID: chatID,
-			Text:   "Empty response from AI",
+			Text:   h.tr(update.Message.From, "chat.empty"),
 		})
 		return
 	}
 
-	messages = append(messages, llm.Message{
-		Role:    "assistant",
-		Content: response,
-	})
+	answer := answerMessage(request, response, route)
+	messages = append(messages, answer)
+
+	if reasoning != "" {
+		h.sendReply(ctx, sender, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "chat.reasoning", reasoning),
+		})
+	}
 
-	if err := h.sessionManager.Save(userID, messages); err != nil {
+	reaction := h.reactions.Done
+	if _, err := h.sendReply(ctx, sender, &tgbot.SendMessageParams{
+		ChatID:          chatID,
+		Text:            h.offlineNotice(update.Message.From, route) + thinkSpoiler(thought) + response + h.routeFooter(update.Message.From, route),
+		ReplyMarkup:     h.answerMarkup(update.Message.From, answer.ID, truncated),
+		ReplyParameters: h.replyTo(update.Message),
+	}); err != nil {
+		log.Printf("Failed to send reply to chat %d: %v", chatID, err)
+		reaction = h.reactions.Error
+	}
+	h.react(ctx, sender, update.Message, reaction)
+	h.runtime.answered.Add(1)
+
+	// Compacting can summarize the history with another request, so it
+	// waits until the reply is out. The session stays locked until saved.
+	messages = h.compactHistory(ctx, userID, messages)
+	err = h.sessionManager.Save(key, messages)
+	h.lastPrompts.set(key, promptRef{chatID: chatID, messageID: update.Message.ID})
+	unlock()
+	if err != nil {
 		log.Printf("Failed to save session for user %d: %v", userID, err)
+	} else {
+		h.titleThread(ctx, key, userID, messages)
 	}
 
-	sender.SendMessage(ctx, &tgbot.SendMessageParams{
-		ChatID: chatID,
-		Text:   response,
+	h.notifyMessage(userID, chatID, map[string]any{
+		"message_id":        answer.ID,
+		"provider":          answer.Provider,
+		"model":             answer.Model,
+		"prompt_tokens":     answer.Tokens.Prompt,
+		"completion_tokens": answer.Tokens.Completion,
+	}, map[string]any{
+		"prompt": prompt.Content,
+		"answer": response,
 	})
+	h.warnUsage(ctx, sender, chatID, update.Message.From)
+	h.recordStats(userID, route.Provider, request, response, latency)
+	h.remember(ctx, userID, prompt.Content, response)
+	h.extractFacts(ctx, userID, prompt.Content, response)
+	h.recordDigest(userID, prompt.Content, response)
 }
 
-func (h *Handlers) checkAuth(update *models.Update) bool {
-	if len(h.allowedUsers) == 0 {
-		return true
+// answerMessage is the history entry for response, recording which provider
+// and model wrote it and the estimated tokens of the exchange.
+func answerMessage(request []llm.Message, response string, route llm.Route) llm.Message {
+	return llm.Stamped(llm.Message{
+		Role:     "assistant",
+		Content:  response,
+		Provider: route.Provider,
+		Model:    route.Model,
+		Tokens: llm.TokenCounts{
+			Prompt:     llm.CountTokens(request),
+			Completion: llm.EstimateTokens(response),
+		},
+	})
+}
+
+func resolveSender(b any) BotSender {
+	switch v := b.(type) {
+	case *tgbot.Bot:
+		return &botAdapter{Bot: v}
+	case BotSender:
+		return v
 	}
+	return nil
+}
 
-	var userID int64
-	if update.Message != nil {
-		userID = update.Message.From.ID
-	} else if update.CallbackQuery != nil {
-		userID = update.CallbackQuery.From.ID
-	} else if update.EditedMessage != nil {
-		userID = update.EditedMessage.From.ID
+func (h *Handlers) checkAuth(ctx context.Context, sender BotSender, update *models.Update) bool {
+	if h.devMode() {
+		return true
 	}
 
+	userID := updateUserID(update)
 	if userID == 0 {
 		log.Printf("[%s] Unauthorized access attempt: missing user info", timestamp())
 		return false
 	}
 
+	h.resolveUsername(updateUser(update))
+	if h.Role(userID) != RoleNone {
+		return true
+	}
+
+	log.Printf("[%s] Unauthorized access attempt from user %d", timestamp(), userID)
+	if h.accessReporter != nil {
+		h.reportUnauthorized(ctx, sender, update)
+	}
+	return false
+}
+
+func (h *Handlers) devMode() bool {
+	h.authMu.RLock()
+	defer h.authMu.RUnlock()
+	return h.openAccess()
+}
+
+// openAccess reports whether no owners, admins, users, guests or usernames
+// are configured, which lets everyone in. Callers hold authMu.
+func (h *Handlers) openAccess() bool {
+	return len(h.owners) == 0 && len(h.admins) == 0 && len(h.allowedUsers) == 0 &&
+		len(h.guests) == 0 && len(h.allowedNames) == 0
+}
+
+func (h *Handlers) isAllowed(userID int64) bool {
+	h.authMu.RLock()
+	defer h.authMu.RUnlock()
+
 	for _, allowed := range h.allowedUsers {
 		if userID == allowed {
 			return true
 		}
 	}
-
-	log.Printf("[%s] Unauthorized access attempt from user %d", timestamp(), userID)
 	return false
 }
 
+func displayName(user *models.User) string {
+	if user.FirstName != "" {
+		return user.FirstName
+	}
+	if user.Username != "" {
+		return user.Username
+	}
+	return fmt.Sprintf("%d", user.ID)
+}
+
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
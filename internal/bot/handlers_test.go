@@ -3,31 +3,86 @@ package bot
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 
 	tgbot "github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
 	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/session"
 )
 
 type mockRouter struct {
 	providerName string
+	provider     llm.Provider
 	response     string
 	err          error
+	lastMessages []llm.Message
+	lastOpts     []llm.RequestOption
+
+	userProviders map[int64]string
 }
 
 func (m *mockRouter) GetProvider() (llm.Provider, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	if m.provider != nil {
+		return m.provider, nil
+	}
 	return &mockProvider{name: m.providerName}, nil
 }
 
-func (m *mockRouter) SendMessage(ctx context.Context, messages []llm.Message) (string, error) {
+func (m *mockRouter) GetProviderByName(name string) (llm.Provider, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.provider != nil {
+		return m.provider, nil
+	}
+	return &mockProvider{name: name}, nil
+}
+
+func (m *mockRouter) GetProviderForUser(userID int64) (llm.Provider, error) {
+	if name, ok := m.userProviders[userID]; ok {
+		return &mockProvider{name: name}, nil
+	}
+	return m.GetProvider()
+}
+
+func (m *mockRouter) SetDefaultForUser(userID int64, name string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.userProviders == nil {
+		m.userProviders = make(map[int64]string)
+	}
+	if name == "" {
+		delete(m.userProviders, userID)
+	} else {
+		m.userProviders[userID] = name
+	}
+	return nil
+}
+
+func (m *mockRouter) ProviderNames() []string {
+	return []string{"openai", "anthropic"}
+}
+
+func (m *mockRouter) SendMessage(ctx context.Context, messages []llm.Message, opts ...llm.RequestOption) (string, error) {
+	m.lastMessages = messages
+	m.lastOpts = opts
 	return m.response, m.err
 }
 
+func (m *mockRouter) Ping(ctx context.Context) error {
+	return m.err
+}
+
 type mockProvider struct {
 	name string
 }
@@ -45,15 +100,26 @@ func (m *mockProvider) SendMessage(ctx context.Context, messages []llm.Message)
 }
 
 type mockSessionManager struct {
-	messages []llm.Message
-	err      error
+	messages  []llm.Message
+	err       error
+	lastGetID int64
+	savedID   int64
+	saved     []llm.Message
+	providers map[int64]string
+	prompts   map[int64]string
+	languages map[int64]string
+	threads   []session.Thread
+	active    int
 }
 
 func (m *mockSessionManager) Get(userID int64) ([]llm.Message, error) {
+	m.lastGetID = userID
 	return m.messages, m.err
 }
 
 func (m *mockSessionManager) Save(userID int64, messages []llm.Message) error {
+	m.savedID = userID
+	m.saved = messages
 	return m.err
 }
 
@@ -61,13 +127,133 @@ func (m *mockSessionManager) Delete(userID int64) error {
 	return m.err
 }
 
+func (m *mockSessionManager) PopLast(userID int64) (llm.Message, error) {
+	if len(m.messages) == 0 {
+		return llm.Message{}, session.ErrEmptySession
+	}
+	last := m.messages[len(m.messages)-1]
+	m.messages = m.messages[:len(m.messages)-1]
+	return last, m.err
+}
+
+func (m *mockSessionManager) ReplaceLast(userID int64, msg llm.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	if len(m.messages) == 0 {
+		return session.ErrEmptySession
+	}
+	m.messages[len(m.messages)-1] = msg
+	m.savedID = userID
+	m.saved = m.messages
+	return nil
+}
+
+func (m *mockSessionManager) SetRating(userID int64, messageID string, score int) error {
+	if m.err != nil {
+		return m.err
+	}
+	i := slices.IndexFunc(m.messages, func(msg llm.Message) bool { return msg.ID == messageID })
+	if i < 0 {
+		return session.ErrMessageNotFound
+	}
+	m.messages[i].Rating = score
+	m.savedID = userID
+	m.saved = m.messages
+	return nil
+}
+
+func (m *mockSessionManager) GetProvider(userID int64) (string, error) {
+	return m.providers[userID], m.err
+}
+
+func (m *mockSessionManager) SetProvider(userID int64, name string) error {
+	if m.providers == nil {
+		m.providers = make(map[int64]string)
+	}
+	m.providers[userID] = name
+	return m.err
+}
+
+func (m *mockSessionManager) Providers() (map[int64]string, error) {
+	return m.providers, m.err
+}
+
+func (m *mockSessionManager) GetPrompt(userID int64) (string, error) {
+	return m.prompts[userID], m.err
+}
+
+func (m *mockSessionManager) SetPrompt(userID int64, prompt string) error {
+	if m.prompts == nil {
+		m.prompts = make(map[int64]string)
+	}
+	m.prompts[userID] = prompt
+	return m.err
+}
+
+func (m *mockSessionManager) GetLanguage(userID int64) (string, error) {
+	return m.languages[userID], m.err
+}
+
+func (m *mockSessionManager) SetLanguage(userID int64, lang string) error {
+	if m.languages == nil {
+		m.languages = make(map[int64]string)
+	}
+	m.languages[userID] = lang
+	return m.err
+}
+
+func (m *mockSessionManager) NewThread(userID int64, title string) (session.Thread, error) {
+	if m.err != nil {
+		return session.Thread{}, m.err
+	}
+	t := session.Thread{ID: len(m.threads) + 1, Title: title}
+	m.threads = append(m.threads, t)
+	m.active = t.ID
+	return t, nil
+}
+
+func (m *mockSessionManager) Threads(userID int64) ([]session.Thread, int, error) {
+	return m.threads, m.active, m.err
+}
+
+func (m *mockSessionManager) ResumeThread(userID int64, id int) (session.Thread, error) {
+	for _, t := range m.threads {
+		if t.ID == id {
+			m.active = id
+			return t, nil
+		}
+	}
+	return session.Thread{}, errors.New("thread not found")
+}
+
 type mockBot struct {
 	lastMessageParams *tgbot.SendMessageParams
 	lastChatAction    *tgbot.SendChatActionParams
+	sentMessages      []*tgbot.SendMessageParams
+	lastCallback      *tgbot.AnswerCallbackQueryParams
+	lastEdit          *tgbot.EditMessageTextParams
+	lastDocument      *tgbot.SendDocumentParams
 }
 
 func (m *mockBot) SendMessage(ctx context.Context, params *tgbot.SendMessageParams) (*models.Message, error) {
 	m.lastMessageParams = params
+	m.sentMessages = append(m.sentMessages, params)
+	return nil, nil
+}
+
+func (m *mockBot) AnswerCallbackQuery(ctx context.Context, params *tgbot.AnswerCallbackQueryParams) (bool, error) {
+	m.lastCallback = params
+	return true, nil
+}
+
+func (m *mockBot) SendDocument(ctx context.Context, params *tgbot.SendDocumentParams) (*models.Message, error) {
+	m.lastDocument = params
+	return nil, nil
+}
+
+func (m *mockBot) EditMessageText(ctx context.Context, params *tgbot.EditMessageTextParams) (*models.Message, error) {
+	m.lastEdit = params
 	return nil, nil
 }
 
@@ -272,3 +458,248 @@ func TestTextMessageHandler_NoProviderError(t *testing.T) {
 		t.Errorf("expected %q, got %q", expected, bot.lastMessageParams.Text)
 	}
 }
+
+func TestTextMessageHandler_ProviderErrorClasses(t *testing.T) {
+	tests := []struct {
+		class llm.ErrorClass
+		want  string
+	}{
+		{llm.ErrorRateLimited, "too many requests"},
+		{llm.ErrorAuth, "credentials"},
+		{llm.ErrorContextLength, "too long for the model"},
+		{llm.ErrorServer, "having problems"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.class), func(t *testing.T) {
+			router := &mockRouter{err: &llm.ProviderError{Provider: "openai", Class: tt.class, Err: errors.New("boom")}}
+			handlers := NewHandlers(router, &mockSessionManager{}, []int64{})
+
+			bot := &mockBot{}
+			handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "Hello"))
+
+			if bot.lastMessageParams == nil || !strings.Contains(bot.lastMessageParams.Text, tt.want) {
+				t.Errorf("expected message containing %q, got %+v", tt.want, bot.lastMessageParams)
+			}
+		})
+	}
+}
+
+// overflowRouter rejects requests with more than limit messages as too long
+// for the context window.
+type overflowRouter struct {
+	mockRouter
+	limit    int
+	requests [][]llm.Message
+}
+
+func (r *overflowRouter) SendMessage(ctx context.Context, messages []llm.Message, opts ...llm.RequestOption) (string, error) {
+	r.requests = append(r.requests, messages)
+	if len(messages) > r.limit {
+		return "", &llm.ProviderError{Provider: "openai", Class: llm.ErrorContextLength, Err: errors.New("maximum context length exceeded")}
+	}
+	return "ok", nil
+}
+
+func TestTextMessageHandler_RetriesWithShorterHistory(t *testing.T) {
+	router := &overflowRouter{limit: 3}
+	sessions := &mockSessionManager{messages: []llm.Message{
+		{Role: "user", Content: "1"},
+		{Role: "assistant", Content: "a"},
+		{Role: "user", Content: "2"},
+		{Role: "assistant", Content: "b"},
+	}}
+	handlers := NewHandlers(router, sessions, []int64{})
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "3"))
+
+	if len(router.requests) != 2 {
+		t.Fatalf("expected one retry, got %d requests", len(router.requests))
+	}
+	if bot.lastMessageParams == nil || bot.lastMessageParams.Text != "ok" {
+		t.Errorf("expected the retried answer, got %+v", bot.lastMessageParams)
+	}
+	if len(sessions.saved) != 4 || sessions.saved[0].Content != "2" {
+		t.Errorf("expected the shortened history to be saved, got %+v", sessions.saved)
+	}
+}
+
+type mockSelector struct {
+	result []llm.Message
+	err    error
+}
+
+func (m *mockSelector) Select(ctx context.Context, history []llm.Message, prompt string) ([]llm.Message, error) {
+	return m.result, m.err
+}
+
+func TestTextMessageHandler_UsesContextSelector(t *testing.T) {
+	history := []llm.Message{
+		{Role: "user", Content: "old"},
+		{Role: "assistant", Content: "old answer"},
+		{Role: "user", Content: "relevant"},
+		{Role: "assistant", Content: "relevant answer"},
+	}
+	router := &mockRouter{response: "ok"}
+	selector := &mockSelector{result: history[2:]}
+	handlers := NewHandlers(router, &mockSessionManager{messages: history}, []int64{1}, WithContextSelector(selector))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "question"))
+
+	if len(router.lastMessages) != 3 || router.lastMessages[0].Content != "relevant" || router.lastMessages[2].Content != "question" {
+		t.Errorf("expected pruned context plus prompt, got %+v", router.lastMessages)
+	}
+
+	selector.err = errors.New("embed failed")
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "question"))
+	if len(router.lastMessages) != 5 {
+		t.Errorf("expected full history on selector error, got %d messages", len(router.lastMessages))
+	}
+}
+
+type mockCompactor struct {
+	err error
+	// bot, when set, records how many replies it had sent when the
+	// history was compacted.
+	bot         *mockBot
+	sentAtStart int
+}
+
+func (m *mockCompactor) Compact(ctx context.Context, history []llm.Message, opts ...llm.RequestOption) ([]llm.Message, error) {
+	if m.bot != nil {
+		m.sentAtStart = len(m.bot.sentMessages)
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return append([]llm.Message{llm.SummaryMessage("summary")}, history[len(history)-2:]...), nil
+}
+
+func TestTextMessageHandler_CompactsHistory(t *testing.T) {
+	history := []llm.Message{
+		{Role: "user", Content: "old"},
+		{Role: "assistant", Content: "old answer"},
+	}
+	b := &mockBot{}
+	compactor := &mockCompactor{bot: b}
+	sessions := &mockSessionManager{messages: history}
+	handlers := NewHandlers(&mockRouter{response: "ok"}, sessions, []int64{1}, WithHistoryCompactor(compactor))
+
+	handlers.TextMessageHandler(context.Background(), b, makeUpdate(1, 1, "question"))
+
+	if len(sessions.saved) != 3 || !llm.IsSummary(sessions.saved[0]) || sessions.saved[2].Content != "ok" {
+		t.Errorf("expected compacted history to be saved, got %+v", sessions.saved)
+	}
+	if compactor.sentAtStart != 1 {
+		t.Errorf("expected the reply to be sent before compacting, %d were sent", compactor.sentAtStart)
+	}
+
+	compactor.err = errors.New("provider down")
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "question"))
+	if len(sessions.saved) != 4 {
+		t.Errorf("expected full history to be saved on failure, got %d messages", len(sessions.saved))
+	}
+}
+
+type mockDeduplicator struct {
+	err error
+}
+
+func (m *mockDeduplicator) Deduplicate(ctx context.Context, history []llm.Message) ([]llm.Message, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return history[2:], nil
+}
+
+func TestTextMessageHandler_DeduplicatesHistory(t *testing.T) {
+	history := []llm.Message{
+		{Role: "user", Content: "what is go"},
+		{Role: "assistant", Content: "a language"},
+		{Role: "user", Content: "what's go?"},
+		{Role: "assistant", Content: "a programming language"},
+	}
+	router := &mockRouter{response: "ok"}
+	sessions := &mockSessionManager{messages: history}
+	dedup := &mockDeduplicator{}
+	handlers := NewHandlers(router, sessions, nil, WithHistoryDeduplicator(dedup))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "new"))
+
+	if len(router.lastMessages) != 3 || router.lastMessages[0].Content != "what's go?" {
+		t.Errorf("expected the repeated exchange to be left out, got %+v", router.lastMessages)
+	}
+	if len(sessions.saved) != 6 {
+		t.Errorf("expected the stored history to be kept, got %d messages", len(sessions.saved))
+	}
+
+	dedup.err = errors.New("embed failed")
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "new"))
+	if len(router.lastMessages) != 5 {
+		t.Errorf("expected full history on error, got %d messages", len(router.lastMessages))
+	}
+}
+
+func TestTextMessageHandler_HistoryTokens(t *testing.T) {
+	history := []llm.Message{
+		{Role: "user", Content: "an old question with plenty of words in it"},
+		{Role: "assistant", Content: "an old answer with even more words in it than the question"},
+		{Role: "user", Content: "recent"},
+		{Role: "assistant", Content: "reply"},
+	}
+	router := &mockRouter{providerName: "openai", response: "ok"}
+	sessions := &mockSessionManager{messages: history}
+	handlers := NewHandlers(router, sessions, nil, WithHistoryTokens(llm.CountTokens(history[2:])))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "new"))
+
+	for _, m := range router.lastMessages {
+		if strings.Contains(m.Content, "old") {
+			t.Fatalf("expected the old exchange to be left out, got %+v", router.lastMessages)
+		}
+	}
+	if len(router.lastMessages) != 3 || router.lastMessages[0].Content != "recent" {
+		t.Errorf("expected the recent exchange and the prompt, got %+v", router.lastMessages)
+	}
+	if len(sessions.saved) != 6 {
+		t.Errorf("expected the stored history to be kept, got %d messages", len(sessions.saved))
+	}
+}
+
+func TestTextMessageHandler_SpoilersThinkWithoutSavingIt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message":{"role":"assistant","content":"<think>Greet back.</think>Hello!"},"done":true}`))
+	}))
+	defer server.Close()
+	router, err := llm.NewRouter(&config.Config{
+		Providers: config.ProvidersConfig{Ollama: config.ProviderConfig{Enabled: true, DefaultModel: "deepseek-r1", Think: "spoiler"}},
+		APIKeys:   map[string]string{"OLLAMA_BASE_URL": server.URL + "/v1/"},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{1}, WithThinkSpoilers(true))
+	bot := &mockBot{}
+
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "hi"))
+
+	if got := bot.lastMessageParams.Text; !strings.Contains(got, "||Greet back") || !strings.Contains(got, "Hello") {
+		t.Errorf("expected the reasoning as a spoiler above the answer, got %q", got)
+	}
+	if len(sessions.saved) != 2 || sessions.saved[1].Content != "Hello!" {
+		t.Errorf("expected only the answer in the history, got %+v", sessions.saved)
+	}
+}
+
+func TestTextMessageHandler_StoresRouteOfAnswer(t *testing.T) {
+	router := newPartsRouter(t, "Hello!")
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{1})
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hi"))
+
+	if len(sessions.saved) != 2 || sessions.saved[1].Provider != "ollama" || sessions.saved[1].Model != "llama3.2" {
+		t.Errorf("expected the stored answer to record its provider and model, got %+v", sessions.saved)
+	}
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// Publisher sends a message to an MQTT topic.
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+}
+
+type homeAssistant struct {
+	publisher Publisher
+	topic     string
+}
+
+// haCommand is the JSON payload /ha publishes.
+type haCommand struct {
+	Text     string `json:"text"`
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username,omitempty"`
+	ChatID   int64  `json:"chat_id"`
+}
+
+// WithHomeAssistant publishes the commands users send with /ha to topic.
+func WithHomeAssistant(publisher Publisher, topic string) Option {
+	return func(h *Handlers) {
+		h.homeAssistant = &homeAssistant{publisher: publisher, topic: topic}
+	}
+}
+
+// HACommandHandler publishes "/ha turn off the lights" as a command for
+// Home Assistant automations to act on. It is registered for admins only,
+// since it can reach locks and alarms.
+func (h *Handlers) HACommandHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	chatID := update.Message.Chat.ID
+	reply := func(key string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(user, key),
+		})
+	}
+
+	if h.homeAssistant == nil {
+		reply("ha.disabled")
+		return
+	}
+	text := commandArgs(update.Message.Text)
+	if text == "" {
+		reply("ha.usage")
+		return
+	}
+
+	payload, err := json.Marshal(haCommand{
+		Text:     text,
+		UserID:   user.ID,
+		Username: user.Username,
+		ChatID:   chatID,
+	})
+	if err == nil {
+		err = h.homeAssistant.publisher.Publish(h.homeAssistant.topic, payload)
+	}
+	if err != nil {
+		log.Printf("Failed to publish Home Assistant command from user %d: %v", user.ID, err)
+		reply("ha.error")
+		return
+	}
+	reply("ha.sent")
+}
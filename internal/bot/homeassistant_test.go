@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type mockPublisher struct {
+	topic   string
+	payload []byte
+	err     error
+}
+
+func (m *mockPublisher) Publish(topic string, payload []byte) error {
+	m.topic = topic
+	m.payload = payload
+	return m.err
+}
+
+func TestHACommandHandler_PublishesCommand(t *testing.T) {
+	publisher := &mockPublisher{}
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithHomeAssistant(publisher, "helpi/commands"))
+	bot := &mockBot{}
+
+	handlers.HACommandHandler(context.Background(), bot, makeUpdate(1, 1, "/ha turn off the lights"))
+
+	if publisher.topic != "helpi/commands" {
+		t.Errorf("expected the command topic, got %q", publisher.topic)
+	}
+	var cmd haCommand
+	if err := json.Unmarshal(publisher.payload, &cmd); err != nil {
+		t.Fatalf("invalid payload %s: %v", publisher.payload, err)
+	}
+	if cmd.Text != "turn off the lights" || cmd.UserID != 1 || cmd.ChatID != 1 {
+		t.Errorf("unexpected command %+v", cmd)
+	}
+	if bot.lastMessageParams.Text != "Sent to Home Assistant." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestHACommandHandler_ReportsFailures(t *testing.T) {
+	publisher := &mockPublisher{err: errors.New("not connected")}
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithHomeAssistant(publisher, "helpi/commands"))
+	bot := &mockBot{}
+
+	handlers.HACommandHandler(context.Background(), bot, makeUpdate(1, 1, "/ha turn off the lights"))
+
+	if bot.lastMessageParams.Text != "Error sending the command to Home Assistant" {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestHACommandHandler_Disabled(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1})
+	bot := &mockBot{}
+
+	handlers.HACommandHandler(context.Background(), bot, makeUpdate(1, 1, "/ha turn off the lights"))
+
+	if bot.lastMessageParams.Text != "Home Assistant is not connected." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestHACommand_AdminsOnly(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1})
+
+	c, ok := handlers.Commands().Lookup("ha")
+	if !ok || c.Role != RoleAdmin {
+		t.Errorf("expected /ha to require the admin role, got %+v", c)
+	}
+}
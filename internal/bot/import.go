@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+const maxImportSize = 2 << 20
+
+// isImportCaption reports whether a document was sent with /import as its
+// caption, which Telegram does not deliver as a command.
+func isImportCaption(msg *models.Message) bool {
+	return strings.HasPrefix(msg.Caption, "/") && commandName(msg.Caption) == "import"
+}
+
+// ImportHandler adds a conversation exported from helpi, as JSON or
+// Markdown, to the current conversation, or to a new thread with
+// "/import new". The file is sent with /import as its caption, or /import
+// is sent as a reply to it.
+func (h *Handlers) ImportHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	chatID := update.Message.Chat.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	text := update.Message.Text
+	doc := update.Message.Document
+	if doc != nil {
+		text = update.Message.Caption
+	} else if update.Message.ReplyToMessage != nil {
+		doc = update.Message.ReplyToMessage.Document
+	}
+	args := commandArgs(text)
+	if doc == nil || (args != "" && args != "new") {
+		reply(h.tr(user, "import.usage"))
+		return
+	}
+	if doc.FileSize > maxImportSize {
+		reply(h.tr(user, "import.too_large", maxImportSize>>20))
+		return
+	}
+
+	downloader, ok := sender.(FileDownloader)
+	if !ok {
+		return
+	}
+	data, err := downloadFile(ctx, downloader, doc.FileID, maxImportSize)
+	if err != nil {
+		log.Printf("Failed to download import from user %d: %v", user.ID, err)
+		reply(h.tr(user, "import.download_error"))
+		return
+	}
+
+	imported, err := session.ParseTranscript(doc.FileName, data)
+	if err != nil {
+		reply(h.tr(user, "import.invalid", err))
+		return
+	}
+
+	key := h.sessionKey(chatID, user.ID)
+	unlock, err := h.lockSession(ctx, key)
+	if err != nil {
+		log.Printf("Failed to lock session for user %d: %v", user.ID, err)
+		reply(h.tr(user, "import.error"))
+		return
+	}
+	defer unlock()
+	if args == "new" {
+		title := strings.TrimSuffix(doc.FileName, filepath.Ext(doc.FileName))
+		if len([]rune(title)) > maxThreadTitleLength {
+			title = string([]rune(title)[:maxThreadTitleLength])
+		}
+		thread, err := h.sessionManager.NewThread(key, title)
+		if err != nil {
+			log.Printf("Failed to create thread for user %d: %v", user.ID, err)
+			reply(h.tr(user, "import.error"))
+			return
+		}
+		if err := h.sessionManager.Save(key, imported); err != nil {
+			log.Printf("Failed to save import for user %d: %v", user.ID, err)
+			reply(h.tr(user, "import.error"))
+			return
+		}
+		reply(h.tr(user, "import.thread", len(imported), thread.ID, thread.Title))
+		return
+	}
+
+	history, err := h.sessionManager.Get(key)
+	if err != nil {
+		log.Printf("Failed to load session for user %d: %v", user.ID, err)
+		reply(h.tr(user, "import.error"))
+		return
+	}
+	if err := h.sessionManager.Save(key, append(history, imported...)); err != nil {
+		log.Printf("Failed to save import for user %d: %v", user.ID, err)
+		reply(h.tr(user, "import.error"))
+		return
+	}
+	reply(h.tr(user, "import.done", len(imported)))
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestImportHandler_MergesIntoSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "## User\nwhat is go?\n\n## Assistant\nA language.")
+	}))
+	defer server.Close()
+
+	sessions := &mockSessionManager{messages: []llm.Message{{Role: "user", Content: "earlier"}}}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1})
+
+	bot := &mockFileBot{baseURL: server.URL}
+	handlers.DocumentHandler(context.Background(), bot, makeDocumentUpdate(1, "chat.md", "/import"))
+
+	if len(sessions.saved) != 3 || sessions.saved[0].Content != "earlier" || sessions.saved[2].Content != "A language." {
+		t.Errorf("expected imported messages after the existing ones, got %+v", sessions.saved)
+	}
+	if !strings.Contains(bot.lastMessageParams.Text, "Imported 2 messages") {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestImportHandler_NewThreadFromReply(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"Role":"user","Content":"hi"},{"Role":"assistant","Content":"hello"}]`)
+	}))
+	defer server.Close()
+
+	sessions := &mockSessionManager{messages: []llm.Message{{Role: "user", Content: "earlier"}}}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1})
+
+	update := makeUpdate(1, 1, "/import new")
+	update.Message.ReplyToMessage = &models.Message{Document: &models.Document{FileID: "doc1", FileName: "trip.json", FileSize: 64}}
+	bot := &mockFileBot{baseURL: server.URL}
+	handlers.ImportHandler(context.Background(), bot, update)
+
+	if len(sessions.threads) != 1 || sessions.threads[0].Title != "trip" {
+		t.Fatalf("expected a new thread named after the file, got %+v", sessions.threads)
+	}
+	if len(sessions.saved) != 2 || sessions.saved[0].Content != "hi" {
+		t.Errorf("expected only the imported messages in the thread, got %+v", sessions.saved)
+	}
+}
+
+func TestImportHandler_RejectsInvalidFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "no conversation here")
+	}))
+	defer server.Close()
+
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1})
+
+	bot := &mockFileBot{baseURL: server.URL}
+	handlers.DocumentHandler(context.Background(), bot, makeDocumentUpdate(1, "notes.md", "/import"))
+
+	if sessions.saved != nil || !strings.Contains(bot.lastMessageParams.Text, "Could not import") {
+		t.Errorf("expected the file to be rejected, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.ImportHandler(context.Background(), bot, makeUpdate(1, 1, "/import"))
+	if !strings.Contains(bot.lastMessageParams.Text, "with /import as its caption") {
+		t.Errorf("expected usage without a file, got %q", bot.lastMessageParams.Text)
+	}
+}
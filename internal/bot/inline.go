@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// maxInlineMessageLength is the longest text an inline result can send.
+const maxInlineMessageLength = 4096
+
+// inlineDebounce is how long a user must stop typing before their inline
+// query is answered.
+const inlineDebounce = 500 * time.Millisecond
+
+type InlineAnswerer interface {
+	AnswerInlineQuery(ctx context.Context, params *tgbot.AnswerInlineQueryParams) (bool, error)
+}
+
+// WithInlineQueries limits inline queries to perMinute per user with the
+// given burst and waits for users to stop typing before answering them.
+// Zero disables the limit.
+func WithInlineQueries(perMinute, burst int) Option {
+	return func(h *Handlers) {
+		h.inlineDebounce = inlineDebounce
+		if perMinute > 0 {
+			h.inlineLimiter = NewRateLimitMiddleware(perMinute, burst)
+		}
+	}
+}
+
+// IsInlineQuery matches updates sent when a user types @bot <question>.
+func IsInlineQuery(update *models.Update) bool {
+	return update.InlineQuery != nil
+}
+
+// InlineQueryHandler answers @bot <question> with a single result holding
+// the reply. Inline questions are neither read from nor saved to the user's
+// history. Telegram sends a query per keystroke, so each query waits for the
+// debounce window and a new query from the same user drops the previous one;
+// only the queries that are answered count against the limit.
+func (h *Handlers) InlineQueryHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || update.InlineQuery == nil || update.InlineQuery.From == nil {
+		return
+	}
+	answerer, ok := sender.(InlineAnswerer)
+	if !ok {
+		return
+	}
+
+	query := update.InlineQuery
+	user := query.From
+	question := strings.TrimSpace(query.Query)
+	if question == "" {
+		return
+	}
+
+	h.inlineQueries.cancel(user.ID)
+	ctx, done := h.inlineQueries.start(ctx, user.ID)
+	defer done()
+
+	if h.inlineDebounce > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(h.inlineDebounce):
+		}
+	}
+
+	answer := func(results ...models.InlineQueryResult) {
+		if _, err := answerer.AnswerInlineQuery(ctx, &tgbot.AnswerInlineQueryParams{
+			InlineQueryID: query.ID,
+			Results:       results,
+			IsPersonal:    true,
+		}); err != nil {
+			log.Printf("Failed to answer inline query from user %d: %v", user.ID, err)
+		}
+	}
+
+	if h.inlineLimiter != nil {
+		if allowed, _, _ := h.inlineLimiter.allow(user.ID); !allowed {
+			log.Printf("[RATE] User %d exceeded inline query limit", user.ID)
+			answer()
+			return
+		}
+	}
+	if h.budgetRefusal(user) != "" {
+		answer()
+		return
+	}
+
+	request := h.withUserPrompt(user.ID, []llm.Message{{Role: "user", Content: question}})
+	response, err := h.router.SendMessage(ctx, request, h.requestOptions(user.ID)...)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		log.Printf("Inline query from user %d failed: %v", user.ID, err)
+		answer()
+		return
+	}
+
+	response = strings.TrimSpace(h.postprocess(ctx, user.ID, response))
+	answer(&models.InlineQueryResultArticle{
+		ID:          query.ID,
+		Title:       truncate(question, 64),
+		Description: truncate(response, 128),
+		InputMessageContent: &models.InputTextMessageContent{
+			MessageText: truncate(response, maxInlineMessageLength-1),
+		},
+	})
+}
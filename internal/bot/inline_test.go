@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type inlineBot struct {
+	mockBot
+	answers []*tgbot.AnswerInlineQueryParams
+}
+
+func (b *inlineBot) AnswerInlineQuery(ctx context.Context, params *tgbot.AnswerInlineQueryParams) (bool, error) {
+	b.answers = append(b.answers, params)
+	return true, nil
+}
+
+func makeInlineUpdate(userID int64, query string) *models.Update {
+	return &models.Update{InlineQuery: &models.InlineQuery{ID: "q1", From: &models.User{ID: userID}, Query: query}}
+}
+
+func TestInlineQueryHandler_AnswersWithoutHistory(t *testing.T) {
+	router := &mockRouter{response: "Paris"}
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{1})
+	b := &inlineBot{}
+
+	handlers.InlineQueryHandler(context.Background(), b, makeInlineUpdate(1, "capital of France?"))
+
+	if len(b.answers) != 1 || len(b.answers[0].Results) != 1 {
+		t.Fatalf("expected one answer with one result, got %+v", b.answers)
+	}
+	article, ok := b.answers[0].Results[0].(*models.InlineQueryResultArticle)
+	if !ok {
+		t.Fatalf("expected an article result, got %T", b.answers[0].Results[0])
+	}
+	content := article.InputMessageContent.(*models.InputTextMessageContent)
+	if content.MessageText != "Paris" || article.Title != "capital of France?" {
+		t.Errorf("unexpected article %+v", article)
+	}
+	if !b.answers[0].IsPersonal {
+		t.Error("expected inline answers to be personal")
+	}
+	if sessions.lastGetID != 0 || sessions.saved != nil {
+		t.Error("expected inline queries not to touch the session")
+	}
+}
+
+func TestInlineQueryHandler_RateLimited(t *testing.T) {
+	router := &mockRouter{response: "ok"}
+	handlers := NewHandlers(router, &mockSessionManager{}, nil, WithInlineQueries(1, 1))
+	handlers.inlineDebounce = 0
+	b := &inlineBot{}
+
+	handlers.InlineQueryHandler(context.Background(), b, makeInlineUpdate(1, "one"))
+	handlers.InlineQueryHandler(context.Background(), b, makeInlineUpdate(1, "two"))
+
+	if len(b.answers) != 2 || len(b.answers[1].Results) != 0 {
+		t.Errorf("expected the second query to be answered with no results, got %+v", b.answers)
+	}
+}
+
+func TestInlineQueryHandler_ProviderError(t *testing.T) {
+	router := &mockRouter{err: errors.New("boom")}
+	handlers := NewHandlers(router, &mockSessionManager{}, nil)
+	b := &inlineBot{}
+
+	handlers.InlineQueryHandler(context.Background(), b, makeInlineUpdate(1, "hello"))
+	handlers.InlineQueryHandler(context.Background(), b, makeInlineUpdate(1, "  "))
+
+	if len(b.answers) != 1 || len(b.answers[0].Results) != 0 {
+		t.Errorf("expected an empty answer on error and none for a blank query, got %+v", b.answers)
+	}
+}
+
+func TestInlineQueryHandler_DebouncesTyping(t *testing.T) {
+	router := &mockRouter{response: "ok"}
+	handlers := NewHandlers(router, &mockSessionManager{}, nil, WithInlineQueries(1, 1))
+	handlers.inlineDebounce = 50 * time.Millisecond
+	b := &inlineBot{}
+
+	done := make(chan struct{})
+	go func() {
+		handlers.InlineQueryHandler(context.Background(), b, makeInlineUpdate(1, "capital of"))
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	handlers.InlineQueryHandler(context.Background(), b, makeInlineUpdate(1, "capital of France?"))
+	<-done
+
+	if len(b.answers) != 1 || len(b.answers[0].Results) != 1 {
+		t.Fatalf("expected only the last query to be answered within the limit, got %+v", b.answers)
+	}
+	if router.lastMessages[len(router.lastMessages)-1].Content != "capital of France?" {
+		t.Errorf("expected the last query to be asked, got %+v", router.lastMessages)
+	}
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/i18n"
+)
+
+func WithDefaultLanguage(lang string) Option {
+	return func(h *Handlers) {
+		h.defaultLanguage = i18n.Normalize(lang)
+	}
+}
+
+// Language resolves the language for user: their /lang choice, then the
+// configured default, then the language Telegram reports for them.
+func (h *Handlers) Language(user *models.User) string {
+	if user == nil {
+		return h.fallbackLanguage()
+	}
+	if lang, err := h.sessionManager.GetLanguage(user.ID); err != nil {
+		log.Printf("Failed to load language for user %d: %v", user.ID, err)
+	} else if lang = i18n.Normalize(lang); lang != "" {
+		return lang
+	}
+	if h.defaultLanguage != "" {
+		return h.defaultLanguage
+	}
+	if lang := i18n.Normalize(user.LanguageCode); lang != "" {
+		return lang
+	}
+	return i18n.DefaultLanguage
+}
+
+func (h *Handlers) fallbackLanguage() string {
+	if h.defaultLanguage != "" {
+		return h.defaultLanguage
+	}
+	return i18n.DefaultLanguage
+}
+
+func (h *Handlers) tr(user *models.User, key string, args ...any) string {
+	return i18n.T(h.Language(user), key, args...)
+}
+
+func (h *Handlers) LangHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	code := strings.ToLower(commandArgs(update.Message.Text))
+	if code == "" {
+		lang := h.Language(user)
+		reply(i18n.T(lang, "lang.status", i18n.Name(lang)+" ("+lang+")", strings.Join(i18n.Supported(), ", ")))
+		return
+	}
+	reply(h.setLanguage(user, code))
+}
+
+// setLanguage stores the language of user, or resets it for "default",
+// and returns the message to show.
+func (h *Handlers) setLanguage(user *models.User, code string) string {
+	lang := ""
+	if code != "default" {
+		if lang = i18n.Normalize(code); lang == "" {
+			return h.tr(user, "lang.unknown", code, strings.Join(i18n.Supported(), ", "))
+		}
+	}
+	if err := h.sessionManager.SetLanguage(user.ID, lang); err != nil {
+		log.Printf("Failed to save language for user %d: %v", user.ID, err)
+		return h.tr(user, "lang.save_error")
+	}
+	if lang == "" {
+		return h.tr(user, "lang.reset")
+	}
+	return i18n.T(lang, "lang.set", i18n.Name(lang))
+}
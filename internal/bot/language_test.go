@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLangHandler_SetsLanguageForUser(t *testing.T) {
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1})
+
+	bot := &mockBot{}
+	handlers.LangHandler(context.Background(), bot, makeUpdate(1, 1, "/lang es"))
+	if sessions.languages[1] != "es" {
+		t.Fatalf("expected language to be saved, got %q", sessions.languages[1])
+	}
+	if bot.lastMessageParams.Text != "Idioma cambiado a Español." {
+		t.Errorf("unexpected confirmation %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.ClearHandler(context.Background(), bot, makeUpdate(1, 1, "/clear"))
+	if bot.lastMessageParams.Text != "Historial de conversación borrado." {
+		t.Errorf("expected Spanish reply, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.LangHandler(context.Background(), bot, makeUpdate(1, 1, "/lang xx"))
+	if !strings.Contains(bot.lastMessageParams.Text, `"xx"`) {
+		t.Errorf("expected unknown language error, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestLanguage_Resolution(t *testing.T) {
+	sessions := &mockSessionManager{languages: map[int64]string{2: "pt"}}
+	update := makeUpdate(1, 1, "hi")
+	update.Message.From.LanguageCode = "de-AT"
+
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1})
+	if got := handlers.Language(update.Message.From); got != "de" {
+		t.Errorf("expected Telegram language, got %q", got)
+	}
+
+	handlers = NewHandlers(&mockRouter{}, sessions, []int64{1}, WithDefaultLanguage("es"))
+	if got := handlers.Language(update.Message.From); got != "es" {
+		t.Errorf("expected configured default, got %q", got)
+	}
+
+	update.Message.From.ID = 2
+	if got := handlers.Language(update.Message.From); got != "pt" {
+		t.Errorf("expected user choice, got %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// RecoveryMiddleware keeps a panicking handler from taking the bot down.
+// It should be the first middleware so it also covers the others.
+func RecoveryMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[PANIC] Update %d: %v\n%s", update.ID, r, debug.Stack())
+			}
+		}()
+		next(ctx, b, update)
+	}
+}
+
+// LoggingMiddleware logs each update with its sender and how long it took to
+// handle.
+func LoggingMiddleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		start := time.Now()
+		next(ctx, b, update)
+		log.Printf("[UPDATE] %s from user %d handled in %s", updateKind(update), updateUserID(update), time.Since(start).Round(time.Millisecond))
+	}
+}
+
+func updateKind(update *models.Update) string {
+	switch {
+	case update.Message != nil:
+		return "message"
+	case update.EditedMessage != nil:
+		return "edited message"
+	case update.CallbackQuery != nil:
+		return "callback"
+	case update.InlineQuery != nil:
+		return "inline query"
+	}
+	return "update"
+}
+
+func updateUserID(update *models.Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		return update.Message.From.ID
+	case update.EditedMessage != nil && update.EditedMessage.From != nil:
+		return update.EditedMessage.From.ID
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.From.ID
+	case update.InlineQuery != nil && update.InlineQuery.From != nil:
+		return update.InlineQuery.From.ID
+	}
+	return 0
+}
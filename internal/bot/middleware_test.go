@@ -0,0 +1,55 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	wrapped := RecoveryMiddleware(func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		panic("boom")
+	})
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("expected panic to be recovered, got %v", r)
+		}
+	}()
+	wrapped(context.Background(), nil, makeUpdate(1, 1, "hi"))
+}
+
+func TestLoggingMiddleware_CallsNext(t *testing.T) {
+	nextCalled := false
+	wrapped := LoggingMiddleware(func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		nextCalled = true
+	})
+
+	wrapped(context.Background(), nil, &models.Update{})
+	if !nextCalled {
+		t.Error("expected next handler to be called")
+	}
+}
+
+func TestUpdateUserID(t *testing.T) {
+	tests := []struct {
+		name   string
+		update *models.Update
+		want   int64
+	}{
+		{"message", makeUpdate(5, 5, "hi"), 5},
+		{"edited", &models.Update{EditedMessage: &models.Message{From: &models.User{ID: 6}}}, 6},
+		{"callback", &models.Update{CallbackQuery: &models.CallbackQuery{From: models.User{ID: 7}}}, 7},
+		{"channel post", &models.Update{Message: &models.Message{}}, 0},
+		{"empty", &models.Update{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updateUserID(tt.update); got != tt.want {
+				t.Errorf("updateUserID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	modelCallbackPrefix = "model:"
+	modelDefaultChoice  = "default"
+)
+
+func (h *Handlers) ModelHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	text, markup, err := h.modelPicker(update.Message.From)
+	if err != nil {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   h.tr(update.Message.From, "model.none"),
+		})
+		return
+	}
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID:      update.Message.Chat.ID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+}
+
+func (h *Handlers) ModelCallbackHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || update.CallbackQuery == nil {
+		return
+	}
+
+	query := update.CallbackQuery
+	answer := func(text string) {
+		if answerer, ok := sender.(CallbackAnswerer); ok {
+			answerer.AnswerCallbackQuery(ctx, &tgbot.AnswerCallbackQueryParams{
+				CallbackQueryID: query.ID,
+				Text:            text,
+			})
+		}
+	}
+
+	name := strings.TrimPrefix(query.Data, modelCallbackPrefix)
+	if name == modelDefaultChoice {
+		name = ""
+	}
+
+	if err := h.switchProvider(query.From.ID, name); err != nil {
+		log.Printf("User %d failed to switch to provider %s: %v", query.From.ID, name, err)
+		answer(h.tr(&query.From, "model.unavailable"))
+		return
+	}
+
+	if name == "" {
+		answer(h.tr(&query.From, "switch.default"))
+	} else {
+		answer(h.tr(&query.From, "switch.done", name))
+	}
+
+	editor, ok := sender.(MessageEditor)
+	msg := query.Message.Message
+	if !ok || msg == nil {
+		return
+	}
+	text, markup, err := h.modelPicker(&query.From)
+	if err != nil {
+		return
+	}
+	editor.EditMessageText(ctx, &tgbot.EditMessageTextParams{
+		ChatID:      msg.Chat.ID,
+		MessageID:   msg.ID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+}
+
+func (h *Handlers) modelPicker(user *models.User) (string, *models.InlineKeyboardMarkup, error) {
+	active, err := h.providerFor(user.ID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var rows [][]models.InlineKeyboardButton
+	for _, name := range h.providerNames(user.ID) {
+		label := name
+		if provider, err := h.router.GetProviderByName(name); err == nil {
+			if mp, ok := provider.(llm.ModelProvider); ok && mp.Model() != "" {
+				label = fmt.Sprintf("%s (%s)", name, mp.Model())
+			}
+		}
+		if name == active.Name() {
+			label = "✓ " + label
+		}
+		rows = append(rows, []models.InlineKeyboardButton{
+			{Text: label, CallbackData: modelCallbackPrefix + name},
+		})
+	}
+	rows = append(rows, []models.InlineKeyboardButton{
+		{Text: h.tr(user, "model.use_default"), CallbackData: modelCallbackPrefix + modelDefaultChoice},
+	})
+
+	text := h.tr(user, "model.picker", active.Name())
+	return text, &models.InlineKeyboardMarkup{InlineKeyboard: rows}, nil
+}
+
+// ModelsHandler lists the models offered by the user's active provider.
+func (h *Handlers) ModelsHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	provider, err := h.providerFor(user.ID)
+	if err != nil {
+		reply(h.tr(user, "model.none"))
+		return
+	}
+	lister, ok := provider.(llm.ModelLister)
+	if !ok {
+		reply(h.tr(user, "models.unsupported", provider.Name()))
+		return
+	}
+
+	names, err := lister.ListModels(ctx)
+	if err != nil {
+		log.Printf("Failed to list models for %s: %v", provider.Name(), err)
+		reply(h.tr(user, "models.error", provider.Name()))
+		return
+	}
+	if len(names) == 0 {
+		reply(h.tr(user, "models.empty", provider.Name()))
+		return
+	}
+	reply(h.tr(user, "models.list", provider.Name(), strings.Join(names, "\n")))
+}
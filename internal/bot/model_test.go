@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+)
+
+func TestModelHandler_ShowsProviderKeyboard(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{providerName: "openai"}, &mockSessionManager{}, []int64{1})
+
+	bot := &mockBot{}
+	handlers.ModelHandler(context.Background(), bot, makeUpdate(1, 1, "/model"))
+
+	markup, ok := bot.lastMessageParams.ReplyMarkup.(*models.InlineKeyboardMarkup)
+	if !ok {
+		t.Fatalf("expected inline keyboard, got %T", bot.lastMessageParams.ReplyMarkup)
+	}
+	if len(markup.InlineKeyboard) != 3 {
+		t.Fatalf("expected a row per provider plus default, got %d rows", len(markup.InlineKeyboard))
+	}
+
+	first := markup.InlineKeyboard[0][0]
+	if first.CallbackData != "model:openai" || !strings.HasPrefix(first.Text, "✓") {
+		t.Errorf("expected active openai button, got %+v", first)
+	}
+	second := markup.InlineKeyboard[1][0]
+	if second.CallbackData != "model:anthropic" || strings.HasPrefix(second.Text, "✓") {
+		t.Errorf("unexpected anthropic button %+v", second)
+	}
+}
+
+func TestModelCallbackHandler_SwitchesProvider(t *testing.T) {
+	router := &mockRouter{providerName: "openai"}
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{1})
+
+	bot := &mockBot{}
+	handlers.ModelCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, "model:anthropic"))
+
+	if router.userProviders[1] != "anthropic" || sessions.providers[1] != "anthropic" {
+		t.Errorf("expected anthropic to be selected and persisted, got %q / %q", router.userProviders[1], sessions.providers[1])
+	}
+	if bot.lastCallback == nil || !strings.Contains(bot.lastCallback.Text, "Switched to anthropic") {
+		t.Errorf("unexpected callback answer %+v", bot.lastCallback)
+	}
+	if bot.lastEdit == nil || !strings.Contains(bot.lastEdit.Text, "Active provider: anthropic") {
+		t.Errorf("expected picker to be refreshed, got %+v", bot.lastEdit)
+	}
+
+	handlers.ModelCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, "model:default"))
+	if _, ok := router.userProviders[1]; ok {
+		t.Error("expected user default to be cleared")
+	}
+}
+
+type listingProvider struct {
+	mockProvider
+	models []string
+}
+
+func (p *listingProvider) ListModels(ctx context.Context) ([]string, error) {
+	return p.models, nil
+}
+
+func TestModelsHandler(t *testing.T) {
+	router := &mockRouter{provider: &listingProvider{mockProvider: mockProvider{name: "ollama"}, models: []string{"llama3.2", "qwen3"}}}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+
+	bot := &mockBot{}
+	handlers.ModelsHandler(context.Background(), bot, makeUpdate(1, 1, "/models"))
+	if !strings.Contains(bot.lastMessageParams.Text, "ollama") || !strings.Contains(bot.lastMessageParams.Text, "llama3.2\nqwen3") {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+
+	router.provider = &mockProvider{name: "openai"}
+	handlers.ModelsHandler(context.Background(), bot, makeUpdate(1, 1, "/models"))
+	if bot.lastMessageParams.Text != "openai cannot list its models." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"context"
+	"errors"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/jrswab/helpi/internal/api"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// Notify sends a message pushed through the HTTP API or MQTT to its chat.
+// With a prompt, the answer to the prompt and the text is sent, followed by
+// the text itself. Messages to chats in quiet hours are held like other
+// unprompted messages unless they are urgent; held reports whether this one
+// was.
+func (h *Handlers) Notify(ctx context.Context, b any, n api.Notification) (held bool, err error) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return false, errors.New("no bot to send with")
+	}
+
+	text := n.Text
+	if n.Prompt != "" {
+		var opts []llm.RequestOption
+		if n.Provider != "" {
+			opts = append(opts, llm.WithProvider(n.Provider))
+		}
+		answer, err := h.router.SendMessage(ctx, []llm.Message{{Role: "user", Content: n.Prompt + "\n\n" + n.Text}}, opts...)
+		if err != nil {
+			return false, err
+		}
+		if answer == "" {
+			return false, errors.New("empty answer")
+		}
+		text = answer + "\n\n" + n.Text
+	}
+
+	if !n.Urgent && h.hold(n.ChatID, text) {
+		return true, nil
+	}
+	_, err = h.sendReply(ctx, sender, &tgbot.SendMessageParams{
+		ChatID: n.ChatID,
+		Text:   text,
+	})
+	return false, err
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/api"
+)
+
+func TestNotify_SendsText(t *testing.T) {
+	router := &mockRouter{response: "unused"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+	bot := &mockBot{}
+
+	if _, err := handlers.Notify(context.Background(), bot, api.Notification{ChatID: 42, Text: "Backup done"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if bot.lastMessageParams.ChatID != int64(42) || bot.lastMessageParams.Text != "Backup done" {
+		t.Errorf("unexpected message %+v", bot.lastMessageParams)
+	}
+	if router.lastMessages != nil {
+		t.Error("expected no request without a prompt")
+	}
+}
+
+func TestNotify_AnswersPrompt(t *testing.T) {
+	router := &mockRouter{response: "The disk is full"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+	bot := &mockBot{}
+
+	_, err := handlers.Notify(context.Background(), bot, api.Notification{ChatID: 42, Text: "df: 100%", Prompt: "Summarize this alert"})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if bot.lastMessageParams.Text != "The disk is full\n\ndf: 100%" {
+		t.Errorf("expected the answer followed by the text, got %q", bot.lastMessageParams.Text)
+	}
+	if len(router.lastMessages) != 1 || !strings.Contains(router.lastMessages[0].Content, "df: 100%") {
+		t.Errorf("expected the alert in the request, got %+v", router.lastMessages)
+	}
+}
+
+func TestNotify_QuietHours(t *testing.T) {
+	handlers, held := newQuietHandlers(t, "22:00-07:00")
+	handlers.quiet.now = func() time.Time { return time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local) }
+	bot := &mockBot{}
+
+	wasHeld, err := handlers.Notify(context.Background(), bot, api.Notification{ChatID: 1, Text: "Backup done"})
+	if err != nil || !wasHeld || len(bot.sentMessages) != 0 {
+		t.Fatalf("expected the notification to be held, got %v, %v and %d sent", wasHeld, err, len(bot.sentMessages))
+	}
+
+	wasHeld, err = handlers.Notify(context.Background(), bot, api.Notification{ChatID: 1, Text: "Disk full", Urgent: true})
+	if err != nil || wasHeld || len(bot.sentMessages) != 1 {
+		t.Errorf("expected the urgent notification to be sent, got %v, %v and %d sent", wasHeld, err, len(bot.sentMessages))
+	}
+	if n, _ := held.Len(); n != 1 {
+		t.Errorf("expected one held message, got %d", n)
+	}
+}
@@ -0,0 +1,223 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/settings"
+)
+
+const (
+	personaCallbackPrefix = "persona:"
+	personaDefaultChoice  = "default"
+)
+
+func WithPersonas(personas []config.PersonaConfig) Option {
+	return func(h *Handlers) {
+		h.personas = personas
+	}
+}
+
+// PersonaHandler shows a keyboard to pick the persona of the current chat,
+// or sets it directly with /persona <name>. In groups only group admins can
+// change it.
+func (h *Handlers) PersonaHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	chatID := update.Message.Chat.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	if len(h.personas) == 0 || h.settings == nil {
+		reply(h.tr(user, "persona.disabled"))
+		return
+	}
+
+	name := strings.ToLower(commandArgs(update.Message.Text))
+	if name == "" {
+		text, markup := h.personaPicker(user, chatID)
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID:      chatID,
+			Text:        text,
+			ReplyMarkup: markup,
+		})
+		return
+	}
+
+	if isGroupChat(chatID) && !h.isGroupAdmin(ctx, sender, chatID, user.ID) {
+		reply(h.tr(user, "groups.admins_only"))
+		return
+	}
+	reply(h.setPersona(user, chatID, name))
+}
+
+func (h *Handlers) PersonaCallbackHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || update.CallbackQuery == nil {
+		return
+	}
+
+	query := update.CallbackQuery
+	answer := func(text string) {
+		if answerer, ok := sender.(CallbackAnswerer); ok {
+			answerer.AnswerCallbackQuery(ctx, &tgbot.AnswerCallbackQueryParams{
+				CallbackQueryID: query.ID,
+				Text:            text,
+			})
+		}
+	}
+
+	msg := query.Message.Message
+	if msg == nil || h.settings == nil {
+		answer(h.tr(&query.From, "callback.unknown"))
+		return
+	}
+	chatID := msg.Chat.ID
+	if isGroupChat(chatID) && !h.isGroupAdmin(ctx, sender, chatID, query.From.ID) {
+		answer(h.tr(&query.From, "groups.admins_only"))
+		return
+	}
+
+	answer(h.setPersona(&query.From, chatID, strings.TrimPrefix(query.Data, personaCallbackPrefix)))
+
+	editor, ok := sender.(MessageEditor)
+	if !ok {
+		return
+	}
+	text, markup := h.personaPicker(&query.From, chatID)
+	editor.EditMessageText(ctx, &tgbot.EditMessageTextParams{
+		ChatID:      chatID,
+		MessageID:   msg.ID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+}
+
+// setPersona stores the persona of chatID and returns the message to show.
+func (h *Handlers) setPersona(user *models.User, chatID int64, name string) string {
+	if name == personaDefaultChoice {
+		name = ""
+	}
+	if name != "" {
+		if _, ok := h.findPersona(name); !ok {
+			return h.tr(user, "persona.unknown", name, strings.Join(h.personaNames(), ", "))
+		}
+	}
+
+	if err := h.settings.SetString(chatID, settings.Persona, name); err != nil {
+		log.Printf("Failed to save persona for chat %d: %v", chatID, err)
+		return h.tr(user, "persona.save_error")
+	}
+	if name == "" {
+		return h.tr(user, "persona.reset")
+	}
+	return h.tr(user, "persona.set", name)
+}
+
+func (h *Handlers) personaPicker(user *models.User, chatID int64) (string, *models.InlineKeyboardMarkup) {
+	current := h.tr(user, "persona.default")
+	active, hasActive := h.chatPersona(chatID)
+	if hasActive {
+		current = active.Name
+	}
+
+	var rows [][]models.InlineKeyboardButton
+	for _, p := range h.personas {
+		label := p.Name
+		if p.Description != "" {
+			label += " - " + p.Description
+		}
+		if hasActive && p.Name == active.Name {
+			label = "✓ " + label
+		}
+		rows = append(rows, []models.InlineKeyboardButton{
+			{Text: label, CallbackData: personaCallbackPrefix + p.Name},
+		})
+	}
+	rows = append(rows, []models.InlineKeyboardButton{
+		{Text: h.tr(user, "persona.default"), CallbackData: personaCallbackPrefix + personaDefaultChoice},
+	})
+
+	return h.tr(user, "persona.picker", current), &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// chatPersona returns the persona picked for chatID. Personas removed from
+// the config since they were picked are ignored.
+func (h *Handlers) chatPersona(chatID int64) (config.PersonaConfig, bool) {
+	if len(h.personas) == 0 || h.settings == nil {
+		return config.PersonaConfig{}, false
+	}
+	name, err := h.settings.String(chatID, settings.Persona)
+	if err != nil {
+		log.Printf("Failed to load persona for chat %d: %v", chatID, err)
+		return config.PersonaConfig{}, false
+	}
+	if name == "" {
+		return config.PersonaConfig{}, false
+	}
+	return h.findPersona(name)
+}
+
+func (h *Handlers) findPersona(name string) (config.PersonaConfig, bool) {
+	for _, p := range h.personas {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return config.PersonaConfig{}, false
+}
+
+func (h *Handlers) personaNames() []string {
+	names := make([]string, 0, len(h.personas))
+	for _, p := range h.personas {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// withChatPrompt prepends the system prompt of the chat's persona, falling
+// back to the user's own prompt.
+func (h *Handlers) withChatPrompt(chatID, userID int64, messages []llm.Message) []llm.Message {
+	persona, ok := h.chatPersona(chatID)
+	if !ok || persona.SystemPrompt == "" {
+		return h.withUserPrompt(userID, messages)
+	}
+
+	result := make([]llm.Message, 0, len(messages)+1)
+	result = append(result, llm.Message{Role: "system", Content: persona.SystemPrompt})
+	return append(result, messages...)
+}
+
+// chatOptions adds the provider, model and temperature of the chat's
+// persona to the request options of userID.
+func (h *Handlers) chatOptions(chatID, userID int64) []llm.RequestOption {
+	opts := h.requestOptions(userID)
+	persona, ok := h.chatPersona(chatID)
+	if !ok {
+		return opts
+	}
+
+	if persona.Provider != "" {
+		opts = append(opts, llm.WithProvider(persona.Provider))
+	}
+	if persona.Model != "" {
+		opts = append(opts, llm.WithModel(persona.Model))
+	}
+	if persona.Temperature != nil {
+		opts = append(opts, llm.WithTemperature(*persona.Temperature))
+	}
+	return opts
+}
@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/settings"
+)
+
+func newPersonaHandlers(router *mockRouter, sessions *mockSessionManager) *Handlers {
+	temperature := 0.2
+	personas := []config.PersonaConfig{
+		{Name: "chef", Description: "Cooking help", SystemPrompt: "You are a chef."},
+		{Name: "coder", SystemPrompt: "You write Go.", Provider: "anthropic", Model: "claude-sonnet", Temperature: &temperature},
+	}
+	return NewHandlers(router, sessions, nil, WithPersonas(personas), WithSettings(settings.New(settings.NewMemoryBackend())))
+}
+
+func TestPersonaHandler_PickerAndSet(t *testing.T) {
+	handlers := newPersonaHandlers(&mockRouter{}, &mockSessionManager{})
+	b := &mockBot{}
+
+	handlers.PersonaHandler(context.Background(), b, makeUpdate(1, 1, "/persona"))
+	markup, ok := b.lastMessageParams.ReplyMarkup.(*models.InlineKeyboardMarkup)
+	if !ok || len(markup.InlineKeyboard) != 3 {
+		t.Fatalf("expected a keyboard with two personas and the default, got %+v", b.lastMessageParams.ReplyMarkup)
+	}
+	if markup.InlineKeyboard[0][0].CallbackData != "persona:chef" {
+		t.Errorf("unexpected callback data %q", markup.InlineKeyboard[0][0].CallbackData)
+	}
+
+	handlers.PersonaHandler(context.Background(), b, makeUpdate(1, 1, "/persona pirate"))
+	if !strings.Contains(b.lastMessageParams.Text, "pirate") {
+		t.Errorf("expected unknown persona message, got %q", b.lastMessageParams.Text)
+	}
+
+	handlers.PersonaCallbackHandler(context.Background(), b, makeCallbackUpdate(1, "persona:chef"))
+	if persona, ok := handlers.chatPersona(1); !ok || persona.Name != "chef" {
+		t.Errorf("expected chef to be picked, got %+v", persona)
+	}
+	if b.lastEdit == nil || !strings.Contains(b.lastEdit.Text, "chef") {
+		t.Errorf("expected the picker to be refreshed, got %+v", b.lastEdit)
+	}
+	if _, ok := handlers.chatPersona(2); ok {
+		t.Error("expected other chats to keep the default persona")
+	}
+
+	handlers.PersonaHandler(context.Background(), b, makeUpdate(1, 1, "/persona default"))
+	if _, ok := handlers.chatPersona(1); ok {
+		t.Error("expected the persona to be reset")
+	}
+}
+
+func TestTextMessageHandler_UsesChatPersona(t *testing.T) {
+	router := &mockRouter{response: "ok"}
+	sessions := &mockSessionManager{prompts: map[int64]string{1: "Be brief."}}
+	handlers := newPersonaHandlers(router, sessions)
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+	if router.lastMessages[0].Content != "Be brief." || len(router.lastOpts) != 2 {
+		t.Errorf("expected the user prompt without a persona, got %+v", router.lastMessages)
+	}
+
+	handlers.PersonaHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "/persona coder"))
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+	if router.lastMessages[0].Role != "system" || router.lastMessages[0].Content != "You write Go." {
+		t.Errorf("expected the persona prompt, got %+v", router.lastMessages)
+	}
+	if len(router.lastOpts) != 5 {
+		t.Errorf("expected user, provider, model, temperature and route report options, got %d", len(router.lastOpts))
+	}
+}
@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	// pinMatchLength is how much of a replied-to message is looked for in
+	// the stored history. Long answers arrive in several messages, so only
+	// the start of each is sure to appear in one stored message.
+	pinMatchLength = 200
+	pinPreview     = 80
+)
+
+// PinHandler pins the message the user replied to, so it is kept when the
+// conversation is truncated or summarized.
+func (h *Handlers) PinHandler(ctx context.Context, b any, update *models.Update) {
+	h.setPinned(ctx, b, update, true)
+}
+
+// UnpinHandler unpins the message the user replied to, or the one numbered
+// in /pins.
+func (h *Handlers) UnpinHandler(ctx context.Context, b any, update *models.Update) {
+	h.setPinned(ctx, b, update, false)
+}
+
+func (h *Handlers) setPinned(ctx context.Context, b any, update *models.Update, pinned bool) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+	usage := "pin.usage"
+	if !pinned {
+		usage = "unpin.usage"
+	}
+
+	key := h.sessionKey(update.Message.Chat.ID, user.ID)
+	unlock, err := h.lockSession(ctx, key)
+	if err != nil {
+		log.Printf("Failed to lock session for user %d: %v", user.ID, err)
+		reply(h.tr(user, "pin.error"))
+		return
+	}
+	defer unlock()
+	messages, err := h.sessionManager.Get(key)
+	if err != nil {
+		log.Printf("Failed to load session for user %d: %v", user.ID, err)
+		reply(h.tr(user, "pin.error"))
+		return
+	}
+
+	index := -1
+	switch {
+	case update.Message.ReplyToMessage != nil:
+		if index = findMessage(messages, update.Message.ReplyToMessage); index < 0 {
+			reply(h.tr(user, "pin.not_found"))
+			return
+		}
+	case !pinned && commandArgs(update.Message.Text) != "":
+		if n, err := strconv.Atoi(commandArgs(update.Message.Text)); err == nil {
+			index = pinnedIndex(messages, n)
+		}
+		if index < 0 {
+			reply(h.tr(user, "unpin.unknown"))
+			return
+		}
+	default:
+		reply(h.tr(user, usage))
+		return
+	}
+
+	if messages[index].Pinned == pinned {
+		if pinned {
+			reply(h.tr(user, "pin.already"))
+		} else {
+			reply(h.tr(user, "unpin.not_pinned"))
+		}
+		return
+	}
+	messages[index].Pinned = pinned
+	if err := h.sessionManager.Save(key, messages); err != nil {
+		log.Printf("Failed to save pin for user %d: %v", user.ID, err)
+		reply(h.tr(user, "pin.error"))
+		return
+	}
+	if pinned {
+		reply(h.tr(user, "pin.done"))
+	} else {
+		reply(h.tr(user, "unpin.done"))
+	}
+}
+
+// PinsHandler lists the pinned messages of the current conversation.
+func (h *Handlers) PinsHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	messages, err := h.sessionManager.Get(h.sessionKey(update.Message.Chat.ID, user.ID))
+	if err != nil {
+		log.Printf("Failed to load session for user %d: %v", user.ID, err)
+		reply(h.tr(user, "pin.error"))
+		return
+	}
+	pinned, _ := llm.SplitPinned(messages)
+	if len(pinned) == 0 {
+		reply(h.tr(user, "pins.none"))
+		return
+	}
+
+	lines := []string{h.tr(user, "pins.header")}
+	for i, m := range pinned {
+		author := h.tr(user, "pins.bot")
+		if m.Role == "user" {
+			author = h.tr(user, "pins.you")
+		}
+		preview := truncate(strings.Join(strings.Fields(m.Content), " "), pinPreview)
+		lines = append(lines, h.tr(user, "pins.item", i+1, author, preview))
+	}
+	reply(strings.Join(lines, "\n"))
+}
+
+// pinnedIndex returns the position in history of the nth pinned message,
+// counting from one as /pins does, or -1.
+func pinnedIndex(history []llm.Message, n int) int {
+	for i, m := range history {
+		if !m.Pinned {
+			continue
+		}
+		if n--; n == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// findMessage returns the index of the newest message in history that msg
+// shows, or -1. Answers lose their formatting when rendered, so only letters
+// and digits are compared. A trailing route footer is ignored.
+func findMessage(history []llm.Message, msg *models.Message) int {
+	text := msg.Text
+	if text == "" {
+		text = msg.Caption
+	}
+	candidates := []string{text}
+	if i := strings.LastIndex(text, "\n\n"); i > 0 {
+		candidates = append(candidates, text[:i])
+	}
+
+	for _, candidate := range candidates {
+		needle := []rune(matchKey(candidate))
+		if len(needle) == 0 {
+			continue
+		}
+		if len(needle) > pinMatchLength {
+			needle = needle[:pinMatchLength]
+		}
+		for i := len(history) - 1; i >= 0; i-- {
+			m := history[i]
+			if m.Role != "user" && m.Role != "assistant" {
+				continue
+			}
+			if strings.Contains(matchKey(m.Content), string(needle)) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func matchKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func makePinUpdate(text, replyTo string) *models.Update {
+	update := makeUpdate(1, 1, text)
+	if replyTo != "" {
+		update.Message.ReplyToMessage = &models.Message{Text: replyTo}
+	}
+	return update
+}
+
+func TestPinHandlers(t *testing.T) {
+	sessions := &historySessions{}
+	sessions.saved = []llm.Message{
+		{Role: "user", Content: "My name is Ada"},
+		{Role: "assistant", Content: "Nice to meet you, **Ada**!"},
+		{Role: "user", Content: "What is 2+2?"},
+		{Role: "assistant", Content: "4"},
+	}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1})
+	bot := &mockBot{}
+
+	handlers.PinHandler(context.Background(), bot, makePinUpdate("/pin", "Nice to meet you, Ada!\n\nvia openai/gpt-4o"))
+	if !sessions.saved[1].Pinned || bot.lastMessageParams.Text != "Pinned. This message stays in the conversation until you /unpin it." {
+		t.Fatalf("expected the answer to be pinned, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.PinHandler(context.Background(), bot, makePinUpdate("/pin", "Something else entirely"))
+	if bot.lastMessageParams.Text != "That message is not in your current conversation." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.PinsHandler(context.Background(), bot, makePinUpdate("/pins", ""))
+	if !strings.Contains(bot.lastMessageParams.Text, "1. Bot: Nice to meet you, **Ada**!") {
+		t.Errorf("unexpected pin list %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.UnpinHandler(context.Background(), bot, makePinUpdate("/unpin 2", ""))
+	if bot.lastMessageParams.Text != "No pinned message with that number. See /pins." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+	handlers.UnpinHandler(context.Background(), bot, makePinUpdate("/unpin 1", ""))
+	if sessions.saved[1].Pinned || bot.lastMessageParams.Text != "Unpinned." {
+		t.Errorf("expected the answer to be unpinned, got %q", bot.lastMessageParams.Text)
+	}
+}
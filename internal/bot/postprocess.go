@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+var blankLines = regexp.MustCompile(`\n[ \t]*(?:\n[ \t]*){2,}`)
+
+func WithPostprocess(steps []config.PostprocessConfig) Option {
+	return func(h *Handlers) {
+		h.postprocessing = steps
+	}
+}
+
+// postprocess runs a response through the configured filters in order. A
+// filter that fails is skipped, so the user still gets an answer.
+func (h *Handlers) postprocess(ctx context.Context, userID int64, response string) string {
+	for _, step := range h.postprocessing {
+		switch step.Filter {
+		case "strip_think":
+			response = llm.StripThink(response)
+		case "collapse_blank_lines":
+			response = strings.TrimSpace(blankLines.ReplaceAllString(response, "\n\n"))
+		case "max_length":
+			response = truncate(response, step.MaxLength)
+		case "translate":
+			translated, err := h.translateResponse(ctx, userID, step, response)
+			if err != nil {
+				log.Printf("Failed to translate answer for user %d: %v", userID, err)
+				continue
+			}
+			response = translated
+		}
+	}
+	return response
+}
+
+func (h *Handlers) translateResponse(ctx context.Context, userID int64, step config.PostprocessConfig, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return text, nil
+	}
+	opts := h.userOptions(userID)
+	if step.Provider != "" {
+		opts = append(opts, llm.WithProvider(step.Provider))
+	}
+	if step.Model != "" {
+		opts = append(opts, llm.WithModel(step.Model))
+	}
+
+	target := languageName(step.Language)
+	translated, err := h.router.SendMessage(ctx, []llm.Message{
+		{
+			Role: "system",
+			Content: fmt.Sprintf("Translate the user's text into %s. If it is already in %s, reply with it unchanged. "+
+				"Preserve the original formatting exactly, including line breaks, lists, Markdown, code blocks, URLs and emoji. "+
+				"Reply with the translation only, without notes or explanations.", target, target),
+		},
+		{Role: "user", Content: text},
+	}, opts...)
+	if err != nil {
+		return "", err
+	}
+	if translated == "" {
+		return "", fmt.Errorf("empty translation")
+	}
+	return translated, nil
+}
@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func TestPostprocess(t *testing.T) {
+	router := &mockRouter{response: "Hola"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithPostprocess([]config.PostprocessConfig{
+		{Filter: "strip_think"},
+		{Filter: "collapse_blank_lines"},
+		{Filter: "max_length", MaxLength: 12},
+	}))
+
+	got := handlers.postprocess(context.Background(), 1, "<think>\nhmm, let me see\n</think>\n\nFirst\n\n\n  \n\nSecond line")
+	if got != "First\n\nSecon…" {
+		t.Errorf("unexpected result %q", got)
+	}
+
+	handlers = NewHandlers(router, &mockSessionManager{}, []int64{1}, WithPostprocess([]config.PostprocessConfig{
+		{Filter: "translate", Language: "es", Provider: "ollama"},
+	}))
+	if got := handlers.postprocess(context.Background(), 1, "Hello"); got != "Hola" {
+		t.Errorf("expected the translation, got %q", got)
+	}
+	if len(router.lastMessages) != 2 || !strings.Contains(router.lastMessages[0].Content, "Spanish") || router.lastMessages[1].Content != "Hello" {
+		t.Errorf("unexpected translation request %+v", router.lastMessages)
+	}
+}
+
+func TestTextMessageHandler_PostprocessesAnswer(t *testing.T) {
+	sessions := &mockSessionManager{}
+	router := &mockRouter{response: "<think>private</think>Visible answer"}
+	handlers := NewHandlers(router, sessions, []int64{1}, WithPostprocess([]config.PostprocessConfig{{Filter: "strip_think"}}))
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "hi"))
+
+	if bot.lastMessageParams == nil || strings.Contains(bot.lastMessageParams.Text, "private") || !strings.Contains(bot.lastMessageParams.Text, "Visible answer") {
+		t.Errorf("expected the think block to be stripped, got %+v", bot.lastMessageParams)
+	}
+	if n := len(sessions.saved); n == 0 || sessions.saved[n-1].Content != "Visible answer" {
+		t.Errorf("expected the filtered answer in history, got %+v", sessions.saved)
+	}
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func WithPrefixes(cfg config.PrefixConfig) Option {
+	return func(h *Handlers) {
+		h.prefixes = cfg
+	}
+}
+
+// providerPrefix reads an "@ollama: …" or "!claude …" prefix naming the
+// provider, and optionally the model, for a single message. It returns the
+// message without the prefix and the options that route it. Prefixed
+// messages are not debounced, since each one names its own provider.
+func (h *Handlers) providerPrefix(text string) (string, []llm.RequestOption, bool) {
+	if !h.prefixes.Enabled {
+		return "", nil, false
+	}
+	name, model, rest, ok := parsePrefix(text)
+	if !ok {
+		return "", nil, false
+	}
+
+	route, ok := h.prefixes.Aliases[strings.ToLower(name)]
+	if !ok {
+		route = config.CommandRouteConfig{Provider: strings.ToLower(name)}
+	}
+	if !slices.Contains(h.router.ProviderNames(), route.Provider) {
+		return "", nil, false
+	}
+	if model != "" {
+		route.Model = model
+	}
+
+	opts := []llm.RequestOption{llm.WithProvider(route.Provider)}
+	if route.Model != "" {
+		opts = append(opts, llm.WithModel(route.Model))
+	}
+	return rest, opts, true
+}
+
+// parsePrefix splits "@name/model: rest" or "!name/model rest". Everything
+// after the first slash is the model, so OpenRouter models such as
+// openai/gpt-4o and Ollama tags such as llama3.2:3b work.
+func parsePrefix(text string) (name, model, rest string, ok bool) {
+	if !strings.HasPrefix(text, "@") && !strings.HasPrefix(text, "!") {
+		return "", "", "", false
+	}
+	end := strings.IndexAny(text, " \t\n")
+	if end < 0 {
+		return "", "", "", false
+	}
+	head, rest := text[1:end], strings.TrimSpace(text[end:])
+	if text[0] == '@' {
+		if head, ok = strings.CutSuffix(head, ":"); !ok {
+			return "", "", "", false
+		}
+	}
+	name, model, _ = strings.Cut(head, "/")
+	if name == "" || rest == "" {
+		return "", "", "", false
+	}
+	return name, model, rest, true
+}
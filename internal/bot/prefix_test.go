@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		text      string
+		wantName  string
+		wantModel string
+		wantRest  string
+		wantOK    bool
+	}{
+		{"@ollama: what's 2+2", "ollama", "", "what's 2+2", true},
+		{"!claude explain this", "claude", "", "explain this", true},
+		{"@ollama/llama3.2:3b: hi", "ollama", "llama3.2:3b", "hi", true},
+		{"!openrouter/openai/gpt-4o\nmultiline\ntext", "openrouter", "openai/gpt-4o", "multiline\ntext", true},
+		{"@ollama what's 2+2", "", "", "", false},
+		{"!claude", "", "", "", false},
+		{"hello @ollama: there", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		name, model, rest, ok := parsePrefix(tt.text)
+		if name != tt.wantName || model != tt.wantModel || rest != tt.wantRest || ok != tt.wantOK {
+			t.Errorf("parsePrefix(%q) = %q, %q, %q, %v", tt.text, name, model, rest, ok)
+		}
+	}
+}
+
+func TestProviderPrefix(t *testing.T) {
+	prefixes := config.PrefixConfig{Enabled: true, Aliases: map[string]config.CommandRouteConfig{
+		"claude": {Provider: "anthropic"},
+		"mini":   {Provider: "openai", Model: "gpt-4o-mini"},
+		"llama":  {Provider: "ollama"},
+	}}
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{}, WithPrefixes(prefixes))
+
+	tests := []struct {
+		text     string
+		wantOpts int
+		wantOK   bool
+	}{
+		{"@openai: hi", 1, true},
+		{"!Claude hi", 1, true},
+		{"!mini hi", 2, true},
+		{"@openai/gpt-4o: hi", 2, true},
+		{"!llama hi", 0, false},
+		{"!important read this", 0, false},
+	}
+
+	for _, tt := range tests {
+		rest, opts, ok := handlers.providerPrefix(tt.text)
+		if ok != tt.wantOK || len(opts) != tt.wantOpts || (ok && rest != "hi") {
+			t.Errorf("providerPrefix(%q) = %q, %d options, %v", tt.text, rest, len(opts), ok)
+		}
+	}
+
+	disabled := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{})
+	if _, _, ok := disabled.providerPrefix("@openai: hi"); ok {
+		t.Error("expected prefixes to be off by default")
+	}
+}
+
+func TestTextMessageHandler_ProviderPrefix(t *testing.T) {
+	router := &mockRouter{response: "4"}
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{}, WithPrefixes(config.PrefixConfig{Enabled: true}))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "@anthropic: what's 2+2"))
+
+	if last := router.lastMessages[len(router.lastMessages)-1]; last.Content != "what's 2+2" {
+		t.Errorf("expected the prefix to be removed, got %q", last.Content)
+	}
+	if len(router.lastOpts) != 3 {
+		t.Errorf("expected user, provider and route report options, got %d", len(router.lastOpts))
+	}
+	if len(sessions.saved) != 2 || sessions.saved[0].Content != "what's 2+2" {
+		t.Errorf("unexpected history %+v", sessions.saved)
+	}
+}
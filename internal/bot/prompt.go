@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+	"log"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const maxSystemPromptLength = 4000
+
+// WithSystemPrompt sets the system prompt used for users who have not set
+// their own with /prompt.
+func WithSystemPrompt(prompt string) Option {
+	return func(h *Handlers) {
+		h.systemPrompt = prompt
+	}
+}
+
+func (h *Handlers) PromptHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	userID := update.Message.From.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	prompt := commandArgs(update.Message.Text)
+	switch {
+	case prompt == "":
+		current, err := h.sessionManager.GetPrompt(userID)
+		if err != nil {
+			reply(h.tr(update.Message.From, "prompt.load_error", err))
+			return
+		}
+		if current == "" {
+			reply(h.tr(update.Message.From, "prompt.none"))
+			return
+		}
+		reply(h.tr(update.Message.From, "prompt.current", current))
+		return
+	case prompt == "clear":
+		prompt = ""
+	case len([]rune(prompt)) > maxSystemPromptLength:
+		reply(h.tr(update.Message.From, "prompt.too_long", maxSystemPromptLength))
+		return
+	}
+
+	if err := h.sessionManager.SetPrompt(userID, prompt); err != nil {
+		log.Printf("Failed to save system prompt for user %d: %v", userID, err)
+		reply(h.tr(update.Message.From, "prompt.save_error"))
+		return
+	}
+
+	if prompt == "" {
+		reply(h.tr(update.Message.From, "prompt.cleared"))
+		return
+	}
+	reply(h.tr(update.Message.From, "prompt.saved"))
+}
+
+func (h *Handlers) withUserPrompt(userID int64, messages []llm.Message) []llm.Message {
+	prompt, err := h.sessionManager.GetPrompt(userID)
+	if err != nil {
+		log.Printf("Failed to load system prompt for user %d: %v", userID, err)
+		return messages
+	}
+	if prompt == "" {
+		prompt = h.systemPrompt
+	}
+	if prompt == "" {
+		return messages
+	}
+
+	result := make([]llm.Message, 0, len(messages)+1)
+	result = append(result, llm.Message{Role: "system", Content: prompt})
+	return append(result, messages...)
+}
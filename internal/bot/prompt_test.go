@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPromptHandler_SetShowClear(t *testing.T) {
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1})
+	bot := &mockBot{}
+
+	handlers.PromptHandler(context.Background(), bot, makeUpdate(1, 1, "/prompt You are a helpful chef."))
+	if sessions.prompts[1] != "You are a helpful chef." {
+		t.Errorf("expected prompt to be saved, got %q", sessions.prompts[1])
+	}
+
+	handlers.PromptHandler(context.Background(), bot, makeUpdate(1, 1, "/prompt"))
+	if !strings.Contains(bot.lastMessageParams.Text, "You are a helpful chef.") {
+		t.Errorf("expected current prompt to be shown, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.PromptHandler(context.Background(), bot, makeUpdate(1, 1, "/prompt clear"))
+	if sessions.prompts[1] != "" {
+		t.Errorf("expected prompt to be cleared, got %q", sessions.prompts[1])
+	}
+}
+
+func TestTextMessageHandler_InjectsUserPrompt(t *testing.T) {
+	router := &mockRouter{response: "ok"}
+	sessions := &mockSessionManager{prompts: map[int64]string{1: "Be brief."}}
+	handlers := NewHandlers(router, sessions, []int64{1})
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+
+	if len(router.lastMessages) != 2 || router.lastMessages[0].Role != "system" || router.lastMessages[0].Content != "Be brief." {
+		t.Errorf("expected system prompt before the user message, got %+v", router.lastMessages)
+	}
+	for _, m := range sessions.saved {
+		if m.Role == "system" {
+			t.Error("expected system prompt not to be stored in history")
+		}
+	}
+}
+
+func TestTextMessageHandler_BotSystemPrompt(t *testing.T) {
+	router := &mockRouter{response: "ok"}
+	sessions := &mockSessionManager{prompts: map[int64]string{2: "Be brief."}}
+	handlers := NewHandlers(router, sessions, nil, WithSystemPrompt("You are a chef."))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+	if len(router.lastMessages) != 2 || router.lastMessages[0].Content != "You are a chef." {
+		t.Errorf("expected bot system prompt, got %+v", router.lastMessages)
+	}
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(2, 2, "hello"))
+	if router.lastMessages[0].Content != "Be brief." {
+		t.Errorf("expected user prompt to replace the bot prompt, got %+v", router.lastMessages)
+	}
+}
@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"context"
+	"log"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type ContextSelector interface {
+	Select(ctx context.Context, history []llm.Message, prompt string) ([]llm.Message, error)
+}
+
+type HistoryDeduplicator interface {
+	Deduplicate(ctx context.Context, history []llm.Message) ([]llm.Message, error)
+}
+
+type HistoryCompactor interface {
+	Compact(ctx context.Context, history []llm.Message, opts ...llm.RequestOption) ([]llm.Message, error)
+}
+
+func WithContextSelector(s ContextSelector) Option {
+	return func(h *Handlers) {
+		h.contextSelector = s
+	}
+}
+
+func (h *Handlers) buildContext(ctx context.Context, history []llm.Message, prompt llm.Message) []llm.Message {
+	selected := history
+	if h.contextSelector != nil && len(history) > 0 {
+		pruned, err := h.contextSelector.Select(ctx, history, prompt.Content)
+		if err != nil {
+			log.Printf("Relevance pruning failed, using full history: %v", err)
+		} else {
+			selected = pruned
+		}
+	}
+
+	result := make([]llm.Message, 0, len(selected)+1)
+	result = append(result, selected...)
+	return append(result, prompt)
+}
+
+// WithHistoryDeduplicator leaves repeated turns out of requests. The stored
+// history keeps them.
+func WithHistoryDeduplicator(d HistoryDeduplicator) Option {
+	return func(h *Handlers) {
+		h.deduplicator = d
+	}
+}
+
+func (h *Handlers) deduplicateHistory(ctx context.Context, userID int64, history []llm.Message) []llm.Message {
+	if h.deduplicator == nil || len(history) == 0 {
+		return history
+	}
+	deduplicated, err := h.deduplicator.Deduplicate(ctx, history)
+	if err != nil {
+		log.Printf("Deduplication failed for user %d, using full history: %v", userID, err)
+		return history
+	}
+	return deduplicated
+}
+
+// WithHistoryTokens sends only as much recent history as fits in limit
+// tokens. Zero sends the whole stored history.
+func WithHistoryTokens(limit int) Option {
+	return func(h *Handlers) {
+		h.historyTokens = limit
+	}
+}
+
+// historyWindow trims history to the token window, counted in the tokenizer
+// of the provider that will answer. Providers that report their context size
+// get at most three quarters of it, leaving room for the prompt and reply.
+func (h *Handlers) historyWindow(chatID, userID int64, history []llm.Message) []llm.Message {
+	if h.historyTokens <= 0 {
+		return history
+	}
+
+	provider, err := h.providerFor(userID)
+	if persona, ok := h.chatPersona(chatID); ok && persona.Provider != "" {
+		provider, err = h.router.GetProviderByName(persona.Provider)
+	}
+	if err != nil {
+		return llm.TokenWindow(history, h.historyTokens, "")
+	}
+
+	limit := h.historyTokens
+	if sizer, ok := provider.(llm.ContextSizer); ok && sizer.ContextSize() > 0 {
+		limit = min(limit, sizer.ContextSize()*3/4)
+	}
+	return llm.TokenWindow(history, limit, provider.Name())
+}
+
+// buildRequest assembles everything sent to the model for prompt: the
+// deduplicated and pruned history plus any recalled memories, document
+// excerpts, system prompt and remembered facts, trimmed to the configured
+// input token limit.
+func (h *Handlers) buildRequest(ctx context.Context, chatID, userID int64, history []llm.Message, prompt llm.Message) []llm.Message {
+	history = h.historyWindow(chatID, userID, h.deduplicateHistory(ctx, userID, history))
+	request := h.withRecall(ctx, userID, history, h.buildContext(ctx, history, prompt), prompt.Content)
+	request = h.withChatPrompt(chatID, userID, h.withDocuments(userID, request, prompt.Content))
+	request = h.withFacts(userID, request)
+	request = h.withChatContext(chatID, request)
+	return llm.TrimToTokens(request, h.maxInputTokens)
+}
+
+func WithHistoryCompactor(c HistoryCompactor) Option {
+	return func(h *Handlers) {
+		h.historyCompactor = c
+	}
+}
+
+func (h *Handlers) compactHistory(ctx context.Context, userID int64, history []llm.Message) []llm.Message {
+	if h.historyCompactor == nil {
+		return history
+	}
+	compacted, err := h.historyCompactor.Compact(ctx, history, h.userOptions(userID)...)
+	if err != nil {
+		log.Printf("Summarization failed for user %d, falling back to truncation: %v", userID, err)
+		return history
+	}
+	return compacted
+}
+
+// historyShrinker is implemented by compactors that can summarize history on
+// demand, such as memory.Summarizer.
+type historyShrinker interface {
+	Shrink(ctx context.Context, history []llm.Message, opts ...llm.RequestOption) ([]llm.Message, error)
+}
+
+// shrinkHistory halves history after a request overflowed the model's
+// context window. The older half is summarized when the history compactor
+// supports it and dropped otherwise. It reports false when there is nothing
+// left to remove.
+func (h *Handlers) shrinkHistory(ctx context.Context, userID int64, history []llm.Message) ([]llm.Message, bool) {
+	if shrinker, ok := h.historyCompactor.(historyShrinker); ok {
+		shrunk, err := shrinker.Shrink(ctx, history, h.userOptions(userID)...)
+		if err == nil && len(shrunk) < len(history) {
+			return shrunk, true
+		}
+		if err != nil {
+			log.Printf("Summarization failed for user %d, dropping old messages instead: %v", userID, err)
+		}
+	}
+	shrunk := llm.DropOldest(history, len(history)/2)
+	return shrunk, len(shrunk) < len(history)
+}
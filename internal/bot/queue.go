@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/queue"
+)
+
+func WithOfflineQueue(q queue.Queue) Option {
+	return func(h *Handlers) {
+		h.offlineQueue = q
+	}
+}
+
+func (h *Handlers) enqueueOffline(userID, chatID int64, text string) error {
+	return h.offlineQueue.Push(queue.Item{
+		UserID: userID,
+		ChatID: chatID,
+		Text:   text,
+	})
+}
+
+func (h *Handlers) RunOfflineQueue(ctx context.Context, b any, interval time.Duration) {
+	if h.offlineQueue == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.ProcessOfflineQueue(ctx, b)
+		}
+	}
+}
+
+func (h *Handlers) ProcessOfflineQueue(ctx context.Context, b any) {
+	sender := resolveSender(b)
+	if sender == nil || h.offlineQueue == nil {
+		return
+	}
+
+	n, err := h.offlineQueue.Len()
+	if err != nil {
+		log.Printf("Failed to read offline queue: %v", err)
+		return
+	}
+	if n == 0 {
+		return
+	}
+
+	if err := h.router.Ping(ctx); err != nil {
+		log.Printf("Offline queue: %d message(s) waiting, providers still unavailable: %v", n, err)
+		return
+	}
+
+	items, err := h.offlineQueue.Pending()
+	if err != nil {
+		log.Printf("Failed to read offline queue: %v", err)
+		return
+	}
+
+	log.Printf("Offline queue: providers recovered, processing %d message(s)", len(items))
+
+	// A message is only removed once its answer was sent, so a crash or a
+	// failed send leaves it queued in its place for the next run.
+	for _, item := range items {
+		if err := h.answerQueued(ctx, sender, item); err != nil {
+			log.Printf("Offline queue: failed to answer user %d, keeping the message: %v", item.UserID, err)
+			if err := h.router.Ping(ctx); err != nil {
+				log.Printf("Offline queue: providers unavailable again, stopping: %v", err)
+				return
+			}
+			continue
+		}
+		if err := h.offlineQueue.Remove(item.ID); err != nil {
+			log.Printf("Failed to remove queued message for user %d: %v", item.UserID, err)
+		}
+	}
+}
+
+func (h *Handlers) answerQueued(ctx context.Context, sender BotSender, item queue.Item) error {
+	key := h.sessionKey(item.ChatID, item.UserID)
+	unlock, err := h.lockSession(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	messages, err := h.sessionManager.Get(key)
+	if err != nil {
+		return err
+	}
+
+	messages = append(messages, llm.Stamped(llm.Message{
+		Role:    "user",
+		Content: item.Text,
+	}))
+
+	var route llm.Route
+	opts := h.chatOptions(item.ChatID, item.UserID)
+	opts = append(opts, llm.WithRouteReport(func(r llm.Route) { route = r }))
+	request := h.withChatPrompt(item.ChatID, item.UserID, messages)
+	response, _, err := h.sendContinued(ctx, request, opts)
+	if err != nil {
+		return err
+	}
+	response = h.postprocess(ctx, item.UserID, response)
+
+	answered := response != ""
+	if !answered {
+		response = h.tr(&models.User{ID: item.UserID}, "chat.empty")
+	}
+
+	if _, err := h.sendReply(ctx, sender, &tgbot.SendMessageParams{
+		ChatID: item.ChatID,
+		Text:   h.tr(&models.User{ID: item.UserID}, "chat.queued_answer", response),
+	}); err != nil {
+		return fmt.Errorf("send answer: %w", err)
+	}
+
+	if answered {
+		messages = append(messages, answerMessage(request, response, route))
+		messages = h.compactHistory(ctx, item.UserID, messages)
+		if err := h.sessionManager.Save(key, messages); err != nil {
+			log.Printf("Failed to save session for user %d: %v", item.UserID, err)
+		}
+	}
+	return nil
+}
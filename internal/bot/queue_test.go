@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/queue"
+)
+
+func newTestQueue(t *testing.T) queue.Queue {
+	t.Helper()
+	q, err := queue.NewQueue(filepath.Join(t.TempDir(), "queue.json"))
+	if err != nil {
+		t.Fatalf("NewQueue() returned error: %v", err)
+	}
+	return q
+}
+
+func TestTextMessageHandler_QueuesWhenProvidersDown(t *testing.T) {
+	router := &mockRouter{err: errors.New("openai: connection refused")}
+	sessionMgr := &mockSessionManager{}
+	q := newTestQueue(t)
+	handlers := NewHandlers(router, sessionMgr, []int64{}, WithOfflineQueue(q))
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(12345, 12345, "Hello"))
+
+	if bot.lastMessageParams == nil {
+		t.Fatal("expected message to be sent")
+	}
+	if !strings.Contains(bot.lastMessageParams.Text, "queued") {
+		t.Errorf("expected queued notice, got %q", bot.lastMessageParams.Text)
+	}
+
+	n, _ := q.Len()
+	if n != 1 {
+		t.Errorf("expected 1 queued message, got %d", n)
+	}
+}
+
+func TestTextMessageHandler_NoQueueWithoutOption(t *testing.T) {
+	router := &mockRouter{err: errors.New("openai: connection refused")}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{})
+
+	bot := &mockBot{}
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(12345, 12345, "Hello"))
+
+	if bot.lastMessageParams.Text != "Error communicating with AI" {
+		t.Errorf("expected generic error, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestProcessOfflineQueue_ProvidersRecovered(t *testing.T) {
+	router := &mockRouter{response: "Late answer"}
+	q := newTestQueue(t)
+	q.Push(queue.Item{UserID: 12345, ChatID: 999, Text: "Hello"})
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{}, WithOfflineQueue(q))
+
+	bot := &mockBot{}
+	handlers.ProcessOfflineQueue(context.Background(), bot)
+
+	if bot.lastMessageParams == nil {
+		t.Fatal("expected queued answer to be sent")
+	}
+	if bot.lastMessageParams.ChatID != int64(999) {
+		t.Errorf("expected answer sent to chat 999, got %v", bot.lastMessageParams.ChatID)
+	}
+	if !strings.Contains(bot.lastMessageParams.Text, "Late answer") {
+		t.Errorf("expected answer text, got %q", bot.lastMessageParams.Text)
+	}
+
+	n, _ := q.Len()
+	if n != 0 {
+		t.Errorf("expected queue to be empty, got %d", n)
+	}
+}
+
+func TestProcessOfflineQueue_ProvidersStillDown(t *testing.T) {
+	router := &mockRouter{err: errors.New("connection refused")}
+	q := newTestQueue(t)
+	q.Push(queue.Item{UserID: 12345, ChatID: 999, Text: "Hello"})
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{}, WithOfflineQueue(q))
+
+	bot := &mockBot{}
+	handlers.ProcessOfflineQueue(context.Background(), bot)
+
+	if bot.lastMessageParams != nil {
+		t.Errorf("expected no message while providers are down, got %q", bot.lastMessageParams.Text)
+	}
+
+	n, _ := q.Len()
+	if n != 1 {
+		t.Errorf("expected message to stay queued, got %d", n)
+	}
+}
+
+func TestProcessOfflineQueue_KeepsUnsentAnswers(t *testing.T) {
+	router := &mockRouter{response: "Late answer"}
+	q := newTestQueue(t)
+	q.Push(queue.Item{UserID: 12345, ChatID: 999, Text: "first"})
+	q.Push(queue.Item{UserID: 12345, ChatID: 999, Text: "second"})
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{}, WithOfflineQueue(q))
+
+	bot := &reactingBot{sendErr: errors.New("chat not found")}
+	handlers.ProcessOfflineQueue(context.Background(), bot)
+
+	items, err := q.Pending()
+	if err != nil {
+		t.Fatalf("Pending() returned error: %v", err)
+	}
+	if len(items) != 2 || items[0].Text != "first" || items[1].Text != "second" {
+		t.Errorf("expected both messages to stay queued in order, got %+v", items)
+	}
+}
@@ -0,0 +1,195 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/queue"
+	"github.com/jrswab/helpi/internal/quiet"
+	"github.com/jrswab/helpi/internal/settings"
+)
+
+// quietOff is stored for chats that turned quiet hours off, so the
+// configured ones do not apply to them either.
+const quietOff = "off"
+
+// quietHours holds proactive messages sent during a chat's quiet hours until
+// the window closes.
+type quietHours struct {
+	global *quiet.Hours
+	held   queue.Queue
+	now    func() time.Time
+}
+
+// WithQuietHours holds digests, feed items and scheduled prompts in held
+// while a chat is in its quiet hours. global applies to chats that have not
+// set their own with /quiet and may be empty.
+func WithQuietHours(global string, held queue.Queue) Option {
+	return func(h *Handlers) {
+		q := &quietHours{held: held, now: time.Now}
+		if global != "" {
+			hours, err := quiet.Parse(global)
+			if err != nil {
+				log.Printf("Ignoring quiet hours: %v", err)
+			} else {
+				q.global = &hours
+			}
+		}
+		h.quiet = q
+	}
+}
+
+// QuietHandler shows or sets the quiet hours of the current chat. In groups
+// only group admins can change them.
+func (h *Handlers) QuietHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	chatID := update.Message.Chat.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	if h.quiet == nil || h.settings == nil {
+		reply(h.tr(user, "quiet.disabled"))
+		return
+	}
+
+	arg := strings.ToLower(commandArgs(update.Message.Text))
+	if arg == "" {
+		hours, ok := h.quietWindow(chatID)
+		if !ok {
+			reply(h.tr(user, "quiet.none"))
+			return
+		}
+		reply(h.tr(user, "quiet.status", hours))
+		return
+	}
+
+	value := quietOff
+	if arg != quietOff {
+		hours, err := quiet.Parse(arg)
+		if err != nil {
+			reply(h.tr(user, "quiet.usage"))
+			return
+		}
+		value = hours.String()
+	}
+
+	if isGroupChat(chatID) && !h.isGroupAdmin(ctx, sender, chatID, user.ID) {
+		reply(h.tr(user, "groups.admins_only"))
+		return
+	}
+	if err := h.settings.SetString(chatID, settings.Quiet, value); err != nil {
+		log.Printf("Failed to save quiet hours for chat %d: %v", chatID, err)
+		reply(h.tr(user, "quiet.save_error"))
+		return
+	}
+	if value == quietOff {
+		reply(h.tr(user, "quiet.off"))
+		return
+	}
+	reply(h.tr(user, "quiet.set", value))
+}
+
+// quietWindow returns the quiet hours of chatID, falling back to the
+// configured ones.
+func (h *Handlers) quietWindow(chatID int64) (quiet.Hours, bool) {
+	if h.quiet == nil {
+		return quiet.Hours{}, false
+	}
+	if h.settings != nil {
+		value, err := h.settings.String(chatID, settings.Quiet)
+		if err != nil {
+			log.Printf("Failed to load quiet hours for chat %d: %v", chatID, err)
+		}
+		if value == quietOff {
+			return quiet.Hours{}, false
+		}
+		if value != "" {
+			hours, err := quiet.Parse(value)
+			if err == nil {
+				return hours, true
+			}
+			log.Printf("Ignoring quiet hours of chat %d: %v", chatID, err)
+		}
+	}
+	if h.quiet.global != nil {
+		return *h.quiet.global, true
+	}
+	return quiet.Hours{}, false
+}
+
+func (h *Handlers) isQuiet(chatID int64) bool {
+	hours, ok := h.quietWindow(chatID)
+	return ok && hours.Contains(h.quiet.now())
+}
+
+// deliver sends a message the bot starts on its own, such as a digest or a
+// feed item. During the chat's quiet hours it is held and sent by
+// RunQuietHours once they end.
+func (h *Handlers) deliver(ctx context.Context, sender BotSender, chatID int64, text string) error {
+	if h.hold(chatID, text) {
+		return nil
+	}
+	_, err := h.sendReply(ctx, sender, &tgbot.SendMessageParams{
+		ChatID: chatID,
+		Text:   text,
+	})
+	return err
+}
+
+// hold holds text when chatID is in its quiet hours and reports whether it
+// did.
+func (h *Handlers) hold(chatID int64, text string) bool {
+	if !h.isQuiet(chatID) {
+		return false
+	}
+	if err := h.quiet.held.Push(queue.Item{ChatID: chatID, Text: text}); err != nil {
+		log.Printf("Failed to hold message for chat %d, sending it now: %v", chatID, err)
+		return false
+	}
+	return true
+}
+
+// RunQuietHours sends held messages of chats whose quiet hours have ended.
+// A message is only removed once it was sent, so a failed send or a crash
+// leaves it held for the next run.
+func (h *Handlers) RunQuietHours(ctx context.Context, b any) {
+	sender := resolveSender(b)
+	if sender == nil || h.quiet == nil {
+		return
+	}
+
+	items, err := h.quiet.held.Pending()
+	if err != nil {
+		log.Printf("Quiet hours: %v", err)
+		return
+	}
+
+	for _, item := range items {
+		if h.isQuiet(item.ChatID) {
+			continue
+		}
+		if _, err := h.sendReply(ctx, sender, &tgbot.SendMessageParams{
+			ChatID: item.ChatID,
+			Text:   item.Text,
+		}); err != nil {
+			log.Printf("Failed to send held message to chat %d, keeping it: %v", item.ChatID, err)
+			continue
+		}
+		if err := h.quiet.held.Remove(item.ID); err != nil {
+			log.Printf("Failed to remove held message for chat %d: %v", item.ChatID, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/queue"
+	"github.com/jrswab/helpi/internal/settings"
+)
+
+func newQuietHandlers(t *testing.T, global string) (*Handlers, queue.Queue) {
+	t.Helper()
+	held, err := queue.NewQueue(filepath.Join(t.TempDir(), "held.json"))
+	if err != nil {
+		t.Fatalf("NewQueue() returned error: %v", err)
+	}
+	h := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1},
+		WithQuietHours(global, held), WithSettings(settings.New(settings.NewMemoryBackend())))
+	return h, held
+}
+
+func TestQuietHandler(t *testing.T) {
+	handlers, _ := newQuietHandlers(t, "")
+	b := &mockBot{}
+
+	handlers.QuietHandler(context.Background(), b, makeUpdate(1, 1, "/quiet"))
+	if !strings.Contains(b.lastMessageParams.Text, "No quiet hours") {
+		t.Errorf("expected no quiet hours, got %q", b.lastMessageParams.Text)
+	}
+
+	handlers.QuietHandler(context.Background(), b, makeUpdate(1, 1, "/quiet 9pm"))
+	if !strings.Contains(b.lastMessageParams.Text, "Usage") {
+		t.Errorf("expected usage for an invalid window, got %q", b.lastMessageParams.Text)
+	}
+
+	handlers.QuietHandler(context.Background(), b, makeUpdate(1, 1, "/quiet 22:00-7:00"))
+	if hours, ok := handlers.quietWindow(1); !ok || hours.String() != "22:00-07:00" {
+		t.Errorf("expected 22:00-07:00, got %v (reply %q)", hours, b.lastMessageParams.Text)
+	}
+}
+
+func TestQuietHandler_OffOverridesGlobal(t *testing.T) {
+	handlers, _ := newQuietHandlers(t, "22:00-07:00")
+
+	if _, ok := handlers.quietWindow(1); !ok {
+		t.Fatal("expected the configured quiet hours to apply")
+	}
+	handlers.QuietHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "/quiet off"))
+	if _, ok := handlers.quietWindow(1); ok {
+		t.Error("expected /quiet off to turn the configured quiet hours off")
+	}
+}
+
+func TestDeliver_HoldsDuringQuietHours(t *testing.T) {
+	handlers, held := newQuietHandlers(t, "22:00-07:00")
+	now := time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local)
+	handlers.quiet.now = func() time.Time { return now }
+	b := &mockBot{}
+
+	if err := handlers.deliver(context.Background(), b, 1, "new item"); err != nil {
+		t.Fatalf("deliver() returned error: %v", err)
+	}
+	if len(b.sentMessages) != 0 {
+		t.Fatalf("expected the message to be held, sent %d", len(b.sentMessages))
+	}
+
+	handlers.RunQuietHours(context.Background(), b)
+	if n, _ := held.Len(); n != 1 || len(b.sentMessages) != 0 {
+		t.Fatalf("expected the message to stay held, got %d held and %d sent", n, len(b.sentMessages))
+	}
+
+	now = now.Add(9 * time.Hour)
+	handlers.RunQuietHours(context.Background(), b)
+	if len(b.sentMessages) != 1 || b.sentMessages[0].Text != "new item" {
+		t.Fatalf("expected the held message to be sent, got %+v", b.sentMessages)
+	}
+	if n, _ := held.Len(); n != 0 {
+		t.Errorf("expected no held messages, got %d", n)
+	}
+}
+
+type downBot struct {
+	mockBot
+	down bool
+}
+
+func (b *downBot) SendMessage(ctx context.Context, params *tgbot.SendMessageParams) (*models.Message, error) {
+	if b.down {
+		return nil, errors.New("telegram unreachable")
+	}
+	return b.mockBot.SendMessage(ctx, params)
+}
+
+func TestRunQuietHours_KeepsMessagesThatFailToSend(t *testing.T) {
+	handlers, held := newQuietHandlers(t, "22:00-07:00")
+	handlers.quiet.now = func() time.Time { return time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local) }
+	held.Push(queue.Item{ChatID: 1, Text: "first"})
+	held.Push(queue.Item{ChatID: 1, Text: "second"})
+
+	b := &downBot{down: true}
+	handlers.RunQuietHours(context.Background(), b)
+	if n, _ := held.Len(); n != 2 {
+		t.Fatalf("expected both messages to stay held, got %d", n)
+	}
+
+	b.down = false
+	handlers.RunQuietHours(context.Background(), b)
+	if len(b.sentMessages) != 2 || b.sentMessages[0].Text != "first" {
+		t.Fatalf("expected the held messages in order, got %+v", b.sentMessages)
+	}
+	if n, _ := held.Len(); n != 0 {
+		t.Errorf("expected no held messages, got %d", n)
+	}
+}
@@ -0,0 +1,34 @@
+package bot
+
+import (
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	QuoteRepliesGroups = "groups"
+	QuoteRepliesAlways = "always"
+)
+
+// WithQuoteReplies sends answers as replies to the message they answer, in
+// group chats only or in every chat depending on mode. An empty mode
+// disables it.
+func WithQuoteReplies(mode string) Option {
+	return func(h *Handlers) {
+		h.quoteReplies = mode
+	}
+}
+
+// replyTo returns the reply parameters that quote msg in the answer, or nil
+// when quoting is off for its chat.
+func (h *Handlers) replyTo(msg *models.Message) *models.ReplyParameters {
+	switch {
+	case h.quoteReplies == QuoteRepliesAlways:
+	case h.quoteReplies == QuoteRepliesGroups && isGroupChat(msg.Chat.ID):
+	default:
+		return nil
+	}
+	return &models.ReplyParameters{
+		MessageID:                msg.ID,
+		AllowSendingWithoutReply: true,
+	}
+}
@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChat_QuoteReplies(t *testing.T) {
+	tests := []struct {
+		mode   string
+		chatID int64
+		quoted bool
+	}{
+		{mode: "", chatID: -100, quoted: false},
+		{mode: QuoteRepliesGroups, chatID: -100, quoted: true},
+		{mode: QuoteRepliesGroups, chatID: 1, quoted: false},
+		{mode: QuoteRepliesAlways, chatID: 1, quoted: true},
+	}
+
+	for _, tt := range tests {
+		handlers := NewHandlers(&mockRouter{response: "answer"}, &mockSessionManager{}, []int64{1}, WithQuoteReplies(tt.mode))
+		b := &mockBot{}
+		update := makeUpdate(1, tt.chatID, "question")
+		update.Message.ID = 42
+		handlers.TextMessageHandler(context.Background(), b, update)
+
+		reply := b.lastMessageParams.ReplyParameters
+		if !tt.quoted {
+			if reply != nil {
+				t.Errorf("mode %q in chat %d: expected no quote, got %+v", tt.mode, tt.chatID, reply)
+			}
+			continue
+		}
+		if reply == nil || reply.MessageID != 42 || !reply.AllowSendingWithoutReply {
+			t.Errorf("mode %q in chat %d: expected a quote of message 42, got %+v", tt.mode, tt.chatID, reply)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/i18n"
+)
+
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	notified bool
+}
+
+type RateLimitMiddleware struct {
+	perMinute int
+	burst     int
+	mu        sync.Mutex
+	buckets   map[int64]*bucket
+	now       func() time.Time
+	language  func(*models.User) string
+	applies   func(userID int64) bool
+}
+
+func NewRateLimitMiddleware(perMinute, burst int) *RateLimitMiddleware {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &RateLimitMiddleware{
+		perMinute: perMinute,
+		burst:     burst,
+		buckets:   make(map[int64]*bucket),
+		now:       time.Now,
+	}
+}
+
+// SetLanguage sets how the middleware picks the language of its notices.
+// Without it notices are sent in English.
+func (m *RateLimitMiddleware) SetLanguage(fn func(*models.User) string) {
+	m.language = fn
+}
+
+// SetScope limits the middleware to the users fn reports true for. Without
+// it every user is limited.
+func (m *RateLimitMiddleware) SetScope(fn func(userID int64) bool) {
+	m.applies = fn
+}
+
+func (m *RateLimitMiddleware) Middleware(next bot.HandlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		if update.Message == nil || update.Message.From == nil || (m.applies != nil && !m.applies(update.Message.From.ID)) {
+			next(ctx, b, update)
+			return
+		}
+
+		userID := update.Message.From.ID
+		allowed, wait, notify := m.allow(userID)
+		if allowed {
+			next(ctx, b, update)
+			return
+		}
+
+		log.Printf("[RATE] User %d exceeded rate limit", userID)
+		if notify && b != nil {
+			b.SendMessage(ctx, &bot.SendMessageParams{
+				ChatID: update.Message.Chat.ID,
+				Text:   i18n.T(m.lang(update.Message.From), "chat.rate_limited", int(math.Ceil(wait.Seconds()))),
+			})
+		}
+	}
+}
+
+func (m *RateLimitMiddleware) lang(user *models.User) string {
+	if m.language == nil {
+		return i18n.DefaultLanguage
+	}
+	return m.language(user)
+}
+
+func (m *RateLimitMiddleware) allow(userID int64) (bool, time.Duration, bool) {
+	if m.perMinute <= 0 {
+		return true, 0, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	rate := float64(m.perMinute) / 60
+
+	bk, ok := m.buckets[userID]
+	if !ok {
+		bk = &bucket{tokens: float64(m.burst), last: now}
+		m.buckets[userID] = bk
+	}
+
+	bk.tokens = math.Min(float64(m.burst), bk.tokens+now.Sub(bk.last).Seconds()*rate)
+	bk.last = now
+
+	if bk.tokens >= 1 {
+		bk.tokens--
+		bk.notified = false
+		return true, 0, false
+	}
+
+	wait := time.Duration((1 - bk.tokens) / rate * float64(time.Second))
+	notify := !bk.notified
+	bk.notified = true
+	return false, wait, notify
+}
@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+func TestRateLimitMiddleware_Allow(t *testing.T) {
+	m := NewRateLimitMiddleware(6, 2)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _, _ := m.allow(1); !ok {
+			t.Fatalf("expected burst message %d to be allowed", i+1)
+		}
+	}
+
+	ok, wait, notify := m.allow(1)
+	if ok {
+		t.Fatal("expected message beyond burst to be limited")
+	}
+	if wait != 10*time.Second {
+		t.Errorf("expected 10s cooldown, got %s", wait)
+	}
+	if !notify {
+		t.Error("expected first rejection to notify the user")
+	}
+	if _, _, notify := m.allow(1); notify {
+		t.Error("expected repeated rejections not to notify again")
+	}
+
+	if ok, _, _ := m.allow(2); !ok {
+		t.Error("expected other users to have their own bucket")
+	}
+
+	now = now.Add(10 * time.Second)
+	if ok, _, _ := m.allow(1); !ok {
+		t.Error("expected a token to be refilled after cooldown")
+	}
+}
+
+func TestRateLimitMiddleware_Disabled(t *testing.T) {
+	m := NewRateLimitMiddleware(0, 0)
+	for i := 0; i < 100; i++ {
+		if ok, _, _ := m.allow(1); !ok {
+			t.Fatal("expected disabled limiter to allow everything")
+		}
+	}
+}
+
+func TestRateLimitMiddleware_Middleware(t *testing.T) {
+	m := NewRateLimitMiddleware(60, 1)
+	m.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	calls := 0
+	wrapped := m.Middleware(func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		calls++
+	})
+
+	update := &models.Update{
+		Message: &models.Message{
+			From: &models.User{ID: 1},
+			Chat: models.Chat{ID: 1},
+			Text: "hi",
+		},
+	}
+	wrapped(context.Background(), nil, update)
+	wrapped(context.Background(), nil, update)
+	wrapped(context.Background(), nil, &models.Update{CallbackQuery: &models.CallbackQuery{From: models.User{ID: 1}}})
+
+	if calls != 2 {
+		t.Errorf("expected the second message to be limited and callbacks to pass, got %d calls", calls)
+	}
+}
+
+func TestRateLimitMiddleware_Scope(t *testing.T) {
+	m := NewRateLimitMiddleware(60, 1)
+	m.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	m.SetScope(func(userID int64) bool { return userID == 2 })
+
+	calls := 0
+	wrapped := m.Middleware(func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		calls++
+	})
+	for _, userID := range []int64{1, 1, 2, 2} {
+		wrapped(context.Background(), nil, &models.Update{
+			Message: &models.Message{From: &models.User{ID: userID}, Chat: models.Chat{ID: userID}, Text: "hi"},
+		})
+	}
+
+	if calls != 3 {
+		t.Errorf("expected only user 2 to be limited, got %d calls", calls)
+	}
+}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"context"
+	"log"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+)
+
+type MessageReactor interface {
+	SetMessageReaction(ctx context.Context, params *tgbot.SetMessageReactionParams) (bool, error)
+}
+
+// WithReactions reacts to incoming messages with an emoji while they are
+// processed and again once they are answered or fail.
+func WithReactions(cfg config.ReactionsConfig) Option {
+	return func(h *Handlers) {
+		h.reactions = cfg
+	}
+}
+
+// react replaces the bot's reaction on msg. Failures are only logged: the
+// reaction is a courtesy and must never block the answer.
+func (h *Handlers) react(ctx context.Context, sender BotSender, msg *models.Message, emoji string) {
+	if !h.reactions.Enabled || emoji == "" {
+		return
+	}
+	reactor, ok := sender.(MessageReactor)
+	if !ok {
+		return
+	}
+	_, err := reactor.SetMessageReaction(ctx, &tgbot.SetMessageReactionParams{
+		ChatID:    msg.Chat.ID,
+		MessageID: msg.ID,
+		Reaction: []models.ReactionType{{
+			Type:              models.ReactionTypeTypeEmoji,
+			ReactionTypeEmoji: &models.ReactionTypeEmoji{Type: models.ReactionTypeTypeEmoji, Emoji: emoji},
+		}},
+	})
+	if err != nil {
+		log.Printf("Failed to react to message %d in chat %d: %v", msg.ID, msg.Chat.ID, err)
+	}
+}
@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+)
+
+type reactingBot struct {
+	mockBot
+	reactions []string
+	sendErr   error
+}
+
+func (b *reactingBot) SendMessage(ctx context.Context, params *tgbot.SendMessageParams) (*models.Message, error) {
+	b.mockBot.SendMessage(ctx, params)
+	return nil, b.sendErr
+}
+
+func (b *reactingBot) SetMessageReaction(ctx context.Context, params *tgbot.SetMessageReactionParams) (bool, error) {
+	b.reactions = append(b.reactions, params.Reaction[0].ReactionTypeEmoji.Emoji)
+	return true, nil
+}
+
+func TestTextMessageHandler_Reactions(t *testing.T) {
+	reactions := config.ReactionsConfig{Enabled: true, Processing: "🤔", Done: "👍", Error: "😢"}
+
+	bot := &reactingBot{}
+	handlers := NewHandlers(&mockRouter{providerName: "openai", response: "Hi"}, &mockSessionManager{}, []int64{1}, WithReactions(reactions))
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "Hello"))
+	if len(bot.reactions) != 2 || bot.reactions[0] != "🤔" || bot.reactions[1] != "👍" {
+		t.Errorf("expected processing then done reactions, got %v", bot.reactions)
+	}
+
+	bot = &reactingBot{}
+	handlers = NewHandlers(&mockRouter{providerName: "openai", err: errors.New("boom")}, &mockSessionManager{}, []int64{1}, WithReactions(reactions))
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "Hello"))
+	if len(bot.reactions) != 2 || bot.reactions[1] != "😢" {
+		t.Errorf("expected error reaction, got %v", bot.reactions)
+	}
+
+	bot = &reactingBot{sendErr: errors.New("chat not found")}
+	handlers = NewHandlers(&mockRouter{providerName: "openai", response: "Hi"}, &mockSessionManager{}, []int64{1}, WithReactions(reactions))
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "Hello"))
+	if len(bot.reactions) != 2 || bot.reactions[1] != "😢" {
+		t.Errorf("expected error reaction when the reply could not be sent, got %v", bot.reactions)
+	}
+
+	bot = &reactingBot{}
+	handlers = NewHandlers(&mockRouter{providerName: "openai", response: "Hi"}, &mockSessionManager{}, []int64{1})
+	handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "Hello"))
+	if len(bot.reactions) != 0 {
+		t.Errorf("expected no reactions when disabled, got %v", bot.reactions)
+	}
+}
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/memory"
+)
+
+const recallInstruction = "Relevant excerpts from earlier conversations with this user. Use them only if they help answer the current message."
+
+type LongTermMemory interface {
+	Remember(ctx context.Context, userID int64, user, assistant string) error
+	Recall(ctx context.Context, userID int64, query string) ([]memory.Memory, error)
+}
+
+func WithLongTermMemory(m LongTermMemory) Option {
+	return func(h *Handlers) {
+		h.longTermMemory = m
+	}
+}
+
+func (h *Handlers) withRecall(ctx context.Context, userID int64, history, messages []llm.Message, query string) []llm.Message {
+	if h.longTermMemory == nil || strings.TrimSpace(query) == "" {
+		return messages
+	}
+
+	recalled, err := h.longTermMemory.Recall(ctx, userID, query)
+	if err != nil {
+		log.Printf("Failed to recall memories for user %d: %v", userID, err)
+		return messages
+	}
+
+	inHistory := make(map[string]bool, len(history))
+	for _, m := range history {
+		if m.Role == "user" {
+			inHistory[m.Content] = true
+		}
+	}
+
+	var sb strings.Builder
+	for _, m := range recalled {
+		if inHistory[m.User] {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n\n[%s]\nUser: %s\nAssistant: %s", m.CreatedAt.Format("2006-01-02"), m.User, m.Assistant))
+	}
+	if sb.Len() == 0 {
+		return messages
+	}
+
+	result := make([]llm.Message, 0, len(messages)+1)
+	result = append(result, llm.ContextMessage(recallInstruction+sb.String()))
+	return append(result, messages...)
+}
+
+func (h *Handlers) remember(ctx context.Context, userID int64, user, assistant string) {
+	if h.longTermMemory == nil || strings.TrimSpace(user) == "" {
+		return
+	}
+	if err := h.longTermMemory.Remember(ctx, userID, user, assistant); err != nil {
+		log.Printf("Failed to store memory for user %d: %v", userID, err)
+	}
+}
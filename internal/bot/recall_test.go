@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/memory"
+)
+
+type mockLongTermMemory struct {
+	recalled   []memory.Memory
+	remembered [][2]string
+}
+
+func (m *mockLongTermMemory) Remember(ctx context.Context, userID int64, user, assistant string) error {
+	m.remembered = append(m.remembered, [2]string{user, assistant})
+	return nil
+}
+
+func (m *mockLongTermMemory) Recall(ctx context.Context, userID int64, query string) ([]memory.Memory, error) {
+	return m.recalled, nil
+}
+
+func TestTextMessageHandler_RecallsAndRemembers(t *testing.T) {
+	ltm := &mockLongTermMemory{recalled: []memory.Memory{
+		{User: "my cat is called Miso", Assistant: "cute name", CreatedAt: time.Now()},
+	}}
+	router := &mockRouter{response: "Miso"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithLongTermMemory(ltm))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "what is my cat called?"))
+
+	if router.lastMessages[0].Role != "system" || !strings.Contains(router.lastMessages[0].Content, "Miso") {
+		t.Errorf("expected recalled memory in request, got %+v", router.lastMessages)
+	}
+	if len(ltm.remembered) != 1 || ltm.remembered[0] != [2]string{"what is my cat called?", "Miso"} {
+		t.Errorf("expected exchange to be remembered, got %+v", ltm.remembered)
+	}
+}
+
+func TestWithRecall_SkipsExchangesAlreadyInHistory(t *testing.T) {
+	ltm := &mockLongTermMemory{recalled: []memory.Memory{{User: "hello", Assistant: "hi"}}}
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithLongTermMemory(ltm))
+
+	history := []llm.Message{{Role: "user", Content: "hello"}, {Role: "assistant", Content: "hi"}}
+	got := handlers.withRecall(context.Background(), 1, history, history, "again")
+	if len(got) != len(history) {
+		t.Errorf("expected no recall message, got %+v", got)
+	}
+}
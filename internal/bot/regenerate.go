@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func (h *Handlers) RegenerateHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	opts := h.chatOptions(chatID, user.ID)
+	if arg := commandArgs(update.Message.Text); arg != "" {
+		temperature, err := strconv.ParseFloat(arg, 64)
+		if err != nil || temperature < 0 || temperature > 2 {
+			reply(h.tr(user, "regenerate.usage"))
+			return
+		}
+		opts = append(opts, llm.WithTemperature(temperature))
+	}
+
+	key := h.sessionKey(chatID, user.ID)
+	unlock, err := h.lockSession(ctx, key)
+	if err != nil {
+		reply(h.tr(user, "chat.history_error"))
+		return
+	}
+	defer unlock()
+	messages, err := h.sessionManager.Get(key)
+	if err != nil {
+		reply(h.tr(user, "chat.history_error"))
+		return
+	}
+	n := len(messages)
+	if n < 2 || messages[n-1].Role != "assistant" || messages[n-2].Role != "user" {
+		reply(h.tr(user, "regenerate.nothing"))
+		return
+	}
+
+	if refusal := h.budgetRefusal(user); refusal != "" {
+		reply(refusal)
+		return
+	}
+
+	ctx, done := h.generate(ctx, sender, user.ID, chatID)
+	defer done()
+
+	var route llm.Route
+	opts = append(opts, llm.WithRouteReport(func(r llm.Route) { route = r }))
+	var thought string
+	if h.thinkSpoilers {
+		opts = append(opts, llm.WithThinkReport(func(s string) {
+			if thought == "" {
+				thought = s
+			}
+		}))
+	}
+	request := h.buildRequest(ctx, chatID, user.ID, messages[:n-2], messages[n-2])
+	response, truncated, err := h.sendContinued(ctx, request, opts)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		log.Printf("Regenerate failed for user %d: %v", user.ID, err)
+		if strings.Contains(err.Error(), "context deadline") {
+			reply(h.tr(user, "chat.timeout"))
+		} else if key, ok := providerErrorKeys[llm.Classify(err)]; ok {
+			reply(h.tr(user, key))
+		} else {
+			reply(h.tr(user, "chat.error"))
+		}
+		return
+	}
+	response = h.postprocess(ctx, user.ID, response)
+	if response == "" {
+		reply(h.tr(user, "chat.empty"))
+		return
+	}
+
+	answer := answerMessage(request, response, route)
+	if err := h.sessionManager.ReplaceLast(key, answer); err != nil {
+		log.Printf("Failed to save regenerated answer for user %d: %v", user.ID, err)
+	}
+
+	if _, err := h.sendReply(ctx, sender, &tgbot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        thinkSpoiler(thought) + response,
+		ReplyMarkup: h.answerMarkup(user, answer.ID, truncated),
+	}); err != nil {
+		log.Printf("Failed to send reply to chat %d: %v", chatID, err)
+	}
+	h.warnUsage(ctx, sender, chatID, user)
+}
@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestRegenerateHandler_ReplacesLastAnswer(t *testing.T) {
+	sessions := &mockSessionManager{messages: []llm.Message{
+		{Role: "user", Content: "tell me a joke"},
+		{Role: "assistant", Content: "old joke"},
+	}}
+	router := &mockRouter{response: "new joke"}
+	handlers := NewHandlers(router, sessions, []int64{1})
+
+	bot := &mockBot{}
+	handlers.RegenerateHandler(context.Background(), bot, makeUpdate(1, 1, "/regenerate 1.2"))
+
+	last := router.lastMessages[len(router.lastMessages)-1]
+	if last.Role != "user" || last.Content != "tell me a joke" {
+		t.Errorf("expected the previous prompt to be resent, got %+v", router.lastMessages)
+	}
+	for _, m := range router.lastMessages {
+		if m.Content == "old joke" {
+			t.Errorf("old answer should not be sent: %+v", router.lastMessages)
+		}
+	}
+	if len(sessions.saved) != 2 || sessions.saved[1].Content != "new joke" {
+		t.Errorf("expected answer to be replaced, got %+v", sessions.saved)
+	}
+	if bot.lastMessageParams.Text != "new joke" {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestRegenerateHandler_NothingToRegenerate(t *testing.T) {
+	router := &mockRouter{response: "x"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+
+	bot := &mockBot{}
+	handlers.RegenerateHandler(context.Background(), bot, makeUpdate(1, 1, "/regenerate"))
+
+	if router.lastMessages != nil || bot.lastMessageParams.Text != "There is no answer to regenerate yet." {
+		t.Errorf("unexpected result: request=%v reply=%q", router.lastMessages, bot.lastMessageParams.Text)
+	}
+
+	handlers.RegenerateHandler(context.Background(), bot, makeUpdate(1, 1, "/regenerate hot"))
+	if bot.lastMessageParams.Text == "" || router.lastMessages != nil {
+		t.Errorf("expected usage error, got %q", bot.lastMessageParams.Text)
+	}
+}
@@ -0,0 +1,318 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/i18n"
+)
+
+// HandlerFunc is the signature shared by all Handlers methods.
+type HandlerFunc func(ctx context.Context, b any, update *models.Update)
+
+// Command is a slash command. Built-in commands are described by the
+// cmd.<name> and cmd.<name>.args messages so /help and the Telegram command
+// menu follow the user's language; custom commands carry their own text.
+// Role is the lowest role that may run the command.
+type Command struct {
+	Name        string
+	Description string
+	Args        string
+	Role        Role
+	Custom      bool
+	Handler     HandlerFunc
+
+	// localize, when set, describes a custom command in lang.
+	localize func(lang string) (args, description string)
+}
+
+func (c Command) withRole(role Role) Command {
+	c.Role = role
+	return c
+}
+
+func (c Command) describe(lang string) (args, description string) {
+	if c.localize != nil {
+		return c.localize(lang)
+	}
+	if c.Custom {
+		return c.Args, c.Description
+	}
+	if c.Args != "" {
+		args = i18n.T(lang, c.Args)
+	}
+	return args, i18n.T(lang, c.Description)
+}
+
+// CommandRegistry keeps commands in registration order. Adding a command
+// with a name that is already registered replaces it.
+type CommandRegistry struct {
+	commands []Command
+}
+
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{}
+}
+
+func (r *CommandRegistry) Add(c Command) {
+	for i, existing := range r.commands {
+		if existing.Name == c.Name {
+			r.commands[i] = c
+			return
+		}
+	}
+	r.commands = append(r.commands, c)
+}
+
+func (r *CommandRegistry) Commands() []Command {
+	return append([]Command(nil), r.commands...)
+}
+
+func (r *CommandRegistry) Lookup(name string) (Command, bool) {
+	for _, c := range r.commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// builtin returns a built-in command described by its cmd.<name> messages.
+// Built-in commands are for users unless given another role.
+func builtin(name string, handler HandlerFunc, args bool) Command {
+	c := Command{Name: name, Description: "cmd." + name, Role: RoleUser, Handler: handler}
+	if args {
+		c.Args = "cmd." + name + ".args"
+	}
+	return c
+}
+
+func (h *Handlers) defaultCommands() *CommandRegistry {
+	r := NewCommandRegistry()
+	r.Add(builtin("start", h.StartHandler, false).withRole(RoleGuest))
+	r.Add(builtin("help", h.HelpHandler, false).withRole(RoleGuest))
+	r.Add(builtin("myid", h.MyIDHandler, false).withRole(RoleGuest))
+	r.Add(builtin("whoami", h.WhoAmIHandler, false).withRole(RoleGuest))
+	r.Add(builtin("status", h.StatusHandler, false))
+	r.Add(builtin("stats", h.StatsHandler, false))
+	r.Add(builtin("model", h.ModelHandler, false).withRole(RoleGuest))
+	r.Add(builtin("models", h.ModelsHandler, false).withRole(RoleGuest))
+	r.Add(builtin("switch", h.SwitchHandler, true).withRole(RoleGuest))
+	r.Add(builtin("prompt", h.PromptHandler, true))
+	r.Add(builtin("persona", h.PersonaHandler, true))
+	r.Add(builtin("clear", h.ClearHandler, false).withRole(RoleGuest))
+	r.Add(builtin("pin", h.PinHandler, false))
+	r.Add(builtin("pins", h.PinsHandler, false))
+	r.Add(builtin("unpin", h.UnpinHandler, true))
+	r.Add(builtin("regenerate", h.RegenerateHandler, true))
+	r.Add(builtin("cancel", h.CancelHandler, false).withRole(RoleGuest))
+	r.Add(builtin("new", h.NewThreadHandler, true))
+	r.Add(builtin("threads", h.ThreadsHandler, false))
+	r.Add(builtin("resume", h.ResumeHandler, true))
+	r.Add(builtin("import", h.ImportHandler, true))
+	r.Add(builtin("translate", h.TranslateHandler, true))
+	r.Add(builtin("docs", h.DocsHandler, false))
+	r.Add(builtin("remember", h.RememberHandler, true))
+	r.Add(builtin("memories", h.MemoriesHandler, false))
+	r.Add(builtin("forget", h.ForgetHandler, true))
+	r.Add(builtin("watch", h.WatchHandler, true))
+	r.Add(builtin("unwatch", h.UnwatchHandler, true))
+	r.Add(builtin("lang", h.LangHandler, true).withRole(RoleGuest))
+	r.Add(builtin("settings", h.SettingsHandler, false))
+	r.Add(builtin("feedback", h.FeedbackHandler, true))
+	r.Add(builtin("digest", h.DigestHandler, true))
+	r.Add(builtin("quiet", h.QuietHandler, true))
+	r.Add(builtin("groupmode", h.GroupModeHandler, true))
+
+	r.Add(builtin("feedbacks", h.FeedbackListHandler, false).withRole(RoleAdmin))
+	r.Add(builtin("ha", h.HACommandHandler, true).withRole(RoleAdmin))
+	r.Add(builtin("admin", h.AdminHandler, true).withRole(RoleOwner))
+
+	names := make([]string, 0, len(h.commandRoutes))
+	for name := range h.commandRoutes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		route := h.commandRoutes[name]
+		r.Add(Command{
+			Name:    name,
+			Role:    RoleUser,
+			Custom:  true,
+			Handler: h.RoutedCommandHandler,
+			localize: func(lang string) (string, string) {
+				return i18n.T(lang, "command.route.args"), routeDescription(lang, route.Description, route.Provider, route.Model)
+			},
+		})
+	}
+	return r
+}
+
+// Commands returns the registry used for /help, handler registration and
+// the Telegram command menu. Commands added to it before RegisterCommands
+// is called are served like the built-in ones.
+func (h *Handlers) Commands() *CommandRegistry {
+	return h.commands
+}
+
+// RegisterCommands registers a handler for every command in the registry.
+// Commands match as /name and, as group members send them, as
+// /name@username. Users whose role is too low for a command get a notice
+// instead.
+func (h *Handlers) RegisterCommands(b *tgbot.Bot, username string) {
+	for _, c := range h.commands.Commands() {
+		handler := h.guard(c)
+		b.RegisterHandlerMatchFunc(isCommand(c.Name, username), func(ctx context.Context, b *tgbot.Bot, update *models.Update) {
+			handler(ctx, b, update)
+		})
+	}
+}
+
+// isCommand matches text messages starting with /name, or with
+// /name@username when the command is addressed to the bot.
+func isCommand(name, username string) tgbot.MatchFunc {
+	return func(update *models.Update) bool {
+		if update.Message == nil {
+			return false
+		}
+		text := update.Message.Text
+		for _, e := range update.Message.Entities {
+			if e.Type != models.MessageEntityTypeBotCommand || e.Offset != 0 || e.Length > len(text) {
+				continue
+			}
+			command, mention, addressed := strings.Cut(text[1:e.Length], "@")
+			return command == name && (!addressed || strings.EqualFold(mention, username))
+		}
+		return false
+	}
+}
+
+var roleNotices = map[Role]string{
+	RoleUser:  "help.users_only",
+	RoleAdmin: "help.admin_only",
+	RoleOwner: "help.owner_only",
+}
+
+func (h *Handlers) guard(c Command) HandlerFunc {
+	if c.Role <= RoleGuest {
+		return c.Handler
+	}
+	return func(ctx context.Context, b any, update *models.Update) {
+		if update.Message == nil || update.Message.From == nil {
+			return
+		}
+		if h.commandRole(update.Message.From.ID) < c.Role {
+			if sender := resolveSender(b); sender != nil {
+				sender.SendMessage(ctx, &tgbot.SendMessageParams{
+					ChatID: update.Message.Chat.ID,
+					Text:   h.tr(update.Message.From, roleNotices[c.Role]),
+				})
+			}
+			return
+		}
+		c.Handler(ctx, b, update)
+	}
+}
+
+// helpText lists the commands visible to user, with custom commands in a
+// section of their own.
+func (h *Handlers) helpText(user *models.User) string {
+	lang := h.Language(user)
+	role := RoleUser
+	if user != nil {
+		role = h.commandRole(user.ID)
+	}
+
+	var builtins, custom []string
+	for _, c := range h.commands.Commands() {
+		if c.Role > role {
+			continue
+		}
+		args, description := c.describe(lang)
+		line := "/" + c.Name
+		if args != "" {
+			line += " " + args
+		}
+		line += " - " + description
+		if c.Custom {
+			custom = append(custom, line)
+		} else {
+			builtins = append(builtins, line)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, "help.text") + "\n\n")
+	sb.WriteString(strings.Join(builtins, "\n"))
+	if len(custom) > 0 {
+		sb.WriteString("\n\n" + i18n.T(lang, "help.custom_commands") + "\n")
+		sb.WriteString(strings.Join(custom, "\n"))
+	}
+	sb.WriteString("\n\n" + i18n.T(lang, "help.footer"))
+	return sb.String()
+}
+
+// CommandPublisher is the part of the Telegram API used to fill the
+// client's command menu.
+type CommandPublisher interface {
+	SetMyCommands(ctx context.Context, params *tgbot.SetMyCommandsParams) (bool, error)
+}
+
+var menuCommandName = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// PublishCommands sends the registry to Telegram so commands autocomplete in
+// the client. Every supported language gets its own list. The default list
+// is a user's; owners, admins and guests get theirs in their private chats.
+func (h *Handlers) PublishCommands(ctx context.Context, publisher CommandPublisher) error {
+	for _, lang := range i18n.Supported() {
+		code := lang
+		if lang == i18n.DefaultLanguage {
+			code = ""
+		}
+		if err := h.publishCommands(ctx, publisher, lang, code, &models.BotCommandScopeDefault{}, RoleUser); err != nil {
+			return err
+		}
+		for _, userID := range h.menuMembers() {
+			if err := h.publishCommands(ctx, publisher, lang, code, &models.BotCommandScopeChat{ChatID: userID}, h.commandRole(userID)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *Handlers) publishCommands(ctx context.Context, publisher CommandPublisher, lang, code string, scope models.BotCommandScope, role Role) error {
+	var menu []models.BotCommand
+	for _, c := range h.commands.Commands() {
+		if c.Role > role {
+			continue
+		}
+		if !menuCommandName.MatchString(c.Name) {
+			log.Printf("Command /%s cannot be shown in the Telegram menu", c.Name)
+			continue
+		}
+		_, description := c.describe(lang)
+		if description == "" {
+			description = c.Name
+		}
+		if r := []rune(description); len(r) > 256 {
+			description = string(r[:256])
+		}
+		menu = append(menu, models.BotCommand{Command: c.Name, Description: description})
+	}
+
+	if _, err := publisher.SetMyCommands(ctx, &tgbot.SetMyCommandsParams{
+		Commands:     menu,
+		Scope:        scope,
+		LanguageCode: code,
+	}); err != nil {
+		return fmt.Errorf("failed to publish commands for language %s: %w", lang, err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type commandPublisher struct {
+	calls []*tgbot.SetMyCommandsParams
+}
+
+func (p *commandPublisher) SetMyCommands(ctx context.Context, params *tgbot.SetMyCommandsParams) (bool, error) {
+	p.calls = append(p.calls, params)
+	return true, nil
+}
+
+func TestCommandRegistry_AddReplaces(t *testing.T) {
+	r := NewCommandRegistry()
+	r.Add(Command{Name: "a", Description: "first"})
+	r.Add(Command{Name: "b"})
+	r.Add(Command{Name: "a", Description: "second"})
+
+	commands := r.Commands()
+	if len(commands) != 2 || commands[0].Name != "a" || commands[0].Description != "second" {
+		t.Errorf("expected a to be replaced in place, got %+v", commands)
+	}
+	if _, ok := r.Lookup("b"); !ok {
+		t.Error("expected b to be found")
+	}
+}
+
+func TestHelpText_GeneratedFromRegistry(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{100}))
+	handlers.Commands().Add(Command{Name: "weather", Description: "Ask about the weather", Custom: true})
+
+	help := handlers.helpText(&models.User{ID: 1})
+	for _, want := range []string{"/switch <provider> - Change your AI provider", "/stats - Show your usage statistics", "Custom commands:\n/weather - Ask about the weather", "How it works:"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("expected help to contain %q, got %q", want, help)
+		}
+	}
+	if strings.Contains(help, "/feedbacks") {
+		t.Error("expected admin-only commands to be hidden from users")
+	}
+
+	if help := handlers.helpText(&models.User{ID: 100}); !strings.Contains(help, "/feedbacks - Review user feedback") {
+		t.Errorf("expected admins to see admin-only commands, got %q", help)
+	}
+
+	if help := handlers.helpText(&models.User{ID: 1, LanguageCode: "de"}); !strings.Contains(help, "/switch <anbieter> - KI-Anbieter wechseln") {
+		t.Errorf("expected translated help, got %q", help)
+	}
+}
+
+func TestGuard_RejectsNonAdmins(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{}, WithAdmins([]int64{100}))
+	called := false
+	handler := handlers.guard(Command{Name: "secret", Role: RoleAdmin, Handler: func(ctx context.Context, b any, update *models.Update) {
+		called = true
+	}})
+
+	bot := &mockBot{}
+	handler(context.Background(), bot, makeUpdate(1, 1, "/secret"))
+	if called {
+		t.Error("expected non-admin to be rejected")
+	}
+	if bot.lastMessageParams == nil || !strings.Contains(bot.lastMessageParams.Text, "only available to admins") {
+		t.Errorf("expected admin-only notice, got %+v", bot.lastMessageParams)
+	}
+
+	handler(context.Background(), bot, makeUpdate(100, 100, "/secret"))
+	if !called {
+		t.Error("expected admin to be allowed")
+	}
+}
+
+func TestGuard_Roles(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1},
+		WithOwners([]int64{10}), WithAdmins([]int64{20}), WithGuests([]int64{30}))
+	commands := handlers.Commands()
+
+	tests := []struct {
+		command string
+		userID  int64
+		allowed bool
+	}{
+		{"admin", 10, true},
+		{"admin", 20, false},
+		{"feedbacks", 20, true},
+		{"feedbacks", 1, false},
+		{"stats", 1, true},
+		{"stats", 30, false},
+		{"whoami", 30, true},
+	}
+	for _, tt := range tests {
+		c, ok := commands.Lookup(tt.command)
+		if !ok {
+			t.Fatalf("/%s is not registered", tt.command)
+		}
+		called := false
+		c.Handler = func(ctx context.Context, b any, update *models.Update) { called = true }
+		handlers.guard(c)(context.Background(), &mockBot{}, makeUpdate(tt.userID, tt.userID, "/"+tt.command))
+		if called != tt.allowed {
+			t.Errorf("/%s by user %d: allowed = %v, want %v", tt.command, tt.userID, called, tt.allowed)
+		}
+	}
+
+	if help := handlers.helpText(&models.User{ID: 30}); strings.Contains(help, "/stats") || !strings.Contains(help, "/clear") {
+		t.Errorf("expected guests to see only guest commands, got %q", help)
+	}
+}
+
+func TestPublishCommands(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{}, WithAdmins([]int64{100}))
+	handlers.Commands().Add(Command{Name: "Bad-Name", Description: "skipped", Custom: true})
+	publisher := &commandPublisher{}
+
+	if err := handlers.PublishCommands(context.Background(), publisher); err != nil {
+		t.Fatalf("PublishCommands() returned error: %v", err)
+	}
+
+	var english, admin *tgbot.SetMyCommandsParams
+	for _, call := range publisher.calls {
+		switch scope := call.Scope.(type) {
+		case *models.BotCommandScopeDefault:
+			if call.LanguageCode == "" {
+				english = call
+			}
+		case *models.BotCommandScopeChat:
+			if scope.ChatID == int64(100) && call.LanguageCode == "" {
+				admin = call
+			}
+		}
+	}
+	if english == nil || admin == nil {
+		t.Fatalf("expected default and admin scopes, got %d calls", len(publisher.calls))
+	}
+
+	menu := func(params *tgbot.SetMyCommandsParams) map[string]string {
+		m := make(map[string]string)
+		for _, c := range params.Commands {
+			m[c.Command] = c.Description
+		}
+		return m
+	}
+	if got := menu(english)["clear"]; got != "Clear your conversation history" {
+		t.Errorf("unexpected /clear description %q", got)
+	}
+	if _, ok := menu(english)["feedbacks"]; ok {
+		t.Error("expected admin-only commands to be left out of the default menu")
+	}
+	if _, ok := menu(admin)["feedbacks"]; !ok {
+		t.Error("expected admin-only commands in the admin menu")
+	}
+	if _, ok := menu(english)["Bad-Name"]; ok {
+		t.Error("expected invalid command names to be skipped")
+	}
+}
+
+func TestIsCommand_MatchesAddressedCommands(t *testing.T) {
+	match := isCommand("gpt", "helpi_bot")
+	command := func(text string) *models.Update {
+		length := strings.IndexAny(text+" ", " ")
+		return &models.Update{Message: &models.Message{
+			Text:     text,
+			Entities: []models.MessageEntity{{Type: models.MessageEntityTypeBotCommand, Offset: 0, Length: length}},
+		}}
+	}
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"/gpt hello", true},
+		{"/gpt@helpi_bot hello", true},
+		{"/gpt@Helpi_Bot", true},
+		{"/gpt@other_bot hello", false},
+		{"/gpt4 hello", false},
+		{"/claude hello", false},
+	}
+	for _, tt := range tests {
+		if got := match(command(tt.text)); got != tt.want {
+			t.Errorf("isCommand(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+	if match(&models.Update{Message: &models.Message{Text: "say /gpt"}}) {
+		t.Error("expected text without a leading command not to match")
+	}
+}
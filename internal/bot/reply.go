@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const maxQuotedLength = 4000
+
+// quotedContext describes the message the user replied to, preferring the
+// exact passage when they quoted only part of it.
+func quotedContext(msg *models.Message) string {
+	reply := msg.ReplyToMessage
+	if reply == nil {
+		return ""
+	}
+
+	text := reply.Text
+	if text == "" {
+		text = reply.Caption
+	}
+	part := "message"
+	if msg.Quote != nil && strings.TrimSpace(msg.Quote.Text) != "" {
+		text = msg.Quote.Text
+		part = "part of a message"
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	if runes := []rune(text); len(runes) > maxQuotedLength {
+		text = string(runes[:maxQuotedLength]) + "…"
+	}
+
+	author := "an earlier answer of yours"
+	if reply.From != nil && !reply.From.IsBot {
+		author = "a message from " + displayName(reply.From)
+	}
+	return fmt.Sprintf("The user's next message is a reply to this %s (%s):\n\n%s", part, author, text)
+}
+
+// withReplyContext inserts the quoted message right before the final prompt
+// so the model can resolve references like "explain this part".
+func withReplyContext(msg *models.Message, messages []llm.Message) []llm.Message {
+	quoted := quotedContext(msg)
+	if quoted == "" || len(messages) == 0 {
+		return messages
+	}
+
+	last := len(messages) - 1
+	result := make([]llm.Message, 0, len(messages)+1)
+	result = append(result, messages[:last]...)
+	result = append(result, llm.ContextMessage(quoted))
+	return append(result, messages[last])
+}
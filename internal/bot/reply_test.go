@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestTextMessageHandler_IncludesRepliedToMessage(t *testing.T) {
+	router := &mockRouter{response: "sure"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+
+	update := makeUpdate(1, 1, "explain this")
+	update.Message.ReplyToMessage = &models.Message{
+		Text: "Goroutines are cheap threads.",
+		From: &models.User{ID: 99, IsBot: true},
+	}
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, update)
+
+	n := len(router.lastMessages)
+	if n < 2 {
+		t.Fatalf("expected quoted context before the prompt, got %+v", router.lastMessages)
+	}
+	quoted := router.lastMessages[n-2]
+	if quoted.Role != "system" || !strings.Contains(quoted.Content, "Goroutines are cheap threads.") {
+		t.Errorf("expected replied-to message as context, got %+v", quoted)
+	}
+	if router.lastMessages[n-1].Content != "explain this" {
+		t.Errorf("expected prompt last, got %+v", router.lastMessages[n-1])
+	}
+}
+
+func TestQuotedContext_PrefersPartialQuote(t *testing.T) {
+	msg := &models.Message{
+		ReplyToMessage: &models.Message{Text: "first part. second part.", From: &models.User{FirstName: "Ana"}},
+		Quote:          &models.TextQuote{Text: "second part."},
+	}
+
+	got := quotedContext(msg)
+	if strings.Contains(got, "first part") || !strings.Contains(got, "second part.") {
+		t.Errorf("expected only the quoted passage, got %q", got)
+	}
+	if !strings.Contains(got, "Ana") {
+		t.Errorf("expected author name, got %q", got)
+	}
+}
+
+func TestWithReplyContext_NoReply(t *testing.T) {
+	messages := []llm.Message{{Role: "user", Content: "hi"}}
+
+	if got := withReplyContext(&models.Message{Text: "hi"}, messages); len(got) != 1 {
+		t.Errorf("expected request unchanged, got %+v", got)
+	}
+}
@@ -0,0 +1,108 @@
+package bot
+
+import "slices"
+
+// Role decides what a user may do. Roles are ordered, so each role can do
+// everything the roles below it can.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleGuest
+	RoleUser
+	RoleAdmin
+	RoleOwner
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleGuest:
+		return "guest"
+	case RoleUser:
+		return "user"
+	case RoleAdmin:
+		return "admin"
+	case RoleOwner:
+		return "owner"
+	}
+	return "none"
+}
+
+// Access lists a bot's users by role.
+type Access struct {
+	Owners []int64
+	Admins []int64
+	Users  []int64
+	Guests []int64
+	// Usernames are users allowed by their Telegram username.
+	Usernames []string
+}
+
+func WithOwners(owners []int64) Option {
+	return func(h *Handlers) {
+		h.owners = owners
+	}
+}
+
+func WithGuests(guests []int64) Option {
+	return func(h *Handlers) {
+		h.guests = guests
+	}
+}
+
+// Role returns the highest role userID is listed under. Owners of allowed
+// usernames are users. In development mode, with nobody configured under
+// any role, everyone else is a user.
+func (h *Handlers) Role(userID int64) Role {
+	h.authMu.RLock()
+	defer h.authMu.RUnlock()
+
+	switch {
+	case slices.Contains(h.owners, userID):
+		return RoleOwner
+	case slices.Contains(h.admins, userID):
+		return RoleAdmin
+	case slices.Contains(h.allowedUsers, userID), h.allowedByUsername(userID):
+		return RoleUser
+	case slices.Contains(h.guests, userID):
+		return RoleGuest
+	case h.openAccess():
+		return RoleUser
+	}
+	return RoleNone
+}
+
+// IsGuest reports whether userID only has guest access.
+func (h *Handlers) IsGuest(userID int64) bool {
+	return h.Role(userID) == RoleGuest
+}
+
+// commandRole is the role commands are checked against. Without any owners
+// configured admins act as owners, so configs from before roles keep their
+// admin commands.
+func (h *Handlers) commandRole(userID int64) Role {
+	role := h.Role(userID)
+	h.authMu.RLock()
+	defer h.authMu.RUnlock()
+	if role == RoleAdmin && len(h.owners) == 0 {
+		return RoleOwner
+	}
+	return role
+}
+
+// menuMembers returns everyone whose commands differ from a user's, who
+// each get a command menu of their own.
+func (h *Handlers) menuMembers() []int64 {
+	h.authMu.RLock()
+	defer h.authMu.RUnlock()
+	return mergeIDs(mergeIDs(slices.Clone(h.owners), h.admins), h.guests)
+}
+
+func mergeIDs(ids, more []int64) []int64 {
+	for _, id := range more {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRole(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1, 20},
+		WithOwners([]int64{10}), WithAdmins([]int64{20}), WithGuests([]int64{30}))
+
+	tests := []struct {
+		userID int64
+		want   Role
+	}{
+		{10, RoleOwner},
+		{20, RoleAdmin},
+		{1, RoleUser},
+		{30, RoleGuest},
+		{99, RoleNone},
+	}
+	for _, tt := range tests {
+		if got := handlers.Role(tt.userID); got != tt.want {
+			t.Errorf("Role(%d) = %s, want %s", tt.userID, got, tt.want)
+		}
+	}
+
+	if !handlers.checkAuth(context.Background(), &mockBot{}, makeUpdate(30, 30, "hi")) {
+		t.Error("expected guests to be authorized")
+	}
+	if handlers.checkAuth(context.Background(), &mockBot{}, makeUpdate(99, 99, "hi")) {
+		t.Error("expected unknown users to be rejected")
+	}
+}
+
+func TestCommandRole_AdminsActAsOwnersWithoutOwners(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1}, WithAdmins([]int64{20}))
+	if got := handlers.commandRole(20); got != RoleOwner {
+		t.Errorf("expected admins to act as owners, got %s", got)
+	}
+
+	handlers.UpdateAccess(Access{Owners: []int64{10}, Admins: []int64{20}, Users: []int64{1}})
+	if got := handlers.commandRole(20); got != RoleAdmin {
+		t.Errorf("expected admins to stay admins once owners are configured, got %s", got)
+	}
+}
+
+func TestRole_DevModeIsClosedByGuests(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{})
+	if got := handlers.Role(99); got != RoleUser {
+		t.Errorf("expected everyone to be a user in development mode, got %s", got)
+	}
+
+	handlers.UpdateAccess(Access{Guests: []int64{30}})
+	if got := handlers.Role(99); got != RoleNone {
+		t.Errorf("expected listing guests to end development mode, got %s", got)
+	}
+}
+
+func TestRole_DevModeIsClosedByOwnersAndAdmins(t *testing.T) {
+	for _, opt := range []Option{WithOwners([]int64{10}), WithAdmins([]int64{20})} {
+		handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{}, opt)
+		if got := handlers.Role(99); got != RoleNone {
+			t.Errorf("expected an owner or admin to end development mode, got %s", got)
+		}
+		if handlers.checkAuth(context.Background(), &mockBot{}, makeUpdate(99, 99, "hi")) {
+			t.Error("expected unknown users to be rejected")
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// WithShowRoute appends the provider and model that answered to each reply.
+func WithShowRoute(show bool) Option {
+	return func(h *Handlers) {
+		h.showRoute = show
+	}
+}
+
+// WithShowReasoning sends the model's reasoning summary, when the provider
+// returns one, ahead of the answer.
+func WithShowReasoning(show bool) Option {
+	return func(h *Handlers) {
+		h.showReasoning = show
+	}
+}
+
+// WithThinkSpoilers sends the <think> reasoning of providers set to
+// think: spoiler as spoilers above the answer.
+func WithThinkSpoilers(show bool) Option {
+	return func(h *Handlers) {
+		h.thinkSpoilers = show
+	}
+}
+
+// WithThinkingStatus posts a message while a model with extended thinking
+// is thinking.
+func WithThinkingStatus(show bool) Option {
+	return func(h *Handlers) {
+		h.thinkingStatus = show
+	}
+}
+
+func (h *Handlers) routeFooter(user *models.User, route llm.Route) string {
+	if !h.showRoute || route.Provider == "" {
+		return ""
+	}
+	target := route.Provider
+	if route.Model != "" {
+		target += "/" + route.Model
+	}
+	if route.Rule != "" {
+		return "\n\n" + h.tr(user, "chat.route_rule", target, route.Rule)
+	}
+	return "\n\n" + h.tr(user, "chat.route", target)
+}
+
+// WithOfflineNotice marks answers from the offline fallback with a notice.
+func WithOfflineNotice(enabled bool) Option {
+	return func(h *Handlers) {
+		h.offline = enabled
+	}
+}
+
+// offlineNotice warns that an answer came from the offline fallback.
+func (h *Handlers) offlineNotice(user *models.User, route llm.Route) string {
+	if !route.Offline {
+		return ""
+	}
+	return h.tr(user, "chat.offline") + "\n\n"
+}
@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestRouteFooter(t *testing.T) {
+	user := &models.User{ID: 1}
+	route := llm.Route{Rule: "code", Provider: "anthropic", Model: "claude"}
+
+	hidden := NewHandlers(&mockRouter{}, &mockSessionManager{}, nil)
+	if got := hidden.routeFooter(user, route); got != "" {
+		t.Errorf("expected no footer by default, got %q", got)
+	}
+
+	shown := NewHandlers(&mockRouter{}, &mockSessionManager{}, nil, WithShowRoute(true))
+	if got := shown.routeFooter(user, route); got != "\n\nAnswered by anthropic/claude (rule: code)" {
+		t.Errorf("unexpected footer %q", got)
+	}
+	if got := shown.routeFooter(user, llm.Route{Provider: "openai"}); got != "\n\nAnswered by openai" {
+		t.Errorf("unexpected footer %q", got)
+	}
+}
+
+func TestOfflineNotice(t *testing.T) {
+	user := &models.User{ID: 1}
+	h := NewHandlers(&mockRouter{}, &mockSessionManager{}, nil, WithOfflineNotice(true))
+
+	if got := h.offlineNotice(user, llm.Route{Provider: "openai"}); got != "" {
+		t.Errorf("expected no notice for a cloud answer, got %q", got)
+	}
+	if got := h.offlineNotice(user, llm.Route{Provider: "ollama", Offline: true}); !strings.HasPrefix(got, "📴") || !strings.HasSuffix(got, "\n\n") {
+		t.Errorf("unexpected notice %q", got)
+	}
+}
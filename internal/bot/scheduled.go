@@ -0,0 +1,190 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/batch"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func WithBatchTracker(t batch.Tracker) Option {
+	return func(h *Handlers) {
+		h.batchTracker = t
+	}
+}
+
+func (h *Handlers) RunScheduledPrompt(ctx context.Context, b any, sp config.ScheduledPromptConfig) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	provider, err := h.scheduledProvider(sp)
+	if err != nil {
+		log.Printf("Scheduled prompt %s: %v", sp.Name, err)
+		return
+	}
+
+	messages := []llm.Message{{Role: "user", Content: sp.Prompt}}
+	if h.budget != nil {
+		if err := h.budget.Allow(0); err != nil {
+			log.Printf("Scheduled prompt %s skipped: %v", sp.Name, err)
+			return
+		}
+	}
+
+	if sp.Priority == "batch" && h.batchTracker != nil {
+		if h.submitBatch(ctx, provider, sp, messages) {
+			return
+		}
+		log.Printf("Scheduled prompt %s: falling back to immediate request", sp.Name)
+	}
+
+	redacted, originals := h.redact(provider.Name(), messages)
+	response, err := provider.SendMessage(ctx, redacted)
+	if err != nil {
+		log.Printf("Scheduled prompt %s failed: %v", sp.Name, err)
+		return
+	}
+	if response == "" {
+		log.Printf("Scheduled prompt %s: empty response", sp.Name)
+		return
+	}
+	h.charge(provider, redacted, response)
+	response = llm.Restore(response, originals)
+
+	if err := h.deliver(ctx, sender, sp.ChatID, response); err != nil {
+		log.Printf("Scheduled prompt %s: failed to send response: %v", sp.Name, err)
+	}
+}
+
+func (h *Handlers) scheduledProvider(sp config.ScheduledPromptConfig) (llm.Provider, error) {
+	if sp.Provider != "" {
+		return h.router.GetProviderByName(sp.Provider)
+	}
+	return h.router.GetProvider()
+}
+
+// redact masks personal data in messages sent straight to provider, which
+// would otherwise bypass the router's redaction.
+func (h *Handlers) redact(provider string, messages []llm.Message) ([]llm.Message, map[string]string) {
+	if rd, ok := h.router.(llm.Redactor); ok {
+		return rd.Redact(provider, messages)
+	}
+	return messages, nil
+}
+
+func (h *Handlers) submitBatch(ctx context.Context, provider llm.Provider, sp config.ScheduledPromptConfig, messages []llm.Message) bool {
+	bp, ok := provider.(llm.BatchProvider)
+	if !ok {
+		log.Printf("Scheduled prompt %s: provider %s does not support batch requests", sp.Name, provider.Name())
+		return false
+	}
+
+	redacted, originals := h.redact(provider.Name(), messages)
+	batchID, err := bp.SubmitBatch(ctx, []llm.BatchRequest{{CustomID: sp.Name, Messages: redacted}})
+	if err != nil {
+		log.Printf("Scheduled prompt %s: failed to submit batch: %v", sp.Name, err)
+		return false
+	}
+	h.charge(provider, redacted, "")
+
+	if err := h.batchTracker.Add(batch.Job{
+		Name:       sp.Name,
+		Provider:   provider.Name(),
+		BatchID:    batchID,
+		ChatID:     sp.ChatID,
+		Redactions: originals,
+	}); err != nil {
+		log.Printf("Scheduled prompt %s: failed to track batch %s: %v", sp.Name, batchID, err)
+		return true
+	}
+
+	log.Printf("Scheduled prompt %s: submitted batch %s to %s", sp.Name, batchID, provider.Name())
+	return true
+}
+
+func (h *Handlers) RunBatchPoller(ctx context.Context, b any, interval time.Duration) {
+	if h.batchTracker == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.PollBatchJobs(ctx, b)
+		}
+	}
+}
+
+func (h *Handlers) PollBatchJobs(ctx context.Context, b any) {
+	sender := resolveSender(b)
+	if sender == nil || h.batchTracker == nil {
+		return
+	}
+
+	jobs, err := h.batchTracker.Pending()
+	if err != nil {
+		log.Printf("Failed to read batch jobs: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		provider, err := h.router.GetProviderByName(job.Provider)
+		if err != nil {
+			log.Printf("Batch %s: %v", job.BatchID, err)
+			continue
+		}
+
+		bp, ok := provider.(llm.BatchProvider)
+		if !ok {
+			log.Printf("Batch %s: provider %s does not support batch requests", job.BatchID, job.Provider)
+			continue
+		}
+
+		results, done, err := bp.BatchResults(ctx, job.BatchID)
+		if !done {
+			if err != nil {
+				log.Printf("Batch %s: %v", job.BatchID, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			log.Printf("Batch %s failed: %v", job.BatchID, err)
+			sender.SendMessage(ctx, &tgbot.SendMessageParams{
+				ChatID: job.ChatID,
+				Text:   h.tr(&models.User{ID: job.ChatID}, "scheduled.incomplete", job.Name),
+			})
+		}
+
+		for _, result := range results {
+			h.charge(provider, nil, result.Content)
+			text := llm.Restore(result.Content, job.Redactions)
+			if result.Error != "" {
+				log.Printf("Batch %s: scheduled prompt %s failed: %s", job.BatchID, job.Name, result.Error)
+				text = h.tr(&models.User{ID: job.ChatID}, "scheduled.failed", job.Name)
+			}
+			if text == "" {
+				continue
+			}
+			if err := h.deliver(ctx, sender, job.ChatID, text); err != nil {
+				log.Printf("Batch %s: failed to send result: %v", job.BatchID, err)
+			}
+		}
+
+		if err := h.batchTracker.Remove(job.BatchID); err != nil {
+			log.Printf("Failed to remove batch %s: %v", job.BatchID, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/batch"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type mockBatchProvider struct {
+	mockProvider
+	response  string
+	sent      []llm.Message
+	submitted []llm.BatchRequest
+	results   []llm.BatchResult
+	done      bool
+}
+
+func (m *mockBatchProvider) SendMessage(ctx context.Context, messages []llm.Message) (string, error) {
+	m.sent = messages
+	return m.response, nil
+}
+
+func (m *mockBatchProvider) SubmitBatch(ctx context.Context, requests []llm.BatchRequest) (string, error) {
+	m.submitted = append(m.submitted, requests...)
+	return "batch_1", nil
+}
+
+func (m *mockBatchProvider) BatchResults(ctx context.Context, batchID string) ([]llm.BatchResult, bool, error) {
+	return m.results, m.done, nil
+}
+
+func newTestTracker(t *testing.T) batch.Tracker {
+	t.Helper()
+	tr, err := batch.NewTracker(filepath.Join(t.TempDir(), "batches.json"))
+	if err != nil {
+		t.Fatalf("NewTracker() returned error: %v", err)
+	}
+	return tr
+}
+
+func TestRunScheduledPrompt_Normal(t *testing.T) {
+	provider := &mockBatchProvider{mockProvider: mockProvider{name: "openai"}, response: "Good morning"}
+	handlers := NewHandlers(&mockRouter{provider: provider}, &mockSessionManager{}, []int64{})
+
+	bot := &mockBot{}
+	handlers.RunScheduledPrompt(context.Background(), bot, config.ScheduledPromptConfig{
+		Name: "morning", ChatID: 42, Prompt: "Say good morning",
+	})
+
+	if bot.lastMessageParams == nil {
+		t.Fatal("expected message to be sent")
+	}
+	if bot.lastMessageParams.ChatID != int64(42) || bot.lastMessageParams.Text != "Good morning" {
+		t.Errorf("unexpected message: %+v", bot.lastMessageParams)
+	}
+	if len(provider.submitted) != 0 {
+		t.Error("expected no batch submission for normal priority")
+	}
+}
+
+func TestRunScheduledPrompt_BatchThenPoll(t *testing.T) {
+	provider := &mockBatchProvider{mockProvider: mockProvider{name: "openai"}}
+	tracker := newTestTracker(t)
+	handlers := NewHandlers(&mockRouter{provider: provider}, &mockSessionManager{}, []int64{}, WithBatchTracker(tracker))
+
+	bot := &mockBot{}
+	handlers.RunScheduledPrompt(context.Background(), bot, config.ScheduledPromptConfig{
+		Name: "digest", ChatID: 42, Prompt: "Summarize", Priority: "batch",
+	})
+
+	if bot.lastMessageParams != nil {
+		t.Errorf("expected no immediate reply for batch priority, got %q", bot.lastMessageParams.Text)
+	}
+	if len(provider.submitted) != 1 || provider.submitted[0].CustomID != "digest" {
+		t.Fatalf("expected one batch request for digest, got %+v", provider.submitted)
+	}
+
+	jobs, _ := tracker.Pending()
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 tracked job, got %d", len(jobs))
+	}
+
+	handlers.PollBatchJobs(context.Background(), bot)
+	if bot.lastMessageParams != nil {
+		t.Error("expected nothing delivered while batch is running")
+	}
+
+	provider.done = true
+	provider.results = []llm.BatchResult{{CustomID: "digest", Content: "Batch summary"}}
+	handlers.PollBatchJobs(context.Background(), bot)
+
+	if bot.lastMessageParams == nil || bot.lastMessageParams.Text != "Batch summary" {
+		t.Fatalf("expected batch result to be delivered, got %+v", bot.lastMessageParams)
+	}
+
+	jobs, _ = tracker.Pending()
+	if len(jobs) != 0 {
+		t.Errorf("expected tracker to be empty after delivery, got %d", len(jobs))
+	}
+}
+
+func TestRunScheduledPrompt_BatchUnsupportedFallsBack(t *testing.T) {
+	router := &mockRouter{response: "ignored"}
+	tracker := newTestTracker(t)
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{}, WithBatchTracker(tracker))
+
+	bot := &mockBot{}
+	handlers.RunScheduledPrompt(context.Background(), bot, config.ScheduledPromptConfig{
+		Name: "digest", ChatID: 42, Prompt: "Summarize", Priority: "batch",
+	})
+
+	jobs, _ := tracker.Pending()
+	if len(jobs) != 0 {
+		t.Errorf("expected no tracked jobs for unsupported provider, got %d", len(jobs))
+	}
+}
+
+// redactingRouter masks every "secret" as [SECRET_1].
+type redactingRouter struct {
+	mockRouter
+}
+
+func (r *redactingRouter) Redact(provider string, messages []llm.Message) ([]llm.Message, map[string]string) {
+	redacted := make([]llm.Message, len(messages))
+	for i, m := range messages {
+		m.Content = strings.ReplaceAll(m.Content, "secret", "[SECRET_1]")
+		redacted[i] = m
+	}
+	return redacted, map[string]string{"[SECRET_1]": "secret"}
+}
+
+func TestRunScheduledPrompt_Redacts(t *testing.T) {
+	provider := &mockBatchProvider{mockProvider: mockProvider{name: "openai"}, response: "Got [SECRET_1]"}
+	tracker := newTestTracker(t)
+	handlers := NewHandlers(&redactingRouter{mockRouter{provider: provider}}, &mockSessionManager{}, []int64{}, WithBatchTracker(tracker))
+
+	bot := &mockBot{}
+	handlers.RunScheduledPrompt(context.Background(), bot, config.ScheduledPromptConfig{
+		Name: "morning", ChatID: 42, Prompt: "Repeat secret",
+	})
+	if got := provider.sent[0].Content; got != "Repeat [SECRET_1]" {
+		t.Errorf("expected prompt to be redacted, got %q", got)
+	}
+	if bot.lastMessageParams == nil || bot.lastMessageParams.Text != "Got secret" {
+		t.Fatalf("expected restored reply, got %+v", bot.lastMessageParams)
+	}
+
+	handlers.RunScheduledPrompt(context.Background(), bot, config.ScheduledPromptConfig{
+		Name: "digest", ChatID: 42, Prompt: "Repeat secret", Priority: "batch",
+	})
+	if got := provider.submitted[0].Messages[0].Content; got != "Repeat [SECRET_1]" {
+		t.Errorf("expected batch prompt to be redacted, got %q", got)
+	}
+
+	provider.done = true
+	provider.results = []llm.BatchResult{{CustomID: "digest", Content: "Batch [SECRET_1]"}}
+	handlers.PollBatchJobs(context.Background(), bot)
+	if bot.lastMessageParams.Text != "Batch secret" {
+		t.Errorf("expected restored batch result, got %q", bot.lastMessageParams.Text)
+	}
+}
@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jrswab/helpi/internal/session"
+)
+
+// sessionLocks serializes the requests on each session within the process,
+// so one that reads the history, waits for the model and saves the answer
+// does not overwrite an exchange saved meanwhile.
+type sessionLocks struct {
+	mu    sync.Mutex
+	locks map[int64]*sessionLock
+}
+
+type sessionLock struct {
+	held    chan struct{}
+	waiters int
+}
+
+// lock blocks until key is free or ctx is done.
+func (l *sessionLocks) lock(ctx context.Context, key int64) (func(), error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[int64]*sessionLock)
+	}
+	entry := l.locks[key]
+	if entry == nil {
+		entry = &sessionLock{held: make(chan struct{}, 1)}
+		l.locks[key] = entry
+	}
+	entry.waiters++
+	l.mu.Unlock()
+
+	release := func() {
+		l.mu.Lock()
+		if entry.waiters--; entry.waiters == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+
+	select {
+	case entry.held <- struct{}{}:
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+	return func() {
+		<-entry.held
+		release()
+	}, nil
+}
+
+// lockSession holds the session key until the returned function is called,
+// which may be called more than once. Managers implementing session.Locker
+// also hold it against other processes sharing the store.
+func (h *Handlers) lockSession(ctx context.Context, key int64) (func(), error) {
+	unlock, err := h.sessionLocks.lock(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	locker, ok := h.sessionManager.(session.Locker)
+	if !ok {
+		return sync.OnceFunc(unlock), nil
+	}
+	unlockStore, err := locker.Lock(ctx, key)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	return sync.OnceFunc(func() {
+		unlockStore()
+		unlock()
+	}), nil
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// gatedRouter holds its first answer until release is closed.
+type gatedRouter struct {
+	mockRouter
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *gatedRouter) SendMessage(ctx context.Context, messages []llm.Message, opts ...llm.RequestOption) (string, error) {
+	first := false
+	r.once.Do(func() { first = true })
+	if first {
+		close(r.started)
+		<-r.release
+	}
+	return "answer", nil
+}
+
+// storeSessions keeps what was saved, safe for concurrent requests.
+type storeSessions struct {
+	mockSessionManager
+	mu sync.Mutex
+}
+
+func (s *storeSessions) Get(userID int64) ([]llm.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]llm.Message(nil), s.saved...), nil
+}
+
+func (s *storeSessions) Save(userID int64, messages []llm.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved = messages
+	return nil
+}
+
+func TestChat_ConcurrentRequestsKeepBothExchanges(t *testing.T) {
+	router := &gatedRouter{started: make(chan struct{}), release: make(chan struct{})}
+	sessions := &storeSessions{}
+	handlers := NewHandlers(router, sessions, nil)
+	bot := &lockedBot{}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "first"))
+	}()
+	<-router.started
+	go func() {
+		defer wg.Done()
+		handlers.TextMessageHandler(context.Background(), bot, makeUpdate(1, 1, "second"))
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(router.release)
+	wg.Wait()
+
+	if len(sessions.saved) != 4 || sessions.saved[0].Content != "first" || sessions.saved[2].Content != "second" {
+		t.Errorf("expected both exchanges in order, got %+v", sessions.saved)
+	}
+}
+
+func TestSessionLocks_WaitingHonorsContext(t *testing.T) {
+	var locks sessionLocks
+	unlock, err := locks.lock(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("lock() returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := locks.lock(ctx, 1); err == nil {
+		t.Fatal("expected waiting for a held session to stop with the context")
+	}
+	if other, err := locks.lock(context.Background(), 2); err != nil {
+		t.Fatalf("expected other sessions to be free, got %v", err)
+	} else {
+		other()
+	}
+
+	unlock()
+	if again, err := locks.lock(context.Background(), 1); err != nil {
+		t.Fatalf("expected the session to be free after unlock, got %v", err)
+	} else {
+		again()
+	}
+	if len(locks.locks) != 0 {
+		t.Errorf("expected released sessions to be forgotten, got %d", len(locks.locks))
+	}
+}
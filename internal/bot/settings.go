@@ -0,0 +1,268 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/i18n"
+	"github.com/jrswab/helpi/internal/settings"
+)
+
+// Callback data of the /settings menu is settings:<page>, or
+// settings:<page>:<value> for a choice on that page. The empty page is the
+// overview.
+const (
+	settingsCallbackPrefix = "settings:"
+	settingsDefaultChoice  = "default"
+
+	settingsLanguage    = "lang"
+	settingsPersona     = "persona"
+	settingsProvider    = "provider"
+	settingsTemperature = "temp"
+)
+
+// settingsTemperatures are the choices on the temperature page.
+var settingsTemperatures = []float64{0, 0.3, 0.7, 1, 1.5}
+
+// WithSettings gives commands a place to persist per-user preferences.
+func WithSettings(store *settings.Store) Option {
+	return func(h *Handlers) {
+		h.settings = store
+	}
+}
+
+// SettingsHandler opens a menu to change the user's language, provider and
+// temperature and the chat's persona.
+func (h *Handlers) SettingsHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	text, markup := h.settingsPage(update.Message.From, update.Message.Chat.ID, "")
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID:      update.Message.Chat.ID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+}
+
+// SettingsCallbackHandler opens a page of the /settings menu or applies a
+// choice and returns to the overview.
+func (h *Handlers) SettingsCallbackHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || update.CallbackQuery == nil {
+		return
+	}
+
+	query := update.CallbackQuery
+	user := &query.From
+	answer := func(text string) {
+		if answerer, ok := sender.(CallbackAnswerer); ok {
+			answerer.AnswerCallbackQuery(ctx, &tgbot.AnswerCallbackQueryParams{
+				CallbackQueryID: query.ID,
+				Text:            text,
+			})
+		}
+	}
+
+	msg := query.Message.Message
+	if msg == nil || !strings.HasPrefix(query.Data, settingsCallbackPrefix) {
+		answer(h.tr(user, "callback.unknown"))
+		return
+	}
+	chatID := msg.Chat.ID
+
+	page, value, _ := strings.Cut(strings.TrimPrefix(query.Data, settingsCallbackPrefix), ":")
+	if value != "" {
+		answer(h.applySetting(ctx, sender, user, chatID, page, value))
+		page = ""
+	} else {
+		answer("")
+	}
+
+	editor, ok := sender.(MessageEditor)
+	if !ok {
+		return
+	}
+	text, markup := h.settingsPage(user, chatID, page)
+	editor.EditMessageText(ctx, &tgbot.EditMessageTextParams{
+		ChatID:      chatID,
+		MessageID:   msg.ID,
+		Text:        text,
+		ReplyMarkup: markup,
+	})
+}
+
+// applySetting stores value for the setting shown on page and returns the
+// message to show.
+func (h *Handlers) applySetting(ctx context.Context, sender BotSender, user *models.User, chatID int64, page, value string) string {
+	switch page {
+	case settingsLanguage:
+		return h.setLanguage(user, value)
+	case settingsPersona:
+		if len(h.personas) == 0 || h.settings == nil {
+			return h.tr(user, "persona.disabled")
+		}
+		if isGroupChat(chatID) && !h.isGroupAdmin(ctx, sender, chatID, user.ID) {
+			return h.tr(user, "groups.admins_only")
+		}
+		return h.setPersona(user, chatID, value)
+	case settingsProvider:
+		name := value
+		if name == settingsDefaultChoice {
+			name = ""
+		}
+		if err := h.switchProvider(user.ID, name); err != nil {
+			log.Printf("User %d failed to switch to provider %s: %v", user.ID, name, err)
+			return h.tr(user, "model.unavailable")
+		}
+		if name == "" {
+			return h.tr(user, "switch.default")
+		}
+		return h.tr(user, "switch.done", name)
+	case settingsTemperature:
+		return h.setTemperature(user, value)
+	}
+	return h.tr(user, "callback.unknown")
+}
+
+func (h *Handlers) setTemperature(user *models.User, value string) string {
+	if h.settings == nil {
+		return h.tr(user, "callback.unknown")
+	}
+	if value == settingsDefaultChoice {
+		value = ""
+	} else if t, err := strconv.ParseFloat(value, 64); err != nil || t < 0 || t > 2 {
+		return h.tr(user, "callback.unknown")
+	}
+
+	if err := h.settings.SetString(user.ID, settings.Temperature, value); err != nil {
+		log.Printf("Failed to save temperature for user %d: %v", user.ID, err)
+		return h.tr(user, "settings.save_error")
+	}
+	if value == "" {
+		return h.tr(user, "settings.temperature_reset")
+	}
+	return h.tr(user, "settings.temperature_set", value)
+}
+
+// userTemperature returns the temperature userID picked in /settings.
+func (h *Handlers) userTemperature(userID int64) (float64, bool) {
+	if h.settings == nil {
+		return 0, false
+	}
+	value, err := h.settings.String(userID, settings.Temperature)
+	if err != nil {
+		log.Printf("Failed to load temperature for user %d: %v", userID, err)
+		return 0, false
+	}
+	if value == "" {
+		return 0, false
+	}
+	t, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Ignoring temperature %q of user %d", value, userID)
+		return 0, false
+	}
+	return t, true
+}
+
+// settingsPage renders a page of the /settings menu.
+func (h *Handlers) settingsPage(user *models.User, chatID int64, page string) (string, *models.InlineKeyboardMarkup) {
+	var rows [][]models.InlineKeyboardButton
+	choice := func(label, value string, active bool) models.InlineKeyboardButton {
+		if active {
+			label = "✓ " + label
+		}
+		return models.InlineKeyboardButton{Text: label, CallbackData: settingsCallbackPrefix + page + ":" + value}
+	}
+	back := []models.InlineKeyboardButton{{Text: h.tr(user, "settings.back"), CallbackData: settingsCallbackPrefix}}
+
+	switch page {
+	case settingsLanguage:
+		current := h.Language(user)
+		for _, code := range i18n.Supported() {
+			rows = append(rows, []models.InlineKeyboardButton{choice(i18n.Name(code), code, code == current)})
+		}
+		rows = append(rows, []models.InlineKeyboardButton{choice(h.tr(user, "settings.default"), settingsDefaultChoice, false)}, back)
+		return h.tr(user, "settings.language_page", h.languageLabel(user)), &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+
+	case settingsPersona:
+		active, hasActive := h.chatPersona(chatID)
+		for _, p := range h.personas {
+			rows = append(rows, []models.InlineKeyboardButton{choice(p.Name, p.Name, hasActive && p.Name == active.Name)})
+		}
+		rows = append(rows, []models.InlineKeyboardButton{choice(h.tr(user, "persona.default"), personaDefaultChoice, !hasActive)}, back)
+		return h.tr(user, "persona.picker", h.personaLabel(user, chatID)), &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+
+	case settingsProvider:
+		current := h.providerLabel(user.ID)
+		for _, name := range h.providerNames(user.ID) {
+			rows = append(rows, []models.InlineKeyboardButton{choice(name, name, name == current)})
+		}
+		rows = append(rows, []models.InlineKeyboardButton{choice(h.tr(user, "model.use_default"), settingsDefaultChoice, false)}, back)
+		return h.tr(user, "model.picker", current), &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+
+	case settingsTemperature:
+		current, ok := h.userTemperature(user.ID)
+		var row []models.InlineKeyboardButton
+		for _, t := range settingsTemperatures {
+			value := strconv.FormatFloat(t, 'g', -1, 64)
+			row = append(row, choice(value, value, ok && t == current))
+		}
+		rows = append(rows, row, []models.InlineKeyboardButton{choice(h.tr(user, "settings.default"), settingsDefaultChoice, !ok)}, back)
+		return h.tr(user, "settings.temperature_page", h.temperatureLabel(user)), &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+	}
+
+	open := func(key, target string) []models.InlineKeyboardButton {
+		return []models.InlineKeyboardButton{{Text: h.tr(user, key), CallbackData: settingsCallbackPrefix + target}}
+	}
+	lines := []string{
+		h.tr(user, "settings.title"),
+		"",
+		h.tr(user, "settings.language", h.languageLabel(user)),
+		h.tr(user, "settings.provider", h.providerLabel(user.ID)),
+	}
+	rows = append(rows, open("settings.open_language", settingsLanguage), open("settings.open_provider", settingsProvider))
+	if len(h.personas) > 0 && h.settings != nil {
+		lines = append(lines, h.tr(user, "settings.persona", h.personaLabel(user, chatID)))
+		rows = append(rows, open("settings.open_persona", settingsPersona))
+	}
+	if h.settings != nil {
+		lines = append(lines, h.tr(user, "settings.temperature", h.temperatureLabel(user)))
+		rows = append(rows, open("settings.open_temperature", settingsTemperature))
+	}
+	return strings.Join(lines, "\n"), &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func (h *Handlers) languageLabel(user *models.User) string {
+	lang := h.Language(user)
+	return i18n.Name(lang) + " (" + lang + ")"
+}
+
+func (h *Handlers) personaLabel(user *models.User, chatID int64) string {
+	if persona, ok := h.chatPersona(chatID); ok {
+		return persona.Name
+	}
+	return h.tr(user, "persona.default")
+}
+
+func (h *Handlers) providerLabel(userID int64) string {
+	provider, err := h.providerFor(userID)
+	if err != nil {
+		return "none"
+	}
+	return provider.Name()
+}
+
+func (h *Handlers) temperatureLabel(user *models.User) string {
+	if t, ok := h.userTemperature(user.ID); ok {
+		return strconv.FormatFloat(t, 'g', -1, 64)
+	}
+	return h.tr(user, "settings.temperature_default")
+}
@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/settings"
+)
+
+func newSettingsHandlers(sessions *mockSessionManager) *Handlers {
+	return NewHandlers(&mockRouter{providerName: "openai"}, sessions, []int64{1}, WithSettings(settings.New(settings.NewMemoryBackend())))
+}
+
+func callbackTargets(markup models.ReplyMarkup) []string {
+	keyboard, ok := markup.(*models.InlineKeyboardMarkup)
+	if !ok {
+		return nil
+	}
+	var targets []string
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			targets = append(targets, button.CallbackData)
+		}
+	}
+	return targets
+}
+
+func TestSettingsHandler_ShowsOverview(t *testing.T) {
+	handlers := newSettingsHandlers(&mockSessionManager{})
+	bot := &mockBot{}
+
+	handlers.SettingsHandler(context.Background(), bot, makeUpdate(1, 1, "/settings"))
+
+	for _, want := range []string{"Language: English (en)", "Provider: openai", "Temperature: provider default"} {
+		if !strings.Contains(bot.lastMessageParams.Text, want) {
+			t.Errorf("expected %q in %q", want, bot.lastMessageParams.Text)
+		}
+	}
+	got := strings.Join(callbackTargets(bot.lastMessageParams.ReplyMarkup), " ")
+	if got != "settings:lang settings:provider settings:temp" {
+		t.Errorf("unexpected pages %q", got)
+	}
+}
+
+func TestSettingsCallbackHandler_OpensPage(t *testing.T) {
+	handlers := newSettingsHandlers(&mockSessionManager{})
+	bot := &mockBot{}
+
+	handlers.SettingsCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, "settings:provider"))
+
+	if bot.lastEdit == nil {
+		t.Fatal("expected the menu to be edited")
+	}
+	got := strings.Join(callbackTargets(bot.lastEdit.ReplyMarkup), " ")
+	if got != "settings:provider:openai settings:provider:anthropic settings:provider:default settings:" {
+		t.Errorf("unexpected provider page %q", got)
+	}
+}
+
+func TestSettingsCallbackHandler_SetsTemperature(t *testing.T) {
+	handlers := newSettingsHandlers(&mockSessionManager{})
+	bot := &mockBot{}
+
+	handlers.SettingsCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, "settings:temp:0.3"))
+
+	if bot.lastCallback == nil || bot.lastCallback.Text != "Temperature set to 0.3." {
+		t.Errorf("unexpected callback answer %+v", bot.lastCallback)
+	}
+	if temperature, ok := handlers.userTemperature(1); !ok || temperature != 0.3 {
+		t.Errorf("expected temperature 0.3, got %v (set: %v)", temperature, ok)
+	}
+	if bot.lastEdit == nil || !strings.Contains(bot.lastEdit.Text, "Temperature: 0.3") {
+		t.Errorf("expected the overview with the new temperature, got %+v", bot.lastEdit)
+	}
+	if len(handlers.requestOptions(1)) != 2 {
+		t.Errorf("expected the temperature in the request options")
+	}
+
+	handlers.SettingsCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, "settings:temp:default"))
+	if _, ok := handlers.userTemperature(1); ok {
+		t.Error("expected the temperature to be reset")
+	}
+}
+
+func TestSettingsCallbackHandler_SetsLanguage(t *testing.T) {
+	sessions := &mockSessionManager{}
+	handlers := newSettingsHandlers(sessions)
+	bot := &mockBot{}
+
+	handlers.SettingsCallbackHandler(context.Background(), bot, makeCallbackUpdate(1, "settings:lang:de"))
+
+	if sessions.languages[1] != "de" {
+		t.Errorf("expected language de, got %q", sessions.languages[1])
+	}
+	if bot.lastEdit == nil || !strings.Contains(bot.lastEdit.Text, "Einstellungen") {
+		t.Errorf("expected the overview in German, got %+v", bot.lastEdit)
+	}
+}
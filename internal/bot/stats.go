@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+func (h *Handlers) StatsHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: update.Message.Chat.ID,
+			Text:   text,
+		})
+	}
+
+	recorder := h.statsRecorder()
+	if recorder == nil {
+		reply(h.tr(user, "stats.unavailable"))
+		return
+	}
+	stats, err := recorder.Stats(user.ID)
+	if err != nil {
+		log.Printf("Failed to load stats for user %d: %v", user.ID, err)
+		reply(h.tr(user, "stats.error"))
+		return
+	}
+	if stats.Messages == 0 {
+		reply(h.tr(user, "stats.none"))
+		return
+	}
+
+	messages, err := h.sessionManager.Get(h.sessionKey(update.Message.Chat.ID, user.ID))
+	if err != nil {
+		log.Printf("Failed to load session for user %d: %v", user.ID, err)
+	}
+
+	favorite := stats.FavoriteProvider()
+	if favorite == "" {
+		favorite = "-"
+	}
+	reply(h.tr(user, "stats.text",
+		stats.Messages,
+		stats.Tokens,
+		favorite,
+		stats.AverageLatency().Round(100*time.Millisecond).String(),
+		len(messages),
+		llm.CountTokens(messages),
+	))
+}
+
+// statsRecorder returns the session store's stats recorder, or nil when the
+// backend does not keep stats.
+func (h *Handlers) statsRecorder() session.StatsRecorder {
+	recorder, _ := h.sessionManager.(session.StatsRecorder)
+	return recorder
+}
+
+func (h *Handlers) recordStats(userID int64, provider string, request []llm.Message, response string, latency time.Duration) {
+	recorder := h.statsRecorder()
+	if recorder == nil {
+		return
+	}
+	err := recorder.RecordExchange(userID, session.Exchange{
+		Provider: provider,
+		Tokens:   llm.CountTokens(request) + llm.EstimateTokens(response),
+		Latency:  latency,
+	})
+	if err != nil {
+		log.Printf("Failed to record stats for user %d: %v", userID, err)
+	}
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/session"
+)
+
+type statsSessionManager struct {
+	mockSessionManager
+	exchanges []session.Exchange
+	stats     session.Stats
+}
+
+func (m *statsSessionManager) RecordExchange(userID int64, e session.Exchange) error {
+	m.exchanges = append(m.exchanges, e)
+	return nil
+}
+
+func (m *statsSessionManager) Stats(userID int64) (session.Stats, error) {
+	return m.stats, nil
+}
+
+func TestTextMessageHandler_RecordsStats(t *testing.T) {
+	router := &mockRouter{providerName: "openai", response: "Hello back"}
+	sessions := &statsSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{1})
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "Hello"))
+
+	if len(sessions.exchanges) != 1 {
+		t.Fatalf("expected one recorded exchange, got %d", len(sessions.exchanges))
+	}
+	if e := sessions.exchanges[0]; e.Tokens == 0 {
+		t.Errorf("expected token estimate, got %+v", e)
+	}
+
+	if len(sessions.saved) != 2 {
+		t.Fatalf("expected two saved messages, got %d", len(sessions.saved))
+	}
+	for _, msg := range sessions.saved {
+		if msg.ID == "" || msg.Timestamp.IsZero() {
+			t.Errorf("expected saved message to be stamped, got %+v", msg)
+		}
+	}
+	if answer := sessions.saved[1]; answer.Tokens.Prompt == 0 || answer.Tokens.Completion == 0 {
+		t.Errorf("expected token counts on the answer, got %+v", answer.Tokens)
+	}
+}
+
+func TestStatsHandler(t *testing.T) {
+	sessions := &statsSessionManager{}
+	handlers := NewHandlers(&mockRouter{providerName: "openai"}, sessions, []int64{1})
+
+	bot := &mockBot{}
+	handlers.StatsHandler(context.Background(), bot, makeUpdate(1, 1, "/stats"))
+	if bot.lastMessageParams.Text != "No statistics yet. Send me a message first." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+
+	sessions.stats = session.Stats{Messages: 4, Tokens: 1200, LatencyMS: 6000, Providers: map[string]int{"openai": 1, "anthropic": 3}}
+	handlers.StatsHandler(context.Background(), bot, makeUpdate(1, 1, "/stats"))
+	for _, want := range []string{"Messages: 4", "Tokens used: 1200", "Favorite provider: anthropic", "Average response time: 1.5s"} {
+		if !strings.Contains(bot.lastMessageParams.Text, want) {
+			t.Errorf("expected %q in %q", want, bot.lastMessageParams.Text)
+		}
+	}
+
+	handlers = NewHandlers(&mockRouter{providerName: "openai"}, &mockSessionManager{}, []int64{1})
+	handlers.StatsHandler(context.Background(), bot, makeUpdate(1, 1, "/stats"))
+	if bot.lastMessageParams.Text != "Statistics are not available with this memory backend." {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
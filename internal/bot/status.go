@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/version"
+)
+
+// runtimeStats counts what the bot has done since it started.
+type runtimeStats struct {
+	started  time.Time
+	answered atomic.Int64
+	failed   atomic.Int64
+}
+
+func newRuntimeStats() *runtimeStats {
+	return &runtimeStats{started: time.Now()}
+}
+
+func (s *runtimeStats) uptime() time.Duration {
+	return time.Since(s.started)
+}
+
+// WithMemoryBackend names the session backend shown by /status.
+func WithMemoryBackend(name string) Option {
+	return func(h *Handlers) {
+		h.memoryBackend = name
+	}
+}
+
+// StatusHandler shows the build version, uptime, the requesting user's
+// provider and the size of their conversation.
+func (h *Handlers) StatusHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	provider, model := "-", "-"
+	if p, err := h.providerFor(user.ID); err == nil {
+		provider = p.Name()
+		if mp, ok := p.(llm.ModelProvider); ok && mp.Model() != "" {
+			model = mp.Model()
+		}
+	}
+	backend := h.memoryBackend
+	if backend == "" {
+		backend = "-"
+	}
+
+	messages, err := h.sessionManager.Get(h.sessionKey(update.Message.Chat.ID, user.ID))
+	if err != nil {
+		log.Printf("Failed to load session for user %d: %v", user.ID, err)
+	}
+
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: update.Message.Chat.ID,
+		Text: h.tr(user, "status.text",
+			version.String(),
+			formatUptime(h.runtime.uptime()),
+			provider,
+			model,
+			backend,
+			len(messages),
+			llm.CountTokens(messages),
+			h.runtime.answered.Load(),
+			h.runtime.failed.Load(),
+		),
+	})
+}
+
+// WhoAmIHandler shows what the bot knows about the requesting user.
+func (h *Handlers) WhoAmIHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	user := update.Message.From
+	username := "-"
+	if user.Username != "" {
+		username = "@" + user.Username
+	}
+	role := h.tr(user, "whoami.role."+h.Role(user.ID).String())
+
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: update.Message.Chat.ID,
+		Text:   h.tr(user, "whoami.text", user.ID, username, role, h.Language(user)),
+	})
+}
+
+// formatUptime renders d as days, hours and minutes, or seconds when the
+// bot has been up for less than a minute.
+func formatUptime(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d / time.Hour % 24)
+	minutes := int(d / time.Minute % 60)
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestStatusHandler(t *testing.T) {
+	router := &mockRouter{providerName: "openai", response: "Hello back"}
+	sessions := &mockSessionManager{messages: []llm.Message{
+		{Role: "user", Content: "Hi"},
+		{Role: "assistant", Content: "Hello"},
+	}}
+	handlers := NewHandlers(router, sessions, []int64{1}, WithMemoryBackend("redis"))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "Hello"))
+
+	bot := &mockBot{}
+	handlers.StatusHandler(context.Background(), bot, makeUpdate(1, 1, "/status"))
+	for _, want := range []string{"Helpi ", "Provider: openai", "Memory: redis", "Your conversation: 2 messages", "Answered since start: 1, failed: 0"} {
+		if !strings.Contains(bot.lastMessageParams.Text, want) {
+			t.Errorf("expected %q in %q", want, bot.lastMessageParams.Text)
+		}
+	}
+}
+
+func TestWhoAmIHandler(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1, 100}, WithAdmins([]int64{100}))
+
+	bot := &mockBot{}
+	update := makeUpdate(100, 100, "/whoami")
+	update.Message.From.Username = "alice"
+	handlers.WhoAmIHandler(context.Background(), bot, update)
+	for _, want := range []string{"User ID: 100", "Username: @alice", "Role: admin", "Language: en"} {
+		if !strings.Contains(bot.lastMessageParams.Text, want) {
+			t.Errorf("expected %q in %q", want, bot.lastMessageParams.Text)
+		}
+	}
+
+	handlers.WhoAmIHandler(context.Background(), bot, makeUpdate(1, 1, "/whoami"))
+	if !strings.Contains(bot.lastMessageParams.Text, "Role: user") {
+		t.Errorf("expected user role in %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	tests := map[time.Duration]string{
+		42 * time.Second:                            "42s",
+		5 * time.Minute:                             "5m",
+		3*time.Hour + 7*time.Minute:                 "3h 7m",
+		50*time.Hour + 30*time.Minute + time.Second: "2d 2h 30m",
+	}
+	for d, want := range tests {
+		if got := formatUptime(d); got != want {
+			t.Errorf("formatUptime(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
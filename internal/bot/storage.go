@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/session"
+	"github.com/jrswab/helpi/internal/storage"
+)
+
+// maxStorageUsers caps how many users /admin storage lists.
+const maxStorageUsers = 20
+
+type storageQuota struct {
+	dirs  []string
+	quota storage.Quota
+}
+
+// WithStorage reports the disk use of the files in dirs with /admin storage
+// and lets EnforceStorageQuota keep them within quota.
+func WithStorage(dirs []string, quota storage.Quota) Option {
+	return func(h *Handlers) {
+		h.storage = &storageQuota{dirs: dirs, quota: quota}
+	}
+}
+
+// EnforceStorageQuota removes conversations and documents, oldest first,
+// until every user and the total are within the storage quota. Files are
+// removed through the store that owns them, which holds its locks and
+// drops cached copies.
+func (h *Handlers) EnforceStorageQuota() {
+	if h.storage == nil || (h.storage.quota.UserBytes <= 0 && h.storage.quota.TotalBytes <= 0) {
+		return
+	}
+
+	files, err := h.scanStorage()
+	if err != nil {
+		log.Printf("Storage quota: %v", err)
+		return
+	}
+	evicters := h.evicters()
+	for _, f := range storage.Over(files, h.storage.quota) {
+		removed, err := evict(evicters, f)
+		if err != nil {
+			log.Printf("Storage quota: failed to remove %s: %v", f.Path, err)
+			continue
+		}
+		if !removed {
+			log.Printf("Storage quota: no store owns %s, leaving it", f.Path)
+			continue
+		}
+		log.Printf("Storage quota: removed %s (%s) of user %d", f.Path, storage.FormatBytes(f.Size), f.Owner)
+	}
+}
+
+// scanStorage lists the stored files, each counted against the user whose
+// conversation it holds, so per-bot and group conversations are added up
+// with the user's own.
+func (h *Handlers) scanStorage() ([]storage.File, error) {
+	files, err := storage.Scan(h.storage.dirs...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range files {
+		files[i].Owner = session.Owner(files[i].UserID)
+	}
+	return files, nil
+}
+
+func (h *Handlers) evicters() []storage.Evicter {
+	var evicters []storage.Evicter
+	if e, ok := h.sessionManager.(storage.Evicter); ok {
+		evicters = append(evicters, e)
+	}
+	if e, ok := h.documentStore.(storage.Evicter); ok {
+		evicters = append(evicters, e)
+	}
+	return evicters
+}
+
+func evict(evicters []storage.Evicter, f storage.File) (bool, error) {
+	for _, e := range evicters {
+		if removed, err := e.Evict(f); removed || err != nil {
+			return removed, err
+		}
+	}
+	return false, nil
+}
+
+func (h *Handlers) storageReport(user *models.User) string {
+	if h.storage == nil {
+		return h.tr(user, "admin.storage.unsupported")
+	}
+	files, err := h.scanStorage()
+	if err != nil {
+		log.Printf("Failed to scan storage: %v", err)
+		return h.tr(user, "admin.storage.error")
+	}
+	usage := storage.Summarize(files)
+
+	lines := []string{h.tr(user, "admin.storage.total", storage.FormatBytes(usage.Total), h.quotaText(user, h.storage.quota.TotalBytes))}
+	lines = append(lines, h.tr(user, "admin.storage.user_quota", h.quotaText(user, h.storage.quota.UserBytes)))
+	if len(usage.Users) == 0 {
+		return strings.Join(lines, "\n")
+	}
+
+	users := make([]int64, 0, len(usage.Users))
+	for id := range usage.Users {
+		users = append(users, id)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if usage.Users[users[i]] != usage.Users[users[j]] {
+			return usage.Users[users[i]] > usage.Users[users[j]]
+		}
+		return users[i] < users[j]
+	})
+
+	lines = append(lines, "", h.tr(user, "admin.storage.header"))
+	for i, id := range users {
+		if i == maxStorageUsers {
+			lines = append(lines, h.tr(user, "admin.storage.more", len(users)-maxStorageUsers))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%d. %d: %s", i+1, id, storage.FormatBytes(usage.Users[id])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (h *Handlers) quotaText(user *models.User, limit int64) string {
+	if limit <= 0 {
+		return h.tr(user, "admin.storage.unlimited")
+	}
+	return storage.FormatBytes(limit)
+}
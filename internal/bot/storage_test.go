@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/session"
+	"github.com/jrswab/helpi/internal/storage"
+)
+
+func TestAdminHandler_Storage(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "7.json"), make([]byte, 3000), 0644)
+	os.WriteFile(filepath.Join(dir, "8.json"), make([]byte, 100), 0644)
+
+	handlers := NewHandlers(&checkingRouter{}, &mockSessionManager{}, nil, WithAdmins([]int64{1}),
+		WithStorage([]string{dir}, storage.Quota{UserBytes: 1 << 20}))
+	b := &mockBot{}
+	handlers.AdminHandler(context.Background(), b, makeUpdate(1, 1, "/admin storage"))
+
+	text := b.lastMessageParams.Text
+	for _, want := range []string{"Storage: 3.0 KiB used, quota none", "Per-user quota: 1.0 MiB", "1. 7: 2.9 KiB", "2. 8: 100 B"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in report, got %q", want, text)
+		}
+	}
+}
+
+func TestEnforceStorageQuota(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"7_2.json", "7_threads.json"} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, make([]byte, 600), 0644)
+		os.Chtimes(path, old, old)
+	}
+	os.WriteFile(filepath.Join(dir, "7.json"), make([]byte, 600), 0644)
+
+	sessions, err := session.NewManager(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlers := NewHandlers(&mockRouter{}, sessions, nil,
+		WithStorage([]string{dir}, storage.Quota{UserBytes: 1500}))
+	handlers.EnforceStorageQuota()
+
+	if _, err := os.Stat(filepath.Join(dir, "7_2.json")); !os.IsNotExist(err) {
+		t.Error("expected the oldest conversation to be removed")
+	}
+	for _, name := range []string{"7.json", "7_threads.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be kept: %v", name, err)
+		}
+	}
+}
+
+func TestEnforceStorageQuota_CountsBotSessionsAsTheUsers(t *testing.T) {
+	dir := t.TempDir()
+	store, err := session.NewManager(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := session.ForBot(store, 42)
+	sessions.Save(7, []llm.Message{{Role: "user", Content: strings.Repeat("x", 600)}})
+	scoped := filepath.Join(dir, fmt.Sprintf("%d.json", session.UserKey(7, 42)))
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(scoped, old, old)
+	os.WriteFile(filepath.Join(dir, "7.json"), make([]byte, 600), 0644)
+
+	handlers := NewHandlers(&mockRouter{}, sessions, nil,
+		WithStorage([]string{dir}, storage.Quota{UserBytes: 1000}))
+	handlers.EnforceStorageQuota()
+
+	if _, err := os.Stat(scoped); !os.IsNotExist(err) {
+		t.Error("expected the bot's older conversation of user 7 to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "7.json")); err != nil {
+		t.Errorf("expected the newer conversation to be kept: %v", err)
+	}
+}
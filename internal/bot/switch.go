@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+// WithBotID keeps the /switch choices made with this bot apart from those
+// made with other bots sharing the router. The default bot leaves it unset,
+// so choices made before several bots were configured still apply.
+func WithBotID(id int64) Option {
+	return func(h *Handlers) {
+		h.botID = id
+	}
+}
+
+// WithDefaultProvider sets the provider used for users who have not picked
+// one with /switch.
+func WithDefaultProvider(name string) Option {
+	return func(h *Handlers) {
+		h.defaultProvider = name
+	}
+}
+
+func (h *Handlers) SwitchHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	userID := update.Message.From.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	name := strings.ToLower(commandArgs(update.Message.Text))
+	if name == "" {
+		current := "none"
+		if provider, err := h.providerFor(userID); err == nil {
+			current = provider.Name()
+		}
+		reply(h.tr(update.Message.From, "switch.status", current, strings.Join(h.providerNames(userID), ", ")))
+		return
+	}
+
+	if name == "default" {
+		name = ""
+	}
+
+	if err := h.switchProvider(userID, name); err != nil {
+		reply(h.tr(update.Message.From, "switch.failed", name, strings.Join(h.providerNames(userID), ", ")))
+		return
+	}
+
+	if name == "" {
+		reply(h.tr(update.Message.From, "switch.default"))
+		return
+	}
+	reply(h.tr(update.Message.From, "switch.done", name))
+}
+
+func (h *Handlers) switchProvider(userID int64, name string) error {
+	if pr, ok := h.router.(llm.ProfileRouter); ok && h.botID != 0 {
+		if err := pr.SetDefaultForProfile(userID, h.profile(userID), name); err != nil {
+			return err
+		}
+	} else if err := h.router.SetDefaultForUser(userID, name); err != nil {
+		return err
+	}
+	if err := h.sessionManager.SetProvider(userID, name); err != nil {
+		log.Printf("Failed to persist provider for user %d: %v", userID, err)
+	}
+	return nil
+}
+
+// providerFor returns the provider that answers userID's messages when no
+// routing rule applies.
+func (h *Handlers) providerFor(userID int64) (llm.Provider, error) {
+	if h.defaultProvider != "" {
+		if name, err := h.sessionManager.GetProvider(userID); err == nil && name == "" && slices.Contains(h.providerNames(userID), h.defaultProvider) {
+			if provider, err := h.router.GetProviderByName(h.defaultProvider); err == nil {
+				return provider, nil
+			}
+		}
+	}
+	if pr, ok := h.router.(llm.ProfileRouter); ok && h.botID != 0 {
+		return pr.GetProviderForProfile(userID, h.profile(userID))
+	}
+	return h.router.GetProviderForUser(userID)
+}
+
+// profile is the key the router keeps userID's /switch choice under.
+func (h *Handlers) profile(userID int64) int64 {
+	if h.botID == 0 {
+		return userID
+	}
+	return session.ScopedKey(h.botID, userID)
+}
+
+// userOptions identify userID to the router for access checks and their
+// /switch choice.
+func (h *Handlers) userOptions(userID int64) []llm.RequestOption {
+	opts := []llm.RequestOption{llm.WithUser(userID)}
+	if h.botID != 0 {
+		opts = append(opts, llm.WithProfile(h.profile(userID)))
+	}
+	return opts
+}
+
+// providerNames lists the enabled providers userID may use.
+func (h *Handlers) providerNames(userID int64) []string {
+	if checker, ok := h.router.(llm.AccessChecker); ok {
+		return checker.AllowedProviders(userID)
+	}
+	return h.router.ProviderNames()
+}
+
+func (h *Handlers) requestOptions(userID int64) []llm.RequestOption {
+	opts := h.userOptions(userID)
+	if h.defaultProvider != "" {
+		opts = append(opts, llm.WithDefaultProvider(h.defaultProvider))
+	}
+	if t, ok := h.userTemperature(userID); ok {
+		opts = append(opts, llm.WithTemperature(t))
+	}
+	return opts
+}
+
+// RestoreProviderChoices loads the /switch choices made with this bot into
+// r. The default bot's are stored per user and loaded with the router.
+func (h *Handlers) RestoreProviderChoices(r llm.Router) error {
+	pr, ok := r.(llm.ProfileRouter)
+	if !ok || h.botID == 0 {
+		return nil
+	}
+	choices, err := h.sessionManager.Providers()
+	if err != nil {
+		return fmt.Errorf("failed to load user providers: %w", err)
+	}
+	for userID, name := range choices {
+		if err := pr.SetDefaultForProfile(userID, h.profile(userID), name); err != nil {
+			log.Printf("Ignoring saved provider %s for user %d: %v", name, userID, err)
+		}
+	}
+	return nil
+}
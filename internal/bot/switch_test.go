@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+func TestSwitchHandler_SetsAndPersistsProvider(t *testing.T) {
+	router := &mockRouter{providerName: "openai"}
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{1})
+
+	bot := &mockBot{}
+	handlers.SwitchHandler(context.Background(), bot, makeUpdate(1, 1, "/switch Anthropic"))
+
+	if router.userProviders[1] != "anthropic" {
+		t.Errorf("expected router default for user to be anthropic, got %q", router.userProviders[1])
+	}
+	if sessions.providers[1] != "anthropic" {
+		t.Errorf("expected provider to be persisted, got %q", sessions.providers[1])
+	}
+	if !strings.Contains(bot.lastMessageParams.Text, "Switched to anthropic") {
+		t.Errorf("unexpected reply: %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.ModelHandler(context.Background(), bot, makeUpdate(1, 1, "/model"))
+	if !strings.Contains(bot.lastMessageParams.Text, "anthropic") {
+		t.Errorf("expected /model to show the user's provider, got %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.SwitchHandler(context.Background(), bot, makeUpdate(1, 1, "/switch default"))
+	if _, ok := router.userProviders[1]; ok {
+		t.Error("expected user default to be cleared")
+	}
+	if sessions.providers[1] != "" {
+		t.Errorf("expected persisted provider to be cleared, got %q", sessions.providers[1])
+	}
+}
+
+func TestSwitchHandler_ShowsProviders(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{providerName: "openai"}, &mockSessionManager{}, []int64{1})
+
+	bot := &mockBot{}
+	handlers.SwitchHandler(context.Background(), bot, makeUpdate(1, 1, "/switch"))
+
+	if !strings.Contains(bot.lastMessageParams.Text, "Active provider: openai") || !strings.Contains(bot.lastMessageParams.Text, "openai, anthropic") {
+		t.Errorf("unexpected reply: %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestSwitchHandler_UnknownProvider(t *testing.T) {
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(&mockRouter{err: errors.New("provider copilot not enabled")}, sessions, []int64{1})
+
+	bot := &mockBot{}
+	handlers.SwitchHandler(context.Background(), bot, makeUpdate(1, 1, "/switch copilot"))
+
+	if !strings.Contains(bot.lastMessageParams.Text, "Cannot switch") {
+		t.Errorf("unexpected reply: %q", bot.lastMessageParams.Text)
+	}
+	if len(sessions.providers) != 0 {
+		t.Error("expected nothing to be persisted")
+	}
+}
+
+func TestHandlers_DefaultProvider(t *testing.T) {
+	router := &mockRouter{providerName: "openai", response: "ok"}
+	sessions := &mockSessionManager{providers: map[int64]string{2: "ollama"}}
+	handlers := NewHandlers(router, sessions, nil, WithDefaultProvider("anthropic"))
+
+	if p, err := handlers.providerFor(1); err != nil || p.Name() != "anthropic" {
+		t.Errorf("expected bot default provider, got %v, %v", p, err)
+	}
+	router.userProviders = map[int64]string{2: "ollama"}
+	if p, err := handlers.providerFor(2); err != nil || p.Name() != "ollama" {
+		t.Errorf("expected the user's choice to win, got %v, %v", p, err)
+	}
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+	if len(router.lastOpts) != 3 {
+		t.Errorf("expected user, default provider and route report options, got %d", len(router.lastOpts))
+	}
+}
+
+// profileRouter keeps /switch choices per profile like the real router.
+type profileRouter struct {
+	mockRouter
+}
+
+func (r *profileRouter) GetProviderForProfile(userID, profile int64) (llm.Provider, error) {
+	return r.GetProviderForUser(profile)
+}
+
+func (r *profileRouter) SetDefaultForProfile(userID, profile int64, name string) error {
+	return r.SetDefaultForUser(profile, name)
+}
+
+func TestSwitchHandler_KeepsBotsApart(t *testing.T) {
+	router := &profileRouter{mockRouter{providerName: "openai"}}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithBotID(42))
+
+	handlers.SwitchHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "/switch anthropic"))
+
+	profile := session.ScopedKey(42, 1)
+	if router.userProviders[profile] != "anthropic" || router.userProviders[1] != "" {
+		t.Errorf("expected the choice under the bot's profile only, got %v", router.userProviders)
+	}
+	if p, _ := handlers.providerFor(1); p.Name() != "anthropic" {
+		t.Errorf("expected the bot's choice to answer, got %s", p.Name())
+	}
+
+	sessions := &mockSessionManager{providers: map[int64]string{1: "anthropic"}}
+	router.userProviders = nil
+	NewHandlers(router, sessions, nil, WithBotID(42)).RestoreProviderChoices(router)
+	if router.userProviders[profile] != "anthropic" {
+		t.Errorf("expected saved choices to be restored under the profile, got %v", router.userProviders)
+	}
+}
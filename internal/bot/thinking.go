@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// thinkingStatus posts a "thinking" message when the model starts extended
+// thinking, since that can take long enough for the typing indicator alone
+// to look stuck. Once the request is done the message says how long it took.
+type thinkingStatus struct {
+	h       *Handlers
+	ctx     context.Context
+	sender  BotSender
+	message *models.Message
+
+	once      sync.Once
+	started   time.Time
+	messageID int
+}
+
+func (h *Handlers) newThinkingStatus(ctx context.Context, sender BotSender, message *models.Message) *thinkingStatus {
+	return &thinkingStatus{h: h, ctx: ctx, sender: sender, message: message}
+}
+
+func (s *thinkingStatus) start() {
+	s.once.Do(func() {
+		s.started = time.Now()
+		msg, err := s.sender.SendMessage(s.ctx, &tgbot.SendMessageParams{
+			ChatID: s.message.Chat.ID,
+			Text:   s.h.tr(s.message.From, "chat.thinking"),
+		})
+		if err == nil && msg != nil {
+			s.messageID = msg.ID
+		}
+	})
+}
+
+func (s *thinkingStatus) finish() {
+	// Waits for a status being sent, and keeps a late start from sending
+	// one after the answer.
+	s.once.Do(func() {})
+	if s.messageID == 0 {
+		return
+	}
+	editor, ok := s.sender.(MessageEditor)
+	if !ok {
+		return
+	}
+	took := time.Since(s.started).Round(time.Second)
+	editor.EditMessageText(context.WithoutCancel(s.ctx), &tgbot.EditMessageTextParams{
+		ChatID:    s.message.Chat.ID,
+		MessageID: s.messageID,
+		Text:      s.h.tr(s.message.From, "chat.thought", took.String()),
+	})
+}
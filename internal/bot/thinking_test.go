@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+type statusBot struct {
+	mockBot
+}
+
+func (b *statusBot) SendMessage(ctx context.Context, params *tgbot.SendMessageParams) (*models.Message, error) {
+	b.mockBot.SendMessage(ctx, params)
+	return &models.Message{ID: len(b.sentMessages)}, nil
+}
+
+func TestThinkingStatus(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &historySessions{}, []int64{1})
+	bot := &statusBot{}
+	update := makeUpdate(1, 1, "hard question")
+
+	status := handlers.newThinkingStatus(context.Background(), bot, update.Message)
+	status.start()
+	status.start()
+	if len(bot.sentMessages) != 1 || bot.sentMessages[0].Text != "💭 Thinking…" {
+		t.Fatalf("expected one thinking message, got %+v", bot.sentMessages)
+	}
+
+	status.finish()
+	if bot.lastEdit == nil || bot.lastEdit.MessageID != 1 || !strings.HasPrefix(bot.lastEdit.Text, "💭 Thought for ") {
+		t.Errorf("expected the status to be updated, got %+v", bot.lastEdit)
+	}
+}
+
+func TestThinkingStatus_NotThinking(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &historySessions{}, []int64{1})
+	bot := &statusBot{}
+
+	status := handlers.newThinkingStatus(context.Background(), bot, makeUpdate(1, 1, "hi").Message)
+	status.finish()
+	status.start()
+	if len(bot.sentMessages) != 0 || bot.lastEdit != nil {
+		t.Errorf("expected no status without thinking, got %+v", bot.sentMessages)
+	}
+}
@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const maxThreadTitleLength = 64
+
+func (h *Handlers) NewThreadHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	title := commandArgs(update.Message.Text)
+	if len([]rune(title)) > maxThreadTitleLength {
+		title = string([]rune(title)[:maxThreadTitleLength])
+	}
+
+	thread, err := h.sessionManager.NewThread(h.sessionKey(chatID, update.Message.From.ID), title)
+	if err != nil {
+		log.Printf("Failed to create thread for user %d: %v", update.Message.From.ID, err)
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "threads.new_error"),
+		})
+		return
+	}
+
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: chatID,
+		Text:   h.tr(update.Message.From, "threads.started", thread.ID, thread.Title),
+	})
+}
+
+func (h *Handlers) ThreadsHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	threads, active, err := h.sessionManager.Threads(h.sessionKey(chatID, update.Message.From.ID))
+	if err != nil {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "threads.load_error", err),
+		})
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.tr(update.Message.From, "threads.header") + "\n\n")
+	for _, t := range threads {
+		marker := "  "
+		if t.ID == active {
+			marker = "▶ "
+		}
+		sb.WriteString(fmt.Sprintf("%s%d. %s\n", marker, t.ID, t.Title))
+	}
+	sb.WriteString("\n" + h.tr(update.Message.From, "threads.footer"))
+
+	sender.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID: chatID,
+		Text:   sb.String(),
+	})
+}
+
+func (h *Handlers) ResumeHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	reply := func(text string) {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   text,
+		})
+	}
+
+	id, err := strconv.Atoi(commandArgs(update.Message.Text))
+	if err != nil {
+		reply(h.tr(update.Message.From, "threads.resume_usage"))
+		return
+	}
+
+	thread, err := h.sessionManager.ResumeThread(h.sessionKey(chatID, update.Message.From.ID), id)
+	if err != nil {
+		reply(h.tr(update.Message.From, "threads.not_found", id))
+		return
+	}
+	reply(h.tr(update.Message.From, "threads.resumed", thread.ID, thread.Title))
+}
@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestThreadHandlers(t *testing.T) {
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(&mockRouter{}, sessions, []int64{1})
+	bot := &mockBot{}
+
+	handlers.NewThreadHandler(context.Background(), bot, makeUpdate(1, 1, "/new Trip planning"))
+	if !strings.Contains(bot.lastMessageParams.Text, "Started conversation 1: Trip planning") {
+		t.Errorf("unexpected reply: %q", bot.lastMessageParams.Text)
+	}
+	handlers.NewThreadHandler(context.Background(), bot, makeUpdate(1, 1, "/new Recipes"))
+
+	handlers.ThreadsHandler(context.Background(), bot, makeUpdate(1, 1, "/threads"))
+	if !strings.Contains(bot.lastMessageParams.Text, "1. Trip planning") || !strings.Contains(bot.lastMessageParams.Text, "▶ 2. Recipes") {
+		t.Errorf("unexpected thread list: %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.ResumeHandler(context.Background(), bot, makeUpdate(1, 1, "/resume 1"))
+	if sessions.active != 1 || !strings.Contains(bot.lastMessageParams.Text, "Resumed conversation 1") {
+		t.Errorf("expected thread 1 to be resumed, got active %d, reply %q", sessions.active, bot.lastMessageParams.Text)
+	}
+}
+
+func TestResumeHandler_InvalidArgs(t *testing.T) {
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1})
+	bot := &mockBot{}
+
+	handlers.ResumeHandler(context.Background(), bot, makeUpdate(1, 1, "/resume abc"))
+	if !strings.Contains(bot.lastMessageParams.Text, "Usage") {
+		t.Errorf("unexpected reply: %q", bot.lastMessageParams.Text)
+	}
+
+	handlers.ResumeHandler(context.Background(), bot, makeUpdate(1, 1, "/resume 9"))
+	if !strings.Contains(bot.lastMessageParams.Text, "not found") {
+		t.Errorf("unexpected reply: %q", bot.lastMessageParams.Text)
+	}
+}
@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+const (
+	titleExcerptLength = 1000
+	titleTrim          = "\"'`*#_. \t"
+)
+
+const titleInstruction = "Write a title of at most six words for the conversation below, in the language the user writes in. " +
+	"Reply with the title only, without quotes or punctuation at the end."
+
+func WithTitles(cfg config.TitlesConfig) Option {
+	return func(h *Handlers) {
+		h.titles = cfg
+	}
+}
+
+// titleThread names the active thread after its first exchange, unless it
+// was given a title of its own. The title is generated in the background
+// so the reply is not held up.
+func (h *Handlers) titleThread(ctx context.Context, key, userID int64, messages []llm.Message) {
+	renamer, ok := h.sessionManager.(session.ThreadRenamer)
+	if !h.titles.Enabled || !ok || !firstExchange(messages) {
+		return
+	}
+
+	threads, active, err := h.sessionManager.Threads(key)
+	if err != nil {
+		log.Printf("Failed to load threads of user %d: %v", userID, err)
+		return
+	}
+	i := slices.IndexFunc(threads, func(t session.Thread) bool { return t.ID == active })
+	if i < 0 || !session.HasDefaultTitle(threads[i]) {
+		return
+	}
+
+	opts := h.userOptions(userID)
+	if h.titles.Provider != "" {
+		opts = append(opts, llm.WithProvider(h.titles.Provider))
+	}
+	if h.titles.Model != "" {
+		opts = append(opts, llm.WithModel(h.titles.Model))
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		response, err := h.router.SendMessage(ctx, titleMessages(messages), opts...)
+		if err != nil {
+			log.Printf("Failed to title thread %d of user %d: %v", active, userID, err)
+			return
+		}
+		title := cleanTitle(response)
+		if title == "" {
+			return
+		}
+		if err := renamer.RenameThread(key, active, title); err != nil {
+			log.Printf("Failed to title thread %d of user %d: %v", active, userID, err)
+		}
+	}()
+}
+
+// firstExchange reports whether messages hold exactly one answer.
+func firstExchange(messages []llm.Message) bool {
+	answers := 0
+	for _, m := range messages {
+		if m.Role == "assistant" {
+			answers++
+		}
+	}
+	return answers == 1
+}
+
+func titleMessages(messages []llm.Message) []llm.Message {
+	var sb strings.Builder
+	for _, m := range messages {
+		if m.Context || (m.Role != "user" && m.Role != "assistant") {
+			continue
+		}
+		sb.WriteString(m.Role + ": " + truncate(m.Content, titleExcerptLength) + "\n\n")
+	}
+	return []llm.Message{
+		{Role: "system", Content: titleInstruction},
+		{Role: "user", Content: sb.String()},
+	}
+}
+
+// cleanTitle keeps the first line of a model's answer, without the quotes,
+// Markdown and final period models tend to add.
+func cleanTitle(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		title := strings.Trim(line, titleTrim)
+		title = strings.Trim(strings.TrimPrefix(title, "Title:"), titleTrim)
+		if title == "" {
+			continue
+		}
+		if r := []rune(title); len(r) > maxThreadTitleLength {
+			title = string(r[:maxThreadTitleLength])
+		}
+		return title
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/session"
+)
+
+type renamingSessionManager struct {
+	*mockSessionManager
+	renamed chan string
+}
+
+func (m *renamingSessionManager) RenameThread(userID int64, id int, title string) error {
+	m.renamed <- title
+	return nil
+}
+
+func TestTextMessageHandler_TitlesFirstExchange(t *testing.T) {
+	sessions := &renamingSessionManager{
+		mockSessionManager: &mockSessionManager{threads: []session.Thread{{ID: 1, Title: "Default"}}, active: 1},
+		renamed:            make(chan string, 1),
+	}
+	router := &mockRouter{response: "\"Planning a trip to Lisbon.\""}
+	handlers := NewHandlers(router, sessions, []int64{1}, WithTitles(config.TitlesConfig{Enabled: true}))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "Help me plan a trip to Lisbon"))
+
+	select {
+	case title := <-sessions.renamed:
+		if title != "Planning a trip to Lisbon" {
+			t.Errorf("unexpected title %q", title)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the thread to be titled")
+	}
+}
+
+func TestTitleThread_KeepsOwnTitles(t *testing.T) {
+	sessions := &renamingSessionManager{
+		mockSessionManager: &mockSessionManager{threads: []session.Thread{{ID: 2, Title: "Taxes"}}, active: 2},
+		renamed:            make(chan string, 1),
+	}
+	handlers := NewHandlers(&mockRouter{response: "Other"}, sessions, []int64{1}, WithTitles(config.TitlesConfig{Enabled: true}))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+
+	select {
+	case title := <-sessions.renamed:
+		t.Errorf("expected a named thread to keep its title, got %q", title)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := map[string]string{
+		"Trip to Lisbon":                "Trip to Lisbon",
+		"**Title:** \"Tax questions\".": "Tax questions",
+		"\n\n  'Budget'  \nmore":        "Budget",
+		"":                              "",
+	}
+	for in, want := range tests {
+		if got := cleanTitle(in); got != want {
+			t.Errorf("cleanTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const defaultTranslateTarget = "English"
+
+var languageNames = map[string]string{
+	"ar": "Arabic",
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"hi": "Hindi",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"sv": "Swedish",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"zh": "Chinese",
+}
+
+func WithTranslateRoute(route config.CommandRouteConfig) Option {
+	return func(h *Handlers) {
+		h.translateRoute = route
+	}
+}
+
+func (h *Handlers) TranslateHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	target, text := parseTranslateArgs(commandArgs(update.Message.Text))
+	if reply := update.Message.ReplyToMessage; reply != nil && text == "" {
+		text = reply.Text
+		if text == "" {
+			text = reply.Caption
+		}
+	}
+
+	if strings.TrimSpace(text) == "" {
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "translate.usage"),
+		})
+		return
+	}
+
+	ctx, done := h.generate(ctx, sender, update.Message.From.ID, chatID)
+	defer done()
+
+	var opts []llm.RequestOption
+	if h.translateRoute.Provider != "" {
+		opts = append(opts, llm.WithProvider(h.translateRoute.Provider))
+	}
+	if h.translateRoute.Model != "" {
+		opts = append(opts, llm.WithModel(h.translateRoute.Model))
+	}
+
+	response, err := h.router.SendMessage(ctx, translateMessages(languageName(target), text), opts...)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		log.Printf("Translation failed for user %d: %v", update.Message.From.ID, err)
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "translate.error"),
+		})
+		return
+	}
+
+	params := &tgbot.SendMessageParams{
+		ChatID: chatID,
+		Text:   response,
+	}
+	if update.Message.ReplyToMessage != nil {
+		params.ReplyParameters = &models.ReplyParameters{MessageID: update.Message.ReplyToMessage.ID}
+	}
+	h.sendReply(ctx, sender, params)
+}
+
+func parseTranslateArgs(args string) (string, string) {
+	if args == "" {
+		return "", ""
+	}
+	i := strings.IndexAny(args, " \n")
+	if i < 0 {
+		return args, ""
+	}
+	return args[:i], strings.TrimSpace(args[i+1:])
+}
+
+func languageName(code string) string {
+	if code == "" {
+		return defaultTranslateTarget
+	}
+	if name, ok := languageNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	return code
+}
+
+func translateMessages(target, text string) []llm.Message {
+	return []llm.Message{
+		{
+			Role: "system",
+			Content: fmt.Sprintf("You are a translation engine. Detect the language of the user's text and translate it into %s. "+
+				"If the text is already in %s, translate it into English instead. "+
+				"Preserve the original formatting exactly, including line breaks, lists, Markdown, code blocks, URLs and emoji. "+
+				"Reply with the translation only, without notes or explanations.", target, target),
+		},
+		{Role: "user", Content: text},
+	}
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestTranslateHandler_ReplyIsStateless(t *testing.T) {
+	router := &mockRouter{response: "Hola mundo"}
+	sessions := &mockSessionManager{messages: []llm.Message{{Role: "user", Content: "earlier"}}}
+	handlers := NewHandlers(router, sessions, []int64{1}, WithTranslateRoute(config.CommandRouteConfig{Provider: "openai", Model: "gpt-4o-mini"}))
+
+	update := makeUpdate(1, 10, "/translate es")
+	update.Message.ReplyToMessage = &models.Message{ID: 42, Text: "Hello world"}
+	bot := &mockBot{}
+	handlers.TranslateHandler(context.Background(), bot, update)
+
+	if len(router.lastMessages) != 2 {
+		t.Fatalf("expected only system and user messages, got %d", len(router.lastMessages))
+	}
+	if !strings.Contains(router.lastMessages[0].Content, "Spanish") {
+		t.Errorf("expected target language in prompt, got %q", router.lastMessages[0].Content)
+	}
+	if router.lastMessages[1].Content != "Hello world" {
+		t.Errorf("expected replied text to be translated, got %q", router.lastMessages[1].Content)
+	}
+	if len(router.lastOpts) != 2 {
+		t.Errorf("expected provider and model options, got %d", len(router.lastOpts))
+	}
+	if bot.lastMessageParams.Text != "Hola mundo" {
+		t.Errorf("expected translation reply, got %q", bot.lastMessageParams.Text)
+	}
+	if bot.lastMessageParams.ReplyParameters == nil || bot.lastMessageParams.ReplyParameters.MessageID != 42 {
+		t.Errorf("expected reply to original message, got %+v", bot.lastMessageParams.ReplyParameters)
+	}
+}
+
+func TestTranslateHandler_InlineText(t *testing.T) {
+	router := &mockRouter{response: "Bonjour"}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+
+	bot := &mockBot{}
+	handlers.TranslateHandler(context.Background(), bot, makeUpdate(1, 10, "/translate fr Good morning"))
+
+	if router.lastMessages[1].Content != "Good morning" || !strings.Contains(router.lastMessages[0].Content, "French") {
+		t.Errorf("unexpected messages: %+v", router.lastMessages)
+	}
+	if len(router.lastOpts) != 0 {
+		t.Errorf("expected no route options, got %d", len(router.lastOpts))
+	}
+}
+
+func TestTranslateHandler_Usage(t *testing.T) {
+	router := &mockRouter{}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+
+	bot := &mockBot{}
+	handlers.TranslateHandler(context.Background(), bot, makeUpdate(1, 10, "/translate es"))
+
+	if !strings.Contains(bot.lastMessageParams.Text, "Usage") {
+		t.Errorf("expected usage message, got %q", bot.lastMessageParams.Text)
+	}
+	if router.lastMessages != nil {
+		t.Error("expected no LLM request without text")
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	tests := map[string]string{"": "English", "ES": "Spanish", "Klingon": "Klingon"}
+	for code, want := range tests {
+		if got := languageName(code); got != want {
+			t.Errorf("languageName(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-telegram/bot/models"
+)
+
+// UsernameCache remembers which user ID each allowed username belongs to.
+// The Bot API cannot look a user up by username, so a username is pinned to
+// the first account that messages the bot with it. The username each user
+// currently carries is kept in memory, so access follows the live name.
+type UsernameCache struct {
+	path    string
+	mu      sync.Mutex
+	ids     map[string]int64
+	current map[int64]string
+}
+
+// NewUsernameCache loads the cache at path. An empty path keeps it in
+// memory only.
+func NewUsernameCache(path string) (*UsernameCache, error) {
+	c := &UsernameCache{path: path, ids: make(map[string]int64), current: make(map[int64]string)}
+	if path == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usernames: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.ids); err != nil {
+		return nil, fmt.Errorf("failed to parse usernames: %w", err)
+	}
+	return c, nil
+}
+
+func (c *UsernameCache) Lookup(username string) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.ids[normalizeUsername(username)]
+	return id, ok
+}
+
+// Seen records the username userID carries right now.
+func (c *UsernameCache) Seen(userID int64, username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current[userID] = normalizeUsername(username)
+}
+
+// Current returns the username userID was last seen with since the start.
+func (c *UsernameCache) Current(userID int64) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current[userID]
+}
+
+// Pin maps username to userID unless it is already pinned, and returns the
+// ID it is pinned to. A new mapping is saved.
+func (c *UsernameCache) Pin(username string, userID int64) (int64, error) {
+	name := normalizeUsername(username)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id, ok := c.ids[name]; ok {
+		return id, nil
+	}
+	c.ids[name] = userID
+	if c.path == "" {
+		return userID, nil
+	}
+
+	data, err := json.Marshal(c.ids)
+	if err != nil {
+		return userID, fmt.Errorf("failed to marshal usernames: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return userID, fmt.Errorf("failed to create usernames directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return userID, fmt.Errorf("failed to write usernames: %w", err)
+	}
+	return userID, nil
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimPrefix(username, "@"))
+}
+
+func WithAllowedUsernames(usernames []string) Option {
+	return func(h *Handlers) {
+		h.allowedNames = usernames
+	}
+}
+
+func WithUsernameCache(c *UsernameCache) Option {
+	return func(h *Handlers) {
+		h.usernames = c
+	}
+}
+
+// resolveUsername records the username user carries and pins it to their
+// ID when it is on the allow list and not pinned yet.
+func (h *Handlers) resolveUsername(user *models.User) {
+	if h.usernames == nil || user == nil {
+		return
+	}
+	h.usernames.Seen(user.ID, user.Username)
+	if user.Username == "" {
+		return
+	}
+	h.authMu.RLock()
+	allowed := h.allowedName(user.Username)
+	h.authMu.RUnlock()
+	if !allowed {
+		return
+	}
+
+	_, known := h.usernames.Lookup(user.Username)
+	id, err := h.usernames.Pin(user.Username, user.ID)
+	if err != nil {
+		log.Printf("Failed to save username of user %d: %v", user.ID, err)
+	}
+	switch {
+	case !known:
+		log.Printf("Resolved @%s to user %d", user.Username, user.ID)
+	case id != user.ID:
+		log.Printf("User %d uses @%s, which is pinned to user %d; not allowing them", user.ID, user.Username, id)
+	}
+}
+
+// allowedByUsername reports whether userID currently carries one of the
+// allowed usernames and is the account it was pinned to. Callers hold
+// authMu.
+func (h *Handlers) allowedByUsername(userID int64) bool {
+	if h.usernames == nil {
+		return false
+	}
+	name := h.usernames.Current(userID)
+	if name == "" || !h.allowedName(name) {
+		return false
+	}
+	id, ok := h.usernames.Lookup(name)
+	return ok && id == userID
+}
+
+// allowedName reports whether username is on the allow list. Callers hold
+// authMu.
+func (h *Handlers) allowedName(username string) bool {
+	return slices.ContainsFunc(h.allowedNames, func(name string) bool {
+		return normalizeUsername(name) == normalizeUsername(username)
+	})
+}
+
+func updateUser(update *models.Update) *models.User {
+	switch {
+	case update.Message != nil:
+		return update.Message.From
+	case update.EditedMessage != nil:
+		return update.EditedMessage.From
+	case update.CallbackQuery != nil:
+		return &update.CallbackQuery.From
+	case update.InlineQuery != nil:
+		return update.InlineQuery.From
+	}
+	return nil
+}
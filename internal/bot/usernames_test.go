@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckAuth_AllowedUsername(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usernames.json")
+	cache, err := NewUsernameCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlers := NewHandlers(&mockRouter{}, &mockSessionManager{}, []int64{1},
+		WithAllowedUsernames([]string{"@Alice_W"}), WithUsernameCache(cache))
+
+	update := makeUpdate(555, 555, "hi")
+	update.Message.From.Username = "alice_w"
+	if !handlers.checkAuth(context.Background(), &mockBot{}, update) {
+		t.Fatal("expected the allowed username to be authorized")
+	}
+
+	// Access follows the live username.
+	update.Message.From.Username = "alice_renamed"
+	if handlers.checkAuth(context.Background(), &mockBot{}, update) {
+		t.Error("expected the account to lose access after changing its username")
+	}
+	if handlers.checkAuth(context.Background(), &mockBot{}, makeUpdate(777, 777, "hi")) {
+		t.Error("expected other users to be rejected")
+	}
+
+	reloaded, err := NewUsernameCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := reloaded.Lookup("ALICE_W"); !ok || id != 555 {
+		t.Errorf("expected the mapping to be saved, got %d, %v", id, ok)
+	}
+
+	// The username stays pinned to the first account.
+	moved := makeUpdate(888, 888, "hi")
+	moved.Message.From.Username = "alice_w"
+	if handlers.checkAuth(context.Background(), &mockBot{}, moved) {
+		t.Error("expected another account taking the username to be rejected")
+	}
+
+	update.Message.From.Username = "alice_w"
+	if !handlers.checkAuth(context.Background(), &mockBot{}, update) {
+		t.Error("expected the pinned account to regain access with its username")
+	}
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const maxImageSize = 10 << 20
+
+type FileDownloader interface {
+	GetFile(ctx context.Context, params *tgbot.GetFileParams) (*models.File, error)
+	FileDownloadLink(f *models.File) string
+}
+
+func IsPhotoMessage(update *models.Update) bool {
+	return update.Message != nil && len(update.Message.Photo) > 0
+}
+
+func (h *Handlers) PhotoHandler(ctx context.Context, b any, update *models.Update) {
+	sender := resolveSender(b)
+	if sender == nil || !IsPhotoMessage(update) {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	downloader, ok := sender.(FileDownloader)
+	if !ok {
+		return
+	}
+
+	photo := largestPhoto(update.Message.Photo)
+	data, err := downloadFile(ctx, downloader, photo.FileID, maxImageSize)
+	if err != nil {
+		log.Printf("Failed to download photo from user %d: %v", update.Message.From.ID, err)
+		sender.SendMessage(ctx, &tgbot.SendMessageParams{
+			ChatID: chatID,
+			Text:   h.tr(update.Message.From, "chat.image_error"),
+		})
+		return
+	}
+
+	h.ask(ctx, sender, update, llm.Message{
+		Role:    "user",
+		Content: attributed(update.Message, strings.TrimSpace(update.Message.Caption)),
+		Images:  []llm.Image{{MIMEType: "image/jpeg", Data: data}},
+	})
+}
+
+func largestPhoto(photos []models.PhotoSize) models.PhotoSize {
+	best := photos[0]
+	for _, p := range photos[1:] {
+		if p.Width*p.Height > best.Width*best.Height {
+			best = p
+		}
+	}
+	return best
+}
+
+func downloadFile(ctx context.Context, downloader FileDownloader, fileID string, maxSize int64) ([]byte, error) {
+	file, err := downloader.GetFile(ctx, &tgbot.GetFileParams{FileID: fileID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.FileSize > maxSize {
+		return nil, fmt.Errorf("file too large: %d bytes", file.FileSize)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloader.FileDownloadLink(file), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file too large")
+	}
+
+	return data, nil
+}
+
+func historyMessage(prompt llm.Message) llm.Message {
+	if len(prompt.Images) == 0 {
+		return prompt
+	}
+	return llm.Message{
+		Role:    prompt.Role,
+		Content: strings.TrimSpace("[Image] " + prompt.Content),
+	}
+}
@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tgbot "github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type mockFileBot struct {
+	mockBot
+	baseURL   string
+	requested string
+}
+
+func (m *mockFileBot) GetFile(ctx context.Context, params *tgbot.GetFileParams) (*models.File, error) {
+	m.requested = params.FileID
+	return &models.File{FileID: params.FileID, FilePath: "photos/" + params.FileID + ".jpg"}, nil
+}
+
+func (m *mockFileBot) FileDownloadLink(f *models.File) string {
+	return m.baseURL + "/" + f.FilePath
+}
+
+func makePhotoUpdate(userID, chatID int64, caption string) *models.Update {
+	update := makeUpdate(userID, chatID, "")
+	update.Message.Caption = caption
+	update.Message.Photo = []models.PhotoSize{
+		{FileID: "small", Width: 90, Height: 90},
+		{FileID: "large", Width: 1280, Height: 960},
+		{FileID: "medium", Width: 320, Height: 240},
+	}
+	return update
+}
+
+func TestPhotoHandler_SendsImageToProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "jpeg-bytes")
+	}))
+	defer server.Close()
+
+	router := &mockRouter{response: "A cat on a sofa."}
+	sessions := &mockSessionManager{}
+	handlers := NewHandlers(router, sessions, []int64{1})
+
+	bot := &mockFileBot{baseURL: server.URL}
+	handlers.PhotoHandler(context.Background(), bot, makePhotoUpdate(1, 1, "What is this?"))
+
+	if bot.requested != "large" {
+		t.Errorf("expected largest photo to be downloaded, got %q", bot.requested)
+	}
+	last := router.lastMessages[len(router.lastMessages)-1]
+	if last.Content != "What is this?" || len(last.Images) != 1 || string(last.Images[0].Data) != "jpeg-bytes" {
+		t.Errorf("expected caption and image in request, got %+v", last)
+	}
+	if last.Images[0].MIMEType != "image/jpeg" {
+		t.Errorf("unexpected MIME type %q", last.Images[0].MIMEType)
+	}
+	if len(sessions.saved) != 2 || sessions.saved[0].Content != "[Image] What is this?" || len(sessions.saved[0].Images) != 0 {
+		t.Errorf("expected image placeholder in history, got %+v", sessions.saved)
+	}
+	if bot.lastMessageParams.Text != `A cat on a sofa\.` {
+		t.Errorf("unexpected reply %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestPhotoHandler_VisionUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "jpeg-bytes")
+	}))
+	defer server.Close()
+
+	router := &mockRouter{err: fmt.Errorf("ollama: %w", llm.ErrVisionUnsupported)}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+
+	bot := &mockFileBot{baseURL: server.URL}
+	handlers.PhotoHandler(context.Background(), bot, makePhotoUpdate(1, 1, ""))
+
+	if !strings.Contains(bot.lastMessageParams.Text, "does not support images") {
+		t.Errorf("expected vision error message, got %q", bot.lastMessageParams.Text)
+	}
+}
+
+func TestPhotoHandler_DownloadError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	router := &mockRouter{}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1})
+
+	bot := &mockFileBot{baseURL: server.URL}
+	handlers.PhotoHandler(context.Background(), bot, makePhotoUpdate(1, 1, ""))
+
+	if !strings.Contains(bot.lastMessageParams.Text, "Error downloading image") {
+		t.Errorf("expected download error, got %q", bot.lastMessageParams.Text)
+	}
+	if router.lastMessages != nil {
+		t.Error("expected no LLM request")
+	}
+}
+
+func TestIsPhotoMessage(t *testing.T) {
+	if IsPhotoMessage(makeUpdate(1, 1, "hi")) {
+		t.Error("expected text message not to match")
+	}
+	if !IsPhotoMessage(makePhotoUpdate(1, 1, "")) {
+		t.Error("expected photo message to match")
+	}
+}
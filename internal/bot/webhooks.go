@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"github.com/jrswab/helpi/internal/webhook"
+)
+
+// WithWebhooks posts answers, provider errors and budget warnings to the
+// endpoints of dispatcher.
+func WithWebhooks(dispatcher *webhook.Dispatcher) Option {
+	return func(h *Handlers) {
+		h.webhooks = dispatcher
+	}
+}
+
+// notify sends an event to the configured webhooks, if any.
+func (h *Handlers) notify(eventType string, userID, chatID int64, data map[string]any) {
+	if h.webhooks == nil {
+		return
+	}
+	h.webhooks.Send(webhook.Event{
+		Type:   eventType,
+		UserID: userID,
+		ChatID: chatID,
+		Data:   data,
+	})
+}
+
+// notifyMessage sends a message event. content only reaches endpoints that
+// include content.
+func (h *Handlers) notifyMessage(userID, chatID int64, data, content map[string]any) {
+	if h.webhooks == nil {
+		return
+	}
+	h.webhooks.Send(webhook.Event{
+		Type:    webhook.EventMessage,
+		UserID:  userID,
+		ChatID:  chatID,
+		Data:    data,
+		Content: content,
+	})
+}
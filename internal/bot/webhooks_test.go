@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/budget"
+	"github.com/jrswab/helpi/internal/webhook"
+)
+
+// newWebhookRecorder returns a dispatcher and a function that waits for its
+// deliveries and returns the events received.
+func newWebhookRecorder(t *testing.T) (*webhook.Dispatcher, func() []webhook.Event) {
+	t.Helper()
+	var mu sync.Mutex
+	var events []webhook.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e webhook.Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("invalid webhook body: %v", err)
+		}
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	d := webhook.New([]webhook.Endpoint{{URL: server.URL, IncludeContent: true}})
+	return d, func() []webhook.Event {
+		d.Wait()
+		mu.Lock()
+		defer mu.Unlock()
+		return events
+	}
+}
+
+func TestTextMessageHandler_SendsMessageWebhook(t *testing.T) {
+	d, received := newWebhookRecorder(t)
+	handlers := NewHandlers(&mockRouter{response: "answer"}, &mockSessionManager{}, []int64{1}, WithWebhooks(d))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+
+	events := received()
+	if len(events) != 1 || events[0].Type != webhook.EventMessage {
+		t.Fatalf("expected one message event, got %+v", events)
+	}
+	if e := events[0]; e.UserID != 1 || e.Data["prompt"] != "hello" || e.Data["answer"] != "answer" {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
+
+func TestTextMessageHandler_SendsErrorWebhook(t *testing.T) {
+	d, received := newWebhookRecorder(t)
+	router := &mockRouter{err: errors.New("provider exploded")}
+	handlers := NewHandlers(router, &mockSessionManager{}, []int64{1}, WithWebhooks(d))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+
+	events := received()
+	if len(events) != 1 || events[0].Type != webhook.EventError || events[0].Data["error"] != "provider exploded" {
+		t.Errorf("expected one error event, got %+v", events)
+	}
+}
+
+func TestTextMessageHandler_SendsUsageWebhook(t *testing.T) {
+	d, received := newWebhookRecorder(t)
+	tracker, _ := budget.NewTracker(filepath.Join(t.TempDir(), "budget.json"), budget.Limits{UserDaily: 20})
+	tracker.Record(1, 15)
+	handlers := NewHandlers(metered(&mockRouter{response: "answer"}, tracker), &mockSessionManager{}, []int64{1}, WithBudget(tracker, 0), WithWebhooks(d))
+
+	handlers.TextMessageHandler(context.Background(), &mockBot{}, makeUpdate(1, 1, "hello"))
+
+	var usage []webhook.Event
+	for _, e := range received() {
+		if e.Type == webhook.EventUsage {
+			usage = append(usage, e)
+		}
+	}
+	if len(usage) != 1 || usage[0].Data["scope"] != "user" {
+		t.Errorf("expected one user usage event, got %+v", usage)
+	}
+}
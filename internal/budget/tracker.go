@@ -0,0 +1,210 @@
+package budget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// warnRatio is the share of a daily budget after which users are warned.
+const warnRatio = 0.8
+
+var (
+	ErrUserBudget   = errors.New("daily budget for user exceeded")
+	ErrGlobalBudget = errors.New("daily global budget exceeded")
+)
+
+// Limits are daily token and spend budgets. Zero means unlimited. Spend is
+// priced with Prices, keyed by "provider/model", model or provider name;
+// requests to models without a price cost nothing.
+type Limits struct {
+	UserDaily   int
+	GlobalDaily int
+	UserSpend   float64
+	GlobalSpend float64
+	Prices      map[string]Price
+}
+
+// Price is what a model costs per million prompt and completion tokens.
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+type Warning int
+
+const (
+	NoWarning Warning = iota
+	UserWarning
+	GlobalWarning
+)
+
+type usage struct {
+	Day       string            `json:"day"`
+	Total     int               `json:"total"`
+	Users     map[int64]int     `json:"users"`
+	Spend     float64           `json:"spend,omitempty"`
+	UserSpend map[int64]float64 `json:"user_spend,omitempty"`
+}
+
+type Tracker struct {
+	path   string
+	limits Limits
+	now    func() time.Time
+
+	mu       sync.Mutex
+	usage    usage
+	warnings map[int64]Warning
+}
+
+func NewTracker(path string, limits Limits) (*Tracker, error) {
+	if path == "" {
+		path = "./data/budget.json"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create budget directory: %w", err)
+	}
+
+	t := &Tracker{path: path, limits: limits, now: time.Now, warnings: make(map[int64]Warning)}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read budget: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &t.usage); err != nil {
+			return nil, fmt.Errorf("failed to parse budget: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// Allow reports whether userID may send another request today. Requests
+// without a user, userID 0, only count against the global budget.
+func (t *Tracker) Allow(userID int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollover()
+	if exceeded(t.usage.Total, t.limits.GlobalDaily) || exceeded(t.usage.Spend, t.limits.GlobalSpend) {
+		return ErrGlobalBudget
+	}
+	if userID != 0 && (exceeded(t.usage.Users[userID], t.limits.UserDaily) || exceeded(t.usage.UserSpend[userID], t.limits.UserSpend)) {
+		return ErrUserBudget
+	}
+	return nil
+}
+
+// Record adds tokens to today's usage and reports whether this request took
+// the user or the whole bot past the warning threshold.
+func (t *Tracker) Record(userID int64, tokens int) (Warning, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.record(userID, tokens, 0)
+}
+
+// Charge records u for userID and implements llm.Meter, so every request
+// sent through the router counts. A warning it causes is kept for TakeWarning.
+func (t *Tracker) Charge(userID int64, u llm.Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	warning, err := t.record(userID, u.PromptTokens+u.CompletionTokens, t.cost(u))
+	if err != nil {
+		log.Printf("Failed to record usage for user %d: %v", userID, err)
+	}
+	if warning != NoWarning {
+		t.warnings[userID] = warning
+	}
+}
+
+// TakeWarning returns and forgets the warning the latest charges for userID
+// caused, if any.
+func (t *Tracker) TakeWarning(userID int64) Warning {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	warning := t.warnings[userID]
+	delete(t.warnings, userID)
+	return warning
+}
+
+func (t *Tracker) record(userID int64, tokens int, cost float64) (Warning, error) {
+	t.rollover()
+	userBefore, totalBefore := t.usage.Users[userID], t.usage.Total
+	userSpendBefore, spendBefore := t.usage.UserSpend[userID], t.usage.Spend
+	t.usage.Total += tokens
+	t.usage.Spend += cost
+	if userID != 0 {
+		t.usage.Users[userID] += tokens
+		t.usage.UserSpend[userID] += cost
+	}
+
+	warning := NoWarning
+	if crossed(totalBefore, t.usage.Total, t.limits.GlobalDaily) || crossed(spendBefore, t.usage.Spend, t.limits.GlobalSpend) {
+		warning = GlobalWarning
+	}
+	if userID != 0 && (crossed(userBefore, t.usage.Users[userID], t.limits.UserDaily) || crossed(userSpendBefore, t.usage.UserSpend[userID], t.limits.UserSpend)) {
+		warning = UserWarning
+	}
+	return warning, t.save()
+}
+
+// cost prices u with the most specific matching entry of Limits.Prices.
+func (t *Tracker) cost(u llm.Usage) float64 {
+	for _, key := range []string{u.Provider + "/" + u.Model, u.Model, u.Provider} {
+		if price, ok := t.limits.Prices[key]; ok {
+			return (float64(u.PromptTokens)*price.Input + float64(u.CompletionTokens)*price.Output) / 1e6
+		}
+	}
+	return 0
+}
+
+// Used returns today's token usage for userID and for all users.
+func (t *Tracker) Used(userID int64) (user, total int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollover()
+	return t.usage.Users[userID], t.usage.Total
+}
+
+func (t *Tracker) rollover() {
+	if day := t.now().Format(time.DateOnly); t.usage.Day != day {
+		t.usage = usage{Day: day}
+	}
+	if t.usage.Users == nil {
+		t.usage.Users = make(map[int64]int)
+	}
+	if t.usage.UserSpend == nil {
+		t.usage.UserSpend = make(map[int64]float64)
+	}
+}
+
+func (t *Tracker) save() error {
+	data, err := json.Marshal(t.usage)
+	if err != nil {
+		return fmt.Errorf("failed to marshal budget: %w", err)
+	}
+	if err := os.WriteFile(t.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write budget: %w", err)
+	}
+	return nil
+}
+
+func exceeded[T int | float64](used, limit T) bool {
+	return limit > 0 && used >= limit
+}
+
+func crossed[T int | float64](before, after, limit T) bool {
+	threshold := T(float64(limit) * warnRatio)
+	return limit > 0 && before < threshold && after >= threshold
+}
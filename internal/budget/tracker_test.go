@@ -0,0 +1,122 @@
+package budget
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestTracker_WarnsThenRefuses(t *testing.T) {
+	tracker, err := NewTracker(filepath.Join(t.TempDir(), "budget.json"), Limits{UserDaily: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w, _ := tracker.Record(1, 50); w != NoWarning {
+		t.Errorf("expected no warning at 50%%, got %v", w)
+	}
+	if w, _ := tracker.Record(1, 30); w != UserWarning {
+		t.Errorf("expected user warning at 80%%, got %v", w)
+	}
+	if w, _ := tracker.Record(1, 5); w != NoWarning {
+		t.Errorf("expected warning only once, got %v", w)
+	}
+	if err := tracker.Allow(1); err != nil {
+		t.Errorf("expected request under budget to be allowed, got %v", err)
+	}
+
+	tracker.Record(1, 15)
+	if err := tracker.Allow(1); !errors.Is(err, ErrUserBudget) {
+		t.Errorf("expected ErrUserBudget, got %v", err)
+	}
+	if err := tracker.Allow(2); err != nil {
+		t.Errorf("expected other users to be unaffected, got %v", err)
+	}
+}
+
+func TestTracker_GlobalLimit(t *testing.T) {
+	tracker, _ := NewTracker(filepath.Join(t.TempDir(), "budget.json"), Limits{GlobalDaily: 10})
+
+	if w, _ := tracker.Record(1, 8); w != GlobalWarning {
+		t.Errorf("expected global warning, got %v", w)
+	}
+	tracker.Record(2, 2)
+	if err := tracker.Allow(3); !errors.Is(err, ErrGlobalBudget) {
+		t.Errorf("expected ErrGlobalBudget, got %v", err)
+	}
+}
+
+func TestTracker_PersistsAndResetsDaily(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budget.json")
+	tracker, _ := NewTracker(path, Limits{UserDaily: 10})
+	tracker.Record(1, 10)
+
+	reloaded, err := NewTracker(path, Limits{UserDaily: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.Allow(1); !errors.Is(err, ErrUserBudget) {
+		t.Errorf("expected usage to survive a restart, got %v", err)
+	}
+
+	reloaded.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+	if err := reloaded.Allow(1); err != nil {
+		t.Errorf("expected budget to reset the next day, got %v", err)
+	}
+}
+
+func TestTracker_PricesSpendByModel(t *testing.T) {
+	tracker, _ := NewTracker(filepath.Join(t.TempDir(), "budget.json"), Limits{
+		UserSpend: 1,
+		Prices: map[string]Price{
+			"gpt-4o":       {Input: 2.5, Output: 10},
+			"openai/gpt-x": {Input: 100_000, Output: 100_000},
+		},
+	})
+
+	tracker.Charge(1, llm.Usage{Provider: "openai", Model: "gpt-4o", PromptTokens: 100_000, CompletionTokens: 50_000})
+	if err := tracker.Allow(1); err != nil {
+		t.Errorf("expected $0.75 to be under the $1 budget, got %v", err)
+	}
+	if w := tracker.TakeWarning(1); w != NoWarning {
+		t.Errorf("expected no warning below 80%%, got %v", w)
+	}
+
+	tracker.Charge(1, llm.Usage{Provider: "ollama", Model: "llama3", PromptTokens: 1_000_000})
+	if err := tracker.Allow(1); err != nil {
+		t.Errorf("expected unpriced models to cost nothing, got %v", err)
+	}
+
+	tracker.Charge(1, llm.Usage{Provider: "openai", Model: "gpt-4o", PromptTokens: 20_000})
+	if w := tracker.TakeWarning(1); w != UserWarning {
+		t.Errorf("expected user warning at 80%%, got %v", w)
+	}
+	if w := tracker.TakeWarning(1); w != NoWarning {
+		t.Errorf("expected the warning to be taken once, got %v", w)
+	}
+
+	tracker.Charge(1, llm.Usage{Provider: "openai", Model: "gpt-x", PromptTokens: 10})
+	if err := tracker.Allow(1); !errors.Is(err, ErrUserBudget) {
+		t.Errorf("expected the provider/model price to apply, got %v", err)
+	}
+}
+
+func TestTracker_UnattributedRequestsOnlyCountGlobally(t *testing.T) {
+	tracker, _ := NewTracker(filepath.Join(t.TempDir(), "budget.json"), Limits{UserDaily: 10, GlobalDaily: 100})
+
+	tracker.Charge(0, llm.Usage{PromptTokens: 50})
+	if err := tracker.Allow(0); err != nil {
+		t.Errorf("expected the user limit not to apply without a user, got %v", err)
+	}
+	if _, total := tracker.Used(1); total != 50 {
+		t.Errorf("expected 50 tokens globally, got %d", total)
+	}
+
+	tracker.Charge(0, llm.Usage{PromptTokens: 50})
+	if err := tracker.Allow(0); !errors.Is(err, ErrGlobalBudget) {
+		t.Errorf("expected ErrGlobalBudget, got %v", err)
+	}
+}
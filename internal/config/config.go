@@ -1,20 +1,133 @@
 package config
 
 type Config struct {
-	Telegram     TelegramConfig    `yaml:"telegram"`
-	AllowedUsers []int64           `yaml:"allowed_users"`
-	Providers    ProvidersConfig   `yaml:"providers"`
-	Memory       MemoryConfig      `yaml:"memory"`
-	APIKeys      map[string]string `yaml:"-"`
+	Strict           bool                          `yaml:"strict"`
+	Telegram         TelegramConfig                `yaml:"telegram"`
+	Bots             []BotConfig                   `yaml:"bots"`
+	AllowedUsers     []int64                       `yaml:"allowed_users"`
+	AllowedUsernames []string                      `yaml:"allowed_usernames"`
+	Admins           []int64                       `yaml:"admins"`
+	Roles            RolesConfig                   `yaml:"roles"`
+	Access           AccessConfig                  `yaml:"access"`
+	Providers        ProvidersConfig               `yaml:"providers"`
+	Memory           MemoryConfig                  `yaml:"memory"`
+	OfflineQueue     OfflineQueueConfig            `yaml:"offline_queue"`
+	Offline          OfflineConfig                 `yaml:"offline"`
+	ScheduledPrompts []ScheduledPromptConfig       `yaml:"scheduled_prompts"`
+	Batch            BatchConfig                   `yaml:"batch"`
+	Commands         map[string]CommandRouteConfig `yaml:"commands"`
+	Personas         []PersonaConfig               `yaml:"personas"`
+	Feedback         FeedbackConfig                `yaml:"feedback"`
+	Digest           DigestConfig                  `yaml:"digest"`
+	Translate        CommandRouteConfig            `yaml:"translate"`
+	Titles           TitlesConfig                  `yaml:"titles"`
+	Postprocess      []PostprocessConfig           `yaml:"postprocess"`
+	Prefixes         PrefixConfig                  `yaml:"prefixes"`
+	Groups           GroupsConfig                  `yaml:"groups"`
+	RateLimit        RateLimitConfig               `yaml:"rate_limit"`
+	Health           HealthConfig                  `yaml:"health"`
+	API              APIConfig                     `yaml:"api"`
+	Documents        DocumentsConfig               `yaml:"documents"`
+	Budget           BudgetConfig                  `yaml:"budget"`
+	Routing          RoutingConfig                 `yaml:"routing"`
+	Redaction        RedactionConfig               `yaml:"redaction"`
+	Secrets          SecretsConfig                 `yaml:"secrets"`
+	Updates          UpdatesConfig                 `yaml:"updates"`
+	Feeds            FeedsConfig                   `yaml:"feeds"`
+	QuietHours       QuietHoursConfig              `yaml:"quiet_hours"`
+	Storage          StorageConfig                 `yaml:"storage"`
+	Continuation     ContinuationConfig            `yaml:"continuation"`
+	ProviderAccess   ProviderAccessConfig          `yaml:"provider_access"`
+	Webhooks         []WebhookConfig               `yaml:"webhooks"`
+	MQTT             MQTTConfig                    `yaml:"mqtt"`
+	APIKeys          map[string]string             `yaml:"-"`
+
+	// lines maps field paths such as memory.max_messages to their line in
+	// config.yaml.
+	lines   map[string]int
+	unknown error
 }
 
+// TelegramConfig holds the bot token and chat behavior. QuoteReplies sends
+// answers as replies to the question they answer: "groups" in group chats
+// only, "always" everywhere.
 type TelegramConfig struct {
-	Token string `yaml:"token"`
+	Token           string          `yaml:"token"`
+	DefaultLanguage string          `yaml:"default_language"`
+	DebounceSeconds int             `yaml:"debounce_seconds"`
+	ReprocessEdits  bool            `yaml:"reprocess_edits"`
+	Reactions       ReactionsConfig `yaml:"reactions"`
+	Inline          InlineConfig    `yaml:"inline"`
+	AdminChatID     int64           `yaml:"admin_chat_id"`
+	QuoteReplies    string          `yaml:"quote_replies"`
+}
+
+// InlineConfig enables answering @bot <question> from any chat. Inline
+// queries have their own rate limit, separate from rate_limit, of
+// QueriesPerMinute per user, 10 when unset. Unlimited turns the limit off.
+type InlineConfig struct {
+	Enabled          bool `yaml:"enabled"`
+	Unlimited        bool `yaml:"unlimited"`
+	QueriesPerMinute int  `yaml:"queries_per_minute"`
+	Burst            int  `yaml:"burst"`
+}
+
+// BotConfig is an additional bot run by the same process. Bots share the
+// providers and the memory backend; each has its own token and access list.
+type BotConfig struct {
+	Name             string   `yaml:"name"`
+	Token            string   `yaml:"token"`
+	TokenEnv         string   `yaml:"token_env"`
+	AllowedUsers     []int64  `yaml:"allowed_users"`
+	AllowedUsernames []string `yaml:"allowed_usernames"`
+	SystemPrompt     string   `yaml:"system_prompt"`
+	DefaultProvider  string   `yaml:"default_provider"`
+}
+
+// ReactionsConfig sets the emoji the bot reacts with while it works on a
+// message and once it has answered or failed.
+type ReactionsConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Processing string `yaml:"processing"`
+	Done       string `yaml:"done"`
+	Error      string `yaml:"error"`
 }
 
 type ProviderConfig struct {
-	Enabled      bool   `yaml:"enabled"`
-	DefaultModel string `yaml:"default_model"`
+	Enabled        bool     `yaml:"enabled"`
+	DefaultModel   string   `yaml:"default_model"`
+	API            string   `yaml:"api"`
+	Tools          []string `yaml:"tools"`
+	VectorStoreIDs []string `yaml:"vector_store_ids"`
+	Store          bool     `yaml:"store"`
+	SystemPrompt   string   `yaml:"system_prompt"`
+	TimeoutSeconds int      `yaml:"timeout_seconds"`
+	// MaxRetries is how often failed requests are retried, 2 when unset;
+	// 0 turns retries off.
+	MaxRetries   *int   `yaml:"max_retries"`
+	RetryBackoff string `yaml:"retry_backoff"`
+
+	Temperature      *float64 `yaml:"temperature"`
+	TopP             *float64 `yaml:"top_p"`
+	FrequencyPenalty *float64 `yaml:"frequency_penalty"`
+	MaxTokens        int      `yaml:"max_tokens"`
+
+	ReasoningEffort string `yaml:"reasoning_effort"`
+	ShowReasoning   bool   `yaml:"show_reasoning"`
+	// ThinkingBudget turns on Anthropic's extended thinking with that many
+	// tokens to think in.
+	ThinkingBudget int `yaml:"thinking_budget"`
+	// Think is what happens to <think> blocks in answers: "keep" (the
+	// default), "strip" or "spoiler".
+	Think string `yaml:"think"`
+
+	KeepAlive string `yaml:"keep_alive"`
+	NumCtx    int    `yaml:"num_ctx"`
+
+	// Proxy is an http, https or socks5 proxy URL, or "none" to connect
+	// directly even when providers.proxy is set.
+	Proxy   string            `yaml:"proxy"`
+	Headers map[string]string `yaml:"headers"`
 }
 
 type ProvidersConfig struct {
@@ -23,9 +136,390 @@ type ProvidersConfig struct {
 	OpenRouter ProviderConfig `yaml:"openrouter"`
 	OpenCode   ProviderConfig `yaml:"opencode"`
 	Ollama     ProviderConfig `yaml:"ollama"`
+	// Local is an OpenAI-compatible server on this machine, such as LM
+	// Studio or llama.cpp, found by probing their usual ports.
+	Local ProviderConfig `yaml:"local"`
+
+	// Proxy is used by providers without a proxy of their own.
+	Proxy string `yaml:"proxy"`
+
+	OpenAICompatible []CustomProviderConfig `yaml:"openai_compatible"`
+}
+
+type CustomProviderConfig struct {
+	Name           string `yaml:"name"`
+	BaseURL        string `yaml:"base_url"`
+	APIKeyEnv      string `yaml:"api_key_env"`
+	ProviderConfig `yaml:",inline"`
 }
 
 type MemoryConfig struct {
+	Backend     string          `yaml:"backend"`
+	Path        string          `yaml:"path"`
+	MaxMessages int             `yaml:"max_messages"`
+	MaxTokens   int             `yaml:"max_tokens"`
+	TTLDays     int             `yaml:"ttl_days"`
+	Archive     bool            `yaml:"archive_expired"`
+	Strategy    string          `yaml:"strategy"`
+	Pruning     string          `yaml:"pruning"`
+	Relevance   RelevanceConfig `yaml:"relevance"`
+	RAG         RAGConfig       `yaml:"rag"`
+	Dedup       DedupConfig     `yaml:"dedup"`
+	Facts       FactsConfig     `yaml:"facts"`
+	Cache       CacheConfig     `yaml:"cache"`
+}
+
+type CacheConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Addr       string `yaml:"addr"`
+	DB         int    `yaml:"db"`
+	TTLSeconds int    `yaml:"ttl_seconds"`
+}
+
+type RelevanceConfig struct {
+	Provider string `yaml:"provider"`
+	Model    string `yaml:"model"`
+	TopK     int    `yaml:"top_k"`
+	Recent   int    `yaml:"recent"`
+}
+
+type RAGConfig struct {
+	Enabled  bool    `yaml:"enabled"`
+	Provider string  `yaml:"provider"`
+	Model    string  `yaml:"model"`
+	Path     string  `yaml:"path"`
+	TopK     int     `yaml:"top_k"`
+	MinScore float64 `yaml:"min_score"`
+}
+
+// DedupConfig leaves turns that repeat a later one out of requests. Turns
+// count as repeats when their embeddings have a cosine similarity of at
+// least Threshold.
+type DedupConfig struct {
+	Enabled   bool    `yaml:"enabled"`
+	Provider  string  `yaml:"provider"`
+	Model     string  `yaml:"model"`
+	Threshold float64 `yaml:"threshold"`
+}
+
+// FactsConfig enables /remember. Facts are kept per user under Path and
+// added to the system prompt of every request. With Extract, the model also
+// picks facts out of each exchange.
+type FactsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+	Extract bool   `yaml:"extract"`
+}
+
+type OfflineQueueConfig struct {
+	Enabled              bool   `yaml:"enabled"`
+	Path                 string `yaml:"path"`
+	CheckIntervalSeconds int    `yaml:"check_interval_seconds"`
+}
+
+// OfflineConfig answers with a local provider while cloud providers cannot
+// be reached. They are probed every ProbeSeconds to notice when they are
+// back.
+type OfflineConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	Provider     string `yaml:"provider"`
+	Model        string `yaml:"model"`
+	ProbeSeconds int    `yaml:"probe_seconds"`
+}
+
+// ScheduledPromptConfig sends the answer to Prompt to ChatID every day at
+// At, through the bot named Bot or the first one.
+type ScheduledPromptConfig struct {
+	Name     string `yaml:"name"`
+	ChatID   int64  `yaml:"chat_id"`
+	At       string `yaml:"at"`
+	Prompt   string `yaml:"prompt"`
+	Provider string `yaml:"provider"`
+	Priority string `yaml:"priority"`
+	Bot      string `yaml:"bot"`
+}
+
+type BatchConfig struct {
+	Path                string `yaml:"path"`
+	PollIntervalSeconds int    `yaml:"poll_interval_seconds"`
+}
+
+type CommandRouteConfig struct {
+	Description string `yaml:"description"`
+	Provider    string `yaml:"provider"`
+	Model       string `yaml:"model"`
+}
+
+// PersonaConfig is a profile a chat can pick with /persona. Provider,
+// model and temperature are optional and default to the user's choice.
+type PersonaConfig struct {
+	Name         string   `yaml:"name"`
+	Description  string   `yaml:"description"`
+	SystemPrompt string   `yaml:"system_prompt"`
+	Provider     string   `yaml:"provider"`
+	Model        string   `yaml:"model"`
+	Temperature  *float64 `yaml:"temperature"`
+}
+
+// UsernamesPath caches the user IDs that allowed_usernames resolve to.
+type AccessConfig struct {
+	ReportUnauthorized bool   `yaml:"report_unauthorized"`
+	ApprovedPath       string `yaml:"approved_path"`
+	UsernamesPath      string `yaml:"usernames_path"`
+}
+
+// FeedbackConfig stores feedback sent with /feedback and answers reported
+// as bad at Path. Buttons adds 👍 and 👎 buttons to every answer.
+type FeedbackConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+	Buttons bool   `yaml:"buttons"`
+}
+
+// DigestConfig schedules the conversation digests users opt in to with
+// /digest. Daily digests are sent at At, weekly ones at At on Weekday.
+type DigestConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	At       string `yaml:"at"`
+	Weekday  string `yaml:"weekday"`
+	Provider string `yaml:"provider"`
+	Path     string `yaml:"path"`
+}
+
+// GroupsConfig sets how group chats share conversations. ChatContext,
+// "description" or "pinned", adds the chat's description or pinned message
+// to every request made in it.
+type GroupsConfig struct {
+	DefaultMode string `yaml:"default_mode"`
+	Path        string `yaml:"path"`
+	ChatContext string `yaml:"chat_context"`
+}
+
+type DocumentsConfig struct {
+	Enabled     bool   `yaml:"enabled"`
 	Path        string `yaml:"path"`
-	MaxMessages int    `yaml:"max_messages"`
+	MaxExcerpts int    `yaml:"max_excerpts"`
+}
+
+type RoutingConfig struct {
+	ShowRoute bool                `yaml:"show_route"`
+	Rules     []RoutingRuleConfig `yaml:"rules"`
+}
+
+type RoutingRuleConfig struct {
+	Name      string   `yaml:"name"`
+	Provider  string   `yaml:"provider"`
+	Model     string   `yaml:"model"`
+	Pattern   string   `yaml:"pattern"`
+	Keywords  []string `yaml:"keywords"`
+	MinLength int      `yaml:"min_length"`
+	MaxLength int      `yaml:"max_length"`
+	MinTokens int      `yaml:"min_tokens"`
+}
+
+// RedactionConfig masks personal data in requests to cloud providers.
+// Types are email, phone and credit_card; all three are used when unset.
+type RedactionConfig struct {
+	Enabled         bool                     `yaml:"enabled"`
+	Types           []string                 `yaml:"types"`
+	Patterns        []RedactionPatternConfig `yaml:"patterns"`
+	ExemptProviders []string                 `yaml:"exempt_providers"`
+}
+
+type RedactionPatternConfig struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+// ProviderAccessConfig limits which providers and models users can use.
+// Users listed under Users get their own grant and everyone else gets
+// Default.
+type ProviderAccessConfig struct {
+	Default ProviderGrantConfig           `yaml:"default"`
+	Users   map[int64]ProviderGrantConfig `yaml:"users"`
+}
+
+// ProviderGrantConfig lists allowed providers and models. An empty list
+// allows all of them.
+type ProviderGrantConfig struct {
+	Providers []string `yaml:"providers"`
+	Models    []string `yaml:"models"`
+}
+
+// RolesConfig sorts users into roles. Owners run operator commands such as
+// /admin, admins review feedback and approve access requests, users chat,
+// and guests chat at a lower rate limit with the providers and models in
+// Guest only. allowed_users and admins are read as users and admins.
+type RolesConfig struct {
+	Owners []int64     `yaml:"owners"`
+	Admins []int64     `yaml:"admins"`
+	Users  []int64     `yaml:"users"`
+	Guests []int64     `yaml:"guests"`
+	Guest  GuestConfig `yaml:"guest"`
+}
+
+type GuestConfig struct {
+	MessagesPerMinute   int `yaml:"messages_per_minute"`
+	Burst               int `yaml:"burst"`
+	ProviderGrantConfig `yaml:",inline"`
+}
+
+// TitlesConfig has threads named by the model after their first exchange.
+// Provider and Model can pick a cheaper model than the user's.
+type TitlesConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Provider string `yaml:"provider"`
+	Model    string `yaml:"model"`
+}
+
+// PostprocessConfig is one filter answers pass through before they are
+// sent. Filters run in the order they are listed: strip_think,
+// collapse_blank_lines, max_length with MaxLength, or translate into
+// Language, optionally with Provider and Model.
+type PostprocessConfig struct {
+	Filter    string `yaml:"filter"`
+	MaxLength int    `yaml:"max_length"`
+	Language  string `yaml:"language"`
+	Provider  string `yaml:"provider"`
+	Model     string `yaml:"model"`
+}
+
+// PrefixConfig lets a message start with @provider: or !provider, with an
+// optional /model, to send just that message elsewhere. Aliases are extra
+// names for a provider and model, such as claude for anthropic.
+type PrefixConfig struct {
+	Enabled bool                          `yaml:"enabled"`
+	Aliases map[string]CommandRouteConfig `yaml:"aliases"`
+}
+
+// BudgetConfig caps what the bot may use per day. The spend limits are in
+// the currency of Prices, which price models per million tokens and are
+// keyed by "provider/model", model or provider name.
+type BudgetConfig struct {
+	MaxInputTokens    int                    `yaml:"max_input_tokens"`
+	DailyUserTokens   int                    `yaml:"daily_user_tokens"`
+	DailyGlobalTokens int                    `yaml:"daily_global_tokens"`
+	DailyUserSpend    float64                `yaml:"daily_user_spend"`
+	DailyGlobalSpend  float64                `yaml:"daily_global_spend"`
+	Prices            map[string]PriceConfig `yaml:"prices"`
+	Path              string                 `yaml:"path"`
+}
+
+// Enabled reports whether any daily limit is set.
+func (b BudgetConfig) Enabled() bool {
+	return b.DailyUserTokens > 0 || b.DailyGlobalTokens > 0 || b.DailyUserSpend > 0 || b.DailyGlobalSpend > 0
+}
+
+// PriceConfig is what a model costs per million prompt (input) and
+// completion (output) tokens.
+type PriceConfig struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+type HealthConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+}
+
+// APIConfig serves POST /api/notify on Addr, which sends messages to chats
+// for callers presenting HELPI_API_TOKEN as a bearer token. Addr defaults to
+// the loopback interface, so other machines cannot reach it unless asked.
+type APIConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+}
+
+type RateLimitConfig struct {
+	MessagesPerMinute int `yaml:"messages_per_minute"`
+	Burst             int `yaml:"burst"`
+}
+
+// SecretsConfig names a store to read API keys and tokens from when they are
+// not in the environment. Path is the Vault secret path for vault and the
+// encrypted file for sops.
+type SecretsConfig struct {
+	Backend string `yaml:"backend"`
+	Path    string `yaml:"path"`
+	Address string `yaml:"address"`
+}
+
+// FeedsConfig enables /watch. Watched feeds are checked every
+// IntervalMinutes, and new items are summarized with Provider when Summarize
+// is set.
+type FeedsConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Path            string `yaml:"path"`
+	IntervalMinutes int    `yaml:"interval_minutes"`
+	Summarize       bool   `yaml:"summarize"`
+	Provider        string `yaml:"provider"`
+}
+
+// QuietHoursConfig holds digests, feed items and scheduled prompts during
+// Hours, such as "22:00-07:00" in server time, for chats that have not set
+// their own with /quiet. Held messages are kept at Path.
+type QuietHoursConfig struct {
+	Hours string `yaml:"hours"`
+	Path  string `yaml:"path"`
+}
+
+// StorageConfig caps the disk used by file sessions and documents, per
+// user and in total. When a limit is exceeded the oldest conversations and
+// documents are removed. Zero means no limit.
+type StorageConfig struct {
+	UserMB  int `yaml:"user_mb"`
+	TotalMB int `yaml:"total_mb"`
+}
+
+// ContinuationConfig handles answers cut off by a provider's max_tokens.
+// Up to Max follow-up requests ask the model to continue, and the parts are
+// sent as one answer. With Button, an answer that is still cut off gets a
+// button that asks for more.
+type ContinuationConfig struct {
+	Max    int  `yaml:"max"`
+	Button bool `yaml:"button"`
+}
+
+// WebhookConfig posts events to URL as JSON. Events limits them to
+// "message", "error" and "usage_threshold"; empty means all of them. When
+// SecretEnv is set, deliveries are signed with the secret it names. Message
+// events only carry the prompt and answer with IncludeContent.
+type WebhookConfig struct {
+	URL            string   `yaml:"url"`
+	SecretEnv      string   `yaml:"secret_env"`
+	Events         []string `yaml:"events"`
+	IncludeContent bool     `yaml:"include_content"`
+}
+
+// MQTTConfig connects to an MQTT broker, such as the one Home Assistant
+// uses. Messages on each subscription's topic are sent to its chat, and /ha
+// publishes commands to CommandTopic. The password is read from
+// MQTT_PASSWORD.
+type MQTTConfig struct {
+	Enabled       bool                     `yaml:"enabled"`
+	Broker        string                   `yaml:"broker"`
+	ClientID      string                   `yaml:"client_id"`
+	Username      string                   `yaml:"username"`
+	CommandTopic  string                   `yaml:"command_topic"`
+	Subscriptions []MQTTSubscriptionConfig `yaml:"subscriptions"`
+}
+
+// MQTTSubscriptionConfig forwards messages on Topic to ChatID through the
+// bot named Bot or the first one. With a Prompt, the model's answer to the
+// prompt and the message is sent instead.
+type MQTTSubscriptionConfig struct {
+	Topic    string `yaml:"topic"`
+	ChatID   int64  `yaml:"chat_id"`
+	Prompt   string `yaml:"prompt"`
+	Provider string `yaml:"provider"`
+	Bot      string `yaml:"bot"`
+}
+
+// UpdatesConfig turns on a daily check for newer helpi releases at At.
+// Admins are told about each new release once; the last one they were told
+// about is kept at Path.
+type UpdatesConfig struct {
+	Check bool   `yaml:"check"`
+	At    string `yaml:"at"`
+	Path  string `yaml:"path"`
 }
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+func Changes(old, cur *Config) []string {
+	var changes []string
+
+	if added, removed := diffIDs(old.AllowedUsers, cur.AllowedUsers); len(added)+len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("allowed_users: added %v, removed %v", added, removed))
+	}
+	if added, removed := diffIDs(old.Admins, cur.Admins); len(added)+len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("admins: added %v, removed %v", added, removed))
+	}
+	if added, removed := diffIDs(old.Roles.Owners, cur.Roles.Owners); len(added)+len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("roles.owners: added %v, removed %v", added, removed))
+	}
+	if added, removed := diffIDs(old.Roles.Guests, cur.Roles.Guests); len(added)+len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("roles.guests: added %v, removed %v", added, removed))
+	}
+
+	oldProviders, curProviders := providerSettings(old), providerSettings(cur)
+	for _, name := range sortedKeys(oldProviders, curProviders) {
+		before, after := oldProviders[name], curProviders[name]
+		switch {
+		case !before.Enabled && after.Enabled:
+			changes = append(changes, fmt.Sprintf("provider %s enabled", name))
+		case before.Enabled && !after.Enabled:
+			changes = append(changes, fmt.Sprintf("provider %s disabled", name))
+		case !reflect.DeepEqual(before, after):
+			changes = append(changes, fmt.Sprintf("provider %s settings changed", name))
+		}
+	}
+
+	for _, key := range sortedKeys(old.APIKeys, cur.APIKeys) {
+		if old.APIKeys[key] != cur.APIKeys[key] {
+			changes = append(changes, fmt.Sprintf("%s changed", key))
+		}
+	}
+
+	if old.Telegram.Token != cur.Telegram.Token {
+		changes = append(changes, "telegram token changed (requires restart)")
+	}
+
+	return changes
+}
+
+func providerSettings(cfg *Config) map[string]ProviderConfig {
+	settings := map[string]ProviderConfig{
+		"openai":     cfg.Providers.OpenAI,
+		"anthropic":  cfg.Providers.Anthropic,
+		"openrouter": cfg.Providers.OpenRouter,
+		"opencode":   cfg.Providers.OpenCode,
+		"ollama":     cfg.Providers.Ollama,
+		"local":      cfg.Providers.Local,
+	}
+	for _, c := range cfg.Providers.OpenAICompatible {
+		settings[c.Name] = c.ProviderConfig
+	}
+	return settings
+}
+
+func diffIDs(old, cur []int64) (added, removed []int64) {
+	before := make(map[int64]bool, len(old))
+	for _, id := range old {
+		before[id] = true
+	}
+	after := make(map[int64]bool, len(cur))
+	for _, id := range cur {
+		after[id] = true
+		if !before[id] {
+			added = append(added, id)
+		}
+	}
+	for _, id := range old {
+		if !after[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
+
+func sortedKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const envPrefix = "HELPI"
+
+func envOnlyMode() bool {
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, envPrefix+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+func applyEnvOverrides(cfg *Config) error {
+	return applyEnv(reflect.ValueOf(cfg).Elem(), envPrefix)
+}
+
+func applyEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			next := prefix + "_" + strings.ToUpper(tag)
+			if opts == "inline" {
+				next = prefix
+			}
+			if err := applyEnv(fv, next); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := prefix + "_" + strings.ToUpper(tag)
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(fv, raw); err != nil {
+			return &ConfigError{Field: name, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+func setFromEnv(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := setFromEnv(elem.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice:
+			return fmt.Errorf("cannot be set from the environment, use config.yaml")
+		}
+		items := splitList(raw)
+		slice := reflect.MakeSlice(v.Type(), 0, len(items))
+		for _, item := range items {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setFromEnv(elem, item); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("cannot be set from the environment, use config.yaml")
+	}
+	return nil
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,12 +1,23 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/jrswab/helpi/internal/i18n"
+	"github.com/jrswab/helpi/internal/quiet"
+	"github.com/jrswab/helpi/internal/secrets"
+	"github.com/jrswab/helpi/internal/webhook"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,47 +25,249 @@ type ConfigError struct {
 	Field   string
 	Message string
 	Path    string
+	Line    int
 }
 
 func (e *ConfigError) Error() string {
-	if e.Path != "" {
-		return fmt.Sprintf("%s: %s (path: %s)", e.Field, e.Message, e.Path)
-	}
+	msg := e.Message
 	if e.Field != "" {
-		return fmt.Sprintf("%s: %s", e.Field, e.Message)
+		msg = e.Field + ": " + msg
+	}
+	switch {
+	case e.Path != "" && e.Line > 0:
+		return fmt.Sprintf("%s (path: %s, line %d)", msg, e.Path, e.Line)
+	case e.Path != "":
+		return fmt.Sprintf("%s (path: %s)", msg, e.Path)
+	case e.Line > 0:
+		return fmt.Sprintf("%s (line %d)", msg, e.Line)
+	}
+	return msg
+}
+
+var processEnv = environKeys()
+
+func environKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, kv := range os.Environ() {
+		if key, _, ok := strings.Cut(kv, "="); ok {
+			keys[key] = true
+		}
 	}
-	return e.Message
+	return keys
 }
 
 func Load() (*Config, error) {
-	dir, err := findConfigDir()
+	dir, cfg, err := loadBase()
 	if err != nil {
 		return nil, err
 	}
 
-	cfg, err := loadYAML(dir)
-	if err != nil {
+	if err := loadEnv(dir, cfg); err != nil {
 		return nil, err
 	}
 
-	if err := loadEnv(dir, cfg); err != nil {
+	if err := applyEnvOverrides(cfg); err != nil {
 		return nil, err
 	}
+	applyRoles(cfg)
 
 	if err := validateConfig(cfg); err != nil {
-		return nil, err
+		var configErr *ConfigError
+		if errors.As(err, &configErr) && configErr.Line == 0 {
+			configErr.Line = cfg.lines[configErr.Field]
+		}
+		// A typo often leaves a required setting empty, so show both.
+		return nil, errors.Join(err, cfg.unknown)
+	}
+
+	for _, p := range []*ProviderConfig{
+		&cfg.Providers.OpenAI,
+		&cfg.Providers.Anthropic,
+		&cfg.Providers.OpenRouter,
+		&cfg.Providers.OpenCode,
+		&cfg.Providers.Ollama,
+		&cfg.Providers.Local,
+	} {
+		applyProviderDefaults(p, cfg.Providers.Proxy)
+	}
+	for i := range cfg.Providers.OpenAICompatible {
+		applyProviderDefaults(&cfg.Providers.OpenAICompatible[i].ProviderConfig, cfg.Providers.Proxy)
 	}
 
+	if cfg.Telegram.Reactions.Processing == "" {
+		cfg.Telegram.Reactions.Processing = "🤔"
+	}
+	if cfg.Telegram.Reactions.Done == "" {
+		cfg.Telegram.Reactions.Done = "👍"
+	}
+	if cfg.Telegram.Reactions.Error == "" {
+		cfg.Telegram.Reactions.Error = "😢"
+	}
+	if cfg.Telegram.Inline.QueriesPerMinute == 0 {
+		cfg.Telegram.Inline.QueriesPerMinute = 10
+	}
+	if cfg.Memory.Backend == "" {
+		cfg.Memory.Backend = "file"
+	}
 	if cfg.Memory.Path == "" {
 		cfg.Memory.Path = "./data/sessions"
 	}
 	if cfg.Memory.MaxMessages == 0 {
 		cfg.Memory.MaxMessages = 50
 	}
+	if cfg.Memory.Strategy == "" {
+		cfg.Memory.Strategy = "truncate"
+	}
+	if cfg.Memory.Cache.Addr == "" {
+		cfg.Memory.Cache.Addr = "localhost:6379"
+	}
+	if cfg.Memory.Cache.TTLSeconds == 0 {
+		cfg.Memory.Cache.TTLSeconds = 3600
+	}
+	if cfg.Memory.RAG.Path == "" {
+		cfg.Memory.RAG.Path = "./data/memory"
+	}
+	if cfg.Memory.RAG.TopK == 0 {
+		cfg.Memory.RAG.TopK = 3
+	}
+	if cfg.OfflineQueue.Path == "" {
+		cfg.OfflineQueue.Path = "./data/queue.json"
+	}
+	if cfg.OfflineQueue.CheckIntervalSeconds == 0 {
+		cfg.OfflineQueue.CheckIntervalSeconds = 60
+	}
+	if cfg.Offline.Provider == "" {
+		cfg.Offline.Provider = "ollama"
+	}
+	if cfg.Offline.ProbeSeconds == 0 {
+		cfg.Offline.ProbeSeconds = 60
+	}
+	if cfg.Access.ApprovedPath == "" {
+		cfg.Access.ApprovedPath = "./data/approved_users.json"
+	}
+	if cfg.Access.UsernamesPath == "" {
+		cfg.Access.UsernamesPath = "./data/usernames.json"
+	}
+	if cfg.Groups.DefaultMode == "" {
+		cfg.Groups.DefaultMode = "per_user"
+	}
+	if cfg.Groups.Path == "" {
+		cfg.Groups.Path = "./data/groups.json"
+	}
+	if cfg.Feedback.Path == "" {
+		cfg.Feedback.Path = "./data/feedback.json"
+	}
+	if cfg.Documents.Path == "" {
+		cfg.Documents.Path = "./data/documents"
+	}
+	if cfg.Documents.MaxExcerpts == 0 {
+		cfg.Documents.MaxExcerpts = 4
+	}
+	if cfg.Budget.Path == "" {
+		cfg.Budget.Path = "./data/budget.json"
+	}
+	if len(cfg.Redaction.Types) == 0 {
+		cfg.Redaction.Types = []string{"email", "phone", "credit_card"}
+	}
+	if cfg.Prefixes.Aliases == nil {
+		cfg.Prefixes.Aliases = map[string]CommandRouteConfig{
+			"claude": {Provider: "anthropic"},
+			"gpt":    {Provider: "openai"},
+		}
+	}
+	if cfg.Redaction.ExemptProviders == nil {
+		cfg.Redaction.ExemptProviders = []string{"ollama", "local"}
+	}
+	if cfg.Health.Addr == "" {
+		cfg.Health.Addr = ":8080"
+	}
+	if cfg.API.Addr == "" {
+		cfg.API.Addr = "127.0.0.1:8081"
+	}
+	if cfg.MQTT.ClientID == "" {
+		cfg.MQTT.ClientID = "helpi"
+	}
+	if cfg.MQTT.CommandTopic == "" {
+		cfg.MQTT.CommandTopic = "helpi/commands"
+	}
+	if cfg.Digest.At == "" {
+		cfg.Digest.At = "08:00"
+	}
+	if cfg.Digest.Weekday == "" {
+		cfg.Digest.Weekday = "monday"
+	}
+	if cfg.Memory.Facts.Path == "" {
+		cfg.Memory.Facts.Path = "./data/facts"
+	}
+	if cfg.Feeds.Path == "" {
+		cfg.Feeds.Path = "./data/feeds.json"
+	}
+	if cfg.Feeds.IntervalMinutes == 0 {
+		cfg.Feeds.IntervalMinutes = 30
+	}
+	if cfg.Digest.Path == "" {
+		cfg.Digest.Path = "./data/digest.json"
+	}
+	if cfg.QuietHours.Path == "" {
+		cfg.QuietHours.Path = "./data/held.json"
+	}
+	if cfg.Updates.At == "" {
+		cfg.Updates.At = "09:00"
+	}
+	if cfg.Updates.Path == "" {
+		cfg.Updates.Path = "./data/updates.json"
+	}
+	if cfg.Batch.Path == "" {
+		cfg.Batch.Path = "./data/batches.json"
+	}
+	if cfg.Batch.PollIntervalSeconds == 0 {
+		cfg.Batch.PollIntervalSeconds = 300
+	}
+	if cfg.Roles.Guest.MessagesPerMinute == 0 {
+		cfg.Roles.Guest.MessagesPerMinute = 5
+	}
 
 	return cfg, nil
 }
 
+// applyRoles folds roles.users and roles.admins into allowed_users and
+// admins, which is what the bots read. A roles section on its own is enough
+// to configure access.
+func applyRoles(cfg *Config) {
+	r := cfg.Roles
+	if cfg.AllowedUsers == nil && (r.Users != nil || len(r.Owners)+len(r.Admins)+len(r.Guests) > 0) {
+		cfg.AllowedUsers = []int64{}
+	}
+	cfg.AllowedUsers = mergeIDs(cfg.AllowedUsers, r.Users)
+	cfg.Admins = mergeIDs(cfg.Admins, r.Admins)
+}
+
+func mergeIDs(ids, more []int64) []int64 {
+	for _, id := range more {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func loadBase() (string, *Config, error) {
+	dir, err := findConfigDir()
+	if err == nil {
+		cfg, err := loadYAML(dir)
+		return dir, cfg, err
+	}
+	if !envOnlyMode() {
+		return "", nil, err
+	}
+
+	cwd, cwdErr := os.Getwd()
+	if cwdErr != nil {
+		return "", nil, &ConfigError{Message: "failed to get current working directory", Path: ""}
+	}
+	return cwd, &Config{APIKeys: make(map[string]string)}, nil
+}
+
 func findConfigDir() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -95,11 +308,20 @@ func loadYAML(dir string) (*Config, error) {
 		return nil, &ConfigError{Message: "config.yaml is empty", Path: path}
 	}
 
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, &ConfigError{Message: fmt.Sprintf("failed to parse YAML: %v", err), Path: path}
+	}
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := doc.Decode(&cfg); err != nil {
 		return nil, &ConfigError{Message: fmt.Sprintf("failed to parse YAML: %v", err), Path: path}
 	}
 
+	cfg.lines, cfg.unknown = checkSchema(&doc)
+	if cfg.Strict && cfg.unknown != nil {
+		return nil, cfg.unknown
+	}
+
 	cfg.APIKeys = make(map[string]string)
 
 	return &cfg, nil
@@ -108,36 +330,162 @@ func loadYAML(dir string) (*Config, error) {
 func loadEnv(dir string, cfg *Config) error {
 	envPath := filepath.Join(dir, ".env")
 
-	if _, err := os.Stat(envPath); os.IsNotExist(err) {
-		if os.Getenv("TELEGRAM_BOT_TOKEN") != "" {
-			cfg.Telegram.Token = os.Getenv("TELEGRAM_BOT_TOKEN")
+	if _, err := os.Stat(envPath); err == nil {
+		values, err := godotenv.Read(envPath)
+		if err != nil {
+			return &ConfigError{Message: fmt.Sprintf("failed to parse .env file: %v", err), Path: envPath}
+		}
+		for key, value := range values {
+			if _, set := os.LookupEnv(key); !set || !processEnv[key] {
+				os.Setenv(key, value)
+			}
 		}
-		return nil
 	}
 
-	if err := godotenv.Load(envPath); err != nil {
-		return &ConfigError{Message: fmt.Sprintf("failed to parse .env file: %v", err), Path: envPath}
+	secret, err := secretLookup(dir, cfg.Secrets)
+	if err != nil {
+		return err
 	}
 
-	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+	token, err := secret("TELEGRAM_BOT_TOKEN")
+	if err != nil {
+		return err
+	}
+	if token != "" {
 		cfg.Telegram.Token = token
 	}
+	for i, b := range cfg.Bots {
+		if b.TokenEnv != "" {
+			if cfg.Bots[i].Token, err = secret(b.TokenEnv); err != nil {
+				return err
+			}
+		}
+	}
 
-	cfg.APIKeys["OPENAI_API_KEY"] = os.Getenv("OPENAI_API_KEY")
-	cfg.APIKeys["ANTHROPIC_API_KEY"] = os.Getenv("ANTHROPIC_API_KEY")
-	cfg.APIKeys["OPENROUTER_API_KEY"] = os.Getenv("OPENROUTER_API_KEY")
-	cfg.APIKeys["OPENCODE_API_KEY"] = os.Getenv("OPENCODE_API_KEY")
-	cfg.APIKeys["OLLAMA_BASE_URL"] = os.Getenv("OLLAMA_BASE_URL")
+	keys := []string{
+		"OPENAI_API_KEY",
+		"ANTHROPIC_API_KEY",
+		"OPENROUTER_API_KEY",
+		"OPENCODE_API_KEY",
+		"OLLAMA_BASE_URL",
+		"LOCAL_BASE_URL",
+		"DATABASE_URL",
+		"REDIS_PASSWORD",
+		"HELPI_API_TOKEN",
+		"MQTT_PASSWORD",
+	}
+	for _, c := range cfg.Providers.OpenAICompatible {
+		if c.APIKeyEnv != "" {
+			keys = append(keys, c.APIKeyEnv)
+		}
+	}
+	for _, w := range cfg.Webhooks {
+		if w.SecretEnv != "" {
+			keys = append(keys, w.SecretEnv)
+		}
+	}
+	for _, key := range keys {
+		if cfg.APIKeys[key], err = secret(key); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// secretLookup returns a function that reads a secret from the environment,
+// from the file named by its _FILE variant, from the configured secrets
+// backend or from the OS keyring, in that order.
+func secretLookup(dir string, sc SecretsConfig) (func(string) (string, error), error) {
+	backend, err := newSecretsBackend(dir, sc)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored map[string]string
+	if backend != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if stored, err = backend.Secrets(ctx); err != nil {
+			return nil, &ConfigError{Field: "secrets", Message: err.Error()}
+		}
+	}
+
+	return func(key string) (string, error) {
+		value, err := secrets.FromFile(key)
+		if err != nil {
+			return "", &ConfigError{Field: key, Message: err.Error()}
+		}
+		if value == "" {
+			value = stored[key]
+		}
+		if value == "" {
+			value = secrets.FromKeyring(key)
+		}
+		return value, nil
+	}, nil
+}
+
+func newSecretsBackend(dir string, sc SecretsConfig) (secrets.Backend, error) {
+	if sc.Backend == "" {
+		return nil, nil
+	}
+	if sc.Path == "" {
+		return nil, &ConfigError{Field: "secrets.path", Message: "is required when secrets.backend is set"}
+	}
+
+	switch sc.Backend {
+	case "vault":
+		address := sc.Address
+		if address == "" {
+			address = os.Getenv("VAULT_ADDR")
+		}
+		if address == "" {
+			return nil, &ConfigError{Field: "secrets.address", Message: "is required when VAULT_ADDR is not set"}
+		}
+		token, err := secrets.FromFile("VAULT_TOKEN")
+		if err != nil {
+			return nil, &ConfigError{Field: "VAULT_TOKEN", Message: err.Error()}
+		}
+		if token == "" {
+			return nil, &ConfigError{Field: "VAULT_TOKEN", Message: "is required for the vault secrets backend"}
+		}
+		return secrets.NewVault(address, sc.Path, token), nil
+	case "sops":
+		path := sc.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return secrets.NewSOPS(path), nil
+	}
+	return nil, &ConfigError{Field: "secrets.backend", Message: "must be vault or sops"}
+}
+
 func validateConfig(cfg *Config) error {
-	if cfg.Telegram.Token = strings.TrimSpace(cfg.Telegram.Token); cfg.Telegram.Token == "" {
+	if cfg.Telegram.Token = strings.TrimSpace(cfg.Telegram.Token); cfg.Telegram.Token == "" && len(cfg.Bots) == 0 {
 		return &ConfigError{Field: "telegram.token", Message: "is required and cannot be empty"}
 	}
 
-	if cfg.AllowedUsers == nil {
+	if lang := cfg.Telegram.DefaultLanguage; lang != "" && i18n.Normalize(lang) == "" {
+		return &ConfigError{Field: "telegram.default_language", Message: "must be one of " + strings.Join(i18n.Supported(), ", ")}
+	}
+
+	if cfg.Telegram.DebounceSeconds < 0 {
+		return &ConfigError{Field: "telegram.debounce_seconds", Message: "must be >= 0"}
+	}
+
+	if err := validateReactions(cfg.Telegram.Reactions); err != nil {
+		return err
+	}
+
+	if cfg.Telegram.Inline.QueriesPerMinute < 0 {
+		return &ConfigError{Field: "telegram.inline.queries_per_minute", Message: "must be >= 0"}
+	}
+	if cfg.Telegram.Inline.Burst < 0 {
+		return &ConfigError{Field: "telegram.inline.burst", Message: "must be >= 0"}
+	}
+
+	if cfg.AllowedUsers == nil && len(cfg.AllowedUsernames) == 0 {
 		return &ConfigError{Field: "allowed_users", Message: "is required and cannot be nil"}
 	}
 
@@ -147,6 +495,24 @@ func validateConfig(cfg *Config) error {
 		}
 	}
 
+	if err := validateUsernames("allowed_usernames", cfg.AllowedUsernames); err != nil {
+		return err
+	}
+
+	for _, admin := range cfg.Admins {
+		if admin <= 0 {
+			return &ConfigError{Field: "admins", Message: "each admin ID must be a positive integer"}
+		}
+	}
+
+	if err := validateRoles(cfg.Roles); err != nil {
+		return err
+	}
+
+	if cfg.Access.ReportUnauthorized && len(cfg.Admins)+len(cfg.Roles.Owners) == 0 {
+		return &ConfigError{Field: "access.report_unauthorized", Message: "requires at least one admin"}
+	}
+
 	if cfg.Providers.OpenAI.Enabled && cfg.Providers.OpenAI.DefaultModel == "" {
 		return &ConfigError{Field: "providers.openai.default_model", Message: "is required when provider is enabled"}
 	}
@@ -160,39 +526,1076 @@ func validateConfig(cfg *Config) error {
 		return &ConfigError{Field: "providers.opencode.default_model", Message: "is required when provider is enabled"}
 	}
 
+	if err := validateProviderAPI("openai", cfg.Providers.OpenAI, true); err != nil {
+		return err
+	}
+	if err := validateProviderAPI("anthropic", cfg.Providers.Anthropic, false); err != nil {
+		return err
+	}
+	if err := validateProviderAPI("openrouter", cfg.Providers.OpenRouter, true); err != nil {
+		return err
+	}
+	if err := validateProviderAPI("opencode", cfg.Providers.OpenCode, true); err != nil {
+		return err
+	}
+	if err := validateProviderAPI("ollama", cfg.Providers.Ollama, false); err != nil {
+		return err
+	}
+	if err := validateProviderAPI("local", cfg.Providers.Local, false); err != nil {
+		return err
+	}
+
+	if err := validateProxy("providers.proxy", cfg.Providers.Proxy); err != nil {
+		return err
+	}
+	providerCfgs := providerSettings(cfg)
+	for _, name := range sortedKeys(providerCfgs) {
+		if err := validateProviderRetry(name, providerCfgs[name]); err != nil {
+			return err
+		}
+		if err := validateProviderHTTP(name, providerCfgs[name]); err != nil {
+			return err
+		}
+		if err := validateProviderGeneration(name, providerCfgs[name]); err != nil {
+			return err
+		}
+	}
+
 	if cfg.Memory.MaxMessages < 1 {
 		return &ConfigError{Field: "memory.max_messages", Message: "must be >= 1"}
 	}
+	if cfg.Memory.MaxTokens < 0 {
+		return &ConfigError{Field: "memory.max_tokens", Message: "must be >= 0"}
+	}
 
-	if err := validateAPIKeys(cfg); err != nil {
+	if err := validateMemoryBackend(cfg); err != nil {
 		return err
 	}
 
-	return nil
-}
+	if cfg.Memory.TTLDays < 0 {
+		return &ConfigError{Field: "memory.ttl_days", Message: "must be >= 0"}
+	}
 
-func validateAPIKeys(cfg *Config) error {
-	if cfg.Providers.OpenAI.Enabled {
-		if cfg.APIKeys["OPENAI_API_KEY"] == "" {
-			return &ConfigError{Field: "OPENAI_API_KEY", Message: "is required when openai provider is enabled"}
+	if cfg.Memory.Cache.DB < 0 {
+		return &ConfigError{Field: "memory.cache.db", Message: "must be >= 0"}
+	}
+	if cfg.Memory.Cache.TTLSeconds < 0 {
+		return &ConfigError{Field: "memory.cache.ttl_seconds", Message: "must be >= 0"}
+	}
+
+	if err := validatePruning(cfg.Memory); err != nil {
+		return err
+	}
+
+	if err := validateDedup(cfg.Memory.Dedup); err != nil {
+		return err
+	}
+
+	if err := validateRAG(cfg.Memory.RAG); err != nil {
+		return err
+	}
+
+	if s := cfg.Memory.Strategy; s != "" && s != "truncate" && s != "summarize" {
+		return &ConfigError{Field: "memory.strategy", Message: `must be "truncate" or "summarize"`}
+	}
+
+	if m := cfg.Groups.DefaultMode; m != "" && m != "per_user" && m != "shared" {
+		return &ConfigError{Field: "groups.default_mode", Message: `must be "per_user" or "shared"`}
+	}
+	if q := cfg.Telegram.QuoteReplies; q != "" && q != "groups" && q != "always" {
+		return &ConfigError{Field: "telegram.quote_replies", Message: `must be "groups" or "always"`}
+	}
+	if c := cfg.Groups.ChatContext; c != "" && c != "description" && c != "pinned" {
+		return &ConfigError{Field: "groups.chat_context", Message: `must be "description" or "pinned"`}
+	}
+
+	if cfg.OfflineQueue.CheckIntervalSeconds < 0 {
+		return &ConfigError{Field: "offline_queue.check_interval_seconds", Message: "must be >= 0"}
+	}
+	if err := validateOffline(cfg.Offline, cfg.Providers); err != nil {
+		return err
+	}
+
+	if cfg.RateLimit.MessagesPerMinute < 0 {
+		return &ConfigError{Field: "rate_limit.messages_per_minute", Message: "must be >= 0"}
+	}
+	if cfg.RateLimit.Burst < 0 {
+		return &ConfigError{Field: "rate_limit.burst", Message: "must be >= 0"}
+	}
+
+	if cfg.Documents.MaxExcerpts < 0 {
+		return &ConfigError{Field: "documents.max_excerpts", Message: "must be >= 0"}
+	}
+
+	if cfg.Budget.MaxInputTokens < 0 {
+		return &ConfigError{Field: "budget.max_input_tokens", Message: "must be >= 0"}
+	}
+	if cfg.Budget.DailyUserTokens < 0 {
+		return &ConfigError{Field: "budget.daily_user_tokens", Message: "must be >= 0"}
+	}
+	if cfg.Budget.DailyGlobalTokens < 0 {
+		return &ConfigError{Field: "budget.daily_global_tokens", Message: "must be >= 0"}
+	}
+	if cfg.Budget.DailyUserSpend < 0 {
+		return &ConfigError{Field: "budget.daily_user_spend", Message: "must be >= 0"}
+	}
+	if cfg.Budget.DailyGlobalSpend < 0 {
+		return &ConfigError{Field: "budget.daily_global_spend", Message: "must be >= 0"}
+	}
+	if (cfg.Budget.DailyUserSpend > 0 || cfg.Budget.DailyGlobalSpend > 0) && len(cfg.Budget.Prices) == 0 {
+		return &ConfigError{Field: "budget.prices", Message: "required when a spend limit is set"}
+	}
+	for key, price := range cfg.Budget.Prices {
+		if price.Input < 0 || price.Output < 0 {
+			return &ConfigError{Field: "budget.prices." + key, Message: "must be >= 0"}
 		}
 	}
 
-	if cfg.Providers.Anthropic.Enabled {
-		if cfg.APIKeys["ANTHROPIC_API_KEY"] == "" {
-			return &ConfigError{Field: "ANTHROPIC_API_KEY", Message: "is required when anthropic provider is enabled"}
+	if cfg.Batch.PollIntervalSeconds < 0 {
+		return &ConfigError{Field: "batch.poll_interval_seconds", Message: "must be >= 0"}
+	}
+
+	if err := validateScheduledPrompts(cfg.ScheduledPrompts); err != nil {
+		return err
+	}
+
+	if err := validateCustomProviders(cfg.Providers.OpenAICompatible); err != nil {
+		return err
+	}
+
+	providers := providerNames(cfg)
+	if err := validateRoutingRules(cfg.Routing.Rules, providers); err != nil {
+		return err
+	}
+
+	if err := validateRedaction(cfg.Redaction, providers); err != nil {
+		return err
+	}
+
+	if err := validateBots(cfg.Bots, cfg.Telegram.Token, providers); err != nil {
+		return err
+	}
+
+	if err := validateDigest(cfg.Digest, providers); err != nil {
+		return err
+	}
+	if cfg.Storage.UserMB < 0 {
+		return &ConfigError{Field: "storage.user_mb", Message: "must be >= 0"}
+	}
+	if cfg.Storage.TotalMB < 0 {
+		return &ConfigError{Field: "storage.total_mb", Message: "must be >= 0"}
+	}
+	if cfg.Feedback.Buttons && !cfg.Feedback.Enabled {
+		return &ConfigError{Field: "feedback.buttons", Message: "requires feedback.enabled"}
+	}
+	if cfg.Continuation.Max < 0 || cfg.Continuation.Max > 10 {
+		return &ConfigError{Field: "continuation.max", Message: "must be between 0 and 10"}
+	}
+	if h := cfg.QuietHours.Hours; h != "" {
+		if _, err := quiet.Parse(h); err != nil {
+			return &ConfigError{Field: "quiet_hours.hours", Message: "must be a window such as 22:00-07:00"}
 		}
 	}
 
-	if cfg.Providers.OpenRouter.Enabled {
-		if cfg.APIKeys["OPENROUTER_API_KEY"] == "" {
-			return &ConfigError{Field: "OPENROUTER_API_KEY", Message: "is required when openrouter provider is enabled"}
+	if cfg.Updates.At != "" {
+		if _, err := time.Parse("15:04", cfg.Updates.At); err != nil {
+			return &ConfigError{Field: "updates.at", Message: "must be a time in HH:MM format"}
 		}
 	}
 
-	if cfg.Providers.OpenCode.Enabled {
-		if cfg.APIKeys["OPENCODE_API_KEY"] == "" {
-			return &ConfigError{Field: "OPENCODE_API_KEY", Message: "is required when opencode provider is enabled"}
+	if err := validateFeeds(cfg.Feeds, providers); err != nil {
+		return err
+	}
+
+	if err := validateProviderAccess(cfg.ProviderAccess, providers); err != nil {
+		return err
+	}
+
+	if cfg.API.Enabled && cfg.Health.Enabled && cfg.API.Addr != "" && cfg.API.Addr == cfg.Health.Addr {
+		return &ConfigError{Field: "api.addr", Message: "must differ from health.addr"}
+	}
+
+	if err := validateWebhooks(cfg.Webhooks); err != nil {
+		return err
+	}
+
+	if err := validateMQTT(cfg.MQTT, providers); err != nil {
+		return err
+	}
+	if err := validateBotRefs(cfg); err != nil {
+		return err
+	}
+	if err := validateGrant("roles.guest", cfg.Roles.Guest.ProviderGrantConfig, providers); err != nil {
+		return err
+	}
+
+	if err := validatePersonas(cfg.Personas, providers); err != nil {
+		return err
+	}
+
+	if err := validateCommands(cfg.Commands, providers); err != nil {
+		return err
+	}
+
+	if cfg.Translate.Provider != "" && !providers[cfg.Translate.Provider] {
+		return &ConfigError{Field: "translate.provider", Message: fmt.Sprintf("unknown provider %q", cfg.Translate.Provider)}
+	}
+	if cfg.Translate.Provider == "" && cfg.Translate.Model != "" {
+		return &ConfigError{Field: "translate.model", Message: "requires a provider"}
+	}
+	if err := validatePostprocess(cfg.Postprocess, providers); err != nil {
+		return err
+	}
+	if err := validatePrefixes(cfg.Prefixes, providers); err != nil {
+		return err
+	}
+	if cfg.Titles.Provider != "" && !providers[cfg.Titles.Provider] {
+		return &ConfigError{Field: "titles.provider", Message: fmt.Sprintf("unknown provider %q", cfg.Titles.Provider)}
+	}
+	if cfg.Titles.Provider == "" && cfg.Titles.Model != "" {
+		return &ConfigError{Field: "titles.model", Message: "requires a provider"}
+	}
+
+	if err := validateAPIKeys(cfg); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateProviderAPI(name string, p ProviderConfig, supportsResponses bool) error {
+	switch p.API {
+	case "", "chat":
+		if len(p.Tools) > 0 {
+			return &ConfigError{Field: "providers." + name + ".tools", Message: "requires api: responses"}
+		}
+	case "responses":
+		if !supportsResponses {
+			return &ConfigError{Field: "providers." + name + ".api", Message: "responses API is not supported by this provider"}
+		}
+	default:
+		return &ConfigError{Field: "providers." + name + ".api", Message: fmt.Sprintf("unknown api %q (expected chat or responses)", p.API)}
+	}
+
+	for _, tool := range p.Tools {
+		switch tool {
+		case "web_search":
+		case "file_search":
+			if len(p.VectorStoreIDs) == 0 {
+				return &ConfigError{Field: "providers." + name + ".vector_store_ids", Message: "is required when file_search tool is enabled"}
+			}
+		default:
+			return &ConfigError{Field: "providers." + name + ".tools", Message: fmt.Sprintf("unknown tool %q", tool)}
+		}
+	}
+
+	return nil
+}
+
+func validateProviderRetry(name string, p ProviderConfig) error {
+	if p.TimeoutSeconds < 0 {
+		return &ConfigError{Field: "providers." + name + ".timeout_seconds", Message: "must be >= 0"}
+	}
+	if p.MaxRetries != nil && *p.MaxRetries < 0 {
+		return &ConfigError{Field: "providers." + name + ".max_retries", Message: "must be >= 0"}
+	}
+	if p.RetryBackoff != "" {
+		if d, err := time.ParseDuration(p.RetryBackoff); err != nil || d <= 0 {
+			return &ConfigError{Field: "providers." + name + ".retry_backoff", Message: fmt.Sprintf("invalid duration %q (e.g. 500ms, 2s)", p.RetryBackoff)}
+		}
+	}
+	return nil
+}
+
+func validateProviderHTTP(name string, p ProviderConfig) error {
+	if p.Proxy != "none" {
+		if err := validateProxy("providers."+name+".proxy", p.Proxy); err != nil {
+			return err
+		}
+	}
+	for header := range p.Headers {
+		if header == "" || strings.ContainsAny(header, " \t:\r\n") {
+			return &ConfigError{Field: "providers." + name + ".headers", Message: fmt.Sprintf("invalid header name %q", header)}
+		}
+	}
+	return nil
+}
+
+func validateProxy(field, raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return &ConfigError{Field: field, Message: fmt.Sprintf("invalid proxy URL %q", raw)}
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return &ConfigError{Field: field, Message: "must be an http, https or socks5 URL"}
+	}
+	return nil
+}
+
+// maxTemperature is the highest temperature provider accepts. Anthropic
+// allows at most 1, the others 2.
+func maxTemperature(provider string) float64 {
+	if provider == "anthropic" {
+		return 1
+	}
+	return 2
+}
+
+func validateProviderGeneration(name string, p ProviderConfig) error {
+	if t, max := p.Temperature, maxTemperature(name); t != nil && (*t < 0 || *t > max) {
+		return &ConfigError{Field: "providers." + name + ".temperature", Message: fmt.Sprintf("must be between 0 and %g", max)}
+	}
+	if t := p.TopP; t != nil && (*t < 0 || *t > 1) {
+		return &ConfigError{Field: "providers." + name + ".top_p", Message: "must be between 0 and 1"}
+	}
+	if f := p.FrequencyPenalty; f != nil && (*f < -2 || *f > 2) {
+		return &ConfigError{Field: "providers." + name + ".frequency_penalty", Message: "must be between -2 and 2"}
+	}
+	if p.MaxTokens < 0 {
+		return &ConfigError{Field: "providers." + name + ".max_tokens", Message: "must be >= 0"}
+	}
+	switch p.ReasoningEffort {
+	case "", "none", "minimal", "low", "medium", "high", "xhigh":
+	default:
+		return &ConfigError{Field: "providers." + name + ".reasoning_effort", Message: "must be one of none, minimal, low, medium, high or xhigh"}
+	}
+	if p.ThinkingBudget != 0 && name != "anthropic" {
+		return &ConfigError{Field: "providers." + name + ".thinking_budget", Message: "is only supported by anthropic"}
+	}
+	if p.ThinkingBudget != 0 && p.ThinkingBudget < 1024 {
+		return &ConfigError{Field: "providers." + name + ".thinking_budget", Message: "must be at least 1024"}
+	}
+	if p.ThinkingBudget > 0 && p.MaxTokens > 0 && p.MaxTokens <= p.ThinkingBudget {
+		return &ConfigError{Field: "providers." + name + ".max_tokens", Message: "must be greater than thinking_budget"}
+	}
+	if p.ShowReasoning && p.API != "responses" && p.ThinkingBudget == 0 {
+		return &ConfigError{Field: "providers." + name + ".show_reasoning", Message: `requires api: "responses", or thinking_budget for anthropic`}
+	}
+	switch p.Think {
+	case "", "keep", "strip", "spoiler":
+	default:
+		return &ConfigError{Field: "providers." + name + ".think", Message: "must be keep, strip or spoiler"}
+	}
+	if p.KeepAlive != "" {
+		if _, err := strconv.Atoi(p.KeepAlive); err != nil {
+			if _, err := time.ParseDuration(p.KeepAlive); err != nil {
+				return &ConfigError{Field: "providers." + name + ".keep_alive", Message: `must be a duration such as "10m" or a number of seconds`}
+			}
+		}
+	}
+	if p.NumCtx < 0 {
+		return &ConfigError{Field: "providers." + name + ".num_ctx", Message: "must be >= 0"}
+	}
+	return nil
+}
+
+func applyProviderDefaults(p *ProviderConfig, proxy string) {
+	if p.Proxy == "" {
+		p.Proxy = proxy
+	}
+	if p.TimeoutSeconds == 0 {
+		p.TimeoutSeconds = 120
+	}
+	if p.MaxRetries == nil {
+		retries := 2
+		p.MaxRetries = &retries
+	}
+	if p.RetryBackoff == "" {
+		p.RetryBackoff = "1s"
+	}
+}
+
+func validateScheduledPrompts(prompts []ScheduledPromptConfig) error {
+	seen := make(map[string]bool)
+	for i, sp := range prompts {
+		field := fmt.Sprintf("scheduled_prompts[%d]", i)
+		if sp.Name == "" {
+			return &ConfigError{Field: field + ".name", Message: "is required"}
+		}
+		if seen[sp.Name] {
+			return &ConfigError{Field: field + ".name", Message: fmt.Sprintf("duplicate name %q", sp.Name)}
+		}
+		seen[sp.Name] = true
+		if sp.ChatID == 0 {
+			return &ConfigError{Field: field + ".chat_id", Message: "is required"}
+		}
+		if strings.TrimSpace(sp.Prompt) == "" {
+			return &ConfigError{Field: field + ".prompt", Message: "is required"}
+		}
+		if _, err := time.Parse("15:04", sp.At); err != nil {
+			return &ConfigError{Field: field + ".at", Message: "must be a time in HH:MM format"}
+		}
+		switch sp.Priority {
+		case "", "normal":
+		case "batch":
+			if sp.Provider != "" && sp.Provider != "openai" && sp.Provider != "anthropic" {
+				return &ConfigError{Field: field + ".provider", Message: "batch priority requires the openai or anthropic provider"}
+			}
+		default:
+			return &ConfigError{Field: field + ".priority", Message: fmt.Sprintf("unknown priority %q (expected normal or batch)", sp.Priority)}
+		}
+	}
+
+	return nil
+}
+
+var builtinCommands = map[string]bool{
+	"start":      true,
+	"help":       true,
+	"myid":       true,
+	"whoami":     true,
+	"status":     true,
+	"feedback":   true,
+	"feedbacks":  true,
+	"admin":      true,
+	"new":        true,
+	"threads":    true,
+	"resume":     true,
+	"import":     true,
+	"lang":       true,
+	"regenerate": true,
+	"docs":       true,
+	"remember":   true,
+	"memories":   true,
+	"forget":     true,
+	"watch":      true,
+	"unwatch":    true,
+	"translate":  true,
+	"groupmode":  true,
+	"switch":     true,
+	"prompt":     true,
+	"model":      true,
+	"models":     true,
+	"clear":      true,
+	"pin":        true,
+	"pins":       true,
+	"unpin":      true,
+	"stats":      true,
+	"cancel":     true,
+	"persona":    true,
+	"digest":     true,
+	"quiet":      true,
+	"settings":   true,
+	"ha":         true,
+}
+
+var knownProviders = map[string]bool{
+	"openai":     true,
+	"anthropic":  true,
+	"openrouter": true,
+	"opencode":   true,
+	"ollama":     true,
+	"local":      true,
+}
+
+var embeddingProviders = map[string]bool{
+	"openai":     true,
+	"openrouter": true,
+	"ollama":     true,
+}
+
+func validateMemoryBackend(cfg *Config) error {
+	switch cfg.Memory.Backend {
+	case "", "file":
+		return nil
+	case "postgres":
+		if cfg.APIKeys["DATABASE_URL"] == "" {
+			return &ConfigError{Field: "DATABASE_URL", Message: "is required when memory.backend is postgres"}
+		}
+		return nil
+	default:
+		return &ConfigError{Field: "memory.backend", Message: `must be "file" or "postgres"`}
+	}
+}
+
+func validatePruning(m MemoryConfig) error {
+	switch m.Pruning {
+	case "", "recent":
+		return nil
+	case "relevance":
+	default:
+		return &ConfigError{Field: "memory.pruning", Message: `must be "recent" or "relevance"`}
+	}
+
+	if !embeddingProviders[m.Relevance.Provider] {
+		return &ConfigError{Field: "memory.relevance.provider", Message: "must be an embedding provider (openai, openrouter or ollama)"}
+	}
+	if m.Relevance.TopK < 0 {
+		return &ConfigError{Field: "memory.relevance.top_k", Message: "must be >= 0"}
+	}
+	if m.Relevance.Recent < 0 {
+		return &ConfigError{Field: "memory.relevance.recent", Message: "must be >= 0"}
+	}
+
+	return nil
+}
+
+func validateDedup(d DedupConfig) error {
+	if !d.Enabled {
+		return nil
+	}
+	if !embeddingProviders[d.Provider] {
+		return &ConfigError{Field: "memory.dedup.provider", Message: "must be an embedding provider (openai, openrouter or ollama)"}
+	}
+	if d.Threshold < 0 || d.Threshold > 1 {
+		return &ConfigError{Field: "memory.dedup.threshold", Message: "must be between 0 and 1"}
+	}
+	return nil
+}
+
+func validateRAG(r RAGConfig) error {
+	if !r.Enabled {
+		return nil
+	}
+	if !embeddingProviders[r.Provider] {
+		return &ConfigError{Field: "memory.rag.provider", Message: "must be an embedding provider (openai, openrouter or ollama)"}
+	}
+	if r.TopK < 0 {
+		return &ConfigError{Field: "memory.rag.top_k", Message: "must be >= 0"}
+	}
+	if r.MinScore < 0 || r.MinScore > 1 {
+		return &ConfigError{Field: "memory.rag.min_score", Message: "must be between 0 and 1"}
+	}
+	return nil
+}
+
+// ShowsReasoning reports whether any provider returns reasoning summaries.
+func (c *Config) ShowsReasoning() bool {
+	for _, p := range providerSettings(c) {
+		if p.Enabled && p.ShowReasoning {
+			return true
+		}
+	}
+	return false
+}
+
+// SpoilersThink reports whether any provider sends its <think> reasoning as
+// a spoiler.
+func (c *Config) SpoilersThink() bool {
+	for _, p := range providerSettings(c) {
+		if p.Enabled && p.Think == "spoiler" {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesThinking reports whether any provider has extended thinking on.
+func (c *Config) UsesThinking() bool {
+	for _, p := range providerSettings(c) {
+		if p.Enabled && p.ThinkingBudget > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func providerNames(cfg *Config) map[string]bool {
+	names := make(map[string]bool, len(knownProviders)+len(cfg.Providers.OpenAICompatible))
+	for name := range knownProviders {
+		names[name] = true
+	}
+	for _, c := range cfg.Providers.OpenAICompatible {
+		names[c.Name] = true
+	}
+	return names
+}
+
+func validateCustomProviders(providers []CustomProviderConfig) error {
+	seen := make(map[string]bool)
+	for i, c := range providers {
+		field := fmt.Sprintf("providers.openai_compatible[%d]", i)
+		if !isValidCommandName(c.Name) {
+			return &ConfigError{Field: field + ".name", Message: "must be 1-32 lowercase letters, digits or underscores"}
+		}
+		if knownProviders[c.Name] || seen[c.Name] {
+			return &ConfigError{Field: field + ".name", Message: fmt.Sprintf("duplicate provider name %q", c.Name)}
+		}
+		seen[c.Name] = true
+
+		if !c.Enabled {
+			continue
+		}
+		if c.BaseURL == "" {
+			return &ConfigError{Field: field + ".base_url", Message: "is required when provider is enabled"}
+		}
+		if c.DefaultModel == "" {
+			return &ConfigError{Field: field + ".default_model", Message: "is required when provider is enabled"}
+		}
+		if err := validateProviderAPI(c.Name, c.ProviderConfig, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateRoutingRules(rules []RoutingRuleConfig, providers map[string]bool) error {
+	for i, r := range rules {
+		field := fmt.Sprintf("routing.rules[%d]", i)
+		if r.Name == "" {
+			return &ConfigError{Field: field + ".name", Message: "is required"}
+		}
+		if !providers[r.Provider] {
+			return &ConfigError{Field: field + ".provider", Message: fmt.Sprintf("unknown provider %q", r.Provider)}
+		}
+		if r.Pattern != "" {
+			if _, err := regexp.Compile(r.Pattern); err != nil {
+				return &ConfigError{Field: field + ".pattern", Message: fmt.Sprintf("invalid regular expression: %v", err)}
+			}
+		}
+		if r.MinLength < 0 || r.MaxLength < 0 || r.MinTokens < 0 {
+			return &ConfigError{Field: field, Message: "min_length, max_length and min_tokens must be >= 0"}
+		}
+		if r.MaxLength > 0 && r.MinLength > r.MaxLength {
+			return &ConfigError{Field: field, Message: "min_length must not exceed max_length"}
+		}
+		if r.Pattern == "" && len(r.Keywords) == 0 && r.MinLength == 0 && r.MaxLength == 0 && r.MinTokens == 0 {
+			return &ConfigError{Field: field, Message: "needs at least one of pattern, keywords, min_length, max_length or min_tokens"}
+		}
+	}
+	return nil
+}
+
+// reactionEmoji are the emoji Telegram accepts in setMessageReaction.
+var reactionEmoji = strings.Fields("👍 👎 ❤ 🔥 🥰 👏 😁 🤔 🤯 😱 🤬 😢 🎉 🤩 🤮 💩 🙏 👌 🕊 🤡 🥱 🥴 😍 🐳 ❤‍🔥 🌚 🌭 💯 🤣 ⚡ 🍌 🏆 💔 🤨 😐 🍓 🍾 💋 🖕 😈 😴 😭 🤓 👻 👨‍💻 👀 🎃 🙈 😇 😨 🤝 ✍ 🤗 🫡 🎅 🎄 ☃ 💅 🤪 🗿 🆒 💘 🙉 🦄 😘 💊 🙊 😎 👾 🤷‍♂ 🤷 🤷‍♀ 😡")
+
+func validateReactions(r ReactionsConfig) error {
+	for _, f := range []struct{ name, emoji string }{
+		{"processing", r.Processing},
+		{"done", r.Done},
+		{"error", r.Error},
+	} {
+		if f.emoji != "" && !slices.Contains(reactionEmoji, strings.TrimSuffix(f.emoji, "\uFE0F")) {
+			return &ConfigError{Field: "telegram.reactions." + f.name, Message: fmt.Sprintf("%q is not an emoji Telegram allows as a reaction", f.emoji)}
+		}
+	}
+	return nil
+}
+
+func validateRedaction(r RedactionConfig, providers map[string]bool) error {
+	for i, t := range r.Types {
+		switch t {
+		case "email", "phone", "credit_card":
+		default:
+			return &ConfigError{Field: fmt.Sprintf("redaction.types[%d]", i), Message: fmt.Sprintf("unknown type %q (expected email, phone or credit_card)", t)}
+		}
+	}
+	for i, p := range r.Patterns {
+		field := fmt.Sprintf("redaction.patterns[%d]", i)
+		if !isValidCommandName(p.Name) {
+			return &ConfigError{Field: field + ".name", Message: "must be 1-32 lowercase letters, digits or underscores"}
+		}
+		if _, err := regexp.Compile(p.Pattern); err != nil || p.Pattern == "" {
+			return &ConfigError{Field: field + ".pattern", Message: "must be a valid regular expression"}
+		}
+	}
+	for i, name := range r.ExemptProviders {
+		if !providers[name] {
+			return &ConfigError{Field: fmt.Sprintf("redaction.exempt_providers[%d]", i), Message: fmt.Sprintf("unknown provider %q", name)}
+		}
+	}
+	return nil
+}
+
+func validateBots(bots []BotConfig, mainToken string, providers map[string]bool) error {
+	names := map[string]bool{"default": mainToken != ""}
+	tokens := map[string]bool{mainToken: mainToken != ""}
+	for i := range bots {
+		b := &bots[i]
+		field := fmt.Sprintf("bots[%d]", i)
+		if !isValidCommandName(b.Name) {
+			return &ConfigError{Field: field + ".name", Message: "must be 1-32 lowercase letters, digits or underscores"}
+		}
+		if names[b.Name] {
+			return &ConfigError{Field: field + ".name", Message: fmt.Sprintf("duplicate bot name %q", b.Name)}
+		}
+		names[b.Name] = true
+
+		if b.Token = strings.TrimSpace(b.Token); b.Token == "" {
+			if b.TokenEnv != "" {
+				return &ConfigError{Field: field + ".token_env", Message: fmt.Sprintf("environment variable %s is not set", b.TokenEnv)}
+			}
+			return &ConfigError{Field: field + ".token", Message: "is required and cannot be empty"}
+		}
+		if tokens[b.Token] {
+			return &ConfigError{Field: field + ".token", Message: "is already used by another bot"}
+		}
+		tokens[b.Token] = true
+
+		for _, userID := range b.AllowedUsers {
+			if userID <= 0 {
+				return &ConfigError{Field: field + ".allowed_users", Message: "each user ID must be a positive integer"}
+			}
+		}
+		if err := validateUsernames(field+".allowed_usernames", b.AllowedUsernames); err != nil {
+			return err
+		}
+		if b.DefaultProvider != "" && !providers[b.DefaultProvider] {
+			return &ConfigError{Field: field + ".default_provider", Message: fmt.Sprintf("unknown provider %q", b.DefaultProvider)}
+		}
+	}
+	return nil
+}
+
+// validateBotRefs checks that jobs sending to a chat name a configured bot.
+func validateBotRefs(cfg *Config) error {
+	bots := make(map[string]bool)
+	for _, b := range cfg.AllBots() {
+		bots[b.Name] = true
+	}
+	for i, sp := range cfg.ScheduledPrompts {
+		if sp.Bot != "" && !bots[sp.Bot] {
+			return &ConfigError{Field: fmt.Sprintf("scheduled_prompts[%d].bot", i), Message: fmt.Sprintf("unknown bot %q", sp.Bot)}
+		}
+	}
+	for i, sub := range cfg.MQTT.Subscriptions {
+		if sub.Bot != "" && !bots[sub.Bot] {
+			return &ConfigError{Field: fmt.Sprintf("mqtt.subscriptions[%d].bot", i), Message: fmt.Sprintf("unknown bot %q", sub.Bot)}
+		}
+	}
+	return nil
+}
+
+// UnknownFields reports keys in config.yaml that match no setting, usually
+// typos. They are ignored unless strict is set, in which case Load fails.
+func (c *Config) UnknownFields() error {
+	return c.unknown
+}
+
+// AllBots returns every bot to run: the one configured under telegram,
+// named "default", followed by the bots list. Bots without their own
+// allowed_users or allowed_usernames use the top-level lists.
+func (c *Config) AllBots() []BotConfig {
+	var bots []BotConfig
+	if c.Telegram.Token != "" {
+		bots = append(bots, BotConfig{Name: "default", Token: c.Telegram.Token, AllowedUsers: c.AllowedUsers, AllowedUsernames: c.AllowedUsernames})
+	}
+	for _, b := range c.Bots {
+		if b.AllowedUsers == nil {
+			b.AllowedUsers = c.AllowedUsers
+		}
+		if b.AllowedUsernames == nil {
+			b.AllowedUsernames = c.AllowedUsernames
+		}
+		bots = append(bots, b)
+	}
+	return bots
+}
+
+func validateCommands(commands map[string]CommandRouteConfig, providers map[string]bool) error {
+	for name, route := range commands {
+		field := "commands." + name
+		if !isValidCommandName(name) {
+			return &ConfigError{Field: field, Message: "command names must be 1-32 lowercase letters, digits or underscores"}
+		}
+		if builtinCommands[name] {
+			return &ConfigError{Field: field, Message: "conflicts with a built-in command"}
+		}
+		if route.Provider != "" && !providers[route.Provider] {
+			return &ConfigError{Field: field + ".provider", Message: fmt.Sprintf("unknown provider %q", route.Provider)}
+		}
+		if route.Provider == "" && route.Model != "" {
+			return &ConfigError{Field: field + ".model", Message: "requires a provider"}
+		}
+	}
+
+	return nil
+}
+
+func validateDigest(d DigestConfig, providers map[string]bool) error {
+	if d.At != "" {
+		if _, err := time.Parse("15:04", d.At); err != nil {
+			return &ConfigError{Field: "digest.at", Message: "must be a time in HH:MM format"}
+		}
+	}
+	if d.Weekday != "" && !isWeekday(d.Weekday) {
+		return &ConfigError{Field: "digest.weekday", Message: fmt.Sprintf("unknown weekday %q", d.Weekday)}
+	}
+	if d.Provider != "" && !providers[d.Provider] {
+		return &ConfigError{Field: "digest.provider", Message: fmt.Sprintf("unknown provider %q", d.Provider)}
+	}
+	return nil
+}
+
+func validateFeeds(f FeedsConfig, providers map[string]bool) error {
+	if f.IntervalMinutes != 0 && f.IntervalMinutes < 5 {
+		return &ConfigError{Field: "feeds.interval_minutes", Message: "must be at least 5"}
+	}
+	if f.Provider != "" && !providers[f.Provider] {
+		return &ConfigError{Field: "feeds.provider", Message: fmt.Sprintf("unknown provider %q", f.Provider)}
+	}
+	return nil
+}
+
+func validateProviderAccess(pa ProviderAccessConfig, providers map[string]bool) error {
+	if err := validateGrant("provider_access.default", pa.Default, providers); err != nil {
+		return err
+	}
+	for userID, g := range pa.Users {
+		if err := validateGrant(fmt.Sprintf("provider_access.users.%d", userID), g, providers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateWebhooks(webhooks []WebhookConfig) error {
+	for i, w := range webhooks {
+		field := fmt.Sprintf("webhooks[%d]", i)
+		u, err := url.Parse(w.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return &ConfigError{Field: field + ".url", Message: "must be an http or https URL"}
+		}
+		for _, event := range w.Events {
+			if !slices.Contains(webhook.Events, event) {
+				return &ConfigError{Field: field + ".events", Message: fmt.Sprintf("unknown event %q", event)}
+			}
+		}
+	}
+	return nil
+}
+
+func validateMQTT(m MQTTConfig, providers map[string]bool) error {
+	if !m.Enabled {
+		return nil
+	}
+	u, err := url.Parse(m.Broker)
+	if err != nil || u.Host == "" {
+		return &ConfigError{Field: "mqtt.broker", Message: "must be a URL such as tcp://localhost:1883"}
+	}
+	switch u.Scheme {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss":
+	default:
+		return &ConfigError{Field: "mqtt.broker", Message: "must be a tcp, ssl, ws or wss URL"}
+	}
+	if strings.ContainsAny(m.CommandTopic, "+#") {
+		return &ConfigError{Field: "mqtt.command_topic", Message: "must not contain wildcards"}
+	}
+	for i, sub := range m.Subscriptions {
+		field := fmt.Sprintf("mqtt.subscriptions[%d]", i)
+		if sub.Topic == "" {
+			return &ConfigError{Field: field + ".topic", Message: "is required"}
+		}
+		if sub.ChatID == 0 {
+			return &ConfigError{Field: field + ".chat_id", Message: "is required"}
+		}
+		if sub.Provider != "" && !providers[sub.Provider] {
+			return &ConfigError{Field: field + ".provider", Message: fmt.Sprintf("unknown provider %q", sub.Provider)}
+		}
+		if sub.Provider != "" && sub.Prompt == "" {
+			return &ConfigError{Field: field + ".provider", Message: "requires a prompt"}
+		}
+	}
+	return nil
+}
+
+func validateOffline(o OfflineConfig, providers ProvidersConfig) error {
+	if o.ProbeSeconds < 0 {
+		return &ConfigError{Field: "offline.probe_seconds", Message: "must be >= 0"}
+	}
+	if !o.Enabled {
+		return nil
+	}
+	var enabled bool
+	switch o.Provider {
+	case "", "ollama":
+		enabled = providers.Ollama.Enabled
+	case "local":
+		enabled = providers.Local.Enabled
+	default:
+		return &ConfigError{Field: "offline.provider", Message: "must be ollama or local"}
+	}
+	if !enabled {
+		return &ConfigError{Field: "offline.provider", Message: "must be an enabled provider"}
+	}
+	return nil
+}
+
+func validatePostprocess(steps []PostprocessConfig, providers map[string]bool) error {
+	for i, step := range steps {
+		field := fmt.Sprintf("postprocess[%d]", i)
+		switch step.Filter {
+		case "strip_think", "collapse_blank_lines":
+		case "max_length":
+			if step.MaxLength <= 0 {
+				return &ConfigError{Field: field + ".max_length", Message: "must be > 0"}
+			}
+		case "translate":
+			if step.Language == "" {
+				return &ConfigError{Field: field + ".language", Message: "is required for translate"}
+			}
+			if step.Provider != "" && !providers[step.Provider] {
+				return &ConfigError{Field: field + ".provider", Message: fmt.Sprintf("unknown provider %q", step.Provider)}
+			}
+			if step.Provider == "" && step.Model != "" {
+				return &ConfigError{Field: field + ".model", Message: "requires a provider"}
+			}
+		default:
+			return &ConfigError{Field: field + ".filter", Message: fmt.Sprintf("unknown filter %q (expected strip_think, collapse_blank_lines, max_length or translate)", step.Filter)}
+		}
+	}
+	return nil
+}
+
+var prefixAliasPattern = regexp.MustCompile(`^[a-z0-9_.-]+$`)
+
+func validatePrefixes(p PrefixConfig, providers map[string]bool) error {
+	for _, alias := range sortedKeys(p.Aliases) {
+		route := p.Aliases[alias]
+		field := "prefixes.aliases." + alias
+		if !prefixAliasPattern.MatchString(alias) {
+			return &ConfigError{Field: field, Message: "aliases must be lowercase letters, digits, dots, dashes or underscores"}
+		}
+		if route.Provider == "" {
+			return &ConfigError{Field: field + ".provider", Message: "is required"}
+		}
+		if !providers[route.Provider] {
+			return &ConfigError{Field: field + ".provider", Message: fmt.Sprintf("unknown provider %q", route.Provider)}
+		}
+	}
+	return nil
+}
+
+var usernamePattern = regexp.MustCompile(`^@?[A-Za-z0-9_]{5,32}$`)
+
+func validateUsernames(field string, usernames []string) error {
+	for _, name := range usernames {
+		if !usernamePattern.MatchString(name) {
+			return &ConfigError{Field: field, Message: fmt.Sprintf("%q is not a Telegram username (5-32 letters, digits or underscores)", name)}
+		}
+	}
+	return nil
+}
+
+func validateRoles(r RolesConfig) error {
+	lists := []struct {
+		field string
+		ids   []int64
+	}{
+		{"roles.owners", r.Owners},
+		{"roles.admins", r.Admins},
+		{"roles.users", r.Users},
+		{"roles.guests", r.Guests},
+	}
+	for _, l := range lists {
+		for _, id := range l.ids {
+			if id <= 0 {
+				return &ConfigError{Field: l.field, Message: "each user ID must be a positive integer"}
+			}
+		}
+	}
+
+	if r.Guest.MessagesPerMinute < 0 {
+		return &ConfigError{Field: "roles.guest.messages_per_minute", Message: "must be >= 0"}
+	}
+	if r.Guest.Burst < 0 {
+		return &ConfigError{Field: "roles.guest.burst", Message: "must be >= 0"}
+	}
+	if len(r.Guests) > 0 && len(r.Guest.Providers) == 0 && len(r.Guest.Models) == 0 {
+		return &ConfigError{Field: "roles.guest.providers", Message: "or roles.guest.models is required when guests are listed"}
+	}
+	return nil
+}
+
+func validateGrant(field string, g ProviderGrantConfig, providers map[string]bool) error {
+	for _, p := range g.Providers {
+		if !providers[p] {
+			return &ConfigError{Field: field + ".providers", Message: fmt.Sprintf("unknown provider %q", p)}
+		}
+	}
+	return nil
+}
+
+func isWeekday(day string) bool {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(day, d.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePersonas(personas []PersonaConfig, providers map[string]bool) error {
+	names := make(map[string]bool)
+	for i, p := range personas {
+		field := fmt.Sprintf("personas[%d]", i)
+		if !isValidCommandName(p.Name) {
+			return &ConfigError{Field: field + ".name", Message: "must be 1-32 lowercase letters, digits or underscores"}
+		}
+		if names[p.Name] {
+			return &ConfigError{Field: field + ".name", Message: fmt.Sprintf("duplicate persona %q", p.Name)}
+		}
+		names[p.Name] = true
+
+		if p.Provider != "" && !providers[p.Provider] {
+			return &ConfigError{Field: field + ".provider", Message: fmt.Sprintf("unknown provider %q", p.Provider)}
+		}
+		if p.Provider == "" && p.Model != "" {
+			return &ConfigError{Field: field + ".model", Message: "requires a provider"}
+		}
+		if t, max := p.Temperature, maxTemperature(p.Provider); t != nil && (*t < 0 || *t > max) {
+			return &ConfigError{Field: field + ".temperature", Message: fmt.Sprintf("must be between 0 and %g", max)}
+		}
+	}
+	return nil
+}
+
+func isValidCommandName(name string) bool {
+	if len(name) == 0 || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func validateAPIKeys(cfg *Config) error {
+	if cfg.Providers.OpenAI.Enabled {
+		if cfg.APIKeys["OPENAI_API_KEY"] == "" {
+			return &ConfigError{Field: "OPENAI_API_KEY", Message: "is required when openai provider is enabled"}
+		}
+	}
+
+	if cfg.Providers.Anthropic.Enabled {
+		if cfg.APIKeys["ANTHROPIC_API_KEY"] == "" {
+			return &ConfigError{Field: "ANTHROPIC_API_KEY", Message: "is required when anthropic provider is enabled"}
+		}
+	}
+
+	if cfg.Providers.OpenRouter.Enabled {
+		if cfg.APIKeys["OPENROUTER_API_KEY"] == "" {
+			return &ConfigError{Field: "OPENROUTER_API_KEY", Message: "is required when openrouter provider is enabled"}
+		}
+	}
+
+	if cfg.Providers.OpenCode.Enabled {
+		if cfg.APIKeys["OPENCODE_API_KEY"] == "" {
+			return &ConfigError{Field: "OPENCODE_API_KEY", Message: "is required when opencode provider is enabled"}
+		}
+	}
+
+	for _, c := range cfg.Providers.OpenAICompatible {
+		if c.Enabled && c.APIKeyEnv != "" && cfg.APIKeys[c.APIKeyEnv] == "" {
+			return &ConfigError{Field: c.APIKeyEnv, Message: fmt.Sprintf("is required when %s provider is enabled", c.Name)}
+		}
+	}
+
+	if cfg.API.Enabled && cfg.APIKeys["HELPI_API_TOKEN"] == "" {
+		return &ConfigError{Field: "HELPI_API_TOKEN", Message: "is required when api is enabled"}
+	}
+
+	for _, w := range cfg.Webhooks {
+		if w.SecretEnv != "" && cfg.APIKeys[w.SecretEnv] == "" {
+			return &ConfigError{Field: w.SecretEnv, Message: fmt.Sprintf("is required to sign webhooks to %s", w.URL)}
 		}
 	}
 
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schema walks a parsed config.yaml alongside the Config type. It collects
+// keys that match no field and remembers the line of every known key so
+// validation errors can point at it.
+type schema struct {
+	lines   map[string]int
+	unknown []error
+}
+
+func checkSchema(doc *yaml.Node) (map[string]int, error) {
+	s := &schema{lines: make(map[string]int)}
+	s.walk(doc, reflect.TypeOf(Config{}), "")
+	return s.lines, errors.Join(s.unknown...)
+}
+
+func (s *schema) walk(node *yaml.Node, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			s.walk(child, t, path)
+		}
+		return
+	case yaml.AliasNode:
+		s.walk(node.Alias, t, path)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				s.walk(value, t, path)
+				continue
+			}
+			field := joinPath(path, key.Value)
+			ft, ok := fields[key.Value]
+			if !ok {
+				s.unknown = append(s.unknown, &ConfigError{Field: field, Message: unknownFieldMessage(key.Value, fields), Line: key.Line})
+				continue
+			}
+			s.lines[field] = key.Line
+			s.walk(value, ft, field)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field := joinPath(path, key.Value)
+			s.lines[field] = key.Line
+			s.walk(value, t.Elem(), field)
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range node.Content {
+			field := fmt.Sprintf("%s[%d]", path, i)
+			s.lines[field] = item.Line
+			s.walk(item, t.Elem(), field)
+		}
+	}
+}
+
+// yamlFields maps the YAML keys of struct type t to their field types,
+// following inlined structs.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			for k, v := range yamlFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func unknownFieldMessage(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 0
+	for name := range fields {
+		d := editDistance(key, name)
+		if best == "" || d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	if best != "" && bestDist <= max(2, len(key)/3) {
+		return fmt.Sprintf("unknown field, did you mean %q?", best)
+	}
+	return "unknown field"
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
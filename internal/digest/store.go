@@ -0,0 +1,184 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	Daily  = "daily"
+	Weekly = "weekly"
+)
+
+// ValidFrequency reports whether f is a digest frequency users can pick.
+func ValidFrequency(f string) bool {
+	return f == Daily || f == Weekly
+}
+
+// Entry is one answered message of a user who opted in to digests.
+type Entry struct {
+	UserID    int64     `json:"user_id"`
+	Prompt    string    `json:"prompt"`
+	Response  string    `json:"response"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store keeps recent exchanges until they are old enough to have been
+// included in every digest.
+type Store interface {
+	Record(entry Entry) error
+	Since(userID int64, since time.Time) ([]Entry, error)
+	Prune(before time.Time) error
+}
+
+// fileStore keeps one JSON entry per line, so recording an exchange only
+// appends to the file instead of rewriting every user's entries.
+type fileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewStore(path string) (Store, error) {
+	if path == "" {
+		path = "./data/digest.json"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create digest directory: %w", err)
+	}
+
+	s := &fileStore{path: path}
+	if err := s.migrate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate rewrites a file holding a single JSON array, the format of older
+// versions, as one entry per line.
+func (s *fileStore) migrate() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read digest entries: %w", err)
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return nil
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse digest entries: %w", err)
+	}
+	return s.write(entries)
+}
+
+func (s *fileStore) Record(entry Entry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal digest entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open digest entries: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write digest entry: %w", err)
+	}
+	return f.Close()
+}
+
+func (s *fileStore) Since(userID int64, since time.Time) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Entry
+	for _, e := range entries {
+		if e.UserID == userID && !e.CreatedAt.Before(since) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (s *fileStore) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	kept := entries[:0]
+	for _, e := range entries {
+		if !e.CreatedAt.Before(before) {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	return s.write(kept)
+}
+
+func (s *fileStore) read() ([]Entry, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read digest entries: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	dec := json.NewDecoder(f)
+	for {
+		var e Entry
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse digest entries: %w", err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+func (s *fileStore) write(entries []Entry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to marshal digest entries: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(s.path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write digest entries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package digest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordSinceAndPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digest.json")
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+
+	now := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{UserID: 1, Prompt: "old", CreatedAt: now.AddDate(0, 0, -8)},
+		{UserID: 1, Prompt: "this week", CreatedAt: now.AddDate(0, 0, -3)},
+		{UserID: 1, Prompt: "today", CreatedAt: now.Add(-time.Hour)},
+		{UserID: 2, Prompt: "other user", CreatedAt: now.Add(-time.Hour)},
+	} {
+		if err := s.Record(e); err != nil {
+			t.Fatalf("Record() returned error: %v", err)
+		}
+	}
+
+	day, err := s.Since(1, now.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("Since() returned error: %v", err)
+	}
+	if len(day) != 1 || day[0].Prompt != "today" {
+		t.Errorf("expected only today's entry, got %+v", day)
+	}
+
+	if err := s.Prune(now.AddDate(0, 0, -7)); err != nil {
+		t.Fatalf("Prune() returned error: %v", err)
+	}
+	reopened, _ := NewStore(path)
+	week, _ := reopened.Since(1, time.Time{})
+	if len(week) != 2 || week[0].Prompt != "this week" {
+		t.Errorf("expected the old entry to be pruned, got %+v", week)
+	}
+}
+
+func TestValidFrequency(t *testing.T) {
+	if !ValidFrequency(Daily) || !ValidFrequency(Weekly) || ValidFrequency("monthly") {
+		t.Error("unexpected ValidFrequency result")
+	}
+}
+
+func TestNewStore_MigratesArrayFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digest.json")
+	legacy := `[{"user_id": 1, "prompt": "before", "created_at": "2024-01-08T07:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+	if err := s.Record(Entry{UserID: 1, Prompt: "after"}); err != nil {
+		t.Fatalf("Record() returned error: %v", err)
+	}
+
+	entries, err := s.Since(1, time.Time{})
+	if err != nil {
+		t.Fatalf("Since() returned error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Prompt != "before" || entries[1].Prompt != "after" {
+		t.Errorf("expected the old entry followed by the new one, got %+v", entries)
+	}
+}
@@ -0,0 +1,106 @@
+package facts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	extractInstructions = "You maintain a list of lasting facts about a user, such as their name, location, job, diet, family, " +
+		"preferences and ongoing projects. Read the exchange below and list any new facts it reveals about the user, " +
+		"one short sentence per line, written as the user would say them (for example \"I am vegetarian\"). " +
+		"Only include facts that will still be true in future conversations. Do not repeat known facts, and ignore " +
+		"questions, requests and anything about the assistant. If there is nothing new, reply with NONE."
+	// maxExtracted caps how many facts one exchange can add, so a confused
+	// answer cannot fill the list.
+	maxExtracted = 3
+)
+
+var listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s*`)
+
+type Completer interface {
+	SendMessage(ctx context.Context, messages []llm.Message, opts ...llm.RequestOption) (string, error)
+}
+
+// Extractor asks a model for lasting facts in each exchange and adds them to
+// the store as learned facts, where /memories shows them and /forget removes
+// them like any other.
+type Extractor struct {
+	completer Completer
+	store     Store
+}
+
+func NewExtractor(completer Completer, store Store) *Extractor {
+	return &Extractor{completer: completer, store: store}
+}
+
+// Extract returns the facts it added.
+func (e *Extractor) Extract(ctx context.Context, userID int64, user, assistant string, opts ...llm.RequestOption) ([]Fact, error) {
+	known, err := e.store.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(known) >= MaxFacts {
+		return nil, nil
+	}
+
+	response, err := e.completer.SendMessage(ctx, extractRequest(known, user, assistant), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract facts: %w", err)
+	}
+
+	var added []Fact
+	for _, text := range parseFacts(response) {
+		if len(added) == maxExtracted {
+			break
+		}
+		fact := Fact{Text: text, Learned: true}
+		switch err := e.store.Add(userID, fact); {
+		case err == nil:
+			added = append(added, fact)
+		case errors.Is(err, ErrDuplicate), errors.Is(err, ErrTooLong):
+		case errors.Is(err, ErrLimit):
+			return added, nil
+		default:
+			return added, err
+		}
+	}
+	return added, nil
+}
+
+func extractRequest(known []Fact, user, assistant string) []llm.Message {
+	var sb strings.Builder
+	if len(known) > 0 {
+		sb.WriteString("Known facts:\n")
+		for _, f := range known {
+			sb.WriteString("- " + f.Text + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("User: " + user + "\n")
+	sb.WriteString("Assistant: " + assistant + "\n")
+
+	return []llm.Message{
+		{Role: "system", Content: extractInstructions},
+		{Role: "user", Content: sb.String()},
+	}
+}
+
+// parseFacts reads one fact per line, dropping list markers and the NONE
+// reply.
+func parseFacts(response string) []string {
+	var facts []string
+	for _, line := range strings.Split(response, "\n") {
+		line = listMarker.ReplaceAllString(strings.TrimSpace(line), "")
+		if line == "" || strings.EqualFold(strings.TrimRight(line, "."), "none") {
+			continue
+		}
+		facts = append(facts, line)
+	}
+	return facts
+}
@@ -0,0 +1,56 @@
+package facts
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type fakeCompleter struct {
+	response string
+	err      error
+	request  []llm.Message
+}
+
+func (c *fakeCompleter) SendMessage(ctx context.Context, messages []llm.Message, opts ...llm.RequestOption) (string, error) {
+	c.request = messages
+	return c.response, c.err
+}
+
+func TestExtract(t *testing.T) {
+	store, _ := NewStore(t.TempDir())
+	store.Add(1, Fact{Text: "I live in Lisbon"})
+	completer := &fakeCompleter{response: "- I am vegetarian\n2. I live in Lisbon\n3D printing is my hobby\n"}
+
+	added, err := NewExtractor(completer, store).Extract(context.Background(), 1, "Any vegetarian dinner ideas? I print on my 3D printer while cooking.", "Try a chickpea curry.")
+	if err != nil {
+		t.Fatalf("Extract() returned error: %v", err)
+	}
+	if len(added) != 2 || added[0].Text != "I am vegetarian" || added[1].Text != "3D printing is my hobby" {
+		t.Fatalf("unexpected facts %+v", added)
+	}
+	if !strings.Contains(completer.request[1].Content, "Known facts:\n- I live in Lisbon") {
+		t.Errorf("expected known facts in the request, got %q", completer.request[1].Content)
+	}
+
+	list, _ := store.List(1)
+	if len(list) != 3 || list[0].Learned || !list[1].Learned {
+		t.Errorf("expected extracted facts to be marked as learned, got %+v", list)
+	}
+}
+
+func TestExtract_Nothing(t *testing.T) {
+	store, _ := NewStore(t.TempDir())
+	added, err := NewExtractor(&fakeCompleter{response: "NONE."}, store).Extract(context.Background(), 1, "What is 2+2?", "4")
+	if err != nil || len(added) != 0 {
+		t.Errorf("expected no facts, got %+v, %v", added, err)
+	}
+
+	_, err = NewExtractor(&fakeCompleter{err: errors.New("rate limited")}, store).Extract(context.Background(), 1, "hi", "hello")
+	if err == nil {
+		t.Error("expected the provider error to be returned")
+	}
+}
@@ -0,0 +1,153 @@
+package facts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxFacts is how many facts a user can keep.
+	MaxFacts = 50
+	// MaxLength is the longest fact, in characters.
+	MaxLength = 500
+)
+
+var (
+	ErrDuplicate = errors.New("fact already stored")
+	ErrLimit     = fmt.Errorf("at most %d facts can be stored", MaxFacts)
+	ErrTooLong   = fmt.Errorf("facts must be at most %d characters", MaxLength)
+	ErrNotFound  = errors.New("no such fact")
+)
+
+// Fact is something a user asked the bot to keep in mind in every
+// conversation.
+type Fact struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	// Learned marks facts picked out of a conversation by an Extractor
+	// rather than added with /remember.
+	Learned bool `json:"learned,omitempty"`
+}
+
+// Store keeps each user's facts apart from their conversation history, so
+// clearing or truncating a conversation does not lose them.
+type Store interface {
+	Add(userID int64, fact Fact) error
+	List(userID int64) ([]Fact, error)
+	// Remove deletes the fact at index, counting from zero in List order.
+	Remove(userID int64, index int) (Fact, error)
+	Clear(userID int64) error
+}
+
+type fileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore keeps facts in one JSON file per user under dir.
+func NewStore(dir string) (Store, error) {
+	if dir == "" {
+		dir = "./data/facts"
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create facts directory: %w", err)
+	}
+
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) Add(userID int64, fact Fact) error {
+	fact.Text = strings.TrimSpace(fact.Text)
+	if len([]rune(fact.Text)) > MaxLength {
+		return ErrTooLong
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.read(userID)
+	if err != nil {
+		return err
+	}
+	for _, f := range facts {
+		if strings.EqualFold(f.Text, fact.Text) {
+			return ErrDuplicate
+		}
+	}
+	if len(facts) >= MaxFacts {
+		return ErrLimit
+	}
+	if fact.CreatedAt.IsZero() {
+		fact.CreatedAt = time.Now()
+	}
+	return s.write(userID, append(facts, fact))
+}
+
+func (s *fileStore) List(userID int64) ([]Fact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(userID)
+}
+
+func (s *fileStore) Remove(userID int64, index int) (Fact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.read(userID)
+	if err != nil {
+		return Fact{}, err
+	}
+	if index < 0 || index >= len(facts) {
+		return Fact{}, ErrNotFound
+	}
+	removed := facts[index]
+	return removed, s.write(userID, append(facts[:index], facts[index+1:]...))
+}
+
+func (s *fileStore) Clear(userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(userID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove facts: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) read(userID int64) ([]Fact, error) {
+	data, err := os.ReadFile(s.path(userID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read facts: %w", err)
+	}
+
+	var facts []Fact
+	if err := json.Unmarshal(data, &facts); err != nil {
+		return nil, fmt.Errorf("failed to parse facts: %w", err)
+	}
+	return facts, nil
+}
+
+func (s *fileStore) write(userID int64, facts []Fact) error {
+	data, err := json.Marshal(facts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal facts: %w", err)
+	}
+	if err := os.WriteFile(s.path(userID), data, 0600); err != nil {
+		return fmt.Errorf("failed to write facts: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) path(userID int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%d.json", userID))
+}
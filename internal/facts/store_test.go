@@ -0,0 +1,73 @@
+package facts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+
+	if err := s.Add(1, Fact{Text: "  I am vegetarian "}); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if err := s.Add(1, Fact{Text: "I live in Lisbon"}); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if err := s.Add(1, Fact{Text: "i am VEGETARIAN"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("expected ErrDuplicate, got %v", err)
+	}
+	if err := s.Add(1, Fact{Text: strings.Repeat("a", MaxLength+1)}); !errors.Is(err, ErrTooLong) {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+	if err := s.Add(2, Fact{Text: "I prefer metric units"}); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+
+	reopened, _ := NewStore(dir)
+	list, err := reopened.List(1)
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+	if len(list) != 2 || list[0].Text != "I am vegetarian" || list[0].CreatedAt.IsZero() {
+		t.Fatalf("unexpected facts %+v", list)
+	}
+
+	removed, err := reopened.Remove(1, 0)
+	if err != nil || removed.Text != "I am vegetarian" {
+		t.Fatalf("Remove() = %+v, %v", removed, err)
+	}
+	if _, err := reopened.Remove(1, 5); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if list, _ := reopened.List(1); len(list) != 1 || list[0].Text != "I live in Lisbon" {
+		t.Errorf("unexpected facts after Remove %+v", list)
+	}
+
+	if err := reopened.Clear(1); err != nil {
+		t.Fatalf("Clear() returned error: %v", err)
+	}
+	if list, _ := reopened.List(1); len(list) != 0 {
+		t.Errorf("expected no facts after Clear, got %+v", list)
+	}
+	if list, _ := reopened.List(2); len(list) != 1 {
+		t.Errorf("expected other users' facts to be kept, got %+v", list)
+	}
+}
+
+func TestStore_Limit(t *testing.T) {
+	s, _ := NewStore(t.TempDir())
+	for i := 0; i < MaxFacts; i++ {
+		if err := s.Add(1, Fact{Text: strings.Repeat("x", i+1)}); err != nil {
+			t.Fatalf("Add() returned error: %v", err)
+		}
+	}
+	if err := s.Add(1, Fact{Text: "one too many"}); !errors.Is(err, ErrLimit) {
+		t.Errorf("expected ErrLimit, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	KindFeedback  = "feedback"
+	KindBadAnswer = "bad_answer"
+	// KindRating is a 👍 or 👎 on an answer. Score is 1 or -1 and
+	// Conversation holds the question and the rated answer.
+	KindRating = "rating"
+)
+
+type Entry struct {
+	ID           int           `json:"id"`
+	Kind         string        `json:"kind"`
+	UserID       int64         `json:"user_id"`
+	ChatID       int64         `json:"chat_id"`
+	Text         string        `json:"text,omitempty"`
+	Answer       string        `json:"answer,omitempty"`
+	Score        int           `json:"score,omitempty"`
+	MessageID    string        `json:"message_id,omitempty"`
+	Conversation []llm.Message `json:"conversation"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type Store interface {
+	Add(entry Entry) (Entry, error)
+	List() ([]Entry, error)
+}
+
+type fileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewStore(path string) (Store, error) {
+	if path == "" {
+		path = "./data/feedback.json"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create feedback directory: %w", err)
+	}
+
+	return &fileStore{path: path}, nil
+}
+
+func (s *fileStore) Add(entry Entry) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.ID = 1
+	if len(entries) > 0 {
+		entry.ID = entries[len(entries)-1].ID + 1
+	}
+	if entry.Kind == "" {
+		entry.Kind = KindFeedback
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	if err := s.write(append(entries, entry)); err != nil {
+		return Entry{}, err
+	}
+
+	return entry, nil
+}
+
+func (s *fileStore) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read()
+}
+
+func (s *fileStore) read() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feedback: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse feedback: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *fileStore) write(entries []Entry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feedback: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write feedback: %w", err)
+	}
+
+	return nil
+}
+
+// Ratings counts the positive and negative ratings in entries. When a user
+// rated the same answer more than once, only the last rating counts.
+func Ratings(entries []Entry) (up, down int) {
+	type rated struct {
+		userID    int64
+		messageID string
+	}
+	latest := make(map[rated]int)
+	for _, e := range entries {
+		if e.Kind == KindRating {
+			latest[rated{e.UserID, e.MessageID}] = e.Score
+		}
+	}
+	for _, score := range latest {
+		switch {
+		case score > 0:
+			up++
+		case score < 0:
+			down++
+		}
+	}
+	return up, down
+}
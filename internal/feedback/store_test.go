@@ -0,0 +1,70 @@
+package feedback
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestAddAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feedback.json")
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+
+	first, err := s.Add(Entry{UserID: 1, Text: "great bot", Conversation: []llm.Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if first.ID != 1 || first.Kind != KindFeedback || first.CreatedAt.IsZero() {
+		t.Errorf("expected defaults to be applied, got %+v", first)
+	}
+
+	second, err := s.Add(Entry{UserID: 2, Kind: KindBadAnswer, Answer: "wrong"})
+	if err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if second.ID != 2 {
+		t.Errorf("expected sequential IDs, got %d", second.ID)
+	}
+
+	reopened, _ := NewStore(path)
+	entries, err := reopened.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Conversation[0].Content != "hi" || entries[1].Answer != "wrong" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestList_Empty(t *testing.T) {
+	s, _ := NewStore(filepath.Join(t.TempDir(), "feedback.json"))
+	entries, err := s.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
+
+func TestRatings_LastRatingCounts(t *testing.T) {
+	entries := []Entry{
+		{Kind: KindRating, UserID: 1, MessageID: "a", Score: 1},
+		{Kind: KindRating, UserID: 1, MessageID: "a", Score: -1},
+		{Kind: KindRating, UserID: 2, MessageID: "a", Score: 1},
+		{Kind: KindRating, UserID: 1, MessageID: "b", Score: 1},
+		{Kind: KindBadAnswer, UserID: 3, Answer: "wrong"},
+	}
+
+	up, down := Ratings(entries)
+	if up != 2 || down != 1 {
+		t.Errorf("Ratings() = %d up, %d down, want 2 up, 1 down", up, down)
+	}
+}
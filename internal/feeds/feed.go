@@ -0,0 +1,213 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxFeedSize caps how much of a feed is read. Feeds with long histories
+// are cut off, which only loses their oldest items.
+const maxFeedSize = 5 << 20
+
+var (
+	ErrNotFeed   = errors.New("not an RSS or Atom feed")
+	ErrNotPublic = errors.New("feed address is not a public IP address")
+
+	// sharedAddressSpace is the carrier-grade NAT range, which is not
+	// reachable from the internet either.
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+	htmlTags = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Feed is a parsed RSS or Atom feed. Items are in the order the feed lists
+// them, which is usually newest first.
+type Feed struct {
+	Title string
+	Items []Item
+}
+
+type Item struct {
+	// ID is the item's guid or Atom id, or its link when it has neither.
+	ID      string
+	Title   string
+	Link    string
+	Summary string
+}
+
+// NewClient returns the HTTP client used to fetch feeds. It only connects to
+// public IP addresses, also after redirects, so /watch cannot reach the
+// bot's host, its network or cloud metadata services.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: publicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Through a proxy only the proxy's address would be checked.
+	transport.Proxy = nil
+	return &http.Client{Timeout: 30 * time.Second, Transport: transport}
+}
+
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrNotPublic, ip)
+	}
+	return nil
+}
+
+// Fetch downloads and parses the feed at url.
+func Fetch(ctx context.Context, client *http.Client, url string) (Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Feed{}, fmt.Errorf("invalid feed URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "helpi")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Feed{}, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Feed{}, fmt.Errorf("failed to fetch feed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return Feed{}, fmt.Errorf("failed to read feed: %w", err)
+	}
+	return Parse(data)
+}
+
+type rssItem struct {
+	GUID  string `xml:"guid"`
+	Title string `xml:"title"`
+	// Links also collects atom:link elements, which carry no text.
+	Links       []string `xml:"link"`
+	Description string   `xml:"description"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomEntry struct {
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Links   []atomLink `xml:"link"`
+	Summary string     `xml:"summary"`
+	Content string     `xml:"content"`
+}
+
+// document covers RSS 2.0, RSS 1.0 (RDF) and Atom, which differ in where
+// the title and items live.
+type document struct {
+	XMLName      xml.Name
+	ChannelTitle string      `xml:"channel>title"`
+	ChannelItems []rssItem   `xml:"channel>item"`
+	RDFItems     []rssItem   `xml:"item"`
+	Title        string      `xml:"title"`
+	Entries      []atomEntry `xml:"entry"`
+}
+
+// Parse reads an RSS 2.0, RSS 1.0 or Atom document.
+func Parse(data []byte) (Feed, error) {
+	var doc document
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		// Feeds that declare a legacy charset are nearly always plain ASCII
+		// in practice, so they are read as is.
+		return input, nil
+	}
+	if err := decoder.Decode(&doc); err != nil {
+		return Feed{}, fmt.Errorf("%w: %v", ErrNotFeed, err)
+	}
+
+	var feed Feed
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "rss":
+		feed.Title = doc.ChannelTitle
+		feed.Items = rssItems(doc.ChannelItems)
+	case "rdf":
+		feed.Title = doc.ChannelTitle
+		feed.Items = rssItems(doc.RDFItems)
+	case "feed":
+		feed.Title = doc.Title
+		for _, e := range doc.Entries {
+			item := Item{ID: strings.TrimSpace(e.ID), Title: clean(e.Title), Link: entryLink(e.Links), Summary: clean(e.Summary)}
+			if item.Summary == "" {
+				item.Summary = clean(e.Content)
+			}
+			if item.ID == "" {
+				item.ID = item.Link
+			}
+			feed.Items = append(feed.Items, item)
+		}
+	default:
+		return Feed{}, ErrNotFeed
+	}
+	feed.Title = clean(feed.Title)
+	return feed, nil
+}
+
+func rssItems(items []rssItem) []Item {
+	result := make([]Item, 0, len(items))
+	for _, i := range items {
+		item := Item{ID: strings.TrimSpace(i.GUID), Title: clean(i.Title), Summary: clean(i.Description)}
+		for _, link := range i.Links {
+			if link = strings.TrimSpace(link); link != "" {
+				item.Link = link
+				break
+			}
+		}
+		if item.ID == "" {
+			item.ID = item.Link
+		}
+		if item.ID == "" {
+			item.ID = item.Title
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func entryLink(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	if len(links) > 0 {
+		return strings.TrimSpace(links[0].Href)
+	}
+	return ""
+}
+
+// clean turns an HTML fragment into plain text on one line.
+func clean(s string) string {
+	s = htmlTags.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
@@ -0,0 +1,125 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+  <title>Example &amp; Co</title>
+  <atom:link href="https://example.com/feed.xml" rel="self"/>
+  <item>
+    <title>Second post</title>
+    <link>https://example.com/2</link>
+    <atom:link href="https://example.com/2" rel="alternate"/>
+    <guid>post-2</guid>
+    <description>&lt;p&gt;Hello &lt;b&gt;world&lt;/b&gt;&lt;/p&gt;</description>
+  </item>
+  <item>
+    <title>First post</title>
+    <link>https://example.com/1</link>
+  </item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom blog</title>
+  <entry>
+    <id>tag:example.com,2024:1</id>
+    <title>Entry</title>
+    <link rel="replies" href="https://example.com/1#comments"/>
+    <link href="https://example.com/1"/>
+    <content type="html">&lt;p&gt;Body&lt;/p&gt;</content>
+  </entry>
+</feed>`
+
+const rdfFeed = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+  <channel><title>RDF news</title></channel>
+  <item><title>Story</title><link>https://example.com/story</link></item>
+</rdf:RDF>`
+
+func TestParse(t *testing.T) {
+	feed, err := Parse([]byte(rssFeed))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if feed.Title != "Example & Co" || len(feed.Items) != 2 {
+		t.Fatalf("unexpected feed %+v", feed)
+	}
+	if want := (Item{ID: "post-2", Title: "Second post", Link: "https://example.com/2", Summary: "Hello world"}); feed.Items[0] != want {
+		t.Errorf("expected %+v, got %+v", want, feed.Items[0])
+	}
+	if feed.Items[1].ID != "https://example.com/1" {
+		t.Errorf("expected the link as ID without a guid, got %q", feed.Items[1].ID)
+	}
+
+	feed, err = Parse([]byte(atomFeed))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if want := (Item{ID: "tag:example.com,2024:1", Title: "Entry", Link: "https://example.com/1", Summary: "Body"}); feed.Title != "Atom blog" || len(feed.Items) != 1 || feed.Items[0] != want {
+		t.Errorf("unexpected Atom feed %+v", feed)
+	}
+
+	feed, err = Parse([]byte(rdfFeed))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if feed.Title != "RDF news" || len(feed.Items) != 1 || feed.Items[0].Link != "https://example.com/story" {
+		t.Errorf("unexpected RDF feed %+v", feed)
+	}
+}
+
+func TestParse_NotFeed(t *testing.T) {
+	for _, data := range []string{"<html><body>hi</body></html>", "not xml at all"} {
+		if _, err := Parse([]byte(data)); !errors.Is(err, ErrNotFeed) {
+			t.Errorf("expected ErrNotFeed for %q, got %v", data, err)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(rssFeed))
+	}))
+	defer server.Close()
+
+	feed, err := Fetch(context.Background(), server.Client(), server.URL+"/feed.xml")
+	if err != nil || len(feed.Items) != 2 {
+		t.Fatalf("Fetch() = %+v, %v", feed, err)
+	}
+	if _, err := Fetch(context.Background(), server.Client(), server.URL+"/missing"); err == nil {
+		t.Error("expected an error for a missing feed")
+	}
+}
+
+func TestNewClient_RefusesNonPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rssFeed))
+	}))
+	defer server.Close()
+
+	if _, err := Fetch(context.Background(), NewClient(), server.URL); !errors.Is(err, ErrNotPublic) {
+		t.Errorf("expected ErrNotPublic for a loopback feed, got %v", err)
+	}
+
+	for _, addr := range []string{"10.0.0.1:80", "192.168.1.1:80", "169.254.169.254:80", "100.64.0.1:80", "[::1]:80", "[fe80::1]:80", "0.0.0.0:80"} {
+		if err := publicOnly("tcp", addr, nil); !errors.Is(err, ErrNotPublic) {
+			t.Errorf("%s: expected ErrNotPublic, got %v", addr, err)
+		}
+	}
+	if err := publicOnly("tcp", "93.184.215.14:443", nil); err != nil {
+		t.Errorf("expected a public address to be allowed, got %v", err)
+	}
+}
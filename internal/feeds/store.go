@@ -0,0 +1,189 @@
+package feeds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxPerUser is how many feeds one user can watch across all chats.
+	MaxPerUser = 20
+	// maxSeen is how many item IDs are remembered per subscription. It only
+	// needs to exceed the number of items a feed lists at once.
+	maxSeen = 500
+)
+
+var (
+	ErrDuplicate = errors.New("feed already watched in this chat")
+	ErrLimit     = fmt.Errorf("at most %d feeds can be watched", MaxPerUser)
+	ErrNotFound  = errors.New("no such subscription")
+)
+
+// Subscription is a feed watched by a user, whose new items are posted to
+// ChatID.
+type Subscription struct {
+	ChatID    int64     `json:"chat_id"`
+	UserID    int64     `json:"user_id"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	Seen      []string  `json:"seen"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Store interface {
+	Add(sub Subscription) error
+	// List returns the subscriptions of a chat in the order they were added.
+	List(chatID int64) ([]Subscription, error)
+	// Remove deletes the subscription at index, counting from zero in List
+	// order.
+	Remove(chatID int64, index int) (Subscription, error)
+	All() ([]Subscription, error)
+	// SetSeen records the item IDs already posted for a subscription.
+	SetSeen(chatID int64, url string, seen []string) error
+}
+
+type fileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewStore(path string) (Store, error) {
+	if path == "" {
+		path = "./data/feeds.json"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create feeds directory: %w", err)
+	}
+
+	return &fileStore{path: path}, nil
+}
+
+func (s *fileStore) Add(sub Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs, err := s.read()
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, existing := range subs {
+		if existing.ChatID == sub.ChatID && existing.URL == sub.URL {
+			return ErrDuplicate
+		}
+		if existing.UserID == sub.UserID {
+			count++
+		}
+	}
+	if count >= MaxPerUser {
+		return ErrLimit
+	}
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = time.Now()
+	}
+	sub.Seen = trimSeen(sub.Seen)
+	return s.write(append(subs, sub))
+}
+
+func (s *fileStore) List(chatID int64) ([]Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	var result []Subscription
+	for _, sub := range subs {
+		if sub.ChatID == chatID {
+			result = append(result, sub)
+		}
+	}
+	return result, nil
+}
+
+func (s *fileStore) Remove(chatID int64, index int) (Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs, err := s.read()
+	if err != nil {
+		return Subscription{}, err
+	}
+	n := 0
+	for i, sub := range subs {
+		if sub.ChatID != chatID {
+			continue
+		}
+		if n == index {
+			return sub, s.write(append(subs[:i], subs[i+1:]...))
+		}
+		n++
+	}
+	return Subscription{}, ErrNotFound
+}
+
+func (s *fileStore) All() ([]Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+func (s *fileStore) SetSeen(chatID int64, url string, seen []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs, err := s.read()
+	if err != nil {
+		return err
+	}
+	for i, sub := range subs {
+		if sub.ChatID == chatID && sub.URL == url {
+			subs[i].Seen = trimSeen(seen)
+			return s.write(subs)
+		}
+	}
+	// The subscription was removed while its feed was being checked.
+	return nil
+}
+
+// trimSeen keeps the newest IDs, which come last.
+func trimSeen(seen []string) []string {
+	if len(seen) > maxSeen {
+		return seen[len(seen)-maxSeen:]
+	}
+	return seen
+}
+
+func (s *fileStore) read() ([]Subscription, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feeds: %w", err)
+	}
+
+	var subs []Subscription
+	if err := json.Unmarshal(data, &subs); err != nil {
+		return nil, fmt.Errorf("failed to parse feeds: %w", err)
+	}
+	return subs, nil
+}
+
+func (s *fileStore) write(subs []Subscription) error {
+	data, err := json.Marshal(subs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feeds: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write feeds: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package feeds
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeds.json")
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+
+	if err := s.Add(Subscription{ChatID: 10, UserID: 1, URL: "https://a.example/feed", Title: "A", Seen: []string{"a1"}}); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if err := s.Add(Subscription{ChatID: 10, UserID: 1, URL: "https://b.example/feed", Title: "B"}); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if err := s.Add(Subscription{ChatID: 10, UserID: 2, URL: "https://a.example/feed"}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("expected ErrDuplicate, got %v", err)
+	}
+	if err := s.Add(Subscription{ChatID: 20, UserID: 2, URL: "https://a.example/feed", Title: "A"}); err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+
+	reopened, _ := NewStore(path)
+	subs, err := reopened.List(10)
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+	if len(subs) != 2 || subs[0].Title != "A" || subs[0].CreatedAt.IsZero() {
+		t.Fatalf("unexpected subscriptions %+v", subs)
+	}
+
+	if err := reopened.SetSeen(10, "https://a.example/feed", []string{"a1", "a2"}); err != nil {
+		t.Fatalf("SetSeen() returned error: %v", err)
+	}
+	if subs, _ := reopened.List(10); len(subs[0].Seen) != 2 {
+		t.Errorf("expected seen items to be saved, got %+v", subs[0])
+	}
+
+	removed, err := reopened.Remove(10, 1)
+	if err != nil || removed.Title != "B" {
+		t.Fatalf("Remove() = %+v, %v", removed, err)
+	}
+	if _, err := reopened.Remove(10, 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if all, _ := reopened.All(); len(all) != 2 {
+		t.Errorf("expected 2 subscriptions left, got %+v", all)
+	}
+}
+
+func TestStore_Limit(t *testing.T) {
+	s, _ := NewStore(filepath.Join(t.TempDir(), "feeds.json"))
+	for i := 0; i < MaxPerUser; i++ {
+		if err := s.Add(Subscription{ChatID: int64(i % 2), UserID: 1, URL: fmt.Sprintf("https://example.com/%d", i)}); err != nil {
+			t.Fatalf("Add() returned error: %v", err)
+		}
+	}
+	if err := s.Add(Subscription{ChatID: 5, UserID: 1, URL: "https://example.com/more"}); !errors.Is(err, ErrLimit) {
+		t.Errorf("expected ErrLimit, got %v", err)
+	}
+	if err := s.Add(Subscription{ChatID: 5, UserID: 2, URL: "https://example.com/more"}); err != nil {
+		t.Errorf("expected other users to be unaffected, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package groups
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	ModePerUser = "per_user"
+	ModeShared  = "shared"
+)
+
+type Store interface {
+	Mode(chatID int64) string
+	SetMode(chatID int64, mode string) error
+}
+
+type fileStore struct {
+	path        string
+	defaultMode string
+	mu          sync.RWMutex
+	modes       map[int64]string
+}
+
+func NewStore(path, defaultMode string) (Store, error) {
+	if path == "" {
+		path = "./data/groups.json"
+	}
+	if defaultMode == "" {
+		defaultMode = ModePerUser
+	}
+	if !ValidMode(defaultMode) {
+		return nil, fmt.Errorf("invalid group mode %q", defaultMode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create groups directory: %w", err)
+	}
+
+	s := &fileStore{
+		path:        path,
+		defaultMode: defaultMode,
+		modes:       make(map[int64]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read groups: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &s.modes); err != nil {
+			return nil, fmt.Errorf("failed to parse groups: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+func ValidMode(mode string) bool {
+	return mode == ModePerUser || mode == ModeShared
+}
+
+func (s *fileStore) Mode(chatID int64) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if mode, ok := s.modes[chatID]; ok {
+		return mode
+	}
+	return s.defaultMode
+}
+
+func (s *fileStore) SetMode(chatID int64, mode string) error {
+	if !ValidMode(mode) {
+		return fmt.Errorf("invalid group mode %q", mode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.modes[chatID] = mode
+
+	data, err := json.Marshal(s.modes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal groups: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write groups: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package groups
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_DefaultAndPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "groups.json")
+	s, err := NewStore(path, "")
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+
+	if mode := s.Mode(-100); mode != ModePerUser {
+		t.Errorf("expected default per_user mode, got %q", mode)
+	}
+
+	if err := s.SetMode(-100, ModeShared); err != nil {
+		t.Fatalf("SetMode() returned error: %v", err)
+	}
+
+	reopened, err := NewStore(path, ModePerUser)
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+	if mode := reopened.Mode(-100); mode != ModeShared {
+		t.Errorf("expected persisted shared mode, got %q", mode)
+	}
+	if mode := reopened.Mode(-200); mode != ModePerUser {
+		t.Errorf("expected other groups to use default, got %q", mode)
+	}
+}
+
+func TestStore_InvalidMode(t *testing.T) {
+	if _, err := NewStore(filepath.Join(t.TempDir(), "groups.json"), "everyone"); err == nil {
+		t.Error("expected error for invalid default mode")
+	}
+
+	s, _ := NewStore(filepath.Join(t.TempDir(), "groups.json"), ModeShared)
+	if err := s.SetMode(-1, "everyone"); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
@@ -0,0 +1,162 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const (
+	probeTimeout = 10 * time.Second
+	cacheTTL     = 30 * time.Second
+)
+
+type Check func(ctx context.Context) error
+
+type Providers interface {
+	ProviderNames() []string
+	GetProviderByName(name string) (llm.Provider, error)
+}
+
+type Status struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Ready     bool              `json:"ready"`
+	Telegram  Status            `json:"telegram"`
+	Providers map[string]Status `json:"providers"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+type Server struct {
+	telegram  Check
+	providers Providers
+	now       func() time.Time
+
+	mu     sync.Mutex
+	last   *Report
+	lastAt time.Time
+}
+
+func NewServer(telegram Check, providers Providers) *Server {
+	return &Server{
+		telegram:  telegram,
+		providers: providers,
+		now:       time.Now,
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	mux.Handle("/debug/vars", loopbackOnly(expvar.Handler()))
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := s.Check(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+	return mux
+}
+
+// loopbackOnly serves next to requests from this machine only. The health
+// server usually listens on every interface for probes, and its variables
+// include the process's command line.
+func loopbackOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Health server listening on %s", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("health server failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) Check(ctx context.Context) Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.last != nil && s.now().Sub(s.lastAt) < cacheTTL {
+		return *s.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	report := Report{
+		Telegram:  probe(ctx, s.telegram),
+		Providers: make(map[string]Status),
+		CheckedAt: s.now(),
+	}
+
+	anyProvider := false
+	for _, name := range s.providers.ProviderNames() {
+		status := s.probeProvider(ctx, name)
+		report.Providers[name] = status
+		anyProvider = anyProvider || status.OK
+	}
+	report.Ready = report.Telegram.OK && anyProvider
+
+	s.last = &report
+	s.lastAt = report.CheckedAt
+	return report
+}
+
+func (s *Server) probeProvider(ctx context.Context, name string) Status {
+	provider, err := s.providers.GetProviderByName(name)
+	if err != nil {
+		return Status{Error: err.Error()}
+	}
+	pinger, ok := provider.(llm.Pinger)
+	if !ok {
+		return Status{OK: provider.IsEnabled()}
+	}
+	return probe(ctx, pinger.Ping)
+}
+
+func probe(ctx context.Context, check Check) Status {
+	if check == nil {
+		return Status{OK: true}
+	}
+	if err := check(ctx); err != nil {
+		return Status{Error: err.Error()}
+	}
+	return Status{OK: true}
+}
@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type fakeProvider struct {
+	name    string
+	pingErr error
+	pings   int
+}
+
+func (p *fakeProvider) Name() string    { return p.name }
+func (p *fakeProvider) IsEnabled() bool { return true }
+func (p *fakeProvider) SendMessage(ctx context.Context, messages []llm.Message) (string, error) {
+	return "", nil
+}
+func (p *fakeProvider) Ping(ctx context.Context) error {
+	p.pings++
+	return p.pingErr
+}
+
+type fakeProviders []*fakeProvider
+
+func (f fakeProviders) ProviderNames() []string {
+	var names []string
+	for _, p := range f {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+func (f fakeProviders) GetProviderByName(name string) (llm.Provider, error) {
+	for _, p := range f {
+		if p.name == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("provider %s not enabled", name)
+}
+
+func TestHealthz(t *testing.T) {
+	s := NewServer(nil, fakeProviders{})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestDebugVars_LoopbackOnly(t *testing.T) {
+	s := NewServer(nil, fakeProviders{})
+
+	for addr, want := range map[string]int{"127.0.0.1:5000": http.StatusOK, "[::1]:5000": http.StatusOK, "192.0.2.1:5000": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d", addr, want, rec.Code)
+		}
+	}
+}
+
+func TestReadyz_ReportsProviders(t *testing.T) {
+	providers := fakeProviders{
+		{name: "openai"},
+		{name: "anthropic", pingErr: errors.New("401 unauthorized")},
+	}
+	s := NewServer(func(ctx context.Context) error { return nil }, providers)
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with one reachable provider, got %d", rec.Code)
+	}
+
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !report.Providers["openai"].OK || report.Providers["anthropic"].OK {
+		t.Errorf("unexpected provider statuses: %+v", report.Providers)
+	}
+	if report.Providers["anthropic"].Error != "401 unauthorized" {
+		t.Errorf("expected provider error to be reported, got %q", report.Providers["anthropic"].Error)
+	}
+}
+
+func TestReadyz_TelegramDown(t *testing.T) {
+	s := NewServer(func(ctx context.Context) error { return errors.New("network unreachable") }, fakeProviders{{name: "openai"}})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rec.Code)
+	}
+}
+
+func TestCheck_CachesResults(t *testing.T) {
+	provider := &fakeProvider{name: "openai"}
+	s := NewServer(nil, fakeProviders{provider})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	s.Check(context.Background())
+	s.Check(context.Background())
+	if provider.pings != 1 {
+		t.Errorf("expected cached result, got %d pings", provider.pings)
+	}
+
+	now = now.Add(cacheTTL)
+	s.Check(context.Background())
+	if provider.pings != 2 {
+		t.Errorf("expected probe after cache expiry, got %d pings", provider.pings)
+	}
+}
@@ -0,0 +1,368 @@
+package i18n
+
+var de = map[string]string{
+	"start.welcome": "Willkommen bei Helpi! Ich helfe dir bei der Arbeit mit KI-Modellen.\n\nVerfügbare Befehle:\n/start - Diese Begrüßung anzeigen\n/help - Ausführliche Hilfe\n/myid - Deine Telegram-ID anzeigen\n/model - KI-Anbieter auswählen\n/switch - KI-Anbieter wechseln\n/prompt - System-Prompt festlegen\n/clear - Gesprächsverlauf löschen\n/new - Neues Gespräch beginnen\n/threads - Deine Gespräche anzeigen\n/translate - Eine Nachricht übersetzen\n/docs - Hochgeladene Dokumente verwalten\n/lang - Sprache des Bots ändern\n/feedback - Feedback zum Bot senden\n\nSchick mir einfach eine Nachricht und ich antworte mit dem konfigurierten KI-Anbieter.",
+
+	"help.text":            "Verfügbare Befehle:",
+	"help.custom_commands": "Eigene Befehle:",
+	"myid.text":            "Deine Telegram-ID: `%d`",
+
+	"help.admin_only":     "Dieser Befehl ist nur für Admins verfügbar.",
+	"help.owner_only":     "Dieser Befehl ist nur für Eigentümer verfügbar.",
+	"help.users_only":     "Gäste können diesen Befehl nicht verwenden.",
+	"cmd.start":           "Begrüßung",
+	"cmd.help":            "Diese Hilfe anzeigen",
+	"cmd.myid":            "Deine Telegram-Benutzer-ID anzeigen",
+	"cmd.whoami":          "Anzeigen, wer du für den Bot bist",
+	"cmd.status":          "Bot-Version, Laufzeit und deinen Anbieter anzeigen",
+	"cmd.stats":           "Deine Nutzungsstatistik anzeigen",
+	"cmd.model":           "Anbieter anzeigen und per Tastatur auswählen",
+	"cmd.models":          "Modelle deines Anbieters anzeigen",
+	"cmd.switch":          "KI-Anbieter wechseln (/switch default zum Zurücksetzen)",
+	"cmd.switch.args":     "<anbieter>",
+	"cmd.prompt":          "Eigenen System-Prompt festlegen (/prompt clear zum Entfernen)",
+	"cmd.prompt.args":     "<text>",
+	"cmd.persona":         "Wähle eine Persona für diesen Chat",
+	"cmd.persona.args":    "<name>",
+	"cmd.clear":           "Gesprächsverlauf löschen",
+	"cmd.pin":             "Auf eine Nachricht antworten, um sie dauerhaft im Gespräch zu behalten",
+	"cmd.pins":            "Deine angehefteten Nachrichten auflisten",
+	"cmd.unpin":           "Auf eine angeheftete Nachricht antworten oder ihre Nummer aus /pins angeben, um sie zu lösen",
+	"cmd.unpin.args":      "[Nummer]",
+	"cmd.regenerate":      "Letzte Antwort neu erzeugen",
+	"cmd.regenerate.args": "[temperatur]",
+	"cmd.cancel":          "Die laufende Antwort abbrechen",
+	"cmd.new":             "Neuen Gesprächsfaden beginnen",
+	"cmd.new.args":        "<titel>",
+	"cmd.threads":         "Deine Gesprächsfäden anzeigen",
+	"cmd.resume":          "Zu einem anderen Faden wechseln",
+	"cmd.resume.args":     "<nummer>",
+	"cmd.import":          "Ein exportiertes Gespräch als JSON- oder Markdown-Datei hinzufügen",
+	"cmd.import.args":     "[new]",
+	"cmd.translate":       "Auf eine Nachricht antworten, um sie zu übersetzen (oder /translate <sprache> <text>)",
+	"cmd.translate.args":  "<sprache>",
+	"cmd.docs":            "Hochgeladene Dokumente anzeigen (/docs clear zum Entfernen)",
+	"cmd.remember":        "Dir eine Tatsache über dich in jedem Gespräch merken",
+	"cmd.remember.args":   "<Tatsache>",
+	"cmd.memories":        "Auflisten, was sich der Bot über dich merkt",
+	"cmd.forget":          "Eine gemerkte Tatsache vergessen (/forget all entfernt alle)",
+	"cmd.forget.args":     "<Nummer>",
+	"cmd.watch":           "Neue Einträge eines RSS- oder Atom-Feeds hier posten oder beobachtete Feeds auflisten",
+	"cmd.watch.args":      "[Feed-URL]",
+	"cmd.unwatch":         "Einen Feed nicht mehr beobachten",
+	"cmd.unwatch.args":    "<Nummer>",
+	"cmd.lang":            "Sprache des Bots ändern (/lang default zum Zurücksetzen)",
+	"cmd.lang.args":       "<code>",
+	"cmd.feedback":        "Feedback zum Bot senden",
+	"cmd.feedback.args":   "<text>",
+	"cmd.digest":          "Erhalte eine tägliche oder wöchentliche Zusammenfassung deiner Unterhaltungen (/digest off zum Beenden)",
+	"cmd.digest.args":     "daily|weekly",
+	"cmd.quiet":           "Zusammenfassungen, Feed-Einträge und geplante Nachrichten während der Ruhezeit zurückhalten (/quiet off zum Beenden)",
+	"cmd.quiet.args":      "HH:MM-HH:MM",
+	"cmd.groupmode":       "Festlegen, ob eine Gruppe ein gemeinsames Gespräch führt (Gruppenadmins)",
+	"cmd.groupmode.args":  "shared|per_user",
+	"cmd.settings":        "Sprache, Anbieter, Temperatur und Persona über ein Menü ändern",
+	"cmd.ha":              "Einen Befehl an Home Assistant senden",
+	"cmd.ha.args":         "<Befehl>",
+	"cmd.feedbacks":       "Feedback der Nutzer ansehen",
+	"cmd.admin":           "Werkzeuge für Betreiber (Admins)",
+	"cmd.admin.args":      "providers|storage|stats",
+	"help.footer": `So funktioniert es:
+- Schick mir eine beliebige Nachricht und ich leite sie an die KI weiter
+- Dein Gesprächsverlauf bleibt zwischen Nachrichten erhalten
+- Mit /clear beginnst du ein neues Gespräch`,
+
+	"clear.done":  "Gesprächsverlauf gelöscht.",
+	"clear.error": "Fehler beim Löschen der Sitzung: %v",
+
+	"cancel.done": "Antwort abgebrochen.",
+	"cancel.none": "Es gibt nichts abzubrechen.",
+
+	"chat.provider_rate_limited": "Der KI-Anbieter erhält zu viele Anfragen. Bitte versuche es in einer Minute erneut.",
+	"chat.provider_auth":         "Der KI-Anbieter hat die Zugangsdaten des Bots abgelehnt. Bitte gib dem Bot-Admin Bescheid.",
+	"chat.context_length":        "Dieses Gespräch ist zu lang für das Modell. Mit /clear oder /new beginnst du ein neues.",
+	"chat.provider_error":        "Der KI-Anbieter hat gerade Probleme. Bitte versuche es später erneut.",
+
+	"chat.history_error": "Fehler beim Laden des Gesprächsverlaufs",
+	"chat.error":         "Fehler bei der Kommunikation mit der KI",
+	"chat.no_provider":   "Kein KI-Anbieter aktiviert. Bitte prüfe die Konfiguration.",
+	"chat.timeout":       "Zeitüberschreitung bei der Anfrage. Bitte versuche es erneut.",
+	"chat.no_vision":     "Der aktive Anbieter unterstützt keine Bilder. Wähle mit /switch einen, der das kann.",
+	"chat.queued":        "Derzeit ist kein KI-Anbieter erreichbar. Deine Nachricht wurde in die Warteschlange gestellt und wird beantwortet, sobald der Dienst wieder verfügbar ist.",
+	"chat.empty":         "Leere Antwort von der KI",
+	"chat.queued_answer": "Antwort auf deine Nachricht aus der Warteschlange:\n\n%s",
+	"chat.rate_limited":  "Du sendest Nachrichten zu schnell. Bitte warte %d Sekunden und versuche es erneut.",
+	"chat.image_error":   "Fehler beim Herunterladen des Bildes",
+
+	"switch.status":     "Aktiver Anbieter: %s\nVerfügbare Anbieter: %s\n\nVerwendung: /switch <anbieter> (oder /switch default)",
+	"switch.failed":     "Wechsel zu %s nicht möglich. Verfügbare Anbieter: %s",
+	"switch.default":    "Zurück zum Standardanbieter gewechselt.",
+	"switch.done":       "Zu %s gewechselt.",
+	"model.none":        "Fehler: Kein KI-Anbieter aktiviert",
+	"model.unavailable": "Dieser Anbieter ist nicht mehr verfügbar.",
+	"model.use_default": "Standard verwenden",
+	"model.picker":      "Aktiver Anbieter: %s\nTippe auf einen Anbieter, um zu wechseln.",
+
+	"models.list":        "Verfügbare Modelle bei %s:\n\n%s",
+	"models.empty":       "Bei %s sind keine Modelle installiert.",
+	"models.error":       "Die Modellliste von %s konnte nicht abgerufen werden.",
+	"models.unsupported": "%s kann seine Modelle nicht auflisten.",
+
+	"stats.text":        "Deine Statistik:\n\nNachrichten: %d\nVerbrauchte Tokens: %d\nLieblingsanbieter: %s\nDurchschnittliche Antwortzeit: %s\nAktuelles Gespräch: %d Nachrichten (~%d Tokens)",
+	"stats.none":        "Noch keine Statistik. Schick mir zuerst eine Nachricht.",
+	"stats.error":       "Fehler beim Laden der Statistik",
+	"stats.unavailable": "Mit diesem Speicher-Backend ist keine Statistik verfügbar.",
+
+	"prompt.load_error": "Fehler beim Laden des System-Prompts: %v",
+	"prompt.none":       "Kein eigener System-Prompt festgelegt.\n\nVerwendung: /prompt <text> zum Festlegen, /prompt clear zum Entfernen",
+	"prompt.current":    "Aktueller System-Prompt:\n\n%s",
+	"prompt.too_long":   "Der System-Prompt ist zu lang (maximal %d Zeichen).",
+	"prompt.save_error": "Fehler beim Speichern des System-Prompts",
+	"prompt.cleared":    "System-Prompt entfernt.",
+	"prompt.saved":      "System-Prompt gespeichert. Er wird für deine nächsten Nachrichten verwendet.",
+
+	"threads.new_error":    "Fehler beim Beginnen eines neuen Gesprächs",
+	"threads.started":      "Gespräch %d begonnen: %s",
+	"threads.load_error":   "Fehler beim Laden der Gespräche: %v",
+	"threads.header":       "Deine Gespräche:",
+	"threads.footer":       "Mit /resume <nummer> wechseln oder mit /new <titel> ein weiteres beginnen.",
+	"threads.resume_usage": "Verwendung: /resume <nummer> (siehe /threads)",
+	"threads.not_found":    "Gespräch %d nicht gefunden. Mit /threads siehst du deine Gespräche.",
+	"threads.resumed":      "Gespräch %d fortgesetzt: %s",
+
+	"translate.usage": "Verwendung: auf eine Nachricht mit /translate <sprache> antworten oder /translate <sprache> <text> senden",
+	"translate.error": "Fehler beim Übersetzen der Nachricht",
+
+	"docs.disabled":       "Das Hochladen von Dokumenten ist nicht aktiviert.",
+	"docs.unsupported":    "Nicht unterstützter Dateityp. Sende eine PDF-, TXT- oder Markdown-Datei.",
+	"docs.too_large":      "Die Datei ist zu groß (maximal %d MB).",
+	"docs.download_error": "Fehler beim Herunterladen des Dokuments",
+	"docs.no_text":        "In diesem Dokument wurde kein Text gefunden. Gescannte PDFs werden nicht unterstützt.",
+	"docs.read_error":     "Fehler beim Lesen des Dokuments",
+	"docs.save_error":     "Fehler beim Speichern des Dokuments",
+	"docs.saved":          "%s gespeichert (%d Abschnitte). Frag mich alles dazu. Mit /docs verwaltest du deine Dokumente.",
+	"docs.delete_error":   "Fehler beim Löschen der Dokumente: %v",
+	"docs.cleared":        "Alle deine Dokumente wurden entfernt.",
+	"docs.load_error":     "Fehler beim Laden der Dokumente: %v",
+	"docs.empty":          "Du hast keine Dokumente. Schick mir eine PDF-, TXT- oder Markdown-Datei, um eins hinzuzufügen.",
+	"docs.header":         "Deine Dokumente:",
+	"docs.entry":          "%d. %s (%d Abschnitte)",
+	"docs.footer":         "Mit /docs clear entfernst du sie.",
+
+	"groups.only_groups":  "Dieser Befehl funktioniert nur in Gruppen.",
+	"groups.disabled":     "Gruppenmodi sind nicht aktiviert.",
+	"groups.status":       "Gesprächsmodus: %s\n\nVerwendung: /groupmode shared|per_user",
+	"groups.unknown_mode": "Unbekannter Modus. Verwende /groupmode shared oder /groupmode per_user",
+	"groups.admins_only":  "Nur Gruppenadmins können den Gesprächsmodus ändern.",
+	"groups.save_error":   "Fehler beim Speichern des Gesprächsmodus",
+	"groups.shared":       "Gesprächsmodus auf shared gesetzt. Die ganze Gruppe teilt sich jetzt ein Gespräch.",
+	"groups.per_user":     "Gesprächsmodus auf per_user gesetzt. Jedes Mitglied hat jetzt ein eigenes Gespräch.",
+
+	"feedback.disabled":           "Feedback ist nicht aktiviert.",
+	"feedback.usage":              "Verwendung: /feedback <text>",
+	"feedback.save_error":         "Fehler beim Speichern des Feedbacks",
+	"feedback.thanks":             "Danke für dein Feedback! (#%d)",
+	"feedback.report":             "Schlechte Antwort melden",
+	"feedback.report_error":       "Fehler beim Speichern der Meldung.",
+	"feedback.reported":           "Danke, die Antwort wurde gemeldet.",
+	"feedback.rated":              "Danke für deine Bewertung!",
+	"feedback.admins_only":        "Nur Admins können Feedback einsehen.",
+	"feedback.load_error":         "Fehler beim Laden des Feedbacks: %v",
+	"feedback.export_unsupported": "Export wird nicht unterstützt.",
+	"feedback.export_error":       "Fehler beim Exportieren des Feedbacks: %v",
+	"feedback.export_caption":     "%d Feedback-Einträge",
+	"feedback.list_empty":         "Noch kein Feedback.",
+	"feedback.list_header":        "Feedback (%d insgesamt, die neuesten %d):",
+	"feedback.list_entry":         "#%d [%s] Nutzer %d: %s",
+	"feedback.list_bad_answer":    "Schlechte Antwort: %s",
+	"feedback.list_footer":        "Mit /feedbacks export lädst du alle Einträge herunter.",
+	"callback.unknown":            "Unbekannte Aktion.",
+
+	"access.approved":              "Dein Zugang wurde freigegeben. Sende /start, um zu beginnen.",
+	"access.denied":                "Deine Zugriffsanfrage wurde abgelehnt.",
+	"access.attempt":               "Unberechtigter Zugriffsversuch",
+	"access.attempt_user_id":       "Nutzer-ID: %d",
+	"access.attempt_username":      "Nutzername: @%s",
+	"access.attempt_name":          "Name: %s",
+	"access.attempt_first_message": "Erste Nachricht: %s",
+	"access.attempt_count":         "Versuche: %d",
+	"access.approve":               "Nutzer zulassen",
+	"access.deny":                  "Ablehnen",
+	"access.admins_only":           "Nur Admins können Zugriffsanfragen beantworten.",
+	"access.invalid_user":          "Ungültige Nutzer-ID.",
+	"access.user_approved":         "Nutzer zugelassen.",
+	"access.user_denied":           "Nutzer abgelehnt.",
+	"access.save_error":            "Nutzer für diese Sitzung zugelassen, aber das Speichern ist fehlgeschlagen.",
+	"access.open":                  "Alle können den Bot bereits nutzen; schränke den Zugriff zuerst in der Konfiguration ein.",
+	"access.outcome_approved":      "✅ Zugelassen von %d",
+	"access.outcome_denied":        "❌ Abgelehnt von %d",
+	"auth.denied":                  "Zugriff verweigert. Du bist nicht berechtigt, diesen Bot zu nutzen.",
+
+	"regenerate.usage":   "Verwendung: /regenerate [temperatur], Temperatur zwischen 0 und 2",
+	"regenerate.nothing": "Es gibt noch keine Antwort, die neu erzeugt werden kann.",
+
+	"continue.button":  "Weiter ▶️",
+	"continue.nothing": "Es gibt keine Antwort, die fortgesetzt werden kann.",
+
+	"chat.route":      "Beantwortet von %s",
+	"chat.route_rule": "Beantwortet von %s (Regel: %s)",
+	"chat.offline":    "📴 Die Cloud-Anbieter sind gerade nicht erreichbar, deshalb hat ein lokales Modell geantwortet.",
+	"chat.reasoning":  "Zusammenfassung der Überlegungen:\n\n%s",
+	"chat.thinking":   "💭 Denke nach…",
+	"chat.thought":    "💭 %s nachgedacht.",
+
+	"budget.user_exceeded":   "Du hast dein Token-Budget für heute aufgebraucht. Bitte versuche es morgen wieder.",
+	"budget.global_exceeded": "Der Bot hat sein Token-Budget für heute erreicht. Bitte versuche es morgen wieder.",
+	"budget.user_warning":    "Hinweis: Du hast 80% deines Token-Budgets für heute verbraucht.",
+	"budget.global_warning":  "Hinweis: Der Bot hat 80% seines Token-Budgets für heute verbraucht.",
+
+	"lang.status":     "Aktuelle Sprache: %s\nVerfügbare Sprachen: %s\n\nVerwendung: /lang <code> (oder /lang default)",
+	"lang.unknown":    "Unbekannte Sprache %q. Verfügbare Sprachen: %s",
+	"lang.set":        "Sprache auf %s umgestellt.",
+	"lang.reset":      "Sprache auf den Standard zurückgesetzt.",
+	"lang.save_error": "Fehler beim Speichern der Sprache",
+
+	"persona.disabled":   "Es sind keine Personas konfiguriert.",
+	"persona.picker":     "Persona: %s\nTippe auf eine Persona, um sie in diesem Chat zu verwenden.",
+	"persona.default":    "Standard",
+	"persona.unknown":    "Unbekannte Persona %q. Verfügbare Personas: %s",
+	"persona.set":        "Persona auf %s gesetzt.",
+	"persona.reset":      "Persona auf den Standard zurückgesetzt.",
+	"persona.save_error": "Fehler beim Speichern der Persona",
+
+	"settings.title":               "⚙️ Einstellungen",
+	"settings.language":            "Sprache: %s",
+	"settings.provider":            "Anbieter: %s",
+	"settings.persona":             "Persona: %s",
+	"settings.temperature":         "Temperatur: %s",
+	"settings.temperature_default": "Standard des Anbieters",
+	"settings.open_language":       "🌐 Sprache",
+	"settings.open_provider":       "🤖 Anbieter",
+	"settings.open_persona":        "🎭 Persona",
+	"settings.open_temperature":    "🌡 Temperatur",
+	"settings.back":                "« Zurück",
+	"settings.default":             "Standard",
+	"settings.language_page":       "Sprache: %s\nTippe auf eine Sprache, um sie zu verwenden.",
+	"settings.temperature_page":    "Temperatur: %s\nNiedrige Werte geben fokussiertere Antworten, hohe Werte kreativere.",
+	"settings.temperature_set":     "Temperatur auf %s gesetzt.",
+	"settings.temperature_reset":   "Temperatur auf den Standard des Anbieters zurückgesetzt.",
+	"settings.save_error":          "Fehler beim Speichern der Einstellung",
+
+	"digest.disabled":      "Zusammenfassungen sind nicht aktiviert.",
+	"digest.status":        "Zusammenfassung: %s\n\nVerwendung: /digest daily|weekly|off",
+	"digest.usage":         "Verwendung: /digest daily|weekly|off",
+	"digest.on.daily":      "Du erhältst jeden Tag eine Zusammenfassung deiner Unterhaltungen.",
+	"digest.on.weekly":     "Du erhältst jede Woche eine Zusammenfassung deiner Unterhaltungen.",
+	"digest.off":           "Zusammenfassungen deaktiviert.",
+	"digest.save_error":    "Fehler beim Speichern der Einstellung",
+	"digest.header.daily":  "Deine tägliche Zusammenfassung:",
+	"digest.header.weekly": "Deine wöchentliche Zusammenfassung:",
+
+	"quiet.disabled":   "Ruhezeiten sind nicht aktiviert.",
+	"quiet.none":       "Keine Ruhezeit festgelegt.\n\nVerwendung: /quiet 22:00-07:00|off",
+	"quiet.status":     "Ruhezeit: %s (Serverzeit)\n\nVerwendung: /quiet 22:00-07:00|off",
+	"quiet.usage":      "Verwendung: /quiet 22:00-07:00|off",
+	"quiet.set":        "Ruhezeit auf %s (Serverzeit) gesetzt. Zusammenfassungen, Feed-Einträge und geplante Nachrichten warten bis zu ihrem Ende.",
+	"quiet.off":        "Ruhezeit deaktiviert.",
+	"quiet.save_error": "Fehler beim Speichern der Ruhezeit",
+
+	"ha.disabled": "Home Assistant ist nicht verbunden.",
+	"ha.usage":    "Verwendung: /ha <Befehl>, zum Beispiel /ha schalte das Licht aus",
+	"ha.sent":     "An Home Assistant gesendet.",
+	"ha.error":    "Fehler beim Senden des Befehls an Home Assistant",
+
+	"admin.usage":                 "Verwendung: /admin providers|storage|stats",
+	"admin.providers.unsupported": "Anbieterprüfungen sind nicht verfügbar.",
+	"admin.providers.checking":    "Prüfe Anbieter...",
+	"admin.providers.none":        "Kein LLM-Anbieter ist aktiviert. Prüfe den Abschnitt providers in config.yaml und die API-Schlüssel.",
+	"admin.providers.header":      "Anbieter:",
+	"admin.providers.up":          "✅ %s (%s): erreichbar, %s",
+	"admin.providers.down":        "❌ %s (%s): nicht erreichbar: %v",
+	"admin.providers.last_error":  "   letzter Fehler vor %s: %s",
+	"admin.storage.unsupported":   "Speicherberichte sind nicht verfügbar.",
+	"admin.storage.error":         "Fehler beim Lesen der Speichernutzung",
+	"admin.storage.total":         "Speicher: %s belegt, Kontingent %s",
+	"admin.storage.user_quota":    "Kontingent pro Nutzer: %s",
+	"admin.storage.unlimited":     "keines",
+	"admin.storage.header":        "Größte Nutzer:",
+	"admin.storage.more":          "...und %d weitere",
+	"admin.stats.disabled":        "Antwortbewertungen sind aus. Setze feedback.enabled und feedback.buttons.",
+	"admin.stats.error":           "Fehler beim Lesen der Antwortbewertungen",
+	"admin.stats.none":            "Es wurden noch keine Antworten bewertet.",
+	"admin.stats.ratings":         "Antwortbewertungen: 👍 %d, 👎 %d (%d%% positiv)",
+
+	"status.text": "Helpi %s\n\nLaufzeit: %s\nAnbieter: %s (%s)\nSpeicher: %s\nDein Gespräch: %d Nachrichten (~%d Tokens)\nBeantwortet seit dem Start: %d, fehlgeschlagen: %d",
+
+	"whoami.text":       "Benutzer-ID: %d\nBenutzername: %s\nRolle: %s\nSprache: %s",
+	"whoami.role.admin": "Admin",
+	"whoami.role.user":  "Benutzer",
+	"whoami.role.owner": "Eigentümer",
+	"whoami.role.guest": "Gast",
+
+	"update.available": "Eine neue helpi-Version ist verfügbar: %s (läuft: %s)\n%s",
+
+	"pin.usage":     "Antworte mit /pin auf eine Nachricht, um sie dauerhaft im Gespräch zu behalten.",
+	"pin.not_found": "Diese Nachricht ist nicht in deinem aktuellen Gespräch.",
+	"pin.already":   "Diese Nachricht ist bereits angeheftet.",
+	"pin.done":      "Angeheftet. Diese Nachricht bleibt im Gespräch, bis du /unpin verwendest.",
+	"pin.error":     "Fehler beim Aktualisieren der angehefteten Nachrichten",
+
+	"pins.none":   "Keine angehefteten Nachrichten. Antworte mit /pin auf eine Nachricht, um sie anzuheften.",
+	"pins.header": "Angeheftete Nachrichten:",
+	"pins.item":   "%d. %s: %s",
+	"pins.you":    "Du",
+	"pins.bot":    "Bot",
+
+	"unpin.usage":      "Antworte mit /unpin auf eine angeheftete Nachricht oder verwende /unpin <Nummer> aus /pins.",
+	"unpin.unknown":    "Keine angeheftete Nachricht mit dieser Nummer. Siehe /pins.",
+	"unpin.not_pinned": "Diese Nachricht ist nicht angeheftet.",
+	"unpin.done":       "Gelöst.",
+
+	"remember.disabled":  "Erinnerungen sind nicht aktiviert.",
+	"remember.usage":     "Verwendung: /remember <Tatsache>, zum Beispiel /remember Ich bin Vegetarier",
+	"remember.done":      "Verstanden. Ich behalte das in jedem Gespräch im Kopf.",
+	"remember.duplicate": "Das merke ich mir bereits.",
+	"remember.limit":     "Du kannst höchstens %d Erinnerungen speichern. Entferne zuerst einige mit /forget.",
+	"remember.too_long":  "Das ist zu lang zum Merken. Bleib unter %d Zeichen.",
+	"remember.error":     "Fehler beim Aktualisieren der Erinnerungen",
+
+	"memories.none":    "Ich habe mir noch nichts über dich gemerkt. Verwende /remember <Tatsache>, um etwas hinzuzufügen.",
+	"memories.header":  "Was ich mir über dich merke:",
+	"memories.learned": "(gelernt)",
+	"memories.footer":  "Verwende /forget <Nummer>, um eine zu entfernen.",
+
+	"forget.usage":   "Verwendung: /forget <Nummer> (siehe /memories) oder /forget all",
+	"forget.unknown": "Keine Erinnerung mit dieser Nummer. Siehe /memories.",
+	"forget.done":    "Vergessen: %s",
+	"forget.all":     "Alle Erinnerungen entfernt.",
+
+	"watch.disabled":    "Feed-Beobachtung ist nicht aktiviert.",
+	"watch.invalid_url": "Sende eine Feed-Adresse, die mit http:// oder https:// beginnt.",
+	"watch.fetch_error": "Unter dieser Adresse konnte kein RSS- oder Atom-Feed gelesen werden.",
+	"watch.done":        "%s wird beobachtet. Neue Einträge werden hier gepostet.",
+	"watch.duplicate":   "Dieser Chat beobachtet diesen Feed bereits.",
+	"watch.limit":       "Du kannst höchstens %d Feeds beobachten. Entferne zuerst einen mit /unwatch.",
+	"watch.error":       "Fehler beim Aktualisieren der beobachteten Feeds",
+	"watch.none":        "In diesem Chat werden keine Feeds beobachtet. Verwende /watch <Feed-URL>, um einen hinzuzufügen.",
+	"watch.header":      "In diesem Chat beobachtete Feeds:",
+	"watch.footer":      "Verwende /unwatch <Nummer>, um einen nicht mehr zu beobachten.",
+
+	"unwatch.usage":   "Verwendung: /unwatch <Nummer> (siehe /watch)",
+	"unwatch.unknown": "Kein Feed mit dieser Nummer. Siehe /watch.",
+	"unwatch.done":    "%s wird nicht mehr beobachtet.",
+
+	"import.usage":          "Sende ein exportiertes Gespräch als JSON- oder Markdown-Datei mit /import als Beschriftung oder antworte auf die Datei mit /import. Mit \"new\" wird es in einen neuen Faden importiert.",
+	"import.too_large":      "Die Datei ist zu groß zum Importieren (max. %d MB).",
+	"import.download_error": "Fehler beim Herunterladen der Datei",
+	"import.invalid":        "Die Datei konnte nicht importiert werden: %v",
+	"import.error":          "Fehler beim Speichern des importierten Gesprächs",
+	"import.done":           "%d Nachrichten in das aktuelle Gespräch importiert.",
+	"import.thread":         "%d Nachrichten in Gespräch %d importiert: %s",
+
+	"command.route.args":      "<text>",
+	"command.route.usage":     "Verwendung: /%s <text>",
+	"command.route.ask":       "%s fragen",
+	"command.route.ask_model": "%s fragen (%s)",
+	"scheduled.incomplete":    "Der geplante Prompt %q konnte nicht abgeschlossen werden.",
+	"scheduled.failed":        "Der geplante Prompt %q ist fehlgeschlagen.",
+}
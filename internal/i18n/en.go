@@ -0,0 +1,368 @@
+package i18n
+
+var en = map[string]string{
+	"start.welcome": "Welcome to Helpi! I'm here to help you interact with AI models.\n\nAvailable commands:\n/start - Show this welcome message\n/help - Get detailed help\n/myid - Get your Telegram ID\n/model - Pick your AI provider\n/switch - Change your AI provider\n/prompt - Set your system prompt\n/clear - Clear your conversation history\n/new - Start a new conversation\n/threads - List your conversations\n/translate - Translate a message\n/docs - Manage your uploaded documents\n/lang - Change the bot language\n/feedback - Send feedback about the bot\n\nJust send me a message and I'll respond using the configured AI provider.",
+
+	"help.text":            "Available commands:",
+	"help.custom_commands": "Custom commands:",
+	"myid.text":            "Your Telegram ID: `%d`",
+
+	"help.admin_only":     "This command is only available to admins.",
+	"help.owner_only":     "This command is only available to owners.",
+	"help.users_only":     "Guests can't use this command.",
+	"cmd.start":           "Welcome message",
+	"cmd.help":            "Show this help message",
+	"cmd.myid":            "Get your Telegram user ID",
+	"cmd.whoami":          "Show who the bot thinks you are",
+	"cmd.status":          "Show the bot version, uptime and your provider",
+	"cmd.stats":           "Show your usage statistics",
+	"cmd.model":           "Show providers and pick one from a keyboard",
+	"cmd.models":          "List the models your provider offers",
+	"cmd.switch":          "Change your AI provider (/switch default to reset)",
+	"cmd.switch.args":     "<provider>",
+	"cmd.prompt":          "Set a custom system prompt (/prompt clear to remove it)",
+	"cmd.prompt.args":     "<text>",
+	"cmd.persona":         "Pick a persona for this chat",
+	"cmd.persona.args":    "<name>",
+	"cmd.clear":           "Clear your conversation history",
+	"cmd.pin":             "Reply to a message to keep it in the conversation for good",
+	"cmd.pins":            "List your pinned messages",
+	"cmd.unpin":           "Reply to a pinned message, or give its number from /pins, to unpin it",
+	"cmd.unpin.args":      "[number]",
+	"cmd.regenerate":      "Retry the last answer",
+	"cmd.regenerate.args": "[temperature]",
+	"cmd.cancel":          "Stop the answer being generated",
+	"cmd.new":             "Start a new conversation thread",
+	"cmd.new.args":        "<title>",
+	"cmd.threads":         "List your conversation threads",
+	"cmd.resume":          "Switch to another thread",
+	"cmd.resume.args":     "<number>",
+	"cmd.import":          "Add an exported conversation sent as a JSON or Markdown file",
+	"cmd.import.args":     "[new]",
+	"cmd.translate":       "Reply to a message to translate it (or /translate <lang> <text>)",
+	"cmd.translate.args":  "<lang>",
+	"cmd.docs":            "List your uploaded documents (/docs clear to remove them)",
+	"cmd.remember":        "Remember a fact about you in every conversation",
+	"cmd.remember.args":   "<fact>",
+	"cmd.memories":        "List what the bot remembers about you",
+	"cmd.forget":          "Forget a remembered fact (/forget all to remove them all)",
+	"cmd.forget.args":     "<number>",
+	"cmd.watch":           "Post new items of an RSS or Atom feed here, or list watched feeds",
+	"cmd.watch.args":      "[feed URL]",
+	"cmd.unwatch":         "Stop watching a feed",
+	"cmd.unwatch.args":    "<number>",
+	"cmd.lang":            "Change the bot language (/lang default to reset)",
+	"cmd.lang.args":       "<code>",
+	"cmd.feedback":        "Send feedback about the bot",
+	"cmd.feedback.args":   "<text>",
+	"cmd.digest":          "Get a daily or weekly digest of your conversations (/digest off to stop)",
+	"cmd.digest.args":     "daily|weekly",
+	"cmd.quiet":           "Hold digests, feed items and scheduled messages during quiet hours (/quiet off to stop)",
+	"cmd.quiet.args":      "HH:MM-HH:MM",
+	"cmd.groupmode":       "Choose whether a group shares one conversation (group admins)",
+	"cmd.groupmode.args":  "shared|per_user",
+	"cmd.settings":        "Change your language, provider, temperature and persona from a menu",
+	"cmd.ha":              "Send a command to Home Assistant",
+	"cmd.ha.args":         "<command>",
+	"cmd.feedbacks":       "Review user feedback",
+	"cmd.admin":           "Operator tools (admins)",
+	"cmd.admin.args":      "providers|storage|stats",
+	"help.footer": `How it works:
+- Send me any message and I'll forward it to the AI
+- Your conversation history is preserved between messages
+- Use /clear to start a fresh conversation`,
+
+	"clear.done":  "Conversation history cleared.",
+	"clear.error": "Error clearing session: %v",
+
+	"cancel.done": "Stopped generating the answer.",
+	"cancel.none": "Nothing to cancel.",
+
+	"chat.provider_rate_limited": "The AI provider is receiving too many requests. Please try again in a minute.",
+	"chat.provider_auth":         "The AI provider rejected the bot's credentials. Please let the bot admin know.",
+	"chat.context_length":        "This conversation is too long for the model. Use /clear or /new to start a fresh one.",
+	"chat.provider_error":        "The AI provider is having problems right now. Please try again later.",
+
+	"chat.history_error": "Error loading conversation history",
+	"chat.error":         "Error communicating with AI",
+	"chat.no_provider":   "No LLM provider enabled. Please check configuration.",
+	"chat.timeout":       "Request timed out. Please try again.",
+	"chat.no_vision":     "The active provider does not support images. Use /switch to choose one that does.",
+	"chat.queued":        "All AI providers are currently unavailable. Your message has been queued and will be answered when service recovers.",
+	"chat.empty":         "Empty response from AI",
+	"chat.queued_answer": "Answer to your queued message:\n\n%s",
+	"chat.rate_limited":  "You're sending messages too quickly. Please wait %d seconds and try again.",
+	"chat.image_error":   "Error downloading image",
+
+	"switch.status":     "Active provider: %s\nAvailable providers: %s\n\nUsage: /switch <provider> (or /switch default)",
+	"switch.failed":     "Cannot switch to %s. Available providers: %s",
+	"switch.default":    "Switched back to the default provider.",
+	"switch.done":       "Switched to %s.",
+	"model.none":        "Error: No LLM provider enabled",
+	"model.unavailable": "That provider is no longer available.",
+	"model.use_default": "Use default",
+	"model.picker":      "Active provider: %s\nTap a provider to switch.",
+
+	"models.list":        "Models available from %s:\n\n%s",
+	"models.empty":       "%s has no models installed.",
+	"models.error":       "Could not fetch the model list from %s.",
+	"models.unsupported": "%s cannot list its models.",
+
+	"stats.text":        "Your statistics:\n\nMessages: %d\nTokens used: %d\nFavorite provider: %s\nAverage response time: %s\nCurrent conversation: %d messages (~%d tokens)",
+	"stats.none":        "No statistics yet. Send me a message first.",
+	"stats.error":       "Error loading statistics",
+	"stats.unavailable": "Statistics are not available with this memory backend.",
+
+	"prompt.load_error": "Error loading system prompt: %v",
+	"prompt.none":       "No custom system prompt set.\n\nUsage: /prompt <text> to set one, /prompt clear to remove it",
+	"prompt.current":    "Current system prompt:\n\n%s",
+	"prompt.too_long":   "System prompt is too long (max %d characters).",
+	"prompt.save_error": "Error saving system prompt",
+	"prompt.cleared":    "System prompt cleared.",
+	"prompt.saved":      "System prompt saved. It will be used for your future messages.",
+
+	"threads.new_error":    "Error starting a new conversation",
+	"threads.started":      "Started conversation %d: %s",
+	"threads.load_error":   "Error loading conversations: %v",
+	"threads.header":       "Your conversations:",
+	"threads.footer":       "Use /resume <number> to switch or /new <title> to start another.",
+	"threads.resume_usage": "Usage: /resume <number> (see /threads)",
+	"threads.not_found":    "Conversation %d not found. Use /threads to list your conversations.",
+	"threads.resumed":      "Resumed conversation %d: %s",
+
+	"translate.usage": "Usage: reply to a message with /translate <language>, or send /translate <language> <text>",
+	"translate.error": "Error translating message",
+
+	"docs.disabled":       "Document uploads are not enabled.",
+	"docs.unsupported":    "Unsupported file type. Send a PDF, TXT or Markdown file.",
+	"docs.too_large":      "File is too large (max %d MB).",
+	"docs.download_error": "Error downloading document",
+	"docs.no_text":        "I couldn't find any text in that document. Scanned PDFs are not supported.",
+	"docs.read_error":     "Error reading document",
+	"docs.save_error":     "Error saving document",
+	"docs.saved":          "Saved %s (%d sections). Ask me anything about it. Use /docs to manage your documents.",
+	"docs.delete_error":   "Error deleting documents: %v",
+	"docs.cleared":        "All your documents have been removed.",
+	"docs.load_error":     "Error loading documents: %v",
+	"docs.empty":          "You have no documents. Send me a PDF, TXT or Markdown file to add one.",
+	"docs.header":         "Your documents:",
+	"docs.entry":          "%d. %s (%d sections)",
+	"docs.footer":         "Use /docs clear to remove them.",
+
+	"groups.only_groups":  "This command only works in groups.",
+	"groups.disabled":     "Group modes are not enabled.",
+	"groups.status":       "Conversation mode: %s\n\nUsage: /groupmode shared|per_user",
+	"groups.unknown_mode": "Unknown mode. Use /groupmode shared or /groupmode per_user",
+	"groups.admins_only":  "Only group admins can change the conversation mode.",
+	"groups.save_error":   "Error saving conversation mode",
+	"groups.shared":       "Conversation mode set to shared. Everyone in this group now shares one conversation.",
+	"groups.per_user":     "Conversation mode set to per_user. Each member now has their own conversation.",
+
+	"feedback.disabled":           "Feedback is not enabled.",
+	"feedback.usage":              "Usage: /feedback <text>",
+	"feedback.save_error":         "Error saving feedback",
+	"feedback.thanks":             "Thanks for your feedback! (#%d)",
+	"feedback.report":             "Report bad answer",
+	"feedback.report_error":       "Error saving report.",
+	"feedback.reported":           "Thanks, the answer has been reported.",
+	"feedback.rated":              "Thanks for rating this answer!",
+	"feedback.admins_only":        "Only admins can review feedback.",
+	"feedback.load_error":         "Error loading feedback: %v",
+	"feedback.export_unsupported": "Export is not supported.",
+	"feedback.export_error":       "Error exporting feedback: %v",
+	"feedback.export_caption":     "%d feedback entries",
+	"feedback.list_empty":         "No feedback yet.",
+	"feedback.list_header":        "Feedback (%d total, showing latest %d):",
+	"feedback.list_entry":         "#%d [%s] user %d: %s",
+	"feedback.list_bad_answer":    "Bad answer: %s",
+	"feedback.list_footer":        "Use /feedbacks export to download all entries.",
+	"callback.unknown":            "Unknown action.",
+
+	"access.approved":              "Your access has been approved. Send /start to begin.",
+	"access.denied":                "Your access request was declined.",
+	"access.attempt":               "Unauthorized access attempt",
+	"access.attempt_user_id":       "User ID: %d",
+	"access.attempt_username":      "Username: @%s",
+	"access.attempt_name":          "Name: %s",
+	"access.attempt_first_message": "First message: %s",
+	"access.attempt_count":         "Attempts: %d",
+	"access.approve":               "Approve user",
+	"access.deny":                  "Deny",
+	"access.admins_only":           "Only admins can answer access requests.",
+	"access.invalid_user":          "Invalid user ID.",
+	"access.user_approved":         "User approved.",
+	"access.user_denied":           "User denied.",
+	"access.save_error":            "User approved for this session, but saving failed.",
+	"access.open":                  "Everyone can use the bot already; restrict access in the config first.",
+	"access.outcome_approved":      "✅ Approved by %d",
+	"access.outcome_denied":        "❌ Denied by %d",
+	"auth.denied":                  "Access denied. You are not authorized to use this bot.",
+
+	"regenerate.usage":   "Usage: /regenerate [temperature], where temperature is between 0 and 2",
+	"regenerate.nothing": "There is no answer to regenerate yet.",
+
+	"continue.button":  "Continue ▶️",
+	"continue.nothing": "There is no answer to continue.",
+
+	"chat.route":      "Answered by %s",
+	"chat.route_rule": "Answered by %s (rule: %s)",
+	"chat.offline":    "📴 The cloud providers can't be reached right now, so a local model answered.",
+	"chat.reasoning":  "Reasoning summary:\n\n%s",
+	"chat.thinking":   "💭 Thinking…",
+	"chat.thought":    "💭 Thought for %s.",
+
+	"budget.user_exceeded":   "You have used your token budget for today. Please try again tomorrow.",
+	"budget.global_exceeded": "The bot has reached its token budget for today. Please try again tomorrow.",
+	"budget.user_warning":    "Heads up: you have used 80% of your token budget for today.",
+	"budget.global_warning":  "Heads up: the bot has used 80% of its token budget for today.",
+
+	"lang.status":     "Current language: %s\nAvailable languages: %s\n\nUsage: /lang <code> (or /lang default)",
+	"lang.unknown":    "Unknown language %q. Available languages: %s",
+	"lang.set":        "Language set to %s.",
+	"lang.reset":      "Language reset to the default.",
+	"lang.save_error": "Error saving language",
+
+	"persona.disabled":   "No personas are configured.",
+	"persona.picker":     "Persona: %s\nTap a persona to use it in this chat.",
+	"persona.default":    "Default",
+	"persona.unknown":    "Unknown persona %q. Available personas: %s",
+	"persona.set":        "Persona set to %s.",
+	"persona.reset":      "Persona reset to the default.",
+	"persona.save_error": "Error saving persona",
+
+	"settings.title":               "⚙️ Settings",
+	"settings.language":            "Language: %s",
+	"settings.provider":            "Provider: %s",
+	"settings.persona":             "Persona: %s",
+	"settings.temperature":         "Temperature: %s",
+	"settings.temperature_default": "provider default",
+	"settings.open_language":       "🌐 Language",
+	"settings.open_provider":       "🤖 Provider",
+	"settings.open_persona":        "🎭 Persona",
+	"settings.open_temperature":    "🌡 Temperature",
+	"settings.back":                "« Back",
+	"settings.default":             "Default",
+	"settings.language_page":       "Language: %s\nTap a language to use it.",
+	"settings.temperature_page":    "Temperature: %s\nLower values give more focused answers, higher values more creative ones.",
+	"settings.temperature_set":     "Temperature set to %s.",
+	"settings.temperature_reset":   "Temperature reset to the provider default.",
+	"settings.save_error":          "Error saving the setting",
+
+	"digest.disabled":      "Digests are not enabled.",
+	"digest.status":        "Digest: %s\n\nUsage: /digest daily|weekly|off",
+	"digest.usage":         "Usage: /digest daily|weekly|off",
+	"digest.on.daily":      "You will get a digest of your conversations every day.",
+	"digest.on.weekly":     "You will get a digest of your conversations every week.",
+	"digest.off":           "Digests turned off.",
+	"digest.save_error":    "Error saving digest setting",
+	"digest.header.daily":  "Your daily digest:",
+	"digest.header.weekly": "Your weekly digest:",
+
+	"quiet.disabled":   "Quiet hours are not enabled.",
+	"quiet.none":       "No quiet hours are set.\n\nUsage: /quiet 22:00-07:00|off",
+	"quiet.status":     "Quiet hours: %s (server time)\n\nUsage: /quiet 22:00-07:00|off",
+	"quiet.usage":      "Usage: /quiet 22:00-07:00|off",
+	"quiet.set":        "Quiet hours set to %s (server time). Digests, feed items and scheduled messages will wait until they end.",
+	"quiet.off":        "Quiet hours turned off.",
+	"quiet.save_error": "Error saving quiet hours",
+
+	"ha.disabled": "Home Assistant is not connected.",
+	"ha.usage":    "Usage: /ha <command>, for example /ha turn off the lights",
+	"ha.sent":     "Sent to Home Assistant.",
+	"ha.error":    "Error sending the command to Home Assistant",
+
+	"admin.usage":                 "Usage: /admin providers|storage|stats",
+	"admin.providers.unsupported": "Provider checks are not available.",
+	"admin.providers.checking":    "Checking providers...",
+	"admin.providers.none":        "No LLM provider is enabled. Check the providers section of config.yaml and the API keys.",
+	"admin.providers.header":      "Providers:",
+	"admin.providers.up":          "✅ %s (%s): up, %s",
+	"admin.providers.down":        "❌ %s (%s): down: %v",
+	"admin.providers.last_error":  "   last error %s ago: %s",
+	"admin.storage.unsupported":   "Storage reporting is not available.",
+	"admin.storage.error":         "Error reading storage usage",
+	"admin.storage.total":         "Storage: %s used, quota %s",
+	"admin.storage.user_quota":    "Per-user quota: %s",
+	"admin.storage.unlimited":     "none",
+	"admin.storage.header":        "Largest users:",
+	"admin.storage.more":          "...and %d more",
+	"admin.stats.disabled":        "Answer ratings are off. Set feedback.enabled and feedback.buttons.",
+	"admin.stats.error":           "Error reading answer ratings",
+	"admin.stats.none":            "No answers have been rated yet.",
+	"admin.stats.ratings":         "Answer ratings: 👍 %d, 👎 %d (%d%% positive)",
+
+	"status.text": "Helpi %s\n\nUptime: %s\nProvider: %s (%s)\nMemory: %s\nYour conversation: %d messages (~%d tokens)\nAnswered since start: %d, failed: %d",
+
+	"whoami.text":       "User ID: %d\nUsername: %s\nRole: %s\nLanguage: %s",
+	"whoami.role.admin": "admin",
+	"whoami.role.user":  "user",
+	"whoami.role.owner": "owner",
+	"whoami.role.guest": "guest",
+
+	"update.available": "A new helpi release is available: %s (running %s)\n%s",
+
+	"pin.usage":     "Reply to a message with /pin to keep it in the conversation for good.",
+	"pin.not_found": "That message is not in your current conversation.",
+	"pin.already":   "That message is already pinned.",
+	"pin.done":      "Pinned. This message stays in the conversation until you /unpin it.",
+	"pin.error":     "Error updating pinned messages",
+
+	"pins.none":   "No pinned messages. Reply to a message with /pin to pin it.",
+	"pins.header": "Pinned messages:",
+	"pins.item":   "%d. %s: %s",
+	"pins.you":    "You",
+	"pins.bot":    "Bot",
+
+	"unpin.usage":      "Reply to a pinned message with /unpin, or use /unpin <number> from /pins.",
+	"unpin.unknown":    "No pinned message with that number. See /pins.",
+	"unpin.not_pinned": "That message is not pinned.",
+	"unpin.done":       "Unpinned.",
+
+	"remember.disabled":  "Memories are not enabled.",
+	"remember.usage":     "Usage: /remember <fact>, for example /remember I am vegetarian",
+	"remember.done":      "Got it. I will keep that in mind in every conversation.",
+	"remember.duplicate": "I already remember that.",
+	"remember.limit":     "You can keep at most %d memories. Remove some with /forget first.",
+	"remember.too_long":  "That is too long to remember. Keep it under %d characters.",
+	"remember.error":     "Error updating memories",
+
+	"memories.none":    "I don't remember anything about you yet. Use /remember <fact> to add something.",
+	"memories.header":  "What I remember about you:",
+	"memories.learned": "(learned)",
+	"memories.footer":  "Use /forget <number> to remove one.",
+
+	"forget.usage":   "Usage: /forget <number> (see /memories) or /forget all",
+	"forget.unknown": "No memory with that number. See /memories.",
+	"forget.done":    "Forgotten: %s",
+	"forget.all":     "All memories removed.",
+
+	"watch.disabled":    "Feed watching is not enabled.",
+	"watch.invalid_url": "Send a feed address starting with http:// or https://.",
+	"watch.fetch_error": "Could not read an RSS or Atom feed at that address.",
+	"watch.done":        "Watching %s. New items will be posted here.",
+	"watch.duplicate":   "This chat already watches that feed.",
+	"watch.limit":       "You can watch at most %d feeds. Remove one with /unwatch first.",
+	"watch.error":       "Error updating watched feeds",
+	"watch.none":        "No feeds are watched in this chat. Use /watch <feed URL> to add one.",
+	"watch.header":      "Feeds watched in this chat:",
+	"watch.footer":      "Use /unwatch <number> to stop watching one.",
+
+	"unwatch.usage":   "Usage: /unwatch <number> (see /watch)",
+	"unwatch.unknown": "No feed with that number. See /watch.",
+	"unwatch.done":    "Stopped watching %s.",
+
+	"import.usage":          "Send an exported conversation as a JSON or Markdown file with /import as its caption, or reply to the file with /import. Add \"new\" to import it into a new thread.",
+	"import.too_large":      "File is too large to import (max %d MB).",
+	"import.download_error": "Error downloading the file",
+	"import.invalid":        "Could not import that file: %v",
+	"import.error":          "Error saving the imported conversation",
+	"import.done":           "Imported %d messages into the current conversation.",
+	"import.thread":         "Imported %d messages into conversation %d: %s",
+
+	"command.route.args":      "<text>",
+	"command.route.usage":     "Usage: /%s <text>",
+	"command.route.ask":       "Ask %s",
+	"command.route.ask_model": "Ask %s (%s)",
+	"scheduled.incomplete":    "Scheduled prompt %q could not be completed.",
+	"scheduled.failed":        "Scheduled prompt %q failed.",
+}
@@ -0,0 +1,368 @@
+package i18n
+
+var es = map[string]string{
+	"start.welcome": "¡Bienvenido a Helpi! Estoy aquí para ayudarte a usar modelos de IA.\n\nComandos disponibles:\n/start - Mostrar este mensaje de bienvenida\n/help - Obtener ayuda detallada\n/myid - Obtener tu ID de Telegram\n/model - Elegir tu proveedor de IA\n/switch - Cambiar tu proveedor de IA\n/prompt - Definir tu prompt de sistema\n/clear - Borrar tu historial de conversación\n/new - Empezar una nueva conversación\n/threads - Ver tus conversaciones\n/translate - Traducir un mensaje\n/docs - Gestionar tus documentos\n/lang - Cambiar el idioma del bot\n/feedback - Enviar comentarios sobre el bot\n\nEnvíame un mensaje y te responderé con el proveedor de IA configurado.",
+
+	"help.text":            "Comandos disponibles:",
+	"help.custom_commands": "Comandos personalizados:",
+	"myid.text":            "Tu ID de Telegram: `%d`",
+
+	"help.admin_only":     "Este comando solo está disponible para administradores.",
+	"help.owner_only":     "Este comando solo está disponible para propietarios.",
+	"help.users_only":     "Los invitados no pueden usar este comando.",
+	"cmd.start":           "Mensaje de bienvenida",
+	"cmd.help":            "Mostrar esta ayuda",
+	"cmd.myid":            "Obtener tu ID de usuario de Telegram",
+	"cmd.whoami":          "Mostrar quién cree el bot que eres",
+	"cmd.status":          "Mostrar la versión del bot, el tiempo activo y tu proveedor",
+	"cmd.stats":           "Ver tus estadísticas de uso",
+	"cmd.model":           "Ver los proveedores y elegir uno desde un teclado",
+	"cmd.models":          "Ver los modelos que ofrece tu proveedor",
+	"cmd.switch":          "Cambiar tu proveedor de IA (/switch default para restablecer)",
+	"cmd.switch.args":     "<proveedor>",
+	"cmd.prompt":          "Definir un prompt de sistema propio (/prompt clear para quitarlo)",
+	"cmd.prompt.args":     "<texto>",
+	"cmd.persona":         "Elige una personalidad para este chat",
+	"cmd.persona.args":    "<nombre>",
+	"cmd.clear":           "Borrar tu historial de conversación",
+	"cmd.pin":             "Responde a un mensaje para mantenerlo siempre en la conversación",
+	"cmd.pins":            "Listar tus mensajes fijados",
+	"cmd.unpin":           "Responde a un mensaje fijado, o indica su número de /pins, para desfijarlo",
+	"cmd.unpin.args":      "[número]",
+	"cmd.regenerate":      "Repetir la última respuesta",
+	"cmd.regenerate.args": "[temperatura]",
+	"cmd.cancel":          "Detener la respuesta en curso",
+	"cmd.new":             "Empezar un nuevo hilo de conversación",
+	"cmd.new.args":        "<título>",
+	"cmd.threads":         "Ver tus hilos de conversación",
+	"cmd.resume":          "Cambiar a otro hilo",
+	"cmd.resume.args":     "<número>",
+	"cmd.import":          "Añadir una conversación exportada enviada como archivo JSON o Markdown",
+	"cmd.import.args":     "[new]",
+	"cmd.translate":       "Responde a un mensaje para traducirlo (o /translate <idioma> <texto>)",
+	"cmd.translate.args":  "<idioma>",
+	"cmd.docs":            "Ver tus documentos subidos (/docs clear para borrarlos)",
+	"cmd.remember":        "Recordar un dato sobre ti en todas las conversaciones",
+	"cmd.remember.args":   "<dato>",
+	"cmd.memories":        "Listar lo que el bot recuerda de ti",
+	"cmd.forget":          "Olvidar un dato recordado (/forget all para borrarlos todos)",
+	"cmd.forget.args":     "<número>",
+	"cmd.watch":           "Publicar aquí las novedades de un feed RSS o Atom, o listar los feeds vigilados",
+	"cmd.watch.args":      "[URL del feed]",
+	"cmd.unwatch":         "Dejar de vigilar un feed",
+	"cmd.unwatch.args":    "<número>",
+	"cmd.lang":            "Cambiar el idioma del bot (/lang default para restablecer)",
+	"cmd.lang.args":       "<código>",
+	"cmd.feedback":        "Enviar comentarios sobre el bot",
+	"cmd.feedback.args":   "<texto>",
+	"cmd.digest":          "Recibe un resumen diario o semanal de tus conversaciones (/digest off para detenerlo)",
+	"cmd.digest.args":     "daily|weekly",
+	"cmd.quiet":           "Retener resúmenes, entradas de feeds y mensajes programados durante las horas de silencio (/quiet off para detenerlo)",
+	"cmd.quiet.args":      "HH:MM-HH:MM",
+	"cmd.groupmode":       "Elegir si un grupo comparte una sola conversación (administradores del grupo)",
+	"cmd.groupmode.args":  "shared|per_user",
+	"cmd.settings":        "Cambiar idioma, proveedor, temperatura y persona desde un menú",
+	"cmd.ha":              "Enviar un comando a Home Assistant",
+	"cmd.ha.args":         "<comando>",
+	"cmd.feedbacks":       "Revisar los comentarios de los usuarios",
+	"cmd.admin":           "Herramientas de operación (admins)",
+	"cmd.admin.args":      "providers|storage|stats",
+	"help.footer": `Cómo funciona:
+- Envíame cualquier mensaje y lo enviaré a la IA
+- Tu historial de conversación se conserva entre mensajes
+- Usa /clear para empezar una conversación nueva`,
+
+	"clear.done":  "Historial de conversación borrado.",
+	"clear.error": "Error al borrar la sesión: %v",
+
+	"cancel.done": "Se detuvo la respuesta.",
+	"cancel.none": "No hay nada que cancelar.",
+
+	"chat.provider_rate_limited": "El proveedor de IA está recibiendo demasiadas solicitudes. Inténtalo de nuevo en un minuto.",
+	"chat.provider_auth":         "El proveedor de IA rechazó las credenciales del bot. Avisa al administrador del bot.",
+	"chat.context_length":        "Esta conversación es demasiado larga para el modelo. Usa /clear o /new para empezar una nueva.",
+	"chat.provider_error":        "El proveedor de IA tiene problemas en este momento. Inténtalo más tarde.",
+
+	"chat.history_error": "Error al cargar el historial de conversación",
+	"chat.error":         "Error al comunicarse con la IA",
+	"chat.no_provider":   "No hay ningún proveedor de IA habilitado. Revisa la configuración.",
+	"chat.timeout":       "La solicitud tardó demasiado. Inténtalo de nuevo.",
+	"chat.no_vision":     "El proveedor activo no admite imágenes. Usa /switch para elegir uno que sí lo haga.",
+	"chat.queued":        "Ningún proveedor de IA está disponible en este momento. Tu mensaje se ha puesto en cola y se responderá cuando el servicio se recupere.",
+	"chat.empty":         "La IA devolvió una respuesta vacía",
+	"chat.queued_answer": "Respuesta a tu mensaje en cola:\n\n%s",
+	"chat.rate_limited":  "Estás enviando mensajes demasiado rápido. Espera %d segundos e inténtalo de nuevo.",
+	"chat.image_error":   "Error al descargar la imagen",
+
+	"switch.status":     "Proveedor activo: %s\nProveedores disponibles: %s\n\nUso: /switch <proveedor> (o /switch default)",
+	"switch.failed":     "No se puede cambiar a %s. Proveedores disponibles: %s",
+	"switch.default":    "Se ha vuelto al proveedor predeterminado.",
+	"switch.done":       "Cambiado a %s.",
+	"model.none":        "Error: no hay ningún proveedor de IA habilitado",
+	"model.unavailable": "Ese proveedor ya no está disponible.",
+	"model.use_default": "Usar predeterminado",
+	"model.picker":      "Proveedor activo: %s\nToca un proveedor para cambiar.",
+
+	"models.list":        "Modelos disponibles en %s:\n\n%s",
+	"models.empty":       "%s no tiene modelos instalados.",
+	"models.error":       "No se pudo obtener la lista de modelos de %s.",
+	"models.unsupported": "%s no puede listar sus modelos.",
+
+	"stats.text":        "Tus estadísticas:\n\nMensajes: %d\nTokens usados: %d\nProveedor favorito: %s\nTiempo medio de respuesta: %s\nConversación actual: %d mensajes (~%d tokens)",
+	"stats.none":        "Aún no hay estadísticas. Envíame un mensaje primero.",
+	"stats.error":       "Error al cargar las estadísticas",
+	"stats.unavailable": "Las estadísticas no están disponibles con este almacenamiento de memoria.",
+
+	"prompt.load_error": "Error al cargar el prompt de sistema: %v",
+	"prompt.none":       "No tienes un prompt de sistema propio.\n\nUso: /prompt <texto> para definirlo, /prompt clear para quitarlo",
+	"prompt.current":    "Prompt de sistema actual:\n\n%s",
+	"prompt.too_long":   "El prompt de sistema es demasiado largo (máximo %d caracteres).",
+	"prompt.save_error": "Error al guardar el prompt de sistema",
+	"prompt.cleared":    "Prompt de sistema eliminado.",
+	"prompt.saved":      "Prompt de sistema guardado. Se usará en tus próximos mensajes.",
+
+	"threads.new_error":    "Error al empezar una nueva conversación",
+	"threads.started":      "Conversación %d iniciada: %s",
+	"threads.load_error":   "Error al cargar las conversaciones: %v",
+	"threads.header":       "Tus conversaciones:",
+	"threads.footer":       "Usa /resume <número> para cambiar o /new <título> para empezar otra.",
+	"threads.resume_usage": "Uso: /resume <número> (ver /threads)",
+	"threads.not_found":    "No se encontró la conversación %d. Usa /threads para ver tus conversaciones.",
+	"threads.resumed":      "Conversación %d reanudada: %s",
+
+	"translate.usage": "Uso: responde a un mensaje con /translate <idioma>, o envía /translate <idioma> <texto>",
+	"translate.error": "Error al traducir el mensaje",
+
+	"docs.disabled":       "La subida de documentos no está habilitada.",
+	"docs.unsupported":    "Tipo de archivo no admitido. Envía un archivo PDF, TXT o Markdown.",
+	"docs.too_large":      "El archivo es demasiado grande (máximo %d MB).",
+	"docs.download_error": "Error al descargar el documento",
+	"docs.no_text":        "No encontré texto en ese documento. Los PDF escaneados no son compatibles.",
+	"docs.read_error":     "Error al leer el documento",
+	"docs.save_error":     "Error al guardar el documento",
+	"docs.saved":          "%s guardado (%d secciones). Pregúntame lo que quieras sobre él. Usa /docs para gestionar tus documentos.",
+	"docs.delete_error":   "Error al borrar los documentos: %v",
+	"docs.cleared":        "Se han eliminado todos tus documentos.",
+	"docs.load_error":     "Error al cargar los documentos: %v",
+	"docs.empty":          "No tienes documentos. Envíame un archivo PDF, TXT o Markdown para añadir uno.",
+	"docs.header":         "Tus documentos:",
+	"docs.entry":          "%d. %s (%d secciones)",
+	"docs.footer":         "Usa /docs clear para eliminarlos.",
+
+	"groups.only_groups":  "Este comando solo funciona en grupos.",
+	"groups.disabled":     "Los modos de grupo no están habilitados.",
+	"groups.status":       "Modo de conversación: %s\n\nUso: /groupmode shared|per_user",
+	"groups.unknown_mode": "Modo desconocido. Usa /groupmode shared o /groupmode per_user",
+	"groups.admins_only":  "Solo los administradores del grupo pueden cambiar el modo de conversación.",
+	"groups.save_error":   "Error al guardar el modo de conversación",
+	"groups.shared":       "Modo de conversación: shared. Todo el grupo comparte ahora una sola conversación.",
+	"groups.per_user":     "Modo de conversación: per_user. Cada miembro tiene ahora su propia conversación.",
+
+	"feedback.disabled":           "Los comentarios no están habilitados.",
+	"feedback.usage":              "Uso: /feedback <texto>",
+	"feedback.save_error":         "Error al guardar los comentarios",
+	"feedback.thanks":             "¡Gracias por tus comentarios! (#%d)",
+	"feedback.report":             "Reportar mala respuesta",
+	"feedback.report_error":       "Error al guardar el reporte.",
+	"feedback.reported":           "Gracias, la respuesta ha sido reportada.",
+	"feedback.rated":              "¡Gracias por valorar esta respuesta!",
+	"feedback.admins_only":        "Solo los administradores pueden revisar los comentarios.",
+	"feedback.load_error":         "Error al cargar los comentarios: %v",
+	"feedback.export_unsupported": "La exportación no está disponible.",
+	"feedback.export_error":       "Error al exportar los comentarios: %v",
+	"feedback.export_caption":     "%d comentarios",
+	"feedback.list_empty":         "Todavía no hay comentarios.",
+	"feedback.list_header":        "Comentarios (%d en total, los %d más recientes):",
+	"feedback.list_entry":         "#%d [%s] usuario %d: %s",
+	"feedback.list_bad_answer":    "Mala respuesta: %s",
+	"feedback.list_footer":        "Usa /feedbacks export para descargar todos los comentarios.",
+	"callback.unknown":            "Acción desconocida.",
+
+	"access.approved":              "Tu acceso ha sido aprobado. Envía /start para empezar.",
+	"access.denied":                "Tu solicitud de acceso fue rechazada.",
+	"access.attempt":               "Intento de acceso no autorizado",
+	"access.attempt_user_id":       "ID de usuario: %d",
+	"access.attempt_username":      "Usuario: @%s",
+	"access.attempt_name":          "Nombre: %s",
+	"access.attempt_first_message": "Primer mensaje: %s",
+	"access.attempt_count":         "Intentos: %d",
+	"access.approve":               "Aprobar usuario",
+	"access.deny":                  "Rechazar",
+	"access.admins_only":           "Solo los administradores pueden responder a las solicitudes de acceso.",
+	"access.invalid_user":          "ID de usuario no válido.",
+	"access.user_approved":         "Usuario aprobado.",
+	"access.user_denied":           "Usuario rechazado.",
+	"access.save_error":            "Usuario aprobado para esta sesión, pero no se pudo guardar.",
+	"access.open":                  "Todos pueden usar el bot ya; primero restringe el acceso en la configuración.",
+	"access.outcome_approved":      "✅ Aprobado por %d",
+	"access.outcome_denied":        "❌ Rechazado por %d",
+	"auth.denied":                  "Acceso denegado. No tienes autorización para usar este bot.",
+
+	"regenerate.usage":   "Uso: /regenerate [temperatura], con una temperatura entre 0 y 2",
+	"regenerate.nothing": "Todavía no hay ninguna respuesta para regenerar.",
+
+	"continue.button":  "Continuar ▶️",
+	"continue.nothing": "No hay ninguna respuesta que continuar.",
+
+	"chat.route":      "Respondido por %s",
+	"chat.route_rule": "Respondido por %s (regla: %s)",
+	"chat.offline":    "📴 Ahora mismo no se puede acceder a los proveedores en la nube, así que ha respondido un modelo local.",
+	"chat.reasoning":  "Resumen del razonamiento:\n\n%s",
+	"chat.thinking":   "💭 Pensando…",
+	"chat.thought":    "💭 Pensó durante %s.",
+
+	"budget.user_exceeded":   "Has agotado tu presupuesto de tokens de hoy. Inténtalo de nuevo mañana.",
+	"budget.global_exceeded": "El bot ha alcanzado su presupuesto de tokens de hoy. Inténtalo de nuevo mañana.",
+	"budget.user_warning":    "Aviso: has usado el 80% de tu presupuesto de tokens de hoy.",
+	"budget.global_warning":  "Aviso: el bot ha usado el 80% de su presupuesto de tokens de hoy.",
+
+	"lang.status":     "Idioma actual: %s\nIdiomas disponibles: %s\n\nUso: /lang <código> (o /lang default)",
+	"lang.unknown":    "Idioma desconocido %q. Idiomas disponibles: %s",
+	"lang.set":        "Idioma cambiado a %s.",
+	"lang.reset":      "Se ha restablecido el idioma predeterminado.",
+	"lang.save_error": "Error al guardar el idioma",
+
+	"persona.disabled":   "No hay personalidades configuradas.",
+	"persona.picker":     "Personalidad: %s\nToca una personalidad para usarla en este chat.",
+	"persona.default":    "Predeterminada",
+	"persona.unknown":    "Personalidad desconocida %q. Personalidades disponibles: %s",
+	"persona.set":        "Personalidad cambiada a %s.",
+	"persona.reset":      "Personalidad restablecida a la predeterminada.",
+	"persona.save_error": "Error al guardar la personalidad",
+
+	"settings.title":               "⚙️ Ajustes",
+	"settings.language":            "Idioma: %s",
+	"settings.provider":            "Proveedor: %s",
+	"settings.persona":             "Persona: %s",
+	"settings.temperature":         "Temperatura: %s",
+	"settings.temperature_default": "predeterminada del proveedor",
+	"settings.open_language":       "🌐 Idioma",
+	"settings.open_provider":       "🤖 Proveedor",
+	"settings.open_persona":        "🎭 Persona",
+	"settings.open_temperature":    "🌡 Temperatura",
+	"settings.back":                "« Volver",
+	"settings.default":             "Predeterminado",
+	"settings.language_page":       "Idioma: %s\nToca un idioma para usarlo.",
+	"settings.temperature_page":    "Temperatura: %s\nLos valores bajos dan respuestas más precisas; los altos, más creativas.",
+	"settings.temperature_set":     "Temperatura establecida en %s.",
+	"settings.temperature_reset":   "Temperatura restablecida a la predeterminada del proveedor.",
+	"settings.save_error":          "Error al guardar el ajuste",
+
+	"digest.disabled":      "Los resúmenes no están habilitados.",
+	"digest.status":        "Resumen: %s\n\nUso: /digest daily|weekly|off",
+	"digest.usage":         "Uso: /digest daily|weekly|off",
+	"digest.on.daily":      "Recibirás un resumen de tus conversaciones cada día.",
+	"digest.on.weekly":     "Recibirás un resumen de tus conversaciones cada semana.",
+	"digest.off":           "Resúmenes desactivados.",
+	"digest.save_error":    "Error al guardar la configuración del resumen",
+	"digest.header.daily":  "Tu resumen diario:",
+	"digest.header.weekly": "Tu resumen semanal:",
+
+	"quiet.disabled":   "Las horas de silencio no están habilitadas.",
+	"quiet.none":       "No hay horas de silencio configuradas.\n\nUso: /quiet 22:00-07:00|off",
+	"quiet.status":     "Horas de silencio: %s (hora del servidor)\n\nUso: /quiet 22:00-07:00|off",
+	"quiet.usage":      "Uso: /quiet 22:00-07:00|off",
+	"quiet.set":        "Horas de silencio configuradas a %s (hora del servidor). Los resúmenes, entradas de feeds y mensajes programados esperarán a que terminen.",
+	"quiet.off":        "Horas de silencio desactivadas.",
+	"quiet.save_error": "Error al guardar las horas de silencio",
+
+	"ha.disabled": "Home Assistant no está conectado.",
+	"ha.usage":    "Uso: /ha <comando>, por ejemplo /ha apaga las luces",
+	"ha.sent":     "Enviado a Home Assistant.",
+	"ha.error":    "Error al enviar el comando a Home Assistant",
+
+	"admin.usage":                 "Uso: /admin providers|storage|stats",
+	"admin.providers.unsupported": "La comprobación de proveedores no está disponible.",
+	"admin.providers.checking":    "Comprobando proveedores...",
+	"admin.providers.none":        "No hay ningún proveedor de LLM activado. Revisa la sección providers de config.yaml y las claves de API.",
+	"admin.providers.header":      "Proveedores:",
+	"admin.providers.up":          "✅ %s (%s): activo, %s",
+	"admin.providers.down":        "❌ %s (%s): caído: %v",
+	"admin.providers.last_error":  "   último error hace %s: %s",
+	"admin.storage.unsupported":   "El informe de almacenamiento no está disponible.",
+	"admin.storage.error":         "Error al leer el uso de almacenamiento",
+	"admin.storage.total":         "Almacenamiento: %s usados, cuota %s",
+	"admin.storage.user_quota":    "Cuota por usuario: %s",
+	"admin.storage.unlimited":     "ninguna",
+	"admin.storage.header":        "Usuarios con más datos:",
+	"admin.storage.more":          "...y %d más",
+	"admin.stats.disabled":        "Las valoraciones de respuestas están desactivadas. Configura feedback.enabled y feedback.buttons.",
+	"admin.stats.error":           "Error al leer las valoraciones de respuestas",
+	"admin.stats.none":            "Todavía no se ha valorado ninguna respuesta.",
+	"admin.stats.ratings":         "Valoraciones de respuestas: 👍 %d, 👎 %d (%d%% positivas)",
+
+	"status.text": "Helpi %s\n\nTiempo activo: %s\nProveedor: %s (%s)\nMemoria: %s\nTu conversación: %d mensajes (~%d tokens)\nRespondidas desde el inicio: %d, fallidas: %d",
+
+	"whoami.text":       "ID de usuario: %d\nNombre de usuario: %s\nRol: %s\nIdioma: %s",
+	"whoami.role.admin": "administrador",
+	"whoami.role.user":  "usuario",
+	"whoami.role.owner": "propietario",
+	"whoami.role.guest": "invitado",
+
+	"update.available": "Hay una nueva versión de helpi disponible: %s (en uso %s)\n%s",
+
+	"pin.usage":     "Responde a un mensaje con /pin para mantenerlo siempre en la conversación.",
+	"pin.not_found": "Ese mensaje no está en tu conversación actual.",
+	"pin.already":   "Ese mensaje ya está fijado.",
+	"pin.done":      "Fijado. Este mensaje se queda en la conversación hasta que uses /unpin.",
+	"pin.error":     "Error al actualizar los mensajes fijados",
+
+	"pins.none":   "No hay mensajes fijados. Responde a un mensaje con /pin para fijarlo.",
+	"pins.header": "Mensajes fijados:",
+	"pins.item":   "%d. %s: %s",
+	"pins.you":    "Tú",
+	"pins.bot":    "Bot",
+
+	"unpin.usage":      "Responde a un mensaje fijado con /unpin, o usa /unpin <número> de /pins.",
+	"unpin.unknown":    "No hay ningún mensaje fijado con ese número. Consulta /pins.",
+	"unpin.not_pinned": "Ese mensaje no está fijado.",
+	"unpin.done":       "Desfijado.",
+
+	"remember.disabled":  "Los recuerdos no están activados.",
+	"remember.usage":     "Uso: /remember <dato>, por ejemplo /remember soy vegetariano",
+	"remember.done":      "Entendido. Lo tendré en cuenta en todas las conversaciones.",
+	"remember.duplicate": "Ya recuerdo eso.",
+	"remember.limit":     "Puedes guardar como máximo %d recuerdos. Borra algunos con /forget primero.",
+	"remember.too_long":  "Es demasiado largo para recordarlo. Usa menos de %d caracteres.",
+	"remember.error":     "Error al actualizar los recuerdos",
+
+	"memories.none":    "Todavía no recuerdo nada sobre ti. Usa /remember <dato> para añadir algo.",
+	"memories.header":  "Lo que recuerdo de ti:",
+	"memories.learned": "(aprendido)",
+	"memories.footer":  "Usa /forget <número> para borrar uno.",
+
+	"forget.usage":   "Uso: /forget <número> (ver /memories) o /forget all",
+	"forget.unknown": "No hay ningún recuerdo con ese número. Consulta /memories.",
+	"forget.done":    "Olvidado: %s",
+	"forget.all":     "Todos los recuerdos borrados.",
+
+	"watch.disabled":    "La vigilancia de feeds no está activada.",
+	"watch.invalid_url": "Envía una dirección de feed que empiece por http:// o https://.",
+	"watch.fetch_error": "No se pudo leer un feed RSS o Atom en esa dirección.",
+	"watch.done":        "Vigilando %s. Las novedades se publicarán aquí.",
+	"watch.duplicate":   "Este chat ya vigila ese feed.",
+	"watch.limit":       "Puedes vigilar como máximo %d feeds. Quita uno con /unwatch primero.",
+	"watch.error":       "Error al actualizar los feeds vigilados",
+	"watch.none":        "No se vigila ningún feed en este chat. Usa /watch <URL del feed> para añadir uno.",
+	"watch.header":      "Feeds vigilados en este chat:",
+	"watch.footer":      "Usa /unwatch <número> para dejar de vigilar uno.",
+
+	"unwatch.usage":   "Uso: /unwatch <número> (ver /watch)",
+	"unwatch.unknown": "No hay ningún feed con ese número. Consulta /watch.",
+	"unwatch.done":    "Se dejó de vigilar %s.",
+
+	"import.usage":          "Envía una conversación exportada como archivo JSON o Markdown con /import como descripción, o responde al archivo con /import. Añade \"new\" para importarla en un hilo nuevo.",
+	"import.too_large":      "El archivo es demasiado grande para importarlo (máx. %d MB).",
+	"import.download_error": "Error al descargar el archivo",
+	"import.invalid":        "No se pudo importar ese archivo: %v",
+	"import.error":          "Error al guardar la conversación importada",
+	"import.done":           "Se importaron %d mensajes en la conversación actual.",
+	"import.thread":         "Se importaron %d mensajes en la conversación %d: %s",
+
+	"command.route.args":      "<texto>",
+	"command.route.usage":     "Uso: /%s <texto>",
+	"command.route.ask":       "Preguntar a %s",
+	"command.route.ask_model": "Preguntar a %s (%s)",
+	"scheduled.incomplete":    "No se pudo completar el prompt programado %q.",
+	"scheduled.failed":        "El prompt programado %q falló.",
+}
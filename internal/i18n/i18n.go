@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const DefaultLanguage = "en"
+
+var catalogs = map[string]map[string]string{
+	"en": en,
+	"es": es,
+	"de": de,
+	"pt": pt,
+}
+
+var names = map[string]string{
+	"en": "English",
+	"es": "Español",
+	"de": "Deutsch",
+	"pt": "Português",
+}
+
+// Normalize maps a Telegram language code such as "pt-br" to a supported
+// catalog, returning "" when there is none.
+func Normalize(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+	if _, ok := catalogs[code]; ok {
+		return code
+	}
+	return ""
+}
+
+func Supported() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+func Name(lang string) string {
+	if name, ok := names[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// T returns the message for key in lang, falling back to English and then to
+// the key itself. Args are applied with fmt.Sprintf.
+func T(lang, key string, args ...any) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = en[key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
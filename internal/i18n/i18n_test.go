@@ -0,0 +1,48 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	for lang, catalog := range catalogs {
+		for key, msg := range en {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing key %q", lang, key)
+				continue
+			}
+			if got, want := verbs.FindAllString(translated, -1), verbs.FindAllString(msg, -1); len(got) != len(want) {
+				t.Errorf("%s: key %q has verbs %v, want %v", lang, key, got, want)
+			}
+		}
+		for key := range catalog {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s: unknown key %q", lang, key)
+			}
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{"pt-br": "pt", "DE": "de", "es_MX": "es", "fr": "", "": ""}
+	for code, want := range tests {
+		if got := Normalize(code); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestT_FallsBackToEnglish(t *testing.T) {
+	if got := T("fr", "switch.done", "ollama"); got != "Switched to ollama." {
+		t.Errorf("unexpected fallback %q", got)
+	}
+	if got := T("de", "switch.done", "ollama"); got != "Zu ollama gewechselt." {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := T("en", "missing.key"); got != "missing.key" {
+		t.Errorf("expected key fallback, got %q", got)
+	}
+}
@@ -0,0 +1,368 @@
+package i18n
+
+var pt = map[string]string{
+	"start.welcome": "Bem-vindo ao Helpi! Estou aqui para ajudar você a usar modelos de IA.\n\nComandos disponíveis:\n/start - Mostrar esta mensagem de boas-vindas\n/help - Obter ajuda detalhada\n/myid - Obter seu ID do Telegram\n/model - Escolher seu provedor de IA\n/switch - Trocar seu provedor de IA\n/prompt - Definir seu prompt de sistema\n/clear - Apagar seu histórico de conversa\n/new - Começar uma nova conversa\n/threads - Ver suas conversas\n/translate - Traduzir uma mensagem\n/docs - Gerenciar seus documentos\n/lang - Mudar o idioma do bot\n/feedback - Enviar feedback sobre o bot\n\nÉ só me mandar uma mensagem que eu respondo usando o provedor de IA configurado.",
+
+	"help.text":            "Comandos disponíveis:",
+	"help.custom_commands": "Comandos personalizados:",
+	"myid.text":            "Seu ID do Telegram: `%d`",
+
+	"help.admin_only":     "Este comando está disponível apenas para administradores.",
+	"help.owner_only":     "Este comando está disponível apenas para proprietários.",
+	"help.users_only":     "Convidados não podem usar este comando.",
+	"cmd.start":           "Mensagem de boas-vindas",
+	"cmd.help":            "Mostrar esta ajuda",
+	"cmd.myid":            "Obter seu ID de usuário do Telegram",
+	"cmd.whoami":          "Mostrar quem o bot acha que você é",
+	"cmd.status":          "Mostrar a versão do bot, o tempo ativo e seu provedor",
+	"cmd.stats":           "Ver suas estatísticas de uso",
+	"cmd.model":           "Ver os provedores e escolher um pelo teclado",
+	"cmd.models":          "Ver os modelos que seu provedor oferece",
+	"cmd.switch":          "Trocar seu provedor de IA (/switch default para redefinir)",
+	"cmd.switch.args":     "<provedor>",
+	"cmd.prompt":          "Definir um prompt de sistema próprio (/prompt clear para remover)",
+	"cmd.prompt.args":     "<texto>",
+	"cmd.persona":         "Escolha uma persona para este chat",
+	"cmd.persona.args":    "<nome>",
+	"cmd.clear":           "Apagar seu histórico de conversa",
+	"cmd.pin":             "Responda a uma mensagem para mantê-la sempre na conversa",
+	"cmd.pins":            "Listar suas mensagens fixadas",
+	"cmd.unpin":           "Responda a uma mensagem fixada, ou informe o número dela em /pins, para desafixá-la",
+	"cmd.unpin.args":      "[número]",
+	"cmd.regenerate":      "Gerar a última resposta de novo",
+	"cmd.regenerate.args": "[temperatura]",
+	"cmd.cancel":          "Parar a resposta em andamento",
+	"cmd.new":             "Começar uma nova conversa",
+	"cmd.new.args":        "<título>",
+	"cmd.threads":         "Ver suas conversas",
+	"cmd.resume":          "Mudar para outra conversa",
+	"cmd.resume.args":     "<número>",
+	"cmd.import":          "Adicionar uma conversa exportada enviada como arquivo JSON ou Markdown",
+	"cmd.import.args":     "[new]",
+	"cmd.translate":       "Responda a uma mensagem para traduzi-la (ou /translate <idioma> <texto>)",
+	"cmd.translate.args":  "<idioma>",
+	"cmd.docs":            "Ver seus documentos enviados (/docs clear para removê-los)",
+	"cmd.remember":        "Lembrar um fato sobre você em todas as conversas",
+	"cmd.remember.args":   "<fato>",
+	"cmd.memories":        "Listar o que o bot lembra sobre você",
+	"cmd.forget":          "Esquecer um fato lembrado (/forget all para remover todos)",
+	"cmd.forget.args":     "<número>",
+	"cmd.watch":           "Publicar aqui as novidades de um feed RSS ou Atom, ou listar os feeds acompanhados",
+	"cmd.watch.args":      "[URL do feed]",
+	"cmd.unwatch":         "Deixar de acompanhar um feed",
+	"cmd.unwatch.args":    "<número>",
+	"cmd.lang":            "Mudar o idioma do bot (/lang default para redefinir)",
+	"cmd.lang.args":       "<código>",
+	"cmd.feedback":        "Enviar feedback sobre o bot",
+	"cmd.feedback.args":   "<texto>",
+	"cmd.digest":          "Receba um resumo diário ou semanal das suas conversas (/digest off para parar)",
+	"cmd.digest.args":     "daily|weekly",
+	"cmd.quiet":           "Reter resumos, itens de feeds e mensagens agendadas durante o horário de silêncio (/quiet off para parar)",
+	"cmd.quiet.args":      "HH:MM-HH:MM",
+	"cmd.groupmode":       "Escolher se um grupo compartilha uma única conversa (administradores do grupo)",
+	"cmd.groupmode.args":  "shared|per_user",
+	"cmd.settings":        "Alterar idioma, provedor, temperatura e persona por um menu",
+	"cmd.ha":              "Enviar um comando ao Home Assistant",
+	"cmd.ha.args":         "<comando>",
+	"cmd.feedbacks":       "Ver o feedback dos usuários",
+	"cmd.admin":           "Ferramentas de operação (admins)",
+	"cmd.admin.args":      "providers|storage|stats",
+	"help.footer": `Como funciona:
+- Mande qualquer mensagem e eu a encaminho para a IA
+- Seu histórico de conversa é mantido entre as mensagens
+- Use /clear para começar uma conversa do zero`,
+
+	"clear.done":  "Histórico de conversa apagado.",
+	"clear.error": "Erro ao apagar a sessão: %v",
+
+	"cancel.done": "Resposta interrompida.",
+	"cancel.none": "Não há nada para cancelar.",
+
+	"chat.provider_rate_limited": "O provedor de IA está recebendo solicitações demais. Tente de novo em um minuto.",
+	"chat.provider_auth":         "O provedor de IA recusou as credenciais do bot. Avise o administrador do bot.",
+	"chat.context_length":        "Esta conversa é longa demais para o modelo. Use /clear ou /new para começar uma nova.",
+	"chat.provider_error":        "O provedor de IA está com problemas no momento. Tente novamente mais tarde.",
+
+	"chat.history_error": "Erro ao carregar o histórico de conversa",
+	"chat.error":         "Erro ao se comunicar com a IA",
+	"chat.no_provider":   "Nenhum provedor de IA habilitado. Verifique a configuração.",
+	"chat.timeout":       "A solicitação demorou demais. Tente novamente.",
+	"chat.no_vision":     "O provedor ativo não aceita imagens. Use /switch para escolher um que aceite.",
+	"chat.queued":        "Nenhum provedor de IA está disponível no momento. Sua mensagem foi colocada na fila e será respondida quando o serviço voltar.",
+	"chat.empty":         "Resposta vazia da IA",
+	"chat.queued_answer": "Resposta à sua mensagem na fila:\n\n%s",
+	"chat.rate_limited":  "Você está enviando mensagens rápido demais. Aguarde %d segundos e tente novamente.",
+	"chat.image_error":   "Erro ao baixar a imagem",
+
+	"switch.status":     "Provedor ativo: %s\nProvedores disponíveis: %s\n\nUso: /switch <provedor> (ou /switch default)",
+	"switch.failed":     "Não foi possível trocar para %s. Provedores disponíveis: %s",
+	"switch.default":    "Voltou para o provedor padrão.",
+	"switch.done":       "Trocado para %s.",
+	"model.none":        "Erro: nenhum provedor de IA habilitado",
+	"model.unavailable": "Esse provedor não está mais disponível.",
+	"model.use_default": "Usar padrão",
+	"model.picker":      "Provedor ativo: %s\nToque em um provedor para trocar.",
+
+	"models.list":        "Modelos disponíveis em %s:\n\n%s",
+	"models.empty":       "%s não tem modelos instalados.",
+	"models.error":       "Não foi possível obter a lista de modelos de %s.",
+	"models.unsupported": "%s não consegue listar seus modelos.",
+
+	"stats.text":        "Suas estatísticas:\n\nMensagens: %d\nTokens usados: %d\nProvedor favorito: %s\nTempo médio de resposta: %s\nConversa atual: %d mensagens (~%d tokens)",
+	"stats.none":        "Ainda não há estatísticas. Mande uma mensagem primeiro.",
+	"stats.error":       "Erro ao carregar as estatísticas",
+	"stats.unavailable": "As estatísticas não estão disponíveis com este armazenamento de memória.",
+
+	"prompt.load_error": "Erro ao carregar o prompt de sistema: %v",
+	"prompt.none":       "Nenhum prompt de sistema próprio definido.\n\nUso: /prompt <texto> para definir, /prompt clear para remover",
+	"prompt.current":    "Prompt de sistema atual:\n\n%s",
+	"prompt.too_long":   "O prompt de sistema é longo demais (máximo de %d caracteres).",
+	"prompt.save_error": "Erro ao salvar o prompt de sistema",
+	"prompt.cleared":    "Prompt de sistema removido.",
+	"prompt.saved":      "Prompt de sistema salvo. Ele será usado nas suas próximas mensagens.",
+
+	"threads.new_error":    "Erro ao começar uma nova conversa",
+	"threads.started":      "Conversa %d iniciada: %s",
+	"threads.load_error":   "Erro ao carregar as conversas: %v",
+	"threads.header":       "Suas conversas:",
+	"threads.footer":       "Use /resume <número> para trocar ou /new <título> para começar outra.",
+	"threads.resume_usage": "Uso: /resume <número> (veja /threads)",
+	"threads.not_found":    "Conversa %d não encontrada. Use /threads para ver suas conversas.",
+	"threads.resumed":      "Conversa %d retomada: %s",
+
+	"translate.usage": "Uso: responda a uma mensagem com /translate <idioma>, ou envie /translate <idioma> <texto>",
+	"translate.error": "Erro ao traduzir a mensagem",
+
+	"docs.disabled":       "O envio de documentos não está habilitado.",
+	"docs.unsupported":    "Tipo de arquivo não suportado. Envie um arquivo PDF, TXT ou Markdown.",
+	"docs.too_large":      "O arquivo é grande demais (máximo de %d MB).",
+	"docs.download_error": "Erro ao baixar o documento",
+	"docs.no_text":        "Não encontrei texto nesse documento. PDFs digitalizados não são suportados.",
+	"docs.read_error":     "Erro ao ler o documento",
+	"docs.save_error":     "Erro ao salvar o documento",
+	"docs.saved":          "%s salvo (%d seções). Pergunte o que quiser sobre ele. Use /docs para gerenciar seus documentos.",
+	"docs.delete_error":   "Erro ao apagar os documentos: %v",
+	"docs.cleared":        "Todos os seus documentos foram removidos.",
+	"docs.load_error":     "Erro ao carregar os documentos: %v",
+	"docs.empty":          "Você não tem documentos. Envie um arquivo PDF, TXT ou Markdown para adicionar um.",
+	"docs.header":         "Seus documentos:",
+	"docs.entry":          "%d. %s (%d seções)",
+	"docs.footer":         "Use /docs clear para removê-los.",
+
+	"groups.only_groups":  "Este comando só funciona em grupos.",
+	"groups.disabled":     "Os modos de grupo não estão habilitados.",
+	"groups.status":       "Modo de conversa: %s\n\nUso: /groupmode shared|per_user",
+	"groups.unknown_mode": "Modo desconhecido. Use /groupmode shared ou /groupmode per_user",
+	"groups.admins_only":  "Só os administradores do grupo podem mudar o modo de conversa.",
+	"groups.save_error":   "Erro ao salvar o modo de conversa",
+	"groups.shared":       "Modo de conversa definido como shared. Todo o grupo agora compartilha uma única conversa.",
+	"groups.per_user":     "Modo de conversa definido como per_user. Cada membro agora tem sua própria conversa.",
+
+	"feedback.disabled":           "O feedback não está habilitado.",
+	"feedback.usage":              "Uso: /feedback <texto>",
+	"feedback.save_error":         "Erro ao salvar o feedback",
+	"feedback.thanks":             "Obrigado pelo seu feedback! (#%d)",
+	"feedback.report":             "Denunciar resposta ruim",
+	"feedback.report_error":       "Erro ao salvar a denúncia.",
+	"feedback.reported":           "Obrigado, a resposta foi denunciada.",
+	"feedback.rated":              "Obrigado por avaliar esta resposta!",
+	"feedback.admins_only":        "Apenas administradores podem revisar o feedback.",
+	"feedback.load_error":         "Erro ao carregar o feedback: %v",
+	"feedback.export_unsupported": "A exportação não é suportada.",
+	"feedback.export_error":       "Erro ao exportar o feedback: %v",
+	"feedback.export_caption":     "%d entradas de feedback",
+	"feedback.list_empty":         "Ainda não há feedback.",
+	"feedback.list_header":        "Feedback (%d no total, mostrando os %d mais recentes):",
+	"feedback.list_entry":         "#%d [%s] usuário %d: %s",
+	"feedback.list_bad_answer":    "Resposta ruim: %s",
+	"feedback.list_footer":        "Use /feedbacks export para baixar todas as entradas.",
+	"callback.unknown":            "Ação desconhecida.",
+
+	"access.approved":              "Seu acesso foi aprovado. Envie /start para começar.",
+	"access.denied":                "Seu pedido de acesso foi recusado.",
+	"access.attempt":               "Tentativa de acesso não autorizada",
+	"access.attempt_user_id":       "ID do usuário: %d",
+	"access.attempt_username":      "Usuário: @%s",
+	"access.attempt_name":          "Nome: %s",
+	"access.attempt_first_message": "Primeira mensagem: %s",
+	"access.attempt_count":         "Tentativas: %d",
+	"access.approve":               "Aprovar usuário",
+	"access.deny":                  "Recusar",
+	"access.admins_only":           "Apenas administradores podem responder a pedidos de acesso.",
+	"access.invalid_user":          "ID de usuário inválido.",
+	"access.user_approved":         "Usuário aprovado.",
+	"access.user_denied":           "Usuário recusado.",
+	"access.save_error":            "Usuário aprovado para esta sessão, mas não foi possível salvar.",
+	"access.open":                  "Todos já podem usar o bot; restrinja o acesso na configuração primeiro.",
+	"access.outcome_approved":      "✅ Aprovado por %d",
+	"access.outcome_denied":        "❌ Recusado por %d",
+	"auth.denied":                  "Acesso negado. Você não tem autorização para usar este bot.",
+
+	"regenerate.usage":   "Uso: /regenerate [temperatura], com temperatura entre 0 e 2",
+	"regenerate.nothing": "Ainda não há nenhuma resposta para gerar de novo.",
+
+	"continue.button":  "Continuar ▶️",
+	"continue.nothing": "Não há nenhuma resposta para continuar.",
+
+	"chat.route":      "Respondido por %s",
+	"chat.route_rule": "Respondido por %s (regra: %s)",
+	"chat.offline":    "📴 Os provedores na nuvem não estão acessíveis agora, então um modelo local respondeu.",
+	"chat.reasoning":  "Resumo do raciocínio:\n\n%s",
+	"chat.thinking":   "💭 Pensando…",
+	"chat.thought":    "💭 Pensou por %s.",
+
+	"budget.user_exceeded":   "Você esgotou seu orçamento de tokens de hoje. Tente novamente amanhã.",
+	"budget.global_exceeded": "O bot atingiu o orçamento de tokens de hoje. Tente novamente amanhã.",
+	"budget.user_warning":    "Atenção: você usou 80% do seu orçamento de tokens de hoje.",
+	"budget.global_warning":  "Atenção: o bot usou 80% do orçamento de tokens de hoje.",
+
+	"lang.status":     "Idioma atual: %s\nIdiomas disponíveis: %s\n\nUso: /lang <código> (ou /lang default)",
+	"lang.unknown":    "Idioma desconhecido %q. Idiomas disponíveis: %s",
+	"lang.set":        "Idioma alterado para %s.",
+	"lang.reset":      "Idioma redefinido para o padrão.",
+	"lang.save_error": "Erro ao salvar o idioma",
+
+	"persona.disabled":   "Nenhuma persona está configurada.",
+	"persona.picker":     "Persona: %s\nToque em uma persona para usá-la neste chat.",
+	"persona.default":    "Padrão",
+	"persona.unknown":    "Persona desconhecida %q. Personas disponíveis: %s",
+	"persona.set":        "Persona alterada para %s.",
+	"persona.reset":      "Persona redefinida para o padrão.",
+	"persona.save_error": "Erro ao salvar a persona",
+
+	"settings.title":               "⚙️ Configurações",
+	"settings.language":            "Idioma: %s",
+	"settings.provider":            "Provedor: %s",
+	"settings.persona":             "Persona: %s",
+	"settings.temperature":         "Temperatura: %s",
+	"settings.temperature_default": "padrão do provedor",
+	"settings.open_language":       "🌐 Idioma",
+	"settings.open_provider":       "🤖 Provedor",
+	"settings.open_persona":        "🎭 Persona",
+	"settings.open_temperature":    "🌡 Temperatura",
+	"settings.back":                "« Voltar",
+	"settings.default":             "Padrão",
+	"settings.language_page":       "Idioma: %s\nToque em um idioma para usá-lo.",
+	"settings.temperature_page":    "Temperatura: %s\nValores baixos dão respostas mais focadas; valores altos, mais criativas.",
+	"settings.temperature_set":     "Temperatura definida como %s.",
+	"settings.temperature_reset":   "Temperatura redefinida para o padrão do provedor.",
+	"settings.save_error":          "Erro ao salvar a configuração",
+
+	"digest.disabled":      "Os resumos não estão ativados.",
+	"digest.status":        "Resumo: %s\n\nUso: /digest daily|weekly|off",
+	"digest.usage":         "Uso: /digest daily|weekly|off",
+	"digest.on.daily":      "Você receberá um resumo das suas conversas todos os dias.",
+	"digest.on.weekly":     "Você receberá um resumo das suas conversas toda semana.",
+	"digest.off":           "Resumos desativados.",
+	"digest.save_error":    "Erro ao salvar a configuração do resumo",
+	"digest.header.daily":  "Seu resumo diário:",
+	"digest.header.weekly": "Seu resumo semanal:",
+
+	"quiet.disabled":   "O horário de silêncio não está habilitado.",
+	"quiet.none":       "Nenhum horário de silêncio definido.\n\nUso: /quiet 22:00-07:00|off",
+	"quiet.status":     "Horário de silêncio: %s (hora do servidor)\n\nUso: /quiet 22:00-07:00|off",
+	"quiet.usage":      "Uso: /quiet 22:00-07:00|off",
+	"quiet.set":        "Horário de silêncio definido para %s (hora do servidor). Resumos, itens de feeds e mensagens agendadas vão esperar até o fim.",
+	"quiet.off":        "Horário de silêncio desativado.",
+	"quiet.save_error": "Erro ao salvar o horário de silêncio",
+
+	"ha.disabled": "O Home Assistant não está conectado.",
+	"ha.usage":    "Uso: /ha <comando>, por exemplo /ha apague as luzes",
+	"ha.sent":     "Enviado ao Home Assistant.",
+	"ha.error":    "Erro ao enviar o comando ao Home Assistant",
+
+	"admin.usage":                 "Uso: /admin providers|storage|stats",
+	"admin.providers.unsupported": "A verificação de provedores não está disponível.",
+	"admin.providers.checking":    "Verificando provedores...",
+	"admin.providers.none":        "Nenhum provedor de LLM está ativado. Verifique a seção providers do config.yaml e as chaves de API.",
+	"admin.providers.header":      "Provedores:",
+	"admin.providers.up":          "✅ %s (%s): ativo, %s",
+	"admin.providers.down":        "❌ %s (%s): fora do ar: %v",
+	"admin.providers.last_error":  "   último erro há %s: %s",
+	"admin.storage.unsupported":   "O relatório de armazenamento não está disponível.",
+	"admin.storage.error":         "Erro ao ler o uso de armazenamento",
+	"admin.storage.total":         "Armazenamento: %s usados, cota %s",
+	"admin.storage.user_quota":    "Cota por usuário: %s",
+	"admin.storage.unlimited":     "nenhuma",
+	"admin.storage.header":        "Usuários com mais dados:",
+	"admin.storage.more":          "...e mais %d",
+	"admin.stats.disabled":        "As avaliações de respostas estão desativadas. Configure feedback.enabled e feedback.buttons.",
+	"admin.stats.error":           "Erro ao ler as avaliações de respostas",
+	"admin.stats.none":            "Nenhuma resposta foi avaliada ainda.",
+	"admin.stats.ratings":         "Avaliações de respostas: 👍 %d, 👎 %d (%d%% positivas)",
+
+	"status.text": "Helpi %s\n\nTempo ativo: %s\nProvedor: %s (%s)\nMemória: %s\nSua conversa: %d mensagens (~%d tokens)\nRespondidas desde o início: %d, com falha: %d",
+
+	"whoami.text":       "ID de usuário: %d\nNome de usuário: %s\nFunção: %s\nIdioma: %s",
+	"whoami.role.admin": "administrador",
+	"whoami.role.user":  "usuário",
+	"whoami.role.owner": "proprietário",
+	"whoami.role.guest": "convidado",
+
+	"update.available": "Uma nova versão do helpi está disponível: %s (em uso %s)\n%s",
+
+	"pin.usage":     "Responda a uma mensagem com /pin para mantê-la sempre na conversa.",
+	"pin.not_found": "Essa mensagem não está na sua conversa atual.",
+	"pin.already":   "Essa mensagem já está fixada.",
+	"pin.done":      "Fixada. Esta mensagem fica na conversa até você usar /unpin.",
+	"pin.error":     "Erro ao atualizar as mensagens fixadas",
+
+	"pins.none":   "Nenhuma mensagem fixada. Responda a uma mensagem com /pin para fixá-la.",
+	"pins.header": "Mensagens fixadas:",
+	"pins.item":   "%d. %s: %s",
+	"pins.you":    "Você",
+	"pins.bot":    "Bot",
+
+	"unpin.usage":      "Responda a uma mensagem fixada com /unpin, ou use /unpin <número> de /pins.",
+	"unpin.unknown":    "Nenhuma mensagem fixada com esse número. Veja /pins.",
+	"unpin.not_pinned": "Essa mensagem não está fixada.",
+	"unpin.done":       "Desafixada.",
+
+	"remember.disabled":  "As memórias não estão ativadas.",
+	"remember.usage":     "Uso: /remember <fato>, por exemplo /remember sou vegetariano",
+	"remember.done":      "Entendido. Vou levar isso em conta em todas as conversas.",
+	"remember.duplicate": "Eu já lembro disso.",
+	"remember.limit":     "Você pode guardar no máximo %d memórias. Remova algumas com /forget primeiro.",
+	"remember.too_long":  "Isso é longo demais para lembrar. Use menos de %d caracteres.",
+	"remember.error":     "Erro ao atualizar as memórias",
+
+	"memories.none":    "Ainda não lembro de nada sobre você. Use /remember <fato> para adicionar algo.",
+	"memories.header":  "O que eu lembro sobre você:",
+	"memories.learned": "(aprendido)",
+	"memories.footer":  "Use /forget <número> para remover uma.",
+
+	"forget.usage":   "Uso: /forget <número> (veja /memories) ou /forget all",
+	"forget.unknown": "Nenhuma memória com esse número. Veja /memories.",
+	"forget.done":    "Esquecido: %s",
+	"forget.all":     "Todas as memórias removidas.",
+
+	"watch.disabled":    "O acompanhamento de feeds não está ativado.",
+	"watch.invalid_url": "Envie um endereço de feed que comece com http:// ou https://.",
+	"watch.fetch_error": "Não foi possível ler um feed RSS ou Atom nesse endereço.",
+	"watch.done":        "Acompanhando %s. As novidades serão publicadas aqui.",
+	"watch.duplicate":   "Este chat já acompanha esse feed.",
+	"watch.limit":       "Você pode acompanhar no máximo %d feeds. Remova um com /unwatch primeiro.",
+	"watch.error":       "Erro ao atualizar os feeds acompanhados",
+	"watch.none":        "Nenhum feed é acompanhado neste chat. Use /watch <URL do feed> para adicionar um.",
+	"watch.header":      "Feeds acompanhados neste chat:",
+	"watch.footer":      "Use /unwatch <número> para deixar de acompanhar um.",
+
+	"unwatch.usage":   "Uso: /unwatch <número> (veja /watch)",
+	"unwatch.unknown": "Nenhum feed com esse número. Veja /watch.",
+	"unwatch.done":    "Deixou de acompanhar %s.",
+
+	"import.usage":          "Envie uma conversa exportada como arquivo JSON ou Markdown com /import na legenda, ou responda ao arquivo com /import. Adicione \"new\" para importá-la em uma nova conversa.",
+	"import.too_large":      "O arquivo é grande demais para importar (máx. %d MB).",
+	"import.download_error": "Erro ao baixar o arquivo",
+	"import.invalid":        "Não foi possível importar esse arquivo: %v",
+	"import.error":          "Erro ao salvar a conversa importada",
+	"import.done":           "%d mensagens importadas na conversa atual.",
+	"import.thread":         "%d mensagens importadas na conversa %d: %s",
+
+	"command.route.args":      "<texto>",
+	"command.route.usage":     "Uso: /%s <texto>",
+	"command.route.ask":       "Perguntar ao %s",
+	"command.route.ask_model": "Perguntar ao %s (%s)",
+	"scheduled.incomplete":    "Não foi possível concluir o prompt agendado %q.",
+	"scheduled.failed":        "O prompt agendado %q falhou.",
+}
@@ -0,0 +1,67 @@
+package ingest
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const DefaultChunkSize = 1200
+
+func Chunk(text string, size int) []string {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	var chunks []string
+	current := ""
+	flush := func() {
+		if strings.TrimSpace(current) != "" {
+			chunks = append(chunks, strings.TrimSpace(current))
+		}
+		current = ""
+	}
+
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		for _, piece := range splitWords(paragraph, size) {
+			if current != "" && utf8.RuneCountInString(current)+2+utf8.RuneCountInString(piece) > size {
+				flush()
+			}
+			if current == "" {
+				current = piece
+			} else {
+				current += "\n\n" + piece
+			}
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+func splitWords(text string, size int) []string {
+	if utf8.RuneCountInString(text) <= size {
+		return []string{text}
+	}
+
+	var pieces []string
+	var current strings.Builder
+	for _, word := range strings.Fields(text) {
+		if current.Len() > 0 && utf8.RuneCountInString(current.String())+1+utf8.RuneCountInString(word) > size {
+			pieces = append(pieces, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteByte(' ')
+		}
+		current.WriteString(word)
+	}
+	if current.Len() > 0 {
+		pieces = append(pieces, current.String())
+	}
+	return pieces
+}
@@ -0,0 +1,67 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported file type")
+	ErrNoText      = errors.New("no extractable text")
+	ErrTooLarge    = errors.New("document expands beyond the size limit")
+)
+
+type Extractor func(data []byte) (string, error)
+
+var extractors = map[string]Extractor{
+	".txt":      extractText,
+	".md":       extractText,
+	".markdown": extractText,
+	".pdf":      extractPDF,
+}
+
+var mimeExtensions = map[string]string{
+	"text/plain":      ".txt",
+	"text/markdown":   ".md",
+	"text/x-markdown": ".md",
+	"application/pdf": ".pdf",
+}
+
+func Supported(name, mimeType string) bool {
+	_, ok := extractorFor(name, mimeType)
+	return ok
+}
+
+func Extract(name, mimeType string, data []byte) (string, error) {
+	extract, ok := extractorFor(name, mimeType)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupported, name)
+	}
+
+	text, err := extract(data)
+	if err != nil {
+		return "", err
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+func extractorFor(name, mimeType string) (Extractor, bool) {
+	if extract, ok := extractors[strings.ToLower(filepath.Ext(name))]; ok {
+		return extract, true
+	}
+	extract, ok := extractors[mimeExtensions[mimeType]]
+	return extract, ok
+}
+
+func extractText(data []byte) (string, error) {
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("file is not valid UTF-8 text")
+	}
+	return strings.TrimPrefix(string(data), "\ufeff"), nil
+}
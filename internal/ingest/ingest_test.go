@@ -0,0 +1,136 @@
+package ingest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func buildPDF(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write([]byte(content))
+	w.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestExtract_Text(t *testing.T) {
+	text, err := Extract("notes.md", "", []byte("# Title\n\nSome notes."))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "# Title\n\nSome notes." {
+		t.Errorf("unexpected text %q", text)
+	}
+}
+
+func TestExtract_MIMEFallback(t *testing.T) {
+	if _, err := Extract("upload", "text/plain", []byte("hello")); err != nil {
+		t.Errorf("expected MIME type to select extractor, got %v", err)
+	}
+}
+
+func TestExtract_Unsupported(t *testing.T) {
+	_, err := Extract("photo.png", "image/png", []byte{0x89, 'P', 'N', 'G'})
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestExtract_PDF(t *testing.T) {
+	data := buildPDF(t, "BT /F1 12 Tf 72 712 Td (Hello PDF \\(world\\)) Tj 0 -14 Td [(Second) -250 (line)] TJ ET")
+
+	text, err := Extract("report.pdf", "application/pdf", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(text, "Hello PDF (world)") || !strings.Contains(text, "\nSecond line") {
+		t.Errorf("unexpected PDF text %q", text)
+	}
+}
+
+func TestExtract_PDFWithoutText(t *testing.T) {
+	data := buildPDF(t, "0 0 m 100 100 l S")
+
+	if _, err := Extract("scan.pdf", "", data); !errors.Is(err, ErrNoText) {
+		t.Errorf("expected ErrNoText, got %v", err)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	text := "first paragraph\n\nsecond paragraph\n\n" + strings.Repeat("word ", 20)
+	chunks := Chunk(text, 40)
+
+	if chunks[0] != "first paragraph\n\nsecond paragraph" {
+		t.Errorf("expected paragraphs to be packed together, got %q", chunks[0])
+	}
+	for _, c := range chunks {
+		if len(c) > 40 {
+			t.Errorf("chunk exceeds size: %q", c)
+		}
+	}
+}
+
+func TestStore_AddListSearchClear(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() returned error: %v", err)
+	}
+
+	store.Add(1, "lease.pdf", []string{"The monthly rent is 1200 dollars.", "Pets are allowed with a deposit."})
+	doc, err := store.Add(1, "manual.txt", []string{"Press the reset button for ten seconds."})
+	if err != nil {
+		t.Fatalf("Add() returned error: %v", err)
+	}
+	if doc.ID != 2 {
+		t.Errorf("expected sequential IDs, got %d", doc.ID)
+	}
+
+	excerpts, err := store.Search(1, "How much is the rent?", 2)
+	if err != nil {
+		t.Fatalf("Search() returned error: %v", err)
+	}
+	if len(excerpts) != 1 || excerpts[0].Document != "lease.pdf" || !strings.Contains(excerpts[0].Text, "rent") {
+		t.Errorf("unexpected excerpts %+v", excerpts)
+	}
+
+	if other, _ := store.Search(2, "rent", 2); len(other) != 0 {
+		t.Errorf("expected documents to be per user, got %+v", other)
+	}
+
+	if err := store.Clear(1); err != nil {
+		t.Fatalf("Clear() returned error: %v", err)
+	}
+	if docs, _ := store.List(1); len(docs) != 0 {
+		t.Errorf("expected no documents after clear, got %d", len(docs))
+	}
+}
+
+func TestExtract_PDFDecompressionLimit(t *testing.T) {
+	data := buildPDF(t, strings.Repeat(" ", maxDecoded+1))
+
+	if _, err := Extract("bomb.pdf", "application/pdf", data); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestExtract_PDFCorruptStream(t *testing.T) {
+	data := buildPDF(t, "BT (Hello) Tj ET")
+	i := bytes.Index(data, []byte("\nendstream"))
+	data = append(data[:i-4:i-4], data[i:]...)
+
+	if _, err := Extract("broken.pdf", "application/pdf", data); err == nil {
+		t.Error("expected an error for a truncated stream")
+	}
+}
@@ -0,0 +1,309 @@
+package ingest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// maxDecoded caps the decompressed size of all streams in a PDF, so a small
+// upload cannot expand to gigabytes.
+const maxDecoded = 64 << 20
+
+func extractPDF(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return "", ErrUnsupported
+	}
+
+	streams, err := pdfStreams(data)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	for _, content := range streams {
+		if bytes.Contains(content, []byte("BT")) {
+			out.WriteString(pdfText(content))
+			out.WriteString("\n")
+		}
+	}
+
+	text := blankLines.ReplaceAllString(strings.TrimSpace(out.String()), "\n\n")
+	if text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+func pdfStreams(data []byte) ([][]byte, error) {
+	var streams [][]byte
+	decodedTotal := 0
+
+	pos := 0
+	for {
+		i := bytes.Index(data[pos:], []byte("stream"))
+		if i < 0 {
+			break
+		}
+		i += pos
+		if i > 0 && data[i-1] == 'd' {
+			pos = i + len("stream")
+			continue
+		}
+
+		start := i + len("stream")
+		if start < len(data) && data[start] == '\r' {
+			start++
+		}
+		if start < len(data) && data[start] == '\n' {
+			start++
+		}
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		raw := data[start : start+end]
+		pos = start + end + len("endstream")
+
+		header := data[:i]
+		if obj := bytes.LastIndex(header, []byte(" obj")); obj >= 0 {
+			header = header[obj:]
+		}
+		if skipStream(header) {
+			continue
+		}
+
+		if bytes.Contains(header, []byte("/FlateDecode")) {
+			r, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			decoded, err := io.ReadAll(io.LimitReader(r, int64(maxDecoded-decodedTotal)+1))
+			r.Close()
+			if err != nil {
+				return nil, fmt.Errorf("decompress PDF stream: %w", err)
+			}
+			decodedTotal += len(decoded)
+			if decodedTotal > maxDecoded {
+				return nil, ErrTooLarge
+			}
+			raw = decoded
+		} else if bytes.Contains(header, []byte("/Filter")) {
+			continue
+		}
+
+		streams = append(streams, raw)
+	}
+
+	return streams, nil
+}
+
+func skipStream(header []byte) bool {
+	for _, marker := range []string{"/ObjStm", "/XRef", "/Image", "/FontFile", "/Metadata"} {
+		if bytes.Contains(header, []byte(marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+func pdfText(content []byte) string {
+	var out strings.Builder
+	var operands []string
+	var nums []float64
+	inArray := false
+	lastY := 0.0
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '(':
+			s, n := readLiteral(content[i:])
+			operands = append(operands, s)
+			i += n
+		case c == '<' && i+1 < len(content) && content[i+1] != '<':
+			s, n := readHex(content[i:])
+			operands = append(operands, s)
+			i += n
+		case c == '[':
+			inArray = true
+			i++
+		case c == ']':
+			inArray = false
+			i++
+		case c == '/':
+			i++
+			for i < len(content) && !isDelimiter(content[i]) {
+				i++
+			}
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(content) && (content[j] == '.' || (content[j] >= '0' && content[j] <= '9')) {
+				j++
+			}
+			f, _ := strconv.ParseFloat(string(content[i:j]), 64)
+			if inArray {
+				if f < -200 {
+					operands = append(operands, " ")
+				}
+			} else {
+				nums = append(nums, f)
+			}
+			i = j
+		case isOperatorByte(c):
+			j := i + 1
+			for j < len(content) && isOperatorByte(content[j]) {
+				j++
+			}
+			op := string(content[i:j])
+			if op == "Tm" && len(nums) >= 6 {
+				if y := nums[len(nums)-1]; y != lastY {
+					out.WriteString("\n")
+					lastY = y
+				} else {
+					out.WriteString(" ")
+				}
+			} else {
+				writeOperator(&out, op, operands, nums)
+			}
+			operands, nums = nil, nil
+			i = j
+		default:
+			i++
+		}
+	}
+
+	return out.String()
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\r\n\f()<>[]{}/%", c) >= 0
+}
+
+func isOperatorByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '*' || c == '\'' || c == '"'
+}
+
+func writeOperator(out *strings.Builder, op string, operands []string, nums []float64) {
+	switch op {
+	case "Tj", "TJ":
+		out.WriteString(strings.Join(operands, ""))
+	case "'", "\"":
+		out.WriteString("\n" + strings.Join(operands, ""))
+	case "T*", "ET":
+		out.WriteString("\n")
+	case "Td", "TD":
+		if len(nums) >= 2 && nums[len(nums)-1] != 0 {
+			out.WriteString("\n")
+		} else {
+			out.WriteString(" ")
+		}
+	}
+}
+
+func readLiteral(b []byte) (string, int) {
+	var buf []byte
+	depth := 0
+	i := 0
+	for ; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c == '\\' && i+1 < len(b):
+			i++
+			switch e := b[i]; e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					j := i
+					for j < len(b) && j < i+3 && b[j] >= '0' && b[j] <= '7' {
+						j++
+					}
+					v, _ := strconv.ParseUint(string(b[i:j]), 8, 8)
+					buf = append(buf, byte(v))
+					i = j - 1
+				} else {
+					buf = append(buf, e)
+				}
+			}
+		case c == '(':
+			if depth > 0 {
+				buf = append(buf, c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return decodePDFString(buf), i + 1
+			}
+			buf = append(buf, c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return decodePDFString(buf), i
+}
+
+func readHex(b []byte) (string, int) {
+	end := bytes.IndexByte(b, '>')
+	if end < 0 {
+		return "", len(b)
+	}
+
+	var digits []byte
+	for _, c := range b[1:end] {
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	raw := make([]byte, len(digits)/2)
+	for i := range raw {
+		v, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		raw[i] = byte(v)
+	}
+	return decodePDFString(raw), end + 1
+}
+
+func decodePDFString(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		return decodeUTF16(b[2:])
+	}
+	if len(b) >= 2 && len(b)%2 == 0 && b[0] == 0 {
+		return decodeUTF16(b)
+	}
+
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+func decodeUTF16(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
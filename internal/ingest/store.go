@@ -0,0 +1,209 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/jrswab/helpi/internal/storage"
+)
+
+type Document struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Chunks    []string  `json:"chunks"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Excerpt struct {
+	Document string
+	Text     string
+	Score    float64
+}
+
+type Store interface {
+	Add(userID int64, name string, chunks []string) (Document, error)
+	List(userID int64) ([]Document, error)
+	Clear(userID int64) error
+	Search(userID int64, query string, limit int) ([]Excerpt, error)
+}
+
+type fileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewStore(dir string) (Store, error) {
+	if dir == "" {
+		dir = "./data/documents"
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create document directory: %w", err)
+	}
+
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) Add(userID int64, name string, chunks []string) (Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs, err := s.read(userID)
+	if err != nil {
+		return Document{}, err
+	}
+
+	doc := Document{ID: 1, Name: name, Chunks: chunks, CreatedAt: time.Now()}
+	for _, d := range docs {
+		doc.ID = max(doc.ID, d.ID+1)
+	}
+
+	if err := s.write(userID, append(docs, doc)); err != nil {
+		return Document{}, err
+	}
+	return doc, nil
+}
+
+func (s *fileStore) List(userID int64) ([]Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(userID)
+}
+
+func (s *fileStore) Clear(userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(userID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete documents: %w", err)
+	}
+	return nil
+}
+
+// Evict removes the documents file f found by storage.Scan.
+func (s *fileStore) Evict(f storage.File) (bool, error) {
+	if filepath.Clean(f.Path) != filepath.Clean(s.path(f.UserID)) {
+		return false, nil
+	}
+	return true, s.Clear(f.UserID)
+}
+
+func (s *fileStore) Search(userID int64, query string, limit int) ([]Excerpt, error) {
+	docs, err := s.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := tokenize(query)
+	if len(terms) == 0 || len(docs) == 0 {
+		return nil, nil
+	}
+
+	type candidate struct {
+		doc    string
+		text   string
+		counts map[string]int
+	}
+	var candidates []candidate
+	df := make(map[string]int)
+	for _, d := range docs {
+		for _, chunk := range d.Chunks {
+			counts := make(map[string]int)
+			for _, tok := range tokens(chunk) {
+				counts[tok]++
+			}
+			for term := range terms {
+				if counts[term] > 0 {
+					df[term]++
+				}
+			}
+			candidates = append(candidates, candidate{doc: d.Name, text: chunk, counts: counts})
+		}
+	}
+
+	var excerpts []Excerpt
+	n := float64(len(candidates))
+	for _, c := range candidates {
+		score := 0.0
+		for term := range terms {
+			if tf := c.counts[term]; tf > 0 {
+				score += (1 + math.Log(float64(tf))) * math.Log(1+n/float64(df[term]))
+			}
+		}
+		if score > 0 {
+			excerpts = append(excerpts, Excerpt{Document: c.doc, Text: c.text, Score: score})
+		}
+	}
+
+	sort.SliceStable(excerpts, func(i, j int) bool { return excerpts[i].Score > excerpts[j].Score })
+	if limit > 0 && len(excerpts) > limit {
+		excerpts = excerpts[:limit]
+	}
+	return excerpts, nil
+}
+
+func (s *fileStore) read(userID int64) ([]Document, error) {
+	data, err := os.ReadFile(s.path(userID))
+	if os.IsNotExist(err) {
+		return []Document{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read documents: %w", err)
+	}
+
+	var docs []Document
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("failed to parse documents: %w", err)
+	}
+	return docs, nil
+}
+
+func (s *fileStore) write(userID int64, docs []Document) error {
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal documents: %w", err)
+	}
+	if err := os.WriteFile(s.path(userID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write documents: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) path(userID int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%d.json", userID))
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "what": true, "who": true,
+	"how": true, "why": true, "when": true, "this": true, "that": true, "with": true, "from": true,
+	"does": true, "did": true, "about": true, "into": true, "your": true, "you": true, "can": true,
+}
+
+func tokenize(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, word := range tokens(text) {
+		terms[word] = true
+	}
+	return terms
+}
+
+func tokens(text string) []string {
+	var out []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		if len([]rune(word)) >= 3 && !stopWords[word] {
+			out = append(out, word)
+		}
+	}
+	return out
+}
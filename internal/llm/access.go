@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+var ErrProviderNotAllowed = errors.New("provider not allowed")
+
+// AccessChecker is implemented by routers that limit which providers each
+// user can use.
+type AccessChecker interface {
+	// AllowedProviders returns the enabled providers userID may use.
+	AllowedProviders(userID int64) []string
+}
+
+// grant is the set of providers and models one user may use. Empty sets
+// allow everything.
+type grant struct {
+	providers map[string]bool
+	models    map[string]bool
+}
+
+type providerAccess struct {
+	fallback grant
+	users    map[int64]grant
+}
+
+// compileAccess builds the grants of cfg. Guests without a grant of their
+// own get the guest role's providers and models.
+func compileAccess(cfg config.ProviderAccessConfig, roles config.RolesConfig) *providerAccess {
+	if len(cfg.Default.Providers) == 0 && len(cfg.Default.Models) == 0 && len(cfg.Users) == 0 && len(roles.Guests) == 0 {
+		return nil
+	}
+	access := &providerAccess{fallback: newGrant(cfg.Default), users: make(map[int64]grant, len(cfg.Users))}
+	for userID, g := range cfg.Users {
+		access.users[userID] = newGrant(g)
+	}
+	for _, userID := range roles.Guests {
+		if _, ok := access.users[userID]; !ok {
+			access.users[userID] = newGrant(roles.Guest.ProviderGrantConfig)
+		}
+	}
+	return access
+}
+
+func newGrant(cfg config.ProviderGrantConfig) grant {
+	g := grant{providers: make(map[string]bool), models: make(map[string]bool)}
+	for _, p := range cfg.Providers {
+		g.providers[p] = true
+	}
+	for _, m := range cfg.Models {
+		g.models[m] = true
+	}
+	return g
+}
+
+func (a *providerAccess) grantFor(userID int64) grant {
+	if a == nil {
+		return grant{}
+	}
+	if g, ok := a.users[userID]; ok {
+		return g
+	}
+	return a.fallback
+}
+
+func (g grant) allowsProvider(name string) bool {
+	return len(g.providers) == 0 || g.providers[name]
+}
+
+// allowsModel reports whether a model may be requested explicitly, by a
+// routing rule, persona or command route. A provider's configured model is
+// always allowed.
+func (g grant) allowsModel(model string) bool {
+	return model == "" || len(g.models) == 0 || g.models[model]
+}
+
+func (r *router) AllowedProviders(userID int64) []string {
+	g := r.access.grantFor(userID)
+	var names []string
+	for _, name := range r.ProviderNames() {
+		if g.allowsProvider(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// permitted returns provider if userID may use it, and otherwise the first
+// allowed provider, preferring fallback and then the router's default.
+func (r *router) permitted(userID int64, provider Provider, fallback string) (Provider, error) {
+	g := r.access.grantFor(userID)
+	if g.allowsProvider(provider.Name()) {
+		return provider, nil
+	}
+
+	candidates := []string{fallback}
+	if p, err := r.GetProvider(); err == nil {
+		candidates = append(candidates, p.Name())
+	}
+	candidates = append(candidates, r.ProviderNames()...)
+	for _, name := range candidates {
+		if name == "" || !g.allowsProvider(name) {
+			continue
+		}
+		if p, err := r.GetProviderByName(name); err == nil {
+			log.Printf("User %d may not use %s, using %s", userID, provider.Name(), name)
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no provider is enabled for user %d", ErrProviderNotAllowed, userID)
+}
+
+func (r *ReloadableRouter) AllowedProviders(userID int64) []string {
+	if checker, ok := r.router().(AccessChecker); ok {
+		return checker.AllowedProviders(userID)
+	}
+	return r.router().ProviderNames()
+}
@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func newAccessRouter(t *testing.T, rules []config.RoutingRuleConfig) *router {
+	t.Helper()
+	r := newRulesRouter(t, rules)
+	r.access = compileAccess(config.ProviderAccessConfig{
+		Default: config.ProviderGrantConfig{Providers: []string{"ollama"}},
+		Users: map[int64]config.ProviderGrantConfig{
+			1: {Models: []string{"claude-sonnet"}},
+		},
+	}, config.RolesConfig{
+		Guests: []int64{1, 3},
+		Guest:  config.GuestConfig{ProviderGrantConfig: config.ProviderGrantConfig{Providers: []string{"openai"}}},
+	})
+	return r
+}
+
+func TestSendMessage_ProviderAccess(t *testing.T) {
+	r := newAccessRouter(t, []config.RoutingRuleConfig{{Name: "code", Provider: "anthropic", Model: "claude-opus", Keywords: []string{"code"}}})
+	messages := []Message{{Role: "user", Content: "write some code"}}
+
+	tests := []struct {
+		name  string
+		opts  []RequestOption
+		want  string
+		model string
+	}{
+		{name: "guest default", opts: []RequestOption{WithUser(2)}, want: "ollama"},
+		{name: "guest explicit provider", opts: []RequestOption{WithUser(2), WithProvider("anthropic"), WithModel("claude-opus")}, want: "ollama"},
+		{name: "owner rule with disallowed model", opts: []RequestOption{WithUser(1)}, want: "anthropic"},
+		{name: "owner allowed model", opts: []RequestOption{WithUser(1), WithProvider("anthropic"), WithModel("claude-sonnet")}, want: "anthropic", model: "claude-sonnet"},
+	}
+	for _, tt := range tests {
+		var route Route
+		opts := append(tt.opts, WithRouteReport(func(rt Route) { route = rt }))
+		got, err := r.SendMessage(context.Background(), messages, opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want || route.Model != tt.model {
+			t.Errorf("%s: got %s with model %q, want %s with model %q", tt.name, got, route.Model, tt.want, tt.model)
+		}
+	}
+}
+
+func TestProviderAccess_SwitchAndList(t *testing.T) {
+	r := newAccessRouter(t, nil)
+
+	if err := r.SetDefaultForUser(2, "anthropic"); !errors.Is(err, ErrProviderNotAllowed) {
+		t.Errorf("expected ErrProviderNotAllowed, got %v", err)
+	}
+	if err := r.SetDefaultForUser(1, "anthropic"); err != nil {
+		t.Errorf("expected the owner to switch, got %v", err)
+	}
+	if got := r.AllowedProviders(2); !slices.Equal(got, []string{"ollama"}) {
+		t.Errorf("expected only ollama for guests, got %v", got)
+	}
+	if got := r.AllowedProviders(1); len(got) != 3 {
+		t.Errorf("expected every provider for the owner, got %v", got)
+	}
+	if got := r.AllowedProviders(3); !slices.Equal(got, []string{"openai"}) {
+		t.Errorf("expected the guest role's providers, got %v", got)
+	}
+
+	r.providers[2].(*mockProvider).enabled = false
+	if _, err := r.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}}, WithUser(2)); !errors.Is(err, ErrProviderNotAllowed) {
+		t.Errorf("expected ErrProviderNotAllowed with no allowed provider enabled, got %v", err)
+	}
+}
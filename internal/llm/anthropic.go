@@ -2,8 +2,10 @@ package llm
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
@@ -15,6 +17,7 @@ type anthropicProvider struct {
 	model       string
 	enabled     bool
 	providerCfg config.ProviderConfig
+	retry       retryPolicy
 }
 
 func NewAnthropicProvider(cfg *config.Config) Provider {
@@ -25,6 +28,8 @@ func NewAnthropicProvider(cfg *config.Config) Provider {
 	if enabled {
 		client = anthropic.NewClient(
 			option.WithAPIKey(apiKey),
+			option.WithMaxRetries(0),
+			option.WithHTTPClient(newHTTPClient(cfg.Providers.Anthropic)),
 		)
 	}
 
@@ -33,6 +38,7 @@ func NewAnthropicProvider(cfg *config.Config) Provider {
 		model:       cfg.Providers.Anthropic.DefaultModel,
 		enabled:     enabled,
 		providerCfg: cfg.Providers.Anthropic,
+		retry:       newRetryPolicy("anthropic", cfg.Providers.Anthropic),
 	}
 }
 
@@ -40,21 +46,86 @@ func (p *anthropicProvider) Name() string {
 	return "anthropic"
 }
 
+func (p *anthropicProvider) Model() string {
+	return p.model
+}
+
 func (p *anthropicProvider) IsEnabled() bool {
 	return p.enabled
 }
 
 func (p *anthropicProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	return p.retry.do(ctx, func(ctx context.Context) (string, error) {
+		return p.send(ctx, messages)
+	})
+}
+
+func (p *anthropicProvider) send(ctx context.Context, messages []Message) (string, error) {
 	if !p.enabled {
 		return "", fmt.Errorf("anthropic: provider not enabled")
 	}
 
+	cfg := generationConfig(ctx, p.providerCfg)
+	params := p.buildParams(modelFromContext(ctx, p.model), cfg, messages)
+
+	// Streaming keeps long answers and extended thinking from running into
+	// the API's limit on how long a non-streaming request may take.
+	stream := p.client.Messages.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	var message anthropic.Message
+	for stream.Next() {
+		event := stream.Current()
+		if err := message.Accumulate(event); err != nil {
+			return "", fmt.Errorf("anthropic: %w", err)
+		}
+		if event.Type == "content_block_start" && event.ContentBlock.Type == "thinking" {
+			reportThinking(ctx)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return "", fmt.Errorf("anthropic: %w", err)
+	}
+
+	var responseText string
+	var thinking []string
+	for _, content := range message.Content {
+		switch content.Type {
+		case "text":
+			responseText += content.Text
+		case "thinking":
+			if text := strings.TrimSpace(content.Thinking); text != "" {
+				thinking = append(thinking, text)
+			}
+		}
+	}
+	if cfg.ShowReasoning {
+		reportReasoning(ctx, strings.Join(thinking, "\n\n"))
+	}
+	reportTruncation(ctx, message.StopReason == anthropic.StopReasonMaxTokens)
+
+	return responseText, nil
+}
+
+func (p *anthropicProvider) Ping(ctx context.Context) error {
+	if !p.enabled {
+		return fmt.Errorf("anthropic: provider not enabled")
+	}
+
+	if _, err := p.client.Models.List(ctx, anthropic.ModelListParams{}); err != nil {
+		return fmt.Errorf("anthropic: %w", err)
+	}
+
+	return nil
+}
+
+func (p *anthropicProvider) buildParams(model string, cfg config.ProviderConfig, messages []Message) anthropic.MessageNewParams {
 	var systemMsg string
 	var conversationMessages []anthropic.MessageParam
 
 	for _, msg := range messages {
 		if msg.Role == "system" {
-			systemMsg = msg.Content
+			systemMsg = joinSystem(systemMsg, msg.Content)
 			continue
 		}
 
@@ -65,19 +136,25 @@ func (p *anthropicProvider) SendMessage(ctx context.Context, messages []Message)
 			role = anthropic.MessageParamRoleUser
 		}
 
+		content := []anthropic.ContentBlockParamUnion{}
+		for _, img := range msg.Images {
+			content = append(content, anthropic.NewImageBlockBase64(img.MIMEType, base64.StdEncoding.EncodeToString(img.Data)))
+		}
+		if msg.Content != "" || len(content) == 0 {
+			content = append(content, anthropic.ContentBlockParamUnion{OfText: &anthropic.TextBlockParam{Text: msg.Content}})
+		}
+
 		msgParam := anthropic.MessageParam{
-			Role: role,
-			Content: []anthropic.ContentBlockParamUnion{
-				{OfText: &anthropic.TextBlockParam{Text: msg.Content}},
-			},
+			Role:    role,
+			Content: content,
 		}
 		conversationMessages = append(conversationMessages, msgParam)
 	}
 
 	params := anthropic.MessageNewParams{
-		Model:     anthropic.Model(p.model),
-		MaxTokens: 1024,
+		Model: anthropic.Model(model),
 	}
+	applyAnthropicGeneration(&params, cfg)
 
 	if systemMsg != "" {
 		params.System = []anthropic.TextBlockParam{
@@ -89,20 +166,79 @@ func (p *anthropicProvider) SendMessage(ctx context.Context, messages []Message)
 		params.Messages = conversationMessages
 	}
 
-	message, err := p.client.Messages.New(ctx, params)
+	return params
+}
+
+func (p *anthropicProvider) SupportsVision() bool {
+	return true
+}
+
+func (p *anthropicProvider) SubmitBatch(ctx context.Context, requests []BatchRequest) (string, error) {
+	if !p.enabled {
+		return "", fmt.Errorf("anthropic: provider not enabled")
+	}
+
+	batchRequests := make([]anthropic.MessageBatchNewParamsRequest, len(requests))
+	for i, req := range requests {
+		params := p.buildParams(p.model, p.providerCfg, req.Messages)
+		batchRequests[i] = anthropic.MessageBatchNewParamsRequest{
+			CustomID: req.CustomID,
+			Params: anthropic.MessageBatchNewParamsRequestParams{
+				Model:       params.Model,
+				MaxTokens:   params.MaxTokens,
+				Temperature: params.Temperature,
+				TopP:        params.TopP,
+				System:      params.System,
+				Messages:    params.Messages,
+				Thinking:    params.Thinking,
+			},
+		}
+	}
+
+	batch, err := p.client.Messages.Batches.New(ctx, anthropic.MessageBatchNewParams{
+		Requests: batchRequests,
+	})
 	if err != nil {
-		return "", fmt.Errorf("anthropic: %w", err)
+		return "", fmt.Errorf("anthropic: failed to create batch: %w", err)
 	}
 
-	if len(message.Content) == 0 {
-		return "", nil
+	return batch.ID, nil
+}
+
+func (p *anthropicProvider) BatchResults(ctx context.Context, batchID string) ([]BatchResult, bool, error) {
+	if !p.enabled {
+		return nil, false, fmt.Errorf("anthropic: provider not enabled")
 	}
 
-	var responseText string
-	for _, content := range message.Content {
-		textBlock := content.AsText()
-		responseText += textBlock.Text
+	batch, err := p.client.Messages.Batches.Get(ctx, batchID)
+	if err != nil {
+		return nil, false, fmt.Errorf("anthropic: %w", err)
 	}
 
-	return responseText, nil
+	if batch.ProcessingStatus != anthropic.MessageBatchProcessingStatusEnded {
+		return nil, false, nil
+	}
+
+	stream := p.client.Messages.Batches.ResultsStreaming(ctx, batchID)
+	defer stream.Close()
+
+	var results []BatchResult
+	for stream.Next() {
+		item := stream.Current()
+		result := BatchResult{CustomID: item.CustomID}
+		if item.Result.Type == "succeeded" {
+			for _, content := range item.Result.Message.Content {
+				result.Content += content.AsText().Text
+			}
+		} else {
+			result.Error = fmt.Sprintf("request %s", item.Result.Type)
+		}
+		results = append(results, result)
+	}
+
+	if err := stream.Err(); err != nil {
+		return nil, true, fmt.Errorf("anthropic: failed to read batch results: %w", err)
+	}
+
+	return results, true, nil
 }
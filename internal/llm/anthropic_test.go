@@ -2,9 +2,16 @@ package llm
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/jrswab/helpi/internal/config"
 )
 
@@ -91,3 +98,104 @@ func TestAnthropicProvider_SendMessage_Disabled(t *testing.T) {
 		t.Errorf("SendMessage() error = %v, want %v", err.Error(), expectedErr)
 	}
 }
+
+func TestAnthropicBuildParams_Images(t *testing.T) {
+	p := &anthropicProvider{}
+	params := p.buildParams("claude", p.providerCfg, []Message{{Role: "user", Content: "describe", Images: []Image{{MIMEType: "image/jpeg", Data: []byte("abc")}}}})
+
+	content := params.Messages[0].Content
+	if len(content) != 2 {
+		t.Fatalf("expected image and text blocks, got %d", len(content))
+	}
+	if content[0].OfImage == nil || content[0].OfImage.Source.OfBase64 == nil || content[0].OfImage.Source.OfBase64.Data != "YWJj" {
+		t.Errorf("expected base64 image block first, got %+v", content[0])
+	}
+	if content[1].OfText == nil || content[1].OfText.Text != "describe" {
+		t.Errorf("expected text block, got %+v", content[1])
+	}
+}
+
+func TestAnthropicBuildParams_Thinking(t *testing.T) {
+	temperature := 0.2
+	p := &anthropicProvider{}
+	params := p.buildParams("claude", config.ProviderConfig{ThinkingBudget: 8000, Temperature: &temperature}, []Message{{Role: "user", Content: "hi"}})
+
+	if params.Thinking.OfEnabled == nil || params.Thinking.OfEnabled.BudgetTokens != 8000 {
+		t.Errorf("expected thinking with a budget of 8000, got %+v", params.Thinking)
+	}
+	if params.MaxTokens != 8000+defaultAnthropicMaxTokens {
+		t.Errorf("expected max_tokens above the budget, got %d", params.MaxTokens)
+	}
+	if params.Temperature.Valid() {
+		t.Error("expected temperature to be dropped with thinking")
+	}
+}
+
+const anthropicStream = `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude","content":[],"stop_reason":null,"usage":{"input_tokens":5,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":"","signature":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"The user greets me."}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hello"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":" there!"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn","stop_sequence":null},"usage":{"output_tokens":12}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`
+
+func TestAnthropicProvider_SendMessage_Streaming(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, anthropicStream)
+	}))
+	defer server.Close()
+
+	p := &anthropicProvider{
+		client:      anthropic.NewClient(option.WithBaseURL(server.URL), option.WithAPIKey("test"), option.WithMaxRetries(0)),
+		model:       "claude",
+		enabled:     true,
+		providerCfg: config.ProviderConfig{ThinkingBudget: 2048, ShowReasoning: true},
+	}
+
+	thinking := 0
+	var reasoning string
+	ctx := contextWithThinkingReport(context.Background(), func() { thinking++ })
+	ctx = contextWithReasoningReport(ctx, func(s string) { reasoning = s })
+
+	response, err := p.send(ctx, []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("send() returned error: %v", err)
+	}
+	if response != "Hello there!" {
+		t.Errorf("expected the text blocks only, got %q", response)
+	}
+	if thinking != 1 || reasoning != "The user greets me." {
+		t.Errorf("expected thinking to be reported once with its text, got %d and %q", thinking, reasoning)
+	}
+	if !strings.Contains(body, `"stream":true`) || !strings.Contains(body, `"budget_tokens":2048`) {
+		t.Errorf("expected a streaming request with thinking, got %s", body)
+	}
+}
@@ -0,0 +1,19 @@
+package llm
+
+import "context"
+
+type BatchRequest struct {
+	CustomID string
+	Messages []Message
+}
+
+type BatchResult struct {
+	CustomID string
+	Content  string
+	Error    string
+}
+
+type BatchProvider interface {
+	SubmitBatch(ctx context.Context, requests []BatchRequest) (string, error)
+	BatchResults(ctx context.Context, batchID string) ([]BatchResult, bool, error)
+}
@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+)
+
+type compatibleProvider struct {
+	name        string
+	client      openai.Client
+	model       string
+	enabled     bool
+	providerCfg config.ProviderConfig
+	state       *responsesState
+	retry       retryPolicy
+}
+
+func NewOpenAICompatibleProvider(cfg config.CustomProviderConfig) Provider {
+	apiKey := "none"
+	if cfg.APIKeyEnv != "" {
+		apiKey = os.Getenv(cfg.APIKeyEnv)
+	}
+	enabled := cfg.Enabled && cfg.BaseURL != "" && apiKey != ""
+
+	var client openai.Client
+	if enabled {
+		client = openai.NewClient(
+			option.WithBaseURL(cfg.BaseURL),
+			option.WithAPIKey(apiKey),
+			option.WithMaxRetries(0),
+			option.WithHTTPClient(newHTTPClient(cfg.ProviderConfig)),
+		)
+	}
+
+	return &compatibleProvider{
+		name:        cfg.Name,
+		client:      client,
+		model:       cfg.DefaultModel,
+		enabled:     enabled,
+		providerCfg: cfg.ProviderConfig,
+		state:       newResponsesState(),
+		retry:       newRetryPolicy(cfg.Name, cfg.ProviderConfig),
+	}
+}
+
+func (p *compatibleProvider) Name() string {
+	return p.name
+}
+
+func (p *compatibleProvider) Model() string {
+	return p.model
+}
+
+func (p *compatibleProvider) IsEnabled() bool {
+	return p.enabled
+}
+
+func (p *compatibleProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	return p.retry.do(ctx, func(ctx context.Context) (string, error) {
+		return p.send(ctx, messages)
+	})
+}
+
+func (p *compatibleProvider) send(ctx context.Context, messages []Message) (string, error) {
+	if !p.enabled {
+		return "", fmt.Errorf("%s: provider not enabled", p.name)
+	}
+
+	if useResponsesAPI(p.providerCfg) {
+		resp, err := sendResponses(ctx, p.client, modelFromContext(ctx, p.model), p.providerCfg, p.state, messages)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", p.name, err)
+		}
+		return resp, nil
+	}
+
+	resp, err := p.client.Chat.Completions.New(ctx, chatCompletionParams(modelFromContext(ctx, p.model), messages, generationConfig(ctx, p.providerCfg)))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", p.name, err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", nil
+	}
+
+	reportTruncation(ctx, resp.Choices[0].FinishReason == finishReasonLength)
+	return applyThink(ctx, p.providerCfg.Think, resp.Choices[0].Message.Content), nil
+}
+
+func (p *compatibleProvider) Ping(ctx context.Context) error {
+	if !p.enabled {
+		return fmt.Errorf("%s: provider not enabled", p.name)
+	}
+
+	if _, err := p.client.Models.List(ctx); err != nil {
+		return fmt.Errorf("%s: %w", p.name, err)
+	}
+
+	return nil
+}
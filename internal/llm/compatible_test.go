@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func TestOpenAICompatibleProvider(t *testing.T) {
+	t.Setenv("GROQ_API_KEY", "test-key")
+
+	p := NewOpenAICompatibleProvider(config.CustomProviderConfig{
+		Name:           "groq",
+		BaseURL:        "https://api.groq.com/openai/v1",
+		APIKeyEnv:      "GROQ_API_KEY",
+		ProviderConfig: config.ProviderConfig{Enabled: true, DefaultModel: "llama-3.1-8b-instant"},
+	})
+
+	if p.Name() != "groq" {
+		t.Errorf("expected name groq, got %s", p.Name())
+	}
+	if !p.IsEnabled() {
+		t.Error("expected provider to be enabled")
+	}
+}
+
+func TestOpenAICompatibleProvider_MissingKey(t *testing.T) {
+	t.Setenv("MISTRAL_API_KEY", "")
+
+	p := NewOpenAICompatibleProvider(config.CustomProviderConfig{
+		Name:           "mistral",
+		BaseURL:        "https://api.mistral.ai/v1",
+		APIKeyEnv:      "MISTRAL_API_KEY",
+		ProviderConfig: config.ProviderConfig{Enabled: true, DefaultModel: "mistral-small"},
+	})
+
+	if p.IsEnabled() {
+		t.Error("expected provider without API key to be disabled")
+	}
+	if _, err := p.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}}); err == nil {
+		t.Error("expected error from disabled provider")
+	}
+}
+
+func TestOpenAICompatibleProvider_NoKeyRequired(t *testing.T) {
+	p := NewOpenAICompatibleProvider(config.CustomProviderConfig{
+		Name:           "lmstudio",
+		BaseURL:        "http://localhost:1234/v1",
+		ProviderConfig: config.ProviderConfig{Enabled: true, DefaultModel: "local-model"},
+	})
+
+	if !p.IsEnabled() {
+		t.Error("expected local provider without api_key_env to be enabled")
+	}
+}
+
+func TestNewRouter_OpenAICompatible(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "test-key")
+
+	cfg := &config.Config{
+		Providers: config.ProvidersConfig{
+			OpenAICompatible: []config.CustomProviderConfig{{
+				Name:           "deepseek",
+				BaseURL:        "https://api.deepseek.com/v1",
+				APIKeyEnv:      "DEEPSEEK_API_KEY",
+				ProviderConfig: config.ProviderConfig{Enabled: true, DefaultModel: "deepseek-chat"},
+			}},
+		},
+	}
+
+	router, err := NewRouter(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := router.GetProviderByName("deepseek")
+	if err != nil {
+		t.Fatalf("expected deepseek provider, got error: %v", err)
+	}
+	if p.Name() != "deepseek" {
+		t.Errorf("unexpected provider %s", p.Name())
+	}
+}
@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+)
+
+func TestEmbedOpenAI_OrdersByIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","model":"m","data":[
+			{"object":"embedding","index":1,"embedding":[0,1]},
+			{"object":"embedding","index":0,"embedding":[1,0]}
+		],"usage":{"prompt_tokens":2,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	client := openai.NewClient(option.WithBaseURL(server.URL), option.WithAPIKey("test"))
+	vectors, err := embedOpenAI(context.Background(), client, "m", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("embedOpenAI() returned error: %v", err)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("expected vectors ordered by index, got %v", vectors)
+	}
+}
+
+func TestOpenAIProvider_Embed_Disabled(t *testing.T) {
+	p := &openAIProvider{}
+	if _, err := p.Embed(context.Background(), "", []string{"a"}); err == nil {
+		t.Error("expected error for disabled provider")
+	}
+}
+
+type mockEmbedProvider struct {
+	mockProvider
+	dims      int
+	lastTexts []string
+}
+
+func (m *mockEmbedProvider) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	m.lastTexts = texts
+	vectors := make([][]float64, len(texts))
+	for i := range texts {
+		vectors[i] = make([]float64, m.dims)
+	}
+	return vectors, nil
+}
+
+func TestNewEmbedder(t *testing.T) {
+	r := NewReloadableRouter(newRouter([]Provider{
+		&mockProvider{name: "anthropic", enabled: true},
+		&mockEmbedProvider{mockProvider: mockProvider{name: "ollama", enabled: true}, dims: 2},
+	}, 0))
+
+	if _, err := NewEmbedder(r, "anthropic"); err == nil || !strings.Contains(err.Error(), "does not support embeddings") {
+		t.Errorf("expected an error for a provider without embeddings, got %v", err)
+	}
+	if _, err := NewEmbedder(r, "openai"); err == nil {
+		t.Error("expected an error for a provider that is not configured")
+	}
+
+	embedder, err := NewEmbedder(r, "ollama")
+	if err != nil {
+		t.Fatalf("NewEmbedder() returned error: %v", err)
+	}
+	if vectors, err := embedder.Embed(context.Background(), "", []string{"a"}); err != nil || len(vectors[0]) != 2 {
+		t.Fatalf("Embed() = %v, %v", vectors, err)
+	}
+
+	// The embedder follows the router when the config is reloaded.
+	r.Swap(newRouter([]Provider{&mockEmbedProvider{mockProvider: mockProvider{name: "ollama", enabled: true}, dims: 3}}, 0))
+	if vectors, err := embedder.Embed(context.Background(), "", []string{"a"}); err != nil || len(vectors[0]) != 3 {
+		t.Errorf("expected the reloaded provider to be used, got %v, %v", vectors, err)
+	}
+}
+
+func TestOpenRouterProvider_Embed(t *testing.T) {
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		path, body = r.URL.Path, string(data)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","model":"m","data":[{"object":"embedding","index":0,"embedding":[0.5,0.5]}]}`))
+	}))
+	defer server.Close()
+
+	p := &openRouterProvider{client: openai.NewClient(option.WithBaseURL(server.URL), option.WithAPIKey("test")), enabled: true}
+	vectors, err := p.Embed(context.Background(), "", []string{"hello"})
+	if err != nil {
+		t.Fatalf("Embed() returned error: %v", err)
+	}
+	if len(vectors) != 1 || vectors[0][0] != 0.5 {
+		t.Errorf("unexpected vectors %v", vectors)
+	}
+	if path != "/embeddings" || !strings.Contains(body, `"model":"openai/text-embedding-3-small"`) {
+		t.Errorf("expected the default model at /embeddings, got %s %s", path, body)
+	}
+}
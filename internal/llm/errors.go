@@ -0,0 +1,141 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/openai/openai-go/v3"
+)
+
+// ErrorClass groups provider failures by what the user can do about them.
+type ErrorClass string
+
+const (
+	ErrorUnknown       ErrorClass = ""
+	ErrorRateLimited   ErrorClass = "rate_limited"
+	ErrorAuth          ErrorClass = "auth"
+	ErrorContextLength ErrorClass = "context_length"
+	ErrorServer        ErrorClass = "server"
+)
+
+// ProviderError is returned by providers once retries are exhausted.
+type ProviderError struct {
+	Provider   string
+	Class      ErrorClass
+	StatusCode int
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	if e.Class == ErrorUnknown {
+		return fmt.Sprintf("%s: %v", e.Provider, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Provider, e.Class, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Classify returns the class of a provider error, or ErrorUnknown.
+func Classify(err error) ErrorClass {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Class
+	}
+	return classify(statusCode(err), err)
+}
+
+func classify(status int, err error) ErrorClass {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorAuth
+	case status == http.StatusRequestEntityTooLarge || (status != 0 && contextLengthExceeded(err)):
+		return ErrorContextLength
+	case status >= http.StatusInternalServerError:
+		return ErrorServer
+	}
+	return ErrorUnknown
+}
+
+var contextLengthMarkers = []string{
+	"context_length_exceeded",
+	"maximum context length",
+	"context window",
+	"prompt is too long",
+	"input is too long",
+	"too many tokens",
+	"maximum number of tokens",
+}
+
+func contextLengthExceeded(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range contextLengthMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func statusCode(err error) int {
+	var openaiErr *openai.Error
+	if errors.As(err, &openaiErr) {
+		return openaiErr.StatusCode
+	}
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		return anthropicErr.StatusCode
+	}
+	var ollamaErr *ollamaError
+	if errors.As(err, &ollamaErr) {
+		return ollamaErr.StatusCode
+	}
+	return 0
+}
+
+// retryAfter reads the Retry-After header of a rate limited response.
+func retryAfter(err error) time.Duration {
+	var resp *http.Response
+	var openaiErr *openai.Error
+	var anthropicErr *anthropic.Error
+	switch {
+	case errors.As(err, &openaiErr):
+		resp = openaiErr.Response
+	case errors.As(err, &anthropicErr):
+		resp = anthropicErr.Response
+	}
+	if resp == nil {
+		return 0
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// wrapProviderError attaches the provider name and error class to API
+// errors. Other errors are returned as they are.
+func wrapProviderError(provider string, err error) error {
+	var providerErr *ProviderError
+	if err == nil || errors.As(err, &providerErr) {
+		return err
+	}
+	status := statusCode(err)
+	if status == 0 {
+		return err
+	}
+	return &ProviderError{Provider: provider, Class: classify(status, err), StatusCode: status, Err: err}
+}
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/openai/openai-go/v3"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"rate limited", &ollamaError{StatusCode: http.StatusTooManyRequests}, ErrorRateLimited},
+		{"unauthorized", &openai.Error{StatusCode: http.StatusUnauthorized}, ErrorAuth},
+		{"forbidden", &ollamaError{StatusCode: http.StatusForbidden}, ErrorAuth},
+		{"context length", &ollamaError{StatusCode: http.StatusBadRequest, Message: "This model's maximum context length is 8192 tokens"}, ErrorContextLength},
+		{"too large", &ollamaError{StatusCode: http.StatusRequestEntityTooLarge}, ErrorContextLength},
+		{"server", &ollamaError{StatusCode: http.StatusBadGateway}, ErrorServer},
+		{"bad request", &ollamaError{StatusCode: http.StatusBadRequest, Message: "invalid model"}, ErrorUnknown},
+		{"wrapped", fmt.Errorf("send: %w", &ollamaError{StatusCode: http.StatusServiceUnavailable}), ErrorServer},
+		{"provider error", &ProviderError{Provider: "openai", Class: ErrorAuth, Err: errors.New("nope")}, ErrorAuth},
+		{"plain", errors.New("connection reset"), ErrorUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := &openai.Error{StatusCode: http.StatusTooManyRequests, Response: &http.Response{Header: http.Header{"Retry-After": {"3"}}}}
+	if got := retryAfter(err); got != 3*time.Second {
+		t.Errorf("retryAfter() = %s, want 3s", got)
+	}
+	if got := retryAfter(&ollamaError{StatusCode: http.StatusTooManyRequests}); got != 0 {
+		t.Errorf("retryAfter() = %s, want 0", got)
+	}
+}
+
+func TestRetryPolicy_WrapsClassifiedErrors(t *testing.T) {
+	p := newRetryPolicy("ollama", config.ProviderConfig{MaxRetries: retries(1), RetryBackoff: "1ms"})
+
+	attempts := 0
+	_, err := p.do(context.Background(), func(ctx context.Context) (string, error) {
+		attempts++
+		return "", &ollamaError{StatusCode: http.StatusUnauthorized, Message: "bad key"}
+	})
+
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Class != ErrorAuth || providerErr.Provider != "ollama" {
+		t.Fatalf("expected auth ProviderError, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected auth errors not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRetryPolicy_GivesUpOnLongRetryAfter(t *testing.T) {
+	p := newRetryPolicy("openai", config.ProviderConfig{MaxRetries: retries(3), RetryBackoff: "1ms"})
+
+	attempts := 0
+	_, err := p.do(context.Background(), func(ctx context.Context) (string, error) {
+		attempts++
+		return "", &openai.Error{StatusCode: http.StatusTooManyRequests, Response: &http.Response{Header: http.Header{"Retry-After": {"3600"}}}}
+	})
+
+	if Classify(err) != ErrorRateLimited {
+		t.Errorf("expected rate limited error, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected no retry when the provider asks to wait an hour, got %d attempts", attempts)
+	}
+}
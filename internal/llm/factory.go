@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jrswab/helpi/internal/config"
@@ -18,6 +19,8 @@ func NewProvider(cfg *config.Config, providerType string) (Provider, error) {
 		return NewOpenRouterProvider(cfg), nil
 	case "opencode":
 		return NewOpenCodeProvider(cfg), nil
+	case "local":
+		return NewLocalProvider(cfg), nil
 	default:
 		return nil, fmt.Errorf("unknown provider type: %s", providerType)
 	}
@@ -62,9 +65,103 @@ func NewRouter(cfg *config.Config) (Router, error) {
 		}
 	}
 
+	if cfg.Providers.Local.Enabled {
+		providers = append(providers, NewLocalProvider(cfg))
+		if defaultIdx == -1 {
+			defaultIdx = len(providers) - 1
+		}
+	}
+
+	for _, c := range cfg.Providers.OpenAICompatible {
+		if c.Enabled {
+			providers = append(providers, NewOpenAICompatibleProvider(c))
+			if defaultIdx == -1 {
+				defaultIdx = len(providers) - 1
+			}
+		}
+	}
+
 	if len(providers) == 0 {
 		return nil, fmt.Errorf("no LLM provider enabled")
 	}
 
-	return newRouter(providers, defaultIdx), nil
+	r := newRouter(providers, defaultIdx).(*router)
+	r.systemPrompts = map[string]string{
+		"openai":     cfg.Providers.OpenAI.SystemPrompt,
+		"anthropic":  cfg.Providers.Anthropic.SystemPrompt,
+		"ollama":     cfg.Providers.Ollama.SystemPrompt,
+		"openrouter": cfg.Providers.OpenRouter.SystemPrompt,
+		"opencode":   cfg.Providers.OpenCode.SystemPrompt,
+		"local":      cfg.Providers.Local.SystemPrompt,
+	}
+	for _, c := range cfg.Providers.OpenAICompatible {
+		r.systemPrompts[c.Name] = c.SystemPrompt
+	}
+
+	if cfg.Offline.Enabled {
+		r.offline = newOfflineFallback(providers, cfg.Offline)
+	}
+
+	rules, err := compileRules(cfg.Routing.Rules)
+	if err != nil {
+		return nil, err
+	}
+	r.rules = rules
+
+	redactor, err := compileRedactor(cfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+	r.redactor = redactor
+	r.access = compileAccess(cfg.ProviderAccess, cfg.Roles)
+
+	return r, nil
+}
+
+// routedEmbedder looks its provider up on every call, so it follows the
+// router across config reloads.
+type routedEmbedder struct {
+	router Router
+	name   string
+}
+
+// NewEmbedder returns an Embedder backed by the named provider of r. It
+// fails if the provider is not configured or cannot embed text.
+func NewEmbedder(r Router, name string) (Embedder, error) {
+	e := &routedEmbedder{router: r, name: name}
+	if _, err := e.provider(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *routedEmbedder) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	embedder, err := e.provider()
+	if err != nil {
+		return nil, err
+	}
+	if rd, ok := e.router.(Redactor); ok {
+		messages := make([]Message, len(texts))
+		for i, text := range texts {
+			messages[i] = Message{Role: "user", Content: text}
+		}
+		messages, _ = rd.Redact(e.name, messages)
+		texts = make([]string, len(messages))
+		for i, m := range messages {
+			texts[i] = m.Content
+		}
+	}
+	return embedder.Embed(ctx, model, texts)
+}
+
+func (e *routedEmbedder) provider() (Embedder, error) {
+	provider, err := e.router.GetProviderByName(e.name)
+	if err != nil {
+		return nil, err
+	}
+	embedder, ok := provider.(Embedder)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support embeddings", e.name)
+	}
+	return embedder, nil
 }
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/openai/openai-go/v3/shared"
+)
+
+const (
+	// Anthropic requires max_tokens on every request.
+	defaultAnthropicMaxTokens = 4096
+	// maxAnthropicTemperature is the highest temperature Anthropic accepts;
+	// higher ones, which other providers allow, are lowered to it.
+	maxAnthropicTemperature = 1.0
+)
+
+type temperatureKey struct{}
+
+func contextWithTemperature(ctx context.Context, t float64) context.Context {
+	return context.WithValue(ctx, temperatureKey{}, t)
+}
+
+// generationConfig applies per-request overrides carried in ctx.
+func generationConfig(ctx context.Context, cfg config.ProviderConfig) config.ProviderConfig {
+	if t, ok := ctx.Value(temperatureKey{}).(float64); ok {
+		cfg.Temperature = &t
+	}
+	return cfg
+}
+
+// Reasoning models reject sampling parameters, so those are dropped for
+// them in favour of reasoning_effort.
+func chatCompletionParams(model string, messages []Message, cfg config.ProviderConfig) openai.ChatCompletionNewParams {
+	params := openai.ChatCompletionNewParams{
+		Model:    shared.ChatModel(model),
+		Messages: toOpenAIMessages(messages),
+	}
+	if cfg.MaxTokens > 0 {
+		params.MaxTokens = openai.Int(int64(cfg.MaxTokens))
+	}
+	if isReasoningModel(model) {
+		params.ReasoningEffort = shared.ReasoningEffort(cfg.ReasoningEffort)
+		return params
+	}
+	if cfg.Temperature != nil {
+		params.Temperature = openai.Float(*cfg.Temperature)
+	}
+	if cfg.TopP != nil {
+		params.TopP = openai.Float(*cfg.TopP)
+	}
+	if cfg.FrequencyPenalty != nil {
+		params.FrequencyPenalty = openai.Float(*cfg.FrequencyPenalty)
+	}
+	return params
+}
+
+// The Responses API has no frequency penalty, so it is ignored there.
+func applyResponsesGeneration(params *responses.ResponseNewParams, model string, cfg config.ProviderConfig) {
+	if cfg.MaxTokens > 0 {
+		params.MaxOutputTokens = openai.Int(int64(cfg.MaxTokens))
+	}
+	if isReasoningModel(model) {
+		params.Reasoning = shared.ReasoningParam{Effort: shared.ReasoningEffort(cfg.ReasoningEffort)}
+		if cfg.ShowReasoning {
+			params.Reasoning.Summary = shared.ReasoningSummaryAuto
+		}
+		return
+	}
+	if cfg.Temperature != nil {
+		params.Temperature = openai.Float(*cfg.Temperature)
+	}
+	if cfg.TopP != nil {
+		params.TopP = openai.Float(*cfg.TopP)
+	}
+}
+
+// Anthropic has no frequency penalty, so it is ignored there. Extended
+// thinking does not allow changing temperature or top_p, and the default
+// max_tokens is raised to leave room for the answer after the thinking
+// budget.
+func applyAnthropicGeneration(params *anthropic.MessageNewParams, cfg config.ProviderConfig) {
+	params.MaxTokens = defaultAnthropicMaxTokens
+	if cfg.MaxTokens > 0 {
+		params.MaxTokens = int64(cfg.MaxTokens)
+	}
+	if cfg.ThinkingBudget > 0 {
+		params.Thinking = anthropic.ThinkingConfigParamOfEnabled(int64(cfg.ThinkingBudget))
+		if params.MaxTokens <= int64(cfg.ThinkingBudget) {
+			params.MaxTokens = int64(cfg.ThinkingBudget) + defaultAnthropicMaxTokens
+		}
+		return
+	}
+	if cfg.Temperature != nil {
+		params.Temperature = anthropic.Float(min(*cfg.Temperature, maxAnthropicTemperature))
+	}
+	if cfg.TopP != nil {
+		params.TopP = anthropic.Float(*cfg.TopP)
+	}
+}
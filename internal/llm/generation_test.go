@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func float(v float64) *float64 { return &v }
+
+func TestChatCompletionParams_Generation(t *testing.T) {
+	cfg := config.ProviderConfig{Temperature: float(0), TopP: float(0.9), FrequencyPenalty: float(0.5), MaxTokens: 2000}
+	data, err := json.Marshal(chatCompletionParams("m", []Message{{Role: "user", Content: "hi"}}, cfg))
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	for _, want := range []string{`"temperature":0`, `"top_p":0.9`, `"frequency_penalty":0.5`, `"max_tokens":2000`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in %s", want, data)
+		}
+	}
+
+	data, _ = json.Marshal(chatCompletionParams("m", nil, config.ProviderConfig{}))
+	if strings.Contains(string(data), "temperature") || strings.Contains(string(data), "max_tokens") {
+		t.Errorf("expected unset parameters to be omitted, got %s", data)
+	}
+}
+
+func TestOpenAIChatParams_UsesMaxCompletionTokens(t *testing.T) {
+	p := &openAIProvider{providerCfg: config.ProviderConfig{MaxTokens: 500}}
+	data, _ := json.Marshal(p.chatParams(context.Background(), "gpt-4o", nil))
+	if !strings.Contains(string(data), `"max_completion_tokens":500`) || strings.Contains(string(data), `"max_tokens"`) {
+		t.Errorf("unexpected params %s", data)
+	}
+}
+
+func TestGenerationConfig_TemperatureOverride(t *testing.T) {
+	cfg := config.ProviderConfig{Temperature: float(0.2)}
+	got := generationConfig(contextWithTemperature(context.Background(), 1.1), cfg)
+	if got.Temperature == nil || *got.Temperature != 1.1 || *cfg.Temperature != 0.2 {
+		t.Errorf("expected override without mutating config, got %v", got.Temperature)
+	}
+}
+
+func TestAnthropicBuildParams_Generation(t *testing.T) {
+	p := &anthropicProvider{}
+	if got := p.buildParams("claude", p.providerCfg, nil).MaxTokens; got != defaultAnthropicMaxTokens {
+		t.Errorf("expected default max tokens %d, got %d", defaultAnthropicMaxTokens, got)
+	}
+
+	p.providerCfg = config.ProviderConfig{MaxTokens: 8000, Temperature: float(0.2)}
+	params := p.buildParams("claude", p.providerCfg, nil)
+	if params.MaxTokens != 8000 || params.Temperature.Value != 0.2 {
+		t.Errorf("unexpected params max_tokens=%d temperature=%v", params.MaxTokens, params.Temperature)
+	}
+
+	params = p.buildParams("claude", config.ProviderConfig{Temperature: float(1.5)}, nil)
+	if params.Temperature.Value != maxAnthropicTemperature {
+		t.Errorf("expected temperature to be lowered to %v, got %v", maxAnthropicTemperature, params.Temperature.Value)
+	}
+}
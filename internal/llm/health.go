@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout bounds the checks of a single provider.
+const healthCheckTimeout = 30 * time.Second
+
+var healthCheckPrompt = []Message{{Role: "user", Content: "Reply with OK."}}
+
+// ProviderHealth is the result of checking one provider.
+type ProviderHealth struct {
+	Name    string
+	Model   string
+	Up      bool
+	Latency time.Duration
+	Err     error
+
+	// LastError is the most recent failure of a real request, kept so a
+	// provider that recovered for the check still shows why it failed.
+	LastError   string
+	LastErrorAt time.Time
+}
+
+// HealthChecker is implemented by routers that can check every enabled
+// provider on demand.
+type HealthChecker interface {
+	CheckProviders(ctx context.Context) []ProviderHealth
+}
+
+// CheckProvider pings provider to verify its credentials and then sends a
+// minimal prompt. Latency is the time the prompt took to answer.
+func CheckProvider(ctx context.Context, provider Provider) ProviderHealth {
+	health := ProviderHealth{Name: provider.Name()}
+	if mp, ok := provider.(ModelProvider); ok {
+		health.Model = mp.Model()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if pinger, ok := provider.(Pinger); ok {
+		if err := pinger.Ping(ctx); err != nil {
+			health.Err = err
+			return health
+		}
+	}
+
+	start := time.Now()
+	resp, err := provider.SendMessage(ctx, healthCheckPrompt)
+	health.Latency = time.Since(start)
+	switch {
+	case err != nil:
+		health.Err = err
+	case strings.TrimSpace(resp) == "":
+		health.Err = fmt.Errorf("%s: empty response", provider.Name())
+	default:
+		health.Up = true
+	}
+	return health
+}
+
+type providerFailure struct {
+	err string
+	at  time.Time
+}
+
+func (r *router) recordFailure(provider string, err error) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures == nil {
+		r.failures = make(map[string]providerFailure)
+	}
+	r.failures[provider] = providerFailure{err: err.Error(), at: time.Now()}
+}
+
+// CheckProviders checks every enabled provider concurrently.
+func (r *router) CheckProviders(ctx context.Context) []ProviderHealth {
+	results := make([]ProviderHealth, len(r.providers))
+	var wg sync.WaitGroup
+	for i, p := range r.providers {
+		if !p.IsEnabled() {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, p Provider) {
+			defer wg.Done()
+			results[i] = CheckProvider(ctx, p)
+		}(i, p)
+	}
+	wg.Wait()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	checked := make([]ProviderHealth, 0, len(results))
+	for _, health := range results {
+		if health.Name == "" {
+			continue
+		}
+		if failure, ok := r.failures[health.Name]; ok {
+			health.LastError = failure.err
+			health.LastErrorAt = failure.at
+		}
+		checked = append(checked, health)
+	}
+	return checked
+}
+
+func (r *ReloadableRouter) CheckProviders(ctx context.Context) []ProviderHealth {
+	if checker, ok := r.router().(HealthChecker); ok {
+		return checker.CheckProviders(ctx)
+	}
+	return nil
+}
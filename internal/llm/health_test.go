@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckProviders(t *testing.T) {
+	failing := &mockProvider{name: "anthropic", enabled: true, response: "OK"}
+	r := newRouter([]Provider{
+		&mockProvider{name: "openai", enabled: true, response: "OK"},
+		failing,
+		&mockProvider{name: "ollama", enabled: false},
+	}, 0)
+
+	failing.err = errors.New("invalid api key")
+	if _, err := r.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}}, WithProvider("anthropic")); err == nil {
+		t.Fatal("expected the request to fail")
+	}
+
+	results := r.(HealthChecker).CheckProviders(context.Background())
+	if len(results) != 2 {
+		t.Fatalf("expected only enabled providers to be checked, got %+v", results)
+	}
+	if !results[0].Up || results[0].Name != "openai" || results[0].LastError != "" {
+		t.Errorf("expected openai to be up, got %+v", results[0])
+	}
+	if results[1].Up || results[1].Err == nil || results[1].LastError != "invalid api key" {
+		t.Errorf("expected anthropic to be down with its last error, got %+v", results[1])
+	}
+}
+
+func TestCheckProvider_EmptyResponse(t *testing.T) {
+	health := CheckProvider(context.Background(), &mockProvider{name: "openai", enabled: true})
+	if health.Up || health.Err == nil {
+		t.Errorf("expected an empty answer to count as down, got %+v", health)
+	}
+}
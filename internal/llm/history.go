@@ -0,0 +1,107 @@
+package llm
+
+// SplitHistory returns the index where the most recent keep messages of
+// history begin, moved back so the kept part opens with a user message.
+// Everything before the index can be dropped or summarized.
+func SplitHistory(history []Message, keep int) int {
+	split := len(history) - keep
+	for split > 0 && split < len(history) && history[split].Role != "user" {
+		split--
+	}
+	if split < 0 {
+		return 0
+	}
+	return split
+}
+
+// SplitPinned separates the pinned messages of history from the others,
+// keeping their order.
+func SplitPinned(history []Message) (pinned, rest []Message) {
+	for _, m := range history {
+		if m.Pinned {
+			pinned = append(pinned, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+	return pinned, rest
+}
+
+// TrimHistory drops the oldest messages of history that are not pinned until
+// at most max remain, or only pinned messages are left. A max of zero or less
+// keeps everything.
+func TrimHistory(history []Message, max int) []Message {
+	drop := len(history) - max
+	if max <= 0 || drop <= 0 {
+		return history
+	}
+	result := make([]Message, 0, max)
+	for _, m := range history {
+		if drop > 0 && !m.Pinned {
+			drop--
+			continue
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+// DropOldest keeps at least the most recent keep messages of history, along
+// with a leading summary and pinned messages.
+func DropOldest(history []Message, keep int) []Message {
+	var summary []Message
+	rest := history
+	if len(rest) > 0 && IsSummary(rest[0]) {
+		summary, rest = rest[:1], rest[1:]
+	}
+
+	split := SplitHistory(rest, keep)
+	if split == 0 {
+		return history
+	}
+	pinned, _ := SplitPinned(rest[:split])
+	result := make([]Message, 0, len(summary)+len(pinned)+len(rest)-split)
+	result = append(result, summary...)
+	result = append(result, pinned...)
+	return append(result, rest[split:]...)
+}
+
+// TokenWindow keeps the most recent messages of history that fit in limit
+// tokens as counted for provider, along with a leading summary and pinned
+// messages, which are counted first. The window opens with a user message so no answer is sent without
+// its question. A limit of zero or less disables the window.
+func TokenWindow(history []Message, limit int, provider string) []Message {
+	if limit <= 0 || CountTokensFor(provider, history) <= limit {
+		return history
+	}
+
+	var summary []Message
+	rest := history
+	if len(rest) > 0 && IsSummary(rest[0]) {
+		summary, rest = rest[:1], rest[1:]
+		limit -= CountTokensFor(provider, summary)
+	}
+	pinned, _ := SplitPinned(rest)
+	limit -= CountTokensFor(provider, pinned)
+
+	start := len(rest)
+	used := 0
+	for start > 0 {
+		if !rest[start-1].Pinned {
+			used += CountTokensFor(provider, rest[start-1:start])
+		}
+		if used > limit {
+			break
+		}
+		start--
+	}
+	for start < len(rest) && rest[start].Role != "user" {
+		start++
+	}
+
+	pinned, _ = SplitPinned(rest[:start])
+	result := make([]Message, 0, len(summary)+len(pinned)+len(rest)-start)
+	result = append(result, summary...)
+	result = append(result, pinned...)
+	return append(result, rest[start:]...)
+}
@@ -0,0 +1,122 @@
+package llm
+
+import "testing"
+
+func TestDropOldest(t *testing.T) {
+	history := []Message{
+		SummaryMessage("earlier"),
+		{Role: "user", Content: "1"},
+		{Role: "assistant", Content: "a"},
+		{Role: "user", Content: "2"},
+		{Role: "assistant", Content: "b"},
+		{Role: "user", Content: "3"},
+		{Role: "assistant", Content: "c"},
+	}
+
+	got := DropOldest(history, 2)
+	if len(got) != 3 || !IsSummary(got[0]) || got[1].Content != "3" {
+		t.Errorf("expected summary plus the last turn, got %+v", got)
+	}
+
+	got = DropOldest(history, 3)
+	if len(got) != 5 || got[1].Content != "2" {
+		t.Errorf("expected the kept part to start at a user message, got %+v", got)
+	}
+
+	if got := DropOldest(history[:3], 2); len(got) != 3 {
+		t.Errorf("expected history without an earlier user turn to be kept, got %+v", got)
+	}
+}
+
+func TestSplitHistory(t *testing.T) {
+	history := []Message{
+		{Role: "user", Content: "1"},
+		{Role: "assistant", Content: "a"},
+		{Role: "user", Content: "2"},
+		{Role: "assistant", Content: "b"},
+	}
+	tests := []struct {
+		keep int
+		want int
+	}{
+		{keep: 2, want: 2},
+		{keep: 1, want: 2},
+		{keep: 3, want: 0},
+		{keep: 0, want: 4},
+		{keep: 10, want: 0},
+	}
+	for _, tt := range tests {
+		if got := SplitHistory(history, tt.keep); got != tt.want {
+			t.Errorf("SplitHistory(keep=%d) = %d, want %d", tt.keep, got, tt.want)
+		}
+	}
+}
+
+func TestTokenWindow(t *testing.T) {
+	history := []Message{
+		SummaryMessage("earlier"),
+		{Role: "user", Content: "first question"},
+		{Role: "assistant", Content: "a long answer that goes on for quite a few words"},
+		{Role: "user", Content: "second"},
+		{Role: "assistant", Content: "short"},
+	}
+
+	limit := CountTokens([]Message{history[0], history[3], history[4]})
+	got := TokenWindow(history, limit, "")
+	if len(got) != 3 || !IsSummary(got[0]) || got[1].Content != "second" {
+		t.Errorf("expected summary plus the last turn, got %+v", got)
+	}
+
+	got = TokenWindow(history, limit+CountTokens(history[2:3]), "")
+	if len(got) != 3 || got[1].Content != "second" {
+		t.Errorf("expected the window to start at a user message, got %+v", got)
+	}
+
+	if got := TokenWindow(history, limit, "anthropic"); len(got) != 1 {
+		t.Errorf("expected anthropic's larger token count to leave only the summary, got %+v", got)
+	}
+
+	if got := TokenWindow(history, 0, ""); len(got) != len(history) {
+		t.Errorf("expected no window without a limit, got %+v", got)
+	}
+}
+
+func TestTrimHistory_KeepsPinned(t *testing.T) {
+	history := []Message{
+		{Role: "user", Content: "1", Pinned: true},
+		{Role: "assistant", Content: "a"},
+		{Role: "user", Content: "2"},
+		{Role: "assistant", Content: "b"},
+		{Role: "user", Content: "3"},
+	}
+
+	got := TrimHistory(history, 3)
+	if len(got) != 3 || got[0].Content != "1" || got[1].Content != "b" || got[2].Content != "3" {
+		t.Errorf("expected the pinned message plus the 2 newest, got %+v", got)
+	}
+
+	if got := TrimHistory(history[:1], 0); len(got) != 1 {
+		t.Errorf("expected no trimming without a limit, got %+v", got)
+	}
+}
+
+func TestWindows_KeepPinned(t *testing.T) {
+	history := []Message{
+		SummaryMessage("earlier"),
+		{Role: "user", Content: "remember my name is Ada", Pinned: true},
+		{Role: "assistant", Content: "a long answer that goes on for quite a few words"},
+		{Role: "user", Content: "second"},
+		{Role: "assistant", Content: "short"},
+	}
+
+	got := DropOldest(history, 2)
+	if len(got) != 4 || !got[1].Pinned || got[2].Content != "second" {
+		t.Errorf("expected summary, pin and the last turn, got %+v", got)
+	}
+
+	limit := CountTokens([]Message{history[0], history[1], history[3], history[4]})
+	got = TokenWindow(history, limit, "")
+	if len(got) != 4 || !got[1].Pinned || got[2].Content != "second" {
+		t.Errorf("expected summary, pin and the last turn, got %+v", got)
+	}
+}
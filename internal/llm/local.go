@@ -0,0 +1,243 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+)
+
+const localProbeTimeout = 2 * time.Second
+
+// LocalServer is an OpenAI-compatible server running on this machine.
+type LocalServer struct {
+	Name    string
+	BaseURL string
+	Models  []string
+}
+
+// localServers are the runtimes DiscoverLocalServers looks for, in order of
+// preference.
+var localServers = []LocalServer{
+	{Name: "LM Studio", BaseURL: "http://localhost:1234/v1"},
+	{Name: "llama.cpp", BaseURL: "http://localhost:8080/v1"},
+	{Name: "Ollama", BaseURL: "http://localhost:11434/v1"},
+}
+
+// DiscoverLocalServers probes the usual ports of LM Studio, llama.cpp and
+// Ollama and returns the servers that answered, with their models.
+func DiscoverLocalServers(ctx context.Context, client *http.Client) []LocalServer {
+	return discoverLocalServers(ctx, client, localServers)
+}
+
+func discoverLocalServers(ctx context.Context, client *http.Client, candidates []LocalServer) []LocalServer {
+	ctx, cancel := context.WithTimeout(ctx, localProbeTimeout)
+	defer cancel()
+
+	found := make([]*LocalServer, len(candidates))
+	var wg sync.WaitGroup
+	for i, c := range candidates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			models, err := listLocalModels(ctx, client, c.BaseURL)
+			if err != nil {
+				return
+			}
+			c.Models = models
+			found[i] = &c
+		}()
+	}
+	wg.Wait()
+
+	var servers []LocalServer
+	for _, s := range found {
+		if s != nil {
+			servers = append(servers, *s)
+		}
+	}
+	return servers
+}
+
+// listLocalModels lists the models of the OpenAI-compatible server at
+// baseURL.
+func listLocalModels(ctx context.Context, client *http.Client, baseURL string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("unexpected model list: %w", err)
+	}
+	models := make([]string, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, m.ID)
+	}
+	return models, nil
+}
+
+type localProvider struct {
+	httpClient  *http.Client
+	baseURL     string
+	candidates  []LocalServer
+	model       string
+	enabled     bool
+	providerCfg config.ProviderConfig
+	retry       retryPolicy
+
+	mu        sync.Mutex
+	client    openai.Client
+	serverURL string
+	found     string
+}
+
+// NewLocalProvider returns a provider for the server at LOCAL_BASE_URL or,
+// without it, for the first local server that answers. Without a default
+// model the server's first model is used.
+func NewLocalProvider(cfg *config.Config) Provider {
+	return &localProvider{
+		httpClient:  newHTTPClient(cfg.Providers.Local),
+		baseURL:     strings.TrimRight(cfg.APIKeys["LOCAL_BASE_URL"], "/"),
+		candidates:  localServers,
+		model:       cfg.Providers.Local.DefaultModel,
+		enabled:     cfg.Providers.Local.Enabled,
+		providerCfg: cfg.Providers.Local,
+		retry:       newRetryPolicy("local", cfg.Providers.Local),
+	}
+}
+
+func (p *localProvider) Name() string {
+	return "local"
+}
+
+func (p *localProvider) Model() string {
+	if p.model != "" {
+		return p.model
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.found
+}
+
+func (p *localProvider) IsEnabled() bool {
+	return p.enabled
+}
+
+// connect finds the server to talk to and the model to use, once. A failed
+// request forgets them so the next one looks again.
+func (p *localProvider) connect(ctx context.Context) (openai.Client, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.serverURL != "" {
+		return p.client, p.serverURL, nil
+	}
+
+	serverURL, models := p.baseURL, []string(nil)
+	if serverURL == "" {
+		servers := discoverLocalServers(ctx, p.httpClient, p.candidates)
+		if len(servers) == 0 {
+			return openai.Client{}, "", fmt.Errorf("no local server found")
+		}
+		serverURL, models = servers[0].BaseURL, servers[0].Models
+	}
+	if p.model == "" {
+		if models == nil {
+			var err error
+			if models, err = listLocalModels(ctx, p.httpClient, serverURL); err != nil {
+				return openai.Client{}, "", err
+			}
+		}
+		if len(models) == 0 {
+			return openai.Client{}, "", fmt.Errorf("no model is loaded on %s", serverURL)
+		}
+		p.found = models[0]
+	}
+
+	p.client = openai.NewClient(
+		option.WithBaseURL(serverURL),
+		option.WithAPIKey("none"),
+		option.WithMaxRetries(0),
+		option.WithHTTPClient(p.httpClient),
+	)
+	p.serverURL = serverURL
+	return p.client, serverURL, nil
+}
+
+func (p *localProvider) forget() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.serverURL, p.found = "", ""
+}
+
+func (p *localProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	return p.retry.do(ctx, func(ctx context.Context) (string, error) {
+		return p.send(ctx, messages)
+	})
+}
+
+func (p *localProvider) send(ctx context.Context, messages []Message) (string, error) {
+	if !p.enabled {
+		return "", fmt.Errorf("local: provider not enabled")
+	}
+
+	client, _, err := p.connect(ctx)
+	if err != nil {
+		return "", fmt.Errorf("local: %w", err)
+	}
+	resp, err := client.Chat.Completions.New(ctx, chatCompletionParams(modelFromContext(ctx, p.Model()), messages, generationConfig(ctx, p.providerCfg)))
+	if err != nil {
+		p.forget()
+		return "", fmt.Errorf("local: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", nil
+	}
+
+	reportTruncation(ctx, resp.Choices[0].FinishReason == finishReasonLength)
+	return applyThink(ctx, p.providerCfg.Think, resp.Choices[0].Message.Content), nil
+}
+
+func (p *localProvider) Ping(ctx context.Context) error {
+	_, err := p.ListModels(ctx)
+	return err
+}
+
+// ListModels returns the models of the local server in use.
+func (p *localProvider) ListModels(ctx context.Context) ([]string, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("local: provider not enabled")
+	}
+
+	_, serverURL, err := p.connect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("local: %w", err)
+	}
+	models, err := listLocalModels(ctx, p.httpClient, serverURL)
+	if err != nil {
+		p.forget()
+		return nil, fmt.Errorf("local: %w", err)
+	}
+	return models, nil
+}
@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func newLocalServer(t *testing.T, models ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/models":
+			data := make([]map[string]string, 0, len(models))
+			for _, m := range models {
+				data = append(data, map[string]string{"id": m})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": data})
+		case "/v1/chat/completions":
+			var req struct {
+				Model string `json:"model"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"answered by %s"}}]}`, req.Model)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDiscoverLocalServers(t *testing.T) {
+	lmStudio := newLocalServer(t, "qwen2.5-7b-instruct")
+	notOpenAI := httptest.NewServer(http.NotFoundHandler())
+	defer notOpenAI.Close()
+
+	servers := discoverLocalServers(context.Background(), http.DefaultClient, []LocalServer{
+		{Name: "llama.cpp", BaseURL: notOpenAI.URL + "/v1"},
+		{Name: "LM Studio", BaseURL: lmStudio.URL + "/v1"},
+		{Name: "Ollama", BaseURL: "http://127.0.0.1:1/v1"},
+	})
+	if len(servers) != 1 || servers[0].Name != "LM Studio" || len(servers[0].Models) != 1 || servers[0].Models[0] != "qwen2.5-7b-instruct" {
+		t.Errorf("unexpected servers %+v", servers)
+	}
+}
+
+func TestLocalProvider_DiscoversServerAndModel(t *testing.T) {
+	server := newLocalServer(t, "llama-3.2-3b", "qwen2.5-7b")
+	p := NewLocalProvider(&config.Config{Providers: config.ProvidersConfig{Local: config.ProviderConfig{Enabled: true}}}).(*localProvider)
+	p.candidates = []LocalServer{{Name: "down", BaseURL: "http://127.0.0.1:1/v1"}, {Name: "test", BaseURL: server.URL + "/v1"}}
+
+	resp, err := p.SendMessage(context.Background(), []Message{{Role: "user", Content: "Hello"}})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if resp != "answered by llama-3.2-3b" {
+		t.Errorf("SendMessage() = %q", resp)
+	}
+	if p.Model() != "llama-3.2-3b" {
+		t.Errorf("Model() = %q", p.Model())
+	}
+}
+
+func TestLocalProvider_BaseURL(t *testing.T) {
+	server := newLocalServer(t, "llama-3.2-3b")
+	p := NewLocalProvider(&config.Config{
+		Providers: config.ProvidersConfig{Local: config.ProviderConfig{Enabled: true, DefaultModel: "mistral"}},
+		APIKeys:   map[string]string{"LOCAL_BASE_URL": server.URL + "/v1/"},
+	}).(*localProvider)
+	p.candidates = nil
+
+	resp, err := p.SendMessage(context.Background(), []Message{{Role: "user", Content: "Hello"}})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if resp != "answered by mistral" {
+		t.Errorf("SendMessage() = %q", resp)
+	}
+	models, err := p.ListModels(context.Background())
+	if err != nil || len(models) != 1 {
+		t.Errorf("ListModels() = %v, %v", models, err)
+	}
+}
+
+func TestLocalProvider_NoServer(t *testing.T) {
+	p := NewLocalProvider(&config.Config{Providers: config.ProvidersConfig{Local: config.ProviderConfig{Enabled: true}}}).(*localProvider)
+	p.candidates = []LocalServer{{Name: "down", BaseURL: "http://127.0.0.1:1/v1"}}
+
+	if err := p.Ping(context.Background()); err == nil {
+		t.Error("expected an error without a local server")
+	}
+}
@@ -0,0 +1,54 @@
+package llm
+
+import "context"
+
+// Usage is the estimated size of one answered request.
+type Usage struct {
+	Provider         string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Meter charges requests against a budget. Allow is asked before a request
+// for userID is sent, with 0 for requests no user made, and Charge is told
+// what an answered request used.
+type Meter interface {
+	Allow(userID int64) error
+	Charge(userID int64, u Usage)
+}
+
+// SetMeter charges every request sent through r to m, refusing requests m
+// does not allow. A nil m turns metering off.
+func (r *ReloadableRouter) SetMeter(m Meter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.meter = m
+}
+
+func (r *ReloadableRouter) sendMetered(ctx context.Context, m Meter, messages []Message, opts []RequestOption) (string, error) {
+	o := newRequestOptions(opts)
+	if err := m.Allow(o.userID); err != nil {
+		return "", err
+	}
+
+	var route Route
+	report := o.onRoute
+	opts = append(opts, WithRouteReport(func(rt Route) {
+		route = rt
+		if report != nil {
+			report(rt)
+		}
+	}))
+
+	response, err := r.router().SendMessage(ctx, messages, opts...)
+	if err == nil && !route.Offline {
+		m.Charge(o.userID, Usage{
+			Provider:         route.Provider,
+			Model:            route.Model,
+			PromptTokens:     CountTokensFor(route.Provider, messages),
+			CompletionTokens: EstimateTokens(response),
+		})
+	}
+	return response, err
+}
@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+// offlineProbeTimeout bounds the probe of a single provider.
+const offlineProbeTimeout = 10 * time.Second
+
+// localProviders run next to the bot, so they count as reachable whenever
+// the network is down.
+var localProviders = map[string]bool{"ollama": true, "local": true}
+
+// Prober is implemented by routers that track which providers can be
+// reached.
+type Prober interface {
+	ProbeProviders(ctx context.Context)
+}
+
+// offlineFallback answers with a local provider while a cloud provider
+// cannot be reached. A provider is marked unreachable when a request to it
+// fails without a response, and reachable again once a probe gets through.
+type offlineFallback struct {
+	provider Provider
+	model    string
+
+	mu          sync.RWMutex
+	unreachable map[string]bool
+}
+
+func newOfflineFallback(providers []Provider, cfg config.OfflineConfig) *offlineFallback {
+	for _, p := range providers {
+		if p.Name() == cfg.Provider && p.IsEnabled() {
+			return &offlineFallback{provider: p, model: cfg.Model, unreachable: make(map[string]bool)}
+		}
+	}
+	log.Printf("Offline fallback provider %s is not enabled, offline mode is off", cfg.Provider)
+	return nil
+}
+
+// replaces reports whether requests for p go to the fallback instead.
+func (f *offlineFallback) replaces(p Provider) bool {
+	if f == nil || localProviders[p.Name()] {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.unreachable[p.Name()]
+}
+
+// failed marks p unreachable when err means no response came back, and
+// reports whether it did.
+func (f *offlineFallback) failed(p Provider, err error) bool {
+	if f == nil || localProviders[p.Name()] || !unreachable(err) {
+		return false
+	}
+	f.set(p.Name(), false)
+	return true
+}
+
+func (f *offlineFallback) set(name string, reachable bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.unreachable[name] == !reachable {
+		return
+	}
+	f.unreachable[name] = !reachable
+	if reachable {
+		log.Printf("Provider %s is reachable again", name)
+	} else {
+		log.Printf("Provider %s is unreachable, answering with %s until it is back", name, f.provider.Name())
+	}
+}
+
+// unreachable reports whether err is a failure to reach a provider at all,
+// as opposed to an error the provider answered with.
+func unreachable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// ProbeProviders pings every enabled cloud provider to learn which can be
+// reached. It does nothing without an offline fallback.
+func (r *router) ProbeProviders(ctx context.Context) {
+	if r.offline == nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, p := range r.providers {
+		pinger, ok := p.(Pinger)
+		if !ok || !p.IsEnabled() || localProviders[p.Name()] {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, offlineProbeTimeout)
+			defer cancel()
+			err := pinger.Ping(ctx)
+			if !errors.Is(err, context.Canceled) {
+				r.offline.set(p.Name(), !unreachable(err))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *ReloadableRouter) ProbeProviders(ctx context.Context) {
+	if prober, ok := r.router().(Prober); ok {
+		prober.ProbeProviders(ctx)
+	}
+}
+
+// WatchReachability probes r's providers right away and then every
+// interval until ctx is done.
+func WatchReachability(ctx context.Context, r Prober, interval time.Duration) {
+	r.ProbeProviders(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.ProbeProviders(ctx)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+var errNetworkDown = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("network is unreachable")}
+
+func newOfflineRouter(cloud *mockPingProvider) (*router, *recordingProvider) {
+	ollama := &recordingProvider{mockProvider: mockProvider{name: "ollama", enabled: true}}
+	r := newRouter([]Provider{cloud, ollama}, 0).(*router)
+	r.offline = newOfflineFallback(r.providers, config.OfflineConfig{Provider: "ollama", Model: "llama3.2"})
+	return r, ollama
+}
+
+func TestSendMessage_OfflineFallbackAfterNetworkError(t *testing.T) {
+	cloud := &mockPingProvider{mockProvider: mockProvider{name: "openai", enabled: true, err: &ProviderError{Provider: "openai", Err: errNetworkDown}}}
+	r, ollama := newOfflineRouter(cloud)
+
+	var route Route
+	resp, err := r.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}}, WithRouteReport(func(rt Route) { route = rt }))
+	if err != nil || resp != "ollama" {
+		t.Fatalf("SendMessage() = %q, %v", resp, err)
+	}
+	if !route.Offline || route.Provider != "ollama" || ollama.lastModel != "llama3.2" {
+		t.Errorf("unexpected route %+v with model %q", route, ollama.lastModel)
+	}
+
+	// Later requests skip the unreachable provider until a probe gets through.
+	cloud.err, cloud.response = nil, "openai"
+	if resp, _ := r.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}}); resp != "ollama" {
+		t.Errorf("expected the fallback while offline, got %q", resp)
+	}
+	r.ProbeProviders(context.Background())
+	if resp, _ := r.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}}); resp != "openai" {
+		t.Errorf("expected the cloud provider once reachable, got %q", resp)
+	}
+}
+
+func TestSendMessage_OfflineFallbackIgnoresAPIErrors(t *testing.T) {
+	cloud := &mockPingProvider{mockProvider: mockProvider{name: "openai", enabled: true, err: &ProviderError{Provider: "openai", Class: ErrorAuth, StatusCode: 401, Err: errors.New("bad key")}}}
+	r, _ := newOfflineRouter(cloud)
+
+	if _, err := r.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}}); err == nil {
+		t.Error("expected the provider's error")
+	}
+	if r.offline.replaces(cloud) {
+		t.Error("a provider that answered should not be marked unreachable")
+	}
+}
+
+func TestProbeProviders(t *testing.T) {
+	cloud := &mockPingProvider{mockProvider: mockProvider{name: "openai", enabled: true}, pingErr: errNetworkDown}
+	r, _ := newOfflineRouter(cloud)
+
+	r.ProbeProviders(context.Background())
+	if !r.offline.replaces(cloud) {
+		t.Error("expected a failed probe to mark the provider unreachable")
+	}
+	cloud.pingErr = errors.New("401 Unauthorized")
+	r.ProbeProviders(context.Background())
+	if r.offline.replaces(cloud) {
+		t.Error("expected an answered probe to mark the provider reachable")
+	}
+}
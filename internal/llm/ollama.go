@@ -1,84 +1,279 @@
 package llm
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/jrswab/helpi/internal/config"
-	"github.com/openai/openai-go/v3"
-	"github.com/openai/openai-go/v3/option"
-	"github.com/openai/openai-go/v3/shared"
 )
 
+const defaultOllamaURL = "http://localhost:11434"
+
 type ollamaProvider struct {
-	client  openai.Client
-	model   string
-	baseURL string
-	enabled bool
+	httpClient  *http.Client
+	model       string
+	baseURL     string
+	enabled     bool
+	providerCfg config.ProviderConfig
+	retry       retryPolicy
 }
 
-func NewOllamaProvider(cfg *config.Config) Provider {
-	enabled := cfg.Providers.Ollama.Enabled
+// ollamaError is a non-2xx reply from the Ollama API.
+type ollamaError struct {
+	StatusCode int
+	Message    string
+}
 
-	baseURL := cfg.Providers.Ollama.DefaultModel
-	if baseURL == "" {
-		baseURL = "http://localhost:11434/v1"
+func (e *ollamaError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("status %d", e.StatusCode)
 	}
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Message)
+}
 
-	var client openai.Client
-	if enabled {
-		client = openai.NewClient(
-			option.WithBaseURL(baseURL),
-			option.WithAPIKey("ollama"),
-		)
-	}
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
 
+type ollamaChatRequest struct {
+	Model     string          `json:"model"`
+	Messages  []ollamaMessage `json:"messages"`
+	Stream    bool            `json:"stream"`
+	KeepAlive any             `json:"keep_alive,omitempty"`
+	Options   map[string]any  `json:"options,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Message    ollamaMessage `json:"message"`
+	DoneReason string        `json:"done_reason"`
+}
+
+type ollamaEmbedRequest struct {
+	Model     string   `json:"model"`
+	Input     []string `json:"input"`
+	KeepAlive any      `json:"keep_alive,omitempty"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+func NewOllamaProvider(cfg *config.Config) Provider {
 	return &ollamaProvider{
-		client:  client,
-		model:   cfg.Providers.Ollama.DefaultModel,
-		baseURL: baseURL,
-		enabled: enabled,
+		httpClient:  newHTTPClient(cfg.Providers.Ollama),
+		model:       cfg.Providers.Ollama.DefaultModel,
+		baseURL:     ollamaBaseURL(cfg.APIKeys["OLLAMA_BASE_URL"]),
+		enabled:     cfg.Providers.Ollama.Enabled,
+		providerCfg: cfg.Providers.Ollama,
+		retry:       newRetryPolicy("ollama", cfg.Providers.Ollama),
 	}
 }
 
+// ollamaBaseURL normalises OLLAMA_BASE_URL. A trailing /v1 left over from
+// the OpenAI-compatible endpoint is dropped so old settings keep working.
+func ollamaBaseURL(raw string) string {
+	url := strings.TrimRight(strings.TrimSpace(raw), "/")
+	url = strings.TrimSuffix(url, "/v1")
+	if url == "" {
+		return defaultOllamaURL
+	}
+	return url
+}
+
 func (p *ollamaProvider) Name() string {
 	return "ollama"
 }
 
+func (p *ollamaProvider) Model() string {
+	return p.model
+}
+
+// ContextSize returns num_ctx, or zero when the model's default is used.
+func (p *ollamaProvider) ContextSize() int {
+	return p.providerCfg.NumCtx
+}
+
 func (p *ollamaProvider) IsEnabled() bool {
 	return p.enabled
 }
 
 func (p *ollamaProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	return p.retry.do(ctx, func(ctx context.Context) (string, error) {
+		return p.send(ctx, messages)
+	})
+}
+
+func (p *ollamaProvider) send(ctx context.Context, messages []Message) (string, error) {
 	if !p.enabled {
 		return "", fmt.Errorf("ollama: provider not enabled")
 	}
 
-	openAIMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
-	for i, msg := range messages {
-		switch msg.Role {
-		case "system":
-			openAIMessages[i] = openai.SystemMessage(msg.Content)
-		case "user":
-			openAIMessages[i] = openai.UserMessage(msg.Content)
-		case "assistant":
-			openAIMessages[i] = openai.AssistantMessage(msg.Content)
-		default:
-			openAIMessages[i] = openai.UserMessage(msg.Content)
+	req := ollamaChatRequest{
+		Model:     modelFromContext(ctx, p.model),
+		Messages:  ollamaMessages(messages),
+		KeepAlive: ollamaKeepAlive(p.providerCfg.KeepAlive),
+		Options:   ollamaOptions(generationConfig(ctx, p.providerCfg)),
+	}
+
+	var resp ollamaChatResponse
+	if err := p.do(ctx, http.MethodPost, "/api/chat", req, &resp); err != nil {
+		return "", fmt.Errorf("ollama: %w", err)
+	}
+
+	reportTruncation(ctx, resp.DoneReason == finishReasonLength)
+	return applyThink(ctx, p.providerCfg.Think, resp.Message.Content), nil
+}
+
+func (p *ollamaProvider) Ping(ctx context.Context) error {
+	if !p.enabled {
+		return fmt.Errorf("ollama: provider not enabled")
+	}
+
+	if err := p.do(ctx, http.MethodGet, "/api/tags", nil, nil); err != nil {
+		return fmt.Errorf("ollama: %w", err)
+	}
+
+	return nil
+}
+
+// ListModels returns the names of the models installed on the Ollama server.
+func (p *ollamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("ollama: provider not enabled")
+	}
+
+	var resp ollamaTagsResponse
+	if err := p.do(ctx, http.MethodGet, "/api/tags", nil, &resp); err != nil {
+		return nil, fmt.Errorf("ollama: %w", err)
+	}
+
+	names := make([]string, 0, len(resp.Models))
+	for _, m := range resp.Models {
+		names = append(names, m.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (p *ollamaProvider) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("ollama: provider not enabled")
+	}
+	if model == "" {
+		model = "nomic-embed-text"
+	}
+
+	req := ollamaEmbedRequest{
+		Model:     model,
+		Input:     texts,
+		KeepAlive: ollamaKeepAlive(p.providerCfg.KeepAlive),
+	}
+
+	var resp ollamaEmbedResponse
+	if err := p.do(ctx, http.MethodPost, "/api/embed", req, &resp); err != nil {
+		return nil, fmt.Errorf("ollama: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama: expected %d embeddings, got %d", len(texts), len(resp.Embeddings))
+	}
+	return resp.Embeddings, nil
+}
+
+func (p *ollamaProvider) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
 		}
+		reader = bytes.NewReader(data)
 	}
 
-	resp, err := p.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Model:    shared.ChatModel(p.model),
-		Messages: openAIMessages,
-	})
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reader)
 	if err != nil {
-		return "", fmt.Errorf("ollama: %w", err)
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
 
-	if len(resp.Choices) == 0 {
-		return "", nil
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
-	return resp.Choices[0].Message.Content, nil
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) != nil {
+			apiErr.Error = strings.TrimSpace(string(data))
+		}
+		return &ollamaError{StatusCode: resp.StatusCode, Message: apiErr.Error}
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func ollamaMessages(messages []Message) []ollamaMessage {
+	result := make([]ollamaMessage, 0, len(messages))
+	for _, m := range messages {
+		result = append(result, ollamaMessage{Role: m.Role, Content: m.Content})
+	}
+	return result
+}
+
+func ollamaOptions(cfg config.ProviderConfig) map[string]any {
+	opts := make(map[string]any)
+	if cfg.Temperature != nil {
+		opts["temperature"] = *cfg.Temperature
+	}
+	if cfg.TopP != nil {
+		opts["top_p"] = *cfg.TopP
+	}
+	if cfg.FrequencyPenalty != nil {
+		opts["frequency_penalty"] = *cfg.FrequencyPenalty
+	}
+	if cfg.MaxTokens > 0 {
+		opts["num_predict"] = cfg.MaxTokens
+	}
+	if cfg.NumCtx > 0 {
+		opts["num_ctx"] = cfg.NumCtx
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+	return opts
+}
+
+// ollamaKeepAlive passes plain numbers (seconds, or -1 to keep the model
+// loaded) as JSON numbers and anything else, such as "10m", as a duration
+// string.
+func ollamaKeepAlive(keepAlive string) any {
+	if keepAlive == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(keepAlive); err == nil {
+		return n
+	}
+	return keepAlive
 }
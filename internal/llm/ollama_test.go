@@ -2,7 +2,12 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/jrswab/helpi/internal/config"
@@ -104,3 +109,131 @@ func TestOllamaProvider_SendMessage_Disabled(t *testing.T) {
 		t.Errorf("SendMessage() error = %v, want %v", err.Error(), expectedErr)
 	}
 }
+
+func newTestOllama(t *testing.T, handler http.HandlerFunc, providerCfg config.ProviderConfig) Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	providerCfg.Enabled = true
+	return NewOllamaProvider(&config.Config{
+		Providers: config.ProvidersConfig{Ollama: providerCfg},
+		APIKeys:   map[string]string{"OLLAMA_BASE_URL": server.URL + "/v1/"},
+	})
+}
+
+func TestOllamaProvider_SendMessage_NativeChat(t *testing.T) {
+	var got map[string]any
+	provider := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"Hi there"},"done":true}`)
+	}, config.ProviderConfig{DefaultModel: "llama3.2", KeepAlive: "-1", NumCtx: 8192, MaxTokens: 256})
+
+	resp, err := provider.SendMessage(context.Background(), []Message{{Role: "user", Content: "Hello"}})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if resp != "Hi there" {
+		t.Errorf("SendMessage() = %q, want %q", resp, "Hi there")
+	}
+
+	if got["model"] != "llama3.2" || got["stream"] != false || got["keep_alive"] != float64(-1) {
+		t.Errorf("unexpected request %v", got)
+	}
+	opts, _ := got["options"].(map[string]any)
+	if opts["num_ctx"] != float64(8192) || opts["num_predict"] != float64(256) {
+		t.Errorf("unexpected options %v", opts)
+	}
+}
+
+func TestOllamaProvider_SendMessage_ReportsTruncation(t *testing.T) {
+	provider := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"Once upon a"},"done":true,"done_reason":"length"}`)
+	}, config.ProviderConfig{DefaultModel: "llama3.2", MaxTokens: 3})
+
+	truncated := false
+	ctx := contextWithTruncationReport(context.Background(), func() { truncated = true })
+	if _, err := provider.SendMessage(ctx, []Message{{Role: "user", Content: "Tell me a story"}}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if !truncated {
+		t.Error("expected the cut-off answer to be reported")
+	}
+}
+
+func TestOllamaProvider_SendMessage_StripsThink(t *testing.T) {
+	provider := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"<think>Say hi.</think>\n\nHi there"},"done":true}`)
+	}, config.ProviderConfig{DefaultModel: "deepseek-r1", Think: "strip"})
+
+	resp, err := provider.SendMessage(context.Background(), []Message{{Role: "user", Content: "Hello"}})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if resp != "Hi there" {
+		t.Errorf("SendMessage() = %q, want %q", resp, "Hi there")
+	}
+}
+
+func TestOllamaProvider_SendMessage_Error(t *testing.T) {
+	provider := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":"model \"missing\" not found"}`)
+	}, config.ProviderConfig{DefaultModel: "missing"})
+
+	_, err := provider.SendMessage(context.Background(), []Message{{Role: "user", Content: "Hello"}})
+	if err == nil || !strings.Contains(err.Error(), `model "missing" not found`) {
+		t.Errorf("expected API error, got %v", err)
+	}
+}
+
+func TestOllamaProvider_ListModels(t *testing.T) {
+	provider := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"models":[{"name":"qwen3:8b"},{"name":"llama3.2:latest"}]}`)
+	}, config.ProviderConfig{})
+
+	names, err := provider.(ModelLister).ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("ListModels() error = %v", err)
+	}
+	if strings.Join(names, ",") != "llama3.2:latest,qwen3:8b" {
+		t.Errorf("ListModels() = %v", names)
+	}
+}
+
+func TestOllamaProvider_Embed(t *testing.T) {
+	provider := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"embeddings":[[0.1,0.2],[0.3,0.4]]}`)
+	}, config.ProviderConfig{})
+
+	vectors, err := provider.(Embedder).Embed(context.Background(), "", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(vectors) != 2 || vectors[1][1] != 0.4 {
+		t.Errorf("Embed() = %v", vectors)
+	}
+}
+
+func TestOllamaBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"":                           "http://localhost:11434",
+		"http://gpu-box:11434/":      "http://gpu-box:11434",
+		"http://localhost:11434/v1":  "http://localhost:11434",
+		"https://ollama.example.com": "https://ollama.example.com",
+	}
+	for raw, want := range tests {
+		if got := ollamaBaseURL(raw); got != want {
+			t.Errorf("ollamaBaseURL(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
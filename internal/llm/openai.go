@@ -1,14 +1,18 @@
 package llm
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/jrswab/helpi/internal/config"
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
-	"github.com/openai/openai-go/v3/shared"
+	"github.com/openai/openai-go/v3/packages/param"
 )
 
 type openAIProvider struct {
@@ -16,6 +20,8 @@ type openAIProvider struct {
 	model       string
 	enabled     bool
 	providerCfg config.ProviderConfig
+	state       *responsesState
+	retry       retryPolicy
 }
 
 func NewOpenAIProvider(cfg *config.Config) Provider {
@@ -26,6 +32,8 @@ func NewOpenAIProvider(cfg *config.Config) Provider {
 	if enabled {
 		client = openai.NewClient(
 			option.WithAPIKey(apiKey),
+			option.WithMaxRetries(0),
+			option.WithHTTPClient(newHTTPClient(cfg.Providers.OpenAI)),
 		)
 	}
 
@@ -34,6 +42,8 @@ func NewOpenAIProvider(cfg *config.Config) Provider {
 		model:       cfg.Providers.OpenAI.DefaultModel,
 		enabled:     enabled,
 		providerCfg: cfg.Providers.OpenAI,
+		state:       newResponsesState(),
+		retry:       newRetryPolicy("openai", cfg.Providers.OpenAI),
 	}
 }
 
@@ -41,40 +51,234 @@ func (p *openAIProvider) Name() string {
 	return "openai"
 }
 
+func (p *openAIProvider) Model() string {
+	return p.model
+}
+
 func (p *openAIProvider) IsEnabled() bool {
 	return p.enabled
 }
 
 func (p *openAIProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	return p.retry.do(ctx, func(ctx context.Context) (string, error) {
+		return p.send(ctx, messages)
+	})
+}
+
+func (p *openAIProvider) send(ctx context.Context, messages []Message) (string, error) {
+	if !p.enabled {
+		return "", fmt.Errorf("openai: provider not enabled")
+	}
+
+	if useResponsesAPI(p.providerCfg) {
+		resp, err := sendResponses(ctx, p.client, modelFromContext(ctx, p.model), p.providerCfg, p.state, messages)
+		if err != nil {
+			return "", fmt.Errorf("openai: %w", err)
+		}
+		return resp, nil
+	}
+
+	resp, err := p.client.Chat.Completions.New(ctx, p.chatParams(ctx, modelFromContext(ctx, p.model), messages))
+	if err != nil {
+		return "", fmt.Errorf("openai: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", nil
+	}
+
+	reportTruncation(ctx, resp.Choices[0].FinishReason == finishReasonLength)
+	return applyThink(ctx, p.providerCfg.Think, resp.Choices[0].Message.Content), nil
+}
+
+// chatParams uses max_completion_tokens, which OpenAI requires for reasoning
+// models; other OpenAI-compatible servers still expect max_tokens.
+func (p *openAIProvider) chatParams(ctx context.Context, model string, messages []Message) openai.ChatCompletionNewParams {
+	params := chatCompletionParams(model, messages, generationConfig(ctx, p.providerCfg))
+	if params.MaxTokens.Valid() {
+		params.MaxCompletionTokens = params.MaxTokens
+		params.MaxTokens = param.Opt[int64]{}
+	}
+	return params
+}
+
+func (p *openAIProvider) Ping(ctx context.Context) error {
+	if !p.enabled {
+		return fmt.Errorf("openai: provider not enabled")
+	}
+
+	if _, err := p.client.Models.List(ctx); err != nil {
+		return fmt.Errorf("openai: %w", err)
+	}
+
+	return nil
+}
+
+func (p *openAIProvider) SubmitBatch(ctx context.Context, requests []BatchRequest) (string, error) {
 	if !p.enabled {
 		return "", fmt.Errorf("openai: provider not enabled")
 	}
 
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, req := range requests {
+		line := map[string]any{
+			"custom_id": req.CustomID,
+			"method":    "POST",
+			"url":       "/v1/chat/completions",
+			"body":      p.chatParams(ctx, p.model, req.Messages),
+		}
+		if err := enc.Encode(line); err != nil {
+			return "", fmt.Errorf("openai: failed to encode batch request: %w", err)
+		}
+	}
+
+	file, err := p.client.Files.New(ctx, openai.FileNewParams{
+		File:    openai.File(&buf, "batch.jsonl", "application/jsonl"),
+		Purpose: openai.FilePurposeBatch,
+	})
+	if err != nil {
+		return "", fmt.Errorf("openai: failed to upload batch file: %w", err)
+	}
+
+	batch, err := p.client.Batches.New(ctx, openai.BatchNewParams{
+		CompletionWindow: openai.BatchNewParamsCompletionWindow24h,
+		Endpoint:         openai.BatchNewParamsEndpointV1ChatCompletions,
+		InputFileID:      file.ID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("openai: failed to create batch: %w", err)
+	}
+
+	return batch.ID, nil
+}
+
+func (p *openAIProvider) BatchResults(ctx context.Context, batchID string) ([]BatchResult, bool, error) {
+	if !p.enabled {
+		return nil, false, fmt.Errorf("openai: provider not enabled")
+	}
+
+	batch, err := p.client.Batches.Get(ctx, batchID)
+	if err != nil {
+		return nil, false, fmt.Errorf("openai: %w", err)
+	}
+
+	switch batch.Status {
+	case openai.BatchStatusCompleted:
+	case openai.BatchStatusFailed, openai.BatchStatusExpired, openai.BatchStatusCancelled:
+		return nil, true, fmt.Errorf("openai: batch %s ended with status %s", batchID, batch.Status)
+	default:
+		return nil, false, nil
+	}
+
+	if batch.OutputFileID == "" {
+		return nil, true, fmt.Errorf("openai: batch %s completed without output", batchID)
+	}
+
+	resp, err := p.client.Files.Content(ctx, batch.OutputFileID)
+	if err != nil {
+		return nil, true, fmt.Errorf("openai: failed to download batch output: %w", err)
+	}
+	defer resp.Body.Close()
+
+	results, err := parseOpenAIBatchOutput(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("openai: %w", err)
+	}
+
+	return results, true, nil
+}
+
+func parseOpenAIBatchOutput(r io.Reader) ([]BatchResult, error) {
+	var results []BatchResult
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var line struct {
+			CustomID string `json:"custom_id"`
+			Response struct {
+				StatusCode int                   `json:"status_code"`
+				Body       openai.ChatCompletion `json:"body"`
+			} `json:"response"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("failed to parse batch output: %w", err)
+		}
+
+		result := BatchResult{CustomID: line.CustomID}
+		switch {
+		case line.Error != nil:
+			result.Error = line.Error.Message
+		case line.Response.StatusCode != 200:
+			result.Error = fmt.Sprintf("request failed with status %d", line.Response.StatusCode)
+		case len(line.Response.Body.Choices) > 0:
+			result.Content = line.Response.Body.Choices[0].Message.Content
+		}
+		results = append(results, result)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch output: %w", err)
+	}
+
+	return results, nil
+}
+
+func toOpenAIMessages(messages []Message) []openai.ChatCompletionMessageParamUnion {
 	openAIMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
 		switch msg.Role {
 		case "system":
 			openAIMessages[i] = openai.SystemMessage(msg.Content)
 		case "user":
-			openAIMessages[i] = openai.UserMessage(msg.Content)
+			if len(msg.Images) > 0 {
+				openAIMessages[i] = openai.UserMessage(openAIContentParts(msg))
+			} else {
+				openAIMessages[i] = openai.UserMessage(msg.Content)
+			}
 		case "assistant":
 			openAIMessages[i] = openai.AssistantMessage(msg.Content)
 		default:
 			openAIMessages[i] = openai.UserMessage(msg.Content)
 		}
 	}
+	return openAIMessages
+}
 
-	resp, err := p.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Model:    shared.ChatModel(p.model),
-		Messages: openAIMessages,
-	})
-	if err != nil {
-		return "", fmt.Errorf("openai: %w", err)
+func openAIContentParts(msg Message) []openai.ChatCompletionContentPartUnionParam {
+	parts := make([]openai.ChatCompletionContentPartUnionParam, 0, len(msg.Images)+1)
+	if msg.Content != "" {
+		parts = append(parts, openai.TextContentPart(msg.Content))
+	}
+	for _, img := range msg.Images {
+		parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: img.DataURL()}))
 	}
+	return parts
+}
 
-	if len(resp.Choices) == 0 {
-		return "", nil
+func (p *openAIProvider) SupportsVision() bool {
+	return true
+}
+
+func (p *openAIProvider) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("openai: provider not enabled")
+	}
+	if model == "" {
+		model = string(openai.EmbeddingModelTextEmbedding3Small)
 	}
 
-	return resp.Choices[0].Message.Content, nil
+	vectors, err := embedOpenAI(ctx, p.client, model, texts)
+	if err != nil {
+		return nil, fmt.Errorf("openai: %w", err)
+	}
+	return vectors, nil
 }
@@ -110,3 +110,18 @@ func TestOpenAIProvider_SendMessage_Disabled(t *testing.T) {
 		t.Errorf("SendMessage() error = %v, want %v", err.Error(), expectedErr)
 	}
 }
+
+func TestToOpenAIMessages_Images(t *testing.T) {
+	msgs := toOpenAIMessages([]Message{{Role: "user", Content: "what is this?", Images: []Image{{MIMEType: "image/png", Data: []byte("abc")}}}})
+
+	parts := msgs[0].OfUser.Content.OfArrayOfContentParts
+	if len(parts) != 2 {
+		t.Fatalf("expected text and image parts, got %d", len(parts))
+	}
+	if parts[0].OfText == nil || parts[0].OfText.Text != "what is this?" {
+		t.Errorf("expected text part first, got %+v", parts[0])
+	}
+	if parts[1].OfImageURL == nil || parts[1].OfImageURL.ImageURL.URL != "data:image/png;base64,YWJj" {
+		t.Errorf("expected data URL image part, got %+v", parts[1])
+	}
+}
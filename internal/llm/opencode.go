@@ -8,7 +8,6 @@ import (
 	"github.com/jrswab/helpi/internal/config"
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
-	"github.com/openai/openai-go/v3/shared"
 )
 
 type openCodeProvider struct {
@@ -16,6 +15,8 @@ type openCodeProvider struct {
 	model       string
 	enabled     bool
 	providerCfg config.ProviderConfig
+	state       *responsesState
+	retry       retryPolicy
 }
 
 func NewOpenCodeProvider(cfg *config.Config) Provider {
@@ -27,6 +28,8 @@ func NewOpenCodeProvider(cfg *config.Config) Provider {
 		client = openai.NewClient(
 			option.WithBaseURL("https://opencode.ai/zen/v1"),
 			option.WithAPIKey(apiKey),
+			option.WithMaxRetries(0),
+			option.WithHTTPClient(newHTTPClient(cfg.Providers.OpenCode)),
 		)
 	}
 
@@ -35,6 +38,8 @@ func NewOpenCodeProvider(cfg *config.Config) Provider {
 		model:       cfg.Providers.OpenCode.DefaultModel,
 		enabled:     enabled,
 		providerCfg: cfg.Providers.OpenCode,
+		state:       newResponsesState(),
+		retry:       newRetryPolicy("opencode", cfg.Providers.OpenCode),
 	}
 }
 
@@ -42,33 +47,34 @@ func (p *openCodeProvider) Name() string {
 	return "opencode"
 }
 
+func (p *openCodeProvider) Model() string {
+	return p.model
+}
+
 func (p *openCodeProvider) IsEnabled() bool {
 	return p.enabled
 }
 
 func (p *openCodeProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	return p.retry.do(ctx, func(ctx context.Context) (string, error) {
+		return p.send(ctx, messages)
+	})
+}
+
+func (p *openCodeProvider) send(ctx context.Context, messages []Message) (string, error) {
 	if !p.enabled {
 		return "", fmt.Errorf("opencode: provider not enabled")
 	}
 
-	openAIMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
-	for i, msg := range messages {
-		switch msg.Role {
-		case "system":
-			openAIMessages[i] = openai.SystemMessage(msg.Content)
-		case "user":
-			openAIMessages[i] = openai.UserMessage(msg.Content)
-		case "assistant":
-			openAIMessages[i] = openai.AssistantMessage(msg.Content)
-		default:
-			openAIMessages[i] = openai.UserMessage(msg.Content)
+	if useResponsesAPI(p.providerCfg) {
+		resp, err := sendResponses(ctx, p.client, modelFromContext(ctx, p.model), p.providerCfg, p.state, messages)
+		if err != nil {
+			return "", fmt.Errorf("opencode: %w", err)
 		}
+		return resp, nil
 	}
 
-	resp, err := p.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Model:    shared.ChatModel(p.model),
-		Messages: openAIMessages,
-	})
+	resp, err := p.client.Chat.Completions.New(ctx, chatCompletionParams(modelFromContext(ctx, p.model), messages, generationConfig(ctx, p.providerCfg)))
 	if err != nil {
 		return "", fmt.Errorf("opencode: %w", err)
 	}
@@ -77,5 +83,18 @@ func (p *openCodeProvider) SendMessage(ctx context.Context, messages []Message)
 		return "", nil
 	}
 
-	return resp.Choices[0].Message.Content, nil
+	reportTruncation(ctx, resp.Choices[0].FinishReason == finishReasonLength)
+	return applyThink(ctx, p.providerCfg.Think, resp.Choices[0].Message.Content), nil
+}
+
+func (p *openCodeProvider) Ping(ctx context.Context) error {
+	if !p.enabled {
+		return fmt.Errorf("opencode: provider not enabled")
+	}
+
+	if _, err := p.client.Models.List(ctx); err != nil {
+		return fmt.Errorf("opencode: %w", err)
+	}
+
+	return nil
 }
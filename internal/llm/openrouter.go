@@ -8,7 +8,6 @@ import (
 	"github.com/jrswab/helpi/internal/config"
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
-	"github.com/openai/openai-go/v3/shared"
 )
 
 type openRouterProvider struct {
@@ -16,6 +15,8 @@ type openRouterProvider struct {
 	model       string
 	enabled     bool
 	providerCfg config.ProviderConfig
+	state       *responsesState
+	retry       retryPolicy
 }
 
 func NewOpenRouterProvider(cfg *config.Config) Provider {
@@ -29,6 +30,8 @@ func NewOpenRouterProvider(cfg *config.Config) Provider {
 			option.WithAPIKey(apiKey),
 			option.WithHeader("HTTP-Referer", "https://github.com/jrswab/helpi"),
 			option.WithHeader("X-Title", "Helpi"),
+			option.WithMaxRetries(0),
+			option.WithHTTPClient(newHTTPClient(cfg.Providers.OpenRouter)),
 		)
 	}
 
@@ -37,6 +40,8 @@ func NewOpenRouterProvider(cfg *config.Config) Provider {
 		model:       cfg.Providers.OpenRouter.DefaultModel,
 		enabled:     enabled,
 		providerCfg: cfg.Providers.OpenRouter,
+		state:       newResponsesState(),
+		retry:       newRetryPolicy("openrouter", cfg.Providers.OpenRouter),
 	}
 }
 
@@ -44,33 +49,34 @@ func (p *openRouterProvider) Name() string {
 	return "openrouter"
 }
 
+func (p *openRouterProvider) Model() string {
+	return p.model
+}
+
 func (p *openRouterProvider) IsEnabled() bool {
 	return p.enabled
 }
 
 func (p *openRouterProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	return p.retry.do(ctx, func(ctx context.Context) (string, error) {
+		return p.send(ctx, messages)
+	})
+}
+
+func (p *openRouterProvider) send(ctx context.Context, messages []Message) (string, error) {
 	if !p.enabled {
 		return "", fmt.Errorf("openrouter: provider not enabled")
 	}
 
-	openAIMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
-	for i, msg := range messages {
-		switch msg.Role {
-		case "system":
-			openAIMessages[i] = openai.SystemMessage(msg.Content)
-		case "user":
-			openAIMessages[i] = openai.UserMessage(msg.Content)
-		case "assistant":
-			openAIMessages[i] = openai.AssistantMessage(msg.Content)
-		default:
-			openAIMessages[i] = openai.UserMessage(msg.Content)
+	if useResponsesAPI(p.providerCfg) {
+		resp, err := sendResponses(ctx, p.client, modelFromContext(ctx, p.model), p.providerCfg, p.state, messages)
+		if err != nil {
+			return "", fmt.Errorf("openrouter: %w", err)
 		}
+		return resp, nil
 	}
 
-	resp, err := p.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-		Model:    shared.ChatModel(p.model),
-		Messages: openAIMessages,
-	})
+	resp, err := p.client.Chat.Completions.New(ctx, chatCompletionParams(modelFromContext(ctx, p.model), messages, generationConfig(ctx, p.providerCfg)))
 	if err != nil {
 		return "", fmt.Errorf("openrouter: %w", err)
 	}
@@ -79,5 +85,37 @@ func (p *openRouterProvider) SendMessage(ctx context.Context, messages []Message
 		return "", nil
 	}
 
-	return resp.Choices[0].Message.Content, nil
+	reportTruncation(ctx, resp.Choices[0].FinishReason == finishReasonLength)
+	return applyThink(ctx, p.providerCfg.Think, resp.Choices[0].Message.Content), nil
+}
+
+func (p *openRouterProvider) Ping(ctx context.Context) error {
+	if !p.enabled {
+		return fmt.Errorf("openrouter: provider not enabled")
+	}
+
+	if _, err := p.client.Models.List(ctx); err != nil {
+		return fmt.Errorf("openrouter: %w", err)
+	}
+
+	return nil
+}
+
+func (p *openRouterProvider) SupportsVision() bool {
+	return true
+}
+
+func (p *openRouterProvider) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("openrouter: provider not enabled")
+	}
+	if model == "" {
+		model = "openai/" + string(openai.EmbeddingModelTextEmbedding3Small)
+	}
+
+	vectors, err := embedOpenAI(ctx, p.client, model, texts)
+	if err != nil {
+		return nil, fmt.Errorf("openrouter: %w", err)
+	}
+	return vectors, nil
 }
@@ -0,0 +1,82 @@
+package llm
+
+import "context"
+
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	provider        string
+	defaultProvider string
+	model           string
+	userID          int64
+	profile         int64
+	temperature     *float64
+	onRoute         func(Route)
+	onReasoning     func(string)
+	onThink         func(string)
+	onThinking      func()
+	onTruncation    func()
+}
+
+func WithProvider(name string) RequestOption {
+	return func(o *requestOptions) {
+		o.provider = name
+	}
+}
+
+// WithDefaultProvider sets the provider used when no routing rule matches
+// and the user has not picked one with /switch.
+func WithDefaultProvider(name string) RequestOption {
+	return func(o *requestOptions) {
+		o.defaultProvider = name
+	}
+}
+
+func WithModel(model string) RequestOption {
+	return func(o *requestOptions) {
+		o.model = model
+	}
+}
+
+func WithUser(userID int64) RequestOption {
+	return func(o *requestOptions) {
+		o.userID = userID
+	}
+}
+
+// WithTemperature overrides the provider's configured temperature for one
+// request.
+func WithTemperature(t float64) RequestOption {
+	return func(o *requestOptions) {
+		o.temperature = &t
+	}
+}
+
+// WithRouteReport calls fn with the provider and model chosen for the
+// request before it is sent.
+func WithRouteReport(fn func(Route)) RequestOption {
+	return func(o *requestOptions) {
+		o.onRoute = fn
+	}
+}
+
+func newRequestOptions(opts []RequestOption) requestOptions {
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+type modelKey struct{}
+
+func contextWithModel(ctx context.Context, model string) context.Context {
+	return context.WithValue(ctx, modelKey{}, model)
+}
+
+func modelFromContext(ctx context.Context, fallback string) string {
+	if model, ok := ctx.Value(modelKey{}).(string); ok && model != "" {
+		return model
+	}
+	return fallback
+}
@@ -0,0 +1,42 @@
+package llm
+
+// ProfileRouter is implemented by routers that can keep a user's /switch
+// choice under another key, the profile, so several bots sharing one router
+// each remember their own. Access is still checked against userID.
+type ProfileRouter interface {
+	GetProviderForProfile(userID, profile int64) (Provider, error)
+	SetDefaultForProfile(userID, profile int64, name string) error
+}
+
+// WithProfile looks up the provider chosen under profile instead of the
+// one chosen by the user given to WithUser.
+func WithProfile(profile int64) RequestOption {
+	return func(o *requestOptions) {
+		o.profile = profile
+	}
+}
+
+func (o requestOptions) profileKey() int64 {
+	if o.profile != 0 {
+		return o.profile
+	}
+	return o.userID
+}
+
+func (r *router) GetProviderForProfile(userID, profile int64) (Provider, error) {
+	return r.providerForUser(userID, profile, "")
+}
+
+func (r *ReloadableRouter) GetProviderForProfile(userID, profile int64) (Provider, error) {
+	if pr, ok := r.router().(ProfileRouter); ok {
+		return pr.GetProviderForProfile(userID, profile)
+	}
+	return r.router().GetProviderForUser(userID)
+}
+
+func (r *ReloadableRouter) SetDefaultForProfile(userID, profile int64, name string) error {
+	if pr, ok := r.router().(ProfileRouter); ok {
+		return pr.SetDefaultForProfile(userID, profile, name)
+	}
+	return r.router().SetDefaultForUser(userID, name)
+}
@@ -7,3 +7,29 @@ type Provider interface {
 	SendMessage(ctx context.Context, messages []Message) (string, error)
 	IsEnabled() bool
 }
+
+type ModelProvider interface {
+	Model() string
+}
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type ContextSizer interface {
+	ContextSize() int
+}
+
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// Embedder turns texts into vectors, one per text and in the same order.
+// An empty model uses the provider's default embedding model.
+type Embedder interface {
+	Embed(ctx context.Context, model string, texts []string) ([][]float64, error)
+}
+
+type VisionProvider interface {
+	SupportsVision() bool
+}
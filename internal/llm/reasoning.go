@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openai/openai-go/v3/responses"
+)
+
+// isReasoningModel reports whether model is an OpenAI reasoning model (the
+// o-series and gpt-5). These reject temperature, top_p and penalties and take
+// a reasoning effort instead. Provider prefixes such as "openai/" are ignored.
+func isReasoningModel(model string) bool {
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	model = strings.ToLower(model)
+	if strings.HasPrefix(model, "gpt-5") {
+		return !strings.Contains(model, "chat")
+	}
+	return len(model) >= 2 && model[0] == 'o' && model[1] >= '1' && model[1] <= '9'
+}
+
+type reasoningKey struct{}
+
+// WithReasoningReport calls fn with the model's reasoning summary when the
+// provider is configured to return one.
+func WithReasoningReport(fn func(summary string)) RequestOption {
+	return func(o *requestOptions) {
+		o.onReasoning = fn
+	}
+}
+
+func contextWithReasoningReport(ctx context.Context, fn func(string)) context.Context {
+	return context.WithValue(ctx, reasoningKey{}, fn)
+}
+
+func reportReasoning(ctx context.Context, summary string) {
+	if fn, ok := ctx.Value(reasoningKey{}).(func(string)); ok && summary != "" {
+		fn(summary)
+	}
+}
+
+type thinkingKey struct{}
+
+// WithThinkingReport calls fn when the model starts thinking before it
+// answers, which can take a while with a large thinking budget.
+func WithThinkingReport(fn func()) RequestOption {
+	return func(o *requestOptions) {
+		o.onThinking = fn
+	}
+}
+
+func contextWithThinkingReport(ctx context.Context, fn func()) context.Context {
+	return context.WithValue(ctx, thinkingKey{}, fn)
+}
+
+func reportThinking(ctx context.Context) {
+	if fn, ok := ctx.Value(thinkingKey{}).(func()); ok {
+		fn()
+	}
+}
+
+func reasoningSummary(resp *responses.Response) string {
+	var parts []string
+	for _, item := range resp.Output {
+		if item.Type != "reasoning" {
+			continue
+		}
+		for _, s := range item.Summary {
+			if text := strings.TrimSpace(s.Text); text != "" {
+				parts = append(parts, text)
+			}
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/openai/openai-go/v3/responses"
+)
+
+func TestIsReasoningModel(t *testing.T) {
+	tests := map[string]bool{
+		"o1":                true,
+		"o3-mini":           true,
+		"o4-mini":           true,
+		"openai/o3":         true,
+		"gpt-5":             true,
+		"gpt-5-mini":        true,
+		"gpt-5-chat-latest": false,
+		"gpt-4o":            false,
+		"omni-moderation":   false,
+		"llama3":            false,
+	}
+	for model, want := range tests {
+		if got := isReasoningModel(model); got != want {
+			t.Errorf("isReasoningModel(%q) = %v, want %v", model, got, want)
+		}
+	}
+}
+
+func TestChatCompletionParams_ReasoningModel(t *testing.T) {
+	cfg := config.ProviderConfig{Temperature: float(0.7), TopP: float(0.9), ReasoningEffort: "high", MaxTokens: 1000}
+	p := &openAIProvider{providerCfg: cfg}
+	data, _ := json.Marshal(p.chatParams(context.Background(), "o3-mini", nil))
+
+	got := string(data)
+	if strings.Contains(got, "temperature") || strings.Contains(got, "top_p") {
+		t.Errorf("expected sampling parameters to be dropped, got %s", got)
+	}
+	for _, want := range []string{`"reasoning_effort":"high"`, `"max_completion_tokens":1000`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in %s", want, got)
+		}
+	}
+}
+
+func TestApplyResponsesGeneration_ReasoningSummary(t *testing.T) {
+	var params responses.ResponseNewParams
+	applyResponsesGeneration(&params, "gpt-5", config.ProviderConfig{Temperature: float(1), ReasoningEffort: "low", ShowReasoning: true})
+
+	data, _ := json.Marshal(params)
+	got := string(data)
+	if strings.Contains(got, "temperature") || !strings.Contains(got, `"effort":"low"`) || !strings.Contains(got, `"summary":"auto"`) {
+		t.Errorf("unexpected params %s", got)
+	}
+}
+
+func TestReasoningSummary(t *testing.T) {
+	var resp responses.Response
+	raw := `{"output":[
+		{"type":"reasoning","id":"rs_1","summary":[{"type":"summary_text","text":"Compared both options."}]},
+		{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Use B."}]}
+	]}`
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := reasoningSummary(&resp); got != "Compared both options." {
+		t.Errorf("unexpected summary %q", got)
+	}
+
+	var reported string
+	ctx := contextWithReasoningReport(context.Background(), func(s string) { reported = s })
+	reportReasoning(ctx, reasoningSummary(&resp))
+	if reported != "Compared both options." {
+		t.Errorf("expected summary to be reported, got %q", reported)
+	}
+}
@@ -0,0 +1,181 @@
+package llm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[ -]?)?(?:\(\d{1,4}\)[ -]?)?\d{2,4}(?:[ -]?\d{2,4}){1,4}`)
+)
+
+type redactPattern struct {
+	label string
+	re    *regexp.Regexp
+	valid func(string) bool
+}
+
+// redactor masks personal data in requests to cloud providers. Matches are
+// replaced with numbered placeholders such as [EMAIL_1] and restored in the
+// reply, so the originals never leave the bot.
+type redactor struct {
+	patterns []redactPattern
+	exempt   map[string]bool
+}
+
+func compileRedactor(cfg config.RedactionConfig) (*redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	r := &redactor{exempt: make(map[string]bool)}
+	for _, name := range cfg.ExemptProviders {
+		r.exempt[name] = true
+	}
+	types := make(map[string]bool, len(cfg.Types))
+	for _, t := range cfg.Types {
+		types[t] = true
+	}
+	// Cards are matched before phone numbers, which would otherwise take
+	// their digits.
+	if types["email"] {
+		r.patterns = append(r.patterns, redactPattern{label: "EMAIL", re: emailPattern})
+	}
+	if types["credit_card"] {
+		r.patterns = append(r.patterns, redactPattern{label: "CARD", re: cardPattern, valid: luhnValid})
+	}
+	if types["phone"] {
+		r.patterns = append(r.patterns, redactPattern{label: "PHONE", re: phonePattern, valid: phoneDigits})
+	}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %s: %w", p.Name, err)
+		}
+		r.patterns = append(r.patterns, redactPattern{label: strings.ToUpper(p.Name), re: re})
+	}
+	return r, nil
+}
+
+// applies reports whether requests to provider should be redacted.
+func (r *redactor) applies(provider string) bool {
+	return r != nil && !r.exempt[provider]
+}
+
+// Redactor is implemented by routers that mask personal data. Callers that
+// talk to a provider directly, such as batch submissions and embeddings,
+// use it so the masking still applies.
+type Redactor interface {
+	// Redact returns messages as they may be sent to provider and the
+	// original values keyed by placeholder, for Restore.
+	Redact(provider string, messages []Message) ([]Message, map[string]string)
+}
+
+func (r *router) Redact(provider string, messages []Message) ([]Message, map[string]string) {
+	if !r.redactor.applies(provider) {
+		return messages, nil
+	}
+	redacted, red := r.redactor.redact(messages)
+	return redacted, red.originals
+}
+
+func (r *ReloadableRouter) Redact(provider string, messages []Message) ([]Message, map[string]string) {
+	if rd, ok := r.router().(Redactor); ok {
+		return rd.Redact(provider, messages)
+	}
+	return messages, nil
+}
+
+// Restore puts the originals returned by Redact back into a reply.
+func Restore(text string, originals map[string]string) string {
+	return (&redaction{originals: originals}).restore(text)
+}
+
+// redaction holds the placeholders used for one request.
+type redaction struct {
+	originals    map[string]string
+	placeholders map[string]string
+	counts       map[string]int
+}
+
+func (r *redactor) redact(messages []Message) ([]Message, *redaction) {
+	red := &redaction{
+		originals:    make(map[string]string),
+		placeholders: make(map[string]string),
+		counts:       make(map[string]int),
+	}
+
+	result := make([]Message, len(messages))
+	for i, m := range messages {
+		for _, p := range r.patterns {
+			m.Content = p.re.ReplaceAllStringFunc(m.Content, func(match string) string {
+				if p.valid != nil && !p.valid(match) {
+					return match
+				}
+				return red.placeholder(p.label, match)
+			})
+		}
+		result[i] = m
+	}
+	return result, red
+}
+
+func (red *redaction) placeholder(label, value string) string {
+	key := label + "\x00" + value
+	if ph, ok := red.placeholders[key]; ok {
+		return ph
+	}
+	red.counts[label]++
+	ph := fmt.Sprintf("[%s_%d]", label, red.counts[label])
+	red.placeholders[key] = ph
+	red.originals[ph] = value
+	return ph
+}
+
+// restore puts the original values back into a provider reply.
+func (red *redaction) restore(text string) string {
+	if len(red.originals) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, len(red.originals)*2)
+	for ph, value := range red.originals {
+		pairs = append(pairs, ph, value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// phoneDigits rejects short numbers such as dates, years and amounts.
+func phoneDigits(s string) bool {
+	n := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n >= 9 && n <= 15
+}
@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+type echoProvider struct {
+	recordingProvider
+}
+
+func (p *echoProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	p.lastMessages = messages
+	return "You said: " + messages[len(messages)-1].Content, nil
+}
+
+func newRedactingRouter(t *testing.T, cfg config.RedactionConfig) (*router, *echoProvider, *echoProvider) {
+	t.Helper()
+	cloud := &echoProvider{recordingProvider{mockProvider: mockProvider{name: "openai", enabled: true}}}
+	local := &echoProvider{recordingProvider{mockProvider: mockProvider{name: "ollama", enabled: true}}}
+	r := newRouter([]Provider{cloud, local}, 0).(*router)
+
+	redactor, err := compileRedactor(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.redactor = redactor
+	return r, cloud, local
+}
+
+func TestSendMessage_Redaction(t *testing.T) {
+	r, cloud, local := newRedactingRouter(t, config.RedactionConfig{
+		Enabled:         true,
+		Types:           []string{"email", "phone", "credit_card"},
+		Patterns:        []config.RedactionPatternConfig{{Name: "ticket", Pattern: `TCK-\d+`}},
+		ExemptProviders: []string{"ollama"},
+	})
+
+	prompt := "Mail jane.doe@example.com or call +1 415-555-0134 about TCK-991, card 4111 1111 1111 1111. Again: jane.doe@example.com"
+	resp, err := r.SendMessage(context.Background(), []Message{{Role: "user", Content: prompt}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := cloud.lastMessages[0].Content
+	want := "Mail [EMAIL_1] or call [PHONE_1] about [TICKET_1], card [CARD_1]. Again: [EMAIL_1]"
+	if sent != want {
+		t.Errorf("sent %q, want %q", sent, want)
+	}
+	if resp != "You said: "+prompt {
+		t.Errorf("expected reply to be restored, got %q", resp)
+	}
+
+	if _, err := r.SendMessage(context.Background(), []Message{{Role: "user", Content: prompt}}, WithProvider("ollama")); err != nil {
+		t.Fatal(err)
+	}
+	if local.lastMessages[0].Content != prompt {
+		t.Errorf("expected exempt provider to get the original text, got %q", local.lastMessages[0].Content)
+	}
+}
+
+func TestRedactor_IgnoresNonPersonalNumbers(t *testing.T) {
+	r, cloud, _ := newRedactingRouter(t, config.RedactionConfig{Enabled: true, Types: []string{"phone", "credit_card"}})
+
+	prompt := "On 2024-05-17 I paid 1299 for order 1234 5678 9012 3456."
+	if _, err := r.SendMessage(context.Background(), []Message{{Role: "user", Content: prompt}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := cloud.lastMessages[0].Content; got != prompt {
+		t.Errorf("expected prompt to be unchanged, got %q", got)
+	}
+}
+
+func TestCompileRedactor_Disabled(t *testing.T) {
+	r, err := compileRedactor(config.RedactionConfig{Types: []string{"email"}})
+	if err != nil || r != nil {
+		t.Fatalf("expected no redactor, got %v, %v", r, err)
+	}
+	if r.applies("openai") {
+		t.Error("nil redactor should not apply")
+	}
+}
+
+func TestEmbedder_Redaction(t *testing.T) {
+	cloud := &mockEmbedProvider{mockProvider: mockProvider{name: "openai", enabled: true}, dims: 2}
+	r := newRouter([]Provider{cloud}, 0).(*router)
+	redactor, err := compileRedactor(config.RedactionConfig{Enabled: true, Types: []string{"email"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.redactor = redactor
+
+	embedder, err := NewEmbedder(NewReloadableRouter(r), "openai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := embedder.Embed(context.Background(), "", []string{"Mail jane.doe@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := cloud.lastTexts[0]; got != "Mail [EMAIL_1]" {
+		t.Errorf("expected embedded text to be redacted, got %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"sync"
+)
+
+type ReloadableRouter struct {
+	mu      sync.RWMutex
+	current Router
+	meter   Meter
+}
+
+func NewReloadableRouter(r Router) *ReloadableRouter {
+	return &ReloadableRouter{current: r}
+}
+
+func (r *ReloadableRouter) Swap(next Router) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = next
+}
+
+func (r *ReloadableRouter) router() Router {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+func (r *ReloadableRouter) GetProvider() (Provider, error) {
+	return r.router().GetProvider()
+}
+
+func (r *ReloadableRouter) GetProviderByName(name string) (Provider, error) {
+	return r.router().GetProviderByName(name)
+}
+
+func (r *ReloadableRouter) GetProviderForUser(userID int64) (Provider, error) {
+	return r.router().GetProviderForUser(userID)
+}
+
+func (r *ReloadableRouter) SetDefaultForUser(userID int64, name string) error {
+	return r.router().SetDefaultForUser(userID, name)
+}
+
+func (r *ReloadableRouter) ProviderNames() []string {
+	return r.router().ProviderNames()
+}
+
+func (r *ReloadableRouter) SendMessage(ctx context.Context, messages []Message, opts ...RequestOption) (string, error) {
+	r.mu.RLock()
+	m := r.meter
+	r.mu.RUnlock()
+	if m != nil {
+		return r.sendMetered(ctx, m, messages, opts)
+	}
+	return r.router().SendMessage(ctx, messages, opts...)
+}
+
+func (r *ReloadableRouter) Ping(ctx context.Context) error {
+	return r.router().Ping(ctx)
+}
@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/jrswab/helpi/internal/config"
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/responses"
+	"github.com/openai/openai-go/v3/shared"
+)
+
+const maxStoredResponses = 1000
+
+type responsesState struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+func newResponsesState() *responsesState {
+	return &responsesState{ids: make(map[string]string)}
+}
+
+func (s *responsesState) lookup(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.ids[key]
+	return id, ok
+}
+
+func (s *responsesState) store(key, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.ids) >= maxStoredResponses {
+		s.ids = make(map[string]string)
+	}
+	s.ids[key] = id
+}
+
+func useResponsesAPI(cfg config.ProviderConfig) bool {
+	return cfg.API == "responses"
+}
+
+func sendResponses(ctx context.Context, client openai.Client, model string, cfg config.ProviderConfig, state *responsesState, messages []Message) (string, error) {
+	var instructions string
+	var conversation []Message
+	for _, msg := range messages {
+		if msg.Role == "system" {
+			instructions = joinSystem(instructions, msg.Content)
+			continue
+		}
+		conversation = append(conversation, msg)
+	}
+
+	params := responses.ResponseNewParams{
+		Model: shared.ResponsesModel(model),
+		Tools: responsesTools(cfg),
+	}
+	applyResponsesGeneration(&params, model, generationConfig(ctx, cfg))
+	if instructions != "" {
+		params.Instructions = openai.String(instructions)
+	}
+
+	input := conversation
+	if cfg.Store && state != nil && len(conversation) > 1 {
+		if id, ok := state.lookup(conversationKey(conversation[:len(conversation)-1])); ok {
+			params.PreviousResponseID = openai.String(id)
+			input = conversation[len(conversation)-1:]
+		}
+	}
+	params.Store = openai.Bool(cfg.Store)
+
+	items := make(responses.ResponseInputParam, 0, len(input))
+	for _, msg := range input {
+		role := responses.EasyInputMessageRoleUser
+		if msg.Role == "assistant" {
+			role = responses.EasyInputMessageRoleAssistant
+		}
+		if len(msg.Images) > 0 && role == responses.EasyInputMessageRoleUser {
+			items = append(items, responses.ResponseInputItemParamOfMessage(responsesContent(msg), role))
+			continue
+		}
+		items = append(items, responses.ResponseInputItemParamOfMessage(msg.Content, role))
+	}
+	params.Input = responses.ResponseNewParamsInputUnion{OfInputItemList: items}
+
+	resp, err := client.Responses.New(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	text := resp.OutputText()
+	if cfg.ShowReasoning {
+		reportReasoning(ctx, reasoningSummary(resp))
+	}
+	reportTruncation(ctx, resp.IncompleteDetails.Reason == incompleteMaxOutputTokens)
+	if cfg.Store && state != nil && resp.ID != "" {
+		full := append(append([]Message{}, conversation...), Message{Role: "assistant", Content: text})
+		state.store(conversationKey(full), resp.ID)
+	}
+
+	return text, nil
+}
+
+func responsesContent(msg Message) responses.ResponseInputMessageContentListParam {
+	content := make(responses.ResponseInputMessageContentListParam, 0, len(msg.Images)+1)
+	if msg.Content != "" {
+		content = append(content, responses.ResponseInputContentUnionParam{
+			OfInputText: &responses.ResponseInputTextParam{Text: msg.Content},
+		})
+	}
+	for _, img := range msg.Images {
+		content = append(content, responses.ResponseInputContentUnionParam{
+			OfInputImage: &responses.ResponseInputImageParam{
+				Detail:   responses.ResponseInputImageDetailAuto,
+				ImageURL: openai.String(img.DataURL()),
+			},
+		})
+	}
+	return content
+}
+
+func responsesTools(cfg config.ProviderConfig) []responses.ToolUnionParam {
+	var tools []responses.ToolUnionParam
+	for _, name := range cfg.Tools {
+		switch name {
+		case "web_search":
+			tools = append(tools, responses.ToolParamOfWebSearch(responses.WebSearchToolTypeWebSearch))
+		case "file_search":
+			tools = append(tools, responses.ToolParamOfFileSearch(cfg.VectorStoreIDs))
+		}
+	}
+	return tools
+}
+
+func conversationKey(messages []Message) string {
+	h := sha256.New()
+	for _, msg := range messages {
+		fmt.Fprintf(h, "%s\x00%s\x00", msg.Role, msg.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func TestUseResponsesAPI(t *testing.T) {
+	if useResponsesAPI(config.ProviderConfig{}) {
+		t.Error("expected chat completions by default")
+	}
+	if useResponsesAPI(config.ProviderConfig{API: "chat"}) {
+		t.Error("expected chat completions for api: chat")
+	}
+	if !useResponsesAPI(config.ProviderConfig{API: "responses"}) {
+		t.Error("expected responses API for api: responses")
+	}
+}
+
+func TestResponsesTools(t *testing.T) {
+	cfg := config.ProviderConfig{
+		Tools:          []string{"web_search", "file_search", "unknown"},
+		VectorStoreIDs: []string{"vs_123"},
+	}
+
+	tools := responsesTools(cfg)
+	if len(tools) != 2 {
+		t.Fatalf("expected 2 tools, got %d", len(tools))
+	}
+	if tools[0].OfWebSearch == nil {
+		t.Error("expected first tool to be web_search")
+	}
+	if tools[1].OfFileSearch == nil {
+		t.Fatal("expected second tool to be file_search")
+	}
+	if len(tools[1].OfFileSearch.VectorStoreIDs) != 1 || tools[1].OfFileSearch.VectorStoreIDs[0] != "vs_123" {
+		t.Errorf("expected vector store IDs to be passed through, got %v", tools[1].OfFileSearch.VectorStoreIDs)
+	}
+}
+
+func TestConversationKey(t *testing.T) {
+	a := []Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}}
+	b := []Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}}
+	c := []Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello!"}}
+
+	if conversationKey(a) != conversationKey(b) {
+		t.Error("expected identical conversations to share a key")
+	}
+	if conversationKey(a) == conversationKey(c) {
+		t.Error("expected different conversations to have different keys")
+	}
+}
+
+func TestResponsesState(t *testing.T) {
+	s := newResponsesState()
+	if _, ok := s.lookup("missing"); ok {
+		t.Error("expected lookup of unknown key to fail")
+	}
+
+	s.store("key", "resp_1")
+	id, ok := s.lookup("key")
+	if !ok || id != "resp_1" {
+		t.Errorf("expected resp_1, got %q (ok=%v)", id, ok)
+	}
+}
@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+type retryPolicy struct {
+	name       string
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+}
+
+func newRetryPolicy(name string, cfg config.ProviderConfig) retryPolicy {
+	p := retryPolicy{
+		name:    name,
+		timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
+		backoff: time.Second,
+	}
+	if cfg.MaxRetries != nil {
+		p.maxRetries = *cfg.MaxRetries
+	}
+	if d, err := time.ParseDuration(cfg.RetryBackoff); err == nil && d > 0 {
+		p.backoff = d
+	}
+	return p
+}
+
+// maxRetryAfter is the longest Retry-After the bot waits out; providers
+// asking for more are reported as rate limited right away.
+const maxRetryAfter = time.Minute
+
+func (p retryPolicy) do(ctx context.Context, send func(ctx context.Context) (string, error)) (string, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := p.attempt(ctx, send)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= p.maxRetries || ctx.Err() != nil || !isRetryable(err) {
+			return "", wrapProviderError(p.name, err)
+		}
+
+		wait := p.backoff << attempt
+		if after := retryAfter(err); after > maxRetryAfter {
+			return "", wrapProviderError(p.name, err)
+		} else if after > wait {
+			wait = after
+		}
+		log.Printf("%s: request failed, retrying in %s (attempt %d/%d): %v", p.name, wait, attempt+1, p.maxRetries, err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (p retryPolicy) attempt(ctx context.Context, send func(ctx context.Context) (string, error)) (string, error) {
+	if p.timeout <= 0 {
+		return send(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return send(ctx)
+}
+
+func isRetryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if status := statusCode(err); status != 0 {
+		class := classify(status, err)
+		return class == ErrorRateLimited || class == ErrorServer
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
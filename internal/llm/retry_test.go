@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func retries(n int) *int { return &n }
+
+func TestRetryPolicy_RetriesTimeouts(t *testing.T) {
+	p := newRetryPolicy("test", config.ProviderConfig{MaxRetries: retries(2), RetryBackoff: "1ms"})
+	p.timeout = 10 * time.Millisecond
+
+	attempts := 0
+	resp, err := p.do(context.Background(), func(ctx context.Context) (string, error) {
+		attempts++
+		if attempts < 3 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "ok", nil
+	})
+
+	if err != nil || resp != "ok" {
+		t.Fatalf("expected success, got %q, %v", resp, err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryPolicy_GivesUpAfterMaxRetries(t *testing.T) {
+	p := newRetryPolicy("test", config.ProviderConfig{MaxRetries: retries(1), RetryBackoff: "1ms"})
+
+	attempts := 0
+	_, err := p.do(context.Background(), func(ctx context.Context) (string, error) {
+		attempts++
+		return "", context.DeadlineExceeded
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryPolicy_DoesNotRetryPermanentErrors(t *testing.T) {
+	p := newRetryPolicy("test", config.ProviderConfig{MaxRetries: retries(3), RetryBackoff: "1ms"})
+
+	attempts := 0
+	_, err := p.do(context.Background(), func(ctx context.Context) (string, error) {
+		attempts++
+		return "", errors.New("invalid request")
+	})
+
+	if err == nil || attempts != 1 {
+		t.Errorf("expected a single failed attempt, got %d attempts, err %v", attempts, err)
+	}
+}
+
+func TestCompatibleProvider_RetriesServerErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	p := NewOpenAICompatibleProvider(config.CustomProviderConfig{
+		Name:           "local",
+		BaseURL:        server.URL,
+		ProviderConfig: config.ProviderConfig{Enabled: true, DefaultModel: "m", MaxRetries: retries(1), RetryBackoff: "1ms"},
+	})
+
+	resp, err := p.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil || resp != "ok" {
+		t.Fatalf("expected success after retry, got %q, %v", resp, err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
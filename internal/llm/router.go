@@ -3,22 +3,38 @@ package llm
 import (
 	"context"
 	"fmt"
+	"log"
+	"sync"
 )
 
 type Router interface {
 	GetProvider() (Provider, error)
-	SendMessage(ctx context.Context, messages []Message) (string, error)
+	GetProviderByName(name string) (Provider, error)
+	GetProviderForUser(userID int64) (Provider, error)
+	SetDefaultForUser(userID int64, name string) error
+	ProviderNames() []string
+	SendMessage(ctx context.Context, messages []Message, opts ...RequestOption) (string, error)
+	Ping(ctx context.Context) error
 }
 
 type router struct {
-	providers  []Provider
-	defaultIdx int
+	providers     []Provider
+	defaultIdx    int
+	systemPrompts map[string]string
+	mu            sync.RWMutex
+	userDefaults  map[int64]string
+	failures      map[string]providerFailure
+	rules         []routingRule
+	redactor      *redactor
+	access        *providerAccess
+	offline       *offlineFallback
 }
 
 func newRouter(providers []Provider, defaultIdx int) Router {
 	return &router{
-		providers:  providers,
-		defaultIdx: defaultIdx,
+		providers:    providers,
+		defaultIdx:   defaultIdx,
+		userDefaults: make(map[int64]string),
 	}
 }
 
@@ -39,11 +55,213 @@ func (r *router) GetProvider() (Provider, error) {
 	return nil, fmt.Errorf("no LLM provider enabled")
 }
 
-func (r *router) SendMessage(ctx context.Context, messages []Message) (string, error) {
+func (r *router) GetProviderByName(name string) (Provider, error) {
+	for _, p := range r.providers {
+		if p.Name() == name && p.IsEnabled() {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("provider %s not enabled", name)
+}
+
+func (r *router) GetProviderForUser(userID int64) (Provider, error) {
+	return r.providerForUser(userID, userID, "")
+}
+
+// providerForUser returns the provider chosen under profile, which is
+// usually userID, or fallback, as far as userID may use it.
+func (r *router) providerForUser(userID, profile int64, fallback string) (Provider, error) {
+	r.mu.RLock()
+	name, ok := r.userDefaults[profile]
+	r.mu.RUnlock()
+
+	if ok {
+		if provider, err := r.GetProviderByName(name); err == nil {
+			return r.permitted(userID, provider, fallback)
+		}
+	}
+	if fallback != "" {
+		if provider, err := r.GetProviderByName(fallback); err == nil {
+			return r.permitted(userID, provider, fallback)
+		}
+	}
+
 	provider, err := r.GetProvider()
+	if err != nil {
+		return nil, err
+	}
+	return r.permitted(userID, provider, fallback)
+}
+
+func (r *router) SetDefaultForUser(userID int64, name string) error {
+	return r.SetDefaultForProfile(userID, userID, name)
+}
+
+func (r *router) SetDefaultForProfile(userID, profile int64, name string) error {
+	if name != "" {
+		if _, err := r.GetProviderByName(name); err != nil {
+			return err
+		}
+		if !r.access.grantFor(userID).allowsProvider(name) {
+			return fmt.Errorf("%w: %s", ErrProviderNotAllowed, name)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if name == "" {
+		delete(r.userDefaults, profile)
+	} else {
+		r.userDefaults[profile] = name
+	}
+	return nil
+}
+
+func (r *router) ProviderNames() []string {
+	var names []string
+	for _, p := range r.providers {
+		if p.IsEnabled() {
+			names = append(names, p.Name())
+		}
+	}
+	return names
+}
+
+func (r *router) SendMessage(ctx context.Context, messages []Message, opts ...RequestOption) (string, error) {
+	o := newRequestOptions(opts)
+
+	provider, ruleName := r.routeByRule(&o, messages)
+	var err error
+	if provider == nil {
+		if o.provider != "" {
+			provider, err = r.GetProviderByName(o.provider)
+		} else {
+			provider, err = r.providerForUser(o.userID, o.profileKey(), o.defaultProvider)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Rules, personas and command routes can pick a provider or model the
+	// user may not use. The model is dropped along with its provider.
+	allowed, err := r.permitted(o.userID, provider, o.defaultProvider)
 	if err != nil {
 		return "", err
 	}
+	if allowed != provider {
+		provider, o.model = allowed, ""
+	}
+	if !r.access.grantFor(o.userID).allowsModel(o.model) {
+		log.Printf("User %d may not use model %s, using the %s default", o.userID, o.model, provider.Name())
+		o.model = ""
+	}
+
+	if hasImages(messages) {
+		if v, ok := provider.(VisionProvider); !ok || !v.SupportsVision() {
+			return "", fmt.Errorf("%s: %w", provider.Name(), ErrVisionUnsupported)
+		}
+	}
+
+	if o.model != "" {
+		ctx = contextWithModel(ctx, o.model)
+	}
+	if o.temperature != nil {
+		ctx = contextWithTemperature(ctx, *o.temperature)
+	}
+
+	if o.onReasoning != nil {
+		ctx = contextWithReasoningReport(ctx, o.onReasoning)
+	}
+	if o.onThinking != nil {
+		ctx = contextWithThinkingReport(ctx, o.onThinking)
+	}
+	if o.onThink != nil {
+		ctx = contextWithThinkReport(ctx, o.onThink)
+	}
+	if o.onTruncation != nil {
+		ctx = contextWithTruncationReport(ctx, o.onTruncation)
+	}
+
+	offline := r.offline.replaces(provider)
+	if offline {
+		provider, o.model = r.offline.provider, r.offline.model
+		ctx = contextWithModel(ctx, o.model)
+	}
+
+	route := newRoute(ruleName, provider, o.model, offline)
+	if route.Rule != "" {
+		log.Printf("Routing rule %q matched: %s/%s", route.Rule, route.Provider, route.Model)
+	}
+	if o.onRoute != nil {
+		o.onRoute(route)
+	}
+
+	resp, err := r.send(ctx, provider, messages)
+	if !offline && r.offline.failed(provider, err) {
+		fallback := r.offline.provider
+		ctx = contextWithModel(ctx, r.offline.model)
+		if o.onRoute != nil {
+			o.onRoute(newRoute(ruleName, fallback, r.offline.model, true))
+		}
+		resp, err = r.send(ctx, fallback, messages)
+	}
+	return resp, err
+}
+
+func newRoute(rule string, provider Provider, model string, offline bool) Route {
+	route := Route{Rule: rule, Provider: provider.Name(), Model: model, Offline: offline}
+	if route.Model == "" {
+		if mp, ok := provider.(ModelProvider); ok {
+			route.Model = mp.Model()
+		}
+	}
+	return route
+}
+
+// send adds provider's system prompt and redacts messages when the
+// provider is not exempt.
+func (r *router) send(ctx context.Context, provider Provider, messages []Message) (string, error) {
+	messages = withSystemPrompt(messages, r.systemPrompts[provider.Name()])
+	if !r.redactor.applies(provider.Name()) {
+		resp, err := provider.SendMessage(ctx, messages)
+		r.recordFailure(provider.Name(), err)
+		return resp, err
+	}
+
+	redacted, red := r.redactor.redact(messages)
+	resp, err := provider.SendMessage(ctx, redacted)
+	r.recordFailure(provider.Name(), err)
+	return red.restore(resp), err
+}
+
+func (r *router) Ping(ctx context.Context) error {
+	provider, err := r.GetProvider()
+	if err != nil {
+		return err
+	}
+
+	pinger, ok := provider.(Pinger)
+	if !ok {
+		return nil
+	}
+
+	return pinger.Ping(ctx)
+}
+
+func withSystemPrompt(messages []Message, prompt string) []Message {
+	if prompt == "" {
+		return messages
+	}
+	for _, m := range messages {
+		if m.Role == "system" && !IsSummary(m) && !m.Context {
+			return messages
+		}
+	}
 
-	return provider.SendMessage(ctx, messages)
+	result := make([]Message, 0, len(messages)+1)
+	result = append(result, Message{Role: "system", Content: prompt})
+	return append(result, messages...)
 }
@@ -154,3 +154,266 @@ func TestSendMessage(t *testing.T) {
 		})
 	}
 }
+
+type mockPingProvider struct {
+	mockProvider
+	pingErr error
+}
+
+func (m *mockPingProvider) Ping(ctx context.Context) error {
+	return m.pingErr
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []Provider
+		wantErr   bool
+	}{
+		{
+			name:      "provider without ping support is healthy",
+			providers: []Provider{&mockProvider{name: "openai", enabled: true}},
+		},
+		{
+			name:      "ping succeeds",
+			providers: []Provider{&mockPingProvider{mockProvider: mockProvider{name: "openai", enabled: true}}},
+		},
+		{
+			name: "ping fails",
+			providers: []Provider{&mockPingProvider{
+				mockProvider: mockProvider{name: "openai", enabled: true},
+				pingErr:      errors.New("connection refused"),
+			}},
+			wantErr: true,
+		},
+		{
+			name:      "no provider enabled",
+			providers: []Provider{&mockProvider{name: "openai", enabled: false}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRouter(tt.providers, 0)
+			err := r.Ping(context.Background())
+			if tt.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetProviderByName(t *testing.T) {
+	r := newRouter([]Provider{
+		&mockProvider{name: "openai", enabled: true},
+		&mockProvider{name: "anthropic", enabled: false},
+	}, 0)
+
+	p, err := r.GetProviderByName("openai")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Name() != "openai" {
+		t.Errorf("expected openai, got %s", p.Name())
+	}
+
+	if _, err := r.GetProviderByName("anthropic"); err == nil {
+		t.Error("expected error for disabled provider")
+	}
+	if _, err := r.GetProviderByName("missing"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+type recordingProvider struct {
+	mockProvider
+	lastModel    string
+	lastMessages []Message
+}
+
+func (m *recordingProvider) SendMessage(ctx context.Context, messages []Message) (string, error) {
+	m.lastModel = modelFromContext(ctx, "default-model")
+	m.lastMessages = messages
+	return m.name, nil
+}
+
+func TestSendMessage_RequestOptions(t *testing.T) {
+	openai := &recordingProvider{mockProvider: mockProvider{name: "openai", enabled: true}}
+	opencode := &recordingProvider{mockProvider: mockProvider{name: "opencode", enabled: true}}
+	r := newRouter([]Provider{openai, opencode}, 0)
+	msgs := []Message{{Role: "user", Content: "hi"}}
+
+	resp, err := r.SendMessage(context.Background(), msgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp != "openai" || openai.lastModel != "default-model" {
+		t.Errorf("expected default provider and model, got %q/%q", resp, openai.lastModel)
+	}
+
+	resp, err = r.SendMessage(context.Background(), msgs, WithProvider("opencode"), WithModel("cheap-model"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp != "opencode" {
+		t.Errorf("expected opencode provider, got %q", resp)
+	}
+	if opencode.lastModel != "cheap-model" {
+		t.Errorf("expected model override, got %q", opencode.lastModel)
+	}
+
+	if _, err := r.SendMessage(context.Background(), msgs, WithProvider("anthropic")); err == nil {
+		t.Error("expected error for unavailable provider")
+	}
+}
+
+func TestSetDefaultForUser(t *testing.T) {
+	openai := &recordingProvider{mockProvider: mockProvider{name: "openai", enabled: true}}
+	anthropic := &recordingProvider{mockProvider: mockProvider{name: "anthropic", enabled: true}}
+	ollama := &mockProvider{name: "ollama", enabled: false}
+	r := newRouter([]Provider{openai, anthropic, ollama}, 0)
+	msgs := []Message{{Role: "user", Content: "hi"}}
+
+	if err := r.SetDefaultForUser(1, "ollama"); err == nil {
+		t.Error("expected error when switching to a disabled provider")
+	}
+	if err := r.SetDefaultForUser(1, "anthropic"); err != nil {
+		t.Fatalf("SetDefaultForUser() returned error: %v", err)
+	}
+
+	p, err := r.GetProviderForUser(1)
+	if err != nil || p.Name() != "anthropic" {
+		t.Errorf("expected anthropic for user 1, got %v, %v", p, err)
+	}
+	p, _ = r.GetProviderForUser(2)
+	if p.Name() != "openai" {
+		t.Errorf("expected global default for other users, got %s", p.Name())
+	}
+
+	resp, _ := r.SendMessage(context.Background(), msgs, WithUser(1))
+	if resp != "anthropic" {
+		t.Errorf("expected user default to route the message, got %q", resp)
+	}
+	resp, _ = r.SendMessage(context.Background(), msgs, WithUser(1), WithProvider("openai"))
+	if resp != "openai" {
+		t.Errorf("expected explicit provider to win over user default, got %q", resp)
+	}
+
+	if err := r.SetDefaultForUser(1, ""); err != nil {
+		t.Fatalf("SetDefaultForUser() returned error: %v", err)
+	}
+	p, _ = r.GetProviderForUser(1)
+	if p.Name() != "openai" {
+		t.Errorf("expected reset to global default, got %s", p.Name())
+	}
+
+	names := r.ProviderNames()
+	if len(names) != 2 || names[0] != "openai" || names[1] != "anthropic" {
+		t.Errorf("expected enabled provider names, got %v", names)
+	}
+}
+
+func TestSendMessage_DefaultProvider(t *testing.T) {
+	openai := &recordingProvider{mockProvider: mockProvider{name: "openai", enabled: true}}
+	anthropic := &recordingProvider{mockProvider: mockProvider{name: "anthropic", enabled: true}}
+	r := newRouter([]Provider{openai, anthropic}, 0)
+	msgs := []Message{{Role: "user", Content: "hi"}}
+
+	resp, _ := r.SendMessage(context.Background(), msgs, WithUser(1), WithDefaultProvider("anthropic"))
+	if resp != "anthropic" {
+		t.Errorf("expected the default provider to answer, got %q", resp)
+	}
+
+	if err := r.SetDefaultForUser(1, "openai"); err != nil {
+		t.Fatalf("SetDefaultForUser() returned error: %v", err)
+	}
+	resp, _ = r.SendMessage(context.Background(), msgs, WithUser(1), WithDefaultProvider("anthropic"))
+	if resp != "openai" {
+		t.Errorf("expected the user's choice to win over the default provider, got %q", resp)
+	}
+}
+
+func TestSendMessage_Profile(t *testing.T) {
+	openai := &recordingProvider{mockProvider: mockProvider{name: "openai", enabled: true}}
+	anthropic := &recordingProvider{mockProvider: mockProvider{name: "anthropic", enabled: true}}
+	r := newRouter([]Provider{openai, anthropic}, 0).(*router)
+	msgs := []Message{{Role: "user", Content: "hi"}}
+
+	if err := r.SetDefaultForProfile(1, 99, "anthropic"); err != nil {
+		t.Fatalf("SetDefaultForProfile() returned error: %v", err)
+	}
+	if resp, _ := r.SendMessage(context.Background(), msgs, WithUser(1)); resp != "openai" {
+		t.Errorf("expected the choice under another profile to be ignored, got %q", resp)
+	}
+	if resp, _ := r.SendMessage(context.Background(), msgs, WithUser(1), WithProfile(99)); resp != "anthropic" {
+		t.Errorf("expected the profile's choice, got %q", resp)
+	}
+	if p, _ := r.GetProviderForProfile(1, 99); p.Name() != "anthropic" {
+		t.Errorf("expected the profile's provider, got %s", p.Name())
+	}
+}
+
+func TestSendMessage_SystemPrompt(t *testing.T) {
+	openai := &recordingProvider{mockProvider: mockProvider{name: "openai", enabled: true}}
+	r := newRouter([]Provider{openai}, 0).(*router)
+	r.systemPrompts = map[string]string{"openai": "Be concise."}
+
+	r.SendMessage(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if len(openai.lastMessages) != 2 || openai.lastMessages[0].Role != "system" || openai.lastMessages[0].Content != "Be concise." {
+		t.Errorf("expected provider system prompt to be prepended, got %+v", openai.lastMessages)
+	}
+
+	r.SendMessage(context.Background(), []Message{{Role: "system", Content: "Talk like a pirate."}, {Role: "user", Content: "hi"}})
+	if len(openai.lastMessages) != 2 || openai.lastMessages[0].Content != "Talk like a pirate." {
+		t.Errorf("expected existing system prompt to take precedence, got %+v", openai.lastMessages)
+	}
+}
+
+func TestSendMessage_ImagesRequireVision(t *testing.T) {
+	text := &recordingProvider{mockProvider: mockProvider{name: "ollama", enabled: true}}
+	r := newRouter([]Provider{text}, 0)
+
+	_, err := r.SendMessage(context.Background(), []Message{{Role: "user", Images: []Image{{MIMEType: "image/png", Data: []byte("x")}}}})
+	if !errors.Is(err, ErrVisionUnsupported) {
+		t.Errorf("expected ErrVisionUnsupported, got %v", err)
+	}
+}
+
+func TestReloadableRouter_Swap(t *testing.T) {
+	r := NewReloadableRouter(newRouter([]Provider{&mockProvider{name: "openai", enabled: true, response: "old"}}, 0))
+
+	if resp, _ := r.SendMessage(context.Background(), nil); resp != "old" {
+		t.Fatalf("expected response from initial router, got %q", resp)
+	}
+
+	r.Swap(newRouter([]Provider{&mockProvider{name: "anthropic", enabled: true, response: "new"}}, 0))
+
+	if resp, _ := r.SendMessage(context.Background(), nil); resp != "new" {
+		t.Errorf("expected response from swapped router, got %q", resp)
+	}
+	if names := r.ProviderNames(); len(names) != 1 || names[0] != "anthropic" {
+		t.Errorf("unexpected provider names %v", names)
+	}
+}
+
+func TestWithSystemPrompt_IgnoresSummary(t *testing.T) {
+	messages := []Message{SummaryMessage("earlier"), {Role: "user", Content: "hi"}}
+
+	got := withSystemPrompt(messages, "be brief")
+	if len(got) != 3 || got[0].Content != "be brief" {
+		t.Errorf("expected provider prompt to be prepended, got %+v", got)
+	}
+}
+
+func TestWithSystemPrompt_IgnoresContextMessages(t *testing.T) {
+	messages := []Message{ContextMessage("excerpt"), {Role: "user", Content: "hi"}}
+
+	got := withSystemPrompt(messages, "be brief")
+	if len(got) != 3 || got[0].Content != "be brief" {
+		t.Errorf("expected provider prompt to be prepended, got %+v", got)
+	}
+}
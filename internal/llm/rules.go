@@ -0,0 +1,143 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+// Route describes where a request was sent. Rule is empty when no routing
+// rule matched. Offline is set when the request went to the offline
+// fallback because its provider could not be reached.
+type Route struct {
+	Rule     string
+	Provider string
+	Model    string
+	Offline  bool
+}
+
+type routingRule struct {
+	name      string
+	provider  string
+	model     string
+	pattern   *regexp.Regexp
+	keywords  []string
+	minLength int
+	maxLength int
+	minTokens int
+}
+
+func compileRules(rules []config.RoutingRuleConfig) ([]routingRule, error) {
+	compiled := make([]routingRule, 0, len(rules))
+	for _, r := range rules {
+		rule := routingRule{
+			name:      r.Name,
+			provider:  r.Provider,
+			model:     r.Model,
+			minLength: r.MinLength,
+			maxLength: r.MaxLength,
+			minTokens: r.MinTokens,
+		}
+		if r.Pattern != "" {
+			pattern, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("routing rule %s: %w", r.Name, err)
+			}
+			rule.pattern = pattern
+		}
+		for _, k := range r.Keywords {
+			rule.keywords = append(rule.keywords, strings.ToLower(k))
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+// matches reports whether every condition set on the rule holds for the
+// latest user prompt and the size of the whole request.
+func (r routingRule) matches(prompt string, requestTokens int) bool {
+	length := utf8.RuneCountInString(prompt)
+	if r.minLength > 0 && length < r.minLength {
+		return false
+	}
+	if r.maxLength > 0 && length > r.maxLength {
+		return false
+	}
+	if r.minTokens > 0 && requestTokens < r.minTokens {
+		return false
+	}
+	if r.pattern != nil && !r.pattern.MatchString(prompt) {
+		return false
+	}
+	if len(r.keywords) > 0 {
+		lower := strings.ToLower(prompt)
+		found := false
+		for _, k := range r.keywords {
+			if strings.Contains(lower, k) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchRule returns the first rule matching messages. Users who picked a
+// provider with /switch keep it and are not routed.
+func (r *router) matchRule(userID int64, messages []Message) (routingRule, bool) {
+	if len(r.rules) == 0 {
+		return routingRule{}, false
+	}
+	if userID != 0 {
+		r.mu.RLock()
+		_, chosen := r.userDefaults[userID]
+		r.mu.RUnlock()
+		if chosen {
+			return routingRule{}, false
+		}
+	}
+
+	prompt := lastUserContent(messages)
+	tokens := CountTokens(messages)
+	for _, rule := range r.rules {
+		if rule.matches(prompt, tokens) {
+			return rule, true
+		}
+	}
+	return routingRule{}, false
+}
+
+// routeByRule returns the provider picked by a matching rule and sets the
+// rule's model. Requests that name a provider or model skip the rules.
+func (r *router) routeByRule(o *requestOptions, messages []Message) (Provider, string) {
+	if o.provider != "" || o.model != "" {
+		return nil, ""
+	}
+	rule, ok := r.matchRule(o.userID, messages)
+	if !ok {
+		return nil, ""
+	}
+	provider, err := r.GetProviderByName(rule.provider)
+	if err != nil {
+		log.Printf("Routing rule %q skipped: %v", rule.name, err)
+		return nil, ""
+	}
+	o.model = rule.model
+	return provider, rule.name
+}
+
+func lastUserContent(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
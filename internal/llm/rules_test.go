@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func newRulesRouter(t *testing.T, rules []config.RoutingRuleConfig) *router {
+	t.Helper()
+	r := newRouter([]Provider{
+		&mockProvider{name: "openai", enabled: true, response: "openai"},
+		&mockProvider{name: "anthropic", enabled: true, response: "anthropic"},
+		&mockProvider{name: "ollama", enabled: true, response: "ollama"},
+	}, 0).(*router)
+
+	compiled, err := compileRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.rules = compiled
+	return r
+}
+
+func TestSendMessage_RoutingRules(t *testing.T) {
+	r := newRulesRouter(t, []config.RoutingRuleConfig{
+		{Name: "code", Provider: "anthropic", Model: "claude-code", Pattern: "(?i)```|\\bfunc\\b|stack trace"},
+		{Name: "short", Provider: "ollama", MaxLength: 20},
+	})
+
+	tests := []struct {
+		prompt string
+		want   string
+		rule   string
+		model  string
+	}{
+		{prompt: "why does this func panic?", want: "anthropic", rule: "code", model: "claude-code"},
+		{prompt: "hi there", want: "ollama", rule: "short"},
+		{prompt: "tell me about the history of the roman empire", want: "openai"},
+	}
+	for _, tt := range tests {
+		var route Route
+		got, err := r.SendMessage(context.Background(), []Message{{Role: "user", Content: tt.prompt}}, WithRouteReport(func(rt Route) { route = rt }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want || route.Rule != tt.rule || route.Model != tt.model {
+			t.Errorf("%q: got %s via %+v, want %s (rule %q, model %q)", tt.prompt, got, route, tt.want, tt.rule, tt.model)
+		}
+	}
+}
+
+func TestSendMessage_RoutingRulesRespectExplicitChoices(t *testing.T) {
+	r := newRulesRouter(t, []config.RoutingRuleConfig{{Name: "short", Provider: "ollama", MaxLength: 20}})
+	messages := []Message{{Role: "user", Content: "hi"}}
+
+	if got, _ := r.SendMessage(context.Background(), messages, WithProvider("anthropic")); got != "anthropic" {
+		t.Errorf("expected explicit provider to win, got %s", got)
+	}
+
+	r.SetDefaultForUser(1, "anthropic")
+	if got, _ := r.SendMessage(context.Background(), messages, WithUser(1)); got != "anthropic" {
+		t.Errorf("expected user's switched provider to win, got %s", got)
+	}
+}
+
+func TestRoutingRule_Conditions(t *testing.T) {
+	rules, _ := compileRules([]config.RoutingRuleConfig{
+		{Name: "long", Provider: "openai", MinTokens: 100},
+		{Name: "keywords", Provider: "openai", Keywords: []string{"SQL", "query"}, MinLength: 5},
+	})
+
+	if rules[0].matches("short", 10) || !rules[0].matches("short", 150) {
+		t.Error("expected min_tokens to compare against the whole request")
+	}
+	if !rules[1].matches("optimize this sql join", 0) {
+		t.Error("expected case-insensitive keyword match")
+	}
+	if rules[1].matches("sql", 0) {
+		t.Error("expected all conditions to be required")
+	}
+}
@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+var thinkBlock = regexp.MustCompile(`(?s)<think>(.*?)</think>`)
+
+// splitThink separates the reasoning that models such as deepseek-r1 put in
+// <think> tags from the answer. Some leave out the opening tag, so
+// everything before a lone closing tag counts as reasoning too.
+func splitThink(s string) (reasoning, answer string) {
+	var parts []string
+	answer = thinkBlock.ReplaceAllStringFunc(s, func(block string) string {
+		parts = append(parts, thinkBlock.FindStringSubmatch(block)[1])
+		return ""
+	})
+	if before, after, ok := strings.Cut(answer, "</think>"); ok {
+		parts = append([]string{before}, parts...)
+		answer = after
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n")), strings.TrimSpace(answer)
+}
+
+// StripThink removes <think> blocks from s.
+func StripThink(s string) string {
+	_, answer := splitThink(s)
+	return answer
+}
+
+type thinkKey struct{}
+
+// WithThinkReport calls fn with the <think> reasoning of answers from
+// providers whose think setting is "spoiler". The answer itself comes
+// without it, so the reasoning is shown as the caller likes and never ends
+// up in the conversation history.
+func WithThinkReport(fn func(reasoning string)) RequestOption {
+	return func(o *requestOptions) {
+		o.onThink = fn
+	}
+}
+
+func contextWithThinkReport(ctx context.Context, fn func(string)) context.Context {
+	return context.WithValue(ctx, thinkKey{}, fn)
+}
+
+// applyThink handles the <think> blocks in a provider's answer as its think
+// setting says. Spoilered reasoning is split off and reported to the
+// WithThinkReport callback.
+func applyThink(ctx context.Context, mode, s string) string {
+	switch mode {
+	case "strip":
+		return StripThink(s)
+	case "spoiler":
+		reasoning, answer := splitThink(s)
+		if fn, ok := ctx.Value(thinkKey{}).(func(string)); ok && reasoning != "" {
+			fn(reasoning)
+		}
+		return answer
+	}
+	return s
+}
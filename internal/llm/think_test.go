@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestApplyThink(t *testing.T) {
+	answer := "<think>\nThe user greets me.\n\nGreet back.\n</think>\n\nHello!"
+	tests := []struct {
+		name string
+		mode string
+		in   string
+		want string
+	}{
+		{"keep", "", answer, answer},
+		{"strip", "strip", answer, "Hello!"},
+		{"strip missing opening tag", "strip", "reasoning goes here</think>\nThe answer", "The answer"},
+		{"spoiler", "spoiler", answer, "Hello!"},
+		{"spoiler without reasoning", "spoiler", "Hello!", "Hello!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyThink(context.Background(), tt.mode, tt.in); got != tt.want {
+				t.Errorf("applyThink(%q) = %q, want %q", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyThink_ReportsSpoileredReasoning(t *testing.T) {
+	var reasoning string
+	ctx := contextWithThinkReport(context.Background(), func(s string) { reasoning = s })
+
+	if got := applyThink(ctx, "spoiler", "<think>Greet back.</think>Hello!"); got != "Hello!" {
+		t.Errorf("expected the answer without reasoning, got %q", got)
+	}
+	if reasoning != "Greet back." {
+		t.Errorf("expected the reasoning to be reported, got %q", reasoning)
+	}
+
+	reasoning = ""
+	applyThink(ctx, "strip", "<think>Greet back.</think>Hello!")
+	if reasoning != "" {
+		t.Errorf("expected stripped reasoning not to be reported, got %q", reasoning)
+	}
+}
@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// messageOverheadTokens approximates the role and separator tokens chat
+	// formats add around every message.
+	messageOverheadTokens = 4
+	// imageTokens is a flat estimate for one attached image.
+	imageTokens = 765
+)
+
+// EstimateTokens approximates how many tokens text uses in BPE tokenizers
+// such as tiktoken's cl100k: about four characters per token for English,
+// with every punctuation mark and non-Latin character counted separately.
+func EstimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+
+	tokens := 0
+	run := 0
+	flush := func() {
+		tokens += (run + 3) / 4
+		run = 0
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			run++
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// CountTokens estimates the prompt size of messages.
+func CountTokens(messages []Message) int {
+	total := 0
+	for _, m := range messages {
+		total += messageOverheadTokens + EstimateTokens(m.Content) + len(m.Images)*imageTokens
+	}
+	return total
+}
+
+// tokenScale corrects the cl100k based estimate for providers whose
+// tokenizers split the same text into more tokens.
+var tokenScale = map[string]float64{
+	"anthropic": 1.2,
+	"ollama":    1.1,
+}
+
+// CountTokensFor estimates the prompt size of messages in the tokenizer of
+// the named provider.
+func CountTokensFor(provider string, messages []Message) int {
+	total := CountTokens(messages)
+	if scale, ok := tokenScale[provider]; ok {
+		total = int(float64(total)*scale + 0.5)
+	}
+	return total
+}
+
+// TrimToTokens drops the oldest conversation messages until messages fit in
+// limit tokens. System messages, pinned messages and the final prompt are
+// always kept. A limit of zero or less disables trimming.
+func TrimToTokens(messages []Message, limit int) []Message {
+	if limit <= 0 || len(messages) == 0 {
+		return messages
+	}
+
+	total := CountTokens(messages)
+	if total <= limit {
+		return messages
+	}
+
+	last := len(messages) - 1
+	drop := make([]bool, len(messages))
+	for i := 0; i < last && total > limit; i++ {
+		if messages[i].Role == "system" || messages[i].Pinned {
+			continue
+		}
+		drop[i] = true
+		total -= CountTokens(messages[i : i+1])
+	}
+
+	result := make([]Message, 0, len(messages))
+	for i, m := range messages {
+		if !drop[i] {
+			result = append(result, m)
+		}
+	}
+	return result
+}
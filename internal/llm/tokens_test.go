@@ -0,0 +1,51 @@
+package llm
+
+import "testing"
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hi", 1},
+		{"hello world", 4},
+		{"Hello, world!", 6},
+		{"日本語", 3},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTrimToTokens_KeepsSystemAndPrompt(t *testing.T) {
+	messages := []Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "user", Content: "first question about something long"},
+		{Role: "assistant", Content: "first answer about something long"},
+		{Role: "user", Content: "second"},
+	}
+
+	limit := CountTokens([]Message{messages[0], messages[3]})
+	got := TrimToTokens(messages, limit)
+	if len(got) != 2 || got[0].Content != "be brief" || got[1].Content != "second" {
+		t.Errorf("expected system message and prompt only, got %+v", got)
+	}
+
+	if got := TrimToTokens(messages, 0); len(got) != len(messages) {
+		t.Errorf("expected no trimming without a limit, got %+v", got)
+	}
+}
+
+func TestCountTokensFor(t *testing.T) {
+	messages := []Message{{Role: "user", Content: "hello world, how are you today?"}}
+	base := CountTokens(messages)
+	if got := CountTokensFor("openai", messages); got != base {
+		t.Errorf("expected the base estimate for openai, got %d want %d", got, base)
+	}
+	if got := CountTokensFor("anthropic", messages); got <= base {
+		t.Errorf("expected a larger estimate for anthropic, got %d base %d", got, base)
+	}
+}
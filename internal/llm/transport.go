@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/jrswab/helpi/internal/config"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// newHTTPClient returns a client that goes through cfg's proxy and adds its
+// extra headers. Without a proxy configured the HTTP_PROXY and HTTPS_PROXY
+// environment variables apply as usual. Either way, hosts listed in
+// NO_PROXY and loopback addresses, where Ollama and local servers usually
+// run, are reached directly.
+func newHTTPClient(cfg config.ProviderConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch cfg.Proxy {
+	case "":
+	case "none":
+		transport.Proxy = nil
+	default:
+		transport.Proxy = proxyFunc(cfg.Proxy)
+	}
+
+	var rt http.RoundTripper = transport
+	if len(cfg.Headers) > 0 {
+		rt = &headerTransport{base: transport, headers: cfg.Headers}
+	}
+	return &http.Client{Transport: rt}
+}
+
+// proxyFunc sends requests through proxy except those to NO_PROXY hosts and
+// loopback addresses.
+func proxyFunc(proxy string) func(*http.Request) (*url.URL, error) {
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	fn := (&httpproxy.Config{HTTPProxy: proxy, HTTPSProxy: proxy, NoProxy: noProxy}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}
+}
+
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
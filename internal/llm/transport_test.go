@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/config"
+)
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	tests := []struct {
+		name  string
+		proxy string
+		want  string
+	}{
+		{name: "socks5", proxy: "socks5://127.0.0.1:1080", want: "socks5://127.0.0.1:1080"},
+		{name: "none", proxy: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+			transport := newHTTPClient(config.ProviderConfig{Proxy: tt.proxy}).Transport.(*http.Transport)
+			if tt.want == "" {
+				if transport.Proxy != nil {
+					t.Error("expected no proxy")
+				}
+				return
+			}
+			u, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "api.openai.com"}})
+			if err != nil || u == nil || u.String() != tt.want {
+				t.Errorf("proxy = %v (%v), want %s", u, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_ProxySkipsLocalHosts(t *testing.T) {
+	t.Setenv("NO_PROXY", "internal.example")
+	transport := newHTTPClient(config.ProviderConfig{Proxy: "http://proxy.corp.example:3128"}).Transport.(*http.Transport)
+
+	for host, proxied := range map[string]bool{
+		"api.openai.com":         true,
+		"localhost:11434":        false,
+		"127.0.0.1:8080":         false,
+		"[::1]:11434":            false,
+		"llm.internal.example":   false,
+		"internal.example:11434": false,
+	} {
+		u, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "http", Host: host}})
+		if err != nil || (u != nil) != proxied {
+			t.Errorf("%s: proxy = %v (%v), want proxied %v", host, u, err, proxied)
+		}
+	}
+}
+
+func TestOllamaProvider_SendsHeaders(t *testing.T) {
+	var got http.Header
+	provider := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"Hi"},"done":true}`)
+	}, config.ProviderConfig{DefaultModel: "llama3.2", Headers: map[string]string{"cf-aig-authorization": "Bearer token"}})
+
+	if _, err := provider.SendMessage(context.Background(), []Message{{Role: "user", Content: "Hello"}}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if got.Get("cf-aig-authorization") != "Bearer token" {
+		t.Errorf("unexpected headers %v", got)
+	}
+}
+
+func TestCompatibleProvider_ThroughProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"Hi"}}]}`)
+	}))
+	defer proxy.Close()
+
+	provider := NewOpenAICompatibleProvider(config.CustomProviderConfig{
+		Name:    "gateway",
+		BaseURL: "http://llm.internal/v1",
+		ProviderConfig: config.ProviderConfig{
+			Enabled:      true,
+			DefaultModel: "gpt-4o-mini",
+			Proxy:        proxy.URL,
+		},
+	})
+	if _, err := provider.SendMessage(context.Background(), []Message{{Role: "user", Content: "Hello"}}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if proxied != "http://llm.internal/v1/chat/completions" {
+		t.Errorf("request went to %q, want it through the proxy", proxied)
+	}
+}
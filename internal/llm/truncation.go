@@ -0,0 +1,29 @@
+package llm
+
+import "context"
+
+type truncationKey struct{}
+
+// WithTruncationReport calls fn when the answer was cut off because it
+// reached the provider's max_tokens limit.
+func WithTruncationReport(fn func()) RequestOption {
+	return func(o *requestOptions) {
+		o.onTruncation = fn
+	}
+}
+
+func contextWithTruncationReport(ctx context.Context, fn func()) context.Context {
+	return context.WithValue(ctx, truncationKey{}, fn)
+}
+
+func reportTruncation(ctx context.Context, truncated bool) {
+	if fn, ok := ctx.Value(truncationKey{}).(func()); ok && truncated {
+		fn()
+	}
+}
+
+// Reasons providers give for answers that hit the token limit.
+const (
+	finishReasonLength        = "length"
+	incompleteMaxOutputTokens = "max_output_tokens"
+)
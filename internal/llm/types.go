@@ -1,6 +1,103 @@
 package llm
 
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrVisionUnsupported = errors.New("provider does not support images")
+
+const summaryPrefix = "Summary of the earlier conversation:\n"
+
 type Message struct {
 	Role    string
 	Content string
+	Images  []Image `json:",omitempty"`
+	// Context marks system messages that add reference material for a
+	// single request rather than instructions.
+	Context bool `json:",omitempty"`
+	// Pinned messages are kept when history is truncated or summarized.
+	Pinned bool `json:",omitempty"`
+
+	// The fields below describe stored messages and are never sent to a
+	// provider. Messages saved before they existed leave them empty.
+	ID        string      `json:",omitempty"`
+	Timestamp time.Time   `json:",omitzero"`
+	Provider  string      `json:",omitempty"`
+	Model     string      `json:",omitempty"`
+	Tokens    TokenCounts `json:",omitzero"`
+	// Rating is the user's 👍 (1) or 👎 (-1) on an answer.
+	Rating int `json:",omitempty"`
+}
+
+// TokenCounts are the estimated tokens of the request an answer was
+// generated from and of the answer itself.
+type TokenCounts struct {
+	Prompt     int `json:",omitempty"`
+	Completion int `json:",omitempty"`
+}
+
+// Stamped returns m with an ID and timestamp, keeping those it already
+// has. Messages are stamped when they are added to a session.
+func Stamped(m Message) Message {
+	if m.ID == "" {
+		m.ID = newMessageID()
+	}
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+	return m
+}
+
+func newMessageID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type Image struct {
+	MIMEType string
+	Data     []byte
+}
+
+func (i Image) DataURL() string {
+	return "data:" + i.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(i.Data)
+}
+
+func hasImages(messages []Message) bool {
+	for _, m := range messages {
+		if len(m.Images) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func SummaryMessage(summary string) Message {
+	return Message{Role: "system", Content: summaryPrefix + summary}
+}
+
+// ContextMessage wraps reference material, such as document excerpts, that
+// should not replace a provider's configured system prompt.
+func ContextMessage(content string) Message {
+	return Message{Role: "system", Content: content, Context: true}
+}
+
+func IsSummary(m Message) bool {
+	return m.Role == "system" && strings.HasPrefix(m.Content, summaryPrefix)
+}
+
+func SummaryText(m Message) string {
+	return strings.TrimPrefix(m.Content, summaryPrefix)
+}
+
+func joinSystem(existing, content string) string {
+	if existing == "" {
+		return content
+	}
+	return existing + "\n\n" + content
 }
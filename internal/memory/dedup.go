@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// Deduplicator drops turns of a conversation that repeat a later turn, such
+// as a question asked again and answered again, so the repeat is not paid
+// for on every request. The newest copy is kept since its answer is the
+// most current.
+type Deduplicator struct {
+	embeddings *embeddingCache
+	threshold  float64
+}
+
+// NewDeduplicator treats turns whose embeddings have a cosine similarity of
+// at least threshold as duplicates.
+func NewDeduplicator(embedder llm.Embedder, model string, threshold float64) *Deduplicator {
+	if threshold <= 0 || threshold > 1 {
+		threshold = 0.95
+	}
+	return &Deduplicator{
+		embeddings: newEmbeddingCache(embedder, model),
+		threshold:  threshold,
+	}
+}
+
+// Deduplicate returns history without the earlier copies of repeated turns.
+// System, summary and pinned messages are always kept.
+func (d *Deduplicator) Deduplicate(ctx context.Context, history []llm.Message) ([]llm.Message, error) {
+	// Turns start at each user message. Kept messages that are not part of
+	// a turn are marked with -1.
+	var turns []turn
+	owner := make([]int, len(history))
+	for i, m := range history {
+		if m.Role == "system" || m.Pinned {
+			owner[i] = -1
+			continue
+		}
+		if m.Role == "user" || len(turns) == 0 {
+			turns = append(turns, turn{index: len(turns)})
+		}
+		owner[i] = len(turns) - 1
+		turns[len(turns)-1].messages = append(turns[len(turns)-1].messages, m)
+	}
+	if len(turns) < 2 {
+		return history, nil
+	}
+
+	texts := make([]string, len(turns))
+	for i, t := range turns {
+		texts[i] = turnText(t)
+	}
+	vectors, err := d.embeddings.embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicate := make([]bool, len(turns))
+	for i := range turns {
+		for j := i + 1; j < len(turns); j++ {
+			if cosine(vectors[i], vectors[j]) >= d.threshold {
+				duplicate[i] = true
+				break
+			}
+		}
+	}
+
+	result := make([]llm.Message, 0, len(history))
+	for i, m := range history {
+		if owner[i] >= 0 && duplicate[owner[i]] {
+			continue
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
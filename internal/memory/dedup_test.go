@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestDeduplicator_DropsEarlierRepeats(t *testing.T) {
+	var history []llm.Message
+	history = append(history, llm.SummaryMessage("earlier chat about fish"))
+	history = append(history, exchange("tell me about cats", "cats purr")...)
+	history = append(history, exchange("what do dogs eat", "dogs eat kibble")...)
+	history = append(history, llm.Message{Role: "user", Content: "my cat is called Tom", Pinned: true})
+	history = append(history, exchange("cats, tell me about them", "cats purr and sleep")...)
+
+	embedder := &keywordEmbedder{}
+	d := NewDeduplicator(embedder, "", 0.95)
+	got, err := d.Deduplicate(context.Background(), history)
+	if err != nil {
+		t.Fatalf("Deduplicate() returned error: %v", err)
+	}
+
+	want := []string{llm.SummaryMessage("earlier chat about fish").Content, "what do dogs eat", "dogs eat kibble", "my cat is called Tom", "cats, tell me about them", "cats purr and sleep"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d messages, got %d: %+v", len(want), len(got), got)
+	}
+	for i, w := range want {
+		if got[i].Content != w {
+			t.Errorf("message %d: expected %q, got %q", i, w, got[i].Content)
+		}
+	}
+
+	// Embeddings are cached across calls.
+	if _, err := d.Deduplicate(context.Background(), history); err != nil {
+		t.Fatalf("Deduplicate() returned error: %v", err)
+	}
+	if embedder.calls != 1 {
+		t.Errorf("expected cached embeddings to be reused, got %d calls", embedder.calls)
+	}
+}
+
+func TestDeduplicator_NoDuplicates(t *testing.T) {
+	history := append(exchange("cats?", "cats"), exchange("dogs?", "dogs")...)
+	got, err := NewDeduplicator(&keywordEmbedder{}, "", 0).Deduplicate(context.Background(), history)
+	if err != nil || len(got) != len(history) {
+		t.Errorf("expected history unchanged, got %+v, %v", got, err)
+	}
+
+	if _, err := NewDeduplicator(&keywordEmbedder{err: errors.New("down")}, "", 0.9).Deduplicate(context.Background(), history); err == nil {
+		t.Error("expected the embedding error to be returned")
+	}
+}
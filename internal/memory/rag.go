@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const maxMemoriesPerUser = 2000
+
+type Memory struct {
+	User      string    `json:"user"`
+	Assistant string    `json:"assistant"`
+	Vector    []float64 `json:"vector"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LongTermStore struct {
+	embedder llm.Embedder
+	model    string
+	dir      string
+	topK     int
+	minScore float64
+
+	mu    sync.Mutex
+	cache map[int64]cachedMemories
+}
+
+// cachedMemories are the memories of a user as last read from or written
+// to their file. They are read again once the file's modification time or
+// size changes.
+type cachedMemories struct {
+	memories []Memory
+	modTime  time.Time
+	size     int64
+}
+
+func NewLongTermStore(embedder llm.Embedder, model, dir string, topK int, minScore float64) (*LongTermStore, error) {
+	if dir == "" {
+		dir = "./data/memory"
+	}
+	if topK <= 0 {
+		topK = 3
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create memory directory: %w", err)
+	}
+
+	return &LongTermStore{
+		embedder: embedder,
+		model:    model,
+		dir:      dir,
+		topK:     topK,
+		minScore: minScore,
+		cache:    make(map[int64]cachedMemories),
+	}, nil
+}
+
+func (s *LongTermStore) Remember(ctx context.Context, userID int64, user, assistant string) error {
+	vectors, err := s.embedder.Embed(ctx, s.model, []string{exchangeText(user, assistant)})
+	if err != nil {
+		return fmt.Errorf("failed to embed exchange: %w", err)
+	}
+	if len(vectors) != 1 {
+		return fmt.Errorf("expected 1 embedding, got %d", len(vectors))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	memories, err := s.read(userID)
+	if err != nil {
+		return err
+	}
+	memories = append(memories[:len(memories):len(memories)], Memory{
+		User:      user,
+		Assistant: assistant,
+		Vector:    vectors[0],
+		CreatedAt: time.Now(),
+	})
+	if len(memories) > maxMemoriesPerUser {
+		memories = memories[len(memories)-maxMemoriesPerUser:]
+	}
+	return s.write(userID, memories)
+}
+
+func (s *LongTermStore) Recall(ctx context.Context, userID int64, query string) ([]Memory, error) {
+	s.mu.Lock()
+	memories, err := s.read(userID)
+	s.mu.Unlock()
+	if err != nil || len(memories) == 0 {
+		return nil, err
+	}
+
+	vectors, err := s.embedder.Embed(ctx, s.model, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("expected 1 embedding, got %d", len(vectors))
+	}
+
+	type scored struct {
+		memory Memory
+		score  float64
+	}
+	var candidates []scored
+	for _, m := range memories {
+		if score := cosine(vectors[0], m.Vector); score >= s.minScore {
+			candidates = append(candidates, scored{memory: m, score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+	if len(candidates) > s.topK {
+		candidates = candidates[:s.topK]
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].memory.CreatedAt.Before(candidates[j].memory.CreatedAt)
+	})
+
+	result := make([]Memory, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.memory
+	}
+	return result, nil
+}
+
+// read returns the memories of userID, from the cache unless their file
+// changed since it was last read. The returned slice is shared and must not
+// be modified.
+func (s *LongTermStore) read(userID int64) ([]Memory, error) {
+	info, err := os.Stat(s.path(userID))
+	if os.IsNotExist(err) {
+		delete(s.cache, userID)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memories: %w", err)
+	}
+	if c, ok := s.cache[userID]; ok && c.modTime.Equal(info.ModTime()) && c.size == info.Size() {
+		return c.memories, nil
+	}
+
+	data, err := os.ReadFile(s.path(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memories: %w", err)
+	}
+
+	var memories []Memory
+	if err := json.Unmarshal(data, &memories); err != nil {
+		return nil, fmt.Errorf("failed to parse memories: %w", err)
+	}
+	s.cache[userID] = cachedMemories{memories: memories, modTime: info.ModTime(), size: int64(len(data))}
+	return memories, nil
+}
+
+func (s *LongTermStore) write(userID int64, memories []Memory) error {
+	data, err := json.Marshal(memories)
+	if err != nil {
+		return fmt.Errorf("failed to marshal memories: %w", err)
+	}
+	if err := os.WriteFile(s.path(userID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write memories: %w", err)
+	}
+	if info, err := os.Stat(s.path(userID)); err == nil {
+		s.cache[userID] = cachedMemories{memories: memories, modTime: info.ModTime(), size: info.Size()}
+	} else {
+		delete(s.cache, userID)
+	}
+	return nil
+}
+
+func (s *LongTermStore) path(userID int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%d.json", userID))
+}
+
+func exchangeText(user, assistant string) string {
+	return "user: " + user + "\nassistant: " + assistant
+}
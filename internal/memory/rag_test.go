@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLongTermStore_RecallsRelevantExchanges(t *testing.T) {
+	s, err := NewLongTermStore(&keywordEmbedder{}, "", t.TempDir(), 1, 0.5)
+	if err != nil {
+		t.Fatalf("NewLongTermStore() returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, ex := range [][2]string{
+		{"my cat is called Miso", "cute name"},
+		{"I also have a dog", "dogs are great"},
+	} {
+		if err := s.Remember(ctx, 1, ex[0], ex[1]); err != nil {
+			t.Fatalf("Remember() returned error: %v", err)
+		}
+	}
+
+	got, err := s.Recall(ctx, 1, "what is my cat's name?")
+	if err != nil {
+		t.Fatalf("Recall() returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].User != "my cat is called Miso" {
+		t.Fatalf("expected the cat exchange, got %+v", got)
+	}
+
+	if got, _ := s.Recall(ctx, 1, "tell me about fish"); len(got) != 0 {
+		t.Errorf("expected nothing above min score, got %+v", got)
+	}
+	if got, _ := s.Recall(ctx, 2, "cat"); len(got) != 0 {
+		t.Errorf("expected memories to be per user, got %+v", got)
+	}
+}
+
+func TestLongTermStore_RereadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLongTermStore(&keywordEmbedder{}, "", dir, 1, 0.5)
+	if err != nil {
+		t.Fatalf("NewLongTermStore() returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := s.Remember(ctx, 1, "my cat is called Miso", "cute name"); err != nil {
+		t.Fatalf("Remember() returned error: %v", err)
+	}
+
+	path := filepath.Join(dir, "1.json")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.Repeat([]byte("x"), int(info.Size())), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Recall(ctx, 1, "cat"); err != nil || len(got) != 1 {
+		t.Fatalf("expected the unchanged file to be served from memory, got %+v (%v)", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Recall(ctx, 1, "cat"); err != nil || len(got) != 0 {
+		t.Errorf("expected the changed file to be read again, got %+v (%v)", got, err)
+	}
+}
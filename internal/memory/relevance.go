@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const maxCachedEmbeddings = 5000
+
+type RelevanceSelector struct {
+	embeddings *embeddingCache
+	topK       int
+	recent     int
+}
+
+// embeddingCache remembers the embeddings of texts it has seen, since the
+// same history is embedded again on every message.
+type embeddingCache struct {
+	embedder llm.Embedder
+	model    string
+
+	mu    sync.Mutex
+	cache map[string][]float64
+}
+
+func newEmbeddingCache(embedder llm.Embedder, model string) *embeddingCache {
+	return &embeddingCache{embedder: embedder, model: model, cache: make(map[string][]float64)}
+}
+
+func NewRelevanceSelector(embedder llm.Embedder, model string, topK, recent int) *RelevanceSelector {
+	if topK <= 0 {
+		topK = 4
+	}
+	if recent <= 0 {
+		recent = 6
+	}
+	return &RelevanceSelector{
+		embeddings: newEmbeddingCache(embedder, model),
+		topK:       topK,
+		recent:     recent,
+	}
+}
+
+type turn struct {
+	index    int
+	messages []llm.Message
+}
+
+func (s *RelevanceSelector) Select(ctx context.Context, history []llm.Message, prompt string) ([]llm.Message, error) {
+	// Pinned messages are always sent, like system messages.
+	var system, rest []llm.Message
+	for _, m := range history {
+		if m.Role == "system" || m.Pinned {
+			system = append(system, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+
+	if len(rest) <= s.recent {
+		return history, nil
+	}
+
+	older, recent := rest[:len(rest)-s.recent], rest[len(rest)-s.recent:]
+	turns := groupTurns(older)
+	if len(turns) <= s.topK {
+		return history, nil
+	}
+
+	texts := make([]string, len(turns)+1)
+	texts[0] = prompt
+	for i, t := range turns {
+		texts[i+1] = turnText(t)
+	}
+
+	vectors, err := s.embeddings.embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		turn  turn
+		score float64
+	}
+	scoredTurns := make([]scored, len(turns))
+	for i, t := range turns {
+		scoredTurns[i] = scored{turn: t, score: cosine(vectors[0], vectors[i+1])}
+	}
+	sort.SliceStable(scoredTurns, func(i, j int) bool {
+		return scoredTurns[i].score > scoredTurns[j].score
+	})
+
+	selected := make([]turn, 0, s.topK)
+	for _, st := range scoredTurns[:s.topK] {
+		selected = append(selected, st.turn)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].index < selected[j].index
+	})
+
+	result := make([]llm.Message, 0, len(system)+len(recent)+2*s.topK)
+	result = append(result, system...)
+	for _, t := range selected {
+		result = append(result, t.messages...)
+	}
+	result = append(result, recent...)
+	return result, nil
+}
+
+func (s *embeddingCache) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	var missing []string
+	var missingIdx []int
+
+	s.mu.Lock()
+	for i, text := range texts {
+		if v, ok := s.cache[cacheKey(text)]; ok {
+			vectors[i] = v
+		} else {
+			missing = append(missing, text)
+			missingIdx = append(missingIdx, i)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(missing) == 0 {
+		return vectors, nil
+	}
+
+	embedded, err := s.embedder.Embed(ctx, s.model, missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed messages: %w", err)
+	}
+	if len(embedded) != len(missing) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(missing), len(embedded))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache)+len(missing) > maxCachedEmbeddings {
+		s.cache = make(map[string][]float64)
+	}
+	for j, i := range missingIdx {
+		vectors[i] = embedded[j]
+		s.cache[cacheKey(missing[j])] = embedded[j]
+	}
+
+	return vectors, nil
+}
+
+func groupTurns(messages []llm.Message) []turn {
+	var turns []turn
+	for _, m := range messages {
+		if m.Role == "user" || len(turns) == 0 {
+			turns = append(turns, turn{index: len(turns)})
+		}
+		last := &turns[len(turns)-1]
+		last.messages = append(last.messages, m)
+	}
+	return turns
+}
+
+func turnText(t turn) string {
+	var text string
+	for _, m := range t.messages {
+		text += m.Role + ": " + m.Content + "\n"
+	}
+	return text
+}
+
+func cacheKey(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type keywordEmbedder struct {
+	calls  int
+	inputs int
+	err    error
+}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	e.calls++
+	e.inputs += len(texts)
+	if e.err != nil {
+		return nil, e.err
+	}
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{
+			float64(strings.Count(text, "cat")),
+			float64(strings.Count(text, "dog")),
+			float64(strings.Count(text, "fish")),
+			0.1,
+		}
+	}
+	return vectors, nil
+}
+
+func exchange(q, a string) []llm.Message {
+	return []llm.Message{{Role: "user", Content: q}, {Role: "assistant", Content: a}}
+}
+
+func TestRelevanceSelector_KeepsRelevantAndRecent(t *testing.T) {
+	var history []llm.Message
+	history = append(history, llm.Message{Role: "system", Content: "be nice"})
+	history = append(history, exchange("tell me about cats", "cats purr")...)
+	history = append(history, exchange("tell me about dogs", "dogs bark")...)
+	history = append(history, exchange("tell me about fish", "fish swim")...)
+	history = append(history, exchange("what is 2+2", "4")...)
+
+	s := NewRelevanceSelector(&keywordEmbedder{}, "", 1, 2)
+	got, err := s.Select(context.Background(), history, "more about dog breeds")
+	if err != nil {
+		t.Fatalf("Select() returned error: %v", err)
+	}
+
+	want := []string{"be nice", "tell me about dogs", "dogs bark", "what is 2+2", "4"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d messages, got %d: %+v", len(want), len(got), got)
+	}
+	for i, w := range want {
+		if got[i].Content != w {
+			t.Errorf("message %d: expected %q, got %q", i, w, got[i].Content)
+		}
+	}
+}
+
+func TestRelevanceSelector_ShortHistoryUnchanged(t *testing.T) {
+	embedder := &keywordEmbedder{}
+	history := exchange("hi", "hello")
+
+	s := NewRelevanceSelector(embedder, "", 2, 4)
+	got, err := s.Select(context.Background(), history, "cat")
+	if err != nil {
+		t.Fatalf("Select() returned error: %v", err)
+	}
+	if len(got) != 2 || embedder.calls != 0 {
+		t.Errorf("expected history unchanged without embedding, got %d messages and %d calls", len(got), embedder.calls)
+	}
+}
+
+func TestRelevanceSelector_CachesEmbeddings(t *testing.T) {
+	embedder := &keywordEmbedder{}
+	var history []llm.Message
+	for _, topic := range []string{"cat", "dog", "fish", "cat dog"} {
+		history = append(history, exchange(topic, topic)...)
+	}
+
+	s := NewRelevanceSelector(embedder, "", 1, 2)
+	s.Select(context.Background(), history, "cat")
+	first := embedder.inputs
+	s.Select(context.Background(), history, "dog")
+
+	if embedder.inputs != first+1 {
+		t.Errorf("expected only the new prompt to be embedded, got %d inputs after %d", embedder.inputs, first)
+	}
+}
+
+func TestRelevanceSelector_EmbedError(t *testing.T) {
+	var history []llm.Message
+	for _, topic := range []string{"cat", "dog", "fish", "bird"} {
+		history = append(history, exchange(topic, topic)...)
+	}
+
+	s := NewRelevanceSelector(&keywordEmbedder{err: errors.New("boom")}, "", 1, 2)
+	if _, err := s.Select(context.Background(), history, "cat"); err == nil {
+		t.Error("expected embedding error to be returned")
+	}
+}
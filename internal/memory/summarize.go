@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+const summarizeInstructions = "Summarize the conversation below so it can be used as context for continuing it later. " +
+	"Keep important facts, names, preferences, decisions, and open questions. Be concise and write in the third person."
+
+type Completer interface {
+	SendMessage(ctx context.Context, messages []llm.Message, opts ...llm.RequestOption) (string, error)
+}
+
+type Summarizer struct {
+	completer   Completer
+	maxMessages int
+	keep        int
+}
+
+func NewSummarizer(completer Completer, maxMessages int) *Summarizer {
+	if maxMessages <= 0 {
+		maxMessages = 50
+	}
+	return &Summarizer{
+		completer:   completer,
+		maxMessages: maxMessages,
+		keep:        maxMessages / 2,
+	}
+}
+
+func (s *Summarizer) Compact(ctx context.Context, history []llm.Message, opts ...llm.RequestOption) ([]llm.Message, error) {
+	if len(history) <= s.maxMessages {
+		return history, nil
+	}
+	return s.compact(ctx, history, s.keep, opts...)
+}
+
+// Shrink summarizes the older half of history whatever its length. It is
+// used when a request no longer fits in the model's context window.
+func (s *Summarizer) Shrink(ctx context.Context, history []llm.Message, opts ...llm.RequestOption) ([]llm.Message, error) {
+	return s.compact(ctx, history, len(history)/2, opts...)
+}
+
+func (s *Summarizer) compact(ctx context.Context, history []llm.Message, keep int, opts ...llm.RequestOption) ([]llm.Message, error) {
+	if len(history) == 0 {
+		return history, nil
+	}
+
+	var previous string
+	rest := history
+	if llm.IsSummary(rest[0]) {
+		previous = llm.SummaryText(rest[0])
+		rest = rest[1:]
+	}
+
+	split := llm.SplitHistory(rest, keep)
+	if split == 0 {
+		return history, nil
+	}
+	// Pinned messages stay as they are instead of being summarized.
+	pinned, older := llm.SplitPinned(rest[:split])
+	if len(older) == 0 {
+		return history, nil
+	}
+
+	summary, err := s.completer.SendMessage(ctx, summaryRequest(previous, older), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+	if strings.TrimSpace(summary) == "" {
+		return nil, fmt.Errorf("failed to summarize conversation: empty summary")
+	}
+
+	compacted := make([]llm.Message, 0, len(pinned)+len(rest)-split+1)
+	compacted = append(compacted, llm.SummaryMessage(strings.TrimSpace(summary)))
+	compacted = append(compacted, pinned...)
+	return append(compacted, rest[split:]...), nil
+}
+
+func summaryRequest(previous string, messages []llm.Message) []llm.Message {
+	var transcript strings.Builder
+	if previous != "" {
+		transcript.WriteString("Earlier summary: " + previous + "\n\n")
+	}
+	for _, m := range messages {
+		switch m.Role {
+		case "user":
+			transcript.WriteString("User: ")
+		case "assistant":
+			transcript.WriteString("Assistant: ")
+		default:
+			continue
+		}
+		transcript.WriteString(m.Content + "\n")
+	}
+
+	return []llm.Message{
+		{Role: "system", Content: summarizeInstructions},
+		{Role: "user", Content: transcript.String()},
+	}
+}
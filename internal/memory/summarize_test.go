@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type fakeCompleter struct {
+	response string
+	err      error
+	last     []llm.Message
+}
+
+func (c *fakeCompleter) SendMessage(ctx context.Context, messages []llm.Message, opts ...llm.RequestOption) (string, error) {
+	c.last = messages
+	return c.response, c.err
+}
+
+func TestSummarizer_UnderLimitUnchanged(t *testing.T) {
+	c := &fakeCompleter{response: "summary"}
+	s := NewSummarizer(c, 4)
+
+	history := exchange("hi", "hello")
+	got, err := s.Compact(context.Background(), history)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || c.last != nil {
+		t.Errorf("expected history to be untouched, got %v", got)
+	}
+}
+
+func TestSummarizer_SummarizesOlderMessages(t *testing.T) {
+	c := &fakeCompleter{response: "User likes cats."}
+	s := NewSummarizer(c, 4)
+
+	var history []llm.Message
+	history = append(history, exchange("I like cats", "noted")...)
+	history = append(history, exchange("and dogs?", "sure")...)
+	history = append(history, exchange("what is 2+2", "4")...)
+
+	got, err := s.Compact(context.Background(), history)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected summary plus 2 recent messages, got %d", len(got))
+	}
+	if !llm.IsSummary(got[0]) || llm.SummaryText(got[0]) != "User likes cats." {
+		t.Errorf("expected summary message first, got %+v", got[0])
+	}
+	if got[1].Content != "what is 2+2" || got[2].Content != "4" {
+		t.Errorf("expected most recent exchange to be kept, got %+v", got[1:])
+	}
+
+	transcript := c.last[len(c.last)-1].Content
+	if !strings.Contains(transcript, "User: I like cats") || strings.Contains(transcript, "2+2") {
+		t.Errorf("unexpected transcript sent for summarization: %q", transcript)
+	}
+}
+
+func TestSummarizer_FoldsPreviousSummary(t *testing.T) {
+	c := &fakeCompleter{response: "new summary"}
+	s := NewSummarizer(c, 4)
+
+	history := []llm.Message{llm.SummaryMessage("old summary")}
+	history = append(history, exchange("a", "b")...)
+	history = append(history, exchange("c", "d")...)
+
+	got, err := s.Compact(context.Background(), history)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if llm.SummaryText(got[0]) != "new summary" {
+		t.Errorf("expected summary to be replaced, got %+v", got[0])
+	}
+	if !strings.Contains(c.last[len(c.last)-1].Content, "old summary") {
+		t.Error("expected previous summary to be included in the request")
+	}
+}
+
+func TestSummarizer_Error(t *testing.T) {
+	s := NewSummarizer(&fakeCompleter{err: errors.New("down")}, 2)
+
+	history := append(exchange("a", "b"), exchange("c", "d")...)
+	if _, err := s.Compact(context.Background(), history); err == nil {
+		t.Error("expected error when summarization fails")
+	}
+}
+
+func TestSummarizer_ShrinkIgnoresLimit(t *testing.T) {
+	c := &fakeCompleter{response: "Talked about pets."}
+	s := NewSummarizer(c, 50)
+
+	var history []llm.Message
+	history = append(history, exchange("I like cats", "noted")...)
+	history = append(history, exchange("and dogs?", "sure")...)
+
+	got, err := s.Shrink(context.Background(), history)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || !llm.IsSummary(got[0]) || got[1].Content != "and dogs?" {
+		t.Errorf("expected the older half to be summarized, got %+v", got)
+	}
+}
+
+func TestSummarizer_KeepsPinnedMessages(t *testing.T) {
+	c := &fakeCompleter{response: "User likes dogs."}
+	s := NewSummarizer(c, 4)
+
+	var history []llm.Message
+	history = append(history, exchange("my name is Ada", "noted")...)
+	history[0].Pinned = true
+	history = append(history, exchange("and dogs?", "sure")...)
+	history = append(history, exchange("what is 2+2", "4")...)
+
+	got, err := s.Compact(context.Background(), history)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 4 || !llm.IsSummary(got[0]) || got[1].Content != "my name is Ada" || got[2].Content != "what is 2+2" {
+		t.Fatalf("expected summary, pinned message and recent exchange, got %+v", got)
+	}
+	if transcript := c.last[len(c.last)-1].Content; strings.Contains(transcript, "Ada") {
+		t.Errorf("expected the pinned message to be left out of the summary, got %q", transcript)
+	}
+}
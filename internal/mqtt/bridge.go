@@ -0,0 +1,138 @@
+package mqtt
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	qos            = 1
+	connectTimeout = 10 * time.Second
+	publishTimeout = 10 * time.Second
+)
+
+// Options configure the connection to the broker. Broker is a URL such as
+// tcp://homeassistant.local:1883, ssl://… or ws://….
+type Options struct {
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+}
+
+// Subscription forwards the messages on Topic, which may contain + and #
+// wildcards, to ChatID through the bot named Bot. When Prompt is set, the
+// model's answer to the prompt and the message is forwarded before it.
+type Subscription struct {
+	Topic    string
+	ChatID   int64
+	Prompt   string
+	Provider string
+	Bot      string
+}
+
+// Message was received on a topic matched by Subscription.
+type Message struct {
+	Subscription Subscription
+	Topic        string
+	Payload      string
+}
+
+// Bridge is a connection to an MQTT broker that keeps its subscriptions
+// across reconnects.
+type Bridge struct {
+	client paho.Client
+
+	mu     sync.Mutex
+	subs   []Subscription
+	handle func(Message)
+}
+
+// Connect connects to the broker, retrying in the background if it is not
+// reachable yet.
+func Connect(opts Options) (*Bridge, error) {
+	b := &Bridge{}
+	clientOpts := paho.NewClientOptions().
+		AddBroker(opts.Broker).
+		SetClientID(opts.ClientID).
+		SetUsername(opts.Username).
+		SetPassword(opts.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOrderMatters(false).
+		SetOnConnectHandler(func(paho.Client) {
+			log.Printf("MQTT: connected to %s", opts.Broker)
+			b.resubscribe()
+		}).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			log.Printf("MQTT: connection to %s lost: %v", opts.Broker, err)
+		})
+
+	b.client = paho.NewClient(clientOpts)
+	token := b.client.Connect()
+	if token.WaitTimeout(connectTimeout) && token.Error() != nil {
+		return nil, fmt.Errorf("connect to %s: %w", opts.Broker, token.Error())
+	}
+	return b, nil
+}
+
+// Subscribe passes every message on subs to handle. Retained messages are
+// skipped, so a restart does not forward old state again.
+func (b *Bridge) Subscribe(subs []Subscription, handle func(Message)) {
+	b.mu.Lock()
+	b.subs = subs
+	b.handle = handle
+	b.mu.Unlock()
+
+	if b.client.IsConnected() {
+		b.resubscribe()
+	}
+}
+
+func (b *Bridge) resubscribe() {
+	b.mu.Lock()
+	subs := b.subs
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		token := b.client.Subscribe(sub.Topic, qos, b.onMessage(sub))
+		go func() {
+			token.Wait()
+			if err := token.Error(); err != nil {
+				log.Printf("MQTT: failed to subscribe to %s: %v", sub.Topic, err)
+			}
+		}()
+	}
+}
+
+func (b *Bridge) onMessage(sub Subscription) paho.MessageHandler {
+	return func(_ paho.Client, m paho.Message) {
+		if m.Retained() || len(m.Payload()) == 0 {
+			return
+		}
+		b.mu.Lock()
+		handle := b.handle
+		b.mu.Unlock()
+		if handle != nil {
+			handle(Message{Subscription: sub, Topic: m.Topic(), Payload: string(m.Payload())})
+		}
+	}
+}
+
+// Publish sends payload to topic and waits for the broker to accept it.
+func (b *Bridge) Publish(topic string, payload []byte) error {
+	token := b.client.Publish(topic, qos, false, payload)
+	if !token.WaitTimeout(publishTimeout) {
+		return fmt.Errorf("publish to %s: timed out", topic)
+	}
+	return token.Error()
+}
+
+// Close disconnects from the broker.
+func (b *Bridge) Close() {
+	b.client.Disconnect(250)
+}
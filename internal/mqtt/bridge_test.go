@@ -0,0 +1,36 @@
+package mqtt
+
+import (
+	"testing"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+type fakeMessage struct {
+	paho.Message
+	topic    string
+	payload  string
+	retained bool
+}
+
+func (m fakeMessage) Topic() string   { return m.topic }
+func (m fakeMessage) Payload() []byte { return []byte(m.payload) }
+func (m fakeMessage) Retained() bool  { return m.retained }
+
+func TestBridge_ForwardsMessages(t *testing.T) {
+	var got []Message
+	b := &Bridge{handle: func(m Message) { got = append(got, m) }}
+	sub := Subscription{Topic: "home/+/alert", ChatID: 42}
+	onMessage := b.onMessage(sub)
+
+	onMessage(nil, fakeMessage{topic: "home/door/alert", payload: "Front door opened"})
+	onMessage(nil, fakeMessage{topic: "home/door/alert", payload: "Front door closed", retained: true})
+	onMessage(nil, fakeMessage{topic: "home/door/alert"})
+
+	if len(got) != 1 {
+		t.Fatalf("expected one forwarded message, got %+v", got)
+	}
+	if got[0].Subscription != sub || got[0].Topic != "home/door/alert" || got[0].Payload != "Front door opened" {
+		t.Errorf("unexpected message %+v", got[0])
+	}
+}
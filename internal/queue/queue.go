@@ -0,0 +1,174 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+type Item struct {
+	// ID is set by Push and identifies the item to Remove.
+	ID       int64     `json:"id,omitempty"`
+	UserID   int64     `json:"user_id"`
+	ChatID   int64     `json:"chat_id"`
+	Text     string    `json:"text"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+type Queue interface {
+	Push(item Item) error
+	Drain() ([]Item, error)
+	// Pending returns the queued items without removing them.
+	Pending() ([]Item, error)
+	Remove(id int64) error
+	Len() (int, error)
+}
+
+type fileQueue struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewQueue(path string) (Queue, error) {
+	if path == "" {
+		path = "./data/queue.json"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	return &fileQueue{path: path}, nil
+}
+
+func (q *fileQueue) Push(item Item) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items, err := q.read()
+	if err != nil {
+		return err
+	}
+
+	if item.QueuedAt.IsZero() {
+		item.QueuedAt = time.Now()
+	}
+	item.ID = nextID(items)
+
+	return q.write(append(items, item))
+}
+
+func (q *fileQueue) Pending() ([]Item, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items, err := q.read()
+	if err != nil {
+		return nil, err
+	}
+
+	// Items queued by older versions have no ID yet.
+	if slices.ContainsFunc(items, func(item Item) bool { return item.ID == 0 }) {
+		for i := range items {
+			if items[i].ID == 0 {
+				items[i].ID = nextID(items)
+			}
+		}
+		if err := q.write(items); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+func (q *fileQueue) Remove(id int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items, err := q.read()
+	if err != nil {
+		return err
+	}
+
+	kept := items[:0]
+	for _, item := range items {
+		if item.ID != id {
+			kept = append(kept, item)
+		}
+	}
+	return q.write(kept)
+}
+
+func nextID(items []Item) int64 {
+	var id int64
+	for _, item := range items {
+		id = max(id, item.ID)
+	}
+	return id + 1
+}
+
+func (q *fileQueue) Drain() ([]Item, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items, err := q.read()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	if err := q.write([]Item{}); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (q *fileQueue) Len() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items, err := q.read()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(items), nil
+}
+
+func (q *fileQueue) read() ([]Item, error) {
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return []Item{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse queue: %w", err)
+	}
+
+	return items, nil
+}
+
+func (q *fileQueue) write(items []Item) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue: %w", err)
+	}
+
+	if err := os.WriteFile(q.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write queue: %w", err)
+	}
+
+	return nil
+}
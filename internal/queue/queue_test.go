@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPushAndDrain(t *testing.T) {
+	q, err := NewQueue(filepath.Join(t.TempDir(), "queue.json"))
+	if err != nil {
+		t.Fatalf("NewQueue() returned error: %v", err)
+	}
+
+	if err := q.Push(Item{UserID: 1, ChatID: 10, Text: "first"}); err != nil {
+		t.Fatalf("Push() returned error: %v", err)
+	}
+	if err := q.Push(Item{UserID: 2, ChatID: 20, Text: "second"}); err != nil {
+		t.Fatalf("Push() returned error: %v", err)
+	}
+
+	n, err := q.Len()
+	if err != nil {
+		t.Fatalf("Len() returned error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 queued items, got %d", n)
+	}
+
+	items, err := q.Drain()
+	if err != nil {
+		t.Fatalf("Drain() returned error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 drained items, got %d", len(items))
+	}
+	if items[0].Text != "first" || items[1].Text != "second" {
+		t.Errorf("expected items in FIFO order, got %q then %q", items[0].Text, items[1].Text)
+	}
+	if items[0].QueuedAt.IsZero() {
+		t.Error("expected QueuedAt to be set on push")
+	}
+
+	n, _ = q.Len()
+	if n != 0 {
+		t.Errorf("expected queue to be empty after drain, got %d", n)
+	}
+}
+
+func TestQueuePersistsAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+
+	q1, _ := NewQueue(path)
+	if err := q1.Push(Item{UserID: 1, ChatID: 1, Text: "persisted"}); err != nil {
+		t.Fatalf("Push() returned error: %v", err)
+	}
+
+	q2, _ := NewQueue(path)
+	items, err := q2.Drain()
+	if err != nil {
+		t.Fatalf("Drain() returned error: %v", err)
+	}
+	if len(items) != 1 || items[0].Text != "persisted" {
+		t.Errorf("expected persisted item, got %+v", items)
+	}
+}
+
+func TestDrain_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	q, _ := NewQueue(path)
+	if _, err := q.Drain(); err == nil {
+		t.Error("expected error for corrupt queue file")
+	}
+}
+
+func TestPendingAndRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	os.WriteFile(path, []byte(`[{"chat_id":1,"text":"old"}]`), 0644)
+	q, err := NewQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Push(Item{ChatID: 2, Text: "new"})
+
+	items, err := q.Pending()
+	if err != nil || len(items) != 2 || items[0].ID == 0 || items[0].ID == items[1].ID {
+		t.Fatalf("expected two items with distinct IDs, got %+v, %v", items, err)
+	}
+	if err := q.Remove(items[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	items, _ = q.Pending()
+	if len(items) != 1 || items[0].Text != "new" {
+		t.Errorf("expected only the new item to be left, got %+v", items)
+	}
+}
@@ -0,0 +1,56 @@
+package quiet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Hours is a daily window such as 22:00-07:00 during which proactive
+// messages are held. A window whose end is before its start runs past
+// midnight.
+type Hours struct {
+	Start int // minutes after midnight
+	End   int
+}
+
+// Parse reads a window written as HH:MM-HH:MM.
+func Parse(s string) (Hours, error) {
+	start, end, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return Hours{}, fmt.Errorf("invalid quiet hours %q (expected HH:MM-HH:MM)", s)
+	}
+	from, err := parseClock(start)
+	if err != nil {
+		return Hours{}, err
+	}
+	to, err := parseClock(end)
+	if err != nil {
+		return Hours{}, err
+	}
+	if from == to {
+		return Hours{}, fmt.Errorf("quiet hours %q start and end at the same time", s)
+	}
+	return Hours{Start: from, End: to}, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", strings.TrimSpace(s))
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether t falls inside the window, in t's location.
+func (h Hours) Contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if h.Start < h.End {
+		return m >= h.Start && m < h.End
+	}
+	return m >= h.Start || m < h.End
+}
+
+func (h Hours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", h.Start/60, h.Start%60, h.End/60, h.End%60)
+}
@@ -0,0 +1,50 @@
+package quiet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	h, err := Parse("22:00-07:30")
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if h.Start != 22*60 || h.End != 7*60+30 {
+		t.Errorf("unexpected window %+v", h)
+	}
+	if h.String() != "22:00-07:30" {
+		t.Errorf("String() = %q", h.String())
+	}
+
+	for _, bad := range []string{"", "22:00", "25:00-07:00", "22:00-7pm", "08:00-08:00"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2026, 1, 1, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		window string
+		t      time.Time
+		want   bool
+	}{
+		{"22:00-07:00", at(23, 15), true},
+		{"22:00-07:00", at(3, 0), true},
+		{"22:00-07:00", at(7, 0), false},
+		{"22:00-07:00", at(12, 0), false},
+		{"13:00-14:00", at(13, 30), true},
+		{"13:00-14:00", at(14, 0), false},
+		{"13:00-14:00", at(9, 0), false},
+	}
+	for _, tt := range tests {
+		h, _ := Parse(tt.window)
+		if got := h.Contains(tt.t); got != tt.want {
+			t.Errorf("%s contains %s = %v, want %v", tt.window, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+type job struct {
+	name string
+	next func(after time.Time) time.Time
+	due  time.Time
+	run  func(ctx context.Context)
+}
+
+type Scheduler struct {
+	mu   sync.Mutex
+	jobs []*job
+	now  func() time.Time
+}
+
+func New() *Scheduler {
+	return &Scheduler{now: time.Now}
+}
+
+func (s *Scheduler) Daily(name, at string, run func(ctx context.Context)) error {
+	hour, minute, err := ParseClock(at)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+
+	next := func(after time.Time) time.Time {
+		t := time.Date(after.Year(), after.Month(), after.Day(), hour, minute, 0, 0, after.Location())
+		if !t.After(after) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t
+	}
+
+	s.add(&job{name: name, next: next, run: run})
+	return nil
+}
+
+// Weekly runs a job every week on weekday at the given HH:MM.
+func (s *Scheduler) Weekly(name string, weekday time.Weekday, at string, run func(ctx context.Context)) error {
+	hour, minute, err := ParseClock(at)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+
+	next := func(after time.Time) time.Time {
+		days := (int(weekday) - int(after.Weekday()) + 7) % 7
+		t := time.Date(after.Year(), after.Month(), after.Day()+days, hour, minute, 0, 0, after.Location())
+		if !t.After(after) {
+			t = t.AddDate(0, 0, 7)
+		}
+		return t
+	}
+
+	s.add(&job{name: name, next: next, run: run})
+	return nil
+}
+
+// Every runs a job at a fixed interval, starting one interval from now.
+func (s *Scheduler) Every(name string, interval time.Duration, run func(ctx context.Context)) error {
+	if interval <= 0 {
+		return fmt.Errorf("job %s: interval must be positive", name)
+	}
+	next := func(after time.Time) time.Time {
+		return after.Add(interval)
+	}
+	s.add(&job{name: name, next: next, run: run})
+	return nil
+}
+
+func (s *Scheduler) Run(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runDue(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) add(j *job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.due = j.next(s.now())
+	s.jobs = append(s.jobs, j)
+}
+
+func (s *Scheduler) runDue(ctx context.Context) {
+	s.mu.Lock()
+	now := s.now()
+	var due []*job
+	for _, j := range s.jobs {
+		if !now.Before(j.due) {
+			due = append(due, j)
+			j.due = j.next(now)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, j := range due {
+		log.Printf("Scheduler: running job %s", j.name)
+		go j.run(ctx)
+	}
+}
+
+// ParseWeekday parses an English weekday name such as "monday".
+func ParseWeekday(day string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(day, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", day)
+}
+
+func ParseClock(at string) (int, int, error) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q (expected HH:MM)", at)
+	}
+	return t.Hour(), t.Minute(), nil
+}
@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	h, m, err := ParseClock("07:30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h != 7 || m != 30 {
+		t.Errorf("expected 07:30, got %02d:%02d", h, m)
+	}
+
+	for _, bad := range []string{"", "7", "25:00", "12:60", "noon"} {
+		if _, _, err := ParseClock(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestDaily_SchedulesNextOccurrence(t *testing.T) {
+	s := New()
+	base := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return base }
+
+	if err := s.Daily("later", "09:00", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("Daily() returned error: %v", err)
+	}
+	if err := s.Daily("earlier", "07:00", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("Daily() returned error: %v", err)
+	}
+
+	if want := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC); !s.jobs[0].due.Equal(want) {
+		t.Errorf("expected later job due %v, got %v", want, s.jobs[0].due)
+	}
+	if want := time.Date(2024, 1, 2, 7, 0, 0, 0, time.UTC); !s.jobs[1].due.Equal(want) {
+		t.Errorf("expected earlier job due %v, got %v", want, s.jobs[1].due)
+	}
+}
+
+func TestWeekly_SchedulesNextOccurrence(t *testing.T) {
+	s := New()
+	// 2024-01-03 is a Wednesday.
+	base := time.Date(2024, 1, 3, 8, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return base }
+
+	s.Weekly("friday", time.Friday, "09:00", func(ctx context.Context) {})
+	s.Weekly("today", time.Wednesday, "09:00", func(ctx context.Context) {})
+	s.Weekly("passed", time.Wednesday, "07:00", func(ctx context.Context) {})
+
+	wants := []time.Time{
+		time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 10, 7, 0, 0, 0, time.UTC),
+	}
+	for i, want := range wants {
+		if !s.jobs[i].due.Equal(want) {
+			t.Errorf("job %s: expected due %v, got %v", s.jobs[i].name, want, s.jobs[i].due)
+		}
+	}
+}
+
+func TestEvery_SchedulesFromNow(t *testing.T) {
+	s := New()
+	base := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return base }
+
+	if err := s.Every("poll", 30*time.Minute, func(ctx context.Context) {}); err != nil {
+		t.Fatalf("Every() returned error: %v", err)
+	}
+	if want := base.Add(30 * time.Minute); !s.jobs[0].due.Equal(want) {
+		t.Errorf("expected due %v, got %v", want, s.jobs[0].due)
+	}
+	if err := s.Every("bad", 0, func(ctx context.Context) {}); err == nil {
+		t.Error("expected error for a zero interval")
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	if d, err := ParseWeekday("Monday"); err != nil || d != time.Monday {
+		t.Errorf("expected Monday, got %v, %v", d, err)
+	}
+	if _, err := ParseWeekday("funday"); err == nil {
+		t.Error("expected error for an unknown weekday")
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	s := New()
+	now := time.Date(2024, 1, 1, 8, 59, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	runs := 0
+	s.Daily("job", "09:00", func(ctx context.Context) {
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		wg.Done()
+	})
+
+	s.runDue(context.Background())
+
+	now = time.Date(2024, 1, 1, 9, 0, 30, 0, time.UTC)
+	wg.Add(1)
+	s.runDue(context.Background())
+	wg.Wait()
+
+	s.runDue(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if runs != 1 {
+		t.Errorf("expected job to run once, got %d", runs)
+	}
+	if want := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC); !s.jobs[0].due.Equal(want) {
+		t.Errorf("expected next run %v, got %v", want, s.jobs[0].due)
+	}
+}
+
+func TestDaily_InvalidTime(t *testing.T) {
+	s := New()
+	if err := s.Daily("bad", "9am", func(ctx context.Context) {}); err == nil {
+		t.Error("expected error for invalid time")
+	}
+}
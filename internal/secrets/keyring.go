@@ -0,0 +1,49 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringService is the service name secrets are stored under in the OS
+// keyring.
+const KeyringService = "helpi"
+
+// KeyringAvailable reports whether the OS keyring can be used. On Linux it
+// requires a desktop session bus, so servers never try to start one.
+func KeyringAvailable() bool {
+	if !desktopSession() {
+		return false
+	}
+	_, err := keyring.Get(KeyringService, "helpi-probe")
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+// FromKeyring returns the value stored for key in the OS keyring, or an
+// empty string when there is none or the keyring cannot be used.
+func FromKeyring(key string) string {
+	if !desktopSession() {
+		return ""
+	}
+	value, err := keyring.Get(KeyringService, key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// StoreInKeyring saves value for key in the OS keyring.
+func StoreInKeyring(key, value string) error {
+	if err := keyring.Set(KeyringService, key, value); err != nil {
+		return fmt.Errorf("failed to store %s in the keyring: %w", key, err)
+	}
+	return nil
+}
+
+func desktopSession() bool {
+	return runtime.GOOS != "linux" || os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+}
@@ -0,0 +1,32 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Backend returns every secret it holds, keyed by environment variable
+// name such as OPENAI_API_KEY.
+type Backend interface {
+	Secrets(ctx context.Context) (map[string]string, error)
+}
+
+// FromFile returns the value of key, or the contents of the file named by
+// key_FILE when key is unset. Trailing newlines, which editors and
+// `kubectl create secret` leave behind, are removed.
+func FromFile(key string) (string, error) {
+	if value := os.Getenv(key); value != "" {
+		return value, nil
+	}
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %w", key, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
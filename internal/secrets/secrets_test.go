@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openai")
+	if err := os.WriteFile(path, []byte("sk-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HELPI_TEST_KEY", "")
+	t.Setenv("HELPI_TEST_KEY_FILE", path)
+	if got, err := FromFile("HELPI_TEST_KEY"); err != nil || got != "sk-file" {
+		t.Errorf("expected the file contents without the newline, got %q, %v", got, err)
+	}
+
+	t.Setenv("HELPI_TEST_KEY", "sk-env")
+	if got, _ := FromFile("HELPI_TEST_KEY"); got != "sk-env" {
+		t.Errorf("expected the variable to win over the file, got %q", got)
+	}
+
+	t.Setenv("HELPI_TEST_KEY", "")
+	t.Setenv("HELPI_TEST_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+	if _, err := FromFile("HELPI_TEST_KEY"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/helpi":
+			w.Write([]byte(`{"data":{"data":{"OPENAI_API_KEY":"sk-v2"},"metadata":{"version":3}}}`))
+		case "/v1/kv/helpi":
+			w.Write([]byte(`{"data":{"OPENAI_API_KEY":"sk-v1","PORT":8080}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	values, err := NewVault(server.URL, "secret/data/helpi", "token").Secrets(context.Background())
+	if err != nil || values["OPENAI_API_KEY"] != "sk-v2" {
+		t.Errorf("expected the KV v2 secret, got %v, %v", values, err)
+	}
+
+	values, err = NewVault(server.URL+"/", "/kv/helpi", "token").Secrets(context.Background())
+	if err != nil || values["OPENAI_API_KEY"] != "sk-v1" || values["PORT"] != "8080" {
+		t.Errorf("expected the KV v1 secret, got %v, %v", values, err)
+	}
+
+	if _, err := NewVault(server.URL, "secret/data/helpi", "wrong").Secrets(context.Background()); err == nil {
+		t.Error("expected an error for a rejected token")
+	}
+}
+
+func TestSOPS(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "sops")
+	content := "#!/bin/sh\necho '{\"ANTHROPIC_API_KEY\":\"sk-ant\"}'\n"
+	if err := os.WriteFile(script, []byte(content), 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer func(orig string) { sopsCommand = orig }(sopsCommand)
+	sopsCommand = script
+
+	values, err := NewSOPS(filepath.Join(dir, "secrets.enc.yaml")).Secrets(context.Background())
+	if err != nil || values["ANTHROPIC_API_KEY"] != "sk-ant" {
+		t.Errorf("expected the decrypted secret, got %v, %v", values, err)
+	}
+
+	sopsCommand = filepath.Join(dir, "missing")
+	if _, err := NewSOPS("secrets.enc.yaml").Secrets(context.Background()); err == nil {
+		t.Error("expected an error when sops cannot run")
+	}
+}
@@ -0,0 +1,43 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// sopsCommand is the sops binary, replaced in tests.
+var sopsCommand = "sops"
+
+type sopsFile struct {
+	path string
+}
+
+// NewSOPS reads secrets from a SOPS encrypted YAML, JSON or .env file whose
+// top-level keys are the secret names. The sops binary must be on PATH and
+// able to reach the decryption key, for example through SOPS_AGE_KEY_FILE.
+func NewSOPS(path string) Backend {
+	return &sopsFile{path: path}
+}
+
+func (s *sopsFile) Secrets(ctx context.Context) (map[string]string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, sopsCommand, "--decrypt", "--output-type", "json", s.path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("sops: decrypting %s failed: %s", s.path, msg)
+		}
+		return nil, fmt.Errorf("sops: decrypting %s failed: %w", s.path, err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &data); err != nil {
+		return nil, fmt.Errorf("sops: invalid output for %s: %w", s.path, err)
+	}
+	return stringValues(data), nil
+}
@@ -0,0 +1,78 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type vault struct {
+	client  *http.Client
+	address string
+	path    string
+	token   string
+}
+
+// NewVault reads secrets from the key/value secret at path, such as
+// secret/data/helpi for a KV version 2 mount or secret/helpi for version 1.
+func NewVault(address, path, token string) Backend {
+	return &vault{
+		client:  &http.Client{Timeout: 15 * time.Second},
+		address: strings.TrimRight(address, "/"),
+		path:    strings.Trim(path, "/"),
+		token:   token,
+	}
+}
+
+func (v *vault) Secrets(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.address+"/v1/"+v.path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault: reading %s failed with HTTP %d: %s", v.path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("vault: invalid response: %w", err)
+	}
+
+	data := secret.Data
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, v2 := data["metadata"]; v2 {
+			data = inner
+		}
+	}
+	return stringValues(data), nil
+}
+
+// stringValues keeps the scalar values of data, formatted as they would be
+// written in an environment file.
+func stringValues(data map[string]any) map[string]string {
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case float64, bool:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,79 @@
+package session
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const backupSuffix = ".bak"
+
+// writeFile replaces path with data without ever leaving a half-written
+// file behind: data goes to a temporary file that is renamed over path.
+// The previous version is kept in path.bak when it is valid JSON, so a
+// corrupted file can be recovered by readFile.
+func writeFile(path string, data []byte) error {
+	if old, err := os.ReadFile(path); err == nil && json.Valid(old) {
+		if err := replaceFile(path+backupSuffix, old); err != nil {
+			return err
+		}
+	}
+	return replaceFile(path, data)
+}
+
+func replaceFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readFile decodes the JSON in path into v. When path does not parse, the
+// backup written by writeFile is decoded instead and put back in place. The
+// returned error wraps os.ErrNotExist when path does not exist.
+func readFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	parseErr := json.Unmarshal(data, v)
+	if parseErr == nil {
+		return nil
+	}
+
+	backup, err := os.ReadFile(path + backupSuffix)
+	if err != nil || json.Unmarshal(backup, v) != nil {
+		return parseErr
+	}
+	log.Printf("Recovered %s from its backup after a parse error: %v", path, parseErr)
+	if err := replaceFile(path, backup); err != nil {
+		log.Printf("Failed to restore %s from its backup: %v", path, err)
+	}
+	return nil
+}
+
+// removeFile deletes path and its backup.
+func removeFile(path string) error {
+	if err := os.Remove(path + backupSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestManager_RecoversCorruptSession(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(dir, 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	first := []llm.Message{{Role: "user", Content: "hello"}}
+	if err := m.Save(1, first); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if err := m.Save(1, append(first, llm.Message{Role: "assistant", Content: "hi"})); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	path := filepath.Join(dir, "1.json")
+	if err := os.WriteFile(path, []byte(`[{"role":"user","con`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := m.Get(1)
+	if err != nil {
+		t.Fatalf("Get() returned error for a corrupt session with a backup: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Content != "hello" {
+		t.Errorf("expected the previous version, got %+v", messages)
+	}
+
+	var restored []llm.Message
+	if err := readFile(path+backupSuffix, &restored); err != nil {
+		t.Fatalf("expected the backup to remain readable: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	backup, _ := os.ReadFile(path + backupSuffix)
+	if string(data) != string(backup) {
+		t.Errorf("expected the backup to be restored in place, got %s", data)
+	}
+}
+
+func TestManager_CorruptSessionWithoutBackup(t *testing.T) {
+	dir := t.TempDir()
+	m, _ := NewManager(dir, 10)
+	if err := os.WriteFile(filepath.Join(dir, "1.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get(1); err == nil {
+		t.Error("expected an error for a corrupt session without a backup")
+	}
+}
+
+func TestManager_WritesLeaveNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	m, _ := NewManager(dir, 10)
+	for i := 0; i < 3; i++ {
+		if err := m.Save(1, []llm.Message{{Role: "user", Content: "hello"}}); err != nil {
+			t.Fatalf("Save() returned error: %v", err)
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".lock") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) != 2 || names[0] != "1.json" || names[1] != "1.json.bak" {
+		t.Errorf("expected only the session and its backup, got %v", names)
+	}
+
+	if err := m.Delete(1); err != nil {
+		t.Fatalf("Delete() returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "1.json.bak")); !os.IsNotExist(err) {
+		t.Errorf("expected Delete to remove the backup too, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache holds serialized sessions in front of the persistent store.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type redisCache struct {
+	client *redis.Client
+}
+
+func NewRedisCache(ctx context.Context, addr, password string, db int) (Cache, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr, Password: password, DB: db})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return &redisCache{client: client}, nil
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *redisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, key).Err()
+}
+
+type cachedManager struct {
+	Manager
+	cache       Cache
+	ttl         time.Duration
+	maxMessages int
+}
+
+// NewCachedManager serves the active session from cache and writes through
+// to store. Cache failures are logged and fall back to store.
+func NewCachedManager(store Manager, cache Cache, ttl time.Duration, maxMessages int) Manager {
+	if maxMessages == 0 {
+		maxMessages = 50
+	}
+	return &cachedManager{Manager: store, cache: cache, ttl: ttl, maxMessages: maxMessages}
+}
+
+func (m *cachedManager) Get(userID int64) ([]llm.Message, error) {
+	ctx := context.Background()
+	data, ok, err := m.cache.Get(ctx, cacheKey(userID))
+	if err != nil {
+		log.Printf("Session cache read failed for user %d: %v", userID, err)
+	}
+	if ok {
+		var messages []llm.Message
+		if err := json.Unmarshal(data, &messages); err == nil {
+			return messages, nil
+		}
+	}
+
+	messages, err := m.Manager.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	m.store(ctx, userID, messages)
+	return messages, nil
+}
+
+func (m *cachedManager) Save(userID int64, messages []llm.Message) error {
+	if err := m.Manager.Save(userID, messages); err != nil {
+		m.invalidate(userID)
+		return err
+	}
+	messages = llm.TrimHistory(messages, m.maxMessages)
+	m.store(context.Background(), userID, messages)
+	return nil
+}
+
+func (m *cachedManager) Delete(userID int64) error {
+	defer m.invalidate(userID)
+	return m.Manager.Delete(userID)
+}
+
+func (m *cachedManager) PopLast(userID int64) (llm.Message, error) {
+	defer m.invalidate(userID)
+	return m.Manager.PopLast(userID)
+}
+
+func (m *cachedManager) ReplaceLast(userID int64, msg llm.Message) error {
+	defer m.invalidate(userID)
+	return m.Manager.ReplaceLast(userID, msg)
+}
+
+func (m *cachedManager) SetRating(userID int64, messageID string, score int) error {
+	defer m.invalidate(userID)
+	return m.Manager.SetRating(userID, messageID, score)
+}
+
+func (m *cachedManager) NewThread(userID int64, title string) (Thread, error) {
+	defer m.invalidate(userID)
+	return m.Manager.NewThread(userID, title)
+}
+
+func (m *cachedManager) ResumeThread(userID int64, id int) (Thread, error) {
+	defer m.invalidate(userID)
+	return m.Manager.ResumeThread(userID, id)
+}
+
+func (m *cachedManager) RenameThread(userID int64, id int, title string) error {
+	renamer, ok := m.Manager.(ThreadRenamer)
+	if !ok {
+		return fmt.Errorf("session backend cannot rename threads")
+	}
+	return renamer.RenameThread(userID, id, title)
+}
+
+func (m *cachedManager) store(ctx context.Context, userID int64, messages []llm.Message) {
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return
+	}
+	if err := m.cache.Set(ctx, cacheKey(userID), data, m.ttl); err != nil {
+		log.Printf("Session cache write failed for user %d: %v", userID, err)
+	}
+}
+
+func (m *cachedManager) invalidate(userID int64) {
+	if err := m.cache.Delete(context.Background(), cacheKey(userID)); err != nil {
+		log.Printf("Session cache invalidation failed for user %d: %v", userID, err)
+	}
+}
+
+// cacheKey names the user's active session. Switching threads invalidates it.
+func cacheKey(userID int64) string {
+	return fmt.Sprintf("helpi:session:%d", userID)
+}
+
+func (m *cachedManager) Lock(ctx context.Context, userID int64) (func(), error) {
+	l, ok := m.Manager.(Locker)
+	if !ok {
+		return func() {}, nil
+	}
+	return l.Lock(ctx, userID)
+}
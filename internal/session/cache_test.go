@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+type fakeCache struct {
+	values map[string][]byte
+	err    error
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{values: make(map[string][]byte)}
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if c.err != nil {
+		return nil, false, c.err
+	}
+	v, ok := c.values[key]
+	return v, ok, nil
+}
+
+func (c *fakeCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.values[key] = value
+	return nil
+}
+
+func (c *fakeCache) Delete(ctx context.Context, key string) error {
+	delete(c.values, key)
+	return c.err
+}
+
+func TestCachedManager_WritesThroughAndServesFromCache(t *testing.T) {
+	store, _ := NewManager(t.TempDir(), 2)
+	cache := newFakeCache()
+	mgr := NewCachedManager(store, cache, time.Minute, 2)
+
+	messages := []llm.Message{{Role: "user", Content: "1"}, {Role: "assistant", Content: "2"}, {Role: "user", Content: "3"}}
+	if err := mgr.Save(1, messages); err != nil {
+		t.Fatal(err)
+	}
+	if persisted, _ := store.Get(1); len(persisted) != 2 {
+		t.Errorf("expected save to reach the store, got %+v", persisted)
+	}
+
+	// Change the store behind the cache's back to prove reads are cached.
+	store.Save(1, nil)
+	got, err := mgr.Get(1)
+	if err != nil || len(got) != 2 || got[0].Content != "2" {
+		t.Errorf("expected truncated history from cache, got %+v, %v", got, err)
+	}
+}
+
+func TestCachedManager_InvalidatesOnThreadSwitch(t *testing.T) {
+	store, _ := NewManager(t.TempDir(), 10)
+	mgr := NewCachedManager(store, newFakeCache(), time.Minute, 10)
+
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "default"}})
+	if _, err := mgr.NewThread(1, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := mgr.Get(1); len(got) != 0 {
+		t.Errorf("expected new thread to be empty, got %+v", got)
+	}
+
+	mgr.PopLast(1)
+	if _, err := mgr.ResumeThread(1, defaultThreadID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := mgr.Get(1); len(got) != 1 {
+		t.Errorf("expected default thread history, got %+v", got)
+	}
+}
+
+func TestCachedManager_FallsBackWhenCacheFails(t *testing.T) {
+	store, _ := NewManager(t.TempDir(), 10)
+	cache := newFakeCache()
+	cache.err = errors.New("connection refused")
+	mgr := NewCachedManager(store, cache, time.Minute, 10)
+
+	if err := mgr.Save(1, []llm.Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("expected cache failure to be ignored, got %v", err)
+	}
+	if got, err := mgr.Get(1); err != nil || len(got) != 1 {
+		t.Errorf("expected history from store, got %+v, %v", got, err)
+	}
+}
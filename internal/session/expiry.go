@@ -0,0 +1,227 @@
+package session
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jrswab/helpi/internal/storage"
+)
+
+// Expirer is implemented by stores that can purge conversations that have
+// not been written since cutoff. ExpireIdle returns the user of every
+// expired conversation.
+type Expirer interface {
+	ExpireIdle(cutoff time.Time, archive bool) ([]int64, error)
+}
+
+var expiredSessions = expvar.NewInt("helpi_sessions_expired_total")
+
+type Janitor struct {
+	expirer Expirer
+	ttl     time.Duration
+	archive bool
+	now     func() time.Time
+}
+
+func NewJanitor(expirer Expirer, ttl time.Duration, archive bool) *Janitor {
+	return &Janitor{expirer: expirer, ttl: ttl, archive: archive, now: time.Now}
+}
+
+// Sweep purges sessions idle for longer than the TTL and returns how many
+// were removed.
+func (j *Janitor) Sweep() (int, error) {
+	users, err := j.expirer.ExpireIdle(j.now().Add(-j.ttl), j.archive)
+	expiredSessions.Add(int64(len(users)))
+	return len(users), err
+}
+
+// Run sweeps once immediately and then every interval until ctx is done.
+func (j *Janitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := j.Sweep()
+		if err != nil {
+			log.Printf("Session cleanup failed: %v", err)
+		}
+		if n > 0 {
+			log.Printf("Session cleanup: expired %d idle session(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *manager) ExpireIdle(cutoff time.Time, archive bool) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, err := os.ReadDir(m.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	archiveDir := filepath.Join(m.path, "archive")
+	if archive {
+		if err := os.MkdirAll(archiveDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create archive directory: %w", err)
+		}
+	}
+
+	var expired []int64
+	for _, entry := range entries {
+		if entry.IsDir() || !isSessionFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+
+		userID, err := m.expire(entry.Name(), archiveDir, archive)
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire session %s: %w", entry.Name(), err)
+		}
+		expired = append(expired, userID)
+	}
+	return expired, nil
+}
+
+// expire removes or archives the session file name and its backup while
+// holding the lock of its user, and returns the user.
+func (m *manager) expire(name, archiveDir string, archive bool) (int64, error) {
+	user, _, _ := strings.Cut(strings.TrimSuffix(name, ".json"), "_")
+	userID, err := strconv.ParseInt(user, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	unlock, err := m.lockUser(userID, true)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	path := filepath.Join(m.path, name)
+	if !archive {
+		return userID, removeFile(path)
+	}
+	if err := os.Rename(path, filepath.Join(archiveDir, name)); err != nil {
+		return userID, err
+	}
+	if err := os.Rename(path+backupSuffix, filepath.Join(archiveDir, name+backupSuffix)); err != nil && !os.IsNotExist(err) {
+		return userID, err
+	}
+	return userID, nil
+}
+
+// Evict removes a conversation file, or its backup, found by storage.Scan
+// while holding the lock of its user.
+func (m *manager) Evict(f storage.File) (bool, error) {
+	name := filepath.Base(f.Path)
+	if filepath.Clean(filepath.Dir(f.Path)) != filepath.Clean(m.path) || !isSessionFile(strings.TrimSuffix(name, backupSuffix)) {
+		return false, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	unlock, err := m.lockUser(f.UserID, true)
+	if err != nil {
+		return true, err
+	}
+	defer unlock()
+
+	if strings.HasSuffix(name, backupSuffix) {
+		err = os.Remove(f.Path)
+	} else {
+		err = removeFile(f.Path)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return true, fmt.Errorf("failed to evict session %s: %w", name, err)
+	}
+	return true, nil
+}
+
+func (m *cachedManager) Evict(f storage.File) (bool, error) {
+	e, ok := m.Manager.(storage.Evicter)
+	if !ok {
+		return false, nil
+	}
+	defer m.invalidate(f.UserID)
+	return e.Evict(f)
+}
+
+// isSessionFile matches <userID>.json and <userID>_<threadID>.json, leaving
+// thread indexes and per-user settings alone.
+func isSessionFile(name string) bool {
+	base, ok := strings.CutSuffix(name, ".json")
+	if !ok {
+		return false
+	}
+	user, thread, hasThread := strings.Cut(base, "_")
+	if _, err := strconv.ParseInt(user, 10, 64); err != nil {
+		return false
+	}
+	if hasThread {
+		if _, err := strconv.Atoi(thread); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *postgresManager) ExpireIdle(cutoff time.Time, archive bool) ([]int64, error) {
+	query := `DELETE FROM session_messages WHERE updated_at < $1 RETURNING user_id`
+	if archive {
+		query = `WITH expired AS (
+			DELETE FROM session_messages WHERE updated_at < $1 RETURNING user_id, thread_id, messages, updated_at
+		)
+		INSERT INTO session_archive (user_id, thread_id, messages, updated_at)
+		SELECT user_id, thread_id, messages, updated_at FROM expired
+		RETURNING user_id`
+	}
+
+	rows, err := m.db.QueryContext(context.Background(), query, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var expired []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return expired, fmt.Errorf("failed to expire sessions: %w", err)
+		}
+		expired = append(expired, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return expired, fmt.Errorf("failed to expire sessions: %w", err)
+	}
+	return expired, nil
+}
+
+// ExpireIdle expires sessions in the underlying store and drops them from
+// the cache, so an expired conversation is not served from it.
+func (m *cachedManager) ExpireIdle(cutoff time.Time, archive bool) ([]int64, error) {
+	e, ok := m.Manager.(Expirer)
+	if !ok {
+		return nil, nil
+	}
+	expired, err := e.ExpireIdle(cutoff, archive)
+	for _, userID := range expired {
+		m.invalidate(userID)
+	}
+	return expired, err
+}
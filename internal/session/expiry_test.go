@@ -0,0 +1,105 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/storage"
+)
+
+func TestExpireIdle_RemovesOnlyIdleSessions(t *testing.T) {
+	dir := t.TempDir()
+	mgr, _ := NewManager(dir, 10)
+	m := mgr.(*manager)
+
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "old"}})
+	mgr.Save(2, []llm.Message{{Role: "user", Content: "new"}})
+	mgr.SetProvider(1, "openai")
+	mgr.NewThread(1, "")
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "old thread"}})
+
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{"1.json", "1_2.json", "1_threads.json", "providers.json"} {
+		os.Chtimes(filepath.Join(dir, name), old, old)
+	}
+
+	users, err := m.ExpireIdle(time.Now().Add(-24*time.Hour), false)
+	if err != nil || len(users) != 2 || users[0] != 1 || users[1] != 1 {
+		t.Fatalf("ExpireIdle() = %v, %v; want both sessions of user 1", users, err)
+	}
+	for _, name := range []string{"2.json", "1_threads.json", "providers.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be kept: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "1.json")); !os.IsNotExist(err) {
+		t.Error("expected idle session to be deleted")
+	}
+}
+
+func TestExpireIdle_Archives(t *testing.T) {
+	dir := t.TempDir()
+	mgr, _ := NewManager(dir, 10)
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "old"}})
+
+	janitor := NewJanitor(mgr.(Expirer), time.Hour, true)
+	janitor.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	before := expiredSessions.Value()
+	if n, err := janitor.Sweep(); err != nil || n != 1 {
+		t.Fatalf("Sweep() = %d, %v; want 1", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "archive", "1.json")); err != nil {
+		t.Errorf("expected session to be archived: %v", err)
+	}
+	if got := expiredSessions.Value() - before; got != 1 {
+		t.Errorf("expected expired counter to increase by 1, got %d", got)
+	}
+}
+
+func TestExpireIdle_DropsCache(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewManager(dir, 10)
+	mgr := NewCachedManager(store, newFakeCache(), time.Minute, 10)
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "old"}})
+
+	users, err := mgr.(Expirer).ExpireIdle(time.Now().Add(time.Hour), false)
+	if err != nil || len(users) != 1 {
+		t.Fatalf("ExpireIdle() = %v, %v; want one session", users, err)
+	}
+	if got, _ := mgr.Get(1); len(got) != 0 {
+		t.Errorf("expected the expired session to be dropped from the cache, got %+v", got)
+	}
+}
+
+func TestEvict_RemovesSessionAndDropsCache(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewManager(dir, 10)
+	mgr := NewCachedManager(store, newFakeCache(), time.Minute, 10)
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "hi"}})
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "hi again"}})
+
+	evicter := mgr.(storage.Evicter)
+	for _, name := range []string{"1.json", "1.json.bak"} {
+		removed, err := evicter.Evict(storage.File{UserID: 1, Path: filepath.Join(dir, name)})
+		if err != nil || !removed {
+			t.Fatalf("Evict(%s) = %v, %v", name, removed, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "1.json")); !os.IsNotExist(err) {
+		t.Error("expected the session file to be removed")
+	}
+	if got, _ := mgr.Get(1); len(got) != 0 {
+		t.Errorf("expected the cached session to be dropped, got %+v", got)
+	}
+
+	if removed, _ := evicter.Evict(storage.File{UserID: 1, Path: filepath.Join(t.TempDir(), "1.json")}); removed {
+		t.Error("expected files outside the session directory to be left alone")
+	}
+	if removed, _ := evicter.Evict(storage.File{UserID: 1, Path: filepath.Join(dir, "1_threads.json")}); removed {
+		t.Error("expected thread indexes to be left alone")
+	}
+}
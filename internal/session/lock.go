@@ -0,0 +1,56 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The file backend guards each user's files, and each shared values file,
+// with an advisory lock on a .lock file next to them, so several bot
+// processes can share one session directory. m.mu still serializes access
+// within a process.
+
+// Locker is implemented by managers that can hold a session for a whole
+// read-modify-write cycle, such as reading the history, asking the model
+// and saving the answer, against other bot processes. Lock blocks until
+// the session is free or ctx is done.
+type Locker interface {
+	Lock(ctx context.Context, userID int64) (unlock func(), err error)
+}
+
+// Lock holds the session of userID on a lock file of its own, so the
+// writes done while it is held can still take lockUser.
+func (m *manager) Lock(ctx context.Context, userID int64) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return lockPath(filepath.Join(m.path, fmt.Sprintf("%d.session.lock", userID)), true)
+}
+
+// lockUser locks the sessions and thread index of userID until the returned
+// function is called.
+func (m *manager) lockUser(userID int64, exclusive bool) (func(), error) {
+	return lockPath(filepath.Join(m.path, fmt.Sprintf("%d.lock", userID)), exclusive)
+}
+
+// lockValues locks the values file name, such as prompts or stats.
+func (m *manager) lockValues(name string, exclusive bool) (func(), error) {
+	return lockPath(filepath.Join(m.path, name+".lock"), exclusive)
+}
+
+func lockPath(path string, exclusive bool) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", filepath.Base(path), err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
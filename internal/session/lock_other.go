@@ -0,0 +1,16 @@
+//go:build !unix
+
+package session
+
+import "os"
+
+// Advisory locks are only taken on Unix. Elsewhere the file backend is safe
+// for a single process only.
+
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestManager_ConcurrentManagersShareDirectory(t *testing.T) {
+	dir := t.TempDir()
+	a, _ := NewManager(dir, 1000)
+	b, _ := NewManager(dir, 1000)
+
+	var wg sync.WaitGroup
+	for _, m := range []Manager{a, b} {
+		wg.Add(1)
+		go func(m Manager) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if err := m.SetPrompt(int64(i), "prompt"); err != nil {
+					t.Errorf("SetPrompt() returned error: %v", err)
+				}
+			}
+		}(m)
+	}
+	wg.Wait()
+
+	values, err := a.(ValueStore).Values("prompts")
+	if err != nil {
+		t.Fatalf("Values() returned error: %v", err)
+	}
+	if len(values) != 50 {
+		t.Errorf("expected 50 prompts from two managers, got %d", len(values))
+	}
+}
+
+func TestLockPath_ExcludesOtherProcesses(t *testing.T) {
+	if _, err := exec.LookPath("flock"); err != nil {
+		t.Skip("flock(1) is not available")
+	}
+	dir := t.TempDir()
+	m := &manager{path: dir}
+
+	unlock, err := m.lockUser(1, true)
+	if err != nil {
+		t.Fatalf("lockUser() returned error: %v", err)
+	}
+	path := filepath.Join(dir, "1.lock")
+	if err := exec.Command("flock", "--nonblock", path, "true").Run(); err == nil {
+		t.Error("expected another process to be unable to take the lock")
+	}
+
+	unlock()
+	if err := exec.Command("flock", "--nonblock", path, "true").Run(); err != nil {
+		t.Errorf("expected the lock to be free after unlock: %v", err)
+	}
+
+}
+
+func TestManager_LockLeavesWritesPossible(t *testing.T) {
+	if _, err := exec.LookPath("flock"); err != nil {
+		t.Skip("flock(1) is not available")
+	}
+	dir := t.TempDir()
+	m, _ := NewManager(dir, 50)
+
+	unlock, err := m.(Locker).Lock(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Lock() returned error: %v", err)
+	}
+	if err := m.Save(1, []llm.Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("expected Save() to work while the session is held, got %v", err)
+	}
+	path := filepath.Join(dir, "1.session.lock")
+	if err := exec.Command("flock", "--nonblock", path, "true").Run(); err == nil {
+		t.Error("expected another process to be unable to take the session")
+	}
+
+	unlock()
+	if err := exec.Command("flock", "--nonblock", path, "true").Run(); err != nil {
+		t.Errorf("expected the session to be free after unlock: %v", err)
+	}
+}
@@ -2,9 +2,11 @@ package session
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 
 	"github.com/jrswab/helpi/internal/llm"
@@ -14,8 +16,26 @@ type Manager interface {
 	Get(userID int64) ([]llm.Message, error)
 	Save(userID int64, messages []llm.Message) error
 	Delete(userID int64) error
+	PopLast(userID int64) (llm.Message, error)
+	ReplaceLast(userID int64, msg llm.Message) error
+	SetRating(userID int64, messageID string, score int) error
+	GetProvider(userID int64) (string, error)
+	SetProvider(userID int64, name string) error
+	Providers() (map[int64]string, error)
+	GetPrompt(userID int64) (string, error)
+	SetPrompt(userID int64, prompt string) error
+	GetLanguage(userID int64) (string, error)
+	SetLanguage(userID int64, lang string) error
+	NewThread(userID int64, title string) (Thread, error)
+	Threads(userID int64) ([]Thread, int, error)
+	ResumeThread(userID int64, id int) (Thread, error)
 }
 
+var (
+	ErrEmptySession    = errors.New("session is empty")
+	ErrMessageNotFound = errors.New("message not found")
+)
+
 type manager struct {
 	path        string
 	maxMessages int
@@ -44,38 +64,139 @@ func (m *manager) Get(userID int64) ([]llm.Message, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	path := m.sessionPath(userID)
-	data, err := os.ReadFile(path)
-	if os.IsNotExist(err) {
-		return []llm.Message{}, nil
+	unlock, err := m.lockUser(userID, false)
+	if err != nil {
+		return nil, err
 	}
+	defer unlock()
+
+	return m.read(userID)
+}
+
+func (m *manager) Save(userID int64, messages []llm.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockUser(userID, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read session: %w", err)
+		return err
 	}
+	defer unlock()
 
-	var messages []llm.Message
-	if err := json.Unmarshal(data, &messages); err != nil {
-		return nil, fmt.Errorf("failed to parse session: %w", err)
+	return m.write(userID, messages)
+}
+
+// PopLast removes and returns the newest message in the active session.
+func (m *manager) PopLast(userID int64) (llm.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockUser(userID, true)
+	if err != nil {
+		return llm.Message{}, err
 	}
+	defer unlock()
 
-	return messages, nil
+	messages, err := m.read(userID)
+	if err != nil {
+		return llm.Message{}, err
+	}
+	if len(messages) == 0 {
+		return llm.Message{}, ErrEmptySession
+	}
+
+	last := messages[len(messages)-1]
+	if err := m.write(userID, messages[:len(messages)-1]); err != nil {
+		return llm.Message{}, err
+	}
+	return last, nil
 }
 
-func (m *manager) Save(userID int64, messages []llm.Message) error {
+// ReplaceLast overwrites the newest message in the active session.
+func (m *manager) ReplaceLast(userID int64, msg llm.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockUser(userID, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	messages, err := m.read(userID)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return ErrEmptySession
+	}
+
+	messages[len(messages)-1] = msg
+	return m.write(userID, messages)
+}
+
+// SetRating rates the message with messageID in the active session.
+func (m *manager) SetRating(userID int64, messageID string, score int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.maxMessages > 0 && len(messages) > m.maxMessages {
-		messages = messages[len(messages)-m.maxMessages:]
+	unlock, err := m.lockUser(userID, true)
+	if err != nil {
+		return err
 	}
+	defer unlock()
+
+	messages, err := m.read(userID)
+	if err != nil {
+		return err
+	}
+	if !rate(messages, messageID, score) {
+		return ErrMessageNotFound
+	}
+	return m.write(userID, messages)
+}
+
+// rate sets the rating of the message with messageID and reports whether
+// messages has one.
+func rate(messages []llm.Message, messageID string, score int) bool {
+	i := slices.IndexFunc(messages, func(m llm.Message) bool { return m.ID == messageID })
+	if i < 0 {
+		return false
+	}
+	messages[i].Rating = score
+	return true
+}
+
+func (m *manager) read(userID int64) ([]llm.Message, error) {
+	path, err := m.sessionPath(userID)
+	if err != nil {
+		return nil, err
+	}
+	var messages []llm.Message
+	err = readFile(path, &messages)
+	if os.IsNotExist(err) {
+		return []llm.Message{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (m *manager) write(userID int64, messages []llm.Message) error {
+	messages = llm.TrimHistory(messages, m.maxMessages)
 
 	data, err := json.Marshal(messages)
 	if err != nil {
 		return fmt.Errorf("failed to marshal session: %w", err)
 	}
 
-	path := m.sessionPath(userID)
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	path, err := m.sessionPath(userID)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(path, data); err != nil {
 		return fmt.Errorf("failed to write session: %w", err)
 	}
 
@@ -86,8 +207,17 @@ func (m *manager) Delete(userID int64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	path := m.sessionPath(userID)
-	if err := os.Remove(path); os.IsNotExist(err) {
+	unlock, err := m.lockUser(userID, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path, err := m.sessionPath(userID)
+	if err != nil {
+		return err
+	}
+	if err := removeFile(path); os.IsNotExist(err) {
 		return nil
 	} else if err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
@@ -96,6 +226,125 @@ func (m *manager) Delete(userID int64) error {
 	return nil
 }
 
-func (m *manager) sessionPath(userID int64) string {
-	return filepath.Join(m.path, fmt.Sprintf("%d.json", userID))
+func (m *manager) GetProvider(userID int64) (string, error) {
+	providers, err := m.Providers()
+	if err != nil {
+		return "", err
+	}
+	return providers[userID], nil
+}
+
+func (m *manager) SetProvider(userID int64, name string) error {
+	return m.setValue("providers", userID, name)
+}
+
+func (m *manager) Providers() (map[int64]string, error) {
+	return m.Values("providers")
+}
+
+func (m *manager) GetPrompt(userID int64) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	unlock, err := m.lockValues("prompts", false)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	prompts, err := m.readValues("prompts")
+	if err != nil {
+		return "", err
+	}
+	return prompts[userID], nil
+}
+
+func (m *manager) SetPrompt(userID int64, prompt string) error {
+	return m.setValue("prompts", userID, prompt)
+}
+
+func (m *manager) GetLanguage(userID int64) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	unlock, err := m.lockValues("languages", false)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	languages, err := m.readValues("languages")
+	if err != nil {
+		return "", err
+	}
+	return languages[userID], nil
+}
+
+func (m *manager) SetLanguage(userID int64, lang string) error {
+	return m.setValue("languages", userID, lang)
+}
+
+func (m *manager) setValue(name string, userID int64, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockValues(name, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	values, err := m.readValues(name)
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		delete(values, userID)
+	} else {
+		values[userID] = value
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	if err := writeFile(m.valuesPath(name), data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (m *manager) readValues(name string) (map[int64]string, error) {
+	values := make(map[int64]string)
+
+	err := readFile(m.valuesPath(name), &values)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", name, err)
+	}
+
+	return values, nil
+}
+
+func (m *manager) valuesPath(name string) string {
+	return filepath.Join(m.path, name+".json")
+}
+
+func (m *manager) sessionPath(userID int64) (string, error) {
+	idx, err := m.readIndex(userID)
+	if err != nil {
+		return "", err
+	}
+	return m.threadPath(userID, idx.Active), nil
+}
+
+func (m *manager) threadPath(userID int64, thread int) string {
+	if thread == defaultThreadID {
+		return filepath.Join(m.path, fmt.Sprintf("%d.json", userID))
+	}
+	return filepath.Join(m.path, fmt.Sprintf("%d_%d.json", userID, thread))
 }
@@ -2,9 +2,11 @@ package session
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/jrswab/helpi/internal/llm"
 )
@@ -88,6 +90,64 @@ func TestGet_ValidJSON(t *testing.T) {
 	}
 }
 
+func TestGet_LegacyMessagesHaveNoMetadata(t *testing.T) {
+	dir := t.TempDir()
+	mgr, err := NewManager(dir, 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	legacy := `[{"Role":"user","Content":"Hello"},{"Role":"assistant","Content":"Hi there"}]`
+	if err := os.WriteFile(filepath.Join(dir, "12345.json"), []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write session file: %v", err)
+	}
+
+	msgs, err := mgr.Get(12345)
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	if len(msgs) != 2 || msgs[1].Content != "Hi there" {
+		t.Fatalf("unexpected messages: %+v", msgs)
+	}
+	if msgs[0].ID != "" || !msgs[0].Timestamp.IsZero() || msgs[1].Provider != "" {
+		t.Errorf("expected legacy messages without metadata, got %+v", msgs)
+	}
+}
+
+func TestSave_RoundTripsMetadata(t *testing.T) {
+	dir := t.TempDir()
+	mgr, err := NewManager(dir, 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	answer := llm.Stamped(llm.Message{
+		Role:     "assistant",
+		Content:  "Hi there",
+		Provider: "openai",
+		Model:    "gpt-4o",
+		Tokens:   llm.TokenCounts{Prompt: 12, Completion: 3},
+	})
+	if err := mgr.Save(12345, []llm.Message{answer}); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	msgs, err := mgr.Get(12345)
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	got := msgs[0]
+	if got.ID != answer.ID || !got.Timestamp.Equal(answer.Timestamp) {
+		t.Errorf("expected ID %q at %v, got %q at %v", answer.ID, answer.Timestamp, got.ID, got.Timestamp)
+	}
+	if got.Provider != "openai" || got.Model != "gpt-4o" || got.Tokens != answer.Tokens {
+		t.Errorf("metadata mismatch: %+v", got)
+	}
+}
+
 func TestGet_CorruptedJSON(t *testing.T) {
 	dir := t.TempDir()
 	mgr, err := NewManager(dir, 10)
@@ -159,6 +219,32 @@ func TestSave_ExceedsMaxMessagesTruncates(t *testing.T) {
 	}
 }
 
+func TestSave_TruncationKeepsPinned(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 3)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	messages := []llm.Message{
+		{Role: "user", Content: "msg1", Pinned: true},
+		{Role: "assistant", Content: "msg2"},
+		{Role: "user", Content: "msg3"},
+		{Role: "assistant", Content: "msg4"},
+		{Role: "user", Content: "msg5"},
+	}
+	if err := mgr.Save(12345, messages); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	msgs, err := mgr.Get(12345)
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+	if len(msgs) != 3 || !msgs[0].Pinned || msgs[1].Content != "msg4" {
+		t.Errorf("expected the pinned message and the 2 newest, got %+v", msgs)
+	}
+}
+
 func TestDelete_ExistingFileRemovesIt(t *testing.T) {
 	dir := t.TempDir()
 	mgr, err := NewManager(dir, 10)
@@ -192,3 +278,179 @@ func TestDelete_NonExistentFileReturnsNil(t *testing.T) {
 		t.Errorf("Delete() returned error for non-existent file: %v", err)
 	}
 }
+
+func TestSetProvider_PersistsAndClears(t *testing.T) {
+	dir := t.TempDir()
+	mgr, err := NewManager(dir, 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	if err := mgr.SetProvider(1, "anthropic"); err != nil {
+		t.Fatalf("SetProvider() returned error: %v", err)
+	}
+	if err := mgr.SetProvider(2, "openai"); err != nil {
+		t.Fatalf("SetProvider() returned error: %v", err)
+	}
+
+	reopened, _ := NewManager(dir, 10)
+	name, err := reopened.GetProvider(1)
+	if err != nil {
+		t.Fatalf("GetProvider() returned error: %v", err)
+	}
+	if name != "anthropic" {
+		t.Errorf("expected anthropic, got %q", name)
+	}
+
+	if err := reopened.Delete(1); err != nil {
+		t.Fatalf("Delete() returned error: %v", err)
+	}
+	if name, _ := reopened.GetProvider(1); name != "anthropic" {
+		t.Errorf("expected clearing history to keep provider, got %q", name)
+	}
+
+	if err := reopened.SetProvider(1, ""); err != nil {
+		t.Fatalf("SetProvider() returned error: %v", err)
+	}
+	providers, err := reopened.Providers()
+	if err != nil {
+		t.Fatalf("Providers() returned error: %v", err)
+	}
+	if len(providers) != 1 || providers[2] != "openai" {
+		t.Errorf("expected only user 2 to remain, got %v", providers)
+	}
+}
+
+func TestSetPrompt_PersistsAndClears(t *testing.T) {
+	dir := t.TempDir()
+	mgr, err := NewManager(dir, 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	if err := mgr.SetPrompt(1, "Answer like a pirate."); err != nil {
+		t.Fatalf("SetPrompt() returned error: %v", err)
+	}
+
+	reopened, _ := NewManager(dir, 10)
+	prompt, err := reopened.GetPrompt(1)
+	if err != nil {
+		t.Fatalf("GetPrompt() returned error: %v", err)
+	}
+	if prompt != "Answer like a pirate." {
+		t.Errorf("expected saved prompt, got %q", prompt)
+	}
+	if name, _ := reopened.GetProvider(1); name != "" {
+		t.Errorf("expected prompts not to leak into providers, got %q", name)
+	}
+
+	if err := reopened.SetPrompt(1, ""); err != nil {
+		t.Fatalf("SetPrompt() returned error: %v", err)
+	}
+	if prompt, _ := reopened.GetPrompt(1); prompt != "" {
+		t.Errorf("expected prompt to be cleared, got %q", prompt)
+	}
+}
+
+func TestPopAndReplaceLast(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	if err := mgr.ReplaceLast(1, llm.Message{Role: "assistant", Content: "x"}); !errors.Is(err, ErrEmptySession) {
+		t.Errorf("expected ErrEmptySession, got %v", err)
+	}
+
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}})
+	if err := mgr.ReplaceLast(1, llm.Message{Role: "assistant", Content: "hey there"}); err != nil {
+		t.Fatalf("ReplaceLast() returned error: %v", err)
+	}
+
+	last, err := mgr.PopLast(1)
+	if err != nil {
+		t.Fatalf("PopLast() returned error: %v", err)
+	}
+	if last.Content != "hey there" {
+		t.Errorf("expected replaced message, got %q", last.Content)
+	}
+	if messages, _ := mgr.Get(1); len(messages) != 1 || messages[0].Content != "hi" {
+		t.Errorf("expected only the user message to remain, got %+v", messages)
+	}
+}
+
+func TestSetRating(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	mgr.Save(1, []llm.Message{{ID: "q", Role: "user", Content: "hi"}, {ID: "a", Role: "assistant", Content: "hello"}})
+	if err := mgr.SetRating(1, "a", 1); err != nil {
+		t.Fatalf("SetRating() returned error: %v", err)
+	}
+	if err := mgr.SetRating(1, "gone", 1); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("expected ErrMessageNotFound, got %v", err)
+	}
+
+	if messages, _ := mgr.Get(1); len(messages) != 2 || messages[1].Rating != 1 || messages[0].Rating != 0 {
+		t.Errorf("expected only the answer to be rated, got %+v", messages)
+	}
+}
+
+func TestManager_Stats(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := mgr.(StatsRecorder)
+
+	if s, err := recorder.Stats(1); err != nil || s.Messages != 0 {
+		t.Fatalf("expected empty stats, got %+v, %v", s, err)
+	}
+
+	for _, e := range []Exchange{
+		{Provider: "openai", Tokens: 100, Latency: time.Second},
+		{Provider: "ollama", Tokens: 50, Latency: 3 * time.Second},
+		{Provider: "ollama", Tokens: 25, Latency: 2 * time.Second},
+	} {
+		if err := recorder.RecordExchange(1, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mgr.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := recorder.Stats(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Messages != 3 || s.Tokens != 175 || s.FavoriteProvider() != "ollama" || s.AverageLatency() != 2*time.Second {
+		t.Errorf("unexpected stats %+v", s)
+	}
+	if other, _ := recorder.Stats(2); other.Messages != 0 {
+		t.Errorf("expected stats to be per user, got %+v", other)
+	}
+}
+
+func TestManager_ValuesShareSettingsFiles(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.SetLanguage(1, "de"); err != nil {
+		t.Fatal(err)
+	}
+
+	values := mgr.(ValueStore)
+	if v, err := values.Value(1, "languages"); err != nil || v != "de" {
+		t.Errorf("Value() = %q, %v, want de", v, err)
+	}
+	if err := values.SetValue(1, "languages", "pt"); err != nil {
+		t.Fatal(err)
+	}
+	if lang, _ := mgr.GetLanguage(1); lang != "pt" {
+		t.Errorf("GetLanguage() = %q, want pt", lang)
+	}
+}
@@ -0,0 +1,473 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+var ErrVerify = errors.New("migrated data does not match the source")
+
+// Snapshot is everything a session store keeps: conversations with their
+// threads, named values such as providers and settings, and usage stats.
+type Snapshot struct {
+	Users  []UserSnapshot
+	Values map[string]map[int64]string
+	Stats  map[int64]Stats
+}
+
+type UserSnapshot struct {
+	UserID   int64
+	Active   int
+	Threads  []Thread
+	Messages map[int][]llm.Message
+}
+
+// Exporter is implemented by stores whose data can be migrated elsewhere.
+type Exporter interface {
+	Export(ctx context.Context) (*Snapshot, error)
+}
+
+// Importer is implemented by stores that can take in a Snapshot. Users,
+// threads, values and stats in the snapshot replace those already stored;
+// everything else is left alone.
+type Importer interface {
+	Import(ctx context.Context, s *Snapshot) error
+}
+
+// MigrationReport counts what a migration copied.
+type MigrationReport struct {
+	Users    int
+	Threads  int
+	Messages int
+	Values   int
+	Stats    int
+}
+
+func (s *Snapshot) report() MigrationReport {
+	r := MigrationReport{Users: len(s.Users), Stats: len(s.Stats)}
+	for _, u := range s.Users {
+		r.Threads += len(u.Threads)
+		for _, messages := range u.Messages {
+			r.Messages += len(messages)
+		}
+	}
+	for _, values := range s.Values {
+		r.Values += len(values)
+	}
+	return r
+}
+
+// Migrate copies everything in src to dst. With dryRun it only reads src
+// and reports what would be copied. When dst can be exported too, the
+// copy is read back and compared with the source.
+func Migrate(ctx context.Context, src Exporter, dst Importer, dryRun bool) (MigrationReport, error) {
+	snapshot, err := src.Export(ctx)
+	if err != nil {
+		return MigrationReport{}, err
+	}
+	report := snapshot.report()
+	if dryRun {
+		return report, nil
+	}
+	if err := dst.Import(ctx, snapshot); err != nil {
+		return report, err
+	}
+
+	exporter, ok := dst.(Exporter)
+	if !ok {
+		return report, nil
+	}
+	copied, err := exporter.Export(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to verify migration: %w", err)
+	}
+	return report, verify(snapshot, copied)
+}
+
+func verify(src, dst *Snapshot) error {
+	users := make(map[int64]UserSnapshot, len(dst.Users))
+	for _, u := range dst.Users {
+		users[u.UserID] = u
+	}
+	for _, u := range src.Users {
+		copied, ok := users[u.UserID]
+		if !ok {
+			return fmt.Errorf("%w: user %d is missing", ErrVerify, u.UserID)
+		}
+		if copied.Active != u.Active || len(copied.Threads) < len(u.Threads) {
+			return fmt.Errorf("%w: threads of user %d differ", ErrVerify, u.UserID)
+		}
+		for thread, messages := range u.Messages {
+			if !sameMessages(messages, copied.Messages[thread]) {
+				return fmt.Errorf("%w: thread %d of user %d differs", ErrVerify, thread, u.UserID)
+			}
+		}
+	}
+	for name, values := range src.Values {
+		for userID, value := range values {
+			if dst.Values[name][userID] != value {
+				return fmt.Errorf("%w: %s of user %d differs", ErrVerify, name, userID)
+			}
+		}
+	}
+	for userID, stats := range src.Stats {
+		if !reflect.DeepEqual(normalizeStats(stats), normalizeStats(dst.Stats[userID])) {
+			return fmt.Errorf("%w: stats of user %d differ", ErrVerify, userID)
+		}
+	}
+	return nil
+}
+
+// sameMessages compares conversations by their stored form, so a nil and
+// an empty image list count as equal.
+func sameMessages(a, b []llm.Message) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
+
+func normalizeStats(s Stats) Stats {
+	if len(s.Providers) == 0 {
+		s.Providers = nil
+	}
+	return s
+}
+
+func (m *manager) Export(ctx context.Context) (*Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries, err := os.ReadDir(m.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	snapshot := &Snapshot{Values: make(map[string]map[int64]string)}
+	seen := make(map[int64]bool)
+	var users []int64
+	for _, entry := range entries {
+		base, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		user, _, _ := strings.Cut(base, "_")
+		if userID, err := strconv.ParseInt(user, 10, 64); err == nil {
+			if !seen[userID] {
+				seen[userID] = true
+				users = append(users, userID)
+			}
+			continue
+		}
+		if base == "stats" {
+			if snapshot.Stats, err = m.readStats(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if snapshot.Values[base], err = m.readValues(base); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i] < users[j] })
+
+	for _, userID := range users {
+		idx, err := m.readIndex(userID)
+		if err != nil {
+			return nil, err
+		}
+		u := UserSnapshot{UserID: userID, Active: idx.Active, Threads: idx.Threads, Messages: make(map[int][]llm.Message)}
+		for _, t := range idx.Threads {
+			var messages []llm.Message
+			err := readFile(m.threadPath(userID, t.ID), &messages)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to load session of user %d: %w", userID, err)
+			}
+			u.Messages[t.ID] = messages
+		}
+		snapshot.Users = append(snapshot.Users, u)
+	}
+	return snapshot, nil
+}
+
+func (m *manager) Import(ctx context.Context, s *Snapshot) error {
+	for _, u := range s.Users {
+		if err := m.importUser(u); err != nil {
+			return err
+		}
+	}
+	for name, values := range s.Values {
+		for userID, value := range values {
+			if err := m.setValue(name, userID, value); err != nil {
+				return err
+			}
+		}
+	}
+	if len(s.Stats) == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	unlock, err := m.lockValues("stats", true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	all, err := m.readStats()
+	if err != nil {
+		return err
+	}
+	for userID, stats := range s.Stats {
+		all[userID] = stats
+	}
+	data, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+	if err := writeFile(m.valuesPath("stats"), data); err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+	return nil
+}
+
+// importUser writes a user's conversations as they are, without trimming
+// them to maxMessages.
+func (m *manager) importUser(u UserSnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockUser(u.UserID, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.writeIndex(u.UserID, threadIndex{Active: u.Active, Threads: u.Threads}); err != nil {
+		return err
+	}
+	for thread, messages := range u.Messages {
+		data, err := json.Marshal(messages)
+		if err != nil {
+			return fmt.Errorf("failed to marshal session: %w", err)
+		}
+		if err := writeFile(m.threadPath(u.UserID, thread), data); err != nil {
+			return fmt.Errorf("failed to write session: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *postgresManager) Export(ctx context.Context) (*Snapshot, error) {
+	snapshot := &Snapshot{Values: make(map[string]map[int64]string), Stats: make(map[int64]Stats)}
+	users := make(map[int64]*UserSnapshot)
+	var order []int64
+
+	rows, err := m.db.QueryContext(ctx, `SELECT user_id, active_thread FROM session_users ORDER BY user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session users: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		u := &UserSnapshot{Messages: make(map[int][]llm.Message)}
+		if err := rows.Scan(&u.UserID, &u.Active); err != nil {
+			return err
+		}
+		users[u.UserID] = u
+		order = append(order, u.UserID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session users: %w", err)
+	}
+
+	rows, err = m.db.QueryContext(ctx, `SELECT user_id, id, title, created_at FROM session_threads ORDER BY user_id, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read threads: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var userID int64
+		var t Thread
+		if err := rows.Scan(&userID, &t.ID, &t.Title, &t.CreatedAt); err != nil {
+			return err
+		}
+		if u, ok := users[userID]; ok {
+			u.Threads = append(u.Threads, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read threads: %w", err)
+	}
+
+	rows, err = m.db.QueryContext(ctx, `SELECT user_id, thread_id, messages FROM session_messages`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var userID int64
+		var thread int
+		var data []byte
+		if err := rows.Scan(&userID, &thread, &data); err != nil {
+			return err
+		}
+		messages, err := decodeMessages(data)
+		if err != nil {
+			return err
+		}
+		if u, ok := users[userID]; ok {
+			u.Messages[thread] = messages
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+
+	rows, err = m.db.QueryContext(ctx, `SELECT name, user_id, value FROM session_values`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var name, value string
+		var userID int64
+		if err := rows.Scan(&name, &userID, &value); err != nil {
+			return err
+		}
+		if snapshot.Values[name] == nil {
+			snapshot.Values[name] = make(map[int64]string)
+		}
+		snapshot.Values[name][userID] = value
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values: %w", err)
+	}
+
+	rows, err = m.db.QueryContext(ctx, `SELECT user_id, messages, tokens, latency_ms, providers FROM session_stats`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var userID int64
+		var s Stats
+		var providers []byte
+		if err := rows.Scan(&userID, &s.Messages, &s.Tokens, &s.LatencyMS, &providers); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(providers, &s.Providers); err != nil {
+			return err
+		}
+		snapshot.Stats[userID] = s
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats: %w", err)
+	}
+
+	for _, userID := range order {
+		u := users[userID]
+		if len(u.Threads) == 0 {
+			u.Threads = []Thread{{ID: defaultThreadID, Title: "Default"}}
+		}
+		snapshot.Users = append(snapshot.Users, *u)
+	}
+	return snapshot, nil
+}
+
+func scanRows(rows *sql.Rows, scan func() error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Import writes the whole snapshot in one transaction, so a failed
+// migration leaves the database as it was. Conversations are not trimmed
+// to maxMessages.
+func (m *postgresManager) Import(ctx context.Context, s *Snapshot) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, u := range s.Users {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO session_users (user_id, active_thread) VALUES ($1, $2)
+			ON CONFLICT (user_id) DO UPDATE SET active_thread = EXCLUDED.active_thread`, u.UserID, u.Active); err != nil {
+			return fmt.Errorf("failed to import user %d: %w", u.UserID, err)
+		}
+		for _, t := range u.Threads {
+			createdAt := t.CreatedAt
+			if createdAt.IsZero() {
+				createdAt = time.Now()
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO session_threads (user_id, id, title, created_at) VALUES ($1, $2, $3, $4)
+				ON CONFLICT (user_id, id) DO UPDATE SET title = EXCLUDED.title, created_at = EXCLUDED.created_at`,
+				u.UserID, t.ID, t.Title, createdAt); err != nil {
+				return fmt.Errorf("failed to import threads of user %d: %w", u.UserID, err)
+			}
+		}
+		for thread, messages := range u.Messages {
+			data, err := json.Marshal(messages)
+			if err != nil {
+				return fmt.Errorf("failed to marshal session: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO session_messages (user_id, thread_id, messages, updated_at)
+				VALUES ($1, $2, $3, now())
+				ON CONFLICT (user_id, thread_id) DO UPDATE SET messages = EXCLUDED.messages, updated_at = now()`,
+				u.UserID, thread, string(data)); err != nil {
+				return fmt.Errorf("failed to import session of user %d: %w", u.UserID, err)
+			}
+		}
+	}
+
+	for name, values := range s.Values {
+		for userID, value := range values {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO session_values (name, user_id, value) VALUES ($1, $2, $3)
+				ON CONFLICT (name, user_id) DO UPDATE SET value = EXCLUDED.value`, name, userID, value); err != nil {
+				return fmt.Errorf("failed to import %s: %w", name, err)
+			}
+		}
+	}
+
+	for userID, stats := range s.Stats {
+		providers, err := json.Marshal(stats.Providers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal stats: %w", err)
+		}
+		if stats.Providers == nil {
+			providers = []byte("{}")
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO session_stats (user_id, messages, tokens, latency_ms, providers) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id) DO UPDATE SET messages = EXCLUDED.messages, tokens = EXCLUDED.tokens,
+				latency_ms = EXCLUDED.latency_ms, providers = EXCLUDED.providers`,
+			userID, stats.Messages, stats.Tokens, stats.LatencyMS, string(providers)); err != nil {
+			return fmt.Errorf("failed to import stats: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
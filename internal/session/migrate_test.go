@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// seedStore fills a file store with two users, one of them with a second
+// thread, plus values and stats.
+func seedStore(t *testing.T, mgr Manager) {
+	t.Helper()
+	if err := mgr.Save(1, []llm.Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.NewThread(1, "trip"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Save(1, []llm.Message{{Role: "user", Content: "plan a trip", Pinned: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.Save(-100, []llm.Message{{Role: "user", Content: "group chat"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.SetProvider(1, "ollama"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.(ValueStore).SetValue(-100, "settings", `{"verbosity":"short"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.(StatsRecorder).RecordExchange(1, Exchange{Provider: "ollama", Tokens: 12, Latency: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkMigrated(t *testing.T, dst Manager) {
+	t.Helper()
+	threads, active, err := dst.Threads(1)
+	if err != nil || len(threads) != 2 || active != 2 || threads[1].Title != "trip" {
+		t.Errorf("expected both threads with the second active, got %+v, %d, %v", threads, active, err)
+	}
+	if got, _ := dst.Get(1); len(got) != 1 || got[0].Content != "plan a trip" || !got[0].Pinned {
+		t.Errorf("unexpected active thread %+v", got)
+	}
+	if _, err := dst.ResumeThread(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := dst.Get(1); len(got) != 2 || got[1].Content != "hello" {
+		t.Errorf("unexpected default thread %+v", got)
+	}
+	if got, _ := dst.Get(-100); len(got) != 1 {
+		t.Errorf("expected the group session, got %+v", got)
+	}
+	if p, _ := dst.GetProvider(1); p != "ollama" {
+		t.Errorf("expected provider ollama, got %q", p)
+	}
+	if v, _ := dst.(ValueStore).Value(-100, "settings"); v != `{"verbosity":"short"}` {
+		t.Errorf("unexpected settings %q", v)
+	}
+	if s, _ := dst.(StatsRecorder).Stats(1); s.Messages != 1 || s.Tokens != 12 || s.Providers["ollama"] != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestMigrate_FileToFile(t *testing.T) {
+	src, err := NewManager(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedStore(t, src)
+	dir := t.TempDir()
+	dst, err := NewManager(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Migrate(context.Background(), src.(Exporter), dst.(Importer), true)
+	if err != nil {
+		t.Fatalf("dry run returned error: %v", err)
+	}
+	want := MigrationReport{Users: 2, Threads: 3, Messages: 4, Values: 2, Stats: 1}
+	if report != want {
+		t.Errorf("expected report %+v, got %+v", want, report)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("dry run wrote %d files", len(entries))
+	}
+
+	// Conversations longer than the destination's max_messages are copied
+	// in full.
+	if _, err := Migrate(context.Background(), src.(Exporter), dst.(Importer), false); err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+	checkMigrated(t, dst)
+	if _, err := os.Stat(filepath.Join(dir, "1_2.json")); err != nil {
+		t.Errorf("expected the second thread in its own file: %v", err)
+	}
+}
+
+func TestMigrate_FileToPostgres(t *testing.T) {
+	dst := newTestPostgres(t, 50)
+	src, err := NewManager(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedStore(t, src)
+
+	if _, err := Migrate(context.Background(), src.(Exporter), dst.(Importer), false); err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+	checkMigrated(t, dst)
+}
@@ -0,0 +1,81 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// ownersValue names the values that map keys derived with UserKey to
+// their user.
+const ownersValue = "owners"
+
+// owners maps the keys derived with UserKey back to their user, so storage
+// quotas count a user's per-bot and group conversations as theirs.
+var owners = &ownerIndex{keys: make(map[int64]int64)}
+
+type ownerIndex struct {
+	mu    sync.Mutex
+	store ValueStore
+	keys  map[int64]int64
+}
+
+// UserKey derives the key of userID's conversation within scope, such as a
+// bot or a group chat, like ScopedKey(scope..., userID), and remembers that
+// it belongs to userID.
+func UserKey(userID int64, scope ...int64) int64 {
+	key := ScopedKey(append(slices.Clone(scope), userID)...)
+	owners.add(key, Owner(userID))
+	return key
+}
+
+// Owner returns the user whose conversation key is, or key itself when it
+// was not derived with UserKey.
+func Owner(key int64) int64 {
+	owners.mu.Lock()
+	defer owners.mu.Unlock()
+	if user, ok := owners.keys[key]; ok {
+		return user
+	}
+	return key
+}
+
+// TrackOwners keeps the keys derived with UserKey in store, so they are
+// known after a restart before their users write again.
+func TrackOwners(store Manager) error {
+	vs, ok := store.(ValueStore)
+	if !ok {
+		return nil
+	}
+	values, err := vs.Values(ownersValue)
+	if err != nil {
+		return fmt.Errorf("failed to load session owners: %w", err)
+	}
+
+	owners.mu.Lock()
+	defer owners.mu.Unlock()
+	owners.store = vs
+	for key, value := range values {
+		if user, err := strconv.ParseInt(value, 10, 64); err == nil {
+			owners.keys[key] = user
+		}
+	}
+	return nil
+}
+
+func (o *ownerIndex) add(key, userID int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if known, ok := o.keys[key]; ok && known == userID {
+		return
+	}
+	o.keys[key] = userID
+	if o.store == nil {
+		return
+	}
+	if err := o.store.SetValue(key, ownersValue, strconv.FormatInt(userID, 10)); err != nil {
+		log.Printf("Failed to save the owner of session %d: %v", key, err)
+	}
+}
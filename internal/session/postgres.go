@@ -0,0 +1,454 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// migrationLockID is the advisory lock key held while the schema is
+// bootstrapped, so replicas starting together apply each migration once.
+const migrationLockID = 7305118
+
+// migrations are applied in order and recorded in schema_migrations. Append
+// new entries; never edit one that has shipped.
+var migrations = [][]string{
+	{
+		`CREATE TABLE session_users (
+			user_id BIGINT PRIMARY KEY,
+			active_thread INTEGER NOT NULL DEFAULT 1
+		)`,
+		`CREATE TABLE session_threads (
+			user_id BIGINT NOT NULL REFERENCES session_users (user_id) ON DELETE CASCADE,
+			id INTEGER NOT NULL,
+			title TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (user_id, id)
+		)`,
+		`CREATE TABLE session_messages (
+			user_id BIGINT NOT NULL REFERENCES session_users (user_id) ON DELETE CASCADE,
+			thread_id INTEGER NOT NULL,
+			messages JSONB NOT NULL DEFAULT '[]',
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (user_id, thread_id)
+		)`,
+		`CREATE TABLE session_values (
+			name TEXT NOT NULL,
+			user_id BIGINT NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY (name, user_id)
+		)`,
+	},
+	{
+		`CREATE TABLE session_archive (
+			user_id BIGINT NOT NULL,
+			thread_id INTEGER NOT NULL,
+			messages JSONB NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL,
+			archived_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`,
+		`CREATE INDEX session_messages_updated_at ON session_messages (updated_at)`,
+	},
+	{
+		`CREATE TABLE session_stats (
+			user_id BIGINT PRIMARY KEY,
+			messages INTEGER NOT NULL DEFAULT 0,
+			tokens BIGINT NOT NULL DEFAULT 0,
+			latency_ms BIGINT NOT NULL DEFAULT 0,
+			providers JSONB NOT NULL DEFAULT '{}'
+		)`,
+	},
+}
+
+type postgresManager struct {
+	db          *sql.DB
+	maxMessages int
+}
+
+// NewPostgresManager stores sessions in Postgres so several bot replicas can
+// share them. Writes lock the user's row for the length of the transaction.
+func NewPostgresManager(ctx context.Context, dsn string, maxMessages int) (Manager, error) {
+	if maxMessages == 0 {
+		maxMessages = 50
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &postgresManager{db: db, maxMessages: maxMessages}, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to lock schema: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this build supports (%d)", current, len(migrations))
+	}
+
+	for i := current; i < len(migrations); i++ {
+		for _, stmt := range migrations[i] {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, i+1); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", i+1, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
+
+// withUserLock runs fn in a transaction holding the user's row lock, passing
+// the active thread ID.
+func (m *postgresManager) withUserLock(userID int64, fn func(ctx context.Context, tx *sql.Tx, thread int) error) error {
+	ctx := context.Background()
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO session_users (user_id) VALUES ($1) ON CONFLICT DO NOTHING`, userID); err != nil {
+		return fmt.Errorf("failed to create session user: %w", err)
+	}
+	var thread int
+	if err := tx.QueryRowContext(ctx, `SELECT active_thread FROM session_users WHERE user_id = $1 FOR UPDATE`, userID).Scan(&thread); err != nil {
+		return fmt.Errorf("failed to lock session: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO session_threads (user_id, id, title) VALUES ($1, $2, 'Default') ON CONFLICT DO NOTHING`, userID, defaultThreadID); err != nil {
+		return fmt.Errorf("failed to create default thread: %w", err)
+	}
+
+	if err := fn(ctx, tx, thread); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Lock holds a session-level advisory lock on userID until unlock is
+// called. The lock lives on a connection of its own, so the transactions of
+// withUserLock still run while it is held. Its two-key form does not
+// overlap with migrationLockID.
+func (m *postgresManager) Lock(ctx context.Context, userID int64) (func(), error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	high, low := int32(userID>>32), int32(userID)
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1, $2)`, high, low); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to lock session: %w", err)
+	}
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1, $2)`, high, low); err != nil {
+			log.Printf("Failed to unlock session of user %d: %v", userID, err)
+			// Drop the connection rather than return it to the pool
+			// still holding the lock.
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}, nil
+}
+
+func (m *postgresManager) Get(userID int64) ([]llm.Message, error) {
+	var data []byte
+	err := m.db.QueryRowContext(context.Background(), `SELECT messages FROM session_messages
+		WHERE user_id = $1 AND thread_id = COALESCE((SELECT active_thread FROM session_users WHERE user_id = $1), $2)`,
+		userID, defaultThreadID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []llm.Message{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	return decodeMessages(data)
+}
+
+func (m *postgresManager) Save(userID int64, messages []llm.Message) error {
+	return m.withUserLock(userID, func(ctx context.Context, tx *sql.Tx, thread int) error {
+		return m.write(ctx, tx, userID, thread, messages)
+	})
+}
+
+func (m *postgresManager) Delete(userID int64) error {
+	return m.withUserLock(userID, func(ctx context.Context, tx *sql.Tx, thread int) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM session_messages WHERE user_id = $1 AND thread_id = $2`, userID, thread); err != nil {
+			return fmt.Errorf("failed to delete session: %w", err)
+		}
+		return nil
+	})
+}
+
+func (m *postgresManager) PopLast(userID int64) (llm.Message, error) {
+	var last llm.Message
+	err := m.withUserLock(userID, func(ctx context.Context, tx *sql.Tx, thread int) error {
+		messages, err := m.read(ctx, tx, userID, thread)
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return ErrEmptySession
+		}
+		last = messages[len(messages)-1]
+		return m.write(ctx, tx, userID, thread, messages[:len(messages)-1])
+	})
+	if err != nil {
+		return llm.Message{}, err
+	}
+	return last, nil
+}
+
+func (m *postgresManager) ReplaceLast(userID int64, msg llm.Message) error {
+	return m.withUserLock(userID, func(ctx context.Context, tx *sql.Tx, thread int) error {
+		messages, err := m.read(ctx, tx, userID, thread)
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return ErrEmptySession
+		}
+		messages[len(messages)-1] = msg
+		return m.write(ctx, tx, userID, thread, messages)
+	})
+}
+
+func (m *postgresManager) SetRating(userID int64, messageID string, score int) error {
+	return m.withUserLock(userID, func(ctx context.Context, tx *sql.Tx, thread int) error {
+		messages, err := m.read(ctx, tx, userID, thread)
+		if err != nil {
+			return err
+		}
+		if !rate(messages, messageID, score) {
+			return ErrMessageNotFound
+		}
+		return m.write(ctx, tx, userID, thread, messages)
+	})
+}
+
+func (m *postgresManager) read(ctx context.Context, tx *sql.Tx, userID int64, thread int) ([]llm.Message, error) {
+	var data []byte
+	err := tx.QueryRowContext(ctx, `SELECT messages FROM session_messages WHERE user_id = $1 AND thread_id = $2`, userID, thread).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []llm.Message{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	return decodeMessages(data)
+}
+
+func (m *postgresManager) write(ctx context.Context, tx *sql.Tx, userID int64, thread int, messages []llm.Message) error {
+	messages = llm.TrimHistory(messages, m.maxMessages)
+
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO session_messages (user_id, thread_id, messages, updated_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (user_id, thread_id) DO UPDATE SET messages = EXCLUDED.messages, updated_at = now()`,
+		userID, thread, string(data)); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+func decodeMessages(data []byte) ([]llm.Message, error) {
+	var messages []llm.Message
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	if messages == nil {
+		messages = []llm.Message{}
+	}
+	return messages, nil
+}
+
+func (m *postgresManager) GetProvider(userID int64) (string, error) {
+	return m.getValue("providers", userID)
+}
+
+func (m *postgresManager) SetProvider(userID int64, name string) error {
+	return m.setValue("providers", userID, name)
+}
+
+func (m *postgresManager) Providers() (map[int64]string, error) {
+	return m.Values("providers")
+}
+
+func (m *postgresManager) GetPrompt(userID int64) (string, error) {
+	return m.getValue("prompts", userID)
+}
+
+func (m *postgresManager) SetPrompt(userID int64, prompt string) error {
+	return m.setValue("prompts", userID, prompt)
+}
+
+func (m *postgresManager) GetLanguage(userID int64) (string, error) {
+	return m.getValue("languages", userID)
+}
+
+func (m *postgresManager) SetLanguage(userID int64, lang string) error {
+	return m.setValue("languages", userID, lang)
+}
+
+func (m *postgresManager) getValue(name string, userID int64) (string, error) {
+	var value string
+	err := m.db.QueryRowContext(context.Background(), `SELECT value FROM session_values WHERE name = $1 AND user_id = $2`, name, userID).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return value, nil
+}
+
+func (m *postgresManager) setValue(name string, userID int64, value string) error {
+	var err error
+	if value == "" {
+		_, err = m.db.ExecContext(context.Background(), `DELETE FROM session_values WHERE name = $1 AND user_id = $2`, name, userID)
+	} else {
+		_, err = m.db.ExecContext(context.Background(), `INSERT INTO session_values (name, user_id, value) VALUES ($1, $2, $3)
+			ON CONFLICT (name, user_id) DO UPDATE SET value = EXCLUDED.value`, name, userID, value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (m *postgresManager) NewThread(userID int64, title string) (Thread, error) {
+	var thread Thread
+	err := m.withUserLock(userID, func(ctx context.Context, tx *sql.Tx, _ int) error {
+		var next int
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) + 1 FROM session_threads WHERE user_id = $1`, userID).Scan(&next); err != nil {
+			return fmt.Errorf("failed to read thread index: %w", err)
+		}
+		thread = Thread{ID: next, Title: title}
+		if thread.Title == "" {
+			thread.Title = fmt.Sprintf("Thread %d", thread.ID)
+		}
+
+		if err := tx.QueryRowContext(ctx, `INSERT INTO session_threads (user_id, id, title) VALUES ($1, $2, $3) RETURNING created_at`,
+			userID, thread.ID, thread.Title).Scan(&thread.CreatedAt); err != nil {
+			return fmt.Errorf("failed to write thread index: %w", err)
+		}
+		return m.setActive(ctx, tx, userID, thread.ID)
+	})
+	if err != nil {
+		return Thread{}, err
+	}
+	return thread, nil
+}
+
+func (m *postgresManager) Threads(userID int64) ([]Thread, int, error) {
+	ctx := context.Background()
+
+	active := defaultThreadID
+	err := m.db.QueryRowContext(ctx, `SELECT active_thread FROM session_users WHERE user_id = $1`, userID).Scan(&active)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, fmt.Errorf("failed to read thread index: %w", err)
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT id, title, created_at FROM session_threads WHERE user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read thread index: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []Thread
+	for rows.Next() {
+		var t Thread
+		if err := rows.Scan(&t.ID, &t.Title, &t.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to read thread index: %w", err)
+		}
+		threads = append(threads, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read thread index: %w", err)
+	}
+	if len(threads) == 0 {
+		threads = []Thread{{ID: defaultThreadID, Title: "Default"}}
+	}
+	return threads, active, nil
+}
+
+func (m *postgresManager) ResumeThread(userID int64, id int) (Thread, error) {
+	var thread Thread
+	err := m.withUserLock(userID, func(ctx context.Context, tx *sql.Tx, _ int) error {
+		err := tx.QueryRowContext(ctx, `SELECT id, title, created_at FROM session_threads WHERE user_id = $1 AND id = $2`, userID, id).
+			Scan(&thread.ID, &thread.Title, &thread.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("thread %d not found", id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read thread index: %w", err)
+		}
+		return m.setActive(ctx, tx, userID, id)
+	})
+	if err != nil {
+		return Thread{}, err
+	}
+	return thread, nil
+}
+
+func (m *postgresManager) RenameThread(userID int64, id int, title string) error {
+	res, err := m.db.ExecContext(context.Background(), `UPDATE session_threads SET title = $3 WHERE user_id = $1 AND id = $2`, userID, id, title)
+	if err != nil {
+		return fmt.Errorf("failed to write thread index: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("thread %d not found", id)
+	}
+	return nil
+}
+
+func (m *postgresManager) setActive(ctx context.Context, tx *sql.Tx, userID int64, thread int) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE session_users SET active_thread = $2 WHERE user_id = $1`, userID, thread); err != nil {
+		return fmt.Errorf("failed to write thread index: %w", err)
+	}
+	return nil
+}
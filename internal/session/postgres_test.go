@@ -0,0 +1,189 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+// newTestPostgres connects to the database named by HELPI_TEST_POSTGRES_DSN
+// and starts from an empty schema. The tests are skipped when it is unset.
+func newTestPostgres(t *testing.T, maxMessages int) Manager {
+	t.Helper()
+	dsn := os.Getenv("HELPI_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("HELPI_TEST_POSTGRES_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, table := range []string{"session_archive", "session_messages", "session_stats", "session_threads", "session_users", "session_values", "schema_migrations"} {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mgr, err := NewPostgresManager(context.Background(), dsn, maxMessages)
+	if err != nil {
+		t.Fatalf("NewPostgresManager() returned error: %v", err)
+	}
+	t.Cleanup(func() { mgr.(*postgresManager).db.Close() })
+	return mgr
+}
+
+func TestPostgres_SessionLifecycle(t *testing.T) {
+	mgr := newTestPostgres(t, 3)
+
+	if got, err := mgr.Get(1); err != nil || len(got) != 0 {
+		t.Fatalf("expected empty session, got %v, %v", got, err)
+	}
+
+	messages := []llm.Message{
+		{Role: "user", Content: "1"},
+		{Role: "assistant", Content: "2"},
+		{Role: "user", Content: "3"},
+		{Role: "assistant", Content: "4"},
+	}
+	if err := mgr.Save(1, messages); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := mgr.Get(1)
+	if len(got) != 3 || got[0].Content != "2" {
+		t.Errorf("expected last 3 messages, got %+v", got)
+	}
+
+	last, err := mgr.PopLast(1)
+	if err != nil || last.Content != "4" {
+		t.Errorf("PopLast() = %+v, %v", last, err)
+	}
+	if err := mgr.ReplaceLast(1, llm.Message{Role: "user", Content: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = mgr.Get(1)
+	if len(got) != 2 || got[1].Content != "x" {
+		t.Errorf("expected replaced message, got %+v", got)
+	}
+
+	if err := mgr.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.PopLast(1); !errors.Is(err, ErrEmptySession) {
+		t.Errorf("expected ErrEmptySession, got %v", err)
+	}
+}
+
+func TestPostgres_ValuesAndThreads(t *testing.T) {
+	mgr := newTestPostgres(t, 10)
+
+	if err := mgr.SetProvider(1, "openai"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.SetLanguage(1, "de"); err != nil {
+		t.Fatal(err)
+	}
+	if providers, _ := mgr.Providers(); providers[1] != "openai" {
+		t.Errorf("expected provider to persist, got %v", providers)
+	}
+	if lang, _ := mgr.GetLanguage(1); lang != "de" {
+		t.Errorf("expected language to persist, got %q", lang)
+	}
+	mgr.SetProvider(1, "")
+	if name, _ := mgr.GetProvider(1); name != "" {
+		t.Errorf("expected provider to be cleared, got %q", name)
+	}
+
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "default"}})
+	thread, err := mgr.NewThread(1, "")
+	if err != nil || thread.ID != 2 || thread.Title != "Thread 2" {
+		t.Fatalf("NewThread() = %+v, %v", thread, err)
+	}
+	if got, _ := mgr.Get(1); len(got) != 0 {
+		t.Errorf("expected new thread to start empty, got %+v", got)
+	}
+
+	if _, err := mgr.ResumeThread(1, defaultThreadID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := mgr.Get(1); len(got) != 1 || got[0].Content != "default" {
+		t.Errorf("expected default thread history, got %+v", got)
+	}
+	threads, active, _ := mgr.Threads(1)
+	if len(threads) != 2 || active != defaultThreadID {
+		t.Errorf("unexpected threads %+v, active %d", threads, active)
+	}
+	if _, err := mgr.ResumeThread(1, 9); err == nil {
+		t.Error("expected error for unknown thread")
+	}
+}
+
+func TestPostgres_ConcurrentPopsTakeDistinctMessages(t *testing.T) {
+	mgr := newTestPostgres(t, 100)
+
+	var messages []llm.Message
+	for i := 0; i < 10; i++ {
+		messages = append(messages, llm.Message{Role: "user", Content: strconv.Itoa(i)})
+	}
+	mgr.Save(1, messages)
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg, err := mgr.PopLast(1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			seen[msg.Content] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 10 {
+		t.Errorf("expected every message popped exactly once, got %v", seen)
+	}
+	if got, _ := mgr.Get(1); len(got) != 0 {
+		t.Errorf("expected empty session, got %+v", got)
+	}
+}
+
+func TestPostgres_LockHoldsSessionAcrossWrites(t *testing.T) {
+	mgr := newTestPostgres(t, 100)
+	locker := mgr.(Locker)
+
+	unlock, err := locker.Lock(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Lock() returned error: %v", err)
+	}
+	if err := mgr.Save(1, []llm.Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("expected Save() to work while the session is held, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := locker.Lock(ctx, 1); err == nil {
+		t.Error("expected a second Lock() to wait for the first")
+	}
+
+	unlock()
+	again, err := locker.Lock(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("expected the session to be free after unlock, got %v", err)
+	}
+	again()
+}
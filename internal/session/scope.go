@@ -0,0 +1,181 @@
+package session
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/jrswab/helpi/internal/llm"
+	"github.com/jrswab/helpi/internal/storage"
+)
+
+// ScopedKey derives a session key from parts. Derived keys are below -2^62,
+// far from user IDs, which are positive, and group chat IDs, which are
+// small negative numbers, so they cannot collide with either.
+func ScopedKey(parts ...int64) int64 {
+	hash := fnv.New64a()
+	for _, p := range parts {
+		binary.Write(hash, binary.BigEndian, p)
+	}
+	return -(1<<62 | int64(hash.Sum64()&(1<<62-1)))
+}
+
+type botManager struct {
+	Manager
+	botID int64
+}
+
+// ForBot keeps the sessions and values of the bot botID apart from those of
+// other bots sharing store. Sessions are kept under ScopedKey(botID, key)
+// and values, which are keyed by user or chat ID, under "<name>.<botID>".
+// store must be a ValueStore.
+func ForBot(store Manager, botID int64) Manager {
+	return &botManager{Manager: store, botID: botID}
+}
+
+func (m *botManager) key(userID int64) int64 {
+	return UserKey(userID, m.botID)
+}
+
+// Evict removes a file of the bot's sessions through the store, whose files
+// are named after the scoped keys.
+func (m *botManager) Evict(f storage.File) (bool, error) {
+	e, ok := m.Manager.(storage.Evicter)
+	if !ok {
+		return false, nil
+	}
+	return e.Evict(f)
+}
+
+func (m *botManager) name(name string) string {
+	return name + "." + strconv.FormatInt(m.botID, 10)
+}
+
+func (m *botManager) values() ValueStore {
+	vs, _ := m.Manager.(ValueStore)
+	return vs
+}
+
+func (m *botManager) Get(userID int64) ([]llm.Message, error) {
+	return m.Manager.Get(m.key(userID))
+}
+
+func (m *botManager) Save(userID int64, messages []llm.Message) error {
+	return m.Manager.Save(m.key(userID), messages)
+}
+
+func (m *botManager) Delete(userID int64) error {
+	return m.Manager.Delete(m.key(userID))
+}
+
+func (m *botManager) PopLast(userID int64) (llm.Message, error) {
+	return m.Manager.PopLast(m.key(userID))
+}
+
+func (m *botManager) ReplaceLast(userID int64, msg llm.Message) error {
+	return m.Manager.ReplaceLast(m.key(userID), msg)
+}
+
+func (m *botManager) SetRating(userID int64, messageID string, score int) error {
+	return m.Manager.SetRating(m.key(userID), messageID, score)
+}
+
+func (m *botManager) NewThread(userID int64, title string) (Thread, error) {
+	return m.Manager.NewThread(m.key(userID), title)
+}
+
+func (m *botManager) Threads(userID int64) ([]Thread, int, error) {
+	return m.Manager.Threads(m.key(userID))
+}
+
+func (m *botManager) ResumeThread(userID int64, id int) (Thread, error) {
+	return m.Manager.ResumeThread(m.key(userID), id)
+}
+
+func (m *botManager) RenameThread(userID int64, id int, title string) error {
+	r, ok := m.Manager.(ThreadRenamer)
+	if !ok {
+		return nil
+	}
+	return r.RenameThread(m.key(userID), id, title)
+}
+
+func (m *botManager) Lock(ctx context.Context, userID int64) (func(), error) {
+	l, ok := m.Manager.(Locker)
+	if !ok {
+		return func() {}, nil
+	}
+	return l.Lock(ctx, m.key(userID))
+}
+
+// Usage stats belong to the user rather than to a conversation, so they
+// are shared by every bot.
+
+func (m *botManager) RecordExchange(userID int64, e Exchange) error {
+	r, ok := m.Manager.(StatsRecorder)
+	if !ok {
+		return nil
+	}
+	return r.RecordExchange(userID, e)
+}
+
+func (m *botManager) Stats(userID int64) (Stats, error) {
+	r, ok := m.Manager.(StatsRecorder)
+	if !ok {
+		return Stats{}, nil
+	}
+	return r.Stats(userID)
+}
+
+func (m *botManager) Value(userID int64, name string) (string, error) {
+	vs := m.values()
+	if vs == nil {
+		return "", errValuesUnsupported
+	}
+	return vs.Value(userID, m.name(name))
+}
+
+func (m *botManager) SetValue(userID int64, name, value string) error {
+	vs := m.values()
+	if vs == nil {
+		return errValuesUnsupported
+	}
+	return vs.SetValue(userID, m.name(name), value)
+}
+
+func (m *botManager) Values(name string) (map[int64]string, error) {
+	vs := m.values()
+	if vs == nil {
+		return nil, errValuesUnsupported
+	}
+	return vs.Values(m.name(name))
+}
+
+func (m *botManager) GetProvider(userID int64) (string, error) {
+	return m.Value(userID, "providers")
+}
+
+func (m *botManager) SetProvider(userID int64, name string) error {
+	return m.SetValue(userID, "providers", name)
+}
+
+func (m *botManager) Providers() (map[int64]string, error) {
+	return m.Values("providers")
+}
+
+func (m *botManager) GetPrompt(userID int64) (string, error) {
+	return m.Value(userID, "prompts")
+}
+
+func (m *botManager) SetPrompt(userID int64, prompt string) error {
+	return m.SetValue(userID, "prompts", prompt)
+}
+
+func (m *botManager) GetLanguage(userID int64) (string, error) {
+	return m.Value(userID, "languages")
+}
+
+func (m *botManager) SetLanguage(userID int64, lang string) error {
+	return m.SetValue(userID, "languages", lang)
+}
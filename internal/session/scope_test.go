@@ -0,0 +1,70 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestScopedKey(t *testing.T) {
+	a, b := ScopedKey(-100, 1), ScopedKey(-100, 2)
+	if a == b || a != ScopedKey(-100, 1) {
+		t.Errorf("expected stable, distinct keys, got %d and %d", a, b)
+	}
+	if a > -1<<62 || b > -1<<62 {
+		t.Errorf("expected keys below -2^62, got %d and %d", a, b)
+	}
+}
+
+func TestForBot_KeepsBotsApart(t *testing.T) {
+	store, _ := NewManager(t.TempDir(), 50)
+	other := ForBot(store, 42)
+
+	store.Save(1, []llm.Message{{Role: "user", Content: "default"}})
+	other.Save(1, []llm.Message{{Role: "user", Content: "other"}})
+	store.SetProvider(1, "openai")
+	other.SetProvider(1, "anthropic")
+	other.(ValueStore).SetValue(-100, "personas", "pirate")
+
+	if got, _ := store.Get(1); len(got) != 1 || got[0].Content != "default" {
+		t.Errorf("expected the default bot's session, got %+v", got)
+	}
+	if got, _ := other.Get(1); len(got) != 1 || got[0].Content != "other" {
+		t.Errorf("expected the other bot's session, got %+v", got)
+	}
+	if got, _ := store.GetProvider(1); got != "openai" {
+		t.Errorf("expected the default bot's provider, got %q", got)
+	}
+	if got, _ := other.Providers(); got[1] != "anthropic" || len(got) != 1 {
+		t.Errorf("expected the other bot's providers by user ID, got %v", got)
+	}
+	if got, _ := store.(ValueStore).Value(-100, "personas"); got != "" {
+		t.Errorf("expected the other bot's persona to stay with it, got %q", got)
+	}
+}
+
+func TestUserKey_OwnerSurvivesRestart(t *testing.T) {
+	defer func(o *ownerIndex) { owners = o }(owners)
+	owners = &ownerIndex{keys: make(map[int64]int64)}
+
+	store, _ := NewManager(t.TempDir(), 50)
+	if err := TrackOwners(store); err != nil {
+		t.Fatalf("TrackOwners() returned error: %v", err)
+	}
+	group := UserKey(7, -100)
+	key := UserKey(group, 42)
+	if key != ScopedKey(42, ScopedKey(-100, 7)) {
+		t.Errorf("expected UserKey to derive the same key as ScopedKey")
+	}
+	if Owner(key) != 7 || Owner(group) != 7 || Owner(8) != 8 {
+		t.Errorf("expected scoped keys to map back to user 7, got %d and %d", Owner(key), Owner(group))
+	}
+
+	owners = &ownerIndex{keys: make(map[int64]int64)}
+	if err := TrackOwners(store); err != nil {
+		t.Fatalf("TrackOwners() returned error: %v", err)
+	}
+	if Owner(key) != 7 {
+		t.Errorf("expected the owner to be loaded from the store, got %d", Owner(key))
+	}
+}
@@ -0,0 +1,178 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Stats are lifetime usage counters for one user. They survive /clear and
+// session expiry.
+type Stats struct {
+	Messages  int            `json:"messages"`
+	Tokens    int            `json:"tokens"`
+	LatencyMS int64          `json:"latency_ms"`
+	Providers map[string]int `json:"providers,omitempty"`
+}
+
+// Exchange is one answered message.
+type Exchange struct {
+	Provider string
+	Tokens   int
+	Latency  time.Duration
+}
+
+// StatsRecorder is implemented by stores that keep per-user Stats.
+type StatsRecorder interface {
+	RecordExchange(userID int64, e Exchange) error
+	Stats(userID int64) (Stats, error)
+}
+
+func (s *Stats) add(e Exchange) {
+	s.Messages++
+	s.Tokens += e.Tokens
+	s.LatencyMS += e.Latency.Milliseconds()
+	if e.Provider != "" {
+		if s.Providers == nil {
+			s.Providers = make(map[string]int)
+		}
+		s.Providers[e.Provider]++
+	}
+}
+
+// FavoriteProvider returns the provider that answered most often, breaking
+// ties alphabetically.
+func (s Stats) FavoriteProvider() string {
+	names := make([]string, 0, len(s.Providers))
+	for name := range s.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var favorite string
+	for _, name := range names {
+		if s.Providers[name] > s.Providers[favorite] {
+			favorite = name
+		}
+	}
+	return favorite
+}
+
+func (s Stats) AverageLatency() time.Duration {
+	if s.Messages == 0 {
+		return 0
+	}
+	return time.Duration(s.LatencyMS/int64(s.Messages)) * time.Millisecond
+}
+
+func (m *manager) RecordExchange(userID int64, e Exchange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockValues("stats", true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	all, err := m.readStats()
+	if err != nil {
+		return err
+	}
+	s := all[userID]
+	s.add(e)
+	all[userID] = s
+
+	data, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+	if err := writeFile(m.valuesPath("stats"), data); err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+	return nil
+}
+
+func (m *manager) Stats(userID int64) (Stats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	unlock, err := m.lockValues("stats", false)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer unlock()
+
+	all, err := m.readStats()
+	if err != nil {
+		return Stats{}, err
+	}
+	return all[userID], nil
+}
+
+func (m *manager) readStats() (map[int64]Stats, error) {
+	all := make(map[int64]Stats)
+
+	err := readFile(m.valuesPath("stats"), &all)
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stats: %w", err)
+	}
+	return all, nil
+}
+
+func (m *postgresManager) RecordExchange(userID int64, e Exchange) error {
+	_, err := m.db.ExecContext(context.Background(), `INSERT INTO session_stats (user_id, messages, tokens, latency_ms, providers)
+		VALUES ($1, 1, $2, $3, CASE WHEN $4::text = '' THEN '{}'::jsonb ELSE jsonb_build_object($4::text, 1) END)
+		ON CONFLICT (user_id) DO UPDATE SET
+			messages = session_stats.messages + 1,
+			tokens = session_stats.tokens + EXCLUDED.tokens,
+			latency_ms = session_stats.latency_ms + EXCLUDED.latency_ms,
+			providers = CASE WHEN $4::text = '' THEN session_stats.providers
+				ELSE session_stats.providers || jsonb_build_object($4::text, COALESCE((session_stats.providers ->> $4::text)::int, 0) + 1) END`,
+		userID, e.Tokens, e.Latency.Milliseconds(), e.Provider)
+	if err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+	return nil
+}
+
+func (m *postgresManager) Stats(userID int64) (Stats, error) {
+	var s Stats
+	var providers []byte
+	err := m.db.QueryRowContext(context.Background(), `SELECT messages, tokens, latency_ms, providers FROM session_stats WHERE user_id = $1`, userID).
+		Scan(&s.Messages, &s.Tokens, &s.LatencyMS, &providers)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Stats{}, nil
+	}
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to read stats: %w", err)
+	}
+	if err := json.Unmarshal(providers, &s.Providers); err != nil {
+		return Stats{}, fmt.Errorf("failed to parse stats: %w", err)
+	}
+	return s, nil
+}
+
+func (m *cachedManager) RecordExchange(userID int64, e Exchange) error {
+	r, ok := m.Manager.(StatsRecorder)
+	if !ok {
+		return nil
+	}
+	return r.RecordExchange(userID, e)
+}
+
+func (m *cachedManager) Stats(userID int64) (Stats, error) {
+	r, ok := m.Manager.(StatsRecorder)
+	if !ok {
+		return Stats{}, nil
+	}
+	return r.Stats(userID)
+}
@@ -0,0 +1,167 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultThreadID = 1
+
+type Thread struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ThreadRenamer is implemented by managers that can change a thread's
+// title.
+type ThreadRenamer interface {
+	RenameThread(userID int64, id int, title string) error
+}
+
+// HasDefaultTitle reports whether t still has the title it was created
+// with when none was given.
+func HasDefaultTitle(t Thread) bool {
+	return t.Title == "Default" || t.Title == fmt.Sprintf("Thread %d", t.ID)
+}
+
+type threadIndex struct {
+	Active  int      `json:"active"`
+	Threads []Thread `json:"threads"`
+}
+
+func (m *manager) NewThread(userID int64, title string) (Thread, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockUser(userID, true)
+	if err != nil {
+		return Thread{}, err
+	}
+	defer unlock()
+
+	idx, err := m.readIndex(userID)
+	if err != nil {
+		return Thread{}, err
+	}
+
+	next := 0
+	for _, t := range idx.Threads {
+		next = max(next, t.ID)
+	}
+	thread := Thread{ID: next + 1, Title: title, CreatedAt: time.Now()}
+	if thread.Title == "" {
+		thread.Title = fmt.Sprintf("Thread %d", thread.ID)
+	}
+
+	idx.Threads = append(idx.Threads, thread)
+	idx.Active = thread.ID
+	if err := m.writeIndex(userID, idx); err != nil {
+		return Thread{}, err
+	}
+
+	return thread, nil
+}
+
+func (m *manager) Threads(userID int64) ([]Thread, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	unlock, err := m.lockUser(userID, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer unlock()
+
+	idx, err := m.readIndex(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return idx.Threads, idx.Active, nil
+}
+
+func (m *manager) ResumeThread(userID int64, id int) (Thread, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockUser(userID, true)
+	if err != nil {
+		return Thread{}, err
+	}
+	defer unlock()
+
+	idx, err := m.readIndex(userID)
+	if err != nil {
+		return Thread{}, err
+	}
+
+	for _, t := range idx.Threads {
+		if t.ID == id {
+			idx.Active = id
+			if err := m.writeIndex(userID, idx); err != nil {
+				return Thread{}, err
+			}
+			return t, nil
+		}
+	}
+
+	return Thread{}, fmt.Errorf("thread %d not found", id)
+}
+
+func (m *manager) RenameThread(userID int64, id int, title string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := m.lockUser(userID, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	idx, err := m.readIndex(userID)
+	if err != nil {
+		return err
+	}
+	for i, t := range idx.Threads {
+		if t.ID == id {
+			idx.Threads[i].Title = title
+			return m.writeIndex(userID, idx)
+		}
+	}
+	return fmt.Errorf("thread %d not found", id)
+}
+
+func (m *manager) readIndex(userID int64) (threadIndex, error) {
+	idx := threadIndex{
+		Active:  defaultThreadID,
+		Threads: []Thread{{ID: defaultThreadID, Title: "Default"}},
+	}
+
+	err := readFile(m.indexPath(userID), &idx)
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return idx, fmt.Errorf("failed to load thread index: %w", err)
+	}
+
+	return idx, nil
+}
+
+func (m *manager) writeIndex(userID int64, idx threadIndex) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal thread index: %w", err)
+	}
+	if err := writeFile(m.indexPath(userID), data); err != nil {
+		return fmt.Errorf("failed to write thread index: %w", err)
+	}
+	return nil
+}
+
+func (m *manager) indexPath(userID int64) string {
+	return filepath.Join(m.path, fmt.Sprintf("%d_threads.json", userID))
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+func TestThreads_DefaultThread(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	threads, active, err := mgr.Threads(1)
+	if err != nil {
+		t.Fatalf("Threads() returned error: %v", err)
+	}
+	if len(threads) != 1 || threads[0].Title != "Default" || active != threads[0].ID {
+		t.Errorf("expected a single active default thread, got %+v (active %d)", threads, active)
+	}
+}
+
+func TestThreads_NewAndResumeKeepHistoriesSeparate(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	if err := mgr.Save(1, []llm.Message{{Role: "user", Content: "first"}}); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	thread, err := mgr.NewThread(1, "Trip planning")
+	if err != nil {
+		t.Fatalf("NewThread() returned error: %v", err)
+	}
+	if thread.ID != 2 || thread.Title != "Trip planning" {
+		t.Errorf("unexpected thread %+v", thread)
+	}
+
+	msgs, _ := mgr.Get(1)
+	if len(msgs) != 0 {
+		t.Errorf("expected new thread to start empty, got %d messages", len(msgs))
+	}
+	mgr.Save(1, []llm.Message{{Role: "user", Content: "second"}})
+
+	if _, err := mgr.ResumeThread(1, 1); err != nil {
+		t.Fatalf("ResumeThread() returned error: %v", err)
+	}
+	msgs, _ = mgr.Get(1)
+	if len(msgs) != 1 || msgs[0].Content != "first" {
+		t.Errorf("expected default thread history, got %+v", msgs)
+	}
+
+	mgr.ResumeThread(1, 2)
+	msgs, _ = mgr.Get(1)
+	if len(msgs) != 1 || msgs[0].Content != "second" {
+		t.Errorf("expected second thread history, got %+v", msgs)
+	}
+
+	threads, active, _ := mgr.Threads(1)
+	if len(threads) != 2 || active != 2 {
+		t.Errorf("expected 2 threads with the second active, got %+v (active %d)", threads, active)
+	}
+}
+
+func TestResumeThread_Unknown(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+
+	if _, err := mgr.ResumeThread(1, 5); err == nil {
+		t.Error("expected error for unknown thread")
+	}
+}
+
+func TestRenameThread(t *testing.T) {
+	mgr, err := NewManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("NewManager() returned error: %v", err)
+	}
+	renamer := mgr.(ThreadRenamer)
+
+	threads, _, _ := mgr.Threads(1)
+	if !HasDefaultTitle(threads[0]) {
+		t.Errorf("expected %q to be a default title", threads[0].Title)
+	}
+	if err := renamer.RenameThread(1, 1, "Trip planning"); err != nil {
+		t.Fatalf("RenameThread() returned error: %v", err)
+	}
+	threads, _, _ = mgr.Threads(1)
+	if threads[0].Title != "Trip planning" || HasDefaultTitle(threads[0]) {
+		t.Errorf("expected the thread to be renamed, got %+v", threads[0])
+	}
+
+	thread, _ := mgr.NewThread(1, "")
+	if !HasDefaultTitle(thread) {
+		t.Errorf("expected %q to be a default title", thread.Title)
+	}
+	if err := renamer.RenameThread(1, 9, "x"); err == nil {
+		t.Error("expected error for unknown thread")
+	}
+}
@@ -0,0 +1,119 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jrswab/helpi/internal/llm"
+)
+
+var (
+	ErrInvalidTranscript = errors.New("not a conversation transcript")
+
+	// roleHeading matches the Markdown headings that start a message, such
+	// as "## User" or "### Assistant:".
+	roleHeading = regexp.MustCompile(`(?i)^#{1,6}\s*(user|assistant|system)\s*:?\s*$`)
+)
+
+// ParseTranscript reads a conversation exported from helpi or written by
+// hand. JSON files hold a list of messages, as stored in session files, or
+// an object with a "messages" list. Markdown files start each message with
+// a "## User", "## Assistant" or "## System" heading.
+func ParseTranscript(filename string, data []byte) ([]llm.Message, error) {
+	var (
+		messages []llm.Message
+		err      error
+	)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		messages, err = parseJSONTranscript(data)
+	case ".md", ".markdown", ".txt":
+		messages = parseMarkdownTranscript(string(data))
+	default:
+		trimmed := bytes.TrimSpace(data)
+		if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+			messages, err = parseJSONTranscript(data)
+		} else {
+			messages = parseMarkdownTranscript(string(data))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return validTranscript(messages)
+}
+
+func parseJSONTranscript(data []byte) ([]llm.Message, error) {
+	var messages []llm.Message
+	if err := json.Unmarshal(data, &messages); err == nil {
+		return messages, nil
+	}
+	var wrapped struct {
+		Messages []llm.Message `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTranscript, err)
+	}
+	return wrapped.Messages, nil
+}
+
+func parseMarkdownTranscript(text string) []llm.Message {
+	var (
+		messages []llm.Message
+		current  *llm.Message
+		body     []string
+	)
+	flush := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(strings.Join(body, "\n"))
+			messages = append(messages, *current)
+		}
+		body = nil
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if m := roleHeading.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			flush()
+			current = &llm.Message{Role: strings.ToLower(m[1])}
+			continue
+		}
+		// Anything before the first role heading, such as a title, is
+		// not part of the conversation.
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+	return messages
+}
+
+// validTranscript checks the roles of the messages and drops empty ones and
+// per-request context, which is never stored in a session. System messages
+// become user messages and nothing is pinned, so an upload cannot add
+// standing instructions to the conversation.
+func validTranscript(messages []llm.Message) ([]llm.Message, error) {
+	result := make([]llm.Message, 0, len(messages))
+	for i, m := range messages {
+		m.Role = strings.ToLower(strings.TrimSpace(m.Role))
+		m.Pinned = false
+		switch m.Role {
+		case "user", "assistant":
+		case "system":
+			m.Role = "user"
+		default:
+			return nil, fmt.Errorf("%w: message %d has unknown role %q", ErrInvalidTranscript, i+1, m.Role)
+		}
+		if m.Context || (strings.TrimSpace(m.Content) == "" && len(m.Images) == 0) {
+			continue
+		}
+		result = append(result, m)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: no messages found", ErrInvalidTranscript)
+	}
+	return result, nil
+}
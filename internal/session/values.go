@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var errValuesUnsupported = errors.New("session store does not support values")
+
+// ValueStore is implemented by stores that keep named per-user string
+// values. Provider, prompt and language choices are stored this way, and
+// internal/settings builds on it.
+type ValueStore interface {
+	Value(userID int64, name string) (string, error)
+	SetValue(userID int64, name, value string) error
+	Values(name string) (map[int64]string, error)
+}
+
+func (m *manager) Value(userID int64, name string) (string, error) {
+	values, err := m.Values(name)
+	if err != nil {
+		return "", err
+	}
+	return values[userID], nil
+}
+
+func (m *manager) SetValue(userID int64, name, value string) error {
+	return m.setValue(name, userID, value)
+}
+
+func (m *manager) Values(name string) (map[int64]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	unlock, err := m.lockValues(name, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return m.readValues(name)
+}
+
+func (m *postgresManager) Value(userID int64, name string) (string, error) {
+	return m.getValue(name, userID)
+}
+
+func (m *postgresManager) SetValue(userID int64, name, value string) error {
+	return m.setValue(name, userID, value)
+}
+
+func (m *postgresManager) Values(name string) (map[int64]string, error) {
+	rows, err := m.db.QueryContext(context.Background(), `SELECT user_id, value FROM session_values WHERE name = $1`, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	values := make(map[int64]string)
+	for rows.Next() {
+		var userID int64
+		var value string
+		if err := rows.Scan(&userID, &value); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		values[userID] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return values, nil
+}
+
+func (m *cachedManager) Value(userID int64, name string) (string, error) {
+	vs, ok := m.Manager.(ValueStore)
+	if !ok {
+		return "", errValuesUnsupported
+	}
+	return vs.Value(userID, name)
+}
+
+func (m *cachedManager) SetValue(userID int64, name, value string) error {
+	vs, ok := m.Manager.(ValueStore)
+	if !ok {
+		return errValuesUnsupported
+	}
+	return vs.SetValue(userID, name, value)
+}
+
+func (m *cachedManager) Values(name string) (map[int64]string, error) {
+	vs, ok := m.Manager.(ValueStore)
+	if !ok {
+		return nil, errValuesUnsupported
+	}
+	return vs.Values(name)
+}
@@ -0,0 +1,162 @@
+package settings
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// Key names a setting. The file backend stores each key in <key>.json under
+// memory.path, so keys must be plain lowercase words.
+type Key string
+
+const (
+	Provider Key = "providers"
+	Prompt   Key = "prompts"
+	Language Key = "languages"
+	// Persona is stored per chat, keyed by chat ID.
+	Persona Key = "personas"
+	Digest  Key = "digests"
+	// Quiet is stored per chat, keyed by chat ID.
+	Quiet       Key = "quiet"
+	Temperature Key = "temperatures"
+)
+
+// Backend persists raw values. An empty value deletes the setting. The
+// session managers implement it.
+type Backend interface {
+	Value(userID int64, name string) (string, error)
+	SetValue(userID int64, name, value string) error
+	Values(name string) (map[int64]string, error)
+}
+
+// Store holds per-user preferences with typed accessors. Values live in the
+// memory backend configured under memory, next to the user's sessions.
+type Store struct {
+	backend Backend
+}
+
+func New(backend Backend) *Store {
+	return &Store{backend: backend}
+}
+
+func (s *Store) String(userID int64, key Key) (string, error) {
+	value, err := s.backend.Value(userID, string(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to read setting %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func (s *Store) SetString(userID int64, key Key, value string) error {
+	if err := s.backend.SetValue(userID, string(key), value); err != nil {
+		return fmt.Errorf("failed to write setting %s: %w", key, err)
+	}
+	return nil
+}
+
+// Bool returns fallback when the setting is unset.
+func (s *Store) Bool(userID int64, key Key, fallback bool) (bool, error) {
+	value, err := s.String(userID, key)
+	if err != nil || value == "" {
+		return fallback, err
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback, fmt.Errorf("setting %s for user %d is not a bool: %q", key, userID, value)
+	}
+	return b, nil
+}
+
+func (s *Store) SetBool(userID int64, key Key, value bool) error {
+	return s.SetString(userID, key, strconv.FormatBool(value))
+}
+
+// Int returns fallback when the setting is unset.
+func (s *Store) Int(userID int64, key Key, fallback int) (int, error) {
+	value, err := s.String(userID, key)
+	if err != nil || value == "" {
+		return fallback, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback, fmt.Errorf("setting %s for user %d is not an integer: %q", key, userID, value)
+	}
+	return n, nil
+}
+
+func (s *Store) SetInt(userID int64, key Key, value int) error {
+	return s.SetString(userID, key, strconv.Itoa(value))
+}
+
+// Float returns fallback when the setting is unset.
+func (s *Store) Float(userID int64, key Key, fallback float64) (float64, error) {
+	value, err := s.String(userID, key)
+	if err != nil || value == "" {
+		return fallback, err
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback, fmt.Errorf("setting %s for user %d is not a number: %q", key, userID, value)
+	}
+	return f, nil
+}
+
+func (s *Store) SetFloat(userID int64, key Key, value float64) error {
+	return s.SetString(userID, key, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// Reset removes the setting so its default applies again.
+func (s *Store) Reset(userID int64, key Key) error {
+	return s.SetString(userID, key, "")
+}
+
+// All returns the value of key for every user that has set it.
+func (s *Store) All(key Key) (map[int64]string, error) {
+	values, err := s.backend.Values(string(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read setting %s: %w", key, err)
+	}
+	return values, nil
+}
+
+// MemoryBackend keeps settings in memory. It is meant for tests and for
+// running without persistent storage.
+type MemoryBackend struct {
+	mu     sync.RWMutex
+	values map[string]map[int64]string
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{values: make(map[string]map[int64]string)}
+}
+
+func (b *MemoryBackend) Value(userID int64, name string) (string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.values[name][userID], nil
+}
+
+func (b *MemoryBackend) SetValue(userID int64, name, value string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if value == "" {
+		delete(b.values[name], userID)
+		return nil
+	}
+	if b.values[name] == nil {
+		b.values[name] = make(map[int64]string)
+	}
+	b.values[name][userID] = value
+	return nil
+}
+
+func (b *MemoryBackend) Values(name string) (map[int64]string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	values := make(map[int64]string, len(b.values[name]))
+	for userID, value := range b.values[name] {
+		values[userID] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,62 @@
+package settings
+
+import "testing"
+
+const testKey Key = "test"
+
+func TestStore_TypedValues(t *testing.T) {
+	s := New(NewMemoryBackend())
+
+	if v, err := s.Bool(1, testKey, true); err != nil || !v {
+		t.Errorf("expected fallback for unset bool, got %v, %v", v, err)
+	}
+	if err := s.SetBool(1, testKey, false); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.Bool(1, testKey, true); err != nil || v {
+		t.Errorf("Bool() = %v, %v, want false", v, err)
+	}
+
+	if err := s.SetInt(1, testKey, 42); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.Int(1, testKey, 0); err != nil || v != 42 {
+		t.Errorf("Int() = %v, %v, want 42", v, err)
+	}
+
+	if err := s.SetFloat(1, testKey, 0.7); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.Float(1, testKey, 0); err != nil || v != 0.7 {
+		t.Errorf("Float() = %v, %v, want 0.7", v, err)
+	}
+
+	if err := s.SetString(1, testKey, "yes please"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := s.Int(1, testKey, 5); err == nil || v != 5 {
+		t.Errorf("expected parse error and fallback, got %v, %v", v, err)
+	}
+
+	if err := s.Reset(1, testKey); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.String(1, testKey); v != "" {
+		t.Errorf("expected reset value to be empty, got %q", v)
+	}
+}
+
+func TestStore_All(t *testing.T) {
+	s := New(NewMemoryBackend())
+	s.SetString(1, Language, "de")
+	s.SetString(2, Language, "es")
+	s.SetString(2, Prompt, "Be brief.")
+
+	all, err := s.All(Language)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[1] != "de" || all[2] != "es" {
+		t.Errorf("All() = %v", all)
+	}
+}
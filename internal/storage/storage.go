@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File is a file that belongs to one user, such as a session or their
+// uploaded documents.
+type File struct {
+	// UserID is the key the file is named after. Owner, when set, is the
+	// user the file counts against, such as the user of a conversation
+	// kept under a scoped key.
+	UserID  int64
+	Owner   int64
+	Path    string
+	Size    int64
+	ModTime time.Time
+	// Evictable is false for files that must outlive the data they
+	// describe, such as thread indexes and lock files.
+	Evictable bool
+}
+
+// Evicter is implemented by stores that can remove one of their files
+// under the locks that guard it. Evict reports false when f is not one of
+// the store's files.
+type Evicter interface {
+	Evict(f File) (bool, error)
+}
+
+// Quota caps the bytes stored per user and in total. Zero means no limit.
+type Quota struct {
+	UserBytes  int64
+	TotalBytes int64
+}
+
+// Usage is the stored bytes per user and in total.
+type Usage struct {
+	Users map[int64]int64
+	Total int64
+}
+
+// Scan lists the files named after a user ID, such as 42.json or
+// 42_3.json, directly under each of dirs. Missing directories are skipped.
+func Scan(dirs ...string) ([]File, error) {
+	var files []File
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			userID, evictable, ok := parseName(entry.Name())
+			if !ok {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			files = append(files, File{
+				UserID:    userID,
+				Path:      filepath.Join(dir, entry.Name()),
+				Size:      info.Size(),
+				ModTime:   info.ModTime(),
+				Evictable: evictable,
+			})
+		}
+	}
+	return files, nil
+}
+
+// parseName returns the user a file belongs to. Conversations
+// (<id>.json, <id>_<thread>.json) and their backups can be evicted;
+// anything else named after the user, such as <id>_threads.json, cannot.
+func parseName(name string) (int64, bool, bool) {
+	user, rest, _ := strings.Cut(name, ".")
+	user, thread, hasThread := strings.Cut(user, "_")
+	userID, err := strconv.ParseInt(user, 10, 64)
+	if err != nil {
+		return 0, false, false
+	}
+	if hasThread {
+		if _, err := strconv.Atoi(thread); err != nil {
+			return userID, false, true
+		}
+	}
+	return userID, rest == "json" || rest == "json.bak", true
+}
+
+func (f File) owner() int64 {
+	if f.Owner != 0 {
+		return f.Owner
+	}
+	return f.UserID
+}
+
+func Summarize(files []File) Usage {
+	u := Usage{Users: make(map[int64]int64)}
+	for _, f := range files {
+		u.Users[f.owner()] += f.Size
+		u.Total += f.Size
+	}
+	return u
+}
+
+// Over returns the files to remove, oldest first, to bring every user and
+// the total within q.
+func Over(files []File, q Quota) []File {
+	usage := Summarize(files)
+
+	candidates := make([]File, 0, len(files))
+	for _, f := range files {
+		if f.Evictable {
+			candidates = append(candidates, f)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ModTime.Before(candidates[j].ModTime)
+	})
+
+	var evict []File
+	var kept []File
+	for _, f := range candidates {
+		if q.UserBytes > 0 && usage.Users[f.owner()] > q.UserBytes {
+			evict = append(evict, f)
+			usage.Users[f.owner()] -= f.Size
+			usage.Total -= f.Size
+			continue
+		}
+		kept = append(kept, f)
+	}
+	for _, f := range kept {
+		if q.TotalBytes <= 0 || usage.Total <= q.TotalBytes {
+			break
+		}
+		evict = append(evict, f)
+		usage.Total -= f.Size
+	}
+	return evict
+}
+
+// FormatBytes writes n in the largest unit that keeps it at least 1.
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
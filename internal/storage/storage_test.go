@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name string, size int, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	sessions, documents := t.TempDir(), t.TempDir()
+	writeFile(t, sessions, "1.json", 10, 0)
+	writeFile(t, sessions, "1_2.json.bak", 5, 0)
+	writeFile(t, sessions, "1_threads.json", 3, 0)
+	writeFile(t, sessions, "prompts.json", 100, 0)
+	writeFile(t, documents, "2.json", 20, 0)
+
+	files, err := Scan(sessions, documents, filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("Scan() returned error: %v", err)
+	}
+	usage := Summarize(files)
+	if usage.Users[1] != 18 || usage.Users[2] != 20 || usage.Total != 38 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	for _, f := range files {
+		if want := !strings.Contains(f.Path, "threads"); f.Evictable != want {
+			t.Errorf("%s: evictable = %v, want %v", f.Path, f.Evictable, want)
+		}
+	}
+}
+
+func TestOver(t *testing.T) {
+	now := time.Now()
+	files := []File{
+		{UserID: 1, Path: "1_1.json", Size: 40, ModTime: now.Add(-3 * time.Hour), Evictable: true},
+		{UserID: 1, Path: "1.json", Size: 40, ModTime: now.Add(-time.Hour), Evictable: true},
+		{UserID: 1, Path: "1_threads.json", Size: 40, ModTime: now.Add(-5 * time.Hour)},
+		{UserID: 2, Path: "2.json", Size: 30, ModTime: now.Add(-4 * time.Hour), Evictable: true},
+		{UserID: 3, Path: "3.json", Size: 30, ModTime: now.Add(-2 * time.Hour), Evictable: true},
+	}
+
+	paths := func(files []File) string {
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Path)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := paths(Over(files, Quota{})); got != "" {
+		t.Errorf("expected nothing to evict without a quota, got %s", got)
+	}
+	if got := paths(Over(files, Quota{UserBytes: 100})); got != "1_1.json" {
+		t.Errorf("expected the oldest file of the user over quota, got %s", got)
+	}
+	if got := paths(Over(files, Quota{TotalBytes: 120})); got != "2.json,1_1.json" {
+		t.Errorf("expected the oldest files overall, got %s", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 2048: "2.0 KiB", 5 << 20: "5.0 MiB"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// releasesURL is the GitHub API endpoint for the latest release, replaced
+// in tests.
+var releasesURL = "https://api.github.com/repos/jrswab/helpi/releases/latest"
+
+// Release is a published helpi release.
+type Release struct {
+	Version string `json:"tag_name"`
+	URL     string `json:"html_url"`
+}
+
+// Latest returns the newest published release.
+func Latest(ctx context.Context, client *http.Client) (Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releasesURL, nil)
+	if err != nil {
+		return Release{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "helpi/"+Current())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("update check failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("update check failed with HTTP %d", resp.StatusCode)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return Release{}, fmt.Errorf("update check: invalid response: %w", err)
+	}
+	return release, nil
+}
+
+// Newer reports whether latest is a higher semantic version than current.
+// Versions that do not parse, such as dev builds, are never older.
+func Newer(latest, current string) bool {
+	l, ok := parseSemver(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseSemver(current)
+	if !ok {
+		return false
+	}
+	for i := range l.core {
+		if l.core[i] != c.core[i] {
+			return l.core[i] > c.core[i]
+		}
+	}
+	// A pre-release sorts before the release it leads up to.
+	if l.pre == "" || c.pre == "" {
+		return l.pre == "" && c.pre != ""
+	}
+	return l.pre > c.pre
+}
+
+type semver struct {
+	core [3]int
+	pre  string
+}
+
+func parseSemver(v string) (semver, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	var s semver
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v, s.pre = v[:i], v[i+1:]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		s.core[i] = n
+	}
+	return s, true
+}
+
+// UpdateChecker reports each newer release once. The last reported release
+// is kept in a file, so a restart does not report it again.
+type UpdateChecker struct {
+	client   *http.Client
+	current  string
+	path     string
+	mu       sync.Mutex
+	notified string
+}
+
+type updateState struct {
+	Notified string `json:"notified"`
+}
+
+func NewUpdateChecker(current, path string) (*UpdateChecker, error) {
+	if path == "" {
+		path = "./data/updates.json"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create update check directory: %w", err)
+	}
+
+	c := &UpdateChecker{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		current: current,
+		path:    path,
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read update check state: %w", err)
+	}
+	if len(data) > 0 {
+		var state updateState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("failed to parse update check state: %w", err)
+		}
+		c.notified = state.Notified
+	}
+	return c, nil
+}
+
+// Check returns the latest release and true when it is newer than the
+// running version and has not been reported before.
+func (c *UpdateChecker) Check(ctx context.Context) (Release, bool, error) {
+	release, err := Latest(ctx, c.client)
+	if err != nil {
+		return Release{}, false, err
+	}
+	if !Newer(release.Version, c.current) {
+		return release, false, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.notified == release.Version {
+		return release, false, nil
+	}
+	c.notified = release.Version
+	if err := c.save(); err != nil {
+		log.Printf("%v", err)
+	}
+	return release, true, nil
+}
+
+func (c *UpdateChecker) save() error {
+	data, err := json.Marshal(updateState{Notified: c.notified})
+	if err != nil {
+		return fmt.Errorf("failed to marshal update check state: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write update check state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.3.0", "v1.2.9", true},
+		{"v1.2.10", "v1.2.9", true},
+		{"v2.0.0", "1.9.9", true},
+		{"v1.2.0", "v1.2.0", false},
+		{"v1.1.0", "v1.2.0", false},
+		{"v1.2.0", "v1.2.0-rc.1", true},
+		{"v1.2.0-rc.1", "v1.2.0", false},
+		{"v1.2.0", "dev", false},
+		{"latest", "v1.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateChecker_ReportsEachReleaseOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v1.4.0", "html_url": "https://github.com/jrswab/helpi/releases/tag/v1.4.0"}`))
+	}))
+	defer server.Close()
+	defer func(url string) { releasesURL = url }(releasesURL)
+	releasesURL = server.URL
+
+	path := filepath.Join(t.TempDir(), "updates.json")
+	checker, err := NewUpdateChecker("v1.3.2", path)
+	if err != nil {
+		t.Fatalf("NewUpdateChecker: %v", err)
+	}
+	release, newer, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if !newer || release.Version != "v1.4.0" || release.URL == "" {
+		t.Errorf("expected v1.4.0 to be reported, got %+v newer=%v", release, newer)
+	}
+
+	if _, newer, _ := checker.Check(context.Background()); newer {
+		t.Error("expected the same release not to be reported twice")
+	}
+
+	restarted, err := NewUpdateChecker("v1.3.2", path)
+	if err != nil {
+		t.Fatalf("NewUpdateChecker: %v", err)
+	}
+	if _, newer, _ := restarted.Check(context.Background()); newer {
+		t.Error("expected a restart not to report the same release again")
+	}
+
+	latest, err := NewUpdateChecker("v1.4.0", filepath.Join(t.TempDir(), "updates.json"))
+	if err != nil {
+		t.Fatalf("NewUpdateChecker: %v", err)
+	}
+	if _, newer, _ := latest.Check(context.Background()); newer {
+		t.Error("expected no update when running the latest release")
+	}
+}
+
+func TestLatest_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	defer func(url string) { releasesURL = url }(releasesURL)
+	releasesURL = server.URL
+
+	if _, err := Latest(context.Background(), server.Client()); err == nil {
+		t.Fatal("expected an error for HTTP 403")
+	}
+}
@@ -0,0 +1,62 @@
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Version, Commit and Date describe the build. Release builds set them with
+//
+//	go build -ldflags "-X github.com/jrswab/helpi/internal/version.Version=v1.2.0 \
+//	  -X github.com/jrswab/helpi/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/jrswab/helpi/internal/version.Date=$(date -u +%Y-%m-%d)" ./cmd/bot
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// Current returns the version of the build. Builds without ldflags fall
+// back to the module version recorded by the Go toolchain.
+func Current() string {
+	if Version == "dev" {
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			return info.Main.Version
+		}
+	}
+	return Version
+}
+
+// String returns the version followed by the commit and build date when
+// they are known. Builds without ldflags fall back to the module version
+// and VCS revision recorded by the Go toolchain.
+func String() string {
+	version, commit := Current(), Commit
+	if info, ok := debug.ReadBuildInfo(); ok && commit == "" {
+		commit = buildSetting(info, "vcs.revision")
+	}
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+
+	var details []string
+	if commit != "" {
+		details = append(details, commit)
+	}
+	if Date != "" {
+		details = append(details, Date)
+	}
+	if len(details) == 0 {
+		return version
+	}
+	return version + " (" + strings.Join(details, ", ") + ")"
+}
+
+func buildSetting(info *debug.BuildInfo, key string) string {
+	for _, s := range info.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Event types.
+const (
+	EventMessage = "message"
+	EventError   = "error"
+	EventUsage   = "usage_threshold"
+)
+
+// Events lists every event type.
+var Events = []string{EventMessage, EventError, EventUsage}
+
+const (
+	// EventHeader names the event type of a delivery.
+	EventHeader = "X-Helpi-Event"
+	// SignatureHeader holds "sha256=" and the hex HMAC-SHA256 of the body,
+	// keyed with the endpoint's secret.
+	SignatureHeader = "X-Helpi-Signature"
+
+	defaultRetries = 3
+	defaultBackoff = time.Second
+	requestTimeout = 10 * time.Second
+)
+
+// Event is the JSON body of a delivery. Content holds what users wrote and
+// were answered; it is merged into Data only for endpoints that include
+// content.
+type Event struct {
+	Type    string         `json:"event"`
+	Time    time.Time      `json:"time"`
+	UserID  int64          `json:"user_id,omitempty"`
+	ChatID  int64          `json:"chat_id,omitempty"`
+	Data    map[string]any `json:"data,omitempty"`
+	Content map[string]any `json:"-"`
+}
+
+// Endpoint receives the events listed in Events, or every event when it is
+// empty. Deliveries are signed when Secret is set. The event's Content is
+// only sent when IncludeContent is set.
+type Endpoint struct {
+	URL            string
+	Secret         string
+	Events         []string
+	IncludeContent bool
+}
+
+func (e Endpoint) wants(eventType string) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, eventType)
+}
+
+// Dispatcher delivers events in the background. Failed deliveries are
+// retried with exponential backoff.
+type Dispatcher struct {
+	endpoints []Endpoint
+	client    *http.Client
+	retries   int
+	backoff   time.Duration
+	wg        sync.WaitGroup
+}
+
+// New returns a Dispatcher for endpoints.
+func New(endpoints []Endpoint) *Dispatcher {
+	return &Dispatcher{
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: requestTimeout},
+		retries:   defaultRetries,
+		backoff:   defaultBackoff,
+	}
+}
+
+// Send delivers e to every endpoint that wants it without waiting for the
+// deliveries. A zero Time is set to now.
+func (d *Dispatcher) Send(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	bodies := make(map[bool][]byte, 2)
+
+	for _, endpoint := range d.endpoints {
+		if !endpoint.wants(e.Type) {
+			continue
+		}
+		body, ok := bodies[endpoint.IncludeContent]
+		if !ok {
+			var err error
+			if body, err = encode(e, endpoint.IncludeContent); err != nil {
+				log.Printf("Webhook: failed to encode %s event: %v", e.Type, err)
+				return
+			}
+			bodies[endpoint.IncludeContent] = body
+		}
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			if err := d.deliver(endpoint, e.Type, body); err != nil {
+				log.Printf("Webhook: failed to deliver %s event to %s: %v", e.Type, endpoint.URL, err)
+			}
+		}()
+	}
+}
+
+func encode(e Event, withContent bool) ([]byte, error) {
+	if withContent && len(e.Content) > 0 {
+		data := make(map[string]any, len(e.Data)+len(e.Content))
+		maps.Copy(data, e.Data)
+		maps.Copy(data, e.Content)
+		e.Data = data
+	}
+	return json.Marshal(e)
+}
+
+// Wait blocks until all deliveries started so far are done.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) deliver(endpoint Endpoint, eventType string, body []byte) error {
+	for attempt := 0; ; attempt++ {
+		retry, err := d.post(endpoint, eventType, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= d.retries {
+			return err
+		}
+		time.Sleep(d.backoff << attempt)
+	}
+}
+
+// post sends one delivery and reports whether a failure is worth retrying.
+func (d *Dispatcher) post(endpoint Endpoint, eventType string, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	if endpoint.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(endpoint.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("status %d", resp.StatusCode)
+	}
+}
+
+// Sign returns the value of SignatureHeader for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
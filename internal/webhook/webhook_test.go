@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestDispatcher(endpoints ...Endpoint) *Dispatcher {
+	d := New(endpoints)
+	d.backoff = time.Millisecond
+	return d
+}
+
+func TestDispatcher_SignsDeliveries(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(Endpoint{URL: server.URL, Secret: "s3cret", IncludeContent: true})
+	d.Send(Event{Type: EventMessage, UserID: 1, ChatID: 2, Content: map[string]any{"answer": "hi"}})
+	d.Wait()
+
+	if header.Get(EventHeader) != EventMessage {
+		t.Errorf("expected event header, got %q", header.Get(EventHeader))
+	}
+	if got, want := header.Get(SignatureHeader), Sign("s3cret", body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		t.Fatalf("invalid body %s: %v", body, err)
+	}
+	if e.Type != EventMessage || e.UserID != 1 || e.ChatID != 2 || e.Data["answer"] != "hi" || e.Time.IsZero() {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
+
+func TestDispatcher_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(Endpoint{URL: server.URL})
+	d.Send(Event{Type: EventError})
+	d.Wait()
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestDispatcher_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(Endpoint{URL: server.URL})
+	d.Send(Event{Type: EventError})
+	d.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestDispatcher_FiltersEvents(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(Endpoint{URL: server.URL, Events: []string{EventUsage}})
+	d.Send(Event{Type: EventMessage})
+	d.Send(Event{Type: EventUsage})
+	d.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected only the usage event, got %d deliveries", calls.Load())
+	}
+}
+
+func TestDispatcher_ContentIsOptIn(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(Endpoint{URL: server.URL})
+	d.Send(Event{Type: EventMessage, Data: map[string]any{"model": "m"}, Content: map[string]any{"prompt": "secret"}})
+	d.Wait()
+
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		t.Fatalf("invalid body %s: %v", body, err)
+	}
+	if e.Data["model"] != "m" || e.Data["prompt"] != nil {
+		t.Errorf("expected only the data without content, got %+v", e.Data)
+	}
+}